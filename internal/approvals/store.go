@@ -95,37 +95,66 @@ func (s *Store) Path() string {
 
 // Create creates a new pending approval request.
 func (s *Store) Create(input CreateInput) (*Request, error) {
-	if strings.TrimSpace(input.Command) == "" {
-		return nil, fmt.Errorf("command is required")
+	now := time.Now().UTC()
+	req, err := newRequest(input, now)
+	if err != nil {
+		return nil, err
 	}
-	if input.TTL <= 0 {
-		input.TTL = 15 * time.Minute
+
+	err = s.withLockedStore(func(sf *storeFile) error {
+		expireLocked(sf, now)
+		sf.Requests = append(sf.Requests, req)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Find returns the request that decides a command, or nil if there is none:
+// the newest approved or executed request for it, else the newest pending or
+// denied one. Expired requests don't count.
+func (s *Store) Find(command string) (*Request, error) {
+	sf, err := s.readLockedStore()
+	if err != nil {
+		return nil, err
 	}
+	expireLocked(sf, time.Now().UTC())
+
+	if req := findLocked(sf, HashCommand(command)); req != nil {
+		clone := *req
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+// FindOrCreate returns Find's request for input's command, creating a
+// pending request when there is none. The lookup and the create happen under
+// one lock, so concurrent callers share a single request.
+func (s *Store) FindOrCreate(input CreateInput) (*Request, error) {
 	now := time.Now().UTC()
-	req := &Request{
-		ID:             "apr-" + shortID(),
-		RunID:          strings.TrimSpace(input.RunID),
-		Command:        strings.TrimSpace(input.Command),
-		CommandHash:    HashCommand(input.Command),
-		Class:          input.Class,
-		RequestedBy:    defaultValue(input.RequestedBy, "system"),
-		Repo:           strings.TrimSpace(input.Repo),
-		Status:         StatusPending,
-		PolicyDecision: input.PolicyDecision,
-		Reason:         strings.TrimSpace(input.Reason),
-		CreatedAt:      now,
-		ExpiresAt:      now.Add(input.TTL),
+	req, err := newRequest(input, now)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.withLockedStore(func(sf *storeFile) error {
+	var found *Request
+	err = s.withLockedStore(func(sf *storeFile) error {
 		expireLocked(sf, now)
+		if existing := findLocked(sf, req.CommandHash); existing != nil {
+			clone := *existing
+			found = &clone
+			return nil
+		}
 		sf.Requests = append(sf.Requests, req)
+		found = req
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	return req, nil
+	return found, nil
 }
 
 // List returns approval requests sorted newest-first.
@@ -295,6 +324,50 @@ func (s *Store) loadUnlocked() (*storeFile, error) {
 	return &sf, nil
 }
 
+func newRequest(input CreateInput, now time.Time) (*Request, error) {
+	if strings.TrimSpace(input.Command) == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	if input.TTL <= 0 {
+		input.TTL = 15 * time.Minute
+	}
+	return &Request{
+		ID:             "apr-" + shortID(),
+		RunID:          strings.TrimSpace(input.RunID),
+		Command:        strings.TrimSpace(input.Command),
+		CommandHash:    HashCommand(input.Command),
+		Class:          input.Class,
+		RequestedBy:    defaultValue(input.RequestedBy, "system"),
+		Repo:           strings.TrimSpace(input.Repo),
+		Status:         StatusPending,
+		PolicyDecision: input.PolicyDecision,
+		Reason:         strings.TrimSpace(input.Reason),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(input.TTL),
+	}, nil
+}
+
+// findLocked returns the newest approved or executed request with the given
+// command hash, else the newest pending or denied one.
+func findLocked(sf *storeFile, hash string) *Request {
+	var open *Request
+	for i := len(sf.Requests) - 1; i >= 0; i-- {
+		req := sf.Requests[i]
+		if req.CommandHash != hash {
+			continue
+		}
+		switch req.Status {
+		case StatusApproved, StatusExecuted:
+			return req
+		case StatusPending, StatusDenied:
+			if open == nil {
+				open = req
+			}
+		}
+	}
+	return open
+}
+
 func expireLocked(sf *storeFile, now time.Time) {
 	for _, req := range sf.Requests {
 		if req.Status == StatusPending && !req.ExpiresAt.IsZero() && now.After(req.ExpiresAt) {
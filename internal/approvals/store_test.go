@@ -2,6 +2,7 @@ package approvals
 
 import (
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -72,3 +73,53 @@ func TestStoreExpiration(t *testing.T) {
 		t.Fatalf("status = %s, want expired", got.Status)
 	}
 }
+
+func TestStoreFindOrCreateConcurrent(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	input := CreateInput{
+		Command:     "budget-override rig/gastown 2026-10-16",
+		Class:       policy.Class2Sensitive,
+		RequestedBy: "gt sling",
+	}
+
+	// Separate stores, as separate gt processes would have.
+	var wg sync.WaitGroup
+	ids := make([]string, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := NewStore(tmp).FindOrCreate(input)
+			if err != nil {
+				t.Errorf("FindOrCreate: %v", err)
+				return
+			}
+			ids[i] = req.ID
+		}(i)
+	}
+	wg.Wait()
+
+	store := NewStore(tmp)
+	if reqs, _ := store.List(""); len(reqs) != 1 {
+		t.Fatalf("store has %d requests, want 1", len(reqs))
+	}
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Errorf("callers got different requests: %v", ids)
+			break
+		}
+	}
+
+	// A denial stands; Find reports it rather than nothing.
+	if _, err := store.Decide(DecideInput{ID: ids[0], Decision: StatusDenied}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Find(input.Command); err != nil || got == nil || got.Status != StatusDenied {
+		t.Errorf("Find after denial = %+v, %v", got, err)
+	}
+	if got, _ := store.Find("git push origin main"); got != nil {
+		t.Errorf("Find for another command = %+v, want nil", got)
+	}
+}
@@ -27,6 +27,8 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	DeliveryStatus     string // External delivery outcome per action (e.g., "email:human=sent; sms:human=failed: ...")
+	LastDeliveryAt     string // When external delivery was last attempted (empty if never)
 }
 
 
@@ -97,6 +99,18 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	// External delivery fields
+	if fields.DeliveryStatus != "" {
+		lines = append(lines, fmt.Sprintf("delivery_status: %s", fields.DeliveryStatus))
+	} else {
+		lines = append(lines, "delivery_status: null")
+	}
+	if fields.LastDeliveryAt != "" {
+		lines = append(lines, fmt.Sprintf("last_delivery_at: %s", fields.LastDeliveryAt))
+	} else {
+		lines = append(lines, "last_delivery_at: null")
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -152,6 +166,10 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery_status":
+			fields.DeliveryStatus = value
+		case "last_delivery_at":
+			fields.LastDeliveryAt = value
 		}
 	}
 
@@ -262,6 +280,35 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	return err
 }
 
+// RecordEscalationDelivery records the outcome of external notification
+// delivery (email, SMS, Slack, webhook, log) on an escalation bead.
+// status is a single-line summary; a "delivery-failed" label is added when
+// failed is true so undelivered escalations can be filtered.
+func (b *Beads) RecordEscalationDelivery(id, status string, failed bool) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", id)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	fields.DeliveryStatus = strings.ReplaceAll(status, "\n", " ")
+	fields.LastDeliveryAt = time.Now().Format(time.RFC3339)
+
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	opts := UpdateOptions{Description: &description}
+	if failed {
+		opts.AddLabels = []string{"delivery-failed"}
+	} else {
+		opts.RemoveLabels = []string{"delivery-failed"}
+	}
+	return b.Update(id, opts)
+}
+
 // GetEscalationBead retrieves an escalation bead by ID.
 // Returns nil if not found.
 func (b *Beads) GetEscalationBead(id string) (*Issue, *EscalationFields, error) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Note: AgentFields, ParseAgentFields, FormatAgentDescription, and CreateAgentBead are in beads.go
@@ -26,6 +27,8 @@ type AttachmentFields struct {
 	Mode             string // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
+	TraceParent      string // W3C traceparent of the sling that dispatched this work
+	FormulaVars      string // JSON object of the vars a molecule root was poured with
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -82,6 +85,12 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "merge_strategy", "merge-strategy", "mergestrategy":
 			fields.MergeStrategy = value
 			hasFields = true
+		case "traceparent":
+			fields.TraceParent = value
+			hasFields = true
+		case "formula_vars", "formula-vars", "formulavars":
+			fields.FormulaVars = value
+			hasFields = true
 		}
 	}
 
@@ -124,6 +133,12 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.MergeStrategy != "" {
 		lines = append(lines, "merge_strategy: "+fields.MergeStrategy)
 	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "traceparent: "+fields.TraceParent)
+	}
+	if fields.FormulaVars != "" {
+		lines = append(lines, "formula_vars: "+fields.FormulaVars)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -157,6 +172,10 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"merge_strategy":    true,
 		"merge-strategy":    true,
 		"mergestrategy":     true,
+		"traceparent":       true,
+		"formula_vars":      true,
+		"formula-vars":      true,
+		"formulavars":       true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -227,6 +246,217 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+	ConvoyDueAt     string // Convoy deadline (ISO 8601), if it has one
+
+	// Tracing
+	TraceParent string // W3C traceparent of the gt done that submitted this MR
+}
+
+// GateRun is one quality-gate outcome recorded on an MR bead. The refinery
+// appends one per gate each time it processes the MR, so the lines form the
+// MR's gate history. Each is stored as a description line of the form:
+//
+//	gate_run: <gate> <status> <elapsed> attempts=<n> tree=<hash> at=<RFC3339>
+type GateRun struct {
+	Gate     string        // Gate name from merge_queue.gates
+	Status   string        // passed, failed, skipped, blocked, or cached
+	Elapsed  time.Duration // Wall time spent running the gate
+	Attempts int           // Runs including flaky retries (0 if not run)
+	Tree     string        // Merged tree hash the gate was evaluated against
+	At       string        // When the result was recorded (RFC3339)
+}
+
+// String formats the run as the value of a gate_run line.
+func (r GateRun) String() string {
+	s := fmt.Sprintf("%s %s %s attempts=%d", r.Gate, r.Status, r.Elapsed.Truncate(time.Millisecond), r.Attempts)
+	if r.Tree != "" {
+		s += " tree=" + r.Tree
+	}
+	if r.At != "" {
+		s += " at=" + r.At
+	}
+	return s
+}
+
+// parseGateRun parses the value of a gate_run line. Unknown tokens are
+// ignored so the format can grow.
+func parseGateRun(value string) (GateRun, bool) {
+	parts := strings.Fields(value)
+	if len(parts) < 2 {
+		return GateRun{}, false
+	}
+	run := GateRun{Gate: parts[0], Status: parts[1]}
+	for _, p := range parts[2:] {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			if d, err := time.ParseDuration(p); err == nil {
+				run.Elapsed = d
+			}
+			continue
+		}
+		switch k {
+		case "attempts":
+			if n, err := parseIntField(v); err == nil {
+				run.Attempts = n
+			}
+		case "tree":
+			run.Tree = v
+		case "at":
+			run.At = v
+		}
+	}
+	return run, true
+}
+
+// ParseGateRuns returns the gate history recorded on an MR bead, oldest first.
+func ParseGateRuns(issue *Issue) []GateRun {
+	if issue == nil {
+		return nil
+	}
+	var runs []GateRun
+	for _, line := range strings.Split(issue.Description, "\n") {
+		if value, ok := gateRunValue(line); ok {
+			if run, ok := parseGateRun(value); ok {
+				runs = append(runs, run)
+			}
+		}
+	}
+	return runs
+}
+
+// SetGateRuns replaces the gate history in an issue's description with runs.
+// The history goes at the end; other content is preserved.
+// Returns the new description string.
+func SetGateRuns(issue *Issue, runs []GateRun) string {
+	var lines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if _, ok := gateRunValue(line); !ok {
+				lines = append(lines, line)
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for _, run := range runs {
+		lines = append(lines, "gate_run: "+run.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// gateRunValue returns the value of line if it is a gate_run line.
+func gateRunValue(line string) (string, bool) {
+	key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return "", false
+	}
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "gate_run", "gate-run", "gaterun":
+		return strings.TrimSpace(value), true
+	}
+	return "", false
+}
+
+// StepRun is the execution record gt mol step done keeps on a molecule step
+// bead to enforce its formula's retry, timeout and when policy. It is
+// stored as description lines of the form:
+//
+//	step_run: <status> attempts=<n> jumps=<n> started=<RFC3339>
+//	step_output: <output>
+type StepRun struct {
+	Status    string // formula.StepStatus of the step (empty if never recorded)
+	Attempts  int    // Failed attempts so far
+	Jumps     int    // on_failure gotos taken from this step
+	StartedAt string // When the current attempt started (RFC3339)
+	Output    string // Output reported when the step finished (one line)
+}
+
+// ParseStepRun returns the execution record on a step bead. A bead without
+// one returns the zero StepRun.
+func ParseStepRun(issue *Issue) StepRun {
+	var run StepRun
+	if issue == nil {
+		return run
+	}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := stepRunLine(line)
+		if !ok {
+			continue
+		}
+		if key == "step_output" {
+			run.Output = value
+			continue
+		}
+		parts := strings.Fields(value)
+		for i, p := range parts {
+			k, v, ok := strings.Cut(p, "=")
+			if !ok {
+				if i == 0 {
+					run.Status = p
+				}
+				continue
+			}
+			switch k {
+			case "attempts":
+				if n, err := parseIntField(v); err == nil {
+					run.Attempts = n
+				}
+			case "jumps":
+				if n, err := parseIntField(v); err == nil {
+					run.Jumps = n
+				}
+			case "started":
+				run.StartedAt = v
+			}
+		}
+	}
+	return run
+}
+
+// SetStepRun replaces the execution record in a step bead's description.
+// The record goes at the end; other content is preserved.
+// Returns the new description string.
+func SetStepRun(issue *Issue, run StepRun) string {
+	var lines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if _, _, ok := stepRunLine(line); !ok {
+				lines = append(lines, line)
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	status := run.Status
+	if status == "" {
+		status = "pending"
+	}
+	rec := fmt.Sprintf("step_run: %s attempts=%d jumps=%d", status, run.Attempts, run.Jumps)
+	if run.StartedAt != "" {
+		rec += " started=" + run.StartedAt
+	}
+	lines = append(lines, rec)
+	if out := strings.Join(strings.Fields(run.Output), " "); out != "" {
+		lines = append(lines, "step_output: "+out)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stepRunLine returns the key and value of line if it is a step_run or
+// step_output line.
+func stepRunLine(line string) (key, value string, ok bool) {
+	k, v, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return "", "", false
+	}
+	switch k = strings.ToLower(strings.TrimSpace(k)); k {
+	case "step_run", "step_output":
+		return k, strings.TrimSpace(v), true
+	}
+	return "", "", false
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -301,6 +531,12 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "convoy_due_at", "convoy-due-at", "convoydueat":
+			fields.ConvoyDueAt = value
+			hasFields = true
+		case "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -365,6 +601,12 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.ConvoyDueAt != "" {
+		lines = append(lines, "convoy_due_at: "+fields.ConvoyDueAt)
+	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "traceparent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -411,6 +653,10 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"convoy_due_at":      true,
+		"convoy-due-at":      true,
+		"convoydueat":        true,
+		"traceparent":        true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -3,6 +3,7 @@ package beads
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 // --- parseIntField (not covered in beads_test.go) ---
@@ -240,6 +241,113 @@ func TestSetAttachmentFieldsPreservesConvoy(t *testing.T) {
 	}
 }
 
+// --- traceparent on attachment and MR fields ---
+
+func TestTraceParentFieldsRoundTrip(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	att := ParseAttachmentFields(&Issue{Description: FormatAttachmentFields(&AttachmentFields{
+		DispatchedBy: "mayor/",
+		TraceParent:  tp,
+	})})
+	if att == nil || att.TraceParent != tp {
+		t.Errorf("attachment TraceParent round-trip: got %+v", att)
+	}
+
+	issue := &Issue{Description: "branch: polecat/nux\ntraceparent: 00-old-old-01\nNotes here"}
+	desc := SetMRFields(issue, &MRFields{Branch: "polecat/nux", TraceParent: tp})
+	if strings.Contains(desc, "00-old-old-01") {
+		t.Errorf("SetMRFields kept the stale traceparent:\n%s", desc)
+	}
+	mr := ParseMRFields(&Issue{Description: desc})
+	if mr == nil || mr.TraceParent != tp {
+		t.Errorf("MR TraceParent round-trip: got %+v", mr)
+	}
+	if !strings.Contains(desc, "Notes here") {
+		t.Errorf("SetMRFields lost non-MR content:\n%s", desc)
+	}
+}
+
+// --- formula_vars on molecule roots ---
+
+func TestFormulaVarsFieldRoundTrip(t *testing.T) {
+	const vars = `{"mode":"strict","target":"main"}`
+
+	issue := &Issue{Description: "formula: mol-review\nformula_vars: {\"mode\":\"old\"}"}
+	desc := SetAttachmentFields(issue, &AttachmentFields{FormulaVars: vars})
+	if strings.Contains(desc, `"old"`) {
+		t.Errorf("SetAttachmentFields kept the stale formula_vars:\n%s", desc)
+	}
+	if !strings.Contains(desc, "formula: mol-review") {
+		t.Errorf("SetAttachmentFields lost the formula line:\n%s", desc)
+	}
+	att := ParseAttachmentFields(&Issue{Description: desc})
+	if att == nil || att.FormulaVars != vars {
+		t.Errorf("FormulaVars round-trip: got %+v", att)
+	}
+}
+
+// --- gate_run history on MR beads ---
+
+func TestGateRunsRoundTrip(t *testing.T) {
+	runs := []GateRun{
+		{Gate: "build", Status: "passed", Elapsed: 1500 * time.Millisecond, Attempts: 1, Tree: "4b825dc6", At: "2026-10-16T12:00:00Z"},
+		{Gate: "test", Status: "failed", Elapsed: 2 * time.Minute, Attempts: 3, Tree: "4b825dc6", At: "2026-10-16T12:02:00Z"},
+		{Gate: "docs", Status: "skipped"},
+	}
+	issue := &Issue{Description: "branch: polecat/nux\ngate_run: lint passed 1s attempts=1\nNotes here\n"}
+	desc := SetGateRuns(issue, runs)
+	if strings.Contains(desc, "gate_run: lint") {
+		t.Errorf("SetGateRuns kept the stale gate_run line:\n%s", desc)
+	}
+	if !strings.Contains(desc, "gate_run: build passed 1.5s attempts=1 tree=4b825dc6 at=2026-10-16T12:00:00Z") {
+		t.Errorf("SetGateRuns gate_run format unexpected:\n%s", desc)
+	}
+	if !strings.HasPrefix(desc, "branch: polecat/nux\nNotes here\ngate_run: ") {
+		t.Errorf("SetGateRuns should keep other content and append history:\n%s", desc)
+	}
+
+	got := ParseGateRuns(&Issue{Description: desc})
+	if len(got) != len(runs) {
+		t.Fatalf("ParseGateRuns = %+v, want %d runs", got, len(runs))
+	}
+	for i, want := range runs {
+		if got[i] != want {
+			t.Errorf("run[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+
+	// MR field updates leave the history alone.
+	desc = SetMRFields(&Issue{Description: desc}, &MRFields{Branch: "polecat/nux", MergeCommit: "abc123"})
+	if n := len(ParseGateRuns(&Issue{Description: desc})); n != len(runs) {
+		t.Errorf("SetMRFields dropped gate history: %d runs left\n%s", n, desc)
+	}
+
+	if got := ParseGateRuns(&Issue{Description: "gate_run: lonely"}); len(got) != 0 {
+		t.Errorf("malformed gate_run should be ignored, got %+v", got)
+	}
+}
+
+func TestStepRunRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Run the test suite.\nstep_run: running attempts=0 jumps=0\n"}
+	run := StepRun{Status: "pending", Attempts: 2, Jumps: 1, StartedAt: "2026-10-16T12:00:00Z", Output: "3 failed\n  in auth"}
+	desc := SetStepRun(issue, run)
+	want := "Run the test suite.\nstep_run: pending attempts=2 jumps=1 started=2026-10-16T12:00:00Z\nstep_output: 3 failed in auth"
+	if desc != want {
+		t.Errorf("SetStepRun =\n%s\nwant\n%s", desc, want)
+	}
+
+	got := ParseStepRun(&Issue{Description: desc})
+	run.Output = "3 failed in auth"
+	if got != run {
+		t.Errorf("ParseStepRun = %+v, want %+v", got, run)
+	}
+
+	if got := ParseStepRun(&Issue{Description: "no record"}); got != (StepRun{}) {
+		t.Errorf("ParseStepRun without a record = %+v", got)
+	}
+}
+
 // --- ParseAgentFieldsFromDescription alias (not covered in beads_test.go) ---
 
 func TestParseAgentFieldsFromDescription(t *testing.T) {
@@ -0,0 +1,231 @@
+// Package budget enforces daily spending caps per rig, convoy, role and agent.
+//
+// Caps live in settings/budgets.json and are measured against the local cost
+// ledger that gt costs record appends to. Crossing a soft cap is escalated by
+// the Deacon; crossing a hard cap stops new polecats being dispatched for the
+// scope until an operator grants an override through the approvals store.
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+// Kind is the dimension a budget applies to.
+type Kind string
+
+const (
+	KindRig    Kind = "rig"
+	KindConvoy Kind = "convoy"
+	KindRole   Kind = "role"
+	KindAgent  Kind = "agent"
+)
+
+// AnyScope is the budgets.json key whose limit applies to every scope of its
+// kind without an entry of its own, e.g. a cap on any single convoy.
+const AnyScope = "*"
+
+// Scope identifies one rig, convoy, role or agent.
+type Scope struct {
+	Kind Kind   `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (s Scope) String() string {
+	return string(s.Kind) + ":" + s.Name
+}
+
+// Limit is a daily spending cap in USD. Zero means no cap at that level.
+type Limit struct {
+	SoftUSD float64 `json:"soft_usd,omitempty"`
+	HardUSD float64 `json:"hard_usd,omitempty"`
+}
+
+// Level is how far spending has gone against a limit.
+type Level string
+
+const (
+	LevelOK   Level = "ok"
+	LevelSoft Level = "soft"
+	LevelHard Level = "hard"
+)
+
+// Level returns the cap a day's spending has crossed.
+func (l Limit) Level(spent float64) Level {
+	switch {
+	case l.HardUSD > 0 && spent >= l.HardUSD:
+		return LevelHard
+	case l.SoftUSD > 0 && spent >= l.SoftUSD:
+		return LevelSoft
+	default:
+		return LevelOK
+	}
+}
+
+// Config is the on-disk format of settings/budgets.json.
+type Config struct {
+	Type    string           `json:"type"`    // "budgets"
+	Version int              `json:"version"` // schema version
+	Rigs    map[string]Limit `json:"rigs,omitempty"`
+	Convoys map[string]Limit `json:"convoys,omitempty"`
+	Roles   map[string]Limit `json:"roles,omitempty"`
+	Agents  map[string]Limit `json:"agents,omitempty"`
+}
+
+// CurrentConfigVersion is the current budgets file schema version.
+const CurrentConfigVersion = 1
+
+// ConfigPath returns the path of a town's budgets.
+func ConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "budgets.json")
+}
+
+// LoadConfig loads a town's budgets. Returns nil (and no error) when the town
+// has none configured.
+func LoadConfig(townRoot string) (*Config, error) {
+	path := ConfigPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading budgets: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cfg.Type != "budgets" && cfg.Type != "" {
+		return nil, fmt.Errorf("parsing %s: expected type 'budgets', got '%s'", path, cfg.Type)
+	}
+	if cfg.Version > CurrentConfigVersion {
+		return nil, fmt.Errorf("parsing %s: version %d, max supported %d", path, cfg.Version, CurrentConfigVersion)
+	}
+	for kind, limits := range cfg.all() {
+		for name, l := range limits {
+			if l.SoftUSD < 0 || l.HardUSD < 0 {
+				return nil, fmt.Errorf("parsing %s: %s %q: caps must not be negative", path, kind, name)
+			}
+			if l.SoftUSD > 0 && l.HardUSD > 0 && l.SoftUSD > l.HardUSD {
+				return nil, fmt.Errorf("parsing %s: %s %q: soft cap $%.2f is above hard cap $%.2f", path, kind, name, l.SoftUSD, l.HardUSD)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+func (c *Config) all() map[Kind]map[string]Limit {
+	return map[Kind]map[string]Limit{
+		KindRig:    c.Rigs,
+		KindConvoy: c.Convoys,
+		KindRole:   c.Roles,
+		KindAgent:  c.Agents,
+	}
+}
+
+// Has reports whether any budget of a kind is configured, so callers can
+// skip resolving scopes (e.g. a bead's convoy) nothing would check.
+func (c *Config) Has(kind Kind) bool {
+	return c != nil && len(c.all()[kind]) > 0
+}
+
+// LimitFor returns a scope's limit: its own entry, else the kind's "*" entry.
+func (c *Config) LimitFor(s Scope) (Limit, bool) {
+	if c == nil || s.Name == "" {
+		return Limit{}, false
+	}
+	limits := c.all()[s.Kind]
+	if l, ok := limits[s.Name]; ok {
+		return l, true
+	}
+	l, ok := limits[AnyScope]
+	return l, ok
+}
+
+// Spend is one day's spending by scope.
+type Spend map[Scope]float64
+
+// SpendForDay totals the ledger entries that ended on day (local time).
+func SpendForDay(entries []costs.LedgerEntry, day time.Time) Spend {
+	target := day.Format("2006-01-02")
+	spend := make(Spend)
+	for _, e := range entries {
+		if e.EndedAt.Local().Format("2006-01-02") != target || e.CostUSD == 0 {
+			continue
+		}
+		for _, s := range []Scope{
+			{KindRig, e.Rig},
+			{KindConvoy, e.Convoy},
+			{KindRole, e.Role},
+			{KindAgent, e.Agent},
+		} {
+			if s.Name != "" {
+				spend[s] += e.CostUSD
+			}
+		}
+	}
+	return spend
+}
+
+// LoadSpend returns the day's spending from the local cost ledger.
+func LoadSpend(day time.Time) (Spend, error) {
+	entries, err := costs.ReadLedger(costs.LedgerPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading cost ledger: %w", err)
+	}
+	return SpendForDay(entries, day), nil
+}
+
+// Status is one scope's spending against its budget.
+type Status struct {
+	Scope    Scope   `json:"scope"`
+	SpentUSD float64 `json:"spent_usd"`
+	Limit    Limit   `json:"limit"`
+	Level    Level   `json:"level"`
+}
+
+// Evaluate returns the status of every budgeted scope: each scope named in
+// the config, plus each scope with spending that a "*" entry covers.
+// Sorted by kind then name.
+func (c *Config) Evaluate(spend Spend) []Status {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[Scope]bool)
+	var out []Status
+	add := func(s Scope) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		if l, ok := c.LimitFor(s); ok {
+			spent := spend[s]
+			out = append(out, Status{Scope: s, SpentUSD: spent, Limit: l, Level: l.Level(spent)})
+		}
+	}
+	for kind, limits := range c.all() {
+		for name := range limits {
+			if name != AnyScope {
+				add(Scope{kind, name})
+			}
+		}
+	}
+	for s := range spend {
+		add(s)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope.Kind != out[j].Scope.Kind {
+			return out[i].Scope.Kind < out[j].Scope.Kind
+		}
+		return out[i].Scope.Name < out[j].Scope.Name
+	})
+	return out
+}
@@ -0,0 +1,145 @@
+package budget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+func writeBudgets(t *testing.T, townRoot, content string) {
+	t.Helper()
+	path := ConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	townRoot := t.TempDir()
+
+	cfg, err := LoadConfig(townRoot)
+	if err != nil || cfg != nil {
+		t.Fatalf("missing file: cfg=%v err=%v, want nil, nil", cfg, err)
+	}
+
+	writeBudgets(t, townRoot, `{
+  "type": "budgets",
+  "version": 1,
+  "rigs": {"gastown": {"soft_usd": 40, "hard_usd": 60}},
+  "convoys": {"*": {"hard_usd": 25}}
+}`)
+	cfg, err = LoadConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !cfg.Has(KindRig) || !cfg.Has(KindConvoy) || cfg.Has(KindAgent) {
+		t.Errorf("Has: rig=%v convoy=%v agent=%v", cfg.Has(KindRig), cfg.Has(KindConvoy), cfg.Has(KindAgent))
+	}
+
+	bad := []string{
+		`{"type": "pricing"}`,
+		`{"type": "budgets", "version": 99}`,
+		`{"type": "budgets", "rigs": {"gastown": {"soft_usd": 50, "hard_usd": 10}}}`,
+		`{"type": "budgets", "roles": {"polecat": {"hard_usd": -1}}}`,
+	}
+	for _, content := range bad {
+		writeBudgets(t, townRoot, content)
+		if _, err := LoadConfig(townRoot); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", content)
+		}
+	}
+}
+
+func TestLimitLevel(t *testing.T) {
+	l := Limit{SoftUSD: 10, HardUSD: 20}
+	tests := []struct {
+		spent float64
+		want  Level
+	}{
+		{0, LevelOK},
+		{9.99, LevelOK},
+		{10, LevelSoft},
+		{19.99, LevelSoft},
+		{20, LevelHard},
+		{50, LevelHard},
+	}
+	for _, tt := range tests {
+		if got := l.Level(tt.spent); got != tt.want {
+			t.Errorf("Level(%v) = %s, want %s", tt.spent, got, tt.want)
+		}
+	}
+	if got := (Limit{SoftUSD: 5}).Level(1000); got != LevelSoft {
+		t.Errorf("soft-only limit Level = %s, want soft", got)
+	}
+}
+
+func TestLimitForWildcard(t *testing.T) {
+	cfg := &Config{Convoys: map[string]Limit{
+		AnyScope:  {HardUSD: 25},
+		"hq-cv-1": {HardUSD: 100},
+	}}
+
+	if l, ok := cfg.LimitFor(Scope{KindConvoy, "hq-cv-1"}); !ok || l.HardUSD != 100 {
+		t.Errorf("own entry: %+v, %v", l, ok)
+	}
+	if l, ok := cfg.LimitFor(Scope{KindConvoy, "hq-cv-2"}); !ok || l.HardUSD != 25 {
+		t.Errorf("wildcard: %+v, %v", l, ok)
+	}
+	if _, ok := cfg.LimitFor(Scope{KindConvoy, ""}); ok {
+		t.Error("empty scope name should have no limit")
+	}
+	if _, ok := cfg.LimitFor(Scope{KindRig, "gastown"}); ok {
+		t.Error("unbudgeted kind should have no limit")
+	}
+}
+
+func TestSpendForDayAndEvaluate(t *testing.T) {
+	today := time.Date(2026, 10, 16, 15, 0, 0, 0, time.Local)
+	entries := []costs.LedgerEntry{
+		{Role: "polecat", Rig: "gastown", Agent: "claude", Convoy: "hq-cv-1", CostUSD: 12, EndedAt: today},
+		{Role: "polecat", Rig: "gastown", Agent: "codex", Convoy: "hq-cv-1", CostUSD: 8, EndedAt: today.Add(-time.Hour)},
+		{Role: "witness", Rig: "gastown", Agent: "claude", CostUSD: 1, EndedAt: today},
+		{Role: "polecat", Rig: "beads", Agent: "claude", Convoy: "hq-cv-2", CostUSD: 3, EndedAt: today},
+		{Role: "polecat", Rig: "gastown", Convoy: "hq-cv-1", CostUSD: 500, EndedAt: today.AddDate(0, 0, -1)},
+	}
+	spend := SpendForDay(entries, today)
+
+	want := map[Scope]float64{
+		{KindRig, "gastown"}:    21,
+		{KindRig, "beads"}:      3,
+		{KindConvoy, "hq-cv-1"}: 20,
+		{KindRole, "polecat"}:   23,
+		{KindAgent, "claude"}:   16,
+	}
+	for s, usd := range want {
+		if spend[s] != usd {
+			t.Errorf("spend[%s] = %v, want %v", s, spend[s], usd)
+		}
+	}
+
+	cfg := &Config{
+		Rigs:    map[string]Limit{"gastown": {SoftUSD: 20, HardUSD: 40}, "idle": {HardUSD: 10}},
+		Convoys: map[string]Limit{AnyScope: {HardUSD: 15}},
+	}
+	got := cfg.Evaluate(spend)
+	wantStatus := []Status{
+		{Scope{KindConvoy, "hq-cv-1"}, 20, Limit{HardUSD: 15}, LevelHard},
+		{Scope{KindConvoy, "hq-cv-2"}, 3, Limit{HardUSD: 15}, LevelOK},
+		{Scope{KindRig, "gastown"}, 21, Limit{SoftUSD: 20, HardUSD: 40}, LevelSoft},
+		{Scope{KindRig, "idle"}, 0, Limit{HardUSD: 10}, LevelOK},
+	}
+	if len(got) != len(wantStatus) {
+		t.Fatalf("Evaluate = %+v, want %+v", got, wantStatus)
+	}
+	for i := range got {
+		if got[i] != wantStatus[i] {
+			t.Errorf("Evaluate[%d] = %+v, want %+v", i, got[i], wantStatus[i])
+		}
+	}
+}
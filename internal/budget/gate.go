@@ -0,0 +1,144 @@
+package budget
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/approvals"
+	"github.com/steveyegge/gastown/internal/policy"
+)
+
+// Breach is a hard cap that blocks dispatch for a scope. Approval is the
+// override request an operator can grant to lift it for the rest of the day.
+type Breach struct {
+	Status
+	Approval *approvals.Request
+}
+
+func (b *Breach) Error() string {
+	msg := fmt.Sprintf("%s %s has spent $%.2f today, over its $%.2f hard cap",
+		b.Scope.Kind, b.Scope.Name, b.SpentUSD, b.Limit.HardUSD)
+	if b.Approval == nil {
+		return msg
+	}
+	if b.Approval.Status == approvals.StatusDenied {
+		return fmt.Sprintf("%s (override %s was denied)", msg, b.Approval.ID)
+	}
+	return fmt.Sprintf("%s; approve %s to continue (gt approvals approve %s)", msg, b.Approval.ID, b.Approval.ID)
+}
+
+// OverrideCommand is the approval command that lifts a scope's hard cap for a
+// day. Approvals match on its hash, so one grant covers every dispatch.
+func OverrideCommand(s Scope, day time.Time) string {
+	return fmt.Sprintf("budget-override %s %s", s, day.Format("2006-01-02"))
+}
+
+// Gate checks dispatch against hard caps. A nil Gate (no budgets configured)
+// allows everything.
+type Gate struct {
+	Config *Config
+	Spend  Spend
+
+	store       *approvals.Store
+	requestedBy string
+	now         time.Time
+}
+
+// NewGate loads a town's budgets and today's spending. Returns nil when the
+// town has no budgets. requestedBy is recorded on override requests.
+func NewGate(townRoot, requestedBy string) (*Gate, error) {
+	cfg, err := LoadConfig(townRoot)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	now := time.Now()
+	spend, err := LoadSpend(now)
+	if err != nil {
+		return nil, err
+	}
+	return &Gate{
+		Config:      cfg,
+		Spend:       spend,
+		store:       approvals.NewStore(townRoot),
+		requestedBy: requestedBy,
+		now:         now,
+	}, nil
+}
+
+// Wants reports whether the gate checks scopes of a kind.
+func (g *Gate) Wants(kind Kind) bool {
+	return g != nil && g.Config.Has(kind)
+}
+
+// Check returns the first scope over its hard cap without an approved
+// override, opening an override request for it if none is pending. Scopes
+// with an empty name are ignored.
+func (g *Gate) Check(scopes ...Scope) (*Breach, error) {
+	return g.check(true, scopes)
+}
+
+// Preview is Check without side effects, for dry runs: a breach carries
+// the existing override request, if any, and none is opened.
+func (g *Gate) Preview(scopes ...Scope) (*Breach, error) {
+	return g.check(false, scopes)
+}
+
+func (g *Gate) check(open bool, scopes []Scope) (*Breach, error) {
+	if g == nil {
+		return nil, nil
+	}
+	for _, s := range scopes {
+		l, ok := g.Config.LimitFor(s)
+		if !ok {
+			continue
+		}
+		spent := g.Spend[s]
+		if l.Level(spent) != LevelHard {
+			continue
+		}
+
+		req, err := g.override(s, l, spent, open)
+		if err != nil {
+			return nil, err
+		}
+		if req != nil && (req.Status == approvals.StatusApproved || req.Status == approvals.StatusExecuted) {
+			continue
+		}
+		return &Breach{
+			Status:   Status{Scope: s, SpentUSD: spent, Limit: l, Level: LevelHard},
+			Approval: req,
+		}, nil
+	}
+	return nil, nil
+}
+
+// override returns today's override request for a scope: an approved one,
+// else a pending or denied one, else (when open is set) a newly opened
+// request. Denials stand for the day; expired requests are reopened.
+func (g *Gate) override(s Scope, l Limit, spent float64, open bool) (*approvals.Request, error) {
+	command := OverrideCommand(s, g.now)
+	if !open {
+		req, err := g.store.Find(command)
+		if err != nil {
+			return nil, fmt.Errorf("reading approvals: %w", err)
+		}
+		return req, nil
+	}
+
+	// The override is for today only, so the request lapses at midnight.
+	y, m, d := g.now.Date()
+	ttl := time.Date(y, m, d+1, 0, 0, 0, 0, g.now.Location()).Sub(g.now)
+	req, err := g.store.FindOrCreate(approvals.CreateInput{
+		Command:        command,
+		Class:          policy.Class2Sensitive,
+		RequestedBy:    g.requestedBy,
+		PolicyDecision: policy.DecisionRequireApproval,
+		Reason: fmt.Sprintf("%s %s spent $%.2f today, over its $%.2f hard cap; approving lets polecats be dispatched for it until midnight",
+			s.Kind, s.Name, spent, l.HardUSD),
+		TTL: ttl,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening override request: %w", err)
+	}
+	return req, nil
+}
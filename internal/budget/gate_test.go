@@ -0,0 +1,131 @@
+package budget
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/approvals"
+)
+
+func newTestGate(t *testing.T) (*Gate, *approvals.Store) {
+	t.Helper()
+	store := approvals.NewStore(t.TempDir())
+	return &Gate{
+		Config: &Config{
+			Rigs:    map[string]Limit{"gastown": {SoftUSD: 20, HardUSD: 40}},
+			Convoys: map[string]Limit{AnyScope: {HardUSD: 25}},
+		},
+		Spend: Spend{
+			{KindRig, "gastown"}:    30,
+			{KindConvoy, "hq-cv-1"}: 26,
+		},
+		store:       store,
+		requestedBy: "test",
+		now:         time.Date(2026, 10, 16, 22, 0, 0, 0, time.Local),
+	}, store
+}
+
+func TestGateCheck(t *testing.T) {
+	gate, store := newTestGate(t)
+
+	// Under the hard cap (soft crossings don't block).
+	breach, err := gate.Check(Scope{KindRig, "gastown"}, Scope{KindConvoy, "hq-cv-2"}, Scope{KindConvoy, ""})
+	if err != nil || breach != nil {
+		t.Fatalf("under cap: breach=%v err=%v", breach, err)
+	}
+
+	// Over: blocked, with an override request opened.
+	breach, err = gate.Check(Scope{KindRig, "gastown"}, Scope{KindConvoy, "hq-cv-1"})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if breach == nil || breach.Scope != (Scope{KindConvoy, "hq-cv-1"}) {
+		t.Fatalf("breach = %+v, want convoy hq-cv-1", breach)
+	}
+	if breach.Approval == nil || breach.Approval.Status != approvals.StatusPending {
+		t.Fatalf("approval = %+v, want pending", breach.Approval)
+	}
+	if want := OverrideCommand(Scope{KindConvoy, "hq-cv-1"}, gate.now); breach.Approval.Command != want {
+		t.Errorf("command = %q, want %q", breach.Approval.Command, want)
+	}
+	if !strings.Contains(breach.Error(), breach.Approval.ID) {
+		t.Errorf("error %q should name the approval", breach.Error())
+	}
+	var asBreach *Breach
+	if !errors.As(breach, &asBreach) {
+		t.Error("Breach should be usable as an error")
+	}
+
+	// Checking again reuses the pending request.
+	again, _ := gate.Check(Scope{KindConvoy, "hq-cv-1"})
+	if again == nil || again.Approval.ID != breach.Approval.ID {
+		t.Fatalf("second check opened another request: %+v", again)
+	}
+	if reqs, _ := store.List(""); len(reqs) != 1 {
+		t.Errorf("store has %d requests, want 1", len(reqs))
+	}
+
+	// Approval lifts the cap.
+	if _, err := store.Decide(approvals.DecideInput{ID: breach.Approval.ID, Decision: approvals.StatusApproved}); err != nil {
+		t.Fatal(err)
+	}
+	if breach, _ := gate.Check(Scope{KindConvoy, "hq-cv-1"}); breach != nil {
+		t.Errorf("approved override still blocks: %v", breach)
+	}
+}
+
+func TestGatePreviewOpensNothing(t *testing.T) {
+	gate, store := newTestGate(t)
+
+	breach, err := gate.Preview(Scope{KindConvoy, "hq-cv-1"})
+	if err != nil || breach == nil || breach.Approval != nil {
+		t.Fatalf("preview over cap: breach=%+v err=%v, want a breach without approval", breach, err)
+	}
+	if reqs, _ := store.List(""); len(reqs) != 0 {
+		t.Fatalf("preview opened %d requests, want none", len(reqs))
+	}
+
+	// Once Check has opened a request, Preview reports it.
+	opened, _ := gate.Check(Scope{KindConvoy, "hq-cv-1"})
+	breach, _ = gate.Preview(Scope{KindConvoy, "hq-cv-1"})
+	if breach == nil || breach.Approval == nil || breach.Approval.ID != opened.Approval.ID {
+		t.Errorf("preview after check = %+v, want approval %s", breach, opened.Approval.ID)
+	}
+}
+
+func TestGateDeniedOverrideStands(t *testing.T) {
+	gate, store := newTestGate(t)
+
+	breach, _ := gate.Check(Scope{KindConvoy, "hq-cv-1"})
+	if _, err := store.Decide(approvals.DecideInput{ID: breach.Approval.ID, Decision: approvals.StatusDenied}); err != nil {
+		t.Fatal(err)
+	}
+
+	breach, err := gate.Check(Scope{KindConvoy, "hq-cv-1"})
+	if err != nil || breach == nil {
+		t.Fatalf("denied override: breach=%v err=%v", breach, err)
+	}
+	if breach.Approval.Status != approvals.StatusDenied {
+		t.Errorf("approval status = %s, want denied", breach.Approval.Status)
+	}
+	if reqs, _ := store.List(""); len(reqs) != 1 {
+		t.Errorf("denial should not reopen a request, store has %d", len(reqs))
+	}
+}
+
+func TestNilGateAllows(t *testing.T) {
+	var gate *Gate
+	if gate.Wants(KindRig) {
+		t.Error("nil gate wants nothing")
+	}
+	if breach, err := gate.Check(Scope{KindRig, "gastown"}); breach != nil || err != nil {
+		t.Errorf("nil gate: breach=%v err=%v", breach, err)
+	}
+
+	// No budgets file: no gate.
+	if gate, err := NewGate(t.TempDir(), "test"); gate != nil || err != nil {
+		t.Errorf("NewGate without budgets = %v, %v", gate, err)
+	}
+}
@@ -0,0 +1,246 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultMaxResumes is how many times a crashed polecat is resumed
+// automatically on the same bead before the crash is escalated.
+const DefaultMaxResumes = 3
+
+// resumeRecordTTL is how long a bead's resume count is kept after its last
+// attempt.
+const resumeRecordTTL = 7 * 24 * time.Hour
+
+// resumeStaleAfter is the age past which a checkpoint file is ignored and the
+// worktree state is captured afresh.
+const resumeStaleAfter = 24 * time.Hour
+
+// ErrResumeBudgetExhausted is returned when a bead has used up its automatic
+// resumes.
+var ErrResumeBudgetExhausted = errors.New("resume budget exhausted")
+
+// Resume is a planned automatic restart of a crashed polecat session.
+type Resume struct {
+	// Checkpoint is the state the crashed session left behind.
+	Checkpoint *Checkpoint
+
+	// Bead is the hooked bead the budget is charged to.
+	Bead string
+
+	// Attempt is this resume's number for the bead, starting at 1.
+	Attempt int
+
+	// Max is the bead's resume budget.
+	Max int
+}
+
+// ContinueConversation reports whether the agent's previous conversation
+// should be resumed. Only the first attempt does: if the resumed session
+// crashes again, the conversation itself may be the problem (a full context
+// window, say), so later attempts start fresh from the checkpoint.
+func (r *Resume) ContinueConversation() bool {
+	return r.Attempt == 1
+}
+
+// SessionID returns the crashed session's runtime session ID, if one was
+// recorded.
+func (r *Resume) SessionID() string {
+	if r.Checkpoint == nil || strings.HasPrefix(r.Checkpoint.SessionID, "pid-") {
+		return ""
+	}
+	return r.Checkpoint.SessionID
+}
+
+// Prompt returns the message that tells the restarted agent what it was
+// doing when the previous session died.
+func (r *Resume) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your previous session crashed. This is automatic resume %d of %d.\n", r.Attempt, r.Max)
+	b.WriteString("Pick up where it left off; don't start the work over.\n")
+
+	cp := r.Checkpoint
+	if cp == nil {
+		cp = &Checkpoint{}
+	}
+	if r.Bead != "" {
+		fmt.Fprintf(&b, "\n- Hooked bead: %s", r.Bead)
+	}
+	if cp.MoleculeID != "" {
+		step := cp.CurrentStep
+		if cp.StepTitle != "" {
+			step = fmt.Sprintf("%s (%s)", cp.CurrentStep, cp.StepTitle)
+		}
+		if step != "" {
+			fmt.Fprintf(&b, "\n- Molecule: %s, step %s", cp.MoleculeID, step)
+		} else {
+			fmt.Fprintf(&b, "\n- Molecule: %s", cp.MoleculeID)
+		}
+	}
+	if cp.Branch != "" {
+		if cp.LastCommit != "" {
+			fmt.Fprintf(&b, "\n- Branch: %s at %s", cp.Branch, shortSHA(cp.LastCommit))
+		} else {
+			fmt.Fprintf(&b, "\n- Branch: %s", cp.Branch)
+		}
+	}
+	if n := len(cp.ModifiedFiles); n > 0 {
+		files := cp.ModifiedFiles
+		if n > 10 {
+			files = files[:10]
+		}
+		fmt.Fprintf(&b, "\n- Uncommitted changes (%d files): %s", n, strings.Join(files, ", "))
+		if n > 10 {
+			b.WriteString(", ...")
+		}
+	}
+	if cp.Notes != "" {
+		fmt.Fprintf(&b, "\n- Notes: %s", cp.Notes)
+	}
+
+	b.WriteString("\n\nRun `gt prime --hook`, check `git status`, then continue the work.")
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// PlanResume prepares an automatic resume of the crashed polecat working in
+// polecatDir and charges it to the bead's resume budget. The checkpoint is
+// read from the worktree, or captured from git if missing or stale. hookBead
+// is used if the checkpoint doesn't name one. Returns an error wrapping
+// ErrResumeBudgetExhausted once the bead has been resumed max times.
+func PlanResume(townRoot, polecatDir, polecat, hookBead string, max int) (*Resume, error) {
+	cp, err := Read(polecatDir)
+	if err != nil || cp == nil || cp.IsStale(resumeStaleAfter) {
+		cp, _ = Capture(polecatDir)
+	}
+	if cp.HookedBead == "" {
+		cp.HookedBead = hookBead
+	}
+	if cp.HookedBead == "" {
+		return nil, fmt.Errorf("no hooked bead to resume")
+	}
+	if max <= 0 {
+		max = DefaultMaxResumes
+	}
+
+	attempt, err := claimResume(townRoot, cp.HookedBead, polecat, max, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &Resume{Checkpoint: cp, Bead: cp.HookedBead, Attempt: attempt, Max: max}, nil
+}
+
+// ResumeRecord counts the automatic resumes of one bead.
+type ResumeRecord struct {
+	Attempts    int       `json:"attempts"`
+	Polecat     string    `json:"polecat,omitempty"`
+	LastAttempt time.Time `json:"last_attempt"`
+	Escalated   bool      `json:"escalated,omitempty"`
+}
+
+// ResumeBudgetPath returns the path of the town's resume counts.
+func ResumeBudgetPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "resume-budget.json")
+}
+
+// ResumeAttempts returns the resume counts by bead ID.
+func ResumeAttempts(townRoot string) (map[string]ResumeRecord, error) {
+	data, err := os.ReadFile(ResumeBudgetPath(townRoot)) //nolint:gosec // G304: path is under the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]ResumeRecord{}, nil
+		}
+		return nil, fmt.Errorf("reading resume budget: %w", err)
+	}
+	records := map[string]ResumeRecord{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing resume budget: %w", err)
+	}
+	return records, nil
+}
+
+// claimResume charges one resume to beadID and returns its attempt number.
+func claimResume(townRoot, beadID, polecat string, max int, now time.Time) (int, error) {
+	var attempt int
+	err := updateResumeRecords(townRoot, now, func(records map[string]ResumeRecord) error {
+		rec := records[beadID]
+		if rec.Attempts >= max {
+			return fmt.Errorf("%w: %s resumed %d times", ErrResumeBudgetExhausted, beadID, rec.Attempts)
+		}
+		rec.Attempts++
+		rec.Polecat = polecat
+		rec.LastAttempt = now
+		records[beadID] = rec
+		attempt = rec.Attempts
+		return nil
+	})
+	return attempt, err
+}
+
+// MarkEscalated records that a bead's exhausted resume budget has been
+// escalated. Returns false if it already had been, so each crash loop is
+// escalated once.
+func MarkEscalated(townRoot, beadID string) (bool, error) {
+	first := false
+	err := updateResumeRecords(townRoot, time.Now(), func(records map[string]ResumeRecord) error {
+		rec := records[beadID]
+		if rec.Escalated {
+			return nil
+		}
+		first = true
+		rec.Escalated = true
+		if rec.LastAttempt.IsZero() {
+			rec.LastAttempt = time.Now()
+		}
+		records[beadID] = rec
+		return nil
+	})
+	return first, err
+}
+
+// updateResumeRecords applies fn to the resume counts under a file lock (the
+// daemon and witness both resume polecats) and saves them if fn succeeds.
+// Records untouched for a week are dropped.
+func updateResumeRecords(townRoot string, now time.Time, fn func(map[string]ResumeRecord) error) error {
+	path := ResumeBudgetPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking resume budget: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	records, err := ResumeAttempts(townRoot)
+	if err != nil {
+		return err
+	}
+	for id, rec := range records {
+		if now.Sub(rec.LastAttempt) > resumeRecordTTL {
+			delete(records, id)
+		}
+	}
+	if err := fn(records); err != nil {
+		return err
+	}
+	if err := util.EnsureDirAndWriteJSON(path, records); err != nil {
+		return fmt.Errorf("writing resume budget: %w", err)
+	}
+	return nil
+}
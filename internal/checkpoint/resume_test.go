@@ -0,0 +1,114 @@
+package checkpoint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanResumeBudget(t *testing.T) {
+	townRoot := t.TempDir()
+	polecatDir := t.TempDir()
+	if err := Write(polecatDir, &Checkpoint{
+		MoleculeID:    "mol-1",
+		CurrentStep:   "implement",
+		HookedBead:    "gt-abc",
+		Branch:        "polecat/toast",
+		ModifiedFiles: []string{"a.go"},
+		SessionID:     "sess-1",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		r, err := PlanResume(townRoot, polecatDir, "toast", "", 2)
+		if err != nil {
+			t.Fatalf("attempt %d: %v", attempt, err)
+		}
+		if r.Attempt != attempt || r.Bead != "gt-abc" {
+			t.Errorf("attempt %d: got %+v", attempt, r)
+		}
+		if r.ContinueConversation() != (attempt == 1) {
+			t.Errorf("attempt %d: ContinueConversation = %v", attempt, r.ContinueConversation())
+		}
+	}
+
+	_, err := PlanResume(townRoot, polecatDir, "toast", "", 2)
+	if !errors.Is(err, ErrResumeBudgetExhausted) {
+		t.Fatalf("third attempt err = %v, want ErrResumeBudgetExhausted", err)
+	}
+
+	// Another bead has its own budget.
+	if r, err := PlanResume(townRoot, t.TempDir(), "ash", "gt-def", 2); err != nil || r.Attempt != 1 {
+		t.Errorf("other bead: %+v, %v", r, err)
+	}
+
+	records, err := ResumeAttempts(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := records["gt-abc"]; rec.Attempts != 2 || rec.Polecat != "toast" {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestPlanResumeNeedsBead(t *testing.T) {
+	if _, err := PlanResume(t.TempDir(), t.TempDir(), "toast", "", 0); err == nil {
+		t.Error("expected error without a hooked bead")
+	}
+}
+
+func TestMarkEscalated(t *testing.T) {
+	townRoot := t.TempDir()
+	first, err := MarkEscalated(townRoot, "gt-abc")
+	if err != nil || !first {
+		t.Fatalf("first MarkEscalated = %v, %v", first, err)
+	}
+	if again, _ := MarkEscalated(townRoot, "gt-abc"); again {
+		t.Error("second MarkEscalated should report already escalated")
+	}
+}
+
+func TestClaimResumeForgetsOldRecords(t *testing.T) {
+	townRoot := t.TempDir()
+	old := time.Now().Add(-8 * 24 * time.Hour)
+	if _, err := claimResume(townRoot, "gt-abc", "toast", 1, old); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := claimResume(townRoot, "gt-abc", "toast", 1, time.Now()); err != nil || n != 1 {
+		t.Errorf("claim after a week = %d, %v; want a fresh budget", n, err)
+	}
+}
+
+func TestResumePrompt(t *testing.T) {
+	r := &Resume{
+		Checkpoint: &Checkpoint{
+			MoleculeID:    "mol-1",
+			CurrentStep:   "implement",
+			StepTitle:     "Implement the fix",
+			Branch:        "polecat/toast",
+			LastCommit:    "0123456789abcdef",
+			ModifiedFiles: []string{"a.go", "b.go"},
+			SessionID:     "pid-42",
+		},
+		Bead:    "gt-abc",
+		Attempt: 2,
+		Max:     3,
+	}
+	prompt := r.Prompt()
+	for _, want := range []string{
+		"automatic resume 2 of 3",
+		"Hooked bead: gt-abc",
+		"mol-1, step implement (Implement the fix)",
+		"polecat/toast at 01234567",
+		"Uncommitted changes (2 files): a.go, b.go",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if r.SessionID() != "" {
+		t.Errorf("pid fallback should not be used as a session ID, got %q", r.SessionID())
+	}
+}
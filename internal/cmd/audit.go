@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/eventstore"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -104,19 +105,23 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	}
 	allEntries = append(allEntries, beadsEntries...)
 
-	// 3. Town log events
-	townlogEntries, err := collectTownlogEvents(townRoot, auditActor, sinceTime)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: could not query town log: %v\n", err)
-	}
-	allEntries = append(allEntries, townlogEntries...)
+	// 3-4. Town log and activity feed events, from the event store when the
+	// Dolt server is up, otherwise by reading the logs.
+	if storeEntries, err := collectStoreEvents(townRoot, auditActor, sinceTime, auditLimit); err == nil {
+		allEntries = append(allEntries, storeEntries...)
+	} else {
+		townlogEntries, err := collectTownlogEvents(townRoot, auditActor, sinceTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not query town log: %v\n", err)
+		}
+		allEntries = append(allEntries, townlogEntries...)
 
-	// 4. Activity feed events
-	feedEntries, err := collectFeedEvents(townRoot, auditActor, sinceTime)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: could not query events feed: %v\n", err)
+		feedEntries, err := collectFeedEvents(townRoot, auditActor, sinceTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not query events feed: %v\n", err)
+		}
+		allEntries = append(allEntries, feedEntries...)
 	}
-	allEntries = append(allEntries, feedEntries...)
 
 	// Sort by timestamp (newest first)
 	sort.Slice(allEntries, func(i, j int) bool {
@@ -333,6 +338,49 @@ func parseBeadsTimestamp(s string) time.Time {
 	return time.Time{}
 }
 
+// collectStoreEvents queries the event store for town log and activity feed
+// events. Returns an error if the store is unavailable.
+func collectStoreEvents(townRoot, actor string, since time.Time, limit int) ([]AuditEntry, error) {
+	store, err := eventstore.OpenTown(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	if _, err := store.Sync(townRoot); err != nil {
+		return nil, err
+	}
+
+	q := eventstore.Query{
+		Sources: []string{eventstore.SourceTownlog, eventstore.SourceEvents},
+		Since:   since,
+		Limit:   limit,
+	}
+	if actor != "" {
+		q.Actors = []string{extractAuthorName(actor)}
+	}
+	found, err := store.Query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	for _, e := range found {
+		entry := AuditEntry{
+			Timestamp: e.Time,
+			Source:    e.Source,
+			Type:      e.Type,
+			Actor:     e.Actor,
+		}
+		if e.Source == eventstore.SourceTownlog {
+			entry.Summary = formatTownlogSummary(townlog.Event{Type: townlog.EventType(e.Type), Agent: e.Actor})
+		} else {
+			entry.Summary = formatFeedSummary(events.Event{Type: e.Type, Actor: e.Actor, Payload: e.Payload})
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // collectTownlogEvents queries the town log for agent lifecycle events.
 func collectTownlogEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
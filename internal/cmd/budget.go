@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show today's spending against budgets",
+	Long: `Show today's spending against the town's budgets.
+
+Budgets are daily spending caps per rig, convoy, role and agent, configured
+in settings/budgets.json:
+
+  {
+    "type": "budgets",
+    "version": 1,
+    "rigs":    {"gastown": {"soft_usd": 40, "hard_usd": 60}},
+    "convoys": {"*": {"soft_usd": 15, "hard_usd": 25}},
+    "roles":   {"polecat": {"hard_usd": 100}},
+    "agents":  {"codex": {"soft_usd": 20}}
+  }
+
+A "*" entry applies to every scope of its kind without its own entry.
+Spending is today's total (local time) from the cost ledger that
+'gt costs record' appends to.
+
+Crossing a soft cap: the Deacon escalates to the Mayor (gt deacon budget-check).
+Crossing a hard cap: gt sling and the convoy dispatcher refuse to spawn
+polecats for the scope and open an override request. Approving it with
+'gt approvals approve <id>' lifts the cap until midnight.
+
+Examples:
+  gt costs budget          # Table of budgeted scopes
+  gt costs budget --json   # Machine-readable`,
+	RunE: runCostsBudget,
+}
+
+var deaconBudgetCheckCmd = &cobra.Command{
+	Use:   "budget-check",
+	Short: "Escalate budgets whose caps were crossed",
+	Long: `Compare today's spending with settings/budgets.json and mail the Mayor
+about each rig, convoy, role or agent that has crossed its soft or hard cap.
+
+Each scope is escalated once per cap per day; state is kept in
+deacon/budget-state.json. Does nothing when no budgets are configured.
+
+Examples:
+  gt deacon budget-check            # Escalate new cap crossings
+  gt deacon budget-check --dry-run  # Show what would be escalated`,
+	RunE: runDeaconBudgetCheck,
+}
+
+var (
+	budgetJSON        bool
+	budgetCheckDryRun bool
+)
+
+func init() {
+	costsCmd.AddCommand(costsBudgetCmd)
+	costsBudgetCmd.Flags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+
+	deaconCmd.AddCommand(deaconBudgetCheckCmd)
+	deaconBudgetCheckCmd.Flags().BoolVar(&budgetCheckDryRun, "dry-run", false, "Show what would be escalated without sending mail")
+	deaconBudgetCheckCmd.Flags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+}
+
+// loadBudgetStatuses returns today's status of every budgeted scope, or nil
+// when the town has no budgets.
+func loadBudgetStatuses(townRoot string) ([]budget.Status, error) {
+	cfg, err := budget.LoadConfig(townRoot)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	spend, err := budget.LoadSpend(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Evaluate(spend), nil
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	statuses, err := loadBudgetStatuses(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if budgetJSON {
+		if statuses == nil {
+			statuses = []budget.Status{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Printf("No budgets configured. Add caps to %s\n", budget.ConfigPath(townRoot))
+		return nil
+	}
+
+	fmt.Printf("\n%s Budgets for %s\n\n", style.Bold.Render("💰"), time.Now().Format("2006-01-02"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCOPE\tSPENT\tSOFT\tHARD\tSTATUS")
+	for _, st := range statuses {
+		fmt.Fprintf(w, "%s\t$%.2f\t%s\t%s\t%s\n", st.Scope, st.SpentUSD,
+			formatBudgetCap(st.Limit.SoftUSD), formatBudgetCap(st.Limit.HardUSD), formatBudgetLevel(st.Level))
+	}
+	return w.Flush()
+}
+
+func runDeaconBudgetCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	statuses, err := loadBudgetStatuses(townRoot)
+	if err != nil {
+		return err
+	}
+
+	alerts, err := deacon.CheckBudgets(townRoot, statuses, time.Now(), budgetCheckDryRun)
+	if err != nil {
+		return err
+	}
+
+	if budgetJSON {
+		if alerts == nil {
+			alerts = []deacon.BudgetAlert{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(alerts)
+	}
+	if len(alerts) == 0 {
+		fmt.Printf("%s No new budget cap crossings\n", style.Success.Render("✓"))
+		return nil
+	}
+	for _, a := range alerts {
+		verb := "Escalated"
+		if budgetCheckDryRun {
+			verb = "Would escalate"
+		}
+		line := fmt.Sprintf("%s %s: $%.2f spent (%s cap)", verb, a.Scope, a.SpentUSD, a.Level)
+		if a.Error != "" {
+			fmt.Printf("%s %s: %s\n", style.Warning.Render("⚠"), line, a.Error)
+			continue
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("→"), line)
+	}
+	return nil
+}
+
+func formatBudgetCap(usd float64) string {
+	if usd <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+func formatBudgetLevel(l budget.Level) string {
+	switch l {
+	case budget.LevelHard:
+		return style.Error.Render("over hard cap")
+	case budget.LevelSoft:
+		return style.Warning.Render("over soft cap")
+	default:
+		return style.Success.Render("ok")
+	}
+}
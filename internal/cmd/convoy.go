@@ -67,6 +67,7 @@ var (
 	convoyOwner        string
 	convoyOwned        bool
 	convoyMerge        string
+	convoyDue          string
 	convoyStatusJSON   bool
 	convoyListJSON     bool
 	convoyListStatus   string
@@ -194,7 +195,8 @@ Examples:
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
   gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
   gt convoy create --owned "Manual deploy" gt-abc           # caller-managed lifecycle
-  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery`,
+  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery
+  gt convoy create "Launch" gt-abc --due 2026-03-01         # refinery favors it as the date nears`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -331,6 +333,7 @@ func init() {
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
 	convoyCreateCmd.Flags().BoolVar(&convoyOwned, "owned", false, "Mark convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	convoyCreateCmd.Flags().StringVar(&convoyDue, "due", "", "Deadline: a date (2006-01-02), RFC3339 time, or duration from now (72h)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -396,6 +399,15 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	var dueAt string
+	if convoyDue != "" {
+		due, err := parseConvoyDueFlag(convoyDue, time.Now())
+		if err != nil {
+			return err
+		}
+		dueAt = due.UTC().Format(time.RFC3339)
+	}
+
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
 	if looksLikeIssueID(name) {
@@ -441,6 +453,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMerge != "" {
 		description += fmt.Sprintf("\nMerge: %s", convoyMerge)
 	}
+	if dueAt != "" {
+		description += fmt.Sprintf("\nDue: %s", dueAt)
+	}
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
@@ -518,6 +533,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMerge != "" {
 		fmt.Printf("  Merge:    %s\n", convoyMerge)
 	}
+	if dueAt != "" {
+		fmt.Printf("  Due:      %s\n", dueAt)
+	}
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
@@ -1579,6 +1597,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned         bool               `json:"owned"`
 			Lifecycle     string             `json:"lifecycle"`
 			MergeStrategy string             `json:"merge_strategy,omitempty"`
+			DueAt         string             `json:"due_at,omitempty"`
 			Tracked       []trackedIssueInfo `json:"tracked"`
 			Completed     int                `json:"completed"`
 			Total         int                `json:"total"`
@@ -1590,6 +1609,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned:         isOwned,
 			Lifecycle:     lifecycle,
 			MergeStrategy: parseConvoyMergeStrategy(convoy.Description),
+			DueAt:         parseConvoyDue(convoy.Description),
 			Tracked:       tracked,
 			Completed:     completed,
 			Total:         len(tracked),
@@ -1612,6 +1632,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	if merge != "" {
 		fmt.Printf("  Merge:     %s\n", merge)
 	}
+	if due := parseConvoyDue(convoy.Description); due != "" {
+		fmt.Printf("  Due:       %s\n", due)
+	}
 	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
@@ -1894,6 +1917,33 @@ func parseConvoyMergeStrategy(description string) string {
 	return ""
 }
 
+// parseConvoyDue extracts the deadline (RFC3339) from a convoy description,
+// or returns empty string if the convoy has none.
+func parseConvoyDue(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Due: ") {
+			return strings.TrimPrefix(line, "Due: ")
+		}
+	}
+	return ""
+}
+
+// parseConvoyDueFlag parses --due: an RFC3339 time, a date (end of that day,
+// UTC), or a duration from now.
+func parseConvoyDueFlag(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --due value %q: use a date (2006-01-02), RFC3339 time, or duration (72h)", value)
+}
+
 // formatYesNo returns "yes" or "no" for a boolean value.
 func formatYesNo(b bool) string {
 	if b {
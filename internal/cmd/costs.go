@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,9 +15,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -29,6 +27,7 @@ var (
 	costsWeek    bool
 	costsByRole  bool
 	costsByRig   bool
+	costsBy      string
 	costsVerbose bool
 
 	// Record subcommand flags
@@ -47,11 +46,21 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each agent's own session logs by summing token
+usage and applying model-specific pricing. The agent preset's usage_format
+picks the log reader: claude (~/.claude/projects/), codex (~/.codex/sessions/),
+gemini (~/.gemini/tmp/) or opencode (~/.local/share/opencode/). Agents
+without a usage format (cursor, amp, copilot, pi, auggie) are recorded at $0.
+
+Prices come from a built-in table, overridden per model by
+<town>/settings/pricing.json (see 'gt costs pricing').
+
+Session costs are attributed to the bead on the agent's hook and the convoy
+tracking it, so --by convoy shows what a feature cost across every agent
+that worked on it.
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -59,12 +68,15 @@ Examples:
   gt costs --week       # This week's costs from digest beads + today's log
   gt costs --by-role    # Breakdown by role (polecat, witness, etc.)
   gt costs --by-rig     # Breakdown by rig
+  gt costs --by convoy --week  # What each convoy cost this week
+  gt costs --by agent   # Breakdown by agent (claude, codex, ...)
   gt costs --json       # Output as JSON
   gt costs -v           # Show debug output for failures
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs pricing      # Show the effective pricing table`,
 	RunE: runCosts,
 }
 
@@ -73,12 +85,15 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
-and calculates the cost based on model pricing, then appends it to
-~/.gt/costs.jsonl. This is a simple append operation that never fails
+This command is intended to be called from the agent's Stop hook.
+It reads token usage from the agent's session log (chosen by GT_AGENT's
+usage_format) and calculates the cost based on model pricing, then appends
+it to ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
 
+The cost is attributed to --work-item, or else the bead on the agent's hook,
+and to the convoy tracking that bead.
+
 Session costs are aggregated daily by 'gt costs digest' into a single
 permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
@@ -128,6 +143,32 @@ Examples:
 	RunE: runCostsMigrate,
 }
 
+var costsPricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "Show the model pricing table used for costs",
+	Long: `Show the per-million-token prices gt costs applies to each model.
+
+Built-in prices can be overridden, and new models added, in
+<town>/settings/pricing.json:
+
+  {
+    "type": "pricing",
+    "version": 1,
+    "models": {
+      "gpt-5-codex": {"input_per_million": 1.25, "output_per_million": 10, "cache_read_per_million": 0.125},
+      "default":     {"input_per_million": 3, "output_per_million": 15}
+    }
+  }
+
+Model names match exactly or by prefix (the longest matching entry wins);
+"default" prices models that match nothing.
+
+Examples:
+  gt costs pricing
+  gt costs pricing --json`,
+	RunE: runCostsPricing,
+}
+
 func init() {
 	rootCmd.AddCommand(costsCmd)
 	costsCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
@@ -135,6 +176,7 @@ func init() {
 	costsCmd.Flags().BoolVar(&costsWeek, "week", false, "Show this week's total from session events")
 	costsCmd.Flags().BoolVar(&costsByRole, "by-role", false, "Show breakdown by role")
 	costsCmd.Flags().BoolVar(&costsByRig, "by-rig", false, "Show breakdown by rig")
+	costsCmd.Flags().StringVar(&costsBy, "by", "", "Show breakdown by: role, rig, agent, bead, convoy")
 	costsCmd.Flags().BoolVarP(&costsVerbose, "verbose", "v", false, "Show debug output for failures")
 
 	// Add record subcommand
@@ -148,6 +190,10 @@ func init() {
 	costsDigestCmd.Flags().StringVar(&digestDate, "date", "", "Digest a specific date (YYYY-MM-DD)")
 	costsDigestCmd.Flags().BoolVar(&digestDryRun, "dry-run", false, "Preview what would be done without making changes")
 
+	// Add pricing subcommand
+	costsCmd.AddCommand(costsPricingCmd)
+	costsPricingCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+
 	// Add migrate subcommand
 	costsCmd.AddCommand(costsMigrateCmd)
 	costsMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Preview what would be migrated without making changes")
@@ -159,6 +205,8 @@ type SessionCost struct {
 	Role    string  `json:"role"`
 	Rig     string  `json:"rig,omitempty"`
 	Worker  string  `json:"worker,omitempty"`
+	Agent   string  `json:"agent,omitempty"`
+	Model   string  `json:"model,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
 }
@@ -169,10 +217,13 @@ type CostEntry struct {
 	Role      string    `json:"role"`
 	Rig       string    `json:"rig,omitempty"`
 	Worker    string    `json:"worker,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
+	Model     string    `json:"model,omitempty"`
 	CostUSD   float64   `json:"cost_usd"`
 	StartedAt time.Time `json:"started_at"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
+	Convoy    string    `json:"convoy,omitempty"`
 }
 
 // CostsOutput is the JSON output structure.
@@ -181,65 +232,43 @@ type CostsOutput struct {
 	Total    float64            `json:"total_usd"`
 	ByRole   map[string]float64 `json:"by_role,omitempty"`
 	ByRig    map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent  map[string]float64 `json:"by_agent,omitempty"`
+	ByBead   map[string]float64 `json:"by_bead,omitempty"`
+	ByConvoy map[string]float64 `json:"by_convoy,omitempty"`
 	Period   string             `json:"period,omitempty"`
 }
 
-// costRegex matches cost patterns like "$1.23" or "$12.34"
-var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
-
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
+// Cost breakdown dimensions for --by.
+const (
+	costsByRoleDim   = "role"
+	costsByRigDim    = "rig"
+	costsByAgentDim  = "agent"
+	costsByBeadDim   = "bead"
+	costsByConvoyDim = "convoy"
+)
 
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
+// unattributedKey labels costs with no bead or convoy in a breakdown.
+const unattributedKey = "(unattributed)"
 
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
+// costRegex matches cost patterns like "$1.23" or "$12.34"
+var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
 func runCosts(cmd *cobra.Command, args []string) error {
+	switch costsBy {
+	case "", costsByRoleDim, costsByRigDim, costsByAgentDim, costsByBeadDim, costsByConvoyDim:
+	default:
+		return fmt.Errorf("invalid --by %q (valid: role, rig, agent, bead, convoy)", costsBy)
+	}
+	// --by-role and --by-rig are shorthands for --by role / --by rig
+	if costsByRole {
+		costsBy = costsByRoleDim
+	}
+	if costsByRig {
+		costsBy = costsByRigDim
+	}
+
 	// If querying ledger, use ledger functions
-	if costsToday || costsWeek || costsByRole || costsByRig {
+	if costsToday || costsWeek || costsBy != "" {
 		return runCostsFromLedger()
 	}
 
@@ -248,7 +277,8 @@ func runCosts(cmd *cobra.Command, args []string) error {
 }
 
 func runLiveCosts() error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
+	townRoot, _ := workspace.FindFromCwd()
 
 	// Get all tmux sessions
 	sessions, err := t.ListSessions()
@@ -256,7 +286,8 @@ func runLiveCosts() error {
 		return fmt.Errorf("listing sessions: %w", err)
 	}
 
-	var costs []SessionCost
+	pricing := loadCostsPricing(townRoot)
+	var sessionCosts []SessionCost
 	var total float64
 
 	for _, sess := range sessions {
@@ -277,24 +308,31 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent's session log
+		agent, _ := t.GetEnvironment(sess, "GT_AGENT")
+		var cost float64
+		var model string
+		usage, err := costs.SessionUsage(townRoot, agent, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", sess, err)
 			}
 			// Still include the session with zero cost
-			cost = 0.0
+		} else {
+			cost = pricing.Cost(usage)
+			model = usage.Model
 		}
 
 		// Check if an agent appears to be running
-		running := t.IsAgentRunning(sess)
+		running := t.IsAgentAlive(sess)
 
-		costs = append(costs, SessionCost{
+		sessionCosts = append(sessionCosts, SessionCost{
 			Session: sess,
 			Role:    role,
 			Rig:     rig,
 			Worker:  worker,
+			Agent:   agentOrDefault(agent),
+			Model:   model,
 			Cost:    cost,
 			Running: running,
 		})
@@ -302,18 +340,37 @@ func runLiveCosts() error {
 	}
 
 	// Sort by session name
-	sort.Slice(costs, func(i, j int) bool {
-		return costs[i].Session < costs[j].Session
+	sort.Slice(sessionCosts, func(i, j int) bool {
+		return sessionCosts[i].Session < sessionCosts[j].Session
 	})
 
 	if costsJSON {
 		return outputCostsJSON(CostsOutput{
-			Sessions: costs,
+			Sessions: sessionCosts,
 			Total:    total,
 		})
 	}
 
-	return outputCostsHuman(costs, total)
+	return outputCostsHuman(sessionCosts, total)
+}
+
+// loadCostsPricing loads the town's pricing table, falling back to the
+// built-in prices if the overrides file is broken.
+func loadCostsPricing(townRoot string) costs.PricingTable {
+	pricing, err := costs.LoadPricing(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (using built-in pricing)\n", err)
+	}
+	return pricing
+}
+
+// agentOrDefault returns the agent name, or the default preset if unset
+// (sessions started before GT_AGENT was recorded ran the default agent).
+func agentOrDefault(agent string) string {
+	if agent == "" {
+		return string(config.DefaultAgentPreset())
+	}
+	return agent
 }
 
 func runCostsFromLedger() error {
@@ -331,7 +388,7 @@ func runCostsFromLedger() error {
 	} else if costsWeek {
 		// For week: query digest beads (costs.digest events)
 		// These are the aggregated daily reports
-		entries, err = queryDigestBeads(7)
+		entries, err = queryDigestBeads(7, costsBy)
 		if err != nil {
 			return fmt.Errorf("querying digest beads: %w", err)
 		}
@@ -339,8 +396,8 @@ func runCostsFromLedger() error {
 		// Also include today's wisps (not yet digested)
 		todayEntries, _ := querySessionCostEntries(now)
 		entries = append(entries, todayEntries...)
-	} else if costsByRole || costsByRig {
-		// When using --by-role or --by-rig without time filter, default to today
+	} else if costsBy != "" {
+		// When using --by (or --by-role/--by-rig) without time filter, default to today
 		// (querying all historical events would be expensive and likely empty)
 		entries, err = querySessionCostEntries(now)
 		if err != nil {
@@ -359,15 +416,8 @@ func runCostsFromLedger() error {
 
 	// Calculate totals
 	var total float64
-	byRole := make(map[string]float64)
-	byRig := make(map[string]float64)
-
 	for _, entry := range entries {
 		total += entry.CostUSD
-		byRole[entry.Role] += entry.CostUSD
-		if entry.Rig != "" {
-			byRig[entry.Rig] += entry.CostUSD
-		}
 	}
 
 	// Build output
@@ -375,11 +425,17 @@ func runCostsFromLedger() error {
 		Total: total,
 	}
 
-	if costsByRole {
-		output.ByRole = byRole
-	}
-	if costsByRig {
-		output.ByRig = byRig
+	switch costsBy {
+	case costsByRoleDim:
+		output.ByRole = costBreakdown(entries, costsByRoleDim)
+	case costsByRigDim:
+		output.ByRig = costBreakdown(entries, costsByRigDim)
+	case costsByAgentDim:
+		output.ByAgent = costBreakdown(entries, costsByAgentDim)
+	case costsByBeadDim:
+		output.ByBead = costBreakdown(entries, costsByBeadDim)
+	case costsByConvoyDim:
+		output.ByConvoy = costBreakdown(entries, costsByConvoyDim)
 	}
 
 	// Set period label
@@ -396,6 +452,39 @@ func runCostsFromLedger() error {
 	return outputLedgerHuman(output, entries)
 }
 
+// costBreakdown sums entry costs by one dimension. Rig breakdowns skip
+// town-level sessions (as they always have); bead and convoy breakdowns keep
+// unattributed costs under their own key so the parts add up to the total.
+func costBreakdown(entries []CostEntry, by string) map[string]float64 {
+	out := make(map[string]float64)
+	for _, e := range entries {
+		var key string
+		switch by {
+		case costsByRoleDim:
+			key = e.Role
+		case costsByRigDim:
+			if e.Rig == "" {
+				continue
+			}
+			key = e.Rig
+		case costsByAgentDim:
+			key = e.Agent
+			if key == "" {
+				key = string(config.DefaultAgentPreset())
+			}
+		case costsByBeadDim:
+			key = e.WorkItem
+		case costsByConvoyDim:
+			key = e.Convoy
+		}
+		if key == "" {
+			key = unattributedKey
+		}
+		out[key] += e.CostUSD
+	}
+	return out
+}
+
 // SessionEvent represents a session.ended event from beads.
 type SessionEvent struct {
 	ID        string    `json:"id"`
@@ -563,7 +652,9 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 }
 
 // queryDigestBeads queries costs.digest events from the past N days and extracts session entries.
-func queryDigestBeads(days int) ([]CostEntry, error) {
+// Digests only keep aggregates, so entries are synthesized from the aggregate
+// matching the requested breakdown (see digestEntries).
+func queryDigestBeads(days int, by string) ([]CostEntry, error) {
 	// Get list of event IDs
 	listArgs := []string{
 		"list",
@@ -634,24 +725,70 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 		}
 
 		// If the digest has per-session data (old format), use it directly.
-		// Otherwise, synthesize entries from the aggregate ByRole data.
+		// Otherwise, synthesize entries from the aggregates.
 		if len(digest.Sessions) > 0 {
 			entries = append(entries, digest.Sessions...)
 		} else {
-			for role, cost := range digest.ByRole {
-				entries = append(entries, CostEntry{
-					SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, role),
-					Role:      role,
-					CostUSD:   cost,
-					EndedAt:   digestDate,
-				})
-			}
+			entries = append(entries, digestEntries(digest, digestDate, by)...)
 		}
 	}
 
 	return entries, nil
 }
 
+// digestEntries synthesizes ledger entries from a digest's aggregate for one
+// breakdown (ByRole by default). Cost the aggregate doesn't cover (town-level
+// sessions in ByRig, or digests written before an aggregate existed) becomes
+// one entry with that dimension unset, so totals still match the digest.
+func digestEntries(digest CostDigest, date time.Time, by string) []CostEntry {
+	var agg map[string]float64
+	set := func(e *CostEntry, key string) { e.Role = key }
+	switch by {
+	case costsByRigDim:
+		agg = digest.ByRig
+		set = func(e *CostEntry, key string) { e.Rig = key }
+	case costsByAgentDim:
+		agg = digest.ByAgent
+		set = func(e *CostEntry, key string) { e.Agent = key }
+	case costsByBeadDim:
+		agg = digest.ByBead
+		set = func(e *CostEntry, key string) { e.WorkItem = key }
+	case costsByConvoyDim:
+		agg = digest.ByConvoy
+		set = func(e *CostEntry, key string) { e.Convoy = key }
+	default:
+		agg = digest.ByRole
+	}
+
+	keys := make([]string, 0, len(agg))
+	for key := range agg {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries []CostEntry
+	remainder := digest.TotalUSD
+	for _, key := range keys {
+		e := CostEntry{
+			SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, key),
+			CostUSD:   agg[key],
+			EndedAt:   date,
+		}
+		set(&e, key)
+		entries = append(entries, e)
+		remainder -= agg[key]
+	}
+	// Ignore float noise left after subtracting a complete aggregate.
+	if remainder > 0.005 {
+		entries = append(entries, CostEntry{
+			SessionID: fmt.Sprintf("digest-%s-rest", digest.Date),
+			CostUSD:   remainder,
+			EndedAt:   date,
+		})
+	}
+	return entries
+}
+
 // parseSessionName extracts role, rig, and worker from a session name.
 // Delegates to session.ParseSessionName for correct handling of hyphenated rig names.
 func parseSessionName(sess string) (role, rig, worker string) {
@@ -699,146 +836,6 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
-	}
-	return latestPath, nil
-}
-
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
-}
-
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
-	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
-}
-
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
-	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
-	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
-	}
-
-	return calculateCost(usage), nil
-}
-
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
 func getTmuxSessionWorkDir(session string) (string, error) {
 	cmd := exec.Command("tmux", "display-message", "-t", session, "-p", "#{pane_current_path}")
@@ -927,30 +924,44 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 		}
 	}
 
+	printCostBreakdown("By Agent:", output.ByAgent)
+	printCostBreakdown("By Bead:", output.ByBead)
+	printCostBreakdown("By Convoy:", output.ByConvoy)
+
 	// Session count
 	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), len(entries))
 
 	return nil
 }
 
-// CostLogEntry represents a single entry in the costs.jsonl log file.
-type CostLogEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
+// printCostBreakdown prints a breakdown, most expensive first.
+func printCostBreakdown(title string, breakdown map[string]float64) {
+	if len(breakdown) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(breakdown))
+	for key := range breakdown {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if breakdown[keys[i]] != breakdown[keys[j]] {
+			return breakdown[keys[i]] > breakdown[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Printf("\n%s\n", style.Bold.Render(title))
+	for _, key := range keys {
+		fmt.Printf("  %-20s $%.2f\n", key, breakdown[key])
+	}
 }
 
+// CostLogEntry represents a single entry in the costs.jsonl log file.
+type CostLogEntry = costs.LedgerEntry
+
 // getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
 func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
-	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
+	return costs.LedgerPath()
 }
 
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
@@ -987,31 +998,52 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Extract cost from the agent's session log
+	townRoot, _ := workspace.FindFromCwd()
+	agent := os.Getenv("GT_AGENT")
 	var cost float64
+	var usage *costs.Usage
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		usage, err = costs.SessionUsage(townRoot, agent, workDir)
 		if err != nil {
 			if costsVerbose {
-				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
+				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from session log: %v\n", err)
 			}
-			cost = 0.0
+			usage = nil
+		} else {
+			cost = loadCostsPricing(townRoot).Cost(usage)
 		}
 	}
 
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
 
+	// Attribute to the work item and the convoy tracking it
+	workItem := recordWorkItem
+	if workItem == "" && workDir != "" {
+		workItem = detectCostWorkItem(workDir)
+	}
+	var convoyID string
+	if workItem != "" {
+		convoyID = costConvoyForBead(workItem, workDir)
+	}
+
 	// Build log entry
 	entry := CostLogEntry{
 		SessionID: session,
 		Role:      role,
 		Rig:       rig,
 		Worker:    worker,
+		Agent:     agentOrDefault(agent),
+		Usage:     usage,
 		CostUSD:   cost,
 		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
+		WorkItem:  workItem,
+		Convoy:    convoyID,
+	}
+	if usage != nil {
+		entry.Model = usage.Model
 	}
 
 	// Marshal to JSON
@@ -1046,8 +1078,8 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Output confirmation (silent if cost is zero and no work item)
 	if cost > 0 || recordWorkItem != "" {
 		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
+		if workItem != "" {
+			fmt.Printf(" (work: %s)", workItem)
 		}
 		fmt.Println()
 	}
@@ -1055,6 +1087,24 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// detectCostWorkItem returns the bead on the current agent's hook, if any.
+func detectCostWorkItem(workDir string) string {
+	roleInfo, err := GetRole()
+	if err != nil {
+		return ""
+	}
+	return detectHookedBead(workDir, roleInfo)
+}
+
+// costConvoyForBead returns the convoy tracking a bead: from the convoy_id
+// gt sling stored on the bead, else by asking beads what tracks it.
+func costConvoyForBead(beadID, workDir string) string {
+	if info := getConvoyInfoFromIssue(beadID, workDir); info != nil {
+		return info.ID
+	}
+	return isTrackedByConvoy(beadID)
+}
+
 // deriveSessionName derives the tmux session name from GT_* environment variables.
 // Uses session.* helpers for canonical naming. Parses GT_ROLE via parseRoleString
 // so compound forms (e.g. "gastown/witness") resolve to their canonical session names.
@@ -1133,6 +1183,9 @@ type CostDigest struct {
 	Sessions     []CostEntry        `json:"sessions,omitempty"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByBead       map[string]float64 `json:"by_bead,omitempty"`
+	ByConvoy     map[string]float64 `json:"by_convoy,omitempty"`
 }
 
 // CostDigestPayload is the compact payload stored in the bead.
@@ -1143,6 +1196,9 @@ type CostDigestPayload struct {
 	SessionCount int                `json:"session_count"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByBead       map[string]float64 `json:"by_bead,omitempty"`
+	ByConvoy     map[string]float64 `json:"by_convoy,omitempty"`
 }
 
 // runCostsDigest aggregates session cost entries into a daily digest bead.
@@ -1181,6 +1237,9 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 		Sessions: costEntries,
 		ByRole:   make(map[string]float64),
 		ByRig:    make(map[string]float64),
+		ByAgent:  costBreakdown(costEntries, costsByAgentDim),
+		ByBead:   attributedOnly(costBreakdown(costEntries, costsByBeadDim)),
+		ByConvoy: attributedOnly(costBreakdown(costEntries, costsByConvoyDim)),
 	}
 
 	for _, e := range costEntries {
@@ -1206,6 +1265,12 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 				fmt.Printf("    %s: $%.2f\n", rig, cost)
 			}
 		}
+		if len(digest.ByConvoy) > 0 {
+			fmt.Printf("  By Convoy:\n")
+			for convoy, cost := range digest.ByConvoy {
+				fmt.Printf("    %s: $%.2f\n", convoy, cost)
+			}
+		}
 		return nil
 	}
 
@@ -1230,6 +1295,16 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// attributedOnly drops the unattributed bucket from a breakdown. Digests keep
+// it implicit: whatever the aggregate doesn't cover is unattributed.
+func attributedOnly(breakdown map[string]float64) map[string]float64 {
+	delete(breakdown, unattributedKey)
+	if len(breakdown) == 0 {
+		return nil
+	}
+	return breakdown
+}
+
 // querySessionCostEntries reads session cost entries from the local log file for a target date.
 func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 	logPath := getCostsLogPath()
@@ -1272,9 +1347,12 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 			Role:      logEntry.Role,
 			Rig:       logEntry.Rig,
 			Worker:    logEntry.Worker,
+			Agent:     logEntry.Agent,
+			Model:     logEntry.Model,
 			CostUSD:   logEntry.CostUSD,
 			EndedAt:   logEntry.EndedAt,
 			WorkItem:  logEntry.WorkItem,
+			Convoy:    logEntry.Convoy,
 		})
 	}
 
@@ -1315,6 +1393,19 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		desc.WriteString("\n")
 	}
 
+	if len(digest.ByConvoy) > 0 {
+		desc.WriteString("## By Convoy\n")
+		convoys := make([]string, 0, len(digest.ByConvoy))
+		for convoy := range digest.ByConvoy {
+			convoys = append(convoys, convoy)
+		}
+		sort.Strings(convoys)
+		for _, convoy := range convoys {
+			desc.WriteString(fmt.Sprintf("- %s: $%.2f\n", convoy, digest.ByConvoy[convoy]))
+		}
+		desc.WriteString("\n")
+	}
+
 	// Build compact payload (aggregate only, no per-session details).
 	// Per-session details can be thousands of records and exceed Dolt column limits.
 	compactPayload := CostDigestPayload{
@@ -1323,6 +1414,9 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		SessionCount: digest.SessionCount,
 		ByRole:       digest.ByRole,
 		ByRig:        digest.ByRig,
+		ByAgent:      digest.ByAgent,
+		ByBead:       digest.ByBead,
+		ByConvoy:     digest.ByConvoy,
 	}
 	payloadJSON, err := json.Marshal(compactPayload)
 	if err != nil {
@@ -1415,6 +1509,46 @@ func deleteSessionCostEntries(targetDate time.Time) (int, error) {
 	return deletedCount, nil
 }
 
+// runCostsPricing prints the effective pricing table.
+func runCostsPricing(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	pricing, err := costs.LoadPricing(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pricing)
+	}
+
+	builtin := costs.DefaultPricing()
+	models := make([]string, 0, len(pricing))
+	for model := range pricing {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	fmt.Printf("%-28s %10s %10s %12s %12s\n", "Model", "Input", "Output", "Cache read", "Cache write")
+	fmt.Println(strings.Repeat("─", 76))
+	for _, model := range models {
+		p := pricing[model]
+		note := ""
+		if b, ok := builtin[model]; !ok || b != p {
+			note = " " + style.Dim.Render("(pricing.json)")
+		}
+		fmt.Printf("%-28s %10s %10s %12s %12s%s\n", model,
+			fmt.Sprintf("$%.3f", p.InputPerMillion), fmt.Sprintf("$%.3f", p.OutputPerMillion),
+			fmt.Sprintf("$%.3f", p.CacheReadPerMillion), fmt.Sprintf("$%.3f", p.CacheWritePerMillion), note)
+	}
+	fmt.Println(style.Dim.Render("\nPrices in USD per million tokens."))
+	if townRoot != "" {
+		fmt.Println(style.Dim.Render("Overrides: " + costs.PricingPath(townRoot)))
+	}
+	return nil
+}
+
 // runCostsMigrate migrates legacy session.ended beads to the new architecture.
 func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	// Query all session.ended events (both open and closed)
@@ -264,3 +264,77 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestCostBreakdown(t *testing.T) {
+	entries := []CostEntry{
+		{Role: "polecat", Rig: "gastown", Agent: "claude", WorkItem: "gt-a", Convoy: "hq-cv-1", CostUSD: 2.0},
+		{Role: "polecat", Rig: "gastown", Agent: "codex", WorkItem: "gt-b", Convoy: "hq-cv-1", CostUSD: 3.0},
+		{Role: "refinery", Rig: "beads", WorkItem: "bd-c", CostUSD: 1.0},
+		{Role: "mayor", CostUSD: 0.5},
+	}
+
+	tests := []struct {
+		by   string
+		want map[string]float64
+	}{
+		{costsByRoleDim, map[string]float64{"polecat": 5.0, "refinery": 1.0, "mayor": 0.5}},
+		{costsByRigDim, map[string]float64{"gastown": 5.0, "beads": 1.0}},
+		{costsByAgentDim, map[string]float64{"claude": 3.5, "codex": 3.0}},
+		{costsByBeadDim, map[string]float64{"gt-a": 2.0, "gt-b": 3.0, "bd-c": 1.0, unattributedKey: 0.5}},
+		{costsByConvoyDim, map[string]float64{"hq-cv-1": 5.0, unattributedKey: 1.5}},
+	}
+	for _, tt := range tests {
+		got := costBreakdown(entries, tt.by)
+		if len(got) != len(tt.want) {
+			t.Errorf("by %s = %v, want %v", tt.by, got, tt.want)
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("by %s [%s] = %v, want %v", tt.by, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestDigestEntries(t *testing.T) {
+	digest := CostDigest{
+		Date:     "2026-10-15",
+		TotalUSD: 10.0,
+		ByRole:   map[string]float64{"polecat": 8.0, "mayor": 2.0},
+		ByRig:    map[string]float64{"gastown": 8.0},
+		ByConvoy: map[string]float64{"hq-cv-1": 6.0},
+	}
+	date := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	sum := func(entries []CostEntry) float64 {
+		var total float64
+		for _, e := range entries {
+			total += e.CostUSD
+		}
+		return total
+	}
+
+	for _, by := range []string{"", costsByRigDim, costsByAgentDim, costsByConvoyDim} {
+		entries := digestEntries(digest, date, by)
+		if got := sum(entries); got != digest.TotalUSD {
+			t.Errorf("by %q: entries sum to %v, want %v", by, got, digest.TotalUSD)
+		}
+	}
+
+	convoys := costBreakdown(digestEntries(digest, date, costsByConvoyDim), costsByConvoyDim)
+	if convoys["hq-cv-1"] != 6.0 || convoys[unattributedKey] != 4.0 {
+		t.Errorf("convoy breakdown = %v", convoys)
+	}
+
+	// Digests written before agent attribution only saw the default agent.
+	agents := costBreakdown(digestEntries(digest, date, costsByAgentDim), costsByAgentDim)
+	if agents["claude"] != 10.0 {
+		t.Errorf("agent breakdown of old digest = %v", agents)
+	}
+
+	roles := costBreakdown(digestEntries(digest, date, ""), costsByRoleDim)
+	if roles["polecat"] != 8.0 || roles["mayor"] != 2.0 {
+		t.Errorf("role breakdown = %v", roles)
+	}
+}
@@ -102,6 +102,9 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("finding town root: %w", err)
 	}
+	if backend := session.BackendName(townRoot); backend != config.SessionBackendTmux {
+		return fmt.Errorf("gt crew at attaches to a tmux session; session_backend is %s (use gt crew start)", backend)
+	}
 	accountsPath := constants.MayorAccountsPath(townRoot)
 	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, crewAccount)
 	if err != nil {
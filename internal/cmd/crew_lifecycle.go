@@ -18,7 +18,6 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -50,7 +49,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 
 		// Check for running session (unless forced)
 		if !forceRemove {
-			t := tmux.NewTmux()
+			t := townSessionBackend()
 			sessionID := crewSessionName(r.Name, name)
 			hasSession, _ := t.HasSession(sessionID)
 			if hasSession {
@@ -61,7 +60,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 		}
 
 		// Kill session if it exists (with proper process cleanup to avoid orphans)
-		t := tmux.NewTmux()
+		t := townSessionBackend()
 		sessionID := crewSessionName(r.Name, name)
 		if hasSession, _ := t.HasSession(sessionID); hasSession {
 			if err := t.KillSessionWithProcesses(sessionID); err != nil {
@@ -572,7 +571,7 @@ func runCrewStop(cmd *cobra.Command, args []string) error {
 	}
 
 	var lastErr error
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	for _, arg := range args {
 		name := arg
@@ -695,7 +694,7 @@ func runCrewStopAll() error {
 	fmt.Printf("%s Stopping %d crew session(s)...\n\n",
 		style.Bold.Render("🛑"), len(targets))
 
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 	var succeeded, failed int
 	var failures []string
 
@@ -436,7 +436,7 @@ func init() {
 }
 
 func runDeaconStart(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -461,7 +461,7 @@ func runDeaconStart(cmd *cobra.Command, args []string) error {
 }
 
 // startDeaconSession creates and initializes the Deacon tmux session.
-func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
+func startDeaconSession(t session.SessionBackend, sessionName, agentOverride string) error {
 	// Find workspace root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -511,8 +511,10 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 
 	// Apply Deacon theme (non-fatal: theming failure doesn't affect operation)
 	// Note: ConfigureGasTownSession includes cycle bindings
-	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionName, theme, "", "Deacon", "health-check")
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		theme := tmux.DeaconTheme()
+		_ = realTmux.ConfigureGasTownSession(sessionName, theme, "", "Deacon", "health-check")
+	}
 
 	// Wait for Claude to start
 	if err := t.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -533,7 +535,7 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 }
 
 func runDeaconStop(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -563,7 +565,7 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 }
 
 func runDeaconAttach(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -605,7 +607,7 @@ type HeartbeatStatus struct {
 }
 
 func runDeaconStatus(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	sessionName := getDeaconSessionName()
 	townRoot, _ := workspace.FindFromCwdOrError()
@@ -722,7 +724,7 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runDeaconRestart(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -877,7 +879,7 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid agent address: %w", err)
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	// Check if session exists
 	exists, err := t.HasSession(sessionName)
@@ -1004,7 +1006,7 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid agent address: %w", err)
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	// Check if session exists
 	exists, err := t.HasSession(sessionName)
@@ -15,7 +15,6 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/plugin"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -26,6 +25,7 @@ var (
 	dogForce      bool
 	dogRemoveAll  bool
 	dogCallAll    bool
+	dogDoneFailed bool
 
 	// Dispatch flags
 	dogDispatchPlugin string
@@ -139,14 +139,17 @@ var dogDoneCmd = &cobra.Command{
 
 Dogs should call this when they complete their work assignment.
 This clears the work field and sets state to idle, making the dog
-available for new work.
+available for new work. When the work was a plugin, the run is recorded
+on the plugin ledger (as failed with --failed), which is what plugin
+gates measure cooldowns and schedules from.
 
 Without a name argument, auto-detects the current dog from the working
 directory (must be run from within a dog's worktree).
 
 Examples:
   gt dog done         # Auto-detect from cwd
-  gt dog done alpha   # Explicit name`,
+  gt dog done alpha   # Explicit name
+  gt dog done --failed  # The plugin run failed`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runDogDone,
 }
@@ -233,6 +236,9 @@ func init() {
 	// Clear flags (reuses dogForce from remove)
 	dogClearCmd.Flags().BoolVarP(&dogForce, "force", "f", false, "Force clear even if session exists")
 
+	// Done flags
+	dogDoneCmd.Flags().BoolVar(&dogDoneFailed, "failed", false, "Record the plugin run as failed")
+
 	// Status flags
 	dogStatusCmd.Flags().BoolVar(&dogStatusJSON, "json", false, "Output as JSON")
 
@@ -569,7 +575,7 @@ func runDogClear(cmd *cobra.Command, args []string) error {
 	// Check for live tmux session
 	if !dogForce {
 		sessionName := fmt.Sprintf("hq-dog-%s", name)
-		tm := tmux.NewTmux()
+		tm := townSessionBackend()
 		if has, _ := tm.HasSession(sessionName); has {
 			return fmt.Errorf("dog %s has an active session (%s)\nUse --force to clear anyway", name, sessionName)
 		}
@@ -628,6 +634,18 @@ func runDogDone(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Record the plugin run before going idle, so the scheduler never sees
+	// the dog free without the run that lets the gate close.
+	if record, ok := pluginRunForDogWork(name, d.Work, dogDoneFailed); ok {
+		townRoot, err := workspace.FindFromCwd()
+		if err == nil {
+			_, err = plugin.NewRecorder(townRoot).RecordRun(record)
+		}
+		if err != nil {
+			style.PrintWarning("could not record %s run: %v", record.PluginName, err)
+		}
+	}
+
 	if err := mgr.ClearWork(name); err != nil {
 		return fmt.Errorf("clearing work for dog %s: %w", name, err)
 	}
@@ -636,6 +654,25 @@ func runDogDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pluginRunForDogWork returns the plugin run to record when a dog finishes
+// work, if the work was a plugin dispatched by gt dog dispatch.
+func pluginRunForDogWork(dogName, work string, failed bool) (plugin.PluginRunRecord, bool) {
+	name, ok := strings.CutPrefix(work, "plugin:")
+	if !ok || name == "" {
+		return plugin.PluginRunRecord{}, false
+	}
+	record := plugin.PluginRunRecord{
+		PluginName: name,
+		Result:     plugin.ResultSuccess,
+		Body:       fmt.Sprintf("Completed by dog %s", dogName),
+	}
+	if failed {
+		record.Result = plugin.ResultFailure
+		record.Body = fmt.Sprintf("Failed (reported by dog %s)", dogName)
+	}
+	return record, true
+}
+
 func splitPathComponents(path string) []string {
 	if path == "" {
 		return nil
@@ -699,7 +736,7 @@ func showDogStatus(mgr *dog.Manager, name string) error {
 
 	// Check for tmux session
 	sessionName := fmt.Sprintf("hq-dog-%s", name)
-	tm := tmux.NewTmux()
+	tm := townSessionBackend()
 	if has, _ := tm.HasSession(sessionName); has {
 		fmt.Printf("\nSession: %s (running)\n", sessionName)
 	}
@@ -1007,9 +1044,8 @@ func formatPluginMailBody(p *plugin.Plugin) string {
 	sb.WriteString(p.Instructions)
 	sb.WriteString("\n\n---\n\n")
 	sb.WriteString("After completion:\n")
-	sb.WriteString("1. Create a wisp to record the result (success/failure)\n")
-	sb.WriteString("2. Send DOG_DONE mail to deacon/\n")
-	sb.WriteString("3. Return to idle state\n")
+	sb.WriteString("1. Send DOG_DONE mail to deacon/\n")
+	sb.WriteString("2. Run `gt dog done` (`gt dog done --failed` if the plugin failed) to record the run and return to idle\n")
 
 	return sb.String()
 }
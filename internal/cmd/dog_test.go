@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/plugin"
 )
 
 // =============================================================================
@@ -272,6 +273,24 @@ func TestDogDone_WorkingToIdle(t *testing.T) {
 	}
 }
 
+// TestPluginRunForDogWork verifies that finishing plugin work records a run
+// and finishing other work does not.
+func TestPluginRunForDogWork(t *testing.T) {
+	r, ok := pluginRunForDogWork("alpha", "plugin:rebuild-gt", false)
+	if !ok || r.PluginName != "rebuild-gt" || r.Result != plugin.ResultSuccess {
+		t.Errorf("plugin work = %+v, %v; want a success run of rebuild-gt", r, ok)
+	}
+	r, _ = pluginRunForDogWork("alpha", "plugin:rebuild-gt", true)
+	if r.Result != plugin.ResultFailure {
+		t.Errorf("--failed result = %q, want failure", r.Result)
+	}
+	for _, work := range []string{"", "hq-convoy-xyz", "plugin:"} {
+		if _, ok := pluginRunForDogWork("alpha", work, false); ok {
+			t.Errorf("work %q recorded a plugin run", work)
+		}
+	}
+}
+
 // TestDogDone_NotFound verifies error handling for non-existent dog.
 func TestDogDone_NotFound(t *testing.T) {
 	m, _ := testDogManager(t)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -184,6 +185,10 @@ This command automates the tedious process of pushing each database individually
 Use --db to sync a single database, --dry-run to preview, or --force for force-push.
 Use --gc to purge closed ephemeral beads (wisps, convoys) before pushing.
 
+Databases without a remote get one automatically when DOLTHUB_TOKEN and
+DOLTHUB_ORG are set. Set GT_DOLT_REMOTE to a file:// URL or absolute path
+(and GT_DOLT_REMOTE_ORG) to create the remotes on a file system instead.
+
 Examples:
   gt dolt sync                # Push all databases with remotes
   gt dolt sync --dry-run      # Preview what would be pushed
@@ -232,6 +237,58 @@ formula's backup step (migration-backup-YYYYMMDD-HHMMSS/).`,
 	RunE: runDoltRollback,
 }
 
+var doltBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up Dolt databases and record a restore point",
+	Long: `Back up the town's Dolt databases to .dolt-backups/ and record a restore point.
+
+For each database this commits the working set, tags HEAD as
+gt-backup-<id>, and syncs the database (full history and tags) to a Dolt
+backup under .dolt-backups/mirrors/. The backup is then verified with the
+same check as 'gt dolt status': every database on disk must be served by
+the Dolt server. Runs against the live server; nothing is stopped.
+
+Retention then thins the restore points to the newest per hour for a
+day, per day for a week and per week for a month (configurable under
+patrols.dolt_backups in mayor/daemon.json). The daemon runs this hourly.
+
+Examples:
+  gt dolt backup              # Back up all databases
+  gt dolt backup --db gastown # Back up one database
+  gt dolt backup --list       # List restore points`,
+	Args: cobra.NoArgs,
+	RunE: runDoltBackup,
+}
+
+var doltRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore Dolt databases to an earlier point in time",
+	Long: `Restore Dolt databases to an earlier state using Dolt's commit history.
+
+--at takes a backup ID (see 'gt dolt backup --list'), a commit hash, or a
+time: a duration ago (90m, 6h, 2d), a date (2026-03-09), a local time
+(2026-03-09T14:00) or an RFC 3339 timestamp. A time restores each database
+to its newest commit at or before that time.
+
+Before resetting, the current state (including uncommitted changes) is
+committed and kept on a gt-pre-restore-<timestamp> branch, so a restore
+can be undone by restoring to that branch's commit.
+
+--from-backup rebuilds databases from their backup mirrors, for when a
+database on disk is damaged. It stops the Dolt server, moves the damaged
+database aside to .dolt-backups/damaged/, restores the mirror (the state
+of the last backup) and restarts the server. Combine it with --at to go
+further back.
+
+Examples:
+  gt dolt restore --at 2h --dry-run            # Preview targets
+  gt dolt restore --at 20260309-140000         # Restore a backup's restore point
+  gt dolt restore --at 2026-03-09T14:00 --db hq
+  gt dolt restore --from-backup --db gastown   # Rebuild a damaged database`,
+	Args: cobra.NoArgs,
+	RunE: runDoltRestore,
+}
+
 var (
 	doltLogLines     int
 	doltLogFollow    bool
@@ -243,6 +300,15 @@ var (
 	doltSyncForce    bool
 	doltSyncDB       string
 	doltSyncGC       bool
+
+	doltBackupDB      string
+	doltBackupList    bool
+	doltBackupNoPrune bool
+
+	doltRestoreAt         string
+	doltRestoreDB         string
+	doltRestoreDry        bool
+	doltRestoreFromBackup bool
 )
 
 func init() {
@@ -260,6 +326,8 @@ func init() {
 	doltCmd.AddCommand(doltCleanupCmd)
 	doltCmd.AddCommand(doltRollbackCmd)
 	doltCmd.AddCommand(doltSyncCmd)
+	doltCmd.AddCommand(doltBackupCmd)
+	doltCmd.AddCommand(doltRestoreCmd)
 
 	doltCleanupCmd.Flags().BoolVar(&doltCleanupDry, "dry-run", false, "Preview what would be removed without making changes")
 
@@ -276,6 +344,15 @@ func init() {
 	doltSyncCmd.Flags().StringVar(&doltSyncDB, "db", "", "Sync a single database instead of all")
 	doltSyncCmd.Flags().BoolVar(&doltSyncGC, "gc", false, "Purge closed ephemeral beads before push (requires bd purge)")
 
+	doltBackupCmd.Flags().StringVar(&doltBackupDB, "db", "", "Back up a single database instead of all")
+	doltBackupCmd.Flags().BoolVar(&doltBackupList, "list", false, "List restore points and exit")
+	doltBackupCmd.Flags().BoolVar(&doltBackupNoPrune, "no-prune", false, "Keep all restore points (skip retention)")
+
+	doltRestoreCmd.Flags().StringVar(&doltRestoreAt, "at", "", "Backup ID, commit hash or time to restore to")
+	doltRestoreCmd.Flags().StringVar(&doltRestoreDB, "db", "", "Restore a single database instead of all")
+	doltRestoreCmd.Flags().BoolVar(&doltRestoreDry, "dry-run", false, "Show restore targets without making changes")
+	doltRestoreCmd.Flags().BoolVar(&doltRestoreFromBackup, "from-backup", false, "Rebuild databases from their backup mirrors (stops the server)")
+
 	rootCmd.AddCommand(doltCmd)
 }
 
@@ -543,7 +620,7 @@ func runDoltSQL(cmd *cobra.Command, args []string) error {
 	}
 
 	// Server not running - list databases and pick first one for embedded mode
-	databases, err := doltserver.ListDatabases(townRoot)
+	databases, err := doltserver.ListRigDatabases(townRoot)
 	if err != nil {
 		return fmt.Errorf("listing databases: %w", err)
 	}
@@ -614,7 +691,7 @@ func runDoltInit(cmd *cobra.Command, args []string) error {
 
 	if len(broken) == 0 {
 		// Also check if there are any databases at all
-		databases, _ := doltserver.ListDatabases(townRoot)
+		databases, _ := doltserver.ListRigDatabases(townRoot)
 		if len(databases) == 0 {
 			fmt.Println("No Dolt databases found and no workspaces configured for Dolt.")
 			fmt.Printf("\nInitialize a rig database with: %s\n", style.Dim.Render("gt dolt init-rig <name>"))
@@ -724,7 +801,7 @@ func runDoltList(cmd *cobra.Command, args []string) error {
 	}
 
 	config := doltserver.DefaultConfig(townRoot)
-	databases, err := doltserver.ListDatabases(townRoot)
+	databases, err := doltserver.ListRigDatabases(townRoot)
 	if err != nil {
 		return fmt.Errorf("listing databases: %w", err)
 	}
@@ -1141,7 +1218,7 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 		if !wasRunning {
 			fmt.Fprintf(os.Stderr, "Warning: --gc requires a running Dolt server, skipping purge\n")
 		} else {
-			databases, listErr := doltserver.ListDatabases(townRoot)
+			databases, listErr := doltserver.ListRigDatabases(townRoot)
 			if listErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: --gc: could not list databases: %v\n", listErr)
 			} else {
@@ -1260,4 +1337,232 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runDoltBackup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doltBackupList {
+		return printDoltRestorePoints(townRoot)
+	}
+
+	config := doltserver.DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — backup requires local server access", config.HostPort())
+	}
+	if doltBackupDB != "" && !doltserver.DatabaseExists(townRoot, doltBackupDB) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", doltBackupDB)
+	}
+
+	fmt.Printf("Backing up Dolt databases to %s...\n\n", style.Dim.Render(doltserver.BackupDir(townRoot)))
+	result, err := doltserver.BackupDatabases(townRoot, doltserver.BackupOptions{
+		Filter:    doltBackupDB,
+		Retention: daemon.DoltBackupRetention(daemon.LoadPatrolConfig(townRoot)),
+		NoPrune:   doltBackupNoPrune,
+	})
+	if err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+
+	snap := result.Snapshot
+	var names []string
+	for db := range snap.Commits {
+		names = append(names, db)
+	}
+	for db := range snap.Errors {
+		if _, ok := snap.Commits[db]; !ok {
+			names = append(names, db)
+		}
+	}
+	sort.Strings(names)
+	for _, db := range names {
+		if reason, ok := snap.Errors[db]; ok {
+			fmt.Printf("  %s %s\n", style.Bold.Render("✗"), db)
+			fmt.Printf("    error: %s\n", reason)
+			continue
+		}
+		fmt.Printf("  %s %s @ %s\n", style.Bold.Render("✓"), db, style.Dim.Render(shortDoltHash(snap.Commits[db])))
+	}
+	for _, db := range snap.Missing {
+		fmt.Printf("  %s %s is on disk but not served by the Dolt server\n", style.Bold.Render("!"), db)
+	}
+
+	fmt.Println()
+	if !snap.Verified {
+		fmt.Printf("%s Backup %s failed verification\n", style.Bold.Render("✗"), snap.ID)
+	} else {
+		fmt.Printf("%s Backup %s verified (%d database(s))\n", style.Bold.Render("✓"), snap.ID, len(snap.Commits))
+	}
+	if len(result.Pruned) > 0 {
+		fmt.Printf("  Retention pruned %d older restore point(s)\n", len(result.Pruned))
+	}
+
+	if !snap.Verified {
+		return fmt.Errorf("backup %s failed verification", snap.ID)
+	}
+	return nil
+}
+
+// printDoltRestorePoints lists the restore points in the backup manifest.
+func printDoltRestorePoints(townRoot string) error {
+	manifest, err := doltserver.LoadBackupManifest(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Snapshots) == 0 {
+		fmt.Printf("No Dolt backups yet. Run %s to take one.\n", style.Bold.Render("gt dolt backup"))
+		return nil
+	}
+
+	fmt.Printf("Restore points in %s:\n\n", doltserver.BackupDir(townRoot))
+	for _, s := range manifest.Snapshots {
+		status := style.Bold.Render("✓") + " verified"
+		switch {
+		case s.Partial && s.Verified:
+			status = style.Bold.Render("✓") + " single database"
+		case !s.Good() && !s.Partial:
+			status = style.Bold.Render("✗") + " failed"
+		}
+		dbs := make([]string, 0, len(s.Commits))
+		for db := range s.Commits {
+			dbs = append(dbs, db)
+		}
+		sort.Strings(dbs)
+		fmt.Printf("  %s  %s  %s\n", s.ID, s.Time.Local().Format("2006-01-02 15:04"), status)
+		fmt.Printf("    %s\n", style.Dim.Render(strings.Join(dbs, ", ")))
+	}
+	return nil
+}
+
+// shortDoltHash abbreviates a Dolt commit hash for display.
+func shortDoltHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+func runDoltRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	config := doltserver.DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — restore requires local server access", config.HostPort())
+	}
+	if doltRestoreAt == "" && !doltRestoreFromBackup {
+		return fmt.Errorf("--at is required: a backup ID, commit hash or time (or use --from-backup)")
+	}
+
+	if doltRestoreFromBackup {
+		if err := restoreDoltFromMirrors(townRoot); err != nil {
+			return err
+		}
+		if doltRestoreAt == "" {
+			return nil
+		}
+		fmt.Println()
+	}
+
+	results := doltserver.RestoreDatabases(townRoot, doltserver.RestoreOptions{
+		At:     doltRestoreAt,
+		Filter: doltRestoreDB,
+		DryRun: doltRestoreDry,
+	})
+	if len(results) == 0 {
+		fmt.Println("No databases to restore.")
+		return nil
+	}
+
+	fmt.Printf("Restoring %d database(s) to %s...\n\n", len(results), doltRestoreAt)
+	var restored, failed int
+	branch := ""
+	for _, r := range results {
+		switch {
+		case r.Error != nil:
+			fmt.Printf("  %s %s\n", style.Bold.Render("✗"), r.Database)
+			fmt.Printf("    error: %v\n", r.Error)
+			failed++
+		case r.DryRun:
+			fmt.Printf("  %s %s → %s (dry run)\n", style.Bold.Render("~"), r.Database, shortDoltHash(r.Target))
+		default:
+			fmt.Printf("  %s %s → %s\n", style.Bold.Render("✓"), r.Database, shortDoltHash(r.Target))
+			branch = r.SafetyBranch
+			restored++
+		}
+	}
+
+	fmt.Println()
+	if branch != "" {
+		fmt.Printf("Previous state kept on branch %s in each restored database.\n", style.Bold.Render(branch))
+	}
+	if !doltRestoreDry {
+		fmt.Printf("Summary: %d restored, %d failed\n", restored, failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to restore", failed)
+	}
+	return nil
+}
+
+// restoreDoltFromMirrors rebuilds databases from their backup mirrors with
+// the Dolt server stopped, then starts it again.
+func restoreDoltFromMirrors(townRoot string) error {
+	databases := []string{doltRestoreDB}
+	if doltRestoreDB == "" {
+		var err error
+		databases, err = doltserver.BackupMirrors(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing backup mirrors: %w", err)
+		}
+		if len(databases) == 0 {
+			return fmt.Errorf("no backup mirrors in %s\nRun 'gt dolt backup' to take one", doltserver.BackupDir(townRoot))
+		}
+	}
+
+	if doltRestoreDry {
+		fmt.Printf("%s Dry run - no changes will be made\n\n", style.Bold.Render("!"))
+		for _, db := range databases {
+			fmt.Printf("  %s %s ← %s\n", style.Bold.Render("~"), db, style.Dim.Render(doltserver.BackupMirrorDir(townRoot, db)))
+		}
+		return nil
+	}
+
+	if running, pid, _ := doltserver.IsRunning(townRoot); running {
+		fmt.Printf("Stopping Dolt server (PID %d)...\n", pid)
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+		fmt.Printf("%s Dolt server stopped\n", style.Bold.Render("✓"))
+	}
+
+	fmt.Printf("\nRebuilding %d database(s) from backup mirrors...\n", len(databases))
+	failed := 0
+	for _, db := range databases {
+		aside, err := doltserver.RestoreFromMirror(townRoot, db)
+		if err != nil {
+			fmt.Printf("  %s %s\n", style.Bold.Render("✗"), db)
+			fmt.Printf("    error: %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s %s rebuilt from backup\n", style.Bold.Render("✓"), db)
+		if aside != "" {
+			fmt.Printf("    previous copy moved to %s\n", style.Dim.Render(aside))
+		}
+	}
+
+	fmt.Printf("\nStarting Dolt server...\n")
+	if err := doltserver.Start(townRoot); err != nil {
+		return fmt.Errorf("starting Dolt server: %w\nStart manually with: gt dolt start", err)
+	}
+	fmt.Printf("%s Dolt server started (accepting connections)\n", style.Bold.Render("✓"))
 
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to rebuild", failed)
+	}
+	return nil
+}
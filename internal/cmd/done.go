@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -22,9 +23,11 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var doneCmd = &cobra.Command{
@@ -331,6 +334,21 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Trace gt done as part of the issue's trace. This defer runs before the
+	// session-kill backstop above, so the span is flushed while we still can.
+	doneCtx, doneSpan := telemetry.Start(issueTraceContext(cwd, issueID), "gt.done",
+		attribute.String("gt.bead", issueID),
+		attribute.String("gt.exit", exitType),
+		attribute.String("gt.branch", branch))
+	defer func() {
+		endErr := retErr
+		if code, ok := IsSilentExit(retErr); ok && code == 0 {
+			endErr = nil
+		}
+		telemetry.End(doneSpan, endErr)
+		telemetry.Flush()
+	}()
+
 	// Write done-intent label EARLY, before push/MR operations.
 	// If gt done crashes after this point, the Witness can detect the intent
 	// and auto-nuke the zombie polecat.
@@ -636,10 +654,11 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 					townRouter := mail.NewRouter(townRoot)
 					defer townRouter.WaitPendingNotifications()
 					reviewMsg := &mail.Message{
-						To:      dispatcher,
-						From:    detectSender(),
-						Subject: fmt.Sprintf("READY_FOR_REVIEW: %s", issueID),
-						Body:    fmt.Sprintf("Branch: %s\nIssue: %s\nReady for review.", branch, issueID),
+						To:          dispatcher,
+						From:        detectSender(),
+						Subject:     fmt.Sprintf("READY_FOR_REVIEW: %s", issueID),
+						Body:        fmt.Sprintf("Branch: %s\nIssue: %s\nReady for review.", branch, issueID),
+						TraceParent: telemetry.TraceParent(doneCtx),
 					}
 					if err := townRouter.Send(reviewMsg); err != nil {
 						style.PrintWarning("could not notify dispatcher: %v", err)
@@ -741,6 +760,14 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			// The refinery continues the trace from here.
+			if tp := telemetry.TraceParent(doneCtx); tp != "" {
+				description += fmt.Sprintf("\ntraceparent: %s", tp)
+			}
+			// Convoy age and deadline feed the refinery's MR scoring.
+			if convoyInfo != nil && convoyInfo.ID != "" {
+				description += convoyMRFields(convoyInfo)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -877,10 +904,11 @@ afterDoltMerge:
 	}
 
 	doneNotification := &mail.Message{
-		To:      witnessAddr,
-		From:    sender,
-		Subject: fmt.Sprintf("POLECAT_DONE %s", polecatName),
-		Body:    strings.Join(bodyLines, "\n"),
+		To:          witnessAddr,
+		From:        sender,
+		Subject:     fmt.Sprintf("POLECAT_DONE %s", polecatName),
+		Body:        strings.Join(bodyLines, "\n"),
+		TraceParent: telemetry.TraceParent(doneCtx),
 	}
 
 	fmt.Printf("\nNotifying Witness...\n")
@@ -895,10 +923,11 @@ afterDoltMerge:
 	// with routine operational mail. The witness handles polecat lifecycle.
 	if issueID != "" {
 		workDoneNotification := &mail.Message{
-			To:      witnessAddr,
-			From:    sender,
-			Subject: fmt.Sprintf("WORK_DONE: %s", issueID),
-			Body:    strings.Join(bodyLines, "\n"),
+			To:          witnessAddr,
+			From:        sender,
+			Subject:     fmt.Sprintf("WORK_DONE: %s", issueID),
+			Body:        strings.Join(bodyLines, "\n"),
+			TraceParent: telemetry.TraceParent(doneCtx),
 		}
 		if err := townRouter.Send(workDoneNotification); err != nil {
 			style.PrintWarning("could not notify witness of work done: %v", err)
@@ -952,6 +981,9 @@ afterDoltMerge:
 		// Step 2: Kill our own session (this terminates Claude and the shell)
 		// This is the last thing we do - the process will be killed when tmux session dies
 		// All exit types kill the session - "done means gone"
+		// End the trace first: deferred code won't run once tmux kills us.
+		telemetry.End(doneSpan, nil)
+		telemetry.Flush()
 		fmt.Printf("%s Terminating session (done means gone)\n", style.Bold.Render("→"))
 		if err := selfKillSession(townRoot, roleInfo); err != nil {
 			// If session kill fails, fall through to normal exit
@@ -972,6 +1004,25 @@ afterDoltMerge:
 	return NewSilentExit(0)
 }
 
+// issueTraceContext returns the context to parent a span for work on issueID:
+// the traceparent gt sling stored on the issue, falling back to the session's
+// GT_TRACEPARENT. The issue is authoritative because a session may outlive
+// the sling that started it.
+func issueTraceContext(cwd, issueID string) context.Context {
+	ctx := telemetry.FromEnv(context.Background())
+	if issueID == "" || !telemetry.Enabled() {
+		return ctx
+	}
+	issue, err := beads.New(beads.ResolveBeadsDir(cwd)).Show(issueID)
+	if err != nil {
+		return ctx
+	}
+	if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.TraceParent != "" {
+		return telemetry.WithTraceParent(context.Background(), fields.TraceParent)
+	}
+	return ctx
+}
+
 // setDoneIntentLabel writes a done-intent:<type>:<unix-ts> label on the agent bead
 // EARLY in gt done, before push/MR. This allows the Witness to detect polecats that
 // crashed mid-gt-done: if the session is dead but done-intent exists, the polecat was
@@ -1363,7 +1414,7 @@ func isPolecatActor(actor string) bool {
 	return len(parts) >= 2 && parts[1] == "polecats"
 }
 
-// selfKillSession terminates the polecat's own tmux session after logging the event.
+// selfKillSession terminates the polecat's own session after logging the event.
 // This completes the self-cleaning model: "done means gone" - both worktree and session.
 //
 // The polecat determines its session from environment variables:
@@ -1405,9 +1456,15 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	// We use KillSessionWithProcessesExcluding to ensure no orphaned processes are left behind,
 	// while excluding our own PID to avoid killing ourselves before cleanup completes.
 	// The tmux kill-session at the end will terminate us along with the session.
-	t := tmux.NewTmux()
-	myPID := strconv.Itoa(os.Getpid())
-	if err := t.KillSessionWithProcessesExcluding(sessionName, []string{myPID}); err != nil {
+	t := session.NewBackend(townRoot)
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		myPID := strconv.Itoa(os.Getpid())
+		if err := realTmux.KillSessionWithProcessesExcluding(sessionName, []string{myPID}); err != nil {
+			return fmt.Errorf("killing session %s: %w", sessionName, err)
+		}
+		return nil
+	}
+	if err := t.KillSessionWithProcesses(sessionName); err != nil {
 		return fmt.Errorf("killing session %s: %w", sessionName, err)
 	}
 
@@ -783,6 +783,43 @@ func TestParseConvoyMergeStrategy(t *testing.T) {
 	}
 }
 
+// TestConvoyDue verifies the --due forms and that the deadline round-trips
+// through the convoy description.
+func TestConvoyDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"2026-03-05", "2026-03-05T23:59:59Z"},
+		{"2026-03-05T12:00:00Z", "2026-03-05T12:00:00Z"},
+		{"72h", "2026-03-04T09:00:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := parseConvoyDueFlag(tt.value, now)
+		if err != nil {
+			t.Errorf("parseConvoyDueFlag(%q): %v", tt.value, err)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339); s != tt.want {
+			t.Errorf("parseConvoyDueFlag(%q) = %s, want %s", tt.value, s, tt.want)
+		}
+	}
+	for _, bad := range []string{"next week", "-5h"} {
+		if _, err := parseConvoyDueFlag(bad, now); err == nil {
+			t.Errorf("parseConvoyDueFlag(%q) should fail", bad)
+		}
+	}
+
+	desc := "Convoy tracking 2 issues\nMerge: mr\nDue: 2026-03-05T23:59:59Z"
+	if got := parseConvoyDue(desc); got != "2026-03-05T23:59:59Z" {
+		t.Errorf("parseConvoyDue = %q", got)
+	}
+	if got := parseConvoyDue("Convoy tracking 1 issues"); got != "" {
+		t.Errorf("parseConvoyDue without deadline = %q, want empty", got)
+	}
+}
+
 // TestDoneCheckpointLabelFormat verifies the done-cp label format matches
 // the expected pattern: done-cp:<stage>:<value>:<unix-ts>
 func TestDoneCheckpointLabelFormat(t *testing.T) {
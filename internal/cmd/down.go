@@ -91,8 +91,9 @@ func runDown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	t := tmux.NewTmux()
-	if !t.IsAvailable() {
+	t := session.NewBackend(townRoot)
+	realTmux, isTmux := t.(*tmux.Tmux)
+	if isTmux && !realTmux.IsAvailable() {
 		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
 	}
 
@@ -115,7 +116,9 @@ func runDown(cmd *cobra.Command, args []string) error {
 		// By default, tmux exits when there are no sessions (exit-empty on).
 		// This ensures the server stays running for subsequent `gt up`.
 		// Ignore errors - if there's no server, nothing to configure.
-		_ = t.SetExitEmpty(false)
+		if isTmux {
+			_ = realTmux.SetExitEmpty(false)
+		}
 	}
 	allOK := true
 
@@ -291,7 +294,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 	}
 
 	// Phase 6: Nuke tmux server (--nuke only, DESTRUCTIVE)
-	if downNuke {
+	if downNuke && isTmux {
 		if downDryRun {
 			printDownStatus("Tmux server", true, "would kill (DESTRUCTIVE)")
 		} else if os.Getenv("GT_NUKE_ACKNOWLEDGED") == "" {
@@ -304,7 +307,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 			fmt.Printf("To proceed, run with: %s\n", style.Bold.Render("GT_NUKE_ACKNOWLEDGED=1 gt down --nuke"))
 			allOK = false
 		} else {
-			if err := t.KillServer(); err != nil {
+			if err := realTmux.KillServer(); err != nil {
 				printDownStatus("Tmux server", false, err.Error())
 				allOK = false
 			} else {
@@ -347,7 +350,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 // stopAllPolecats stops all polecat sessions across all rigs.
 // Returns the number of polecats stopped (or would be stopped in dry-run).
-func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force bool, dryRun bool) int {
+func stopAllPolecats(t session.SessionBackend, townRoot string, rigNames []string, force bool, dryRun bool) int {
 	stopped := 0
 
 	// Load rigs config
@@ -366,7 +369,7 @@ func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force boo
 			continue
 		}
 
-		polecatMgr := polecat.NewSessionManager(t, r)
+		polecatMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
 		infos, err := polecatMgr.ListPolecats()
 		if err != nil {
 			continue
@@ -402,9 +405,9 @@ func printDownStatus(name string, ok bool, detail string) {
 	}
 }
 
-// stopSession gracefully stops a tmux session.
+// stopSession gracefully stops a session.
 // Returns (wasRunning, error) - wasRunning is true if session existed and was stopped.
-func stopSession(t *tmux.Tmux, sessionName string) (bool, error) {
+func stopSession(t session.SessionBackend, sessionName string) (bool, error) {
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return false, err
@@ -453,14 +456,14 @@ func acquireShutdownLock(townRoot string) (*flock.Flock, error) {
 
 // verifyShutdown checks for respawned processes after shutdown.
 // Returns list of things that are still running or respawned.
-func verifyShutdown(t *tmux.Tmux, townRoot string) []string {
+func verifyShutdown(t session.SessionBackend, townRoot string) []string {
 	var respawned []string
 
 	sessions, err := t.ListSessions()
 	if err == nil {
 		for _, sess := range sessions {
 			if session.IsKnownSession(sess) {
-				respawned = append(respawned, fmt.Sprintf("tmux session %s", sess))
+				respawned = append(respawned, fmt.Sprintf("session %s", sess))
 			}
 		}
 	}
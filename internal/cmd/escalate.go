@@ -44,8 +44,10 @@ WORKFLOW:
 
 CONFIGURATION:
   Routing is configured in ~/gt/settings/escalation.json:
-  - routes: Map severity to action lists (bead, mail:mayor, email:human, sms:human)
-  - contacts: Human email/SMS for external notifications
+  - routes: Map severity to action lists (bead, mail:mayor, email:human,
+    sms:human, slack, webhook:<name>, log)
+  - contacts: Human email/SMS, Slack webhook and named webhooks
+  - delivery: SMTP server, SMS bridge command, log path, per-send timeout
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
 
@@ -129,6 +131,9 @@ var escalateShowCmd = &cobra.Command{
 	Short: "Show details of an escalation",
 	Long: `Display detailed information about an escalation.
 
+Includes the outcome of external delivery (email, SMS, Slack, webhooks, log)
+so you can tell whether a human was actually paged.
+
 Examples:
   gt escalate show hq-abc123
   gt escalate show hq-abc123 --json`,
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -128,8 +130,19 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Deliver external notification actions (email:, sms:, slack, webhook:, log)
+	// and record the outcome on the bead so "gt escalate show" reveals whether
+	// a human was actually paged.
+	deliveries := executeExternalActions(townRoot, actions, escalationConfig, &escalation.Notification{
+		ID:          issue.ID,
+		Severity:    severity,
+		Title:       description,
+		Reason:      escalateReason,
+		Source:      escalateSource,
+		EscalatedBy: agentID,
+		RelatedBead: escalateRelatedBead,
+	})
+	recordEscalationDelivery(bd, issue.ID, deliveries)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -151,6 +164,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(deliveries) > 0 {
+			result["deliveries"] = deliveries
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -394,6 +410,20 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Deliver external actions for the new severity
+			fields := beads.ParseEscalationFields(issue.Description)
+			deliveries := executeExternalActions(townRoot, actions, escalationConfig, &escalation.Notification{
+				ID:          result.ID,
+				Severity:    result.NewSeverity,
+				Title:       result.Title,
+				Reason:      fields.Reason,
+				Source:      fields.Source,
+				EscalatedBy: reescalatedBy,
+				RelatedBead: fields.RelatedBead,
+				Reescalated: true,
+			})
+			recordEscalationDelivery(bd, result.ID, deliveries)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -505,6 +535,8 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"delivery":    fields.DeliveryStatus,
+			"deliveredAt": fields.LastDeliveryAt,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -531,6 +563,12 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if fields.DeliveryStatus != "" {
+		fmt.Printf("  Delivery (%s):\n", fields.LastDeliveryAt)
+		for _, part := range strings.Split(fields.DeliveryStatus, "; ") {
+			fmt.Printf("    %s\n", part)
+		}
+	}
 
 	return nil
 }
@@ -553,40 +591,47 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
+// executeExternalActions delivers external notification actions (email:, sms:,
+// slack, webhook:, log) through the escalation dispatcher and prints one line
+// per action. Actions that aren't configured are reported and skipped.
+func executeExternalActions(townRoot string, actions []string, cfg *config.EscalationConfig, n *escalation.Notification) []escalation.Result {
+	results := escalation.NewDispatcher(townRoot, cfg).Dispatch(context.Background(), actions, n)
+	for _, r := range results {
 		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
+		case r.Skipped:
+			style.PrintWarning("%s action skipped: %s in settings/escalation.json", r.Action, r.Error)
+		case r.Error != "":
+			style.PrintWarning("%s delivery failed: %s", r.Action, r.Error)
+		default:
+			fmt.Printf("  %s %s → %s\n", deliveryEmoji(r.Channel), r.Action, r.Target)
+		}
+	}
+	return results
+}
 
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
+// recordEscalationDelivery writes delivery results back to the escalation bead.
+func recordEscalationDelivery(bd *beads.Beads, id string, results []escalation.Result) {
+	if len(results) == 0 {
+		return
+	}
+	status := escalation.FormatResults(results)
+	if err := bd.RecordEscalationDelivery(id, status, escalation.AnyFailed(results)); err != nil {
+		style.PrintWarning("failed to record delivery status on %s: %v", id, err)
+	}
+}
 
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
-		}
+func deliveryEmoji(channel string) string {
+	switch channel {
+	case "email":
+		return "📧"
+	case "sms":
+		return "📱"
+	case "slack", "webhook":
+		return "💬"
+	case "log":
+		return "📝"
+	default:
+		return "→"
 	}
 }
 
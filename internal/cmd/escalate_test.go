@@ -8,6 +8,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,13 +333,15 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	// executeExternalActions prints warnings/info and returns one result per
+	// external action. Unconfigured channels are skipped, never fatal.
 
 	tests := []struct {
-		name    string
-		actions []string
-		cfg     *config.EscalationConfig
+		name        string
+		actions     []string
+		cfg         *config.EscalationConfig
+		wantResults int
+		wantSkipped int
 	}{
 		{
 			name:    "no external actions",
@@ -346,62 +349,62 @@ func TestExecuteExternalActions(t *testing.T) {
 			cfg:     &config.EscalationConfig{},
 		},
 		{
-			name:    "email action without contact",
-			actions: []string{"email:human"},
-			cfg:     &config.EscalationConfig{},
+			name:        "email action without contact",
+			actions:     []string{"email:human"},
+			cfg:         &config.EscalationConfig{},
+			wantResults: 1,
+			wantSkipped: 1,
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp server",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail: "test@example.com",
 				},
 			},
+			wantResults: 1,
+			wantSkipped: 1,
 		},
 		{
-			name:    "sms action without contact",
-			actions: []string{"sms:human"},
-			cfg:     &config.EscalationConfig{},
+			name:        "sms action without contact",
+			actions:     []string{"sms:human"},
+			cfg:         &config.EscalationConfig{},
+			wantResults: 1,
+			wantSkipped: 1,
 		},
 		{
-			name:    "sms action with contact",
+			name:    "sms action with bridge command",
 			actions: []string{"sms:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanSMS: "+15551234567",
 				},
+				Delivery: config.EscalationDelivery{
+					SMSCommand: "cat >/dev/null",
+				},
 			},
+			wantResults: 1,
 		},
 		{
-			name:    "slack action without webhook",
-			actions: []string{"slack"},
-			cfg:     &config.EscalationConfig{},
+			name:        "slack action without webhook",
+			actions:     []string{"slack"},
+			cfg:         &config.EscalationConfig{},
+			wantResults: 1,
+			wantSkipped: 1,
 		},
 		{
-			name:    "slack action with webhook",
-			actions: []string{"slack"},
-			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
-				},
-			},
+			name:        "log action",
+			actions:     []string{"log"},
+			cfg:         &config.EscalationConfig{},
+			wantResults: 1,
 		},
 		{
-			name:    "log action",
-			actions: []string{"log"},
-			cfg:     &config.EscalationConfig{},
-		},
-		{
-			name:    "all external actions combined",
-			actions: []string{"email:human", "sms:human", "slack", "log"},
-			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					HumanEmail:   "test@example.com",
-					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
-				},
-			},
+			name:        "all external actions combined",
+			actions:     []string{"email:human", "sms:human", "slack", "log"},
+			cfg:         &config.EscalationConfig{},
+			wantResults: 4,
+			wantSkipped: 3,
 		},
 		{
 			name:    "empty actions",
@@ -412,8 +415,22 @@ func TestExecuteExternalActions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			n := &escalation.Notification{ID: "hq-test", Severity: "high", Title: "Test escalation"}
+			results := executeExternalActions(t.TempDir(), tt.actions, tt.cfg, n)
+			if len(results) != tt.wantResults {
+				t.Fatalf("got %d results, want %d: %+v", len(results), tt.wantResults, results)
+			}
+			skipped := 0
+			for _, r := range results {
+				if r.Skipped {
+					skipped++
+				} else if r.Error != "" {
+					t.Errorf("%s failed: %s", r.Action, r.Error)
+				}
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", skipped, tt.wantSkipped)
+			}
 		})
 	}
 }
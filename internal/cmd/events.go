@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/eventstore"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	eventsQueryJSON  bool
+	eventsQueryLimit int
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the town's event history",
+	Long: `Query the town's event history.
+
+Gas Town's activity log (.events.jsonl), town log (logs/town.log) and
+command run log (daemon/command-events.jsonl) are indexed into the
+gt_events database on the Dolt server, by time, actor, type and bead.
+The daemon keeps the index current; queries catch up on anything newer
+first. When the Dolt server is down, queries scan the logs instead.`,
+	RunE: requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query [terms...]",
+	Short: "Search events with the event query language",
+	Long: `Search events with the event query language.
+
+Terms are key:value filters; a value may list alternatives separated by
+commas. Words without a key search the event summary.
+
+  actor:<who>     Address (gastown/polecats/Toast), bare name (Toast, any
+                  rig or role), or prefix (gastown/polecats/*)
+  type:<type>     Event type (sling, hook, done, spawn, session_death, ...)
+  bead:<id>       Bead the event refers to
+  rig:<rig>       Rig the event happened in
+  source:<log>    events, townlog or runlog
+  vis:<level>     feed, audit or both
+  since:<time>    Events at or after a time
+  until:<time>    Events before a time
+  on:<day>        Events during one day
+  limit:<n>       Newest n events (default 100)
+
+Times are a duration ago (90m, 24h, 7d), a date (2026-03-09), a local
+time (2026-03-09T14:00), an RFC 3339 timestamp, now, today or yesterday.
+
+Examples:
+  gt events query actor:Toast on:yesterday
+  gt events query type:hook,unhook rig:gastown since:2h
+  gt events query bead:gt-abc
+  gt events query type:session_death since:7d --json`,
+	RunE: runEventsQuery,
+}
+
+var eventsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Index new log events into the event store",
+	Long: `Index new log events into the event store.
+
+Reads whatever the town's event logs gained since the last sync into the
+gt_events database. The daemon does this every heartbeat; run it by hand
+after starting the Dolt server, or to check that indexing works.`,
+	Args: cobra.NoArgs,
+	RunE: runEventsSync,
+}
+
+func init() {
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON")
+	eventsQueryCmd.Flags().IntVarP(&eventsQueryLimit, "limit", "n", 0, "Maximum number of events (overrides limit:)")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	eventsCmd.AddCommand(eventsSyncCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q, err := eventstore.ParseQuery(strings.Join(args, " "), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	if eventsQueryLimit > 0 {
+		q.Limit = eventsQueryLimit
+	}
+
+	found, indexed, err := eventstore.QueryTown(townRoot, q)
+	if err != nil {
+		return err
+	}
+	if !indexed && !eventsQueryJSON {
+		fmt.Fprintln(os.Stderr, style.Dim.Render("Event store unavailable; scanned the event logs instead."))
+	}
+
+	if eventsQueryJSON {
+		if found == nil {
+			found = []eventstore.Event{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(found)
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("%s No matching events\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	// Newest events were selected; print them oldest first.
+	for i := len(found) - 1; i >= 0; i-- {
+		printStoreEvent(found[i])
+	}
+	return nil
+}
+
+func printStoreEvent(e eventstore.Event) {
+	actor := e.Actor
+	if actor == "" {
+		actor = "system"
+	}
+	line := fmt.Sprintf("%s %s %s %s",
+		style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")),
+		formatSource(e.Source),
+		formatType(e.Type),
+		style.Bold.Render(actor))
+	if e.Summary != "" {
+		line += " " + e.Summary
+	}
+	fmt.Println(line)
+}
+
+func runEventsSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	s, err := eventstore.OpenTown(townRoot)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	n, err := s.Sync(townRoot)
+	if err != nil {
+		return err
+	}
+	total, err := s.Count()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Indexed %d new log lines (%d events stored)\n", style.SuccessPrefix, n, total)
+	return nil
+}
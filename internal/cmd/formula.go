@@ -25,12 +25,16 @@ import (
 
 // Formula command flags
 var (
-	formulaListJSON   bool
-	formulaShowJSON   bool
-	formulaRunPR      int
-	formulaRunRig     string
-	formulaRunDryRun  bool
-	formulaCreateType string
+	formulaListJSON       bool
+	formulaShowJSON       bool
+	formulaShowExpand     bool
+	formulaRunPR          int
+	formulaRunRig         string
+	formulaRunDryRun      bool
+	formulaRunVars        []string
+	formulaRunFormat      string
+	formulaRunShowPrompts bool
+	formulaCreateType     string
 )
 
 var formulaCmd = &cobra.Command{
@@ -90,9 +94,14 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --expanded, prints the workflow after composition: extends, include,
+compose.expand and compose.aspects flattened into one validated step DAG,
+as TOML with a comment on each step saying where it came from.
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show shiny-secure --expanded`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -117,12 +126,21 @@ Options:
   --rig=NAME  Target specific rig (default: current or gastown)
   --dry-run   Show what would happen without executing
 
+A dry run resolves vars (--var key=value), renders every prompt, prints the
+parallel execution schedule, and estimates agent count and cost from past
+sessions recorded by gt costs. It exits non-zero on template problems such
+as undefined or unresolved variables, so they surface before any polecat
+spawns. With --format dot or mermaid it prints the step graph instead, in
+the same form as gt mol dag --format.
+
 Examples:
   gt formula run shiny                    # Run formula in current rig
   gt formula run                          # Run default formula from rig config
   gt formula run shiny --pr=123           # Run on PR #123
   gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run release --dry-run        # Preview execution
+  gt formula run code-review --pr=123 --dry-run --show-prompts
+  gt formula run shiny --dry-run --var feature=auth --format mermaid`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -154,11 +172,15 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowExpand, "expanded", false, "Show the flattened workflow after composition")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Set a var or input for --dry-run (key=value, repeatable)")
+	formulaRunCmd.Flags().StringVar(&formulaRunFormat, "format", "", "With --dry-run, print the step graph: dot (Graphviz) or mermaid")
+	formulaRunCmd.Flags().BoolVar(&formulaRunShowPrompts, "show-prompts", false, "With --dry-run, print every rendered prompt")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -188,6 +210,10 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowExpand {
+		return runFormulaShowExpanded(formulaName)
+	}
+
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -203,6 +229,13 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 // For convoy-type formulas, it creates a convoy bead, creates leg beads,
 // and slings each leg to a separate polecat with leg-specific prompts.
 func runFormulaRun(cmd *cobra.Command, args []string) error {
+	if !formulaRunDryRun && (len(formulaRunVars) > 0 || formulaRunFormat != "" || formulaRunShowPrompts) {
+		return fmt.Errorf("--var, --format and --show-prompts require --dry-run")
+	}
+	if err := checkDAGFormat(formulaRunFormat); err != nil {
+		return err
+	}
+
 	// Determine target rig first (needed for default formula lookup)
 	targetRig := formulaRunRig
 	var rigPath string
@@ -283,72 +316,72 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 	return executeConvoyFormula(f, formulaName, targetRig)
 }
 
-// dryRunFormula shows what would happen without executing
+// dryRunFormula shows what would happen without executing: it resolves
+// vars, renders every prompt, prints the execution schedule and a cost
+// estimate, and fails if it finds template problems.
 func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
+	overrides, err := parseVarFlags(formulaRunVars)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.Inputs["pr"]; ok && formulaRunPR > 0 {
+		overrides["pr"] = strconv.Itoa(formulaRunPR)
+	}
+
+	run := convoyRun{
+		formulaName:       formulaName,
+		targetDescription: "local files",
+		reviewID:          generateFormulaShortID(),
+		prNumber:          formulaRunPR,
+	}
+	if formulaRunPR > 0 {
+		run.targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
+		// Fetch PR info if --pr flag is set
+		run.prTitle, run.changedFiles = fetchPRInfo(formulaRunPR)
+	}
+	if f.Output != nil && f.Output.Directory != "" {
+		dirCtx := map[string]interface{}{
+			"review_id":    run.reviewID,
+			"formula_name": formulaName,
+		}
+		run.outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+run.reviewID)
+	}
+
+	history, titles := loadCostHistory()
+	sim := simulateFormula(f, overrides, run, history, titles)
+
+	if formulaRunFormat != "" {
+		fmt.Print(renderDAG(sim.formulaDAG(f), formulaRunFormat))
+		for _, p := range sim.Problems {
+			fmt.Fprintf(os.Stderr, "✗ %s\n", p)
+		}
+		return sim.err()
+	}
+
 	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
 	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
 	fmt.Printf("  Type:    %s\n", f.Type)
 	fmt.Printf("  Rig:     %s\n", targetRig)
 	if formulaRunPR > 0 {
 		fmt.Printf("  PR:      #%d\n", formulaRunPR)
-	}
-
-	if f.Type == formula.TypeConvoy && len(f.Legs) > 0 {
-		// Generate review ID for dry-run display
-		reviewID := generateFormulaShortID()
-
-		// Build target description
-		var targetDescription string
-		if formulaRunPR > 0 {
-			targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
-		} else {
-			targetDescription = "local files"
+		if run.prTitle != "" {
+			fmt.Printf("  PR Title: %s\n", run.prTitle)
 		}
-
-		// Fetch PR info if --pr flag is set
-		var prTitle string
-		var changedFiles []map[string]interface{}
-		if formulaRunPR > 0 {
-			prTitle, changedFiles = fetchPRInfo(formulaRunPR)
-			if prTitle != "" {
-				fmt.Printf("  PR Title: %s\n", prTitle)
-			}
-			if len(changedFiles) > 0 {
-				fmt.Printf("  Changed files: %d\n", len(changedFiles))
-			}
+		if len(run.changedFiles) > 0 {
+			fmt.Printf("  Changed files: %d\n", len(run.changedFiles))
 		}
+	}
 
-		// Show output directory if configured
-		var outputDir string
-		if f.Output != nil && f.Output.Directory != "" {
-			dirCtx := map[string]interface{}{
-				"review_id":    reviewID,
-				"formula_name": formulaName,
-			}
-			outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+reviewID)
-			fmt.Printf("\n  Output directory: %s\n", outputDir)
+	if f.Type == formula.TypeConvoy && len(f.Legs) > 0 {
+		if run.outputDir != "" {
+			fmt.Printf("\n  Output directory: %s\n", run.outputDir)
 		}
 
 		fmt.Printf("\n  Legs (%d parallel):\n", len(f.Legs))
 		for _, leg := range f.Legs {
 			// Show rendered output path for each leg
-			if f.Output != nil && outputDir != "" {
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-				}
-				legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-				outputPath := filepath.Join(outputDir, legPattern)
+			if f.Output != nil && run.outputDir != "" {
+				outputPath, _ := run.legContext(f, leg)["output_path"].(string)
 				fmt.Printf("    • %s: %s\n      → %s\n", leg.ID, leg.Title, outputPath)
 			} else {
 				fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
@@ -356,8 +389,8 @@ func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
 		}
 		if f.Synthesis != nil {
 			fmt.Printf("\n  Synthesis:\n")
-			if f.Output != nil && outputDir != "" {
-				synthPath := filepath.Join(outputDir, f.Output.Synthesis)
+			if f.Output != nil && run.outputDir != "" {
+				synthPath := filepath.Join(run.outputDir, f.Output.Synthesis)
 				fmt.Printf("    • %s\n      → %s\n", f.Synthesis.Title, synthPath)
 			} else {
 				fmt.Printf("    • %s\n", f.Synthesis.Title)
@@ -365,7 +398,78 @@ func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
 		}
 	}
 
-	return nil
+	printFormulaSimulation(sim)
+	return sim.err()
+}
+
+// printFormulaSimulation prints the vars, prompts, schedule and estimate of
+// a dry run.
+func printFormulaSimulation(sim *formulaSimulation) {
+	if len(sim.Vars) > 0 {
+		fmt.Printf("\n  Variables:\n")
+		for _, name := range sortedKeys(sim.Vars) {
+			value := sim.Vars[name]
+			if value == "" {
+				value = style.Dim.Render("(empty)")
+			}
+			fmt.Printf("    %s = %s\n", name, value)
+		}
+	}
+
+	fmt.Printf("\n  Prompts: %d rendered\n", len(sim.Units))
+	if formulaRunShowPrompts {
+		for _, u := range sim.Units {
+			fmt.Printf("\n  %s %s: %s\n", style.Bold.Render("───"), u.ID, u.Title)
+			for _, line := range strings.Split(strings.TrimRight(u.Prompt, "\n"), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	fmt.Printf("\n  Schedule (%d waves):\n", len(sim.Waves))
+	for i, wave := range sim.Waves {
+		mode := ""
+		if len(wave) > 1 {
+			mode = style.Dim.Render(" (parallel)")
+		}
+		fmt.Printf("    %d. %s%s\n", i+1, strings.Join(wave, ", "), mode)
+	}
+	if len(sim.Skipped) > 0 {
+		fmt.Printf("    %s %s\n", style.Dim.Render("Skipped (when is false):"), strings.Join(sim.Skipped, ", "))
+	}
+
+	fmt.Printf("\n  Agents: %d session(s), at most %d at once\n", sim.Sessions, sim.PeakAgents)
+	if sim.CostUSD > 0 {
+		fmt.Printf("  Estimated cost: $%.2f\n", sim.CostUSD)
+		for _, wave := range sim.Waves {
+			for _, id := range wave {
+				u := sim.unit(id)
+				if u == nil {
+					continue
+				}
+				basis := "average session"
+				if u.CostSource == "history" {
+					basis = fmt.Sprintf("%d past run(s)", u.CostSamples)
+				}
+				fmt.Printf("    %-24s $%.2f  %s\n", u.ID, u.CostUSD, style.Dim.Render(basis))
+			}
+		}
+	} else {
+		fmt.Printf("  Estimated cost: %s\n", style.Dim.Render("no cost history (see gt costs record)"))
+	}
+
+	for _, w := range sim.Warnings {
+		fmt.Printf("\n  %s %s", style.Dim.Render("Warning:"), w)
+	}
+	if len(sim.Warnings) > 0 {
+		fmt.Println()
+	}
+	if len(sim.Problems) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Problems:"))
+		for _, p := range sim.Problems {
+			fmt.Printf("    ✗ %s\n", p)
+		}
+	}
 }
 
 // executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
@@ -456,52 +560,26 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 		}
 	}
 
+	run := convoyRun{
+		formulaName:       formulaName,
+		targetDescription: targetDescription,
+		reviewID:          reviewID,
+		prNumber:          formulaRunPR,
+		prTitle:           prTitle,
+		changedFiles:      changedFiles,
+		outputDir:         outputDir,
+	}
+
 	// Step 2: Create leg beads and track them
 	legBeads := make(map[string]string) // leg.ID -> bead ID
 	for _, leg := range f.Legs {
 		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
 
 		// Build leg description with prompt if available
-		legDesc := leg.Description
-		if f.Prompts != nil {
-			if basePrompt, ok := f.Prompts["base"]; ok {
-				// Build template context for this leg
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-					"files":         []string{}, // TODO: support --files flag
-				}
-
-				// Compute output path for this leg
-				if f.Output != nil {
-					legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-					outputPath := filepath.Join(outputDir, legPattern)
-					legCtx["output_path"] = outputPath
-					legCtx["output"] = map[string]interface{}{
-						"directory": outputDir,
-						"synthesis": f.Output.Synthesis,
-					}
-				}
-
-				// Render the base prompt with template context
-				renderedPrompt, err := renderTemplate(basePrompt, legCtx)
-				if err != nil {
-					fmt.Printf("%s Failed to render template for %s: %v\n",
-						style.Dim.Render("Warning:"), leg.ID, err)
-					renderedPrompt = basePrompt // Fall back to raw template
-				}
-				legDesc = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, renderedPrompt)
-			}
+		legDesc, err := run.legPrompt(f, leg, false)
+		if err != nil {
+			fmt.Printf("%s Failed to render template for %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
 		}
 
 		legArgs := []string{
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// expandedStep is a flattened workflow step as shown by --expanded --json.
+type expandedStep struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Needs       []string `json:"needs,omitempty"`
+	Parallel    bool     `json:"parallel,omitempty"`
+	Acceptance  string   `json:"acceptance,omitempty"`
+	Retry       int      `json:"retry,omitempty"`
+	Timeout     string   `json:"timeout,omitempty"`
+	When        string   `json:"when,omitempty"`
+	OnFailure   string   `json:"on_failure,omitempty"`
+	Origin      string   `json:"origin,omitempty"`
+}
+
+// expandedVar is a workflow var as shown by --expanded --json.
+type expandedVar struct {
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// expandedFormula is a flattened workflow as shown by --expanded --json.
+type expandedFormula struct {
+	Formula     string                 `json:"formula"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	Version     int                    `json:"version,omitempty"`
+	Vars        map[string]expandedVar `json:"vars,omitempty"`
+	Steps       []expandedStep         `json:"steps"`
+}
+
+// runFormulaShowExpanded prints a workflow formula with its composition
+// flattened, for debugging extends, include and aspects.
+func runFormulaShowExpanded(name string) error {
+	f, err := loadFormulaForShow(name)
+	if err != nil {
+		return err
+	}
+	if f.Type != formula.TypeWorkflow {
+		return fmt.Errorf("formula %s has type %s; --expanded only applies to workflows", f.Name, f.Type)
+	}
+
+	if formulaShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(expandFormulaView(f))
+	}
+	fmt.Print(renderExpandedFormula(f))
+	return nil
+}
+
+// loadFormulaForShow parses a formula from the formula search paths,
+// falling back to the formulas built into gt.
+func loadFormulaForShow(name string) (*formula.Formula, error) {
+	path, err := findFormulaFile(name)
+	if err == nil {
+		return parseFormulaFile(path)
+	}
+	data, embErr := formula.EmbeddedLoader(name)
+	if embErr != nil {
+		return nil, err
+	}
+	return formula.Parse(data)
+}
+
+func expandFormulaView(f *formula.Formula) expandedFormula {
+	view := expandedFormula{
+		Formula:     f.Name,
+		Type:        string(f.Type),
+		Description: f.Description,
+		Version:     f.Version,
+		Vars:        make(map[string]expandedVar, len(f.Vars)),
+		Steps:       []expandedStep{},
+	}
+	for name, v := range f.Vars {
+		view.Vars[name] = expandedVar(v)
+	}
+	for _, s := range f.Steps {
+		view.Steps = append(view.Steps, expandedStep{
+			ID:          s.ID,
+			Title:       s.Title,
+			Description: s.Description,
+			Needs:       s.Needs,
+			Parallel:    s.Parallel,
+			Acceptance:  s.Acceptance,
+			Retry:       s.Retry,
+			Timeout:     s.Timeout,
+			When:        s.When,
+			OnFailure:   s.OnFailure,
+			Origin:      f.StepOrigin(s.ID),
+		})
+	}
+	return view
+}
+
+// renderExpandedFormula renders a flattened workflow as formula TOML. The
+// output parses as a plain workflow with the same steps.
+func renderExpandedFormula(f *formula.Formula) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s, flattened by gt formula show --expanded\n", f.Name)
+	if len(f.Extends) > 0 {
+		fmt.Fprintf(&b, "# extends: %s\n", strings.Join(f.Extends, ", "))
+	}
+	for _, inc := range f.Include {
+		fmt.Fprintf(&b, "# include: %s\n", inc.Formula)
+	}
+	if f.Compose != nil {
+		for _, e := range f.Compose.Expand {
+			fmt.Fprintf(&b, "# expand: %s with %s\n", e.Target, e.With)
+		}
+		if len(f.Compose.Aspects) > 0 {
+			fmt.Fprintf(&b, "# aspects: %s\n", strings.Join(f.Compose.Aspects, ", "))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nformula = %s\n", tomlString(f.Name))
+	fmt.Fprintf(&b, "type = %s\n", tomlString(string(f.Type)))
+	if f.Description != "" {
+		fmt.Fprintf(&b, "description = %s\n", tomlString(f.Description))
+	}
+	if f.Version != 0 {
+		fmt.Fprintf(&b, "version = %d\n", f.Version)
+	}
+
+	names := make([]string, 0, len(f.Vars))
+	for name := range f.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := f.Vars[name]
+		fmt.Fprintf(&b, "\n[vars.%s]\n", tomlKey(name))
+		if v.Description != "" {
+			fmt.Fprintf(&b, "description = %s\n", tomlString(v.Description))
+		}
+		if v.Required {
+			b.WriteString("required = true\n")
+		}
+		if v.Default != "" {
+			fmt.Fprintf(&b, "default = %s\n", tomlString(v.Default))
+		}
+	}
+
+	for _, s := range f.Steps {
+		b.WriteString("\n")
+		if origin := f.StepOrigin(s.ID); origin != "" {
+			fmt.Fprintf(&b, "# from %s\n", origin)
+		}
+		b.WriteString("[[steps]]\n")
+		fmt.Fprintf(&b, "id = %s\n", tomlString(s.ID))
+		if s.Title != "" {
+			fmt.Fprintf(&b, "title = %s\n", tomlString(s.Title))
+		}
+		if len(s.Needs) > 0 {
+			quoted := make([]string, len(s.Needs))
+			for i, n := range s.Needs {
+				quoted[i] = tomlString(n)
+			}
+			fmt.Fprintf(&b, "needs = [%s]\n", strings.Join(quoted, ", "))
+		}
+		if s.Parallel {
+			b.WriteString("parallel = true\n")
+		}
+		if s.Retry != 0 {
+			fmt.Fprintf(&b, "retry = %d\n", s.Retry)
+		}
+		if s.Timeout != "" {
+			fmt.Fprintf(&b, "timeout = %s\n", tomlString(s.Timeout))
+		}
+		if s.When != "" {
+			fmt.Fprintf(&b, "when = %s\n", tomlString(s.When))
+		}
+		if s.OnFailure != "" {
+			fmt.Fprintf(&b, "on_failure = %s\n", tomlString(s.OnFailure))
+		}
+		if s.Acceptance != "" {
+			fmt.Fprintf(&b, "acceptance = %s\n", tomlString(s.Acceptance))
+		}
+		if s.Description != "" {
+			fmt.Fprintf(&b, "description = %s\n", tomlString(s.Description))
+		}
+	}
+	return b.String()
+}
+
+// tomlString quotes s as a TOML basic string. JSON string escapes are a
+// subset of TOML's, so the JSON encoding is valid TOML.
+func tomlString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// tomlKey quotes a table key unless it is a bare key.
+func tomlKey(k string) string {
+	for _, c := range k {
+		if !(c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return tomlString(k)
+		}
+	}
+	return k
+}
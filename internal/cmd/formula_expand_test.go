@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestRenderExpandedFormula_RoundTrip(t *testing.T) {
+	for _, name := range []string{"shiny-secure", "shiny-enterprise"} {
+		data, err := formula.EmbeddedLoader(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := formula.Parse(data)
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", name, err)
+		}
+
+		out := renderExpandedFormula(f)
+		if !strings.Contains(out, "# from extends shiny\n[[steps]]") {
+			t.Errorf("%s: missing origin comments:\n%s", name, out)
+		}
+
+		// The flattened output is itself a plain workflow with the same DAG.
+		flat, err := formula.Parse([]byte(out))
+		if err != nil {
+			t.Fatalf("%s: parsing --expanded output: %v\n%s", name, err, out)
+		}
+		if flat.IsComposed() {
+			t.Errorf("%s: flattened output should not compose", name)
+		}
+		if !reflect.DeepEqual(flat.Steps, f.Steps) || !reflect.DeepEqual(flat.Vars, f.Vars) {
+			t.Errorf("%s: round trip changed the workflow", name)
+		}
+	}
+}
+
+func TestTomlString(t *testing.T) {
+	if got := tomlString("a \"b\" <c>\n\\"); got != `"a \"b\" <c>\n\\"` {
+		t.Errorf("tomlString = %s", got)
+	}
+	if tomlKey("base_branch") != "base_branch" || tomlKey("a.b") != `"a.b"` {
+		t.Error("tomlKey quoting")
+	}
+}
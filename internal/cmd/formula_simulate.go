@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// convoyRun holds the per-run values convoy prompt templates can use.
+type convoyRun struct {
+	formulaName       string
+	targetDescription string
+	reviewID          string
+	prNumber          int
+	prTitle           string
+	changedFiles      []map[string]interface{}
+	outputDir         string
+}
+
+// legContext returns the template context for a convoy leg's prompt.
+func (r convoyRun) legContext(f *formula.Formula, leg formula.Leg) map[string]interface{} {
+	ctx := map[string]interface{}{
+		"formula_name":       r.formulaName,
+		"target_description": r.targetDescription,
+		"review_id":          r.reviewID,
+		"pr_number":          r.prNumber,
+		"pr_title":           r.prTitle,
+		"leg": map[string]interface{}{
+			"id":          leg.ID,
+			"title":       leg.Title,
+			"focus":       leg.Focus,
+			"description": leg.Description,
+		},
+		"changed_files": r.changedFiles,
+		"files":         []string{}, // TODO: support --files flag
+	}
+
+	// Compute output path for this leg
+	if f.Output != nil {
+		legPattern := renderTemplateOrDefault(f.Output.LegPattern, ctx, leg.ID+"-findings.md")
+		ctx["output_path"] = filepath.Join(r.outputDir, legPattern)
+		ctx["output"] = map[string]interface{}{
+			"directory": r.outputDir,
+			"synthesis": f.Output.Synthesis,
+		}
+	}
+	return ctx
+}
+
+// synthesisContext returns the template context for a convoy's synthesis
+// description.
+func (r convoyRun) synthesisContext(f *formula.Formula) map[string]interface{} {
+	ctx := map[string]interface{}{
+		"formula_name":       r.formulaName,
+		"target_description": r.targetDescription,
+		"review_id":          r.reviewID,
+		"pr_number":          r.prNumber,
+		"pr_title":           r.prTitle,
+	}
+	if f.Output != nil {
+		ctx["output"] = map[string]interface{}{
+			"directory": r.outputDir,
+			"synthesis": f.Output.Synthesis,
+		}
+	}
+	return ctx
+}
+
+// legPrompt renders the description a leg bead is created with.
+func (r convoyRun) legPrompt(f *formula.Formula, leg formula.Leg, strict bool) (string, error) {
+	basePrompt, ok := f.Prompts["base"]
+	if !ok {
+		return leg.Description, nil
+	}
+	render := renderTemplate
+	if strict {
+		render = renderTemplateStrict
+	}
+	rendered, err := render(basePrompt, r.legContext(f, leg))
+	if err != nil {
+		rendered = basePrompt // Fall back to raw template
+	}
+	return fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, rendered), err
+}
+
+// renderTemplateStrict renders a Go text/template, failing on keys missing
+// from the context instead of printing "<no value>".
+func renderTemplateStrict(tmplText string, ctx map[string]interface{}) (string, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("executing template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// simulatedUnit is one piece of work a formula run hands to an agent: a
+// workflow step, convoy leg or synthesis, expansion template or aspect.
+type simulatedUnit struct {
+	ID        string
+	Title     string // rendered
+	Prompt    string // rendered
+	rawTitle  string
+	dependsOn []string
+	parallel  bool
+
+	CostUSD     float64
+	CostSamples int    // past sessions on a bead with this unit's title
+	CostSource  string // "history", "average" or "" (no cost data)
+}
+
+// formulaSimulation is the result of a dry run: resolved vars, rendered
+// prompts, the execution schedule and a cost estimate.
+type formulaSimulation struct {
+	Vars     map[string]string
+	Problems []string // template bugs; the dry run fails
+	Warnings []string
+
+	Units   []*simulatedUnit
+	Waves   [][]string
+	Skipped []string // workflow steps whose when is false for the resolved vars
+
+	Sessions   int // agent sessions the run starts
+	PeakAgents int // most sessions running at once
+	CostUSD    float64
+}
+
+// err reports the simulation's problems as an error.
+func (s *formulaSimulation) err() error {
+	if len(s.Problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("dry run found %d problem(s)", len(s.Problems))
+}
+
+func (s *formulaSimulation) unit(id string) *simulatedUnit {
+	for _, u := range s.Units {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+// synthesisUnitID is the schedule ID of a convoy's synthesis step.
+const synthesisUnitID = "synthesis"
+
+// simulateFormula dry-runs f: it resolves vars against overrides, renders
+// every prompt, computes the schedule under the resolved vars and
+// estimates cost from history (session costs and the titles of the beads
+// they worked on).
+func simulateFormula(f *formula.Formula, overrides map[string]string, run convoyRun,
+	history []costs.LedgerEntry, titles map[string]string) *formulaSimulation {
+	sim := &formulaSimulation{}
+	sim.resolveVars(f, overrides)
+	sim.renderUnits(f, run)
+
+	sim.Waves, sim.Skipped = f.Schedule(sim.Vars)
+	if f.Type == formula.TypeConvoy && f.Synthesis != nil {
+		sim.Waves = append(sim.Waves, []string{synthesisUnitID})
+	}
+	switch f.Type {
+	case formula.TypeConvoy, formula.TypeAspect:
+		// Each leg (and the synthesis) gets its own polecat.
+		for _, wave := range sim.Waves {
+			sim.Sessions += len(wave)
+			if len(wave) > sim.PeakAgents {
+				sim.PeakAgents = len(wave)
+			}
+		}
+	default:
+		// A workflow molecule runs on one polecat.
+		if len(sim.Waves) > 0 {
+			sim.Sessions, sim.PeakAgents = 1, 1
+		}
+	}
+
+	sim.estimateCosts(f, history, titles)
+	return sim
+}
+
+// resolveVars checks the formula's placeholders with ValidateTemplateVariables
+// and resolves each var and input from overrides, then defaults.
+func (s *formulaSimulation) resolveVars(f *formula.Formula, overrides map[string]string) {
+	if err := f.ValidateTemplateVariables(); err != nil {
+		s.Problems = append(s.Problems, err.Error())
+	}
+
+	for _, name := range sortedKeys(overrides) {
+		_, isVar := f.Vars[name]
+		_, isInput := f.Inputs[name]
+		if !isVar && !isInput {
+			s.Problems = append(s.Problems, fmt.Sprintf("unknown var %q (not in [vars] or [inputs])", name))
+		}
+	}
+
+	s.Vars = make(map[string]string)
+	for _, name := range sortedKeys(f.Vars) {
+		v := f.Vars[name]
+		value, ok := overrides[name]
+		if !ok {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			s.Problems = append(s.Problems, fmt.Sprintf("missing required var %q (pass --var %s=...)", name, name))
+		}
+		s.Vars[name] = value
+	}
+
+	warned := make(map[string]bool)
+	for _, name := range sortedKeys(f.Inputs) {
+		in := f.Inputs[name]
+		value, ok := overrides[name]
+		if !ok {
+			value = in.Default
+		}
+		if value != "" {
+			s.Vars[name] = value
+			continue
+		}
+		if in.Required {
+			s.Problems = append(s.Problems, fmt.Sprintf("missing required input %q (pass --var %s=...)", name, name))
+			continue
+		}
+		if len(in.RequiredUnless) == 0 {
+			continue
+		}
+		group := append([]string{name}, in.RequiredUnless...)
+		anySet := false
+		for _, alt := range in.RequiredUnless {
+			if overrides[alt] != "" || f.Inputs[alt].Default != "" {
+				anySet = true
+			}
+		}
+		sort.Strings(group)
+		key := strings.Join(group, ", ")
+		if !anySet && !warned[key] {
+			warned[key] = true
+			s.Warnings = append(s.Warnings, fmt.Sprintf("none of inputs %s is set", key))
+		}
+	}
+}
+
+// renderUnits renders the title and prompt of every unit, recording
+// template errors and placeholders left unresolved as problems.
+func (s *formulaSimulation) renderUnits(f *formula.Formula, run convoyRun) {
+	addRendered := func(kind, id, title, text string, needs []string, parallel bool) {
+		u := &simulatedUnit{
+			ID:        id,
+			rawTitle:  title,
+			Title:     beads.ExpandTemplateVars(title, s.Vars),
+			Prompt:    beads.ExpandTemplateVars(text, s.Vars),
+			dependsOn: needs,
+			parallel:  parallel,
+		}
+		left := formula.ExtractTemplateVariables(u.Title + "\n" + u.Prompt)
+		for _, name := range left {
+			s.Problems = append(s.Problems, fmt.Sprintf("%s %s: unresolved {{%s}}", kind, id, name))
+		}
+		s.Units = append(s.Units, u)
+	}
+	joinPrompt := func(title, body string) string {
+		if body == "" {
+			return title
+		}
+		return title + "\n\n" + body
+	}
+
+	switch f.Type {
+	case formula.TypeWorkflow:
+		for _, step := range f.Steps {
+			addRendered("step", step.ID, step.Title, joinPrompt(step.Title, step.Description), step.Needs, step.Parallel)
+		}
+	case formula.TypeExpansion:
+		for _, tmpl := range f.Template {
+			addRendered("template", tmpl.ID, tmpl.Title, joinPrompt(tmpl.Title, tmpl.Description), tmpl.Needs, false)
+		}
+	case formula.TypeAspect:
+		for _, aspect := range f.Aspects {
+			addRendered("aspect", aspect.ID, aspect.Title, joinPrompt(aspect.Title, aspect.Description), nil, true)
+		}
+	case formula.TypeConvoy:
+		var legIDs []string
+		for _, leg := range f.Legs {
+			prompt, err := run.legPrompt(f, leg, true)
+			if err != nil {
+				s.Problems = append(s.Problems, fmt.Sprintf("leg %s: %v", leg.ID, err))
+			}
+			s.Units = append(s.Units, &simulatedUnit{
+				ID: leg.ID, rawTitle: leg.Title, Title: leg.Title, Prompt: prompt, parallel: true,
+			})
+			legIDs = append(legIDs, leg.ID)
+		}
+		if f.Synthesis != nil {
+			prompt, err := renderTemplateStrict(f.Synthesis.Description, run.synthesisContext(f))
+			if err != nil {
+				s.Problems = append(s.Problems, fmt.Sprintf("synthesis: %v", err))
+				prompt = f.Synthesis.Description
+			}
+			needs := f.Synthesis.DependsOn
+			if len(needs) == 0 {
+				needs = legIDs
+			}
+			s.Units = append(s.Units, &simulatedUnit{
+				ID: synthesisUnitID, rawTitle: f.Synthesis.Title, Title: f.Synthesis.Title, Prompt: prompt, dependsOn: needs,
+			})
+		}
+	}
+}
+
+// estimateCosts prices each scheduled unit at the average cost of past
+// sessions on beads with a matching title. Units without history fall back
+// to the average polecat session; a workflow runs on one polecat, so it
+// shares that average across its steps.
+func (s *formulaSimulation) estimateCosts(f *formula.Formula, history []costs.LedgerEntry, titles map[string]string) {
+	if len(history) == 0 {
+		return
+	}
+
+	var avgSum float64
+	var avgN int
+	for _, e := range history {
+		if e.Role == "polecat" {
+			avgSum += e.CostUSD
+			avgN++
+		}
+	}
+	if avgN == 0 {
+		for _, e := range history {
+			avgSum += e.CostUSD
+		}
+		avgN = len(history)
+	}
+	fallback := avgSum / float64(avgN)
+
+	var scheduled []*simulatedUnit
+	for _, wave := range s.Waves {
+		for _, id := range wave {
+			if u := s.unit(id); u != nil {
+				scheduled = append(scheduled, u)
+			}
+		}
+	}
+	if len(scheduled) == 0 {
+		return
+	}
+	if f.Type == formula.TypeWorkflow || f.Type == formula.TypeExpansion {
+		fallback /= float64(len(scheduled))
+	}
+
+	for _, u := range scheduled {
+		step := &formula.Step{ID: u.ID, Title: u.rawTitle}
+		var sum float64
+		for _, e := range history {
+			title, ok := titles[e.WorkItem]
+			if !ok || e.WorkItem == "" {
+				continue
+			}
+			if _, match := matchStepTitle(step, title); match {
+				sum += e.CostUSD
+				u.CostSamples++
+			}
+		}
+		if u.CostSamples > 0 {
+			u.CostUSD, u.CostSource = sum/float64(u.CostSamples), "history"
+		} else {
+			u.CostUSD, u.CostSource = fallback, "average"
+		}
+		s.CostUSD += u.CostUSD
+	}
+}
+
+// formulaDAG builds a DAG of the formula's units for `gt mol dag`-style
+// graph output. Nodes are "planned", or "skipped" for steps whose when is
+// false for the resolved vars.
+func (s *formulaSimulation) formulaDAG(f *formula.Formula) *DAGInfo {
+	dag := &DAGInfo{
+		RootID:    f.Name,
+		RootTitle: f.Description,
+		Nodes:     make(map[string]*DAGNode),
+	}
+	skipped := make(map[string]bool)
+	for _, id := range s.Skipped {
+		skipped[id] = true
+	}
+	for _, u := range s.Units {
+		node := &DAGNode{
+			ID:           u.ID,
+			Title:        u.Title,
+			Status:       "planned",
+			Parallel:     u.parallel,
+			Dependencies: u.dependsOn,
+		}
+		if skipped[u.ID] {
+			node.Status = "skipped"
+		}
+		dag.Nodes[u.ID] = node
+		dag.TotalNodes++
+	}
+	for _, u := range s.Units {
+		for _, dep := range u.dependsOn {
+			if depNode, ok := dag.Nodes[dep]; ok {
+				depNode.Dependents = append(depNode.Dependents, u.ID)
+			}
+		}
+	}
+	computeTiers(dag)
+	dag.CriticalPath = findCriticalPath(dag)
+	return dag
+}
+
+// loadCostHistory reads the cost ledger that gt costs record writes and
+// looks up the titles of the beads its sessions worked on.
+func loadCostHistory() ([]costs.LedgerEntry, map[string]string) {
+	entries, err := costs.ReadLedger(costs.LedgerPath())
+	if err != nil || len(entries) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, e := range entries {
+		if e.WorkItem != "" && !seen[e.WorkItem] {
+			seen[e.WorkItem] = true
+			ids = append(ids, e.WorkItem)
+		}
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || len(ids) == 0 {
+		return entries, nil
+	}
+	issues, err := beads.New(townRoot).ShowMultiple(ids)
+	if err != nil {
+		return entries, nil
+	}
+	titles := make(map[string]string, len(issues))
+	for id, issue := range issues {
+		if issue != nil {
+			titles[id] = issue.Title
+		}
+	}
+	return entries, titles
+}
+
+// parseVarFlags parses repeated --var key=value flags.
+func parseVarFlags(flags []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, kv := range flags {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", kv)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
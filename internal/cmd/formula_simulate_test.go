@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func mustParseFormula(t *testing.T, src string) *formula.Formula {
+	t.Helper()
+	f, err := formula.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+const simWorkflow = `
+formula = "fix"
+type = "workflow"
+
+[vars.issue]
+required = true
+[vars.mode]
+default = "full"
+
+[[steps]]
+id = "implement"
+title = "Implement {{issue}}"
+
+[[steps]]
+id = "docs"
+title = "Docs for {{issue}}"
+needs = ["implement"]
+parallel = true
+
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["implement"]
+parallel = true
+when = 'vars.mode == "full"'
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["docs", "lint"]
+`
+
+func TestSimulateFormula_Workflow(t *testing.T) {
+	f := mustParseFormula(t, simWorkflow)
+	history := []costs.LedgerEntry{
+		{Role: "polecat", CostUSD: 3.00, WorkItem: "gt-1"},
+		{Role: "polecat", CostUSD: 1.00, WorkItem: "gt-2"},
+		{Role: "polecat", CostUSD: 2.00, WorkItem: "gt-3"},
+		{Role: "mayor", CostUSD: 50.00},
+	}
+	titles := map[string]string{"gt-1": "Implement gt-9", "gt-2": "Implement gt-10", "gt-3": "Unrelated"}
+
+	sim := simulateFormula(f, map[string]string{"issue": "gt-42"}, convoyRun{}, history, titles)
+	if len(sim.Problems) != 0 {
+		t.Fatalf("problems = %v", sim.Problems)
+	}
+	if u := sim.unit("implement"); u.Title != "Implement gt-42" || u.Prompt != "Implement gt-42" {
+		t.Errorf("implement rendered as %q / %q", u.Title, u.Prompt)
+	}
+
+	want := [][]string{{"implement"}, {"docs", "lint"}, {"submit"}}
+	if !reflect.DeepEqual(sim.Waves, want) {
+		t.Errorf("waves = %v, want %v", sim.Waves, want)
+	}
+	if sim.Sessions != 1 || sim.PeakAgents != 1 {
+		t.Errorf("agents = %d/%d, want one polecat", sim.Sessions, sim.PeakAgents)
+	}
+
+	// implement matches two past beads ($2 average); the other three steps
+	// share the $2 polecat average (the mayor session is not a polecat).
+	impl := sim.unit("implement")
+	if impl.CostSource != "history" || impl.CostSamples != 2 || impl.CostUSD != 2.00 {
+		t.Errorf("implement estimate = %+v", impl)
+	}
+	if docs := sim.unit("docs"); docs.CostSource != "average" || math.Abs(docs.CostUSD-0.5) > 1e-9 {
+		t.Errorf("docs estimate = %+v, want a quarter of the average", docs)
+	}
+	if math.Abs(sim.CostUSD-3.5) > 1e-9 {
+		t.Errorf("total = %v, want 3.50", sim.CostUSD)
+	}
+}
+
+func TestSimulateFormula_Problems(t *testing.T) {
+	f := mustParseFormula(t, simWorkflow)
+	sim := simulateFormula(f, map[string]string{"mode": "quick", "nope": "x"}, convoyRun{}, nil, nil)
+
+	joined := strings.Join(sim.Problems, "\n")
+	for _, want := range []string{`unknown var "nope"`, `missing required var "issue"`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("problems %q missing %q", joined, want)
+		}
+	}
+	if sim.err() == nil {
+		t.Error("err() should fail on problems")
+	}
+	if sim.CostUSD != 0 {
+		t.Errorf("no history should mean no estimate, got %v", sim.CostUSD)
+	}
+
+	undefined := mustParseFormula(t, `
+formula = "bad"
+type = "workflow"
+[[steps]]
+id = "a"
+title = "Use {{ghost}}"
+`)
+	sim = simulateFormula(undefined, nil, convoyRun{}, nil, nil)
+	joined = strings.Join(sim.Problems, "\n")
+	if !strings.Contains(joined, "undefined template variables: ghost") || !strings.Contains(joined, "step a: unresolved {{ghost}}") {
+		t.Errorf("problems = %v", sim.Problems)
+	}
+}
+
+func TestSimulateFormula_VarFlipsWhen(t *testing.T) {
+	f := mustParseFormula(t, simWorkflow)
+
+	// At the default mode=full, lint runs beside docs.
+	sim := simulateFormula(f, map[string]string{"issue": "gt-1"}, convoyRun{}, nil, nil)
+	if want := [][]string{{"implement"}, {"docs", "lint"}, {"submit"}}; !reflect.DeepEqual(sim.Waves, want) || sim.Skipped != nil {
+		t.Errorf("default schedule = %v, skipped %v; want %v", sim.Waves, sim.Skipped, want)
+	}
+
+	// --var mode=quick turns lint's condition false.
+	sim = simulateFormula(f, map[string]string{"issue": "gt-1", "mode": "quick"}, convoyRun{}, nil, nil)
+	if want := [][]string{{"implement"}, {"docs"}, {"submit"}}; !reflect.DeepEqual(sim.Waves, want) {
+		t.Errorf("mode=quick waves = %v, want %v", sim.Waves, want)
+	}
+	if !reflect.DeepEqual(sim.Skipped, []string{"lint"}) {
+		t.Errorf("mode=quick skipped = %v, want [lint]", sim.Skipped)
+	}
+}
+
+func TestSimulateFormula_Convoy(t *testing.T) {
+	f := mustParseFormula(t, `
+formula = "review"
+type = "convoy"
+
+[inputs.pr]
+required_unless = ["files"]
+[inputs.files]
+required_unless = ["pr"]
+
+[prompts]
+base = "Review {{.target_description}} for {{.leg.focus}} into {{.output_path}}"
+
+[output]
+directory = ".reviews/{{.review_id}}"
+leg_pattern = "{{.leg.id}}.md"
+synthesis = "summary.md"
+
+[[legs]]
+id = "security"
+title = "Security Review"
+focus = "auth"
+
+[[legs]]
+id = "style"
+title = "Style Review"
+focus = "naming"
+
+[synthesis]
+title = "Synthesis"
+description = "Merge {{.output.directory}} into {{.output.missing}}"
+`)
+	run := convoyRun{formulaName: "review", targetDescription: "PR #7", reviewID: "r1", outputDir: ".reviews/r1"}
+	sim := simulateFormula(f, nil, run, nil, nil)
+
+	if p := sim.unit("security").Prompt; !strings.Contains(p, "Review PR #7 for auth into .reviews/r1/security.md") {
+		t.Errorf("security prompt = %q", p)
+	}
+	if want := [][]string{{"security", "style"}, {"synthesis"}}; !reflect.DeepEqual(sim.Waves, want) {
+		t.Errorf("waves = %v, want %v", sim.Waves, want)
+	}
+	if sim.Sessions != 3 || sim.PeakAgents != 2 {
+		t.Errorf("agents = %d/%d, want 3 sessions, 2 at once", sim.Sessions, sim.PeakAgents)
+	}
+	if len(sim.Problems) != 1 || !strings.Contains(sim.Problems[0], "synthesis:") {
+		t.Errorf("problems = %v, want the synthesis template's missing key", sim.Problems)
+	}
+	if !reflect.DeepEqual(sim.Warnings, []string{"none of inputs files, pr is set"}) {
+		t.Errorf("warnings = %v", sim.Warnings)
+	}
+}
+
+func TestRenderDAG(t *testing.T) {
+	f := mustParseFormula(t, simWorkflow)
+	sim := simulateFormula(f, map[string]string{"issue": "gt-1"}, convoyRun{}, nil, nil)
+	dag := sim.formulaDAG(f)
+
+	dot := renderDAG(dag, dagFormatDot)
+	for _, want := range []string{
+		`digraph "fix" {`,
+		`"implement" [label="implement\nImplement gt-1"];`,
+		`"docs" [label="docs\nDocs for gt-1", peripheries=2];`,
+		`"docs" -> "submit"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot output missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := renderDAG(dag, dagFormatMermaid)
+	for _, want := range []string{"flowchart TD\n", `n0["implement<br/>Implement gt-1"]`, "n0 ==> n1"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	if err := checkDAGFormat("svg"); err == nil {
+		t.Error("checkDAGFormat should reject svg")
+	}
+}
+
+func TestParseVarFlags(t *testing.T) {
+	vars, err := parseVarFlags([]string{"a=1", "b=x=y", "c="})
+	if err != nil || !reflect.DeepEqual(vars, map[string]string{"a": "1", "b": "x=y", "c": ""}) {
+		t.Errorf("parseVarFlags = %v, %v", vars, err)
+	}
+	if _, err := parseVarFlags([]string{"novalue"}); err == nil {
+		t.Error("want error for missing =")
+	}
+}
@@ -157,6 +157,9 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("finding workspace: %w", err)
 	}
+	if backend := session.BackendName(townRoot); backend != config.SessionBackendTmux {
+		return fmt.Errorf("gt mayor attach attaches to a tmux session; session_backend is %s (use gt mayor start)", backend)
+	}
 
 	t := tmux.NewTmux()
 	sessionID := mgr.SessionName()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -50,7 +51,9 @@ Examples:
   gt mol dag gs-wisp-abc     # Show DAG for molecule
   gt mol dag gs-wisp-abc --json  # JSON output
   gt mol dag gs-wisp-abc --tree  # Tree view (default)
-  gt mol dag gs-wisp-abc --tiers # Group by execution tier`,
+  gt mol dag gs-wisp-abc --tiers # Group by execution tier
+  gt mol dag gs-wisp-abc --format dot | dot -Tsvg > dag.svg
+  gt mol dag gs-wisp-abc --format mermaid`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeDag,
 }
@@ -58,16 +61,21 @@ Examples:
 var (
 	dagShowTiers bool
 	dagTreeView  bool
+	dagFormat    string
 )
 
 func init() {
 	moleculeDagCmd.Flags().BoolVar(&dagShowTiers, "tiers", false, "Group output by execution tier")
 	moleculeDagCmd.Flags().BoolVar(&dagTreeView, "tree", true, "Show tree view (default)")
 	moleculeDagCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeDagCmd.Flags().StringVar(&dagFormat, "format", "", "Output as a graph: dot (Graphviz) or mermaid")
 }
 
 func runMoleculeDag(cmd *cobra.Command, args []string) error {
 	rootID := args[0]
+	if err := checkDAGFormat(dagFormat); err != nil {
+		return err
+	}
 
 	workDir, err := findLocalBeadsDir()
 	if err != nil {
@@ -102,6 +110,11 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("building DAG: %w", err)
 	}
 
+	if dagFormat != "" {
+		fmt.Print(renderDAG(dag, dagFormat))
+		return nil
+	}
+
 	// JSON output
 	if moleculeJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -454,3 +467,166 @@ func outputDAGTiers(dag *DAGInfo) error {
 
 	return nil
 }
+
+// Graph output formats for --format.
+const (
+	dagFormatDot     = "dot"
+	dagFormatMermaid = "mermaid"
+)
+
+func checkDAGFormat(format string) error {
+	switch format {
+	case "", dagFormatDot, dagFormatMermaid:
+		return nil
+	}
+	return fmt.Errorf("unknown --format %q (want dot or mermaid)", format)
+}
+
+// renderDAG renders the DAG as a Graphviz or Mermaid graph. Nodes are
+// styled by status and critical path edges are drawn bold.
+func renderDAG(dag *DAGInfo, format string) string {
+	if format == dagFormatMermaid {
+		return renderDAGMermaid(dag)
+	}
+	return renderDAGDot(dag)
+}
+
+// dagNodeOrder returns node IDs by tier, then ID.
+func dagNodeOrder(dag *DAGInfo) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, tier := range dag.TierGroups {
+		for _, id := range tier {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+	// Nodes left out of the tiers (only possible with a cycle)
+	var rest []string
+	for id := range dag.Nodes {
+		if !seen[id] {
+			rest = append(rest, id)
+		}
+	}
+	sort.Strings(rest)
+	return append(ids, rest...)
+}
+
+// criticalEdges returns the edges on the critical path as "from->to".
+func criticalEdges(dag *DAGInfo) map[string]bool {
+	edges := make(map[string]bool)
+	for i := 1; i < len(dag.CriticalPath); i++ {
+		edges[dag.CriticalPath[i-1]+"->"+dag.CriticalPath[i]] = true
+	}
+	return edges
+}
+
+func dagNodeLabel(node *DAGNode) string {
+	if node.Title == "" || node.Title == node.ID {
+		return node.ID
+	}
+	return node.ID + "\n" + node.Title
+}
+
+func renderDAGDot(dag *DAGInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", strconv.Quote(dag.RootID))
+	if dag.RootTitle != "" {
+		fmt.Fprintf(&b, "  label=%s;\n  labelloc=t;\n", strconv.Quote(dag.RootTitle))
+	}
+	b.WriteString("  rankdir=TB;\n  node [shape=box, style=rounded];\n")
+
+	order := dagNodeOrder(dag)
+	for _, id := range order {
+		node := dag.Nodes[id]
+		attrs := []string{"label=" + strconv.Quote(dagNodeLabel(node))}
+		switch node.Status {
+		case "closed":
+			attrs = append(attrs, `style="rounded,filled"`, `fillcolor="#c8e6c9"`)
+		case "in_progress":
+			attrs = append(attrs, `style="rounded,filled"`, `fillcolor="#fff59d"`)
+		case "ready":
+			attrs = append(attrs, `style="rounded,bold"`)
+		case "blocked":
+			attrs = append(attrs, `color="gray50"`, `fontcolor="gray40"`)
+		case "skipped":
+			attrs = append(attrs, `style="rounded,dashed"`, `fontcolor="gray40"`)
+		}
+		if node.Parallel {
+			attrs = append(attrs, "peripheries=2")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", strconv.Quote(id), strings.Join(attrs, ", "))
+	}
+
+	critical := criticalEdges(dag)
+	for _, id := range order {
+		for _, dep := range dag.Nodes[id].Dependencies {
+			if _, ok := dag.Nodes[dep]; !ok {
+				continue
+			}
+			attr := ""
+			if critical[dep+"->"+id] {
+				attr = " [penwidth=2]"
+			}
+			fmt.Fprintf(&b, "  %s -> %s%s;\n", strconv.Quote(dep), strconv.Quote(id), attr)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+var mermaidText = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ")
+
+func renderDAGMermaid(dag *DAGInfo) string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	order := dagNodeOrder(dag)
+	key := make(map[string]string, len(order))
+	classes := make(map[string][]string)
+	for i, id := range order {
+		node := dag.Nodes[id]
+		key[id] = fmt.Sprintf("n%d", i)
+		label := mermaidText.Replace(node.ID)
+		if node.Title != "" && node.Title != node.ID {
+			label += "<br/>" + mermaidText.Replace(node.Title)
+		}
+		if node.Parallel {
+			label += " ∥"
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", key[id], label)
+		switch node.Status {
+		case "closed", "in_progress", "ready", "blocked", "skipped":
+			classes[node.Status] = append(classes[node.Status], key[id])
+		}
+	}
+
+	critical := criticalEdges(dag)
+	for _, id := range order {
+		for _, dep := range dag.Nodes[id].Dependencies {
+			if _, ok := key[dep]; !ok {
+				continue
+			}
+			arrow := "-->"
+			if critical[dep+"->"+id] {
+				arrow = "==>"
+			}
+			fmt.Fprintf(&b, "  %s %s %s\n", key[dep], arrow, key[id])
+		}
+	}
+
+	styles := []struct{ status, class, def string }{
+		{"closed", "done", "fill:#c8e6c9"},
+		{"in_progress", "active", "fill:#fff59d"},
+		{"ready", "ready", "stroke-width:3px"},
+		{"blocked", "blocked", "color:#666,stroke:#999"},
+		{"skipped", "skipped", "color:#666,stroke-dasharray:5 5"},
+	}
+	for _, st := range styles {
+		if ids := classes[st.status]; len(ids) > 0 {
+			fmt.Fprintf(&b, "  classDef %s %s\n  class %s %s\n", st.class, st.def, strings.Join(ids, ","), st.class)
+		}
+	}
+	return b.String()
+}
@@ -7,9 +7,11 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -34,21 +36,38 @@ This command handles the step-to-step transition for polecats:
    - Sends POLECAT_DONE to witness
    - Exits the session
 
+If the molecule's formula gives the step a policy, it is enforced here:
+
+  retry = N             Report a failure with --failed and the step is re-run
+                        up to N more times
+  timeout = "45m"       A step finished after its timeout counts as failed
+                        (the clock starts when the step is handed out)
+  when = "<expr>"       Steps whose condition is false are skipped; the
+                        condition can read vars and earlier steps' --output
+  on_failure = ...      Once retries are used up: escalate (default), skip,
+                        or goto:<step> to re-run from an earlier step
+
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
-Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+Examples:
+  gt mol step done gt-abc.1                              # Complete step 1 of molecule gt-abc
+  gt mol step done gt-abc.2 --output "internal/auth/x.go" # Record output for later when conditions
+  gt mol step done gt-abc.3 --failed --output "3 tests failing"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
 	moleculeStepDryRun bool
+	moleculeStepFailed bool
+	moleculeStepOutput string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepDoneCmd.Flags().BoolVar(&moleculeStepFailed, "failed", false, "The step failed: apply its retry/on_failure policy instead of closing it")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepOutput, "output", "", "Step output (or failure detail), readable by later steps' when conditions")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 }
 
@@ -61,7 +80,11 @@ type StepDoneResult struct {
 	NextStepTitle string   `json:"next_step_title,omitempty"`
 	ParallelSteps []string `json:"parallel_steps,omitempty"` // Multiple ready steps for fan-out
 	Complete      bool     `json:"complete"`
-	Action        string   `json:"action"` // "continue", "parallel", "done", "no_more_ready"
+	Action        string   `json:"action"`                  // "continue", "parallel", "done", "no_more_ready", "retry", "goto", "escalated"
+	Failed        bool     `json:"failed,omitempty"`        // Step failed (--failed or timed out)
+	TimedOut      bool     `json:"timed_out,omitempty"`     // Step overran its formula timeout
+	Attempt       int      `json:"attempt,omitempty"`       // Failure count of the step, including this one
+	SkippedSteps  []string `json:"skipped_steps,omitempty"` // Steps skipped by when conditions or on_failure = skip
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -106,11 +129,36 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		MoleculeID: moleculeID,
 	}
 
-	// Step 3: Close the step
-	if moleculeStepDryRun {
+	// Load the formula step policy (retry, timeout, when, on_failure), if any
+	run := loadMoleculeRun(b, moleculeID)
+	ref := run.stepRef(stepID)
+
+	failed, detail := moleculeStepFailed, moleculeStepOutput
+	if ref != "" && !failed {
+		if fs := run.formula.GetStep(ref); fs.TimedOut(run.state.Step(ref).StartedAt, time.Now()) {
+			failed, result.TimedOut = true, true
+			detail = fmt.Sprintf("timed out after %s", fs.Timeout)
+		}
+	}
+
+	// Step 3: Close the step, or apply the failure policy
+	var next *beads.Issue
+	if failed {
+		next, err = run.failStep(b, step, detail, &result, moleculeStepDryRun)
+		if err != nil {
+			return err
+		}
+	} else if moleculeStepDryRun {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
+		if ref != "" {
+			s := run.state.Step(ref)
+			s.Status, s.Output = formula.StepDone, moleculeStepOutput
+			if err := run.record(b, ref); err != nil {
+				style.PrintWarning("could not record output of %s: %v", stepID, err)
+			}
+		}
 		if err := b.Close(stepID); err != nil {
 			return fmt.Errorf("closing step: %w", err)
 		}
@@ -119,28 +167,48 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 4: Find all ready steps (supports fan-out pattern)
-	readySteps, allComplete, err := findAllReadySteps(b, moleculeID)
-	if err != nil {
-		return fmt.Errorf("finding next steps: %w", err)
-	}
-
-	if allComplete {
-		result.Complete = true
-		result.Action = "done"
-	} else if len(readySteps) > 1 {
-		// Multiple ready steps - fan-out pattern
-		result.Action = "parallel"
-		result.ParallelSteps = make([]string, len(readySteps))
-		for i, s := range readySteps {
-			result.ParallelSteps[i] = s.ID
+	var readySteps []*beads.Issue
+	if result.Action == "" {
+		skipped, err := run.skipFalseConditions(b, moleculeStepDryRun)
+		if err != nil {
+			return err
+		}
+		result.SkippedSteps = append(result.SkippedSteps, skipped...)
+
+		var allComplete bool
+		readySteps, allComplete, err = findAllReadySteps(b, moleculeID)
+		if err != nil {
+			return fmt.Errorf("finding next steps: %w", err)
+		}
+
+		if allComplete {
+			result.Complete = true
+			result.Action = "done"
+		} else if len(readySteps) > 1 {
+			// Multiple ready steps - fan-out pattern
+			result.Action = "parallel"
+			result.ParallelSteps = make([]string, len(readySteps))
+			for i, s := range readySteps {
+				result.ParallelSteps[i] = s.ID
+			}
+		} else if len(readySteps) == 1 {
+			result.NextStepID = readySteps[0].ID
+			result.NextStepTitle = readySteps[0].Title
+			result.Action = "continue"
+		} else {
+			// There are more steps but none are ready (blocked on dependencies)
+			result.Action = "no_more_ready"
+		}
+	}
+
+	// Start the timeout clock of the steps being handed out
+	if !moleculeStepDryRun {
+		switch result.Action {
+		case "continue", "parallel":
+			run.markStarted(b, readySteps)
+		case "retry", "goto":
+			run.markStarted(b, []*beads.Issue{next})
 		}
-	} else if len(readySteps) == 1 {
-		result.NextStepID = readySteps[0].ID
-		result.NextStepTitle = readySteps[0].Title
-		result.Action = "continue"
-	} else {
-		// There are more steps but none are ready (blocked on dependencies)
-		result.Action = "no_more_ready"
 	}
 
 	// JSON output
@@ -166,6 +234,14 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 			style.Dim.Render("ℹ"))
 		fmt.Printf("Run 'gt mol progress %s' to see blocked steps\n", moleculeID)
 		return nil
+
+	case "retry", "goto":
+		return handleStepContinue(cwd, townRoot, next, moleculeStepDryRun)
+
+	case "escalated":
+		fmt.Printf("\n%s Step %s stays open and blocks the molecule until the escalation is resolved\n",
+			style.Dim.Render("ℹ"), stepID)
+		return nil
 	}
 
 	return nil
@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// moleculeRun ties a molecule's step beads to the workflow formula it was
+// poured from, so gt mol step done can enforce the formula's step policy
+// (retry, timeout, when, on_failure). The run state is rebuilt from the
+// step beads and the step_run records kept on them.
+type moleculeRun struct {
+	name    string
+	formula *formula.Formula
+	state   *formula.RunState
+
+	beadByStep map[string]*beads.Issue // formula step id -> step bead
+	stepByBead map[string]string       // step bead id -> formula step id
+}
+
+// loadMoleculeRun returns the run of a molecule whose formula declares a
+// step policy, or nil when the formula can't be found or has no policy.
+// The formula is named by a "formula:" line in the root bead's description,
+// or else by the root bead's title.
+func loadMoleculeRun(b *beads.Beads, moleculeID string) *moleculeRun {
+	root, err := b.Show(moleculeID)
+	if err != nil {
+		return nil
+	}
+	name := moleculeFormulaName(root)
+	if name == "" {
+		return nil
+	}
+	f := loadStepPolicyFormula(name)
+	if f == nil {
+		return nil
+	}
+
+	children, err := b.List(beads.ListOptions{
+		Parent:   moleculeID,
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		style.PrintWarning("could not list steps of %s: %v", moleculeID, err)
+		return nil
+	}
+	return newMoleculeRun(f, moleculeRunVars(root), children)
+}
+
+// loadStepPolicyFormula finds and parses the named workflow formula,
+// returning nil when it can't be found or declares no step policy.
+func loadStepPolicyFormula(name string) *formula.Formula {
+	path, err := findFormulaFile(name)
+	if err != nil && !strings.HasPrefix(name, "mol-") {
+		path, err = findFormulaFile("mol-" + name)
+	}
+	if err != nil {
+		return nil
+	}
+	f, err := parseFormulaFile(path)
+	if err != nil {
+		style.PrintWarning("could not load step policy from formula %s: %v", name, err)
+		return nil
+	}
+	if f.Type != formula.TypeWorkflow || !f.HasStepPolicy() {
+		return nil
+	}
+	return f
+}
+
+// moleculeRunVars returns the vars recorded on a molecule root at pour
+// time by recordMoleculeVars, or nil for molecules poured without them.
+func moleculeRunVars(root *beads.Issue) map[string]string {
+	fields := beads.ParseAttachmentFields(root)
+	if fields == nil || fields.FormulaVars == "" {
+		return nil
+	}
+	var vars map[string]string
+	if err := json.Unmarshal([]byte(fields.FormulaVars), &vars); err != nil {
+		style.PrintWarning("could not parse formula vars on %s: %v", root.ID, err)
+		return nil
+	}
+	return vars
+}
+
+// stepPolicyVars returns the "key=value" vars a formula is poured with
+// when the formula declares a step policy, so they can be recorded on the
+// molecule root for its `when` conditions. Returns nil otherwise.
+func stepPolicyVars(formulaName string, varFlags []string) map[string]string {
+	if len(varFlags) == 0 || loadStepPolicyFormula(formulaName) == nil {
+		return nil
+	}
+	vars, err := parseVarFlags(varFlags)
+	if err != nil {
+		return nil
+	}
+	return vars
+}
+
+// recordMoleculeVars stores the vars a step-policy formula was poured with
+// on the molecule root. Failure only weakens `when` conditions to the
+// formula defaults, so it warns rather than failing the pour.
+func recordMoleculeVars(moleculeID, formulaName string, varFlags []string) {
+	vars := stepPolicyVars(formulaName, varFlags)
+	if vars == nil {
+		return
+	}
+	if err := storeFieldsInBead(moleculeID, beadFieldUpdates{FormulaVars: vars}); err != nil {
+		style.PrintWarning("could not record formula vars on %s: %v", moleculeID, err)
+	}
+}
+
+// newMoleculeRun matches step beads to formula steps by title and rebuilds
+// the run state. The vars recorded on the root at pour time drive `when`
+// conditions; for molecules poured without them, vars substituted into step
+// titles are recovered instead, and other vars take the formula defaults.
+func newMoleculeRun(f *formula.Formula, pourVars map[string]string, children []*beads.Issue) *moleculeRun {
+	run := &moleculeRun{
+		name:       f.Name,
+		formula:    f,
+		beadByStep: make(map[string]*beads.Issue),
+		stepByBead: make(map[string]string),
+	}
+	vars := make(map[string]string)
+	for _, child := range children {
+		for i := range f.Steps {
+			step := &f.Steps[i]
+			if _, taken := run.beadByStep[step.ID]; taken {
+				continue
+			}
+			captured, ok := matchStepTitle(step, child.Title)
+			if !ok {
+				continue
+			}
+			for k, v := range captured {
+				vars[k] = v
+			}
+			run.beadByStep[step.ID] = child
+			run.stepByBead[child.ID] = step.ID
+			break
+		}
+	}
+
+	for k, v := range pourVars {
+		vars[k] = v
+	}
+	run.state = f.NewRunState(vars)
+	for id, bead := range run.beadByStep {
+		rec := beads.ParseStepRun(bead)
+		s := run.state.Step(id)
+		s.Attempts, s.Jumps, s.Output = rec.Attempts, rec.Jumps, rec.Output
+		if t, err := time.Parse(time.RFC3339, rec.StartedAt); err == nil {
+			s.StartedAt = t
+		}
+		switch {
+		case bead.Status == "closed" && rec.Status == string(formula.StepSkipped):
+			s.Status = formula.StepSkipped
+		case bead.Status == "closed":
+			s.Status = formula.StepDone
+		case rec.Status == string(formula.StepFailed):
+			s.Status = formula.StepFailed
+		case bead.Status == "in_progress" || bead.Status == beads.StatusPinned || bead.Status == beads.StatusHooked:
+			s.Status = formula.StepRunning
+		default:
+			s.Status = formula.StepPending
+		}
+	}
+	return run
+}
+
+// moleculeFormulaName returns the formula a molecule root was poured from.
+func moleculeFormulaName(root *beads.Issue) string {
+	for _, line := range strings.Split(root.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "formula") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(root.Title)
+}
+
+// stepTitleVarRegex matches {{var}} placeholders in step titles.
+var stepTitleVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+// matchStepTitle reports whether a bead title was cooked from a formula
+// step, returning the values of any {{var}} placeholders in the step title.
+func matchStepTitle(step *formula.Step, title string) (map[string]string, bool) {
+	if title == step.ID {
+		return nil, true
+	}
+	if step.Title == "" {
+		return nil, false
+	}
+	locs := stepTitleVarRegex.FindAllStringSubmatchIndex(step.Title, -1)
+	if len(locs) == 0 {
+		return nil, title == step.Title
+	}
+
+	var pattern strings.Builder
+	var names []string
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range locs {
+		pattern.WriteString(regexp.QuoteMeta(step.Title[last:loc[0]]))
+		pattern.WriteString("(.+?)")
+		names = append(names, step.Title[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(step.Title[last:]))
+	pattern.WriteString("$")
+
+	m := regexp.MustCompile(pattern.String()).FindStringSubmatch(title)
+	if m == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(names))
+	for i, name := range names {
+		vars[name] = m[i+1]
+	}
+	return vars, true
+}
+
+// stepRef returns the formula step of a step bead ("" if none).
+func (r *moleculeRun) stepRef(beadID string) string {
+	if r == nil {
+		return ""
+	}
+	return r.stepByBead[beadID]
+}
+
+// record writes the run state of formula step id onto its bead. The bead
+// is re-read first so the rest of its description is kept intact.
+func (r *moleculeRun) record(b *beads.Beads, id string) error {
+	bead := r.beadByStep[id]
+	if bead == nil {
+		return nil
+	}
+	current, err := b.Show(bead.ID)
+	if err != nil {
+		return err
+	}
+	s := r.state.Step(id)
+	rec := beads.StepRun{
+		Status:   string(s.Status),
+		Attempts: s.Attempts,
+		Jumps:    s.Jumps,
+		Output:   s.Output,
+	}
+	if !s.StartedAt.IsZero() {
+		rec.StartedAt = s.StartedAt.UTC().Format(time.RFC3339)
+	}
+	desc := beads.SetStepRun(current, rec)
+	if err := b.Update(bead.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return err
+	}
+	bead.Description = desc
+	return nil
+}
+
+// markStarted starts the timeout clock of the step beads about to run.
+func (r *moleculeRun) markStarted(b *beads.Beads, steps []*beads.Issue) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	for _, bead := range steps {
+		id := r.stepRef(bead.ID)
+		if id == "" {
+			continue
+		}
+		s := r.state.Step(id)
+		s.Status, s.StartedAt = formula.StepRunning, now
+		if err := r.record(b, id); err != nil {
+			style.PrintWarning("could not record start of step %s: %v", bead.ID, err)
+		}
+	}
+}
+
+// skipFalseConditions closes the step beads whose needs are settled and
+// whose `when` condition is false, returning their IDs. Skipped steps count
+// as done, so their dependents become ready in their place.
+func (r *moleculeRun) skipFalseConditions(b *beads.Beads, dryRun bool) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	r.formula.Advance(r.state)
+
+	var skipped []string
+	for i := range r.formula.Steps {
+		step := &r.formula.Steps[i]
+		bead := r.beadByStep[step.ID]
+		if bead == nil || bead.Status == "closed" || r.state.Step(step.ID).Status != formula.StepSkipped {
+			continue
+		}
+		skipped = append(skipped, bead.ID)
+		if dryRun {
+			fmt.Printf("[dry-run] Would skip step %s (when %s is false)\n", bead.ID, step.When)
+			continue
+		}
+		if err := r.record(b, step.ID); err != nil {
+			return skipped, fmt.Errorf("recording skipped step: %w", err)
+		}
+		if err := b.CloseWithReason("skipped: when "+step.When+" is false", bead.ID); err != nil {
+			return skipped, fmt.Errorf("skipping step %s: %w", bead.ID, err)
+		}
+		bead.Status = "closed"
+		fmt.Printf("%s Skipped step %s: %s %s\n", style.Dim.Render("○"), bead.ID, bead.Title,
+			style.Dim.Render("(when "+step.When+" is false)"))
+	}
+	return skipped, nil
+}
+
+// failStep applies the formula's failure policy to a failed step and fills
+// in the result. It returns the step bead to run next for a retry or goto,
+// nil otherwise. After a skip the step is closed and the caller continues
+// to the next ready steps as for a completed step.
+func (r *moleculeRun) failStep(b *beads.Beads, step *beads.Issue, detail string, result *StepDoneResult, dryRun bool) (*beads.Issue, error) {
+	id := r.stepRef(step.ID)
+	res := formula.FailureResult{Kind: formula.FailureEscalate, Attempt: 1}
+	if id != "" {
+		res = r.formula.OnStepFailed(r.state, id)
+		r.state.Step(id).Output = detail
+	}
+	result.Failed = true
+	result.Attempt = res.Attempt
+
+	msg := fmt.Sprintf("%s Step %s failed", style.Warning.Render("✗"), step.ID)
+	if detail != "" {
+		msg += ": " + detail
+	}
+	fmt.Println(msg)
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would apply on_failure: %s\n", res.Kind)
+	}
+
+	switch res.Kind {
+	case formula.FailureRetry:
+		result.Action = "retry"
+		fmt.Printf("%s Retrying %s (retry %d of %d)\n", style.Bold.Render("↻"), step.ID,
+			res.Attempt, r.formula.GetStep(id).Retry)
+		if !dryRun {
+			if err := r.record(b, id); err != nil {
+				return nil, fmt.Errorf("recording attempt: %w", err)
+			}
+		}
+		return step, nil
+
+	case formula.FailureSkip:
+		result.Action = ""
+		result.SkippedSteps = append(result.SkippedSteps, step.ID)
+		if !dryRun {
+			if err := r.record(b, id); err != nil {
+				return nil, fmt.Errorf("recording skipped step: %w", err)
+			}
+			if err := b.CloseWithReason("skipped after failure: "+detail, step.ID); err != nil {
+				return nil, fmt.Errorf("closing step: %w", err)
+			}
+		}
+		result.StepClosed = true
+		fmt.Printf("%s Skipped step %s (on_failure = skip)\n", style.Dim.Render("○"), step.ID)
+		return nil, nil
+
+	case formula.FailureGoto:
+		target := r.beadByStep[res.Target]
+		if target == nil {
+			return nil, fmt.Errorf("on_failure target %s has no step bead in this molecule", res.Target)
+		}
+		result.Action = "goto"
+		result.NextStepID, result.NextStepTitle = target.ID, target.Title
+		fmt.Printf("%s Jumping back to %s: %s (jump %d of %d)\n", style.Bold.Render("↩"), target.ID, target.Title,
+			r.state.Step(id).Jumps, formula.MaxStepJumps)
+		if dryRun {
+			return target, nil
+		}
+		for _, rid := range res.Reset {
+			bead := r.beadByStep[rid]
+			if bead == nil {
+				continue
+			}
+			if bead.Status == "closed" {
+				open := "open"
+				if err := b.Update(bead.ID, beads.UpdateOptions{Status: &open}); err != nil {
+					return nil, fmt.Errorf("reopening step %s: %w", bead.ID, err)
+				}
+				bead.Status = open
+			}
+			if err := r.record(b, rid); err != nil {
+				return nil, fmt.Errorf("resetting step %s: %w", bead.ID, err)
+			}
+		}
+		return target, nil
+	}
+
+	// Escalate: the step stays open and blocks the rest of the molecule.
+	result.Action = "escalated"
+	if id != "" && !dryRun {
+		if err := r.record(b, id); err != nil {
+			style.PrintWarning("could not record failure of %s: %v", step.ID, err)
+		}
+	}
+	source := "molecule"
+	if r != nil {
+		source = "formula:" + r.name
+	}
+	reason := fmt.Sprintf("Step %s (%s) failed after %d attempt(s)", step.ID, step.Title, res.Attempt)
+	if detail != "" {
+		reason += ": " + detail
+	}
+	if dryRun {
+		fmt.Printf("[dry-run] Would escalate: %s\n", reason)
+		return nil, nil
+	}
+	escCmd := exec.Command("gt", "escalate", "Molecule step failed: "+step.Title,
+		"--severity", "high", "--reason", reason, "--source", source, "--related", step.ID)
+	escCmd.Stdout = os.Stdout
+	escCmd.Stderr = os.Stderr
+	if err := escCmd.Run(); err != nil {
+		return nil, fmt.Errorf("escalating step failure: %w", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestMatchStepTitle(t *testing.T) {
+	tests := []struct {
+		step  formula.Step
+		title string
+		ok    bool
+		vars  map[string]string
+	}{
+		{formula.Step{ID: "test", Title: "Run tests"}, "Run tests", true, nil},
+		{formula.Step{ID: "test", Title: "Run tests"}, "test", true, nil},
+		{formula.Step{ID: "test", Title: "Run tests"}, "Run tests again", false, nil},
+		{formula.Step{ID: "impl", Title: "Implement {{issue}} on {{branch}}"}, "Implement gt-12 on polecat/nux", true,
+			map[string]string{"issue": "gt-12", "branch": "polecat/nux"}},
+		{formula.Step{ID: "impl", Title: "Implement {{issue}} (fast)"}, "Implement gt-12 (slow)", false, nil},
+	}
+	for _, tt := range tests {
+		vars, ok := matchStepTitle(&tt.step, tt.title)
+		if ok != tt.ok || (tt.vars != nil && !reflect.DeepEqual(vars, tt.vars)) {
+			t.Errorf("matchStepTitle(%q, %q) = %v, %v; want %v, %v", tt.step.Title, tt.title, vars, ok, tt.vars, tt.ok)
+		}
+	}
+}
+
+func TestMoleculeFormulaName(t *testing.T) {
+	if got := moleculeFormulaName(&beads.Issue{Title: "mol-review", Description: "Notes\nformula: review-v2\n"}); got != "review-v2" {
+		t.Errorf("formula field: got %q", got)
+	}
+	if got := moleculeFormulaName(&beads.Issue{Title: "mol-review"}); got != "mol-review" {
+		t.Errorf("title fallback: got %q", got)
+	}
+}
+
+func TestNewMoleculeRun(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "review"
+type = "workflow"
+
+[vars.issue]
+[vars.mode]
+default = "full"
+
+[[steps]]
+id = "diff"
+title = "Diff {{issue}}"
+
+[[steps]]
+id = "security"
+title = "Security review"
+needs = ["diff"]
+when = 'steps.diff.output contains "auth/" && vars.issue == "gt-7"'
+
+[[steps]]
+id = "test"
+title = "Run tests"
+needs = ["security"]
+retry = 1
+timeout = "10m"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := "2026-10-16T12:00:00Z"
+	children := []*beads.Issue{
+		{ID: "gt-m.1", Title: "Diff gt-7", Status: "closed",
+			Description: beads.SetStepRun(&beads.Issue{}, beads.StepRun{Status: "done", Output: "internal/auth/a.go"})},
+		{ID: "gt-m.2", Title: "Security review", Status: "open"},
+		{ID: "gt-m.3", Title: "Run tests", Status: beads.StatusPinned,
+			Description: beads.SetStepRun(&beads.Issue{Description: "Run go test"}, beads.StepRun{Status: "running", Attempts: 1, StartedAt: started})},
+	}
+	run := newMoleculeRun(f, nil, children)
+
+	if run.stepRef("gt-m.3") != "test" || run.stepRef("gt-x") != "" {
+		t.Fatalf("step mapping = %v", run.stepByBead)
+	}
+	if run.state.Vars["issue"] != "gt-7" || run.state.Vars["mode"] != "full" {
+		t.Errorf("vars = %v, want issue from the title and mode default", run.state.Vars)
+	}
+
+	diff := run.state.Steps["diff"]
+	if diff.Status != formula.StepDone || diff.Output != "internal/auth/a.go" {
+		t.Errorf("diff state = %+v", diff)
+	}
+	test := run.state.Steps["test"]
+	if test.Status != formula.StepRunning || test.Attempts != 1 {
+		t.Errorf("test state = %+v", test)
+	}
+	want, _ := time.Parse(time.RFC3339, started)
+	if !test.StartedAt.Equal(want) || !f.GetStep("test").TimedOut(test.StartedAt, want.Add(11*time.Minute)) {
+		t.Errorf("test StartedAt = %v, want %v and a timeout after 10m", test.StartedAt, want)
+	}
+
+	// The security condition reads the recovered var and the diff output.
+	if ready := f.Advance(run.state); !reflect.DeepEqual(ready, []string{"security"}) {
+		t.Errorf("Advance = %v, want [security]", ready)
+	}
+}
+
+func TestNewMoleculeRun_PourVars(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "release"
+type = "workflow"
+
+[vars.channel]
+default = "stable"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "announce"
+title = "Announce release"
+needs = ["build"]
+when = 'vars.channel == "beta"'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := &beads.Issue{ID: "gt-m", Description: beads.SetAttachmentFields(
+		&beads.Issue{Description: "formula: release"},
+		&beads.AttachmentFields{FormulaVars: `{"channel":"beta"}`})}
+	children := []*beads.Issue{
+		{ID: "gt-m.1", Title: "Build", Status: "closed"},
+		{ID: "gt-m.2", Title: "Announce release", Status: "open"},
+	}
+
+	// Without the pour vars the condition falls back to the default and
+	// the step is skipped.
+	if ready := f.Advance(newMoleculeRun(f, nil, children).state); len(ready) != 0 {
+		t.Errorf("Advance without pour vars = %v, want none", ready)
+	}
+
+	// channel appears in no step title; only the root records it.
+	run := newMoleculeRun(f, moleculeRunVars(root), children)
+	if run.state.Vars["channel"] != "beta" {
+		t.Fatalf("vars = %v, want channel from the root", run.state.Vars)
+	}
+	if ready := f.Advance(run.state); !reflect.DeepEqual(ready, []string{"announce"}) {
+		t.Errorf("Advance = %v, want [announce]", ready)
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ batch command flags
+var (
+	mqBatchSize int
+	mqBatchJSON bool
+)
+
+var mqBatchCmd = &cobra.Command{
+	Use:   "batch <rig>",
+	Short: "Merge the top ready MRs together with one gate run",
+	Long: `Speculatively merge several ready merge requests at once.
+
+The highest-scoring ready MRs (same ordering as 'gt mq next') are stacked
+onto a speculative branch and the quality gates run once on the result.
+If they pass, the whole stack lands in a single push.
+
+If they fail, the stack is bisected to find the MR that breaks the gates.
+The MRs before it land, only the culprit's worker is sent MERGE_FAILED,
+and the MRs after it are restacked and tried again. MRs that conflict with
+others in the batch are left in the queue for a later round.
+
+The batch size comes from merge_queue.batch_size in the rig's config.json
+unless --size is given. A size below 2 means batching is disabled.
+
+Examples:
+  gt mq batch gastown             # Batch merge using the configured size
+  gt mq batch gastown --size 4    # Stack up to 4 MRs
+  gt mq batch gastown --json      # Output outcomes as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQBatch,
+}
+
+func init() {
+	mqBatchCmd.Flags().IntVar(&mqBatchSize, "size", 0, "Maximum MRs per batch (default: merge_queue.batch_size)")
+	mqBatchCmd.Flags().BoolVar(&mqBatchJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqBatchCmd)
+}
+
+// MQBatchOutcome is the JSON form of one MR's batch outcome.
+type MQBatchOutcome struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	Status      string `json:"status"` // merged | failed | conflict | deferred
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func runMQBatch(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if mqBatchJSON {
+		// Keep stdout clean for the JSON document.
+		eng.SetOutput(os.Stderr)
+	}
+
+	size := mqBatchSize
+	if size == 0 {
+		size = eng.Config().BatchSize
+	}
+	if size < 2 {
+		return fmt.Errorf("batching is disabled for %s: set merge_queue.batch_size or pass --size (>= 2)", rigName)
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	open, err := eng.ListAllOpenMRs()
+	if err != nil {
+		return fmt.Errorf("listing open MRs: %w", err)
+	}
+	scorer, err := eng.NewScorer(open)
+	if err != nil {
+		return err
+	}
+	batch := refinery.SelectBatch(ready, size, scorer, time.Now())
+	if len(batch) == 0 {
+		if mqBatchJSON {
+			return outputJSON([]MQBatchOutcome{})
+		}
+		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	workerID := getWorkerID()
+	var claimed []*refinery.MRInfo
+	for _, mr := range batch {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Skipping %s: %v\n", style.Dim.Render("⚠"), mr.ID, err)
+			continue
+		}
+		claimed = append(claimed, mr)
+	}
+	if len(claimed) == 0 {
+		return fmt.Errorf("could not claim any of the %d selected MRs", len(batch))
+	}
+
+	res := eng.ProcessBatch(cmd.Context(), claimed)
+	eng.HandleBatchResult(res)
+
+	// Unclaim everything that didn't land so the next round (or the
+	// single-MR path) can pick it up again.
+	for _, o := range res.Outcomes {
+		if o.Deferred || !o.Result.Success {
+			_ = eng.ReleaseMR(o.MR.ID)
+		}
+	}
+
+	outcomes := make([]MQBatchOutcome, 0, len(res.Outcomes))
+	for _, o := range res.Outcomes {
+		outcomes = append(outcomes, MQBatchOutcome{
+			ID:          o.MR.ID,
+			Branch:      o.MR.Branch,
+			Status:      batchOutcomeStatus(o),
+			MergeCommit: o.Result.MergeCommit,
+			Error:       o.Result.Error,
+		})
+	}
+
+	if mqBatchJSON {
+		return outputJSON(outcomes)
+	}
+
+	fmt.Printf("\n%s Batch into %s (%d gate run(s)):\n\n", style.Bold.Render("📦"), res.Target, res.Checks)
+	for _, o := range outcomes {
+		line := fmt.Sprintf("  %s %s  %s", batchStatusIcon(o.Status), o.ID, o.Status)
+		if o.Error != "" && o.Status != "merged" {
+			line += "  " + style.Dim.Render(o.Error)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func batchOutcomeStatus(o refinery.BatchOutcome) string {
+	switch {
+	case o.Deferred:
+		return "deferred"
+	case o.Result.Success:
+		return "merged"
+	case o.Result.Conflict:
+		return "conflict"
+	default:
+		return "failed"
+	}
+}
+
+func batchStatusIcon(status string) string {
+	switch status {
+	case "merged":
+		return style.Success.Render("✓")
+	case "deferred":
+		return style.Dim.Render("↻")
+	default:
+		return style.Error.Render("✗")
+	}
+}
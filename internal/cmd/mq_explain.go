@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ explain command flags
+var (
+	mqExplainRig  string
+	mqExplainJSON bool
+)
+
+var mqExplainCmd = &cobra.Command{
+	Use:   "explain <mr-id>",
+	Short: "Show why a merge request is ranked where it is",
+	Long: `Break down a merge request's priority score factor by factor.
+
+Shows each factor's contribution (priority, convoy age and deadline, retries,
+time in queue, issues it unblocks, file overlap with in-flight MRs) and the
+MR's rank among the ready MRs, using the same scorer as 'gt mq next'.
+
+Scoring weights come from merge_queue.scoring in the rig settings:
+  gt rig settings set <rig> merge_queue.scoring.unblock_weight 80
+
+Examples:
+  gt mq explain gt-mr-abc123
+  gt mq explain gt-mr-abc123 --rig gastown
+  gt mq explain gt-mr-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQExplain,
+}
+
+func init() {
+	mqExplainCmd.Flags().StringVar(&mqExplainRig, "rig", "", "Rig the MR belongs to (default: from the MR's rig field)")
+	mqExplainCmd.Flags().BoolVar(&mqExplainJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqExplainCmd)
+}
+
+// MQExplainOutput is the JSON output of gt mq explain.
+type MQExplainOutput struct {
+	ID        string                 `json:"id"`
+	Rig       string                 `json:"rig"`
+	Score     float64                `json:"score"`
+	Rank      int                    `json:"rank,omitempty"` // 1-based among ready MRs; 0 if not ready
+	Ready     int                    `json:"ready"`
+	BlockedBy []string               `json:"blocked_by,omitempty"`
+	Assignee  string                 `json:"assignee,omitempty"`
+	Factors   []refinery.ScoreFactor `json:"factors"`
+}
+
+func runMQExplain(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	rigName := mqExplainRig
+	if rigName == "" {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		issue, err := beads.New(workDir).Show(mrID)
+		if err != nil {
+			if err == beads.ErrNotFound {
+				return fmt.Errorf("merge request '%s' not found (try --rig)", mrID)
+			}
+			return fmt.Errorf("fetching merge request: %w", err)
+		}
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			rigName = fields.Rig
+		}
+		if rigName == "" {
+			return fmt.Errorf("merge request '%s' has no rig field; pass --rig", mrID)
+		}
+	}
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(r.BeadsPath())
+	issues, err := b.List(beads.ListOptions{
+		Label:    "gt:merge-request",
+		Status:   "open",
+		Priority: -1, // No priority filter
+	})
+	if err != nil {
+		return fmt.Errorf("querying merge queue: %w", err)
+	}
+
+	var target *beads.Issue
+	for _, issue := range issues {
+		if issue.ID == mrID {
+			target = issue
+			break
+		}
+	}
+	if target == nil {
+		issue, err := b.Show(mrID)
+		if err != nil {
+			return fmt.Errorf("merge request '%s' not found in %s: %w", mrID, rigName, err)
+		}
+		target = issue
+	}
+
+	now := time.Now()
+	scorer := newMQScorer(r, issues)
+	breakdown := scorer.Explain(mrInfoForScoring(target, now), now)
+
+	// Rank among ready MRs, the set 'gt mq next' picks from
+	type scored struct {
+		id    string
+		score float64
+	}
+	var ready []scored
+	for _, issue := range issues {
+		if issue.Status != "open" || len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
+			continue
+		}
+		ready = append(ready, scored{id: issue.ID, score: calculateMRScore(scorer, issue, now)})
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].score > ready[j].score
+	})
+
+	out := MQExplainOutput{
+		ID:        target.ID,
+		Rig:       rigName,
+		Score:     breakdown.Total,
+		Ready:     len(ready),
+		BlockedBy: target.BlockedBy,
+		Assignee:  target.Assignee,
+		Factors:   breakdown.Factors,
+	}
+	for i, s := range ready {
+		if s.id == target.ID {
+			out.Rank = i + 1
+			break
+		}
+	}
+
+	if mqExplainJSON {
+		return outputJSON(out)
+	}
+	printMQExplain(out)
+	return nil
+}
+
+func printMQExplain(out MQExplainOutput) {
+	fmt.Printf("%s Score for %s: %s\n", style.Bold.Render("📊"), out.ID, style.Bold.Render(fmt.Sprintf("%.1f", out.Score)))
+	switch {
+	case out.Rank > 0:
+		fmt.Printf("  Rank %d of %d ready MR(s) in %s\n", out.Rank, out.Ready, out.Rig)
+	case len(out.BlockedBy) > 0:
+		fmt.Printf("  %s\n", style.Warning.Render(fmt.Sprintf("Not ready: blocked by %v (score doesn't matter until unblocked)", out.BlockedBy)))
+	default:
+		fmt.Printf("  %s\n", style.Dim.Render("Not in the ready queue"))
+	}
+	if out.Assignee != "" {
+		fmt.Printf("  Claimed by %s\n", out.Assignee)
+	}
+	fmt.Println()
+
+	table := style.NewTable(
+		style.Column{Name: "FACTOR", Width: 12},
+		style.Column{Name: "POINTS", Width: 9, Align: style.AlignRight},
+		style.Column{Name: "DETAIL", Width: 48},
+	)
+	for _, f := range out.Factors {
+		points := fmt.Sprintf("%+.1f", f.Points)
+		if f.Points < 0 {
+			points = style.Error.Render(points)
+		}
+		table.AddRow(f.Name, points, f.Detail)
+	}
+	fmt.Print(table.Render())
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -76,6 +77,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	// Apply additional filters and calculate scores
 	now := time.Now()
+	scorer := newMQScorer(r, issues)
 	type scoredIssue struct {
 		issue          *beads.Issue
 		fields         *beads.MRFields
@@ -132,7 +134,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		branchMissing, branchVerifyErr := verifyBranch(mqListVerify, gitClient, fields)
 
 		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
+		score := calculateMRScore(scorer, issue, now)
 		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
 	}
 
@@ -353,38 +355,41 @@ func outputJSON(data interface{}) error {
 	return enc.Encode(data)
 }
 
-// calculateMRScore computes the priority score for an MR using the refinery scoring function.
-// Higher scores mean higher priority (process first).
-func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
-	// Parse MR creation time
-	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
-	if err != nil {
-		mrCreatedAt, err = time.Parse("2006-01-02T15:04:05Z", issue.CreatedAt)
-		if err != nil {
-			mrCreatedAt = now // Fallback to now if parsing fails
+// newMQScorer builds the refinery's scorer for the rig over the given MR
+// beads, so queue views rank MRs the same way the refinery does. If the
+// scoring config can't be read it warns and falls back to the defaults.
+func newMQScorer(r *rig.Rig, issues []*beads.Issue) *refinery.Scorer {
+	var mrs []*refinery.MRInfo
+	for _, issue := range issues {
+		if mr := refinery.MRInfoFromIssue(issue); mr != nil {
+			mrs = append(mrs, mr)
 		}
 	}
-
-	// Build score input
-	input := refinery.ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
+	scorer, err := refinery.NewEngineer(r).NewScorer(mrs)
+	if err != nil {
+		style.PrintWarning("%v (using default scoring)", err)
+		return refinery.NewScorer(refinery.DefaultScoreConfig())
 	}
+	return scorer
+}
 
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
+// mrInfoForScoring converts an MR bead for scoring, falling back to the
+// bead's own priority and age when it has no MR fields.
+func mrInfoForScoring(issue *beads.Issue, now time.Time) *refinery.MRInfo {
+	mr := refinery.MRInfoFromIssue(issue)
+	if mr == nil {
+		mr = &refinery.MRInfo{ID: issue.ID, Priority: issue.Priority}
+	}
+	if mr.CreatedAt.IsZero() {
+		mr.CreatedAt = now // Fallback if parsing fails
 	}
+	return mr
+}
 
-	return refinery.ScoreMRWithDefaults(input)
+// calculateMRScore computes the priority score for an MR using the refinery scoring function.
+// Higher scores mean higher priority (process first).
+func calculateMRScore(scorer *refinery.Scorer, issue *beads.Issue, now time.Time) float64 {
+	return scorer.Score(mrInfoForScoring(issue, now), now)
 }
 
 // branchVerifier abstracts git branch existence checks for testability.
@@ -91,6 +91,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	now := time.Now()
+	scorer := newMQScorer(r, issues)
 
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
@@ -108,8 +109,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 		scored := make([]scoredIssue, len(ready))
 		for i, issue := range ready {
-			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
+			score := calculateMRScore(scorer, issue, now)
 			scored[i] = scoredIssue{issue: issue, score: score}
 		}
 
@@ -140,7 +140,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
+	score := calculateMRScore(scorer, next, now)
 
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
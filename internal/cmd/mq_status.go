@@ -34,11 +34,24 @@ type MRStatusOutput struct {
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
 
+	// Quality gate history, oldest first
+	Gates []GateRunInfo `json:"gates,omitempty"`
+
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
 	Blocks    []DependencyInfo `json:"blocks,omitempty"`
 }
 
+// GateRunInfo is one recorded quality-gate outcome.
+type GateRunInfo struct {
+	Gate      string `json:"gate"`
+	Status    string `json:"status"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+	Attempts  int    `json:"attempts,omitempty"`
+	Tree      string `json:"tree,omitempty"`
+	At        string `json:"at,omitempty"`
+}
+
 // DependencyInfo represents a dependency or blocker.
 type DependencyInfo struct {
 	ID       string `json:"id"`
@@ -97,6 +110,18 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.CloseReason = mrFields.CloseReason
 	}
 
+	gateRuns := beads.ParseGateRuns(issue)
+	for _, run := range gateRuns {
+		output.Gates = append(output.Gates, GateRunInfo{
+			Gate:      run.Gate,
+			Status:    run.Status,
+			ElapsedMs: run.Elapsed.Milliseconds(),
+			Attempts:  run.Attempts,
+			Tree:      run.Tree,
+			At:        run.At,
+		})
+	}
+
 	// Add dependency info from the issue's Dependencies field
 	for _, dep := range issue.Dependencies {
 		output.DependsOn = append(output.DependsOn, DependencyInfo{
@@ -127,11 +152,11 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
-	return printMqStatus(issue, mrFields)
+	return printMqStatus(issue, mrFields, gateRuns)
 }
 
 // printMqStatus prints detailed MR status in human-readable format.
-func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
+func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields, gateRuns []beads.GateRun) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("📋 Merge Request:"), issue.ID)
 	fmt.Printf("   %s\n\n", issue.Title)
@@ -186,6 +211,32 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		}
 	}
 
+	// Gate history, one line per gate per refinery run
+	if len(gateRuns) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Gate History"))
+		width := 0
+		for _, run := range gateRuns {
+			width = max(width, len(run.Gate))
+		}
+		for _, run := range gateRuns {
+			detail := run.Elapsed.Truncate(time.Millisecond).String()
+			if run.Attempts > 1 {
+				detail += fmt.Sprintf(", %d attempts", run.Attempts)
+			}
+			if run.Tree != "" {
+				detail += ", tree " + run.Tree
+			}
+			if run.At != "" {
+				detail += ", " + run.At
+			}
+			fmt.Printf("   %s %-*s %-7s %s\n",
+				gateStatusIcon(run.Status),
+				width, run.Gate,
+				run.Status,
+				style.Dim.Render(detail))
+		}
+	}
+
 	// Dependencies (what this MR is waiting on)
 	if len(issue.Dependencies) > 0 {
 		fmt.Printf("\n%s\n", style.Bold.Render("Waiting On"))
@@ -239,6 +290,22 @@ func formatStatus(status string) string {
 	}
 }
 
+// gateStatusIcon returns an icon for a gate run status.
+func gateStatusIcon(status string) string {
+	switch status {
+	case "passed", "cached":
+		return style.Success.Render("✓")
+	case "failed":
+		return style.Error.Render("✗")
+	case "skipped":
+		return style.Dim.Render("–")
+	case "blocked":
+		return style.Dim.Render("○")
+	default:
+		return "•"
+	}
+}
+
 // getStatusIcon returns an icon for the given status.
 func getStatusIcon(status string) string {
 	switch status {
@@ -328,6 +395,9 @@ func getDescriptionWithoutMRFields(description string) string {
 		"close-reason": true,
 		"closereason":  true,
 		"type":         true,
+		"gate_run":     true,
+		"gate-run":     true,
+		"gaterun":      true,
 	}
 
 	var lines []string
@@ -16,7 +16,9 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // branchInfo holds parsed branch information.
@@ -75,7 +77,7 @@ func parseBranchName(branch string) branchInfo {
 	return info
 }
 
-func runMqSubmit(cmd *cobra.Command, args []string) error {
+func runMqSubmit(cmd *cobra.Command, args []string) (retErr error) {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -158,6 +160,11 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot determine source issue from branch '%s'; use --issue to specify", branch)
 	}
 
+	ctx, span := telemetry.Start(issueTraceContext(cwd, issueID), "gt.mq.submit",
+		attribute.String("gt.bead", issueID),
+		attribute.String("gt.branch", branch))
+	defer func() { telemetry.End(span, retErr) }()
+
 	// Initialize beads for looking up source issue
 	bd := beads.New(cwd)
 
@@ -209,6 +216,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	if tp := telemetry.TraceParent(ctx); tp != "" {
+		description += fmt.Sprintf("\ntraceparent: %s", tp)
+	}
 
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
@@ -252,6 +262,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" && !mqSubmitNoCleanup {
 		fmt.Println()
 		fmt.Printf("%s Auto-cleanup: polecat work submitted\n", style.Bold.Render("✓"))
+		// The session may be torn down before we return.
+		telemetry.End(span, nil)
+		telemetry.Flush()
 		if err := polecatCleanup(rigName, worker, townRoot); err != nil {
 			// Non-fatal: warn but return success (MR was created)
 			style.PrintWarning("Could not auto-cleanup: %v", err)
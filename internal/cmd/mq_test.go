@@ -136,6 +136,11 @@ func TestGetDescriptionWithoutMRFields(t *testing.T) {
 			description: "Just a regular description\nWith multiple lines",
 			want:        "Just a regular description\nWith multiple lines",
 		},
+		{
+			name:        "gate history",
+			description: "branch: polecat/Nux/gt-xyz\nSome custom notes\ngate_run: test passed 1s attempts=1",
+			want:        "Some custom notes",
+		},
 	}
 
 	for _, tt := range tests {
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
@@ -84,6 +85,11 @@ Role shortcuts (expand to session names):
   witness   Maps to gt-<rig>-witness (uses current rig)
   refinery  Maps to gt-<rig>-refinery (uses current rig)
 
+Remote machines:
+  <machine>:<target>  Nudges an agent in a town on another machine registered
+                      in settings/machines.json (e.g., buildbox:gastown/rictus).
+                      The remote town applies its own DND and delivery rules.
+
 Channel syntax:
   channel:<name>  Nudges all members of a named channel defined in
                   ~/gt/config/messaging.json under "nudge_channels".
@@ -100,6 +106,7 @@ Examples:
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
   gt nudge channel:workers "New priority work available"
+  gt nudge buildbox:gastown/rictus "Rebase on main"
 
   # Use --stdin for messages with special characters or formatting:
   gt nudge gastown/alpha --stdin <<'EOF'
@@ -123,58 +130,90 @@ var waitIdleTimeout = 15 * time.Second
 // For "immediate" mode: sends directly via tmux (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
-func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
+//
+// It returns the nudge ID for "gt nudge status", or "" outside a workspace
+// (where no receipt can be kept).
+func deliverNudge(t session.SessionBackend, sessionName, message, sender string) (string, error) {
 	townRoot, _ := workspace.FindFromCwd()
 
 	// For direct tmux delivery, prefix with sender attribution.
 	// Queue-based delivery stores Sender as a separate field and
 	// FormatForInjection adds the prefix, so we must NOT double-prefix.
 	prefixedMessage := fmt.Sprintf("[from %s] %s", sender, message)
+	queued := nudge.QueuedNudge{
+		ID:       nudge.NewID(),
+		Sender:   sender,
+		Message:  message,
+		Priority: nudgePriorityFlag,
+	}
+
+	// sendNow delivers directly and records the receipt as injected.
+	sendNow := func() (string, error) {
+		if err := t.NudgeSession(sessionName, prefixedMessage); err != nil {
+			return "", err
+		}
+		if townRoot == "" {
+			return "", nil
+		}
+		id, err := nudge.Record(townRoot, sessionName, queued)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: recording nudge receipt: %v\n", err)
+			return "", nil
+		}
+		return id, nil
+	}
 
 	switch nudgeModeFlag {
 	case NudgeModeQueue:
 		if townRoot == "" {
-			return fmt.Errorf("--mode=queue requires a Gas Town workspace")
+			return "", fmt.Errorf("--mode=queue requires a Gas Town workspace")
 		}
-		return nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
-			Sender:   sender,
-			Message:  message,
-			Priority: nudgePriorityFlag,
-		})
+		if err := nudge.Enqueue(townRoot, sessionName, queued); err != nil {
+			return "", err
+		}
+		return queued.ID, nil
 
 	case NudgeModeWaitIdle:
 		if townRoot == "" {
 			// wait-idle needs workspace for queue fallback — fail explicitly
 			// rather than silently degrading to immediate (destructive) delivery.
-			return fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
+			return "", fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
+		}
+		// Try to wait for idle. Idle detection reads the tmux prompt; other
+		// backends treat the agent as busy and queue.
+		err := errors.New("idle detection requires tmux")
+		if realTmux, ok := t.(*tmux.Tmux); ok {
+			err = realTmux.WaitForIdle(sessionName, waitIdleTimeout)
 		}
-		// Try to wait for idle
-		err := t.WaitForIdle(sessionName, waitIdleTimeout)
 		if err == nil {
 			// Agent is idle — safe to deliver directly
-			return t.NudgeSession(sessionName, prefixedMessage)
+			return sendNow()
 		}
 		// Terminal errors (session gone, no server) — propagate, don't queue.
 		// Queueing a nudge for a dead session means it will never be delivered.
 		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
-			return fmt.Errorf("wait-idle: %w", err)
+			return "", fmt.Errorf("wait-idle: %w", err)
 		}
 		// Timeout (agent busy) — queue instead
-		if qErr := nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
-			Sender:   sender,
-			Message:  message,
-			Priority: nudgePriorityFlag,
-		}); qErr != nil {
+		if qErr := nudge.Enqueue(townRoot, sessionName, queued); qErr != nil {
 			// Queue failed — fall back to immediate as last resort.
 			// Better to interrupt than lose the message entirely.
 			fmt.Fprintf(os.Stderr, "Warning: queue fallback failed (%v), delivering immediately\n", qErr)
-			return t.NudgeSession(sessionName, prefixedMessage)
+			return sendNow()
 		}
-		return nil
+		return queued.ID, nil
 
 	default: // NudgeModeImmediate
-		return t.NudgeSession(sessionName, prefixedMessage)
+		return sendNow()
+	}
+}
+
+// nudgeIDSuffix formats a nudge ID for appending to a delivery message.
+func nudgeIDSuffix(id string) string {
+	if id == "" {
+		return ""
 	}
+	return " " + style.Dim.Render(id)
 }
 
 // validNudgeModes is the set of allowed --mode values.
@@ -267,6 +306,17 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		return runNudgeChannel(channelName, message, sender)
 	}
 
+	// Machine-qualified target: the remote town resolves the session,
+	// checks DND and delivers using its own nudge queue.
+	remote, rest, err := resolveRemoteTarget(target)
+	if err != nil {
+		return err
+	}
+	if remote != nil {
+		return runRemoteNudge(remote, rest, message, sender)
+	}
+	target = rest
+
 	// Check DND status for target (unless force flag or channel target)
 	townRoot, _ := workspace.FindFromCwd()
 	if townRoot != "" && !nudgeForceFlag {
@@ -278,7 +328,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	// Expand role shortcuts to session names
 	// These shortcuts let users type "mayor" instead of "gt-mayor"
@@ -316,11 +366,12 @@ func runNudge(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		if err := deliverNudge(t, deaconSession, message, sender); err != nil {
+		id, err := deliverNudge(t, deaconSession, message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging deacon: %w", err)
 		}
 
-		fmt.Printf("%s Nudged deacon (%s)\n", style.Bold.Render("✓"), nudgeModeFlag)
+		fmt.Printf("%s Nudged deacon (%s)%s\n", style.Bold.Render("✓"), nudgeModeFlag, nudgeIDSuffix(id))
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -375,11 +426,12 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 
 		// Send nudge using the configured delivery mode
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		id, err := deliverNudge(t, sessionName, message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
-		fmt.Printf("%s Nudged %s/%s (%s)\n", style.Bold.Render("✓"), rigName, polecatName, nudgeModeFlag)
+		fmt.Printf("%s Nudged %s/%s (%s)%s\n", style.Bold.Render("✓"), rigName, polecatName, nudgeModeFlag, nudgeIDSuffix(id))
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -396,11 +448,12 @@ func runNudge(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("session %q not found", target)
 		}
 
-		if err := deliverNudge(t, target, message, sender); err != nil {
+		id, err := deliverNudge(t, target, message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
-		fmt.Printf("✓ Nudged %s (%s)\n", target, nudgeModeFlag)
+		fmt.Printf("✓ Nudged %s (%s)%s\n", target, nudgeModeFlag, nudgeIDSuffix(id))
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -412,6 +465,27 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRemoteNudge delivers a nudge to an agent in another town by running
+// "gt nudge" on that machine.
+func runRemoteNudge(remote *connection.RemoteTown, target, message, sender string) error {
+	args := []string{"nudge", target, "-m", message, "--mode", nudgeModeFlag, "--priority", nudgePriorityFlag}
+	if nudgeForceFlag {
+		args = append(args, "--force")
+	}
+	out, err := remote.Gt(sender, args...)
+	if err != nil {
+		return fmt.Errorf("nudging %s:%s: %w", remote.Machine.Name, target, err)
+	}
+	fmt.Print(string(out))
+
+	qualified := remote.Machine.Name + ":" + target
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		_ = LogNudge(townRoot, qualified, message)
+	}
+	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", qualified, message))
+	return nil
+}
+
 // runNudgeChannel nudges all members of a named channel.
 // Routes each target through deliverNudge so --mode is respected.
 func runNudgeChannel(channelName, message, sender string) error {
@@ -464,7 +538,7 @@ func runNudgeChannel(channelName, message, sender string) error {
 	}
 
 	// Send nudges via deliverNudge (respects --mode flag)
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	var succeeded, failed, skipped int
 	var failures []string
 
@@ -482,13 +556,13 @@ func runNudgeChannel(channelName, message, sender string) error {
 			}
 		}
 
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		if id, err := deliverNudge(t, sessionName, message, sender); err != nil {
 			failed++
 			failures = append(failures, fmt.Sprintf("%s: %v", sessionName, err))
 			fmt.Printf("  %s %s\n", style.ErrorPrefix, sessionName)
 		} else {
 			succeeded++
-			fmt.Printf("  %s %s\n", style.SuccessPrefix, sessionName)
+			fmt.Printf("  %s %s%s\n", style.SuccessPrefix, sessionName, nudgeIDSuffix(id))
 		}
 
 		// Small delay between nudges
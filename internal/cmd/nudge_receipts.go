@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	nudgeStatusJSON    bool
+	nudgeStatusSession string
+	nudgeAckJSON       bool
+)
+
+var nudgeStatusCmd = &cobra.Command{
+	Use:   "status [nudge-id]",
+	Short: "Show delivery status of nudges",
+	Long: `Show whether a nudge reached its agent.
+
+Every nudge sent from inside a workspace gets an ID (printed by gt nudge) and
+a receipt that moves through these states:
+
+  queued        Waiting in the agent's queue for its next turn boundary
+  injected      In front of the agent (drained by its hook, or sent directly)
+  acknowledged  The agent ran gt nudge ack, or finished the turn that saw it
+  expired       The queue TTL passed before the agent picked it up
+
+With no ID, lists recent nudges (optionally for one session).
+Receipts are kept for 24 hours.
+
+Examples:
+  gt nudge status nd-3f9a1c2b7e41
+  gt nudge status --session gt-gastown-toast
+  gt nudge status --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNudgeStatus,
+}
+
+var nudgeAckCmd = &cobra.Command{
+	Use:   "ack [nudge-id...]",
+	Short: "Acknowledge nudges you have handled",
+	Long: `Acknowledge nudges so their senders know you saw them.
+
+With no IDs, acknowledges every nudge injected into the current session.
+The Stop hook (gt signal stop) does this automatically at the end of each
+turn, so agents only need to ack explicitly mid-turn.
+
+Examples:
+  gt nudge ack nd-3f9a1c2b7e41
+  gt nudge ack`,
+	RunE: runNudgeAck,
+}
+
+func init() {
+	nudgeStatusCmd.Flags().BoolVar(&nudgeStatusJSON, "json", false, "Output as JSON")
+	nudgeStatusCmd.Flags().StringVar(&nudgeStatusSession, "session", "", "Only list nudges for this session")
+	nudgeAckCmd.Flags().BoolVar(&nudgeAckJSON, "json", false, "Output as JSON")
+
+	nudgeCmd.AddCommand(nudgeStatusCmd)
+	nudgeCmd.AddCommand(nudgeAckCmd)
+}
+
+func runNudgeStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 1 {
+		n, err := nudge.Status(townRoot, args[0])
+		if err != nil {
+			return err
+		}
+		if nudgeStatusJSON {
+			return printNudgeJSON(n)
+		}
+		printNudgeReceipt(n)
+		return nil
+	}
+
+	receipts, err := nudge.List(townRoot, nudgeStatusSession)
+	if err != nil {
+		return err
+	}
+	if nudgeStatusJSON {
+		if receipts == nil {
+			receipts = []nudge.QueuedNudge{}
+		}
+		return printNudgeJSON(receipts)
+	}
+	if len(receipts) == 0 {
+		fmt.Println("No nudges in the last 24 hours")
+		return nil
+	}
+	for _, n := range receipts {
+		// Pad before styling; escape codes would throw off %-12s.
+		state := nudgeStateLabel(n.State) + strings.Repeat(" ", max(0, 12-len(n.State)))
+		fmt.Printf("%s  %s  %-24s  %s  %s\n",
+			n.ID, state, n.Session,
+			style.Dim.Render(n.Timestamp.Format("15:04:05")), truncateNudgeMessage(n.Message))
+	}
+	return nil
+}
+
+func runNudgeAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	by := detectSender()
+
+	var acked []nudge.QueuedNudge
+	if len(args) == 0 {
+		sessionName := tmux.CurrentSessionName()
+		if sessionName == "" {
+			return fmt.Errorf("not in an agent session; pass nudge IDs to acknowledge")
+		}
+		acked, err = nudge.AckSession(townRoot, sessionName, by)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, id := range args {
+			n, err := nudge.Ack(townRoot, id, by)
+			if err != nil {
+				return err
+			}
+			acked = append(acked, *n)
+		}
+	}
+
+	if nudgeAckJSON {
+		if acked == nil {
+			acked = []nudge.QueuedNudge{}
+		}
+		return printNudgeJSON(acked)
+	}
+	if len(acked) == 0 {
+		fmt.Println("No nudges to acknowledge")
+		return nil
+	}
+	for _, n := range acked {
+		fmt.Printf("%s Acknowledged %s from %s\n", style.SuccessPrefix, n.ID, n.Sender)
+	}
+	return nil
+}
+
+func printNudgeReceipt(n *nudge.QueuedNudge) {
+	fmt.Printf("%s %s\n", style.Bold.Render(n.ID), nudgeStateLabel(n.State))
+	fmt.Printf("  Session:  %s\n", n.Session)
+	fmt.Printf("  From:     %s\n", n.Sender)
+	fmt.Printf("  Priority: %s\n", n.Priority)
+	fmt.Printf("  Sent:     %s\n", formatNudgeTime(n.Timestamp))
+	if !n.InjectedAt.IsZero() {
+		fmt.Printf("  Injected: %s\n", formatNudgeTime(n.InjectedAt))
+	}
+	if !n.AckedAt.IsZero() {
+		fmt.Printf("  Acked:    %s by %s\n", formatNudgeTime(n.AckedAt), n.AckedBy)
+	}
+	if n.State == nudge.StateQueued && !n.ExpiresAt.IsZero() {
+		fmt.Printf("  Expires:  %s\n", formatNudgeTime(n.ExpiresAt))
+	}
+	fmt.Printf("  Message:  %s\n", n.Message)
+}
+
+func nudgeStateLabel(state string) string {
+	switch state {
+	case nudge.StateAcknowledged:
+		return style.Success.Render(state)
+	case nudge.StateInjected:
+		return style.Info.Render(state)
+	case nudge.StateExpired:
+		return style.Error.Render(state)
+	default:
+		return style.Warning.Render(state)
+	}
+}
+
+func formatNudgeTime(t time.Time) string {
+	d := time.Since(t).Round(time.Second)
+	if d < 0 {
+		return fmt.Sprintf("%s (in %s)", t.Format("15:04:05"), -d)
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Format("15:04:05"), d)
+}
+
+func truncateNudgeMessage(msg string) string {
+	const limit = 60
+	if len(msg) <= limit {
+		return msg
+	}
+	return msg[:limit-3] + "..."
+}
+
+func printNudgeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+func TestNudgeAckAndStatus(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	id, err := nudge.Record(townRoot, "gt-gastown-toast", nudge.QueuedNudge{Sender: "mayor", Message: "Check your hook"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	origJSON := nudgeStatusJSON
+	t.Cleanup(func() { nudgeStatusJSON = origJSON })
+	nudgeStatusJSON = true
+
+	readStatus := func() nudge.QueuedNudge {
+		t.Helper()
+		var runErr error
+		out := captureStdout(t, func() { runErr = runNudgeStatus(nudgeStatusCmd, []string{id}) })
+		if runErr != nil {
+			t.Fatalf("runNudgeStatus: %v", runErr)
+		}
+		var n nudge.QueuedNudge
+		if err := json.Unmarshal([]byte(out), &n); err != nil {
+			t.Fatalf("parsing status output %q: %v", out, err)
+		}
+		return n
+	}
+
+	if n := readStatus(); n.State != nudge.StateInjected {
+		t.Errorf("state before ack = %q, want injected", n.State)
+	}
+
+	var ackErr error
+	out := captureStdout(t, func() { ackErr = runNudgeAck(nudgeAckCmd, []string{id}) })
+	if ackErr != nil {
+		t.Fatalf("runNudgeAck: %v", ackErr)
+	}
+	if !strings.Contains(out, id) {
+		t.Errorf("ack output %q does not mention %s", out, id)
+	}
+
+	if n := readStatus(); n.State != nudge.StateAcknowledged {
+		t.Errorf("state after ack = %q, want acknowledged", n.State)
+	}
+
+	if err := runNudgeStatus(nudgeStatusCmd, []string{"nd-ffffffff"}); err == nil {
+		t.Error("status of an unknown nudge should fail")
+	}
+}
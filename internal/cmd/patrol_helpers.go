@@ -182,6 +182,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	if err := cmdPin.Run(); err != nil {
 		return patrolID, fmt.Errorf("created wisp %s but failed to hook", patrolID)
 	}
+	recordMoleculeVars(patrolID, cfg.PatrolMolName, cfg.ExtraVars)
 
 	return patrolID, nil
 }
@@ -407,3 +407,42 @@ func splitFirstEquals(s string) []string {
 	}
 	return []string{s[:idx], s[idx+1:]}
 }
+
+func TestBuildRefineryPatrolVars_BatchSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigDir := filepath.Join(tmpDir, "testrig")
+	if err := os.MkdirAll(rigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeRigConfig := func(batchSize int) {
+		t.Helper()
+		rigConfig := map[string]interface{}{
+			"type": "rig", "version": 1, "name": "testrig",
+			"merge_queue": map[string]interface{}{"batch_size": batchSize},
+		}
+		data, _ := json.Marshal(rigConfig)
+		if err := os.WriteFile(filepath.Join(rigDir, "config.json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batchVar := func() string {
+		for _, v := range buildRefineryPatrolVars(RoleContext{TownRoot: tmpDir, Rig: "testrig"}) {
+			if parts := splitFirstEquals(v); len(parts) == 2 && parts[0] == "batch_size" {
+				return parts[1]
+			}
+		}
+		return ""
+	}
+
+	writeRigConfig(4)
+	if got := batchVar(); got != "4" {
+		t.Errorf("batch_size = %q, want 4", got)
+	}
+
+	// Batching disabled: the formula default (1) applies.
+	writeRigConfig(1)
+	if got := batchVar(); got != "" {
+		t.Errorf("batch_size = %q, want it omitted", got)
+	}
+}
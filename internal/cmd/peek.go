@@ -34,11 +34,15 @@ Supports both polecats and crew workers:
   - Polecats: rig/name format (e.g., greenplace/furiosa)
   - Crew: rig/crew/name format (e.g., beads/crew/dave)
 
+Prefix the address with a machine name from settings/machines.json to
+peek at an agent in a town on another machine (e.g., buildbox:gastown/rictus).
+
 Examples:
   gt peek greenplace/furiosa         # Polecat: last 100 lines (default)
   gt peek greenplace/furiosa 50      # Polecat: last 50 lines
   gt peek beads/crew/dave            # Crew: last 100 lines
-  gt peek beads/crew/dave -n 200     # Crew: last 200 lines`,
+  gt peek beads/crew/dave -n 200     # Crew: last 200 lines
+  gt peek buildbox:gastown/rictus    # Polecat on a remote machine`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runPeek,
 }
@@ -56,6 +60,24 @@ func runPeek(cmd *cobra.Command, args []string) error {
 		lines = n
 	}
 
+	// Machine-qualified address: capture the pane on the remote machine
+	remote, rest, err := resolveRemoteTarget(address)
+	if err != nil {
+		return err
+	}
+	if remote != nil {
+		sessionID, err := remoteSessionName(remote, rest)
+		if err != nil {
+			return err
+		}
+		output, err := remote.Conn.TmuxCapturePane(sessionID, lines)
+		if err != nil {
+			return fmt.Errorf("capturing output on %s: %w", remote.Machine.Name, err)
+		}
+		fmt.Print(output)
+		return nil
+	}
+
 	rigName, polecatName, err := parseAddress(address)
 	if err != nil {
 		if !strings.Contains(address, "/") {
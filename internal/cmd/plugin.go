@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -41,13 +42,22 @@ GATE TYPES:
   cooldown    Run if enough time has passed (e.g., 1h)
   cron        Run on a schedule (e.g., "0 9 * * *")
   condition   Run if a check command returns exit 0
-  event       Run on events (e.g., startup)
+  event       Run on events (e.g., startup, or "merged,merge_failed")
   manual      Never auto-run, trigger explicitly
 
+The daemon evaluates gates every minute and dispatches due plugins to dogs
+(disable with patrols.plugins in mayor/daemon.json). A duration on a
+condition or event gate is the minimum time between runs.
+
+Plugins can be shared between towns with 'gt plugin install' (from a
+directory, git repository or archive); installs are recorded in
+plugins.lock.json for 'gt plugin upgrade' and 'gt plugin remove'.
+
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
-  gt plugin list --json             # JSON output`,
+  gt plugin list --json             # JSON output
+  gt plugin install <git-url>#plugins/github-sheriff`,
 	RunE: requireSubcommand,
 }
 
@@ -350,6 +360,38 @@ func outputPluginShowText(p *plugin.Plugin) error {
 		}
 	}
 
+	// Requirements
+	if p.Requires != nil {
+		fmt.Println()
+		fmt.Printf("%s\n", style.Bold.Render("Requires:"))
+		if len(p.Requires.Binaries) > 0 {
+			fmt.Printf("  Binaries: %s\n", strings.Join(p.Requires.Binaries, ", "))
+		}
+		if len(p.Requires.Env) > 0 {
+			fmt.Printf("  Env: %s\n", strings.Join(p.Requires.Env, ", "))
+		}
+		if len(p.Requires.Permissions) > 0 {
+			fmt.Printf("  Permissions: %s\n", strings.Join(p.Requires.Permissions, ", "))
+		}
+	}
+
+	// Install record
+	if lock, err := plugin.LoadLockfile(filepath.Dir(p.Path)); err == nil {
+		if entry, ok := lock.Plugins[p.Name]; ok {
+			fmt.Println()
+			fmt.Printf("%s\n", style.Bold.Render("Installed:"))
+			fmt.Printf("  Source: %s\n", entry.Source)
+			if entry.Commit != "" {
+				fmt.Printf("  Commit: %s\n", entry.Commit)
+			}
+			fmt.Printf("  Hash: %s\n", entry.Hash)
+			fmt.Printf("  At: %s\n", entry.InstalledAt.Local().Format("2006-01-02 15:04"))
+			if hash, err := plugin.HashDir(p.Path); err == nil && hash != entry.Hash {
+				fmt.Printf("  %s\n", style.Warning.Render("⚠ modified since install"))
+			}
+		}
+	}
+
 	// Instructions preview
 	if p.Instructions != "" {
 		fmt.Println()
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/plugin"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Plugin install flags
+var (
+	pluginInstallRig   string
+	pluginInstallForce bool
+	pluginInstallJSON  bool
+	pluginUpgradeAll   bool
+	pluginUpgradeFrom  string
+)
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <path|git-url|archive>",
+	Short: "Install a plugin from a directory, git repository or archive",
+	Long: `Install a plugin into the town (or a rig with --rig).
+
+The source is one of:
+  <dir>                          A plugin directory (contains plugin.md)
+  <file>.tar.gz|.tgz|.tar|.zip   An archive holding one plugin directory
+  <git-url>[#<subdir>]           A git repository; #subdir names the plugin
+                                 directory inside it. Use git+<url> for
+                                 URLs that don't look like git (git+file://)
+
+The plugin's [requires] manifest is checked before anything is written:
+binaries must be on PATH, env vars must be set, and each permission (a
+command the plugin runs) is evaluated against mayor/policy.json as a command
+run by a dog. --force installs despite unmet requirements, but never over a
+policy deny.
+
+The source, version and content hash are recorded in plugins.lock.json in
+the plugins directory, for 'gt plugin upgrade' and 'gt plugin remove'.
+
+Examples:
+  gt plugin install ~/src/gastown/plugins/github-sheriff
+  gt plugin install https://github.com/steveyegge/gastown.git#plugins/github-sheriff
+  gt plugin install ./sheriff.tar.gz --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPluginInstall,
+}
+
+var pluginUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name...]",
+	Short: "Upgrade installed plugins from their recorded source",
+	Long: `Re-fetch installed plugins from the source in plugins.lock.json and replace
+them when their content changed.
+
+An upgrade to a lower version, or over local edits to the installed copy,
+needs --force. Use --from to switch a plugin to a new source.
+
+Examples:
+  gt plugin upgrade github-sheriff
+  gt plugin upgrade --all
+  gt plugin upgrade github-sheriff --from ~/src/sheriff`,
+	RunE: runPluginUpgrade,
+}
+
+var pluginRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an installed plugin",
+	Long: `Remove a plugin installed with 'gt plugin install' and its lockfile entry.
+
+Plugins added to a plugins directory by hand are not touched. A plugin edited
+since it was installed needs --force.
+
+Examples:
+  gt plugin remove github-sheriff
+  gt plugin remove github-sheriff --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPluginRemove,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{pluginInstallCmd, pluginUpgradeCmd, pluginRemoveCmd} {
+		c.Flags().StringVar(&pluginInstallRig, "rig", "", "Use the rig's plugins directory instead of the town's")
+		c.Flags().BoolVar(&pluginInstallForce, "force", false, "Override requirement, downgrade and local-edit checks (not policy denies)")
+		pluginCmd.AddCommand(c)
+	}
+	pluginInstallCmd.Flags().BoolVar(&pluginInstallJSON, "json", false, "Output as JSON")
+	pluginUpgradeCmd.Flags().BoolVar(&pluginInstallJSON, "json", false, "Output as JSON")
+	pluginUpgradeCmd.Flags().BoolVar(&pluginUpgradeAll, "all", false, "Upgrade every installed plugin")
+	pluginUpgradeCmd.Flags().StringVar(&pluginUpgradeFrom, "from", "", "Upgrade from this source instead of the recorded one")
+}
+
+// pluginInstaller returns an installer for the town or --rig plugins directory.
+func pluginInstaller() (*plugin.Installer, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	root := townRoot
+	if pluginInstallRig != "" {
+		rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading rigs config: %w", err)
+		}
+		if _, ok := rigsConfig.Rigs[pluginInstallRig]; !ok {
+			return nil, fmt.Errorf("rig %q not found", pluginInstallRig)
+		}
+		root = filepath.Join(townRoot, pluginInstallRig)
+	}
+
+	evaluator, err := policy.LoadOrDefault(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", style.Warning.Render("⚠"), err)
+	}
+
+	return &plugin.Installer{
+		Dir:    filepath.Join(root, "plugins"),
+		Policy: evaluator,
+		Repo:   policy.NormalizeRepo(root),
+		Force:  pluginInstallForce,
+	}, nil
+}
+
+func runPluginInstall(cmd *cobra.Command, args []string) error {
+	inst, err := pluginInstaller()
+	if err != nil {
+		return err
+	}
+
+	res, err := inst.Install(args[0])
+	if err != nil {
+		return pluginRequirementsFailure(err)
+	}
+
+	if pluginInstallJSON {
+		return printPluginJSON(res)
+	}
+	printRequirementChecks(res.Checks)
+	fmt.Printf("%s Installed %s v%d %s\n", style.Success.Render("✓"), res.Plugin.Name, res.Entry.Version,
+		style.Dim.Render("("+res.Entry.Hash[:19]+")"))
+	fmt.Printf("  %s\n", style.Dim.Render(res.Plugin.Path))
+	return nil
+}
+
+func runPluginUpgrade(cmd *cobra.Command, args []string) error {
+	inst, err := pluginInstaller()
+	if err != nil {
+		return err
+	}
+
+	names := args
+	if pluginUpgradeAll {
+		if len(args) > 0 {
+			return fmt.Errorf("--all and plugin names are mutually exclusive")
+		}
+		lock, err := plugin.LoadLockfile(inst.Dir)
+		if err != nil {
+			return err
+		}
+		names = lock.Names()
+		if len(names) == 0 {
+			fmt.Printf("%s No installed plugins in %s\n", style.Dim.Render("○"), inst.Dir)
+			return nil
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("name a plugin to upgrade, or use --all")
+	}
+	if pluginUpgradeFrom != "" && len(names) != 1 {
+		return fmt.Errorf("--from applies to a single plugin")
+	}
+
+	var results []*plugin.InstallResult
+	var failed int
+	for _, name := range names {
+		res, err := inst.Upgrade(name, pluginUpgradeFrom)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.Warning.Render("⚠"), name, err)
+			continue
+		}
+		results = append(results, res)
+		if pluginInstallJSON {
+			continue
+		}
+		if res.Unchanged {
+			fmt.Printf("%s %s is up to date (v%d)\n", style.Dim.Render("○"), name, res.Entry.Version)
+			continue
+		}
+		printRequirementChecks(res.Checks)
+		fmt.Printf("%s Upgraded %s v%d → v%d\n", style.Success.Render("✓"), name, res.Previous.Version, res.Entry.Version)
+	}
+
+	if pluginInstallJSON {
+		if err := printPluginJSON(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runPluginRemove(cmd *cobra.Command, args []string) error {
+	inst, err := pluginInstaller()
+	if err != nil {
+		return err
+	}
+
+	entry, err := inst.Remove(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed %s v%d %s\n", style.Success.Render("✓"), args[0], entry.Version,
+		style.Dim.Render("(from "+entry.Source+")"))
+	return nil
+}
+
+// printRequirementChecks shows the manifest checks of an install.
+func printRequirementChecks(checks []plugin.RequirementCheck) {
+	for _, c := range checks {
+		mark := style.Success.Render("✓")
+		if !c.OK {
+			mark = style.Warning.Render("⚠")
+		}
+		fmt.Printf("  %s %s %s %s\n", mark, c.Kind, c.Name, style.Dim.Render(c.Detail))
+	}
+}
+
+// pluginRequirementsFailure prints unmet requirements before returning the
+// install error, with a hint when --force would help.
+func pluginRequirementsFailure(err error) error {
+	var reqErr *plugin.RequirementsError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Plugin %s has unmet requirements:\n", style.Error.Render("✗"), reqErr.Plugin)
+	for _, c := range reqErr.Failed {
+		fmt.Fprintf(os.Stderr, "  %s %s: %s\n", c.Kind, c.Name, c.Detail)
+	}
+	if !reqErr.Denied() {
+		fmt.Fprintf(os.Stderr, "  Use --force to install anyway\n")
+	}
+	return NewSilentExit(1)
+}
+
+func printPluginJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
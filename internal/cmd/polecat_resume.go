@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var polecatResumeMax int
+
+var polecatResumeCmd = &cobra.Command{
+	Use:   "resume <rig>/<polecat>",
+	Short: "Restart a crashed polecat session from its checkpoint",
+	Long: `Restart a crashed polecat's session in its existing worktree.
+
+The new session is told what the old one was doing, from the polecat's
+checkpoint (.polecat-checkpoint.json) or, if there is none, from the
+worktree's git state. On the first resume of a bead the agent also picks up
+its previous conversation (the preset's resume or continue flag), if it
+supports that; later resumes start a fresh conversation.
+
+Each resume is charged to the hooked bead. Once a bead has been resumed
+--max times the command fails and the crash should be escalated instead.
+The daemon and Witness call this automatically when a polecat with hooked
+work loses its session.
+
+Examples:
+  gt polecat resume greenplace/Toast
+  gt polecat resume greenplace/Toast --max 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatResume,
+}
+
+func init() {
+	polecatResumeCmd.Flags().IntVar(&polecatResumeMax, "max", checkpoint.DefaultMaxResumes, "Resumes allowed per bead before giving up")
+	polecatCmd.AddCommand(polecatResumeCmd)
+}
+
+func runPolecatResume(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := mgr.Get(polecatName)
+	if err != nil {
+		return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+	}
+
+	sessMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
+	if running, _ := sessMgr.IsRunning(polecatName); running {
+		return fmt.Errorf("%s/%s still has a running session", rigName, polecatName)
+	}
+
+	hookBead := p.Issue
+	if _, fields, err := beads.New(r.Path).GetAgentBead(polecatBeadIDForRig(r, rigName, polecatName)); err == nil && fields != nil && fields.HookBead != "" {
+		hookBead = fields.HookBead
+	}
+
+	townRoot := filepath.Dir(r.Path)
+	plan, err := checkpoint.PlanResume(townRoot, p.ClonePath, polecatName, hookBead, polecatResumeMax)
+	if err != nil {
+		return fmt.Errorf("not resuming %s/%s: %w", rigName, polecatName, err)
+	}
+
+	configDir, err := resolveSpawnAccount(townRoot, "")
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+	if err := sessMgr.Start(polecatName, polecat.SessionStartOptions{
+		RuntimeConfigDir: configDir,
+		Resume:           plan,
+	}); err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+
+	sessionName := sessMgr.SessionName(polecatName)
+	agent := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+	_ = events.LogFeed(events.TypeSessionResume, agent,
+		events.SessionResumePayload(sessionName, agent, plan.Bead, "gt polecat resume", plan.Attempt, plan.Max))
+
+	fmt.Printf("%s Resumed %s/%s on %s (attempt %d/%d)\n",
+		style.SuccessPrefix, rigName, polecatName, plan.Bead, plan.Attempt, plan.Max)
+	return nil
+}
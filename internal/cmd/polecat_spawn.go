@@ -15,9 +15,10 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -30,6 +31,7 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 	DoltBranch  string // Dolt branch for write isolation (empty if not created)
 	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
+	TraceParent string // W3C traceparent the session continues (set by the caller before StartSession)
 
 	// Internal fields for deferred session start
 	account string
@@ -82,7 +84,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 
 	// Pre-spawn Dolt health check (gt-94llt7): verify Dolt is reachable before
@@ -198,7 +200,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	doltBranch := doltserver.PolecatBranchName(polecatName)
 
 	// Get session manager for session name (session start is deferred)
-	polecatSessMgr := polecat.NewSessionManager(t, r)
+	polecatSessMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
 	sessionName := polecatSessMgr.SessionName(polecatName)
 
 	fmt.Printf("%s Polecat %s spawned (session start deferred)\n", style.Bold.Render("✓"), polecatName)
@@ -254,20 +256,20 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	}
 
 	// Resolve account
-	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, s.account)
+	claudeConfigDir, err := resolveSpawnAccount(townRoot, s.account)
 	if err != nil {
 		return "", fmt.Errorf("resolving account: %w", err)
 	}
 
 	// Start session
-	t := tmux.NewTmux()
-	polecatSessMgr := polecat.NewSessionManager(t, r)
+	t := session.NewBackend(townRoot)
+	polecatSessMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
 
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
 		DoltBranch:       s.DoltBranch,
+		TraceParent:      s.TraceParent,
 	}
 	if s.agent != "" {
 		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
@@ -283,7 +285,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	// Wait for runtime to be fully ready before returning.
 	spawnTownRoot := filepath.Dir(r.Path)
 	runtimeConfig := config.ResolveRoleAgentConfig("polecat", spawnTownRoot, r.Path)
-	if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
+	if err := session.WaitForRuntimeReady(t, s.SessionName, runtimeConfig, 30*time.Second); err != nil {
 		style.PrintWarning("runtime may not be fully ready: %v", err)
 	}
 
@@ -413,3 +415,25 @@ func verifyWorktreeExists(clonePath string) error {
 
 	return nil
 }
+
+// resolveSpawnAccount returns the CLAUDE_CONFIG_DIR for a new polecat
+// session. An explicit account (GT_ACCOUNT or --account) wins. Otherwise,
+// with "placement": "headroom" in accounts.json, the session goes to the
+// account with the most headroom in its usage window; if that can't be
+// worked out, the default account is used.
+func resolveSpawnAccount(townRoot, accountFlag string) (string, error) {
+	accountsPath := constants.MayorAccountsPath(townRoot)
+	if accountFlag == "" && os.Getenv("GT_ACCOUNT") == "" {
+		if acctCfg, err := config.LoadAccountsConfig(accountsPath); err == nil &&
+			acctCfg.Placement == config.AccountPlacementHeadroom && len(acctCfg.Accounts) > 0 {
+			handle, err := quota.NewManager(townRoot).PlaceSession(acctCfg, nil, time.Now())
+			if err != nil {
+				style.PrintWarning("headroom placement failed, using default account: %v", err)
+			} else if handle != "" {
+				accountFlag = handle
+			}
+		}
+	}
+	configDir, _, err := config.ResolveAccountConfigDir(accountsPath, accountFlag)
+	return configDir, err
+}
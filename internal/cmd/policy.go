@@ -9,6 +9,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/runlog"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -20,7 +22,8 @@ var policyCmd = &cobra.Command{
 
 Examples:
   gt policy eval --cmd "git push origin main"
-  gt policy eval --agent witness --repo /path/to/repo --cmd "gt rig boot myrig" --json`,
+  gt policy eval --agent witness --repo /path/to/repo --cmd "gt rig boot myrig" --json
+  gt policy test candidate-policy.json --since 168h`,
 	RunE: requireSubcommand,
 }
 
@@ -30,6 +33,29 @@ var policyEvalCmd = &cobra.Command{
 	RunE:  runPolicyEval,
 }
 
+var policyTestCmd = &cobra.Command{
+	Use:   "test <policy.json>",
+	Short: "Replay the audit trail against a candidate policy",
+	Long: `Replay past policy decisions from the run log against a candidate policy
+document and report which decisions would change.
+
+Decisions are replayed in time order. Rate-limited rules count the replayed
+history, so "max 5 per hour" sees the same traffic it would have seen.
+
+Examples:
+  gt policy test candidate.json
+  gt policy test candidate.json --since 168h
+  gt policy test candidate.json --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicyTest,
+}
+
+var (
+	policyTestSince string
+	policyTestAll   bool
+	policyTestJSON  bool
+)
+
 var (
 	policyEvalAgent string
 	policyEvalRepo  string
@@ -43,7 +69,12 @@ func init() {
 	policyEvalCmd.Flags().StringVar(&policyEvalCmdIn, "cmd", "", "Command to evaluate")
 	policyEvalCmd.Flags().BoolVar(&policyEvalJSON, "json", false, "Output JSON")
 
+	policyTestCmd.Flags().StringVar(&policyTestSince, "since", "", "Only replay decisions newer than this (e.g. 24h, 168h)")
+	policyTestCmd.Flags().BoolVar(&policyTestAll, "all", false, "List unchanged decisions too")
+	policyTestCmd.Flags().BoolVar(&policyTestJSON, "json", false, "Output JSON")
+
 	policyCmd.AddCommand(policyEvalCmd)
+	policyCmd.AddCommand(policyTestCmd)
 	rootCmd.AddCommand(policyCmd)
 }
 
@@ -65,15 +96,21 @@ func runPolicyEval(cmd *cobra.Command, args []string) error {
 
 	evaluator := policy.NewDefaultEvaluator()
 	if townRoot != "" {
-		evaluator = policy.LoadOrDefault(townRoot)
+		loaded, err := policy.LoadOrDefault(townRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", style.Warning.Render("⚠"), err)
+		}
+		evaluator = loaded.WithRateStore(policy.NewFileRateStore(townRoot))
 	}
 
 	result := evaluator.Evaluate(policy.EvalRequest{
 		Agent:       strings.TrimSpace(policyEvalAgent),
 		Repo:        repo,
 		Command:     command,
+		Args:        strings.Fields(command),
 		RequestedBy: "cli",
 		Timestamp:   time.Now().UTC(),
+		DryRun:      true,
 	})
 
 	if policyEvalJSON {
@@ -90,3 +127,111 @@ func runPolicyEval(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// PolicyTestOutput is the JSON output of gt policy test.
+type PolicyTestOutput struct {
+	Policy   string                `json:"policy"`
+	Replayed int                   `json:"replayed"`
+	Changed  int                   `json:"changed"`
+	Results  []policy.ReplayResult `json:"results"`
+}
+
+func runPolicyTest(cmd *cobra.Command, args []string) error {
+	doc, err := policy.LoadDocument(args[0])
+	if err != nil {
+		return fmt.Errorf("loading candidate policy: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if policyTestSince != "" {
+		d, err := time.ParseDuration(policyTestSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	store := runlog.NewStore(townRoot)
+	events, err := store.ReadEvents("policy_evaluated", since)
+	if err != nil {
+		return fmt.Errorf("reading run log: %w", err)
+	}
+	past := make([]policy.PastDecision, 0, len(events))
+	for _, evt := range events {
+		if d, ok := pastDecisionFromEvent(evt); ok {
+			past = append(past, d)
+		}
+	}
+
+	results := policy.Replay(doc, past)
+	out := PolicyTestOutput{Policy: args[0], Replayed: len(results), Results: []policy.ReplayResult{}}
+	for _, r := range results {
+		if r.Changed() {
+			out.Changed++
+		}
+		if r.Changed() || policyTestAll {
+			out.Results = append(out.Results, r)
+		}
+	}
+
+	if policyTestJSON {
+		return json.NewEncoder(os.Stdout).Encode(out)
+	}
+
+	fmt.Printf("Replayed %d decision(s) from %s against %s\n", out.Replayed, store.Path(), args[0])
+	if out.Changed == 0 {
+		fmt.Printf("%s No decisions would change\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("%s %d decision(s) would change\n", style.Warning.Render("⚠"), out.Changed)
+	}
+	for _, r := range out.Results {
+		marker := " "
+		if r.Changed() {
+			marker = style.Warning.Render("!")
+		}
+		decision := string(r.Decision)
+		if r.Changed() {
+			decision += " → " + string(r.New.Decision)
+		}
+		fmt.Printf("%s %s  %-12s %s\n", marker, r.At.Local().Format("2006-01-02 15:04"), r.Agent, r.Command)
+		line := "    " + decision
+		if r.New.RuleID != "" {
+			line += " (rule " + r.New.RuleID + ")"
+		}
+		if r.Changed() && r.New.Reason != "" {
+			line += ": " + r.New.Reason
+		}
+		fmt.Println(style.Dim.Render(line))
+	}
+	return nil
+}
+
+// pastDecisionFromEvent extracts a replayable decision from a
+// policy_evaluated run log event. Events without a command are skipped.
+func pastDecisionFromEvent(evt runlog.Event) (policy.PastDecision, bool) {
+	command, _ := evt.Payload["command"].(string)
+	if strings.TrimSpace(command) == "" || evt.PolicyDecision == "" {
+		return policy.PastDecision{}, false
+	}
+	d := policy.PastDecision{
+		RunID:    evt.RunID,
+		At:       evt.Timestamp,
+		Agent:    evt.AgentID,
+		Command:  command,
+		Decision: policy.Decision(evt.PolicyDecision),
+	}
+	d.Repo, _ = evt.Payload["repo"].(string)
+	if raw, ok := evt.Payload["args"].([]interface{}); ok {
+		for _, a := range raw {
+			if s, ok := a.(string); ok {
+				d.Args = append(d.Args, s)
+			}
+		}
+	}
+	return d, true
+}
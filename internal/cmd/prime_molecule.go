@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -334,6 +335,13 @@ func buildRefineryPatrolVars(ctx RoleContext) []string {
 	}
 	vars = append(vars, fmt.Sprintf("target_branch=%s", defaultBranch))
 
+	// Batching lives in the rig's config.json merge_queue section, where the
+	// Engineer (and so gt mq batch) reads it.
+	eng := refinery.NewEngineer(&rig.Rig{Name: ctx.Rig, Path: rigPath})
+	if err := eng.LoadConfig(); err == nil && eng.Config().BatchSize > 1 {
+		vars = append(vars, fmt.Sprintf("batch_size=%d", eng.Config().BatchSize))
+	}
+
 	// MQ-specific vars require settings/config.json with a merge_queue section
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 	settings, sErr := config.LoadRigSettings(settingsPath)
@@ -12,8 +12,8 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	ttmux "github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -54,6 +54,12 @@ var quotaStatusCmd = &cobra.Command{
 Displays which accounts are available, rate-limited, or in cooldown,
 along with timestamps for limit detection and estimated reset times.
 
+For each account it also shows the tokens used in the rolling 5-hour window
+(from Claude Code transcripts in the account's config dir), the burn rate
+over the last 30 minutes, the window allowance (window_tokens in
+accounts.json, or learned from the last rate limit) and the projected time
+the account runs out at the current rate.
+
 Examples:
   gt quota status           # Text output
   gt quota status --json    # JSON output`,
@@ -69,6 +75,12 @@ type QuotaStatusItem struct {
 	ResetsAt  string `json:"resets_at,omitempty"`
 	LastUsed  string `json:"last_used,omitempty"`
 	IsDefault bool   `json:"is_default"`
+
+	WindowTokens        int     `json:"window_tokens"`
+	BurnRatePerHour     float64 `json:"burn_rate_per_hour"`
+	LimitTokens         int     `json:"limit_tokens,omitempty"`
+	LimitSource         string  `json:"limit_source,omitempty"`
+	ProjectedExhaustion string  `json:"projected_exhaustion,omitempty"` // RFC3339
 }
 
 func runQuotaStatus(cmd *cobra.Command, args []string) error {
@@ -102,13 +114,32 @@ func runQuotaStatus(cmd *cobra.Command, args []string) error {
 	// Ensure all accounts are tracked
 	mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
 
+	usages := usageByHandle(quota.MeasureUsage(acctCfg, state, nil, time.Now()))
+
 	if quotaJSON {
-		return printQuotaStatusJSON(acctCfg, state)
+		return printQuotaStatusJSON(acctCfg, state, usages)
+	}
+	return printQuotaStatusText(acctCfg, state, usages)
+}
+
+func usageByHandle(usages []quota.AccountUsage) map[string]quota.AccountUsage {
+	byHandle := make(map[string]quota.AccountUsage, len(usages))
+	for _, u := range usages {
+		byHandle[u.Handle] = u
 	}
-	return printQuotaStatusText(acctCfg, state)
+	return byHandle
 }
 
-func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaState) error {
+// windowTokensByAccount returns each account's usage in the rolling window.
+func windowTokensByAccount(acctCfg *config.AccountsConfig, state *config.QuotaState) map[string]int {
+	tokens := make(map[string]int)
+	for _, u := range quota.MeasureUsage(acctCfg, state, nil, time.Now()) {
+		tokens[u.Handle] = u.WindowTokens
+	}
+	return tokens
+}
+
+func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaState, usages map[string]quota.AccountUsage) error {
 	var items []QuotaStatusItem
 	for _, handle := range slices.Sorted(maps.Keys(acctCfg.Accounts)) {
 		acct := acctCfg.Accounts[handle]
@@ -117,22 +148,31 @@ func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaSta
 		if status == "" {
 			status = string(config.QuotaStatusAvailable)
 		}
-		items = append(items, QuotaStatusItem{
-			Handle:    handle,
-			Email:     acct.Email,
-			Status:    status,
-			LimitedAt: qs.LimitedAt,
-			ResetsAt:  qs.ResetsAt,
-			LastUsed:  qs.LastUsed,
-			IsDefault: handle == acctCfg.Default,
-		})
+		u := usages[handle]
+		item := QuotaStatusItem{
+			Handle:          handle,
+			Email:           acct.Email,
+			Status:          status,
+			LimitedAt:       qs.LimitedAt,
+			ResetsAt:        qs.ResetsAt,
+			LastUsed:        qs.LastUsed,
+			IsDefault:       handle == acctCfg.Default,
+			WindowTokens:    u.WindowTokens,
+			BurnRatePerHour: u.BurnRate,
+			LimitTokens:     u.LimitTokens,
+			LimitSource:     u.LimitSource,
+		}
+		if u.ProjectedExhaustion != nil {
+			item.ProjectedExhaustion = u.ProjectedExhaustion.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
 	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(items)
 }
 
-func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaState) error {
+func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaState, usages map[string]quota.AccountUsage) error {
 	available := 0
 	limited := 0
 
@@ -178,6 +218,9 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 		}
 
 		fmt.Printf(" %s %-12s %s%s\n", marker, handle, badge, email)
+		if line := formatAccountUsage(usages[handle], time.Now()); line != "" {
+			fmt.Printf("   %-12s %s\n", "", style.Dim.Render(line))
+		}
 	}
 
 	fmt.Println()
@@ -187,6 +230,49 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 	return nil
 }
 
+// formatAccountUsage summarizes an account's window usage and projection:
+// "1.2M/5.0M tokens in 5h window, 400k/h, runs out ~15:40".
+func formatAccountUsage(u quota.AccountUsage, now time.Time) string {
+	if u.WindowTokens == 0 && u.LimitTokens == 0 {
+		return ""
+	}
+	used := formatTokenCount(u.WindowTokens)
+	if u.LimitTokens > 0 {
+		used += "/" + formatTokenCount(u.LimitTokens)
+		if u.LimitSource == "learned" {
+			used += " (learned)"
+		}
+	}
+	line := fmt.Sprintf("%s tokens in %s window", used, formatWindow(quota.DefaultUsageWindow))
+	if u.BurnRate > 0 {
+		line += fmt.Sprintf(", %s/h", formatTokenCount(int(u.BurnRate)))
+	}
+	switch {
+	case u.ProjectedExhaustion == nil:
+	case !u.ProjectedExhaustion.After(now):
+		line += ", at limit"
+	default:
+		line += fmt.Sprintf(", runs out ~%s (in %s)", u.ProjectedExhaustion.Local().Format("15:04"),
+			u.ProjectedExhaustion.Sub(now).Round(time.Minute))
+	}
+	return line
+}
+
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func formatWindow(d time.Duration) string {
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
+
 // Scan command flags
 var (
 	scanUpdate bool
@@ -221,7 +307,7 @@ func runQuotaScan(cmd *cobra.Command, args []string) error {
 	// acctCfg can be nil if no accounts configured — scan still works
 
 	// Create scanner
-	t := ttmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	scanner, err := quota.NewScanner(t, nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
@@ -254,15 +340,25 @@ func updateQuotaState(townRoot string, results []quota.ScanResult, acctCfg *conf
 		}
 		mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
 
+		// Usage at the moment an account first hits its limit is what we
+		// learn its allowance from.
+		var usage map[string]int
 		now := time.Now().UTC().Format(time.RFC3339)
 		for _, r := range results {
 			if r.RateLimited && r.AccountHandle != "" {
 				existing := state.Accounts[r.AccountHandle]
 				state.Accounts[r.AccountHandle] = config.AccountQuotaState{
-					Status:    config.QuotaStatusLimited,
-					LimitedAt: now,
-					ResetsAt:  r.ResetsAt,
-					LastUsed:  existing.LastUsed,
+					Status:      config.QuotaStatusLimited,
+					LimitedAt:   now,
+					ResetsAt:    r.ResetsAt,
+					LastUsed:    existing.LastUsed,
+					LimitTokens: existing.LimitTokens,
+				}
+				if existing.Status != config.QuotaStatusLimited {
+					if usage == nil {
+						usage = windowTokensByAccount(acctCfg, state)
+					}
+					quota.LearnLimit(state, r.AccountHandle, usage[r.AccountHandle])
 				}
 			}
 		}
@@ -315,7 +411,9 @@ func printScanText(results []quota.ScanResult) error {
 
 // Rotate command flags
 var (
-	rotateDryRun bool
+	rotateDryRun    bool
+	rotateProactive bool
+	rotateHorizon   time.Duration
 )
 
 var quotaRotateCmd = &cobra.Command{
@@ -332,10 +430,16 @@ The rotation process:
   3. Updates tmux session environment with new CLAUDE_CONFIG_DIR
   4. Restarts blocked sessions via respawn-pane
 
+With --proactive, sessions on accounts projected to hit their limit within
+--horizon (see 'gt quota status') are also moved, to the accounts with the
+most headroom, before they stall. Sessions resume where they left off.
+
 Examples:
-  gt quota rotate              # Rotate all blocked sessions
-  gt quota rotate --dry-run    # Show plan without executing
-  gt quota rotate --json       # JSON output`,
+  gt quota rotate                          # Rotate all blocked sessions
+  gt quota rotate --proactive              # Also move sessions about to be blocked
+  gt quota rotate --proactive --horizon 30m
+  gt quota rotate --dry-run                # Show plan without executing
+  gt quota rotate --json                   # JSON output`,
 	RunE: runQuotaRotate,
 }
 
@@ -356,7 +460,7 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create scanner and plan rotation
-	t := ttmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	scanner, err := quota.NewScanner(t, nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
@@ -368,14 +472,24 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("planning rotation: %w", err)
 	}
 
-	if len(plan.LimitedSessions) == 0 {
-		fmt.Printf(" %s No rate-limited sessions detected\n", style.SuccessPrefix)
+	if rotateProactive {
+		if err := planProactiveRotation(plan, mgr, acctCfg); err != nil {
+			return fmt.Errorf("planning proactive rotation: %w", err)
+		}
+	}
+
+	if len(plan.LimitedSessions) == 0 && len(plan.AtRiskSessions) == 0 {
+		if rotateProactive {
+			fmt.Printf(" %s No rate-limited or at-risk sessions detected\n", style.SuccessPrefix)
+		} else {
+			fmt.Printf(" %s No rate-limited sessions detected\n", style.SuccessPrefix)
+		}
 		return nil
 	}
 
 	if len(plan.Assignments) == 0 {
-		fmt.Printf(" %s %d sessions rate-limited but no available accounts to rotate to\n",
-			style.WarningPrefix, len(plan.LimitedSessions))
+		fmt.Printf(" %s %d sessions rate-limited or at risk but no available accounts to rotate to\n",
+			style.WarningPrefix, len(plan.LimitedSessions)+len(plan.AtRiskSessions))
 		return nil
 	}
 
@@ -388,23 +502,31 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 		for _, session := range sortedSessions {
 			newAccount := plan.Assignments[session]
-			var oldAccount string
+			var oldAccount, reason string
 			for _, r := range plan.LimitedSessions {
 				if r.Session == session {
 					oldAccount = r.AccountHandle
 					break
 				}
 			}
+			for _, r := range plan.AtRiskSessions {
+				if r.Session == session {
+					oldAccount = r.AccountHandle
+					reason = style.Dim.Render(" (before limit)")
+					break
+				}
+			}
 			if oldAccount == "" {
 				oldAccount = "(unknown)"
 			}
-			fmt.Printf(" %s %-25s %s → %s\n",
+			fmt.Printf(" %s %-25s %s → %s%s\n",
 				style.ArrowPrefix, session,
 				style.Dim.Render(oldAccount),
 				style.Success.Render(newAccount),
+				reason,
 			)
 		}
-		unassigned := len(plan.LimitedSessions) - len(plan.Assignments)
+		unassigned := len(plan.LimitedSessions) + len(plan.AtRiskSessions) - len(plan.Assignments)
 		if unassigned > 0 {
 			fmt.Printf("\n %s %d sessions cannot be rotated (not enough available accounts)\n",
 				style.WarningPrefix, unassigned)
@@ -424,7 +546,11 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	if !quotaJSON {
 		fmt.Println()
 	}
-	rotator := quota.NewRotator(t, t, mgr, acctCfg, buildRestartCommand, quotaLogger{},
+	exec, ok := t.(quota.TmuxExecutor)
+	if !ok {
+		return fmt.Errorf("session backend %q does not support account rotation", session.BackendName(townRoot))
+	}
+	rotator := quota.NewRotator(t, exec, mgr, acctCfg, buildRestartCommand, quotaLogger{},
 		townRoot, "" /* agentName: default "claude" */, symlinkSessionToConfigDir)
 	results := rotator.Execute(plan, sortedSessions)
 
@@ -454,6 +580,29 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// planProactiveRotation adds sessions on accounts projected to run out
+// within --horizon to the plan. Accounts the scan just found limited are
+// not rotation targets.
+func planProactiveRotation(plan *quota.RotatePlan, mgr *quota.Manager, acctCfg *config.AccountsConfig) error {
+	state, err := mgr.Load()
+	if err != nil {
+		return fmt.Errorf("loading quota state: %w", err)
+	}
+	mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
+	for _, r := range plan.LimitedSessions {
+		if r.AccountHandle != "" {
+			qs := state.Accounts[r.AccountHandle]
+			qs.Status = config.QuotaStatusLimited
+			qs.ResetsAt = r.ResetsAt
+			state.Accounts[r.AccountHandle] = qs
+		}
+	}
+
+	now := time.Now()
+	plan.PlanProactive(quota.MeasureUsage(acctCfg, state, nil, now), state, now, rotateHorizon)
+	return nil
+}
+
 var quotaClearCmd = &cobra.Command{
 	Use:   "clear [handle...]",
 	Short: "Mark account(s) as available again",
@@ -514,6 +663,8 @@ func init() {
 	quotaScanCmd.Flags().BoolVar(&scanUpdate, "update", false, "Update quota state with detected limits")
 
 	quotaRotateCmd.Flags().BoolVar(&rotateDryRun, "dry-run", false, "Show plan without executing")
+	quotaRotateCmd.Flags().BoolVar(&rotateProactive, "proactive", false, "Also rotate sessions whose account is projected to hit its limit soon")
+	quotaRotateCmd.Flags().DurationVar(&rotateHorizon, "horizon", quota.DefaultRotateHorizon, "With --proactive: rotate accounts projected to run out within this long")
 	quotaRotateCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
 
 	quotaCmd.AddCommand(quotaStatusCmd)
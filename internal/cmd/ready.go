@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -36,6 +37,10 @@ Aggregates ready issues from:
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
 
+Rig issues that are likely to touch files an active polecat branch has
+changed are annotated "likely conflicts with <polecat>" (see the
+dispatch.conflict_policy rig setting).
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
@@ -54,6 +59,10 @@ type ReadySource struct {
 	Name   string         `json:"name"`   // "town" or rig name
 	Issues []*beads.Issue `json:"issues"` // Ready issues from this source
 	Error  string         `json:"error,omitempty"`
+
+	// Conflicts maps issue IDs to the active polecat branches they are
+	// likely to conflict with (rig sources only).
+	Conflicts map[string][]convoy.Conflict `json:"conflicts,omitempty"`
 }
 
 // ReadyResult is the aggregated result of gt ready.
@@ -168,6 +177,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 				filtered = filterWisps(filtered, wispIDs)
 				// Filter identity beads (agents, roles, rigs) - not actionable work
 				src.Issues = filterIdentityBeads(filtered)
+				src.Conflicts = predictReadyConflicts(r, src.Issues)
 			}
 			sources = append(sources, src)
 		}(r)
@@ -295,6 +305,9 @@ func printReadyHuman(result ReadyResult) error {
 			}
 
 			fmt.Printf("  [%s] %s %s\n", priorityStyled, style.Dim.Render(issue.ID), title)
+			if conflicts := src.Conflicts[issue.ID]; len(conflicts) > 0 {
+				fmt.Printf("       %s %s\n", style.Warning.Render("⚠"), style.Dim.Render(convoy.DescribeConflicts(conflicts)))
+			}
 		}
 		fmt.Println()
 	}
@@ -326,6 +339,40 @@ func printReadyHuman(result ReadyResult) error {
 	return nil
 }
 
+// predictReadyConflicts annotates a rig's ready issues with the active
+// polecat branches they are likely to conflict with. Returns nil when the
+// rig's conflict policy is off or nothing overlaps.
+func predictReadyConflicts(r *rig.Rig, issues []*beads.Issue) map[string][]convoy.Conflict {
+	if len(issues) == 0 || convoy.LoadConflictPolicy(r.Path) == config.ConflictPolicyOff {
+		return nil
+	}
+	predictor, err := convoy.NewConflictPredictor(r)
+	if err != nil || len(predictor.Active()) == 0 {
+		return nil
+	}
+
+	var conflicts map[string][]convoy.Conflict
+	for _, issue := range issues {
+		if beads.HasLabel(issue, "gt:merge-request") {
+			continue
+		}
+		found := predictor.Check(convoy.WorkItem{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Description: issue.Description,
+			Labels:      issue.Labels,
+		})
+		if len(found) == 0 {
+			continue
+		}
+		if conflicts == nil {
+			conflicts = make(map[string][]convoy.Conflict)
+		}
+		conflicts[issue.ID] = found
+	}
+	return conflicts
+}
+
 // getFormulaNames reads the formulas directory and returns a set of formula names.
 // Formula names are derived from filenames by removing the ".formula.toml" suffix.
 func getFormulaNames(beadsPath string) map[string]bool {
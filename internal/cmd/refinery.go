@@ -13,7 +13,6 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -507,7 +506,7 @@ func runRefineryAttach(cmd *cobra.Command, args []string) error {
 	sessionID := session.RefinerySessionName(session.PrefixFor(rigName))
 
 	// Check if session exists
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
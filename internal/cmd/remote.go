@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// resolveRemoteTarget checks whether target carries a machine prefix naming a
+// registered remote machine (e.g., "buildbox:gastown/rictus"). It returns the
+// remote town and the target without its prefix, or a nil town for local
+// targets. Outside a workspace every target is treated as local.
+func resolveRemoteTarget(target string) (*connection.RemoteTown, string, error) {
+	if !strings.Contains(target, ":") {
+		return nil, target, nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, target, nil
+	}
+	registry, err := connection.LoadRegistry(townRoot)
+	if err != nil {
+		return nil, target, fmt.Errorf("loading machine registry: %w", err)
+	}
+	return registry.Resolve(target)
+}
+
+// remoteSessionName computes the tmux session name for an agent address in a
+// remote town, using the remote town's rig prefixes.
+// Accepts rig/polecat, rig/crew/name, rig/witness and rig/refinery.
+func remoteSessionName(remote *connection.RemoteTown, address string) (string, error) {
+	rigName, name, ok := strings.Cut(address, "/")
+	if !ok || rigName == "" || name == "" {
+		return "", fmt.Errorf("invalid address format: expected 'machine:rig/polecat', got '%s:%s'", remote.Machine.Name, address)
+	}
+
+	data, err := remote.Conn.ReadFile(remote.Path("mayor", "rigs.json"))
+	if err != nil {
+		return "", fmt.Errorf("reading rigs.json on %s: %w", remote.Machine.Name, err)
+	}
+	registry, err := session.ParsePrefixRegistry(data)
+	if err != nil {
+		return "", fmt.Errorf("parsing rigs.json on %s: %w", remote.Machine.Name, err)
+	}
+	prefix := registry.PrefixForRig(rigName)
+
+	switch {
+	case strings.HasPrefix(name, "crew/"):
+		return session.CrewSessionName(prefix, strings.TrimPrefix(name, "crew/")), nil
+	case name == "witness":
+		return session.WitnessSessionName(prefix), nil
+	case name == "refinery":
+		return session.RefinerySessionName(prefix), nil
+	default:
+		return session.PolecatSessionName(prefix, name), nil
+	}
+}
+
+// forwardedFlags rebuilds the flags the user explicitly set on cmd as
+// command-line arguments, so the same invocation can be replayed on a remote
+// town. Flags named in exclude are skipped.
+func forwardedFlags(cmd *cobra.Command, exclude ...string) []string {
+	skip := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		skip[name] = true
+	}
+
+	var args []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if skip[f.Name] {
+			return
+		}
+		switch v := f.Value.(type) {
+		case pflag.SliceValue:
+			for _, item := range v.GetSlice() {
+				args = append(args, "--"+f.Name, item)
+			}
+		default:
+			if f.Value.Type() == "bool" {
+				args = append(args, "--"+f.Name+"="+f.Value.String())
+			} else {
+				args = append(args, "--"+f.Name, f.Value.String())
+			}
+		}
+	})
+	return args
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/connection"
+)
+
+func TestForwardedFlags(t *testing.T) {
+	var (
+		name   string
+		force  bool
+		vars   []string
+		stdin  bool
+		unused string
+	)
+	cmd := &cobra.Command{Use: "test", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().StringVarP(&name, "account", "a", "", "")
+	cmd.Flags().BoolVar(&force, "force", false, "")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "")
+	cmd.Flags().BoolVar(&stdin, "stdin", false, "")
+	cmd.Flags().StringVar(&unused, "agent", "", "")
+
+	cmd.SetArgs([]string{"-a", "work", "--force", "--var", "x=1", "--var", "y=a b", "--stdin"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := forwardedFlags(cmd, "stdin")
+	want := []string{"--account", "work", "--force=true", "--var", "x=1", "--var", "y=a b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("forwardedFlags = %q, want %q", got, want)
+	}
+}
+
+func TestRemoteSessionName(t *testing.T) {
+	townPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townPath, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"rigs": {"gastown": {"beads": {"prefix": "gt"}}, "beads": {"beads": {"prefix": "bd"}}}}`
+	if err := os.WriteFile(filepath.Join(townPath, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	remote := &connection.RemoteTown{
+		Machine: &connection.Machine{Name: "buildbox", TownPath: townPath},
+		Conn:    connection.NewLocalConnection(),
+	}
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"gastown/rictus", "gt-rictus"},
+		{"beads/crew/dave", "bd-crew-dave"},
+		{"beads/witness", "bd-witness"},
+		{"gastown/refinery", "gt-refinery"},
+	}
+	for _, tt := range tests {
+		got, err := remoteSessionName(remote, tt.address)
+		if err != nil {
+			t.Errorf("remoteSessionName(%q): %v", tt.address, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("remoteSessionName(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+
+	if _, err := remoteSessionName(remote, "gastown"); err == nil {
+		t.Error("expected error for address without polecat")
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -224,6 +223,18 @@ func parseAddress(addr string) (rigName, polecatName string, err error) {
 	return "", "", fmt.Errorf("invalid address format: expected 'rig/polecat', got '%s'", addr)
 }
 
+// townSessionBackend returns the session backend of the town containing the
+// working directory, or tmux outside a town.
+func townSessionBackend() session.SessionBackend {
+	townRoot, _ := workspace.FindFromCwd()
+	return session.NewBackend(townRoot)
+}
+
+// rigSessionBackend returns the session backend of the town a rig belongs to.
+func rigSessionBackend(r *rig.Rig) session.SessionBackend {
+	return session.NewBackend(filepath.Dir(r.Path))
+}
+
 // getSessionManager creates a session manager for the given rig.
 func getSessionManager(rigName string) (*polecat.SessionManager, *rig.Rig, error) {
 	_, r, err := getRig(rigName)
@@ -231,8 +242,7 @@ func getSessionManager(rigName string) (*polecat.SessionManager, *rig.Rig, error
 		return nil, nil, err
 	}
 
-	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
+	polecatMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
 
 	return polecatMgr, r, nil
 }
@@ -378,11 +388,10 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Collect sessions from all rigs
-	t := tmux.NewTmux()
 	var allSessions []SessionListItem
 
 	for _, r := range rigs {
-		polecatMgr := polecat.NewSessionManager(t, r)
+		polecatMgr := polecat.NewSessionManager(rigSessionBackend(r), r)
 		infos, err := polecatMgr.List()
 		if err != nil {
 			continue
@@ -645,7 +654,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Session Health Check\n\n", style.Bold.Render("🔍"))
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	totalChecked := 0
 	totalHealthy := 0
 	totalCrashed := 0
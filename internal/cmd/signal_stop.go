@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -35,6 +37,9 @@ prevents the turn from ending and injects the message as new context.
 
 If nothing is queued, outputs {"decision":"approve"} and the agent goes idle.
 
+Nudges injected into the session before this turn ended are acknowledged
+(see gt nudge status), since the agent has now seen them.
+
 This command must complete in <500ms as it runs on every turn boundary.
 All output goes to stdout as JSON for Claude Code to consume.`,
 	Args:    cobra.NoArgs,
@@ -59,6 +64,12 @@ func runSignalStop(cmd *cobra.Command, args []string) error {
 		return outputStopAllow()
 	}
 
+	// The turn that saw any injected nudges is over: report them as
+	// acknowledged so senders stop re-nudging. Best-effort.
+	if sessionName := tmux.CurrentSessionName(); sessionName != "" {
+		_, _ = nudge.AckSession(townRoot, sessionName, address)
+	}
+
 	// Run checks in parallel for speed (<500ms budget).
 	// Mail and slung-work checks are independent and each shells out to bd.
 	var mailReason, workReason string
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -10,11 +11,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var slingCmd = &cobra.Command{
@@ -52,6 +57,20 @@ Target Resolution:
   gt sling gt-abc mayor                 # Mayor
   gt sling gt-abc deacon/dogs           # Auto-dispatch to idle dog
   gt sling gt-abc deacon/dogs/alpha     # Specific dog
+  gt sling gt-abc buildbox:gastown      # Rig in a town on another machine
+
+Remote Towns:
+  Prefix the target with a machine name from settings/machines.json to
+  sling into a town on that machine. The bead must exist in the remote
+  town's beads; the sling runs there with the flags you gave.
+
+Conflict Prediction (when target is a rig or polecat):
+  Before spawning, the files the bead is likely to touch ("path:" labels,
+  paths in its description, earlier MRs for it) are compared with files the
+  rig's active polecat branches have changed. On a likely conflict the rig's
+  dispatch.conflict_policy applies: warn (default), delay, serialize
+  (make the bead depend on the conflicting work) or off.
+  gt sling gt-abc gastown --on-conflict=delay
 
 Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
@@ -125,6 +144,7 @@ var (
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
+	slingOnConflict    string // --on-conflict: override the rig's conflict policy (warn/delay/serialize/off)
 )
 
 func init() {
@@ -150,11 +170,12 @@ func init() {
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
+	slingCmd.Flags().StringVar(&slingOnConflict, "on-conflict", "", "Likely conflict with an active polecat branch: warn, delay, serialize, off (default: rig's dispatch.conflict_policy)")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	// Polecats cannot sling - check early before writing anything.
 	// Check GT_ROLE first: coordinators (mayor, witness, etc.) may have a stale
 	// GT_POLECAT in their environment from spawning polecats. Only block if the
@@ -178,6 +199,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", slingMerge)
 		}
 	}
+	if err := config.ValidateConflictPolicy(slingOnConflict); err != nil {
+		return fmt.Errorf("invalid --on-conflict value: %w", err)
+	}
 
 	// Disable Dolt auto-commit for all bd commands run during sling (gt-u6n6a).
 	// Under concurrent load (batch slinging), auto-commits from individual bd writes
@@ -228,6 +252,18 @@ func runSling(cmd *cobra.Command, args []string) error {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// Machine-qualified target (e.g., "buildbox:gastown"): hand the whole
+	// sling to the remote town, which owns the beads and spawns the polecat.
+	if len(args) > 1 {
+		remote, rest, err := resolveRemoteTarget(args[len(args)-1])
+		if err != nil {
+			return err
+		}
+		if remote != nil {
+			return runRemoteSling(cmd, remote, args[:len(args)-1], rest)
+		}
+	}
+
 	// Validate target format early, before any dispatch path (bead, formula, batch)
 	// can trigger resolveTarget side-effects like polecat spawning.
 	if len(args) > 1 {
@@ -246,6 +282,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Trace the dispatch. The spawned session, gt done and the refinery
+	// continue this trace via the traceparent stored on the bead.
+	ctx, span := telemetry.Start(telemetry.FromEnv(context.Background()), "gt.sling")
+	defer func() { telemetry.End(span, retErr) }()
+
 	// Determine mode based on flags and argument types
 	var beadID string
 	var formulaName string
@@ -359,6 +400,25 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Conflict prediction: before a polecat is spawned, compare the files this
+	// bead is likely to touch with what the rig's active polecat branches have
+	// changed, and warn, delay or serialize per the conflict policy.
+	if len(args) > 1 {
+		check := newSlingConflictCheck(townRoot, slingConflictRig(args[len(args)-1]), slingOnConflict)
+		if check.apply(beadID, info, slingDryRun) {
+			return fmt.Errorf("not dispatching %s: likely conflicts with active polecat work (conflict policy: %s)", beadID, check.policy)
+		}
+	}
+
+	// Budget enforcement: refuse to spawn a polecat for a rig, convoy, role
+	// or agent that is over its daily hard cap until an override is approved.
+	if len(args) > 1 {
+		rigName := slingConflictRig(args[len(args)-1])
+		if err := checkSlingBudget(newSlingBudgetGate(townRoot, rigName), townRoot, rigName, beadID, slingDryRun); err != nil {
+			return err
+		}
+	}
+
 	// Preflight: check existing molecules BEFORE spawning polecat.
 	// When formulaName is already known (explicit formula via --on flag), we can
 	// validate early to avoid spawning a polecat that will be immediately orphaned
@@ -652,6 +712,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		slingMode = "ralph"
 	}
 
+	span.SetAttributes(attribute.String("gt.bead", beadID), attribute.String("gt.agent", targetAgent))
 	fieldUpdates := beadFieldUpdates{
 		Dispatcher:       actor,
 		Args:             slingArgs,
@@ -660,6 +721,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		Mode:             slingMode,
 		ConvoyID:         slingConvoyID,
 		MergeStrategy:    slingConvoyMergeStrategy,
+		TraceParent:      telemetry.TraceParent(ctx),
 	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
@@ -705,7 +767,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// This ensures polecat sees the molecule when gt prime runs on session start.
 	freshlySpawned := newPolecatInfo != nil
 	if freshlySpawned {
-		pane, err := newPolecatInfo.StartSession()
+		pane, err := startPolecatSession(ctx, newPolecatInfo)
 		if err != nil {
 			// Rollback: session failed, clean up zombie artifacts (worktree, hooked bead).
 			// Without rollback, next sling attempt fails with "bead already hooked" (gt-jn40ft).
@@ -750,6 +812,32 @@ func runSling(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRemoteSling forwards a sling to a town on another machine. The beads are
+// looked up and hooked in the remote town; flags the user set are replayed as
+// given, except --stdin, whose content has already been read into --args or
+// --message.
+func runRemoteSling(cmd *cobra.Command, remote *connection.RemoteTown, beadArgs []string, target string) error {
+	gtArgs := append([]string{"sling"}, beadArgs...)
+	if target != "" {
+		gtArgs = append(gtArgs, target)
+	}
+	gtArgs = append(gtArgs, forwardedFlags(cmd, "stdin", "args", "message")...)
+	if slingArgs != "" {
+		gtArgs = append(gtArgs, "--args", slingArgs)
+	}
+	if slingMessage != "" {
+		gtArgs = append(gtArgs, "--message", slingMessage)
+	}
+
+	fmt.Printf("%s Slinging to %s:%s...\n", style.Bold.Render("🎯"), remote.Machine.Name, target)
+	out, err := remote.Gt(detectSender(), gtArgs...)
+	fmt.Print(string(out))
+	if err != nil {
+		return fmt.Errorf("remote sling on %s failed: %w", remote.Machine.Name, err)
+	}
+	return nil
+}
+
 // checkCrossRigGuard validates that a bead's prefix matches the target rig.
 // Polecats work in their rig's worktree and cannot fix code owned by another rig.
 // Returns an error if the bead belongs to a different rig than the target polecat.
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -12,14 +13,16 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
 // Each bead gets its own freshly spawned polecat.
-func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error {
+func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) (retErr error) {
 	// Validate all beads exist before spawning any polecats
 	for _, beadID := range beadIDs {
 		if err := verifyBeadExists(beadID); err != nil {
@@ -37,6 +40,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 	}
 
+	conflictCheck := newSlingConflictCheck(filepath.Dir(townBeadsDir), rigName, slingOnConflict)
+	budgetGate := newSlingBudgetGate(filepath.Dir(townBeadsDir), rigName)
+
 	if slingDryRun {
 		fmt.Printf("%s Batch slinging %d beads to rig '%s':\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
 		fmt.Printf("  Would cook mol-polecat-work formula once\n")
@@ -48,6 +54,12 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 	fmt.Printf("%s Batch slinging %d beads to rig '%s'...\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
 
+	// One trace for the batch; each bead's polecat gets its own spawn span.
+	ctx, span := telemetry.Start(telemetry.FromEnv(context.Background()), "gt.sling.batch",
+		attribute.String("gt.rig", rigName),
+		attribute.StringSlice("gt.beads", beadIDs))
+	defer func() { telemetry.End(span, retErr) }()
+
 	if slingMaxConcurrent > 0 {
 		fmt.Printf("  Max concurrent spawns: %d\n", slingMaxConcurrent)
 	}
@@ -102,6 +114,17 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		if conflictCheck.apply(beadID, info, false) {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "held: likely conflict (" + conflictCheck.policy + ")"})
+			continue
+		}
+
+		if err := checkSlingBudget(budgetGate, filepath.Dir(townBeadsDir), rigName, beadID, false); err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "held: over budget"})
+			fmt.Printf("  %s %v\n", style.Warning.Render("⚠"), err)
+			continue
+		}
+
 		// Guard: burn existing molecules before applying new formula.
 		// Runs before polecat spawn to avoid wasted spawn/cleanup on rejected beads.
 		if formulaName != "" {
@@ -220,6 +243,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Args:             slingArgs,
 			AttachedMolecule: attachedMoleculeID,
 			NoMerge:          slingNoMerge,
+			TraceParent:      telemetry.TraceParent(ctx),
 		}
 		// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 		if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
@@ -240,7 +264,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Start polecat session now that molecule/bead is attached.
 		// This ensures polecat sees its work when gt prime runs on session start.
-		pane, err := spawnInfo.StartSession()
+		pane, err := startPolecatSession(ctx, spawnInfo)
 		if err != nil {
 			fmt.Printf("  %s Could not start session: %v, cleaning up partial state...\n", style.Dim.Render("✗"), err)
 			rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
@@ -307,7 +331,7 @@ func cleanupSpawnedPolecat(spawnInfo *SpawnedPolecatInfo, rigName string) {
 		return
 	}
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 	if err := polecatMgr.Remove(spawnInfo.PolecatName, true); err != nil {
 		fmt.Printf("  %s Could not clean up orphaned polecat %s: %v\n",
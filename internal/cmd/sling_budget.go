@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// newSlingBudgetGate returns the budget gate for slinging polecat work to
+// rigName, or nil when the target isn't a rig or the town has no budgets.
+// Batch slings share one gate so the ledger is only read once.
+func newSlingBudgetGate(townRoot, rigName string) *budget.Gate {
+	if rigName == "" {
+		return nil
+	}
+	gate, err := budget.NewGate(townRoot, "gt sling")
+	if err != nil {
+		fmt.Printf("%s Could not check budgets: %v\n", style.Dim.Render("Warning:"), err)
+		return nil
+	}
+	return gate
+}
+
+// checkSlingBudget refuses to spawn a polecat for beadID when the rig, the
+// bead's convoy, the polecat role or the polecat's agent is over its hard
+// cap without an approved override. Under --dry-run it only reports, and
+// opens no override request.
+func checkSlingBudget(gate *budget.Gate, townRoot, rigName, beadID string, dryRun bool) error {
+	if gate == nil {
+		return nil
+	}
+
+	scopes := []budget.Scope{
+		{Kind: budget.KindRig, Name: rigName},
+		{Kind: budget.KindRole, Name: constants.RolePolecat},
+	}
+	if gate.Wants(budget.KindConvoy) {
+		scopes = append(scopes, budget.Scope{Kind: budget.KindConvoy, Name: isTrackedByConvoy(beadID)})
+	}
+	if gate.Wants(budget.KindAgent) {
+		agent := slingAgent
+		if agent == "" {
+			agent, _ = config.ResolveRoleAgentName(constants.RolePolecat, townRoot, filepath.Join(townRoot, rigName))
+		}
+		scopes = append(scopes, budget.Scope{Kind: budget.KindAgent, Name: agent})
+	}
+
+	check := gate.Check
+	if dryRun {
+		check = gate.Preview
+	}
+	breach, err := check(scopes...)
+	if err != nil {
+		fmt.Printf("%s Could not check budgets: %v\n", style.Dim.Render("Warning:"), err)
+		return nil
+	}
+	if breach == nil {
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Would refuse to dispatch %s: %v\n", beadID, breach)
+		return nil
+	}
+	return fmt.Errorf("not dispatching %s: %w", beadID, breach)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingConflictRig returns the rig whose polecats a sling target dispatches
+// to, or "" when the target isn't polecat work (crew, mayor, dogs, self).
+func slingConflictRig(target string) string {
+	if rigName, isRig := IsRigName(target); isRig {
+		return rigName
+	}
+	parts := strings.Split(target, "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		if rigName, isRig := IsRigName(parts[0]); isRig {
+			return rigName
+		}
+	}
+	return ""
+}
+
+// slingConflictCheck predicts conflicts between beads being slung to a rig
+// and its active polecat branches, and applies the conflict policy. Batch
+// slings share one check so the branches are only scanned once.
+type slingConflictCheck struct {
+	rigPath   string
+	policy    string
+	predictor *convoy.ConflictPredictor
+}
+
+// newSlingConflictCheck returns a check for rigName, or nil when there is
+// nothing to check (no rig, policy off, or the branches can't be read).
+// override is the --on-conflict flag; empty uses the rig's policy.
+func newSlingConflictCheck(townRoot, rigName, override string) *slingConflictCheck {
+	if rigName == "" {
+		return nil
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	policy := override
+	if policy == "" {
+		policy = convoy.LoadConflictPolicy(rigPath)
+	}
+	if policy == config.ConflictPolicyOff {
+		return nil
+	}
+	predictor, err := convoy.NewConflictPredictor(&rig.Rig{Name: rigName, Path: rigPath})
+	if err != nil {
+		fmt.Printf("%s Could not check for conflicts: %v\n", style.Dim.Render("Warning:"), err)
+		return nil
+	}
+	return &slingConflictCheck{rigPath: rigPath, policy: policy, predictor: predictor}
+}
+
+// apply checks one bead and reports whether to hold it back instead of
+// dispatching. Under --dry-run it only reports what would happen.
+func (c *slingConflictCheck) apply(beadID string, info *beadInfo, dryRun bool) bool {
+	if c == nil {
+		return false
+	}
+	conflicts := c.predictor.Check(convoy.WorkItem{
+		ID:          beadID,
+		Title:       info.Title,
+		Description: info.Description,
+		Labels:      info.Labels,
+	})
+	if len(conflicts) == 0 {
+		return false
+	}
+
+	desc := convoy.DescribeConflicts(conflicts)
+	fmt.Printf("%s %s %s\n", style.Warning.Render("⚠"), beadID, desc)
+	for _, cf := range conflicts {
+		fmt.Printf("    %s: %s\n", cf.Polecat, style.Dim.Render(strings.Join(cf.Files, ", ")))
+	}
+
+	switch c.policy {
+	case config.ConflictPolicyDelay:
+		if dryRun {
+			fmt.Printf("Would delay dispatch of %s (conflict policy: delay)\n", beadID)
+			return false
+		}
+		fmt.Printf("  Delaying dispatch of %s; re-sling later or use --on-conflict=warn\n", beadID)
+		return true
+	case config.ConflictPolicySerialize:
+		if dryRun {
+			fmt.Printf("Would serialize %s after the conflicting work (conflict policy: serialize)\n", beadID)
+			return false
+		}
+		after, err := convoy.SerializeAfter(beads.New(c.rigPath), beadID, conflicts)
+		if err != nil {
+			fmt.Printf("  %s Could not serialize: %v; delaying instead\n", style.Dim.Render("Warning:"), err)
+			return true
+		}
+		if len(after) == 0 {
+			fmt.Printf("  Delaying dispatch of %s; conflicting work has no issue to wait on\n", beadID)
+			return true
+		}
+		fmt.Printf("  %s %s now waits for %s\n", style.Bold.Render("→"), beadID, strings.Join(after, ", "))
+		return true
+	default:
+		fmt.Printf("  Dispatching anyway (conflict policy: %s)\n", c.policy)
+		return false
+	}
+}
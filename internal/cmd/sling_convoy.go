@@ -142,6 +142,8 @@ type ConvoyInfo struct {
 	ID            string // Convoy bead ID (e.g., "hq-cv-abc")
 	Owned         bool   // true if convoy has gt:owned label
 	MergeStrategy string // "direct", "mr", "local", or "" (default = mr)
+	CreatedAt     string // Convoy creation time (RFC3339), for MR scoring
+	DueAt         string // Convoy deadline (RFC3339), if set
 }
 
 // IsOwnedDirect returns true if the convoy is owned with direct merge strategy.
@@ -157,7 +159,12 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 	if convoyID == "" {
 		return nil
 	}
+	return lookupConvoyInfo(convoyID)
+}
 
+// lookupConvoyInfo reads a convoy's details from town beads. It returns
+// basic info (just the ID) if the convoy can't be read.
+func lookupConvoyInfo(convoyID string) *ConvoyInfo {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
 		return nil
@@ -177,6 +184,7 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 	var convoys []struct {
 		Labels      []string `json:"labels"`
 		Description string   `json:"description"`
+		CreatedAt   string   `json:"created_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
 		return &ConvoyInfo{ID: convoyID}
@@ -192,12 +200,34 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 		}
 	}
 
-	// Parse merge strategy from description
+	// Parse merge strategy and deadline from description
 	info.MergeStrategy = parseConvoyMergeStrategy(convoys[0].Description)
+	info.DueAt = parseConvoyDue(convoys[0].Description)
+	info.CreatedAt = convoys[0].CreatedAt
 
 	return info
 }
 
+// convoyMRFields returns the convoy lines for an MR bead description. The
+// attachment fields only carry the convoy ID, so the convoy's timing is
+// looked up in town beads when missing.
+func convoyMRFields(info *ConvoyInfo) string {
+	createdAt, dueAt := info.CreatedAt, info.DueAt
+	if createdAt == "" && dueAt == "" {
+		if full := lookupConvoyInfo(info.ID); full != nil {
+			createdAt, dueAt = full.CreatedAt, full.DueAt
+		}
+	}
+	lines := "\nconvoy_id: " + info.ID
+	if createdAt != "" {
+		lines += "\nconvoy_created_at: " + createdAt
+	}
+	if dueAt != "" {
+		lines += "\nconvoy_due_at: " + dueAt
+	}
+	return lines
+}
+
 // getConvoyInfoFromIssue reads convoy info directly from the issue's attachment fields.
 // This is the primary lookup method (gt-7b6wf fix): gt sling stores convoy_id and
 // merge_strategy on the issue when dispatching, avoiding unreliable cross-rig dep
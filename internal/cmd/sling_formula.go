@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/steveyegge/gastown/internal/cli"
@@ -12,8 +13,10 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type wispCreateJSON struct {
@@ -72,9 +75,13 @@ func verifyFormulaExists(formulaName string) error {
 
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
-func runSlingFormula(args []string) error {
+func runSlingFormula(args []string) (retErr error) {
 	formulaName := args[0]
 
+	ctx, span := telemetry.Start(telemetry.FromEnv(context.Background()), "gt.sling",
+		attribute.String("gt.formula", formulaName))
+	defer func() { telemetry.End(span, retErr) }()
+
 	// Get town root early - needed for BEADS_DIR when running bd commands
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -192,9 +199,12 @@ func runSlingFormula(args []string) error {
 	// attached_molecule as a self-reference (the wisp's own ID pointing to itself
 	// is meaningless). attached_molecule is only meaningful when a formula-on-bead
 	// creates a wisp that's bonded to a separate base bead.
+	span.SetAttributes(attribute.String("gt.bead", wispRootID), attribute.String("gt.agent", targetAgent))
 	fieldUpdates := beadFieldUpdates{
-		Dispatcher: actor,
-		Args:       slingArgs,
+		Dispatcher:  actor,
+		Args:        slingArgs,
+		TraceParent: telemetry.TraceParent(ctx),
+		FormulaVars: stepPolicyVars(formulaName, slingVars),
 	}
 	if err := storeFieldsInBead(wispRootID, fieldUpdates); err != nil {
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
@@ -226,7 +236,7 @@ func runSlingFormula(args []string) error {
 	// Start spawned polecat session now that hook is set.
 	// This ensures polecat sees the wisp when gt prime runs on session start.
 	if resolved.NewPolecatInfo != nil {
-		pane, err := resolved.NewPolecatInfo.StartSession()
+		pane, err := startPolecatSession(ctx, resolved.NewPolecatInfo)
 		if err != nil {
 			// Rollback: unhook wisp, delete Dolt branch, clean up polecat worktree/agent bead
 			rollbackSlingArtifactsFn(resolved.NewPolecatInfo, wispRootID, "")
@@ -1,26 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // resolveBeadDir returns the directory to run bd commands for a given bead ID.
@@ -72,10 +74,11 @@ func resolveBeadDirFromRigsJSON(townRoot, prefix string) string {
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title        string          `json:"title"`
-	Status       string          `json:"status"`
-	Assignee     string          `json:"assignee"`
-	Description  string          `json:"description"`
+	Title        string           `json:"title"`
+	Status       string           `json:"status"`
+	Assignee     string           `json:"assignee"`
+	Description  string           `json:"description"`
+	Labels       []string         `json:"labels,omitempty"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 }
 
@@ -213,13 +216,15 @@ func getBeadInfo(beadID string) (*beadInfo, error) {
 // This enables a single read-modify-write cycle instead of sequential independent updates,
 // eliminating the race condition where concurrent writers could overwrite each other's fields.
 type beadFieldUpdates struct {
-	Dispatcher       string // Agent that dispatched the work
-	Args             string // Natural language instructions
-	AttachedMolecule string // Wisp root ID
-	NoMerge          bool   // Skip merge queue on completion
-	Mode             string // Execution mode: "" (normal) or "ralph"
-	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
-	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
+	Dispatcher       string            // Agent that dispatched the work
+	Args             string            // Natural language instructions
+	AttachedMolecule string            // Wisp root ID
+	NoMerge          bool              // Skip merge queue on completion
+	Mode             string            // Execution mode: "" (normal) or "ralph"
+	ConvoyID         string            // Convoy bead ID (e.g., "hq-cv-abc")
+	MergeStrategy    string            // Convoy merge strategy: "direct", "mr", "local"
+	TraceParent      string            // W3C traceparent of the sling, continued by gt done
+	FormulaVars      map[string]string // Vars a molecule root was poured with
 }
 
 // storeFieldsInBead performs a single read-modify-write to update all attachment fields
@@ -283,6 +288,16 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.MergeStrategy != "" {
 		fields.MergeStrategy = updates.MergeStrategy
 	}
+	if updates.TraceParent != "" {
+		fields.TraceParent = updates.TraceParent
+	}
+	if len(updates.FormulaVars) > 0 {
+		data, err := json.Marshal(updates.FormulaVars)
+		if err != nil {
+			return fmt.Errorf("encoding formula vars: %w", err)
+		}
+		fields.FormulaVars = string(data)
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -301,6 +316,19 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	return nil
 }
 
+// startPolecatSession starts a freshly spawned polecat's session under a
+// polecat.spawn span. The span's traceparent is handed to the session so gt
+// commands run there continue the sling's trace.
+func startPolecatSession(ctx context.Context, info *SpawnedPolecatInfo) (string, error) {
+	ctx, span := telemetry.Start(ctx, "polecat.spawn",
+		attribute.String("gt.agent", info.AgentID()),
+		attribute.String("gt.rig", info.RigName))
+	info.TraceParent = telemetry.TraceParent(ctx)
+	pane, err := info.StartSession()
+	telemetry.End(span, err)
+	return pane, err
+}
+
 // injectStartPrompt sends a prompt to the target pane to start working.
 // Uses the reliable nudge pattern: literal mode + 500ms debounce + separate Enter.
 func injectStartPrompt(pane, beadID, subject, args string) error {
@@ -358,12 +386,12 @@ func getSessionFromPane(pane string) string {
 // Uses a pragmatic approach: wait for the pane to leave a shell, then (Claude-only)
 // accept the bypass permissions warning and give it a moment to finish initializing.
 func ensureAgentReady(sessionName string) error {
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 
-	if t.IsAgentRunning(sessionName) {
+	if t.IsAgentAlive(sessionName) {
 		// Agent process is detected, but it may have just started (fresh spawn).
 		// Check session age — if < 15s old, the agent likely isn't ready for input yet.
-		if !isSessionYoung(sessionName, 15*time.Second) {
+		if !isSessionYoung(t, sessionName, 15*time.Second) {
 			return nil
 		}
 		// Fall through to apply startup delay for young sessions.
@@ -407,7 +435,7 @@ func ensureAgentReady(sessionName string) error {
 	if rc.Tmux != nil && rc.Tmux.ReadyPromptPrefix == "" && rc.Tmux.ReadyDelayMs < 1000 {
 		rc.Tmux.ReadyDelayMs = 1000
 	}
-	if err := t.WaitForRuntimeReady(sessionName, rc, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForRuntimeReady(t, sessionName, rc, constants.ClaudeStartTimeout); err != nil {
 		// Graceful degradation: warn but proceed (matches original behavior of always continuing)
 		fmt.Fprintf(os.Stderr, "Warning: agent readiness detection timed out for %s: %v\n", sessionName, err)
 	}
@@ -415,17 +443,17 @@ func ensureAgentReady(sessionName string) error {
 	return nil
 }
 
-// isSessionYoung returns true if the tmux session was created less than maxAge ago.
-func isSessionYoung(sessionName string, maxAge time.Duration) bool {
-	out, err := exec.Command("tmux", "display-message", "-t", sessionName, "-p", "#{session_created}").Output()
+// isSessionYoung returns true if the session was created less than maxAge ago.
+func isSessionYoung(t session.SessionBackend, sessionName string, maxAge time.Duration) bool {
+	info, err := t.GetSessionInfo(sessionName)
 	if err != nil {
 		return false
 	}
-	createdUnix, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	created, err := session.ParseTmuxSessionCreated(info.Created)
 	if err != nil {
 		return false
 	}
-	return time.Since(time.Unix(createdUnix, 0)) < maxAge
+	return time.Since(created) < maxAge
 }
 
 // detectCloneRoot finds the root of the current git clone.
@@ -580,7 +608,7 @@ func wakeRigAgents(rigName string) {
 		}
 	} else {
 		// Fallback to direct nudge if town root unavailable
-		t := tmux.NewTmux()
+		t := session.NewBackend(townRoot)
 		_ = t.NudgeSession(witnessSession, "Polecat dispatched - check for work")
 	}
 }
@@ -613,7 +641,7 @@ func nudgeRefinery(rigName, message string) {
 		}
 	} else {
 		// Fallback to direct nudge if town root unavailable
-		t := tmux.NewTmux()
+		t := session.NewBackend(townRoot)
 		_ = t.NudgeSession(refinerySession, message)
 	}
 }
@@ -702,6 +730,9 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 		wispRootID = bondResult.RootID
 	}
 
+	// Step 4: Record the pour vars for step-policy `when` conditions
+	recordMoleculeVars(wispRootID, formulaName, append([]string{featureVar, issueVar}, extraVars...))
+
 	return &FormulaOnBeadResult{
 		WispRootID: wispRootID,
 		BeadToHook: beadID, // Hook the BASE bead (lifecycle fix: wisp is attached_molecule)
@@ -731,7 +762,7 @@ func isHookedAgentDead(assignee string) bool {
 	if sessionName == "" {
 		return false // Unknown format, can't determine
 	}
-	t := tmux.NewTmux()
+	t := townSessionBackend()
 	alive, err := t.HasSession(sessionName)
 	if err != nil {
 		return false // tmux not available or error, be conservative
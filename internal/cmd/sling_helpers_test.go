@@ -85,6 +85,8 @@ func TestNudgeRefinerySessionName(t *testing.T) {
 func TestWakeRigAgentsDoesNotNudgeRefinery(t *testing.T) {
 	logPath := filepath.Join(t.TempDir(), "nudge.log")
 	t.Setenv("GT_TEST_NUDGE_LOG", logPath)
+	// Outside a town, so the witness nudge isn't queued into the source tree.
+	t.Chdir(t.TempDir())
 
 	// wakeRigAgents calls exec.Command("gt", "rig", "boot", ...) and tmux.NudgeSession.
 	// The boot command and witness nudge will fail silently (no real rig/tmux).
@@ -108,6 +110,8 @@ func TestWakeRigAgentsDoesNotNudgeRefinery(t *testing.T) {
 func TestNudgeRefineryNoOpWithoutLog(t *testing.T) {
 	// Ensure test log is NOT set so we exercise the real tmux path
 	t.Setenv("GT_TEST_NUDGE_LOG", "")
+	// Outside a town, so the nudge isn't queued into the source tree.
+	t.Chdir(t.TempDir())
 
 	// Should not panic even though no tmux session exists
 	nudgeRefinery("nonexistent-rig", "test message")
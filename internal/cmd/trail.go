@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/eventstore"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -355,9 +356,12 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 		since = time.Now().Add(-duration)
 	}
 
-	entries, err := readHookTrailEntries(filepath.Join(townRoot, events.EventsFile), since, trailLimit)
+	entries, err := readStoreHookTrailEntries(townRoot, since, trailLimit)
 	if err != nil {
-		return err
+		entries, err = readHookTrailEntries(filepath.Join(townRoot, events.EventsFile), since, trailLimit)
+		if err != nil {
+			return err
+		}
 	}
 
 	if trailJSON {
@@ -396,6 +400,48 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// readStoreHookTrailEntries reads hook activity from the event store.
+// Returns an error if the store is unavailable.
+func readStoreHookTrailEntries(townRoot string, since time.Time, limit int) ([]HookEntry, error) {
+	if limit <= 0 {
+		return []HookEntry{}, nil
+	}
+	store, err := eventstore.OpenTown(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	if _, err := store.Sync(townRoot); err != nil {
+		return nil, err
+	}
+
+	found, err := store.Query(eventstore.Query{
+		Sources: []string{eventstore.SourceEvents},
+		Types:   []string{events.TypeHook, events.TypeUnhook},
+		Since:   since,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]HookEntry, 0, len(found))
+	for _, e := range found {
+		actor := strings.TrimSpace(e.Actor)
+		if actor == "" {
+			actor = "unknown"
+		}
+		entries = append(entries, HookEntry{
+			Type:      e.Type,
+			Actor:     actor,
+			Bead:      e.Bead,
+			Timestamp: e.Time,
+			TimeRel:   relativeTime(e.Time),
+		})
+	}
+	return entries, nil
+}
+
 func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]HookEntry, error) {
 	if limit <= 0 {
 		return []HookEntry{}, nil
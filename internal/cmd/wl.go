@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -48,16 +49,22 @@ This command:
   4. Pushes the registration to your fork
   5. Saves wasteland configuration locally
 
-The upstream argument is a DoltHub path like 'steveyegge/wl-commons'.
+The upstream argument is an org/database path like 'steveyegge/wl-commons'.
 
 Required environment variables:
   DOLTHUB_TOKEN  - Your DoltHub API token
   DOLTHUB_ORG    - Your DoltHub organization name
 
+To join a private wasteland hosted on a file remote instead of DoltHub
+(no network or token needed), set:
+  GT_DOLT_REMOTE      - Root of the file remote (file:///srv/wasteland or an absolute path)
+  GT_DOLT_REMOTE_ORG  - Your org under that root (default: DOLTHUB_ORG)
+
 Examples:
   gt wl join steveyegge/wl-commons
   gt wl join steveyegge/wl-commons --handle my-rig
-  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"`,
+  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"
+  GT_DOLT_REMOTE=/srv/wasteland GT_DOLT_REMOTE_ORG=alice gt wl join ops/wl-commons`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWlJoin,
 }
@@ -79,15 +86,18 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Require DoltHub credentials
-	token := doltserver.DoltHubToken()
-	if token == "" {
-		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+	// Require remote provider credentials
+	provider, err := doltserver.RemoteProviderFromEnv()
+	if err != nil {
+		return fmt.Errorf("GT_DOLT_REMOTE: %w", err)
+	}
+	if err := provider.Check(); err != nil {
+		return err
 	}
 
-	forkOrg := doltserver.DoltHubOrg()
+	forkOrg := doltserver.RemoteOrg()
 	if forkOrg == "" {
-		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name (or GT_DOLT_REMOTE_ORG for a file remote)")
 	}
 
 	// Find town root
@@ -114,7 +124,7 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	// Determine town handle
 	handle := wlJoinHandle
 	if handle == "" {
-		handle = forkOrg // default to DoltHub org as handle
+		handle = forkOrg // default to the fork org as handle
 	}
 
 	displayName := wlJoinDisplayName
@@ -135,21 +145,21 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 
 	// Step 1: Fork the commons
 	fmt.Printf("Forking %s to %s/%s...\n", upstream, forkOrg, upstreamDB)
-	if err := wasteland.ForkDoltHubRepo(upstreamOrg, upstreamDB, forkOrg, token); err != nil {
+	if err := provider.ForkRepo(upstreamOrg, upstreamDB, forkOrg); err != nil {
 		return fmt.Errorf("forking commons: %w", err)
 	}
 	fmt.Printf("  %s Fork created (or already exists)\n", style.Bold.Render("✓"))
 
 	// Step 2: Clone the fork locally
 	fmt.Printf("Cloning fork to %s...\n", localDir)
-	if err := wasteland.CloneLocally(forkOrg, upstreamDB, localDir); err != nil {
+	if err := wasteland.CloneLocally(provider.URL(forkOrg, upstreamDB), localDir); err != nil {
 		return fmt.Errorf("cloning fork: %w", err)
 	}
 	fmt.Printf("  %s Clone complete\n", style.Bold.Render("✓"))
 
 	// Step 3: Add upstream remote
 	fmt.Printf("Adding upstream remote...\n")
-	if err := wasteland.AddUpstreamRemote(localDir, upstreamOrg, upstreamDB); err != nil {
+	if err := wasteland.AddUpstreamRemote(localDir, provider.URL(upstreamOrg, upstreamDB)); err != nil {
 		return fmt.Errorf("adding upstream remote: %w", err)
 	}
 	fmt.Printf("  %s Upstream remote configured\n", style.Bold.Render("✓"))
@@ -171,6 +181,7 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	// Step 6: Save wasteland config
 	cfg := &wasteland.Config{
 		Upstream:   upstream,
+		Remote:     os.Getenv("GT_DOLT_REMOTE"),
 		ForkOrg:    forkOrg,
 		ForkDB:     upstreamDB,
 		LocalDir:   localDir,
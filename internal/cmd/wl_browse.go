@@ -8,7 +8,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -31,6 +33,9 @@ var wlBrowseCmd = &cobra.Command{
 Uses the clone-then-discard pattern: clones the commons database to a
 temporary directory, queries it, then deletes the clone.
 
+After 'gt wl join', browses the joined wasteland's upstream commons on the
+remote it was joined through (DoltHub or a GT_DOLT_REMOTE file remote).
+
 EXAMPLES:
   gt wl browse                          # All open wanted items
   gt wl browse --project gastown        # Filter by project
@@ -53,7 +58,8 @@ func init() {
 }
 
 func runWLBrowse(cmd *cobra.Command, args []string) error {
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
@@ -68,16 +74,21 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	commonsOrg := "hop"
-	commonsDB := "wl-commons"
+	commonsOrg, commonsDB, provider, err := wlBrowseCommons(townRoot)
+	if err != nil {
+		return err
+	}
 	cloneDir := filepath.Join(tmpDir, commonsDB)
 
 	remote := fmt.Sprintf("%s/%s", commonsOrg, commonsDB)
 	fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
 
-	cloneCmd := exec.Command(doltPath, "clone", remote, cloneDir)
+	cloneCmd := exec.Command(doltPath, "clone", provider.URL(commonsOrg, commonsDB), cloneDir)
 	cloneCmd.Stderr = os.Stderr
 	if err := cloneCmd.Run(); err != nil {
+		if provider.Name() != doltserver.RemoteProviderDoltHub {
+			return fmt.Errorf("cloning %s: %w\nEnsure the database exists at %s", remote, err, provider.URL(commonsOrg, commonsDB))
+		}
 		return fmt.Errorf("cloning %s: %w\nEnsure the database exists on DoltHub: https://www.dolthub.com/%s", remote, err, remote)
 	}
 	fmt.Printf("%s Cloned successfully\n\n", style.Bold.Render("✓"))
@@ -95,6 +106,29 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 	return renderWLBrowseTable(doltPath, cloneDir, query)
 }
 
+// wlBrowseCommons returns the commons to browse: the joined wasteland's
+// upstream if the town has joined one, otherwise hop/wl-commons on the
+// GT_DOLT_REMOTE provider.
+func wlBrowseCommons(townRoot string) (org, db string, provider doltserver.RemoteProvider, err error) {
+	if cfg, cfgErr := wasteland.LoadConfig(townRoot); cfgErr == nil {
+		org, db, err = wasteland.ParseUpstream(cfg.Upstream)
+		if err != nil {
+			return "", "", nil, err
+		}
+		provider, err = cfg.Provider()
+		if err != nil {
+			return "", "", nil, fmt.Errorf("wasteland remote: %w", err)
+		}
+		return org, db, provider, nil
+	}
+
+	provider, err = doltserver.RemoteProviderFromEnv()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("GT_DOLT_REMOTE: %w", err)
+	}
+	return "hop", "wl-commons", provider, nil
+}
+
 func buildWLBrowseQuery() string {
 	var conditions []string
 
@@ -129,6 +129,11 @@ type AgentPresetInfo struct {
 	// EmitsPermissionWarning indicates the agent shows a bypass-permissions warning on startup
 	// that needs to be acknowledged via tmux.
 	EmitsPermissionWarning bool `json:"emits_permission_warning,omitempty"`
+
+	// UsageFormat names the session log format gt costs reads token usage from
+	// (e.g., "claude", "codex", "gemini", "opencode").
+	// Empty means the agent's usage can't be extracted and its sessions cost $0.
+	UsageFormat string `json:"usage_format,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -182,6 +187,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ReadyDelayMs:           10000,
 		InstructionsFile:       "CLAUDE.md",
 		EmitsPermissionWarning: true,
+		UsageFormat:            "claude",
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -205,6 +211,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "settings.json",
 		ReadyDelayMs:      5000,
 		InstructionsFile:  "AGENTS.md",
+		UsageFormat:       "gemini",
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -224,6 +231,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		PromptMode:       "none",
 		ReadyDelayMs:     3000,
 		InstructionsFile: "AGENTS.md",
+		UsageFormat:      "codex",
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -297,6 +305,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "gastown.js",
 		ReadyDelayMs:      8000,
 		InstructionsFile:  "AGENTS.md",
+		UsageFormat:       "opencode",
 	},
 	AgentCopilot: {
 		Name:                AgentCopilot,
@@ -546,6 +555,25 @@ func BuildResumeCommand(agentName, sessionID string) string {
 	}
 }
 
+// ResumeArgs returns the arguments that make an agent pick up its previous
+// conversation: the ResumeFlag with sessionID when the ID is known and the
+// agent takes it as a flag, otherwise the ContinueFlag (most recent
+// conversation in the working directory). Returns nil if the agent supports
+// neither.
+func ResumeArgs(agentName, sessionID string) []string {
+	info := GetAgentPresetByName(agentName)
+	if info == nil {
+		return nil
+	}
+	if sessionID != "" && info.ResumeFlag != "" && info.ResumeStyle != "subcommand" {
+		return []string{info.ResumeFlag, sessionID}
+	}
+	if info.ContinueFlag != "" {
+		return []string{info.ContinueFlag}
+	}
+	return nil
+}
+
 // SupportsSessionResume checks if an agent supports session resumption.
 func SupportsSessionResume(agentName string) bool {
 	info := GetAgentPresetByName(agentName)
@@ -398,6 +398,28 @@ func TestBuildResumeCommand(t *testing.T) {
 	}
 }
 
+func TestResumeArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		agentName string
+		sessionID string
+		want      string
+	}{
+		{"claude", "session-123", "--resume session-123"},
+		{"claude", "", "--continue"},
+		{"gemini", "gemini-sess", "--resume gemini-sess"},
+		{"gemini", "", ""},
+		{"codex", "codex-sess", ""}, // subcommand style can't be appended
+		{"unknown", "x", ""},
+	}
+	for _, tt := range tests {
+		got := strings.Join(ResumeArgs(tt.agentName, tt.sessionID), " ")
+		if got != tt.want {
+			t.Errorf("ResumeArgs(%q, %q) = %q, want %q", tt.agentName, tt.sessionID, got, tt.want)
+		}
+	}
+}
+
 func TestSupportsSessionResume(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -219,9 +219,28 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Dispatch != nil {
+		if err := ValidateConflictPolicy(c.Dispatch.ConflictPolicy); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ErrInvalidConflictPolicy indicates an invalid dispatch conflict_policy.
+var ErrInvalidConflictPolicy = errors.New("invalid conflict_policy")
+
+// ValidateConflictPolicy checks a dispatch conflict policy. Empty means the
+// default (warn).
+func ValidateConflictPolicy(policy string) error {
+	switch policy {
+	case "", ConflictPolicyOff, ConflictPolicyWarn, ConflictPolicyDelay, ConflictPolicySerialize:
+		return nil
+	}
+	return fmt.Errorf("%w: got '%s', want one of %s, %s, %s, %s", ErrInvalidConflictPolicy, policy,
+		ConflictPolicyWarn, ConflictPolicyDelay, ConflictPolicySerialize, ConflictPolicyOff)
+}
+
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
@@ -259,6 +278,44 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	if c.Scoring != nil {
+		if err := validateMRScoringConfig(c.Scoring); err != nil {
+			return fmt.Errorf("invalid scoring: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateMRScoringConfig checks that scoring weights are usable. Everything
+// except base_score is a magnitude, so negative values are rejected.
+func validateMRScoringConfig(c *MRScoringConfig) error {
+	weights := map[string]*float64{
+		"priority_weight":     c.PriorityWeight,
+		"convoy_age_weight":   c.ConvoyAgeWeight,
+		"mr_age_weight":       c.MRAgeWeight,
+		"retry_penalty":       c.RetryPenalty,
+		"max_retry_penalty":   c.MaxRetryPenalty,
+		"deadline_weight":     c.DeadlineWeight,
+		"unblock_weight":      c.UnblockWeight,
+		"max_unblock_bonus":   c.MaxUnblockBonus,
+		"overlap_penalty":     c.OverlapPenalty,
+		"max_overlap_penalty": c.MaxOverlapPenalty,
+	}
+	for name, w := range weights {
+		if w != nil && *w < 0 {
+			return fmt.Errorf("%s must be non-negative, got %v", name, *w)
+		}
+	}
+	if c.DeadlineHorizon != "" {
+		dur, err := time.ParseDuration(c.DeadlineHorizon)
+		if err != nil {
+			return fmt.Errorf("deadline_horizon: %w", err)
+		}
+		if dur <= 0 {
+			return fmt.Errorf("deadline_horizon must be positive, got %v", dur)
+		}
+	}
 	return nil
 }
 
@@ -1714,6 +1771,18 @@ func ExtractSimpleRole(gtRole string) string {
 // (ResolveRoleAgentConfig) to select the appropriate agent for the role.
 // This enables per-role model selection via role_agents in settings.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
+	return buildStartupCommand(envVars, rigPath, prompt, "", false)
+}
+
+// BuildResumeStartupCommand is like BuildStartupCommand, but the agent picks
+// up its previous conversation: the preset's ResumeFlag with sessionID if
+// known, otherwise its ContinueFlag. Agents that support neither start fresh
+// with the prompt.
+func BuildResumeStartupCommand(envVars map[string]string, rigPath, sessionID, prompt string) string {
+	return buildStartupCommand(envVars, rigPath, prompt, sessionID, true)
+}
+
+func buildStartupCommand(envVars map[string]string, rigPath, prompt, sessionID string, resume bool) string {
 	var rc *RuntimeConfig
 	var townRoot string
 
@@ -1796,6 +1865,18 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		cmd = "exec env " + strings.Join(exports, " ") + " "
 	}
 
+	if resume {
+		agent := rc.ResolvedAgent
+		if agent == "" {
+			agent = rc.Provider
+		}
+		if args := ResumeArgs(agent, sessionID); len(args) > 0 {
+			withResume := *rc
+			withResume.Args = append(append([]string(nil), rc.Args...), args...)
+			rc = &withResume
+		}
+	}
+
 	// Add runtime command
 	if prompt != "" {
 		cmd += rc.BuildCommandWithPrompt(prompt)
@@ -2013,6 +2094,23 @@ func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt string) st
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
 
+// BuildPolecatResumeCommand builds the startup command for a polecat whose
+// session crashed, resuming its previous conversation where the agent
+// supports it. See BuildResumeStartupCommand.
+func BuildPolecatResumeCommand(rigName, polecatName, rigPath, sessionID, prompt string) string {
+	var townRoot string
+	if rigPath != "" {
+		townRoot = filepath.Dir(rigPath)
+	}
+	envVars := AgentEnv(AgentEnvConfig{
+		Role:      "polecat",
+		Rig:       rigName,
+		AgentName: polecatName,
+		TownRoot:  townRoot,
+	})
+	return BuildResumeStartupCommand(envVars, rigPath, sessionID, prompt)
+}
+
 // BuildPolecatStartupCommandWithAgentOverride is like BuildPolecatStartupCommand, but uses agentOverride if non-empty.
 func BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, rigPath, prompt, agentOverride string) (string, error) {
 	var townRoot string
@@ -2207,6 +2305,26 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		}
 	}
 
+	// Validate delivery timeout if specified
+	if c.Delivery.Timeout != "" {
+		if _, err := time.ParseDuration(c.Delivery.Timeout); err != nil {
+			return fmt.Errorf("invalid delivery.timeout: %w", err)
+		}
+	}
+
+	// Validate SMTP settings if specified
+	if smtp := c.Delivery.SMTP; smtp != nil {
+		if smtp.Host == "" {
+			return fmt.Errorf("%w: delivery.smtp.host", ErrMissingField)
+		}
+		if smtp.From == "" {
+			return fmt.Errorf("%w: delivery.smtp.from", ErrMissingField)
+		}
+		if smtp.Port < 0 || smtp.Port > 65535 {
+			return fmt.Errorf("invalid delivery.smtp.port: %d", smtp.Port)
+		}
+	}
+
 	// Initialize nil maps
 	if c.Routes == nil {
 		c.Routes = make(map[string][]string)
@@ -2250,6 +2368,31 @@ func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
 	return []string{"bead", "mail:mayor"}
 }
 
+// GetDeliveryTimeout returns the per-delivery timeout for external actions.
+// Returns 30 seconds if not configured or invalid.
+func (c *EscalationConfig) GetDeliveryTimeout() time.Duration {
+	if c.Delivery.Timeout == "" {
+		return 30 * time.Second
+	}
+	d, err := time.ParseDuration(c.Delivery.Timeout)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// GetLogPath returns the escalation log path for "log" actions.
+// Relative paths are resolved against townRoot.
+func (c *EscalationConfig) GetLogPath(townRoot string) string {
+	if c.Delivery.LogPath == "" {
+		return filepath.Join(townRoot, "logs", "escalations.log")
+	}
+	if filepath.IsAbs(c.Delivery.LogPath) {
+		return c.Delivery.LogPath
+	}
+	return filepath.Join(townRoot, c.Delivery.LogPath)
+}
+
 // GetMaxReescalations returns the maximum number of re-escalations allowed.
 // Returns 2 if not configured (nil). Explicit 0 means "never re-escalate".
 func (c *EscalationConfig) GetMaxReescalations() int {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -240,6 +241,64 @@ func TestRigSettingsWithCustomMergeQueue(t *testing.T) {
 	}
 }
 
+func TestRigSettingsScoringValidation(t *testing.T) {
+	t.Parallel()
+	weight := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		scoring *MRScoringConfig
+		wantErr bool
+	}{
+		{"valid", &MRScoringConfig{PriorityWeight: weight(150), DeadlineHorizon: "24h"}, false},
+		{"negative base allowed", &MRScoringConfig{BaseScore: weight(-10)}, false},
+		{"negative weight", &MRScoringConfig{OverlapPenalty: weight(-1)}, true},
+		{"bad horizon", &MRScoringConfig{DeadlineHorizon: "soon"}, true},
+		{"zero horizon", &MRScoringConfig{DeadlineHorizon: "0s"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "settings.json")
+			settings := &RigSettings{
+				Type:       "rig-settings",
+				Version:    1,
+				MergeQueue: &MergeQueueConfig{Scoring: tt.scoring},
+			}
+			data, err := json.Marshal(settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err = LoadRigSettings(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadRigSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRigSettingsConflictPolicyValidation(t *testing.T) {
+	t.Parallel()
+	for policy, wantErr := range map[string]bool{
+		"":                      false,
+		ConflictPolicyWarn:      false,
+		ConflictPolicyDelay:     false,
+		ConflictPolicySerialize: false,
+		ConflictPolicyOff:       false,
+		"block":                 true,
+	} {
+		settings := &RigSettings{Type: "rig-settings", Version: 1, Dispatch: &DispatchConfig{ConflictPolicy: policy}}
+		err := SaveRigSettings(filepath.Join(t.TempDir(), "settings.json"), settings)
+		if (err != nil) != wantErr {
+			t.Errorf("conflict_policy %q: error = %v, wantErr %v", policy, err, wantErr)
+		}
+		if wantErr && !errors.Is(err, ErrInvalidConflictPolicy) {
+			t.Errorf("conflict_policy %q: error %v should wrap ErrInvalidConflictPolicy", policy, err)
+		}
+	}
+}
+
 func TestRigConfigValidation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1286,6 +1345,27 @@ func TestBuildStartupCommand_UsesRigAgentWhenRigPathProvided(t *testing.T) {
 	}
 }
 
+func TestBuildResumeStartupCommand(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	env := map[string]string{"GT_ROLE": "polecat"}
+
+	cmd := BuildResumeStartupCommand(env, rigPath, "", "pick up where you left off")
+	if !strings.Contains(cmd, `--continue "pick up where you left off"`) {
+		t.Errorf("expected continue flag before prompt: %q", cmd)
+	}
+
+	cmd = BuildResumeStartupCommand(env, rigPath, "sess-1", "go")
+	if !strings.Contains(cmd, "--resume sess-1") || strings.Contains(cmd, "--continue") {
+		t.Errorf("expected resume of the known session: %q", cmd)
+	}
+
+	if plain := BuildStartupCommand(env, rigPath, "go"); strings.Contains(plain, "--resume") || strings.Contains(plain, "--continue") {
+		t.Errorf("BuildStartupCommand should not resume: %q", plain)
+	}
+}
+
 func TestBuildStartupCommand_UsesRoleAgentsFromTownSettings(t *testing.T) {
 	townRoot := t.TempDir()
 	rigPath := filepath.Join(townRoot, "testrig")
@@ -86,8 +86,19 @@ type TownSettings struct {
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
+
+	// SessionBackend selects what hosts agent sessions.
+	// Values: "tmux" (default), "pty" (headless PTYs hosted by the daemon,
+	// for hosts without tmux). Can be overridden by GT_SESSION_BACKEND.
+	SessionBackend string `json:"session_backend,omitempty"`
 }
 
+// Session backend constants.
+const (
+	SessionBackendTmux = "tmux"
+	SessionBackendPTY  = "pty"
+)
+
 // NewTownSettings creates a new TownSettings with defaults.
 func NewTownSettings() *TownSettings {
 	return &TownSettings{
@@ -323,6 +334,25 @@ type WorkflowConfig struct {
 	DefaultFormula string `json:"default_formula,omitempty"`
 }
 
+// DispatchConfig represents work dispatch settings for a rig.
+type DispatchConfig struct {
+	// ConflictPolicy decides what gt sling and the convoy dispatcher do when
+	// an issue is likely to touch files an active polecat branch has changed:
+	//   "warn"      - dispatch anyway and print a warning (default)
+	//   "delay"     - don't dispatch; try again later
+	//   "serialize" - make the issue depend on the conflicting work
+	//   "off"       - don't predict conflicts
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
+}
+
+// Conflict policy constants.
+const (
+	ConflictPolicyOff       = "off"
+	ConflictPolicyWarn      = "warn"
+	ConflictPolicyDelay     = "delay"
+	ConflictPolicySerialize = "serialize"
+)
+
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
 	Type       string            `json:"type"`                  // "rig-settings"
@@ -332,6 +362,7 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Dispatch   *DispatchConfig   `json:"dispatch,omitempty"`    // work dispatch settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -884,6 +915,35 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// Scoring overrides the weights the refinery uses to order the queue.
+	// Nil (or any unset field) keeps the built-in defaults.
+	Scoring *MRScoringConfig `json:"scoring,omitempty"`
+}
+
+// MRScoringConfig overrides merge request scoring weights. Each field maps to
+// a refinery.ScoreConfig weight; unset fields keep the refinery default.
+type MRScoringConfig struct {
+	BaseScore       *float64 `json:"base_score,omitempty"`
+	PriorityWeight  *float64 `json:"priority_weight,omitempty"`
+	ConvoyAgeWeight *float64 `json:"convoy_age_weight,omitempty"`
+	MRAgeWeight     *float64 `json:"mr_age_weight,omitempty"`
+	RetryPenalty    *float64 `json:"retry_penalty,omitempty"`
+	MaxRetryPenalty *float64 `json:"max_retry_penalty,omitempty"`
+
+	// DeadlineWeight is points per hour as a convoy's due date approaches,
+	// starting DeadlineHorizon (e.g., "48h") before it.
+	DeadlineWeight  *float64 `json:"deadline_weight,omitempty"`
+	DeadlineHorizon string   `json:"deadline_horizon,omitempty"`
+
+	// UnblockWeight is points per open issue the MR's source issue blocks.
+	UnblockWeight   *float64 `json:"unblock_weight,omitempty"`
+	MaxUnblockBonus *float64 `json:"max_unblock_bonus,omitempty"`
+
+	// OverlapPenalty is points lost per file the MR shares with MRs already
+	// being merged, to keep likely conflicts out of the same window.
+	OverlapPenalty    *float64 `json:"overlap_penalty,omitempty"`
+	MaxOverlapPenalty *float64 `json:"max_overlap_penalty,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -989,13 +1049,29 @@ type AccountsConfig struct {
 	Version  int                `json:"version"`  // schema version
 	Accounts map[string]Account `json:"accounts"` // handle -> account details
 	Default  string             `json:"default"`  // default account handle
+
+	// Placement picks the account for new sessions that don't name one:
+	// "default" (the default account) or "headroom" (the account with the
+	// most quota left in its rolling usage window). Empty means "default".
+	Placement string `json:"placement,omitempty"`
 }
 
+// Account placement strategies for AccountsConfig.Placement.
+const (
+	AccountPlacementDefault  = "default"
+	AccountPlacementHeadroom = "headroom"
+)
+
 // Account represents a single Claude Code account.
 type Account struct {
 	Email       string `json:"email"`                 // account email
 	Description string `json:"description,omitempty"` // human description
 	ConfigDir   string `json:"config_dir"`            // path to CLAUDE_CONFIG_DIR
+
+	// WindowTokens is the account's token allowance per rolling usage window.
+	// When unset, quota learns it from the usage seen when the account hits
+	// its rate limit.
+	WindowTokens int `json:"window_tokens,omitempty"`
 }
 
 // CurrentAccountsVersion is the current schema version for AccountsConfig.
@@ -1037,6 +1113,10 @@ type AccountQuotaState struct {
 	LimitedAt string             `json:"limited_at,omitempty"` // RFC3339 when limit was detected
 	ResetsAt  string             `json:"resets_at,omitempty"`  // Human-readable reset time from provider (e.g. "7pm (America/Los_Angeles)")
 	LastUsed  string             `json:"last_used,omitempty"`  // RFC3339 when account was last assigned to a session
+
+	// LimitTokens is the usage in the rolling window when the account was
+	// last found rate-limited: a learned estimate of its allowance.
+	LimitTokens int `json:"limit_tokens,omitempty"`
 }
 
 // CurrentQuotaVersion is the current schema version for QuotaState.
@@ -1118,12 +1198,17 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "webhook:<name>" → POST JSON to contacts.webhooks[name]
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Delivery configures the transports used by external notification actions
+	// (SMTP server, SMS bridge command, log file location, timeouts).
+	Delivery EscalationDelivery `json:"delivery,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1141,6 +1226,43 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+
+	// Webhooks maps names to URLs for generic "webhook:<name>" actions.
+	// Payloads are Slack-compatible JSON, so any incoming-webhook receiver works.
+	Webhooks map[string]string `json:"webhooks,omitempty"`
+}
+
+// EscalationDelivery configures how external escalation actions are delivered.
+type EscalationDelivery struct {
+	// SMTP configures the mail server used by "email:" actions.
+	SMTP *EscalationSMTP `json:"smtp,omitempty"`
+
+	// SMSCommand is a shell command that bridges "sms:" actions to an SMS
+	// provider. It runs via "sh -c" with GT_SMS_TO set to the destination
+	// number and the message text on stdin. Example:
+	//   "twilio api:core:messages:create --to \"$GT_SMS_TO\" --body \"$(cat)\""
+	SMSCommand string `json:"sms_command,omitempty"`
+
+	// LogPath is the append-only escalation log written by "log" actions.
+	// Relative paths are resolved against the town root.
+	// Default: "logs/escalations.log"
+	LogPath string `json:"log_path,omitempty"`
+
+	// Timeout bounds each individual delivery attempt.
+	// Format: Go duration string. Default: "30s"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// EscalationSMTP contains SMTP server settings for email delivery.
+type EscalationSMTP struct {
+	Host     string `json:"host"`               // SMTP server hostname
+	Port     int    `json:"port,omitempty"`     // default 587
+	Username string `json:"username,omitempty"` // auth username (empty = no auth)
+	From     string `json:"from"`               // envelope and header sender
+
+	// PasswordEnv names the environment variable holding the SMTP password,
+	// so the secret never lives in settings/escalation.json.
+	PasswordEnv string `json:"password_env,omitempty"`
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
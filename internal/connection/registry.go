@@ -7,15 +7,51 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // Machine represents a managed machine in the federation.
 type Machine struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`      // "local", "ssh"
-	Host     string `json:"host"`      // for ssh: user@host
+	Host     string `json:"host"`      // for ssh: user@host[:port]
 	KeyPath  string `json:"key_path"`  // SSH private key path
 	TownPath string `json:"town_path"` // Path to town root on remote
+
+	// Host key verification for ssh machines. HostKey pins the server key
+	// (authorized_keys format); otherwise KnownHosts (default
+	// ~/.ssh/known_hosts) must contain the host.
+	HostKey    string `json:"host_key,omitempty"`
+	KnownHosts string `json:"known_hosts,omitempty"`
+
+	// Timeouts for ssh machines (Go duration strings). Defaults: 15s dial, 5m per command.
+	DialTimeout    string `json:"dial_timeout,omitempty"`
+	CommandTimeout string `json:"command_timeout,omitempty"`
+}
+
+// SSHConfig returns the SSH connection settings for an ssh machine.
+func (m *Machine) SSHConfig() (SSHConfig, error) {
+	cfg := SSHConfig{
+		Host:           m.Host,
+		KeyPath:        m.KeyPath,
+		KnownHostsPath: m.KnownHosts,
+		HostKey:        m.HostKey,
+	}
+	if m.DialTimeout != "" {
+		d, err := time.ParseDuration(m.DialTimeout)
+		if err != nil {
+			return cfg, fmt.Errorf("machine %s: invalid dial_timeout: %w", m.Name, err)
+		}
+		cfg.DialTimeout = d
+	}
+	if m.CommandTimeout != "" {
+		d, err := time.ParseDuration(m.CommandTimeout)
+		if err != nil {
+			return cfg, fmt.Errorf("machine %s: invalid command_timeout: %w", m.Name, err)
+		}
+		cfg.CommandTimeout = d
+	}
+	return cfg, nil
 }
 
 // registryData is the JSON file structure.
@@ -122,12 +158,20 @@ func (r *MachineRegistry) Add(m *Machine) error {
 	if m.Name == "" {
 		return fmt.Errorf("machine name is required")
 	}
+	if reservedMachineNames[m.Name] {
+		return fmt.Errorf("machine name %q is reserved (conflicts with %s: addresses)", m.Name, m.Name)
+	}
 	if m.Type == "" {
 		return fmt.Errorf("machine type is required")
 	}
 	if m.Type == "ssh" && m.Host == "" {
 		return fmt.Errorf("ssh machine requires host")
 	}
+	if m.Type == "ssh" {
+		if _, err := m.SSHConfig(); err != nil {
+			return err
+		}
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -176,13 +220,22 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		cfg, err := m.SSHConfig()
+		if err != nil {
+			return nil, err
+		}
+		return NewSSHConnection(m.Name, cfg), nil
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
 }
 
+// CloseConnections closes all pooled SSH connections.
+// Long-running processes (daemon) should call this on shutdown.
+func (r *MachineRegistry) CloseConnections() {
+	defaultSSHPool.closeAll()
+}
+
 // LocalConnection returns the local connection.
 // This is a convenience method for the common case.
 func (r *MachineRegistry) LocalConnection() *LocalConnection {
@@ -0,0 +1,118 @@
+package connection
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// reservedMachineNames are address prefixes that already mean something else
+// ("list:ops", "queue:work", "channel:all"). A machine with one of these names
+// would make those addresses ambiguous.
+var reservedMachineNames = map[string]bool{
+	"list":     true,
+	"group":    true,
+	"queue":    true,
+	"announce": true,
+	"channel":  true,
+}
+
+// RegistryPath returns the machine registry path for a town.
+func RegistryPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "machines.json")
+}
+
+// LoadRegistry loads the town's machine registry (settings/machines.json).
+// A missing file yields a registry containing only the local machine.
+func LoadRegistry(townRoot string) (*MachineRegistry, error) {
+	return NewMachineRegistry(RegistryPath(townRoot))
+}
+
+// RemoteTown is a Gas Town hosted on another machine and reached over a
+// Connection. Paths are in the remote machine's filesystem.
+type RemoteTown struct {
+	Machine *Machine
+	Conn    Connection
+}
+
+// Path joins elements onto the remote town root.
+// Remote paths are always slash-separated.
+func (t *RemoteTown) Path(elem ...string) string {
+	return path.Join(append([]string{t.Machine.TownPath}, elem...)...)
+}
+
+// Gt runs a gt command in the remote town root and returns combined output.
+// If actor is a full agent address (contains "/"), it is passed as GT_ROLE and
+// BD_ACTOR so the remote side attributes the operation to the local sender.
+func (t *RemoteTown) Gt(actor string, args ...string) ([]byte, error) {
+	envArgs := []string{"GT_TOWN_ROOT=" + t.Machine.TownPath}
+	if strings.Contains(actor, "/") {
+		envArgs = append(envArgs, "GT_ROLE="+actor, "BD_ACTOR="+actor)
+	}
+	cmdArgs := append(append(envArgs, "gt"), args...)
+	out, err := t.Conn.ExecDir(t.Machine.TownPath, "env", cmdArgs...)
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return out, fmt.Errorf("gt %s on %s: %s: %w", firstArg(args), t.Machine.Name, msg, err)
+		}
+		return out, fmt.Errorf("gt %s on %s: %w", firstArg(args), t.Machine.Name, err)
+	}
+	return out, nil
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// SplitMachine splits a "machine:rest" target when machine names a registered
+// remote machine. A local machine prefix is stripped; for anything else (no
+// prefix, unknown name such as "list:" or "channel:") the target is returned
+// unchanged with an empty machine name.
+func (r *MachineRegistry) SplitMachine(target string) (machine, rest string) {
+	idx := strings.Index(target, ":")
+	if idx <= 0 {
+		return "", target
+	}
+	name := target[:idx]
+	if name == "local" {
+		return "", target[idx+1:]
+	}
+	if reservedMachineNames[name] {
+		return "", target
+	}
+	m, err := r.Get(name)
+	if err != nil {
+		return "", target
+	}
+	if m.Type == "local" {
+		return "", target[idx+1:]
+	}
+	return name, target[idx+1:]
+}
+
+// Resolve returns the RemoteTown for a machine-qualified target such as
+// "buildbox:gastown/rictus", together with the target stripped of its machine
+// prefix. For local targets it returns a nil RemoteTown.
+func (r *MachineRegistry) Resolve(target string) (*RemoteTown, string, error) {
+	name, rest := r.SplitMachine(target)
+	if name == "" {
+		return nil, rest, nil
+	}
+
+	m, err := r.Get(name)
+	if err != nil {
+		return nil, rest, err
+	}
+	if m.TownPath == "" {
+		return nil, rest, fmt.Errorf("machine %s has no town_path configured", name)
+	}
+	conn, err := r.Connection(name)
+	if err != nil {
+		return nil, rest, fmt.Errorf("connecting to %s: %w", name, err)
+	}
+	return &RemoteTown{Machine: m, Conn: conn}, rest, nil
+}
@@ -0,0 +1,121 @@
+package connection
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) *MachineRegistry {
+	t.Helper()
+	townRoot := t.TempDir()
+	r, err := LoadRegistry(townRoot)
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+	if err := r.Add(&Machine{Name: "buildbox", Type: "ssh", Host: "gt@buildbox", TownPath: "/home/gt/town"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := r.Add(&Machine{Name: "notown", Type: "ssh", Host: "gt@notown"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return r
+}
+
+func TestRegistrySplitMachine(t *testing.T) {
+	r := newTestRegistry(t)
+
+	tests := []struct {
+		target      string
+		wantMachine string
+		wantRest    string
+	}{
+		{"buildbox:gastown/rictus", "buildbox", "gastown/rictus"},
+		{"buildbox:mayor/", "buildbox", "mayor/"},
+		{"local:gastown/rictus", "", "gastown/rictus"},
+		{"gastown/rictus", "", "gastown/rictus"},
+		{"list:oncall", "", "list:oncall"},
+		{"channel:alerts", "", "channel:alerts"},
+		{"unknown:gastown/rictus", "", "unknown:gastown/rictus"},
+		{":gastown", "", ":gastown"},
+	}
+	for _, tt := range tests {
+		machine, rest := r.SplitMachine(tt.target)
+		if machine != tt.wantMachine || rest != tt.wantRest {
+			t.Errorf("SplitMachine(%q) = (%q, %q), want (%q, %q)",
+				tt.target, machine, rest, tt.wantMachine, tt.wantRest)
+		}
+	}
+}
+
+func TestRegistryResolve(t *testing.T) {
+	r := newTestRegistry(t)
+
+	remote, rest, err := r.Resolve("buildbox:gastown/rictus")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if remote == nil || remote.Machine.Name != "buildbox" || rest != "gastown/rictus" {
+		t.Fatalf("Resolve = (%+v, %q)", remote, rest)
+	}
+	if remote.Conn.IsLocal() {
+		t.Error("expected remote connection")
+	}
+	if got := remote.Path("mayor", "rigs.json"); got != "/home/gt/town/mayor/rigs.json" {
+		t.Errorf("Path = %q", got)
+	}
+
+	remote, rest, err = r.Resolve("gastown/rictus")
+	if err != nil || remote != nil || rest != "gastown/rictus" {
+		t.Errorf("local Resolve = (%v, %q, %v)", remote, rest, err)
+	}
+
+	if _, _, err := r.Resolve("notown:gastown/rictus"); err == nil || !strings.Contains(err.Error(), "town_path") {
+		t.Errorf("expected town_path error, got %v", err)
+	}
+}
+
+func TestRegistryAddReservedName(t *testing.T) {
+	r := newTestRegistry(t)
+	for _, name := range []string{"list", "queue", "announce", "channel"} {
+		if err := r.Add(&Machine{Name: name, Type: "ssh", Host: "gt@" + name}); err == nil {
+			t.Errorf("Add(%q) succeeded, want reserved-name error", name)
+		}
+	}
+}
+
+func TestRemoteTownGt(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+
+	// Fake gt that reports how it was invoked.
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho \"root=$GT_TOWN_ROOT role=$GT_ROLE actor=$BD_ACTOR pwd=$(pwd) args=$*\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(script), 0755); err != nil {
+		t.Fatalf("writing fake gt: %v", err)
+	}
+	t.Setenv("GT_ROLE", "")
+	t.Setenv("BD_ACTOR", "")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	townPath := t.TempDir()
+	remote := &RemoteTown{Machine: &Machine{Name: "buildbox", TownPath: townPath}, Conn: conn}
+
+	out, err := remote.Gt("gastown/Toast", "nudge", "gastown/rictus", "-m", "hi there")
+	if err != nil {
+		t.Fatalf("Gt: %v", err)
+	}
+	want := "root=" + townPath + " role=gastown/Toast actor=gastown/Toast pwd=" + townPath + " args=nudge gastown/rictus -m hi there"
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("Gt output = %q\nwant %q", got, want)
+	}
+
+	// Bare role names are not forwarded as identities.
+	out, err = remote.Gt("mayor", "status")
+	if err != nil {
+		t.Fatalf("Gt: %v", err)
+	}
+	if !strings.Contains(string(out), "role= actor= ") {
+		t.Errorf("bare actor forwarded: %q", out)
+	}
+}
@@ -0,0 +1,751 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Default SSH timeouts. DialTimeout bounds TCP connect plus handshake;
+// CommandTimeout bounds a single remote command.
+const (
+	DefaultSSHDialTimeout    = 15 * time.Second
+	DefaultSSHCommandTimeout = 5 * time.Minute
+)
+
+// sshKeepaliveTimeout bounds the liveness probe of a pooled client, so a
+// half-dead connection is redialed instead of blocking the caller.
+const sshKeepaliveTimeout = 5 * time.Second
+
+// SSHConfig configures an SSH connection to a remote machine.
+type SSHConfig struct {
+	// Host is "[user@]host[:port]". User defaults to $USER, port to 22.
+	Host string
+
+	// KeyPath is the private key used for authentication. When empty,
+	// keys from a running ssh-agent (SSH_AUTH_SOCK) are used.
+	KeyPath string
+
+	// KnownHostsPath is the known_hosts file used to verify the server's
+	// host key. Default: ~/.ssh/known_hosts.
+	KnownHostsPath string
+
+	// HostKey pins the server's host key in authorized_keys format
+	// (e.g., "ssh-ed25519 AAAA..."). Takes precedence over KnownHostsPath.
+	HostKey string
+
+	// DialTimeout and CommandTimeout override the defaults when non-zero.
+	DialTimeout    time.Duration
+	CommandTimeout time.Duration
+}
+
+// SSHConnection implements Connection over SSH, using SFTP for file
+// operations and remote shell commands for execution and tmux.
+//
+// The underlying *ssh.Client is shared through an sshPool, so many
+// SSHConnection values for the same machine reuse one TCP connection.
+type SSHConnection struct {
+	name string
+	cfg  SSHConfig
+	pool *sshPool
+}
+
+// NewSSHConnection creates an SSH connection for the named machine.
+// The connection is established lazily on first use.
+func NewSSHConnection(name string, cfg SSHConfig) *SSHConnection {
+	return &SSHConnection{name: name, cfg: cfg, pool: defaultSSHPool}
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Close drops the pooled client for this machine. Other SSHConnection values
+// for the same machine will transparently reconnect.
+func (c *SSHConnection) Close() error {
+	return c.pool.close(c.poolKey())
+}
+
+func (c *SSHConnection) poolKey() string {
+	return c.name + "|" + c.cfg.Host + "|" + c.cfg.KeyPath
+}
+
+// conn returns the pooled client entry, dialing if needed.
+func (c *SSHConnection) conn() (*pooledSSH, error) {
+	p, err := c.pool.get(c.poolKey(), c.cfg)
+	if err != nil {
+		return nil, &ConnectionError{Op: "connect", Machine: c.name, Err: err}
+	}
+	return p, nil
+}
+
+// sftpClient returns the pooled SFTP client, starting the subsystem if needed.
+func (c *SSHConnection) sftpClient() (*sftp.Client, error) {
+	p, err := c.conn()
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.sftpClient()
+	if err != nil {
+		c.pool.invalidate(c.poolKey(), p)
+		return nil, &ConnectionError{Op: "sftp", Machine: c.name, Err: err}
+	}
+	return s, nil
+}
+
+// ReadFile reads the named remote file.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	s, err := c.sftpClient()
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.Open(path)
+	if err != nil {
+		return nil, mapSFTPError(err, path, "read")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, mapSFTPError(err, path, "read")
+	}
+	return data, nil
+}
+
+// WriteFile writes data to the named remote file, creating or truncating it.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	s, err := c.sftpClient()
+	if err != nil {
+		return err
+	}
+	f, err := s.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return mapSFTPError(err, path, "write")
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return mapSFTPError(err, path, "write")
+	}
+	if err := f.Close(); err != nil {
+		return mapSFTPError(err, path, "write")
+	}
+	if err := s.Chmod(path, perm); err != nil {
+		return mapSFTPError(err, path, "chmod")
+	}
+	return nil
+}
+
+// MkdirAll creates a remote directory and all parent directories.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	s, err := c.sftpClient()
+	if err != nil {
+		return err
+	}
+	if err := s.MkdirAll(path); err != nil {
+		return mapSFTPError(err, path, "mkdir")
+	}
+	if err := s.Chmod(path, perm); err != nil {
+		return mapSFTPError(err, path, "chmod")
+	}
+	return nil
+}
+
+// Remove removes the named remote file or empty directory.
+func (c *SSHConnection) Remove(path string) error {
+	s, err := c.sftpClient()
+	if err != nil {
+		return err
+	}
+	if err := s.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // Already gone
+		}
+		return mapSFTPError(err, path, "remove")
+	}
+	return nil
+}
+
+// RemoveAll removes the named remote file or directory and any children.
+func (c *SSHConnection) RemoveAll(path string) error {
+	s, err := c.sftpClient()
+	if err != nil {
+		return err
+	}
+	if err := s.RemoveAll(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return mapSFTPError(err, path, "remove")
+	}
+	return nil
+}
+
+// Stat returns file info for the named remote file.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	s, err := c.sftpClient()
+	if err != nil {
+		return nil, err
+	}
+	fi, err := s.Stat(path)
+	if err != nil {
+		return nil, mapSFTPError(err, path, "stat")
+	}
+	return FromOSFileInfo(fi), nil
+}
+
+// Glob returns the names of all remote files matching the pattern.
+// Results are sorted, matching filepath.Glob.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	s, err := c.sftpClient()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the remote path exists.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	_, err := c.Stat(path)
+	if err != nil {
+		var nf *NotFoundError
+		if errors.As(err, &nf) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Exec runs a remote command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.run(buildRemoteCommand("", nil, cmd, args))
+}
+
+// ExecDir runs a remote command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.run(buildRemoteCommand(dir, nil, cmd, args))
+}
+
+// ExecEnv runs a remote command with additional environment variables.
+// Variables are passed inline via env(1) rather than SSH setenv requests,
+// since most sshd configurations reject AcceptEnv for arbitrary names.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	return c.run(buildRemoteCommand("", env, cmd, args))
+}
+
+// RemoteExitError is returned when a remote command exits non-zero.
+// It mirrors *exec.ExitError so callers can inspect the status.
+type RemoteExitError struct {
+	Command string
+	Status  int
+}
+
+func (e *RemoteExitError) Error() string {
+	return fmt.Sprintf("remote command exited with status %d: %s", e.Status, e.Command)
+}
+
+// ExitCode returns the remote exit status.
+func (e *RemoteExitError) ExitCode() int {
+	return e.Status
+}
+
+// run executes a shell command line on the remote host, enforcing the
+// command timeout. On timeout the session is closed and the remote process
+// receives SIGKILL where the server supports signals.
+func (c *SSHConnection) run(command string) ([]byte, error) {
+	p, err := c.conn()
+	if err != nil {
+		return nil, err
+	}
+	session, err := p.client.NewSession()
+	if err != nil {
+		// A dead client surfaces here first; drop it so the next call redials.
+		c.pool.invalidate(c.poolKey(), p)
+		return nil, &ConnectionError{Op: "exec", Machine: c.name, Err: err}
+	}
+	defer session.Close()
+
+	// Stdout and stderr are copied by separate goroutines; share one
+	// locked buffer so the combined output stays intact.
+	var out syncBuffer
+	session.Stdout = &out
+	session.Stderr = &out
+
+	timeout := c.cfg.CommandTimeout
+	if timeout <= 0 {
+		timeout = DefaultSSHCommandTimeout
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(command) }()
+
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		return out.Bytes(), &ConnectionError{
+			Op:      "exec",
+			Machine: c.name,
+			Err:     fmt.Errorf("command timed out after %s: %s", timeout, command),
+		}
+	}
+
+	if err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return out.Bytes(), &RemoteExitError{Command: command, Status: exitErr.ExitStatus()}
+		}
+		return out.Bytes(), &ConnectionError{Op: "exec", Machine: c.name, Err: err}
+	}
+	return out.Bytes(), nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// Bytes returns a copy of the buffered output.
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// TmuxNewSession creates a new detached tmux session on the remote host.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	return c.tmuxErr(c.Exec("tmux", args...))
+}
+
+// TmuxKillSession terminates a remote tmux session.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	return c.tmuxErr(c.Exec("tmux", "kill-session", "-t", "="+name))
+}
+
+// TmuxSendKeys sends literal text followed by Enter to a remote tmux session.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	if err := c.tmuxErr(c.Exec("tmux", "send-keys", "-t", session, "-l", keys)); err != nil {
+		return err
+	}
+	return c.tmuxErr(c.Exec("tmux", "send-keys", "-t", session, "Enter"))
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	out, err := c.Exec("tmux", "capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+	if err != nil {
+		return "", c.tmuxErr(out, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// TmuxHasSession returns true if the remote tmux session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	out, err := c.Exec("tmux", "has-session", "-t", "="+name)
+	if err != nil {
+		var exitErr *RemoteExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
+		return false, c.tmuxErr(out, err)
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.Exec("tmux", "list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if isNoTmuxServer(string(out)) {
+			return nil, nil
+		}
+		return nil, c.tmuxErr(out, err)
+	}
+	var sessions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+	return sessions, nil
+}
+
+// tmuxErr attaches tmux's stderr to a failed remote tmux command.
+func (c *SSHConnection) tmuxErr(out []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("tmux on %s: %s: %w", c.name, msg, err)
+	}
+	return fmt.Errorf("tmux on %s: %w", c.name, err)
+}
+
+func isNoTmuxServer(out string) bool {
+	return strings.Contains(out, "no server running") ||
+		strings.Contains(out, "error connecting to")
+}
+
+// mapSFTPError converts SFTP errors to the package's error types.
+func mapSFTPError(err error, path, op string) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return &NotFoundError{Path: path}
+	case errors.Is(err, fs.ErrPermission):
+		return &PermissionError{Path: path, Op: op}
+	default:
+		return err
+	}
+}
+
+// buildRemoteCommand renders a POSIX shell command line with every argument
+// single-quoted, optionally changing directory and setting environment first.
+func buildRemoteCommand(dir string, env map[string]string, cmd string, args []string) string {
+	var b strings.Builder
+	if dir != "" {
+		b.WriteString("cd ")
+		b.WriteString(shellQuote(dir))
+		b.WriteString(" && ")
+	}
+	if len(env) > 0 {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("env")
+		for _, k := range keys {
+			b.WriteString(" ")
+			b.WriteString(shellQuote(k + "=" + env[k]))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString(shellQuote(cmd))
+	for _, a := range args {
+		b.WriteString(" ")
+		b.WriteString(shellQuote(a))
+	}
+	return b.String()
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./=:@%+,", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// parseSSHHost splits "[user@]host[:port]" into user and dial address.
+func parseSSHHost(host string) (user, addr string) {
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		user = host[:at]
+		host = host[at+1:]
+	}
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	return user, host
+}
+
+// clientConfig builds the ssh.ClientConfig: auth methods and host key check.
+// The returned release func closes any ssh-agent connections opened during
+// the handshake; call it once the handshake is done.
+func (cfg SSHConfig) clientConfig() (*ssh.ClientConfig, string, func(), error) {
+	user, addr := parseSSHHost(cfg.Host)
+
+	var auths []ssh.AuthMethod
+	if cfg.KeyPath != "" {
+		key, err := os.ReadFile(expandHome(cfg.KeyPath)) //nolint:gosec // G304: key path from machine registry
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("reading ssh key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("parsing ssh key %s: %w", cfg.KeyPath, err)
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	}
+
+	// Agent signers sign over the agent socket, so it stays open until the
+	// handshake completes.
+	var agentConns []net.Conn
+	release := func() {
+		for _, conn := range agentConns {
+			_ = conn.Close()
+		}
+		agentConns = nil
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		auths = append(auths, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			conn, err := net.Dial("unix", sock)
+			if err != nil {
+				return nil, err
+			}
+			agentConns = append(agentConns, conn)
+			return agent.NewClient(conn).Signers()
+		}))
+	}
+	if len(auths) == 0 {
+		return nil, "", nil, fmt.Errorf("no ssh credentials: set key_path or run ssh-agent")
+	}
+
+	hostKeyCallback, err := cfg.hostKeyCallback()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            auths,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         cfg.dialTimeout(),
+	}, addr, release, nil
+}
+
+func (cfg SSHConfig) dialTimeout() time.Duration {
+	if cfg.DialTimeout > 0 {
+		return cfg.DialTimeout
+	}
+	return DefaultSSHDialTimeout
+}
+
+// dialSSH connects to addr and runs the SSH handshake, both within timeout.
+// ssh.ClientConfig.Timeout only covers the TCP connect, so a server that
+// accepts and then stalls would otherwise hang the handshake forever.
+func dialSSH(addr string, cfg *ssh.ClientConfig, timeout time.Duration) (*ssh.Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// hostKeyCallback verifies the server against a pinned key or known_hosts.
+// There is deliberately no insecure fallback: an unknown host is an error.
+func (cfg SSHConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if cfg.HostKey != "" {
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("parsing pinned host key: %w", err)
+		}
+		return ssh.FixedHostKey(pub), nil
+	}
+
+	path := cfg.KnownHostsPath
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating known_hosts: %w", err)
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("loading known_hosts (add the host with ssh-keyscan or set host_key): %w", err)
+	}
+	return cb, nil
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// sshPool shares one *ssh.Client (and lazily one *sftp.Client) per machine.
+type sshPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledSSH
+	dialing map[string]*sync.Mutex // serializes connects per machine
+}
+
+type pooledSSH struct {
+	client *ssh.Client
+
+	sftpOnce sync.Once
+	sftp     *sftp.Client
+	sftpErr  error
+}
+
+var defaultSSHPool = newSSHPool()
+
+func newSSHPool() *sshPool {
+	return &sshPool{
+		clients: make(map[string]*pooledSSH),
+		dialing: make(map[string]*sync.Mutex),
+	}
+}
+
+func (p *pooledSSH) sftpClient() (*sftp.Client, error) {
+	p.sftpOnce.Do(func() {
+		p.sftp, p.sftpErr = sftp.NewClient(p.client)
+	})
+	return p.sftp, p.sftpErr
+}
+
+// close closes the client and its SFTP session. Closing the connection
+// first makes an in-flight sftpClient fail fast; the empty Do then waits for
+// it to finish, so p.sftp is safe to read and a late SFTP client isn't leaked.
+func (p *pooledSSH) close() error {
+	err := p.client.Close()
+	p.sftpOnce.Do(func() {})
+	if p.sftp != nil {
+		_ = p.sftp.Close()
+	}
+	return err
+}
+
+// alive reports whether the client answers a keepalive within timeout.
+func (p *pooledSSH) alive(timeout time.Duration) bool {
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := p.client.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err == nil
+	case <-timer.C:
+		return false
+	}
+}
+
+// get returns a live pooled client for key, dialing a new one if the pool is
+// empty or the cached client no longer answers keepalives. The keepalive and
+// the dial hold only key's dial lock, never the pool lock, so one unreachable
+// machine does not stall operations on the others.
+func (p *sshPool) get(key string, cfg SSHConfig) (*pooledSSH, error) {
+	p.mu.Lock()
+	dial, ok := p.dialing[key]
+	if !ok {
+		dial = &sync.Mutex{}
+		p.dialing[key] = dial
+	}
+	p.mu.Unlock()
+
+	dial.Lock()
+	defer dial.Unlock()
+
+	p.mu.Lock()
+	existing := p.clients[key]
+	p.mu.Unlock()
+
+	if existing != nil {
+		if existing.alive(sshKeepaliveTimeout) {
+			return existing, nil
+		}
+		p.invalidate(key, existing)
+	}
+
+	clientCfg, addr, release, err := cfg.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dialSSH(addr, clientCfg, cfg.dialTimeout())
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &pooledSSH{client: client}
+	p.mu.Lock()
+	p.clients[key] = entry
+	p.mu.Unlock()
+	return entry, nil
+}
+
+// invalidate drops entry from the pool if it is still the current client.
+func (p *sshPool) invalidate(key string, entry *pooledSSH) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients[key] == entry {
+		_ = entry.close()
+		delete(p.clients, key)
+	}
+}
+
+func (p *sshPool) close(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.clients[key]
+	if !ok {
+		return nil
+	}
+	delete(p.clients, key)
+	return entry.close()
+}
+
+// closeAll closes every pooled client.
+func (p *sshPool) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, entry := range p.clients {
+		_ = entry.close()
+		delete(p.clients, key)
+	}
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
@@ -0,0 +1,471 @@
+package connection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer is an in-process SSH server supporting "exec" requests
+// (run via sh -c) and the "sftp" subsystem.
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.Signer
+	conns   atomic.Int32
+}
+
+func newTestSSHServer(t *testing.T, clientKey ssh.PublicKey) *testSSHServer {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating host key: %v", err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatalf("host signer: %v", err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	cfg.AddHostKey(hostKey)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &testSSHServer{addr: ln.Addr().String(), hostKey: hostKey}
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveConn(nc, cfg)
+		}
+	}()
+	return s
+}
+
+func (s *testSSHServer) serveConn(nc net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		return
+	}
+	s.conns.Add(1)
+	go func() {
+		for req := range reqs {
+			if req.WantReply {
+				_ = req.Reply(req.Type == "keepalive@openssh.com", nil)
+			}
+		}
+	}()
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			_ = nch.Reject(ssh.UnknownChannelType, "session only")
+			continue
+		}
+		ch, chReqs, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSession(ch, chReqs)
+	}
+}
+
+func serveSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		switch req.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+
+			cmd := exec.Command("sh", "-c", payload.Command)
+			cmd.Stdout = ch
+			cmd.Stderr = ch.Stderr()
+			status := uint32(0)
+			if err := cmd.Run(); err != nil {
+				status = 1
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					status = uint32(exitErr.ExitCode())
+				}
+			}
+			buf := make([]byte, 4)
+			binary.BigEndian.PutUint32(buf, status)
+			_, _ = ch.SendRequest("exit-status", false, buf)
+			return
+		case "subsystem":
+			var payload struct{ Name string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil || payload.Name != "sftp" {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+			server, err := sftp.NewServer(ch)
+			if err != nil {
+				return
+			}
+			if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
+				return
+			}
+			return
+		case "signal":
+			// Timeout path: client closes the session; nothing to do.
+		default:
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+// newTestSSHConnection starts a server and returns a connection to it using
+// a freshly generated client key and a pinned host key.
+func newTestSSHConnection(t *testing.T) (*SSHConnection, *testSSHServer) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating client key: %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("client public key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("marshaling client key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("writing client key: %v", err)
+	}
+
+	srv := newTestSSHServer(t, sshPub)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	conn := NewSSHConnection("buildbox", SSHConfig{
+		Host:    "tester@" + srv.addr,
+		KeyPath: keyPath,
+		HostKey: string(ssh.MarshalAuthorizedKey(srv.hostKey.PublicKey())),
+	})
+	conn.pool = newSSHPool()
+	t.Cleanup(conn.pool.closeAll)
+	return conn, srv
+}
+
+func TestSSHConnectionExec(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	dir := t.TempDir()
+
+	if conn.IsLocal() || conn.Name() != "buildbox" {
+		t.Fatalf("unexpected identity: %s local=%v", conn.Name(), conn.IsLocal())
+	}
+
+	out, err := conn.Exec("echo", "hello world", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "hello world it's" {
+		t.Errorf("Exec output = %q", got)
+	}
+
+	out, err = conn.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != dir {
+		t.Errorf("ExecDir pwd = %q, want %q", got, dir)
+	}
+
+	out, err = conn.ExecEnv(map[string]string{"GT_TEST": "a b"}, "sh", "-c", "echo $GT_TEST")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "a b" {
+		t.Errorf("ExecEnv output = %q", got)
+	}
+
+	out, err = conn.Exec("sh", "-c", "echo oops >&2; exit 7")
+	var exitErr *RemoteExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 7 {
+		t.Fatalf("expected RemoteExitError status 7, got %v", err)
+	}
+	if !strings.Contains(string(out), "oops") {
+		t.Errorf("stderr not captured: %q", out)
+	}
+}
+
+func TestSSHConnectionFiles(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	root := t.TempDir()
+
+	nested := filepath.Join(root, "a", "b")
+	if err := conn.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	path := filepath.Join(nested, "mail.json")
+	if err := conn.WriteFile(path, []byte(`{"to":"mayor"}`), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	data, err := conn.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != `{"to":"mayor"}` {
+		t.Errorf("ReadFile = %q", data)
+	}
+
+	fi, err := conn.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Name() != "mail.json" || fi.Size() != int64(len(data)) || fi.IsDir() || fi.Mode().Perm() != 0600 {
+		t.Errorf("Stat = %+v", fi)
+	}
+
+	if err := conn.WriteFile(filepath.Join(nested, "other.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	matches, err := conn.Glob(filepath.Join(nested, "*.json"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 2 || filepath.Base(matches[0]) != "mail.json" || filepath.Base(matches[1]) != "other.json" {
+		t.Errorf("Glob = %v", matches)
+	}
+
+	missing := filepath.Join(root, "missing")
+	if _, err := conn.ReadFile(missing); !errors.As(err, new(*NotFoundError)) {
+		t.Errorf("ReadFile missing: expected NotFoundError, got %v", err)
+	}
+	if ok, err := conn.Exists(missing); err != nil || ok {
+		t.Errorf("Exists missing = %v, %v", ok, err)
+	}
+	if ok, err := conn.Exists(path); err != nil || !ok {
+		t.Errorf("Exists = %v, %v", ok, err)
+	}
+
+	if err := conn.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := conn.Remove(path); err != nil {
+		t.Errorf("Remove of missing file should succeed, got %v", err)
+	}
+	if err := conn.RemoveAll(filepath.Join(root, "a")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a")); !os.IsNotExist(err) {
+		t.Errorf("RemoveAll left directory behind: %v", err)
+	}
+}
+
+func TestSSHConnectionPooling(t *testing.T) {
+	conn, srv := newTestSSHConnection(t)
+
+	other := NewSSHConnection(conn.name, conn.cfg)
+	other.pool = conn.pool
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); _, _ = conn.Exec("true") }()
+		go func() { defer wg.Done(); _, _ = other.Exists("/") }()
+	}
+	wg.Wait()
+
+	if got := srv.conns.Load(); got != 1 {
+		t.Errorf("server saw %d connections, want 1 (pooled)", got)
+	}
+
+	// Closing drops the pooled client; the next call redials.
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := other.Exec("true"); err != nil {
+		t.Fatalf("Exec after Close: %v", err)
+	}
+	if got := srv.conns.Load(); got != 2 {
+		t.Errorf("server saw %d connections after reconnect, want 2", got)
+	}
+}
+
+func TestSSHConnectionCloseDuringSFTPOpen(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+
+	// Closing a pooled client races its lazy SFTP open. Close must wait for
+	// the open and fail it fast, never read p.sftp mid-open or hang.
+	for i := 0; i < 10; i++ {
+		entry, err := conn.pool.get(conn.poolKey(), conn.cfg)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		opened := make(chan struct{})
+		go func() {
+			defer close(opened)
+			_, _ = entry.sftpClient()
+		}()
+		_ = conn.pool.close(conn.poolKey())
+		<-opened
+	}
+}
+
+func TestSSHConnectionHostKeyMismatch(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+
+	_, wrongPriv, _ := ed25519.GenerateKey(rand.Reader)
+	wrong, _ := ssh.NewSignerFromKey(wrongPriv)
+	conn.cfg.HostKey = string(ssh.MarshalAuthorizedKey(wrong.PublicKey()))
+
+	_, err := conn.Exec("true")
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) || connErr.Op != "connect" {
+		t.Fatalf("expected connect error for mismatched host key, got %v", err)
+	}
+}
+
+func TestSSHConnectionKnownHosts(t *testing.T) {
+	conn, srv := newTestSSHConnection(t)
+	conn.cfg.HostKey = ""
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	conn.cfg.KnownHostsPath = knownHosts
+
+	// Unknown host is rejected.
+	if err := os.WriteFile(knownHosts, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec("true"); err == nil {
+		t.Fatal("expected unknown host to be rejected")
+	}
+
+	host, port, _ := net.SplitHostPort(srv.addr)
+	line := "[" + host + "]:" + port + " " + string(ssh.MarshalAuthorizedKey(srv.hostKey.PublicKey()))
+	if err := os.WriteFile(knownHosts, []byte(line), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec("true"); err != nil {
+		t.Fatalf("Exec with known host: %v", err)
+	}
+}
+
+func TestSSHConnectionCommandTimeout(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	conn.cfg.CommandTimeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := conn.Exec("sleep", "5")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("timeout took %s", elapsed)
+	}
+}
+
+func TestSSHConnectionStalledHandshake(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+
+	// A server that accepts TCP but never speaks SSH.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = nc.Close() })
+		}
+	}()
+
+	stalled := NewSSHConnection("stalled", SSHConfig{
+		Host:        "tester@" + ln.Addr().String(),
+		KeyPath:     conn.cfg.KeyPath,
+		HostKey:     conn.cfg.HostKey,
+		DialTimeout: 2 * time.Second,
+	})
+	stalled.pool = conn.pool
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		_, err := stalled.Exec("true")
+		done <- err
+	}()
+
+	// The stalled dial must not hold up other machines in the same pool.
+	if _, err := conn.Exec("true"); err != nil {
+		t.Fatalf("Exec on healthy machine: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("healthy machine waited %s behind the stalled dial", elapsed)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected handshake timeout error")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("handshake did not time out")
+	}
+}
+
+func TestBuildRemoteCommand(t *testing.T) {
+	got := buildRemoteCommand("/town root", map[string]string{"B": "2", "A": "x y"}, "gt", []string{"mail", "send", "it's"})
+	want := `cd '/town root' && env 'A=x y' B=2 gt mail send 'it'\''s'`
+	if got != want {
+		t.Errorf("buildRemoteCommand =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	t.Setenv("USER", "me")
+	tests := []struct {
+		in, user, addr string
+	}{
+		{"builder@box", "builder", "box:22"},
+		{"box:2222", "me", "box:2222"},
+		{"ci@10.0.0.5:2200", "ci", "10.0.0.5:2200"},
+	}
+	for _, tt := range tests {
+		user, addr := parseSSHHost(tt.in)
+		if user != tt.user || addr != tt.addr {
+			t.Errorf("parseSSHHost(%q) = %q, %q; want %q, %q", tt.in, user, addr, tt.user, tt.addr)
+		}
+	}
+}
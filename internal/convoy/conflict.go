@@ -0,0 +1,255 @@
+package convoy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// PathLabelPrefix marks an issue label as a path hint for conflict
+// prediction, e.g. "path:internal/refinery/" or "path:docs/*.md".
+const PathLabelPrefix = "path:"
+
+// sourceExtensions are file extensions that make a bare word in an issue
+// description (no directory) count as a path hint.
+var sourceExtensions = map[string]bool{
+	".go": true, ".md": true, ".toml": true, ".json": true, ".yaml": true, ".yml": true,
+	".sh": true, ".py": true, ".js": true, ".ts": true, ".tsx": true, ".rs": true,
+	".sql": true, ".proto": true, ".html": true, ".css": true, ".tmpl": true,
+}
+
+// WorkItem is an issue about to be dispatched, as far as conflict
+// prediction cares.
+type WorkItem struct {
+	ID          string
+	Title       string
+	Description string
+	Labels      []string
+}
+
+// Conflict is a predicted overlap between a work item and an active polecat
+// branch.
+type Conflict struct {
+	Polecat string   `json:"polecat"`
+	Issue   string   `json:"issue,omitempty"`
+	Branch  string   `json:"branch,omitempty"`
+	Files   []string `json:"files"`
+}
+
+// PathHints returns the paths a work item is likely to touch: "path:" labels
+// first, then file paths mentioned in its title and description.
+func PathHints(w WorkItem) []string {
+	seen := make(map[string]bool)
+	var hints []string
+	add := func(h string) {
+		if h != "" && !seen[h] {
+			seen[h] = true
+			hints = append(hints, h)
+		}
+	}
+
+	for _, label := range w.Labels {
+		if strings.HasPrefix(label, PathLabelPrefix) {
+			add(strings.TrimPrefix(strings.TrimPrefix(label, PathLabelPrefix), "./"))
+		}
+	}
+	for _, word := range strings.Fields(w.Title + "\n" + w.Description) {
+		if h, ok := pathHint(word); ok {
+			add(h)
+		}
+	}
+	return hints
+}
+
+// pathHint reports whether a word from free text looks like a repo path.
+func pathHint(word string) (string, bool) {
+	word = strings.Trim(word, "`'\"()[]{}<>,;:!?*")
+	word = strings.TrimSuffix(word, ".")
+	word = strings.TrimPrefix(word, "./")
+	if word == "" || strings.Contains(word, "://") || strings.HasPrefix(word, "/") || strings.HasPrefix(word, "~") {
+		return "", false
+	}
+	if i := strings.Index(word, ":"); i > 0 {
+		word = word[:i] // file.go:123
+	}
+	if strings.Contains(word, "/") {
+		// Role addresses (gastown/polecats/Toast) and the like are paths in
+		// form only; they simply won't match any changed file.
+		return word, !strings.Contains(word, "//")
+	}
+	return word, sourceExtensions[path.Ext(word)] && len(word) > len(path.Ext(word))
+}
+
+// MatchesPath reports whether a changed file falls under a path hint. A hint
+// can be an exact path, a directory (with or without a trailing slash), a
+// glob, or a bare file name that matches in any directory.
+func MatchesPath(hint, file string) bool {
+	if strings.ContainsAny(hint, "*?[") {
+		if ok, _ := path.Match(hint, file); ok {
+			return true
+		}
+		ok, _ := path.Match(hint, path.Base(file))
+		return ok && !strings.Contains(hint, "/")
+	}
+	if strings.HasSuffix(hint, "/") {
+		return strings.HasPrefix(file, hint)
+	}
+	if strings.Contains(hint, "/") {
+		return file == hint || strings.HasPrefix(file, hint+"/")
+	}
+	return path.Base(file) == hint
+}
+
+// PredictConflicts matches path hints against the files each active polecat
+// branch has changed. Branches working on selfIssue are ignored so that
+// re-dispatching an issue doesn't conflict with its own earlier attempt.
+func PredictConflicts(hints []string, active []*polecat.BranchChanges, selfIssue string) []Conflict {
+	if len(hints) == 0 {
+		return nil
+	}
+	var conflicts []Conflict
+	for _, b := range active {
+		if selfIssue != "" && b.Issue == selfIssue {
+			continue
+		}
+		var files []string
+		for _, f := range b.Files {
+			for _, h := range hints {
+				if MatchesPath(h, f) {
+					files = append(files, f)
+					break
+				}
+			}
+		}
+		if len(files) > 0 {
+			conflicts = append(conflicts, Conflict{Polecat: b.Name, Issue: b.Issue, Branch: b.Branch, Files: files})
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return len(conflicts[i].Files) > len(conflicts[j].Files)
+	})
+	return conflicts
+}
+
+// ConflictPredictor predicts conflicts between work about to be dispatched to
+// a rig and the branches its polecats are working on. Active branches and
+// prior MRs are read once, so one predictor can check many issues.
+type ConflictPredictor struct {
+	polecats *polecat.Manager
+	beads    *beads.Beads
+	active   []*polecat.BranchChanges
+
+	// priorBranches maps a source issue to the branches of its earlier MRs.
+	// Loaded on first use.
+	priorBranches map[string][]string
+}
+
+// NewConflictPredictor snapshots the active polecat branches of a rig.
+func NewConflictPredictor(r *rig.Rig) (*ConflictPredictor, error) {
+	mgr := polecat.NewManager(r, nil, nil) // nil git/tmux: read-only listing
+	active, err := mgr.ActiveChanges()
+	if err != nil {
+		return nil, err
+	}
+	return &ConflictPredictor{
+		polecats: mgr,
+		beads:    beads.New(r.BeadsPath()),
+		active:   active,
+	}, nil
+}
+
+// Active returns the polecat branches the predictor compares against.
+func (p *ConflictPredictor) Active() []*polecat.BranchChanges {
+	return p.active
+}
+
+// Check predicts which active polecat branches w is likely to conflict with.
+// Besides the hints from PathHints, files changed by earlier MRs for the same
+// issue (a rework, or a re-sling after a failed merge) count as hints.
+func (p *ConflictPredictor) Check(w WorkItem) []Conflict {
+	if len(p.active) == 0 {
+		return nil
+	}
+	hints := PathHints(w)
+	for _, branch := range p.priorMRBranches(w.ID) {
+		files, err := p.polecats.BranchFiles(branch)
+		if err != nil {
+			continue // Branch deleted after merge/reject
+		}
+		hints = append(hints, files...)
+	}
+	return PredictConflicts(hints, p.active, w.ID)
+}
+
+func (p *ConflictPredictor) priorMRBranches(issueID string) []string {
+	if issueID == "" || p.beads == nil {
+		return nil
+	}
+	if p.priorBranches == nil {
+		p.priorBranches = make(map[string][]string)
+		mrs, err := p.beads.List(beads.ListOptions{
+			Label:    "gt:merge-request",
+			Status:   "all",
+			Priority: -1, // No priority filter
+		})
+		if err != nil {
+			return nil
+		}
+		for _, mr := range mrs {
+			if fields := beads.ParseMRFields(mr); fields != nil && fields.SourceIssue != "" && fields.Branch != "" {
+				p.priorBranches[fields.SourceIssue] = append(p.priorBranches[fields.SourceIssue], fields.Branch)
+			}
+		}
+	}
+	return p.priorBranches[issueID]
+}
+
+// LoadConflictPolicy returns the rig's dispatch.conflict_policy, defaulting
+// to warn when unset or unreadable.
+func LoadConflictPolicy(rigPath string) string {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.Dispatch == nil || settings.Dispatch.ConflictPolicy == "" {
+		return config.ConflictPolicyWarn
+	}
+	return settings.Dispatch.ConflictPolicy
+}
+
+// SerializeAfter makes issueID depend on the issues the conflicting polecats
+// are working on, so it becomes ready only once they close. Returns the
+// issues it now waits for.
+func SerializeAfter(b *beads.Beads, issueID string, conflicts []Conflict) ([]string, error) {
+	var after []string
+	for _, c := range conflicts {
+		if c.Issue == "" || c.Issue == issueID {
+			continue
+		}
+		if err := b.AddDependency(issueID, c.Issue); err != nil {
+			return after, fmt.Errorf("adding dependency %s -> %s: %w", issueID, c.Issue, err)
+		}
+		after = append(after, c.Issue)
+	}
+	return after, nil
+}
+
+// DescribeConflicts renders conflicts as a one-line annotation, e.g.
+// "likely conflicts with Toast (gt-abc: internal/cmd/sling.go, +2 more)".
+func DescribeConflicts(conflicts []Conflict) string {
+	parts := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		files := c.Files[0]
+		if len(c.Files) > 1 {
+			files += fmt.Sprintf(", +%d more", len(c.Files)-1)
+		}
+		if c.Issue != "" {
+			files = c.Issue + ": " + files
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", c.Polecat, files))
+	}
+	return "likely conflicts with " + strings.Join(parts, ", ")
+}
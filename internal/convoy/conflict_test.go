@@ -0,0 +1,136 @@
+package convoy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestPathHints(t *testing.T) {
+	w := WorkItem{
+		ID:    "gt-abc",
+		Title: "Fix retry loop in sling.go",
+		Description: "The bug is in `internal/cmd/sling_batch.go:120` and probably " +
+			"internal/polecat/. See https://example.com/a/b and docs/reference.md.\n" +
+			"Dispatched by gastown/crew/max, version 1.2 or so.",
+		Labels: []string{"gt:task", "path:internal/convoy/", "path:./docs/*.md"},
+	}
+
+	want := []string{
+		"internal/convoy/",
+		"docs/*.md",
+		"sling.go",
+		"internal/cmd/sling_batch.go",
+		"internal/polecat/",
+		"docs/reference.md",
+		"gastown/crew/max",
+	}
+	if got := PathHints(w); !reflect.DeepEqual(got, want) {
+		t.Errorf("PathHints() =\n  %v\nwant\n  %v", got, want)
+	}
+}
+
+func TestMatchesPath(t *testing.T) {
+	tests := []struct {
+		hint, file string
+		want       bool
+	}{
+		{"internal/cmd/sling.go", "internal/cmd/sling.go", true},
+		{"internal/cmd/sling.go", "internal/cmd/sling_batch.go", false},
+		{"internal/cmd", "internal/cmd/sling.go", true},
+		{"internal/cmd", "internal/cmdline/x.go", false},
+		{"internal/cmd/", "internal/cmd/sub/x.go", true},
+		{"sling.go", "internal/cmd/sling.go", true},
+		{"sling.go", "internal/cmd/unsling.go", false},
+		{"docs/*.md", "docs/reference.md", true},
+		{"docs/*.md", "docs/design/x.md", false},
+		{"*_test.go", "internal/cmd/sling_test.go", true},
+	}
+	for _, tt := range tests {
+		if got := MatchesPath(tt.hint, tt.file); got != tt.want {
+			t.Errorf("MatchesPath(%q, %q) = %v, want %v", tt.hint, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPredictConflicts(t *testing.T) {
+	active := []*polecat.BranchChanges{
+		{Name: "Toast", Issue: "gt-1", Files: []string{"internal/cmd/sling.go", "README.md"}},
+		{Name: "Nux", Issue: "gt-2", Files: []string{"internal/refinery/batch.go", "internal/refinery/score.go"}},
+		{Name: "Self", Issue: "gt-new", Files: []string{"internal/refinery/engineer.go"}},
+	}
+
+	got := PredictConflicts([]string{"internal/refinery/", "sling.go"}, active, "gt-new")
+	if len(got) != 2 {
+		t.Fatalf("PredictConflicts() = %+v, want 2 conflicts", got)
+	}
+	if got[0].Polecat != "Nux" || len(got[0].Files) != 2 {
+		t.Errorf("first conflict = %+v, want Nux with 2 files (most overlap first)", got[0])
+	}
+	if got[1].Polecat != "Toast" || !reflect.DeepEqual(got[1].Files, []string{"internal/cmd/sling.go"}) {
+		t.Errorf("second conflict = %+v, want Toast on sling.go", got[1])
+	}
+
+	if got := PredictConflicts(nil, active, ""); got != nil {
+		t.Errorf("no hints should predict nothing, got %+v", got)
+	}
+
+	desc := DescribeConflicts(got)
+	if !strings.HasPrefix(desc, "likely conflicts with Nux (gt-2: internal/refinery/batch.go, +1 more)") {
+		t.Errorf("DescribeConflicts() = %q", desc)
+	}
+}
+
+func TestHoldForConflicts(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	orig := predictConflictsFn
+	defer func() { predictConflictsFn = orig }()
+	var predicted int
+	predictConflictsFn = func(_, rigName string, w WorkItem) []Conflict {
+		predicted++
+		if rigName != "gastown" || w.ID != "gt-abc" {
+			t.Errorf("predicted for %s/%s", rigName, w.ID)
+		}
+		return []Conflict{{Polecat: "Toast", Files: []string{"go.mod"}}}
+	}
+
+	setPolicy := func(policy string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := `{"type": "rig-settings", "version": 1, "dispatch": {"conflict_policy": "` + policy + `"}}`
+		if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issue := trackedIssue{ID: "gt-abc", Status: "open", Description: "touches go.mod"}
+
+	// No settings: warn, so the issue is still fed.
+	if hold, note := holdForConflicts(townRoot, "gastown", issue); hold || !strings.Contains(note, "dispatching anyway") {
+		t.Errorf("default policy: hold=%v note=%q, want dispatch with warning", hold, note)
+	}
+
+	setPolicy("delay")
+	if hold, note := holdForConflicts(townRoot, "gastown", issue); !hold || !strings.Contains(note, "Toast") {
+		t.Errorf("delay policy: hold=%v note=%q, want hold naming Toast", hold, note)
+	}
+
+	// Serialize with no issue to wait on falls back to delaying.
+	setPolicy("serialize")
+	if hold, note := holdForConflicts(townRoot, "gastown", issue); !hold || !strings.HasPrefix(note, "delayed") {
+		t.Errorf("serialize policy: hold=%v note=%q, want delayed", hold, note)
+	}
+
+	setPolicy("off")
+	predicted = 0
+	if hold, note := holdForConflicts(townRoot, "gastown", issue); hold || note != "" || predicted != 0 {
+		t.Errorf("off policy: hold=%v note=%q predicted=%d, want no prediction", hold, note, predicted)
+	}
+}
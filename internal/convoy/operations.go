@@ -7,10 +7,15 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	beadsdk "github.com/steveyegge/beads"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -124,10 +129,13 @@ func runConvoyCheck(ctx context.Context, townRoot, convoyID, gtPath string) erro
 
 // trackedIssue holds basic info about an issue tracked by a convoy.
 type trackedIssue struct {
-	ID       string `json:"id"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
-	Priority int    `json:"priority"`
+	ID          string   `json:"id"`
+	Status      string   `json:"status"`
+	Assignee    string   `json:"assignee"`
+	Priority    int      `json:"priority"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
 }
 
 // feedNextReadyIssue finds the next ready issue in a convoy and dispatches it
@@ -144,6 +152,16 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 		return
 	}
 
+	// A convoy over its hard cap stops feeding until an override is approved.
+	gate, err := budget.NewGate(townRoot, caller)
+	if err != nil {
+		logger("%s: convoy %s: could not check budgets: %v", caller, convoyID, err)
+	}
+	if note := holdForBudget(gate, budget.Scope{Kind: budget.KindConvoy, Name: convoyID}); note != "" {
+		logger("%s: convoy %s: %s", caller, convoyID, note)
+		return
+	}
+
 	// Find the first ready issue (open, no assignee).
 	// Pick the first match, which is typically the highest priority.
 	for _, issue := range tracked {
@@ -163,6 +181,20 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 			continue
 		}
 
+		// Hold back work that is likely to conflict with an active polecat
+		// branch, per the rig's conflict policy, and try the next issue.
+		if hold, note := holdForConflicts(townRoot, rig, issue); note != "" {
+			logger("%s: convoy %s: %s %s", caller, convoyID, issue.ID, note)
+			if hold {
+				continue
+			}
+		}
+
+		if note := holdForBudget(gate, polecatBudgetScopes(gate, townRoot, rig)...); note != "" {
+			logger("%s: convoy %s: %s %s", caller, convoyID, issue.ID, note)
+			continue
+		}
+
 		logger("%s: convoy %s: feeding next ready issue %s to %s", caller, convoyID, issue.ID, rig)
 		if err := dispatchIssue(ctx, townRoot, issue.ID, rig, gtPath); err != nil {
 			logger("%s: convoy %s: dispatch %s failed: %s", caller, convoyID, issue.ID, util.FirstLine(err.Error()))
@@ -173,6 +205,82 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 	logger("%s: convoy %s: no ready issues to feed", caller, convoyID)
 }
 
+// predictConflictsFn is a seam for tests. Production snapshots the rig's
+// active polecat branches with a ConflictPredictor.
+var predictConflictsFn = func(townRoot, rigName string, w WorkItem) []Conflict {
+	p, err := NewConflictPredictor(&rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)})
+	if err != nil {
+		return nil
+	}
+	return p.Check(w)
+}
+
+// holdForConflicts applies the rig's conflict policy to an issue about to be
+// fed. It returns whether to hold the issue back and a note for the log
+// (empty when nothing was predicted).
+func holdForConflicts(townRoot, rigName string, issue trackedIssue) (bool, string) {
+	rigPath := filepath.Join(townRoot, rigName)
+	policy := LoadConflictPolicy(rigPath)
+	if policy == config.ConflictPolicyOff {
+		return false, ""
+	}
+	conflicts := predictConflictsFn(townRoot, rigName, WorkItem{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Labels:      issue.Labels,
+	})
+	if len(conflicts) == 0 {
+		return false, ""
+	}
+
+	desc := DescribeConflicts(conflicts)
+	switch policy {
+	case config.ConflictPolicyDelay:
+		return true, "delayed: " + desc
+	case config.ConflictPolicySerialize:
+		after, err := SerializeAfter(beads.New(rigPath), issue.ID, conflicts)
+		if err != nil {
+			return true, fmt.Sprintf("delayed (serialize failed: %v): %s", err, desc)
+		}
+		if len(after) == 0 {
+			return true, "delayed: " + desc // nothing to wait on by ID
+		}
+		return true, fmt.Sprintf("serialized after %s: %s", strings.Join(after, ", "), desc)
+	default:
+		return false, "dispatching anyway: " + desc
+	}
+}
+
+// polecatBudgetScopes returns the budget scopes a polecat spawned in rigName
+// spends against besides its convoy: the rig, the polecat role and the agent
+// the rig runs polecats on.
+func polecatBudgetScopes(gate *budget.Gate, townRoot, rigName string) []budget.Scope {
+	scopes := []budget.Scope{
+		{Kind: budget.KindRig, Name: rigName},
+		{Kind: budget.KindRole, Name: constants.RolePolecat},
+	}
+	if gate.Wants(budget.KindAgent) {
+		agent, _ := config.ResolveRoleAgentName(constants.RolePolecat, townRoot, filepath.Join(townRoot, rigName))
+		scopes = append(scopes, budget.Scope{Kind: budget.KindAgent, Name: agent})
+	}
+	return scopes
+}
+
+// holdForBudget returns a note for the log when a scope is over its hard cap
+// without an approved override (empty when dispatch may go ahead). Checking
+// opens the override request an operator approves to lift the cap.
+func holdForBudget(gate *budget.Gate, scopes ...budget.Scope) string {
+	breach, err := gate.Check(scopes...)
+	if err != nil {
+		return "" // budgets are best-effort here; gt sling checks again
+	}
+	if breach == nil {
+		return ""
+	}
+	return "held: " + breach.Error()
+}
+
 // getConvoyTrackedIssues returns issues tracked by a convoy with fresh status.
 // Uses SDK GetDependenciesWithMetadata filtered by tracks, then GetIssuesByIDs for current status.
 func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID string) []trackedIssue {
@@ -224,6 +332,9 @@ func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID
 			t.Status = string(fresh.Status)
 			t.Assignee = fresh.Assignee
 			t.Priority = fresh.Priority
+			t.Title = fresh.Title
+			t.Description = fresh.Description
+			t.Labels = fresh.Labels
 		} else if meta, ok := metaByID[id]; ok {
 			t.Status = meta.status
 			t.Assignee = meta.assignee
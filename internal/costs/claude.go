@@ -0,0 +1,224 @@
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// claudeExtractor reads Claude Code transcripts from
+// ~/.claude/projects/<workdir-with-dashes>/<session>.jsonl.
+type claudeExtractor struct{}
+
+// claudeTranscriptLine is one line of a Claude Code transcript.
+type claudeTranscriptLine struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	Message   *struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+func (claudeExtractor) SessionUsage(workDir string) (*Usage, error) {
+	projectDir, err := claudeProjectDir(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("getting project dir: %w", err)
+	}
+	transcript, err := latestFile(projectDir, ".jsonl")
+	if err != nil {
+		return nil, err
+	}
+	return parseClaudeTranscript(transcript)
+}
+
+// claudeProjectDir returns the Claude Code project directory for a working
+// directory. Claude Code stores transcripts under its config dir
+// (CLAUDE_CONFIG_DIR, default ~/.claude) in projects/<path-with-dashes>/.
+func claudeProjectDir(workDir string) (string, error) {
+	// Keep the leading slash - it becomes a leading dash in Claude's encoding
+	projectName := strings.ReplaceAll(workDir, "/", "-")
+
+	if configDir := os.Getenv("CLAUDE_CONFIG_DIR"); configDir != "" {
+		dir := filepath.Join(configDir, "projects", projectName)
+		if _, err := os.Stat(dir); err == nil {
+			return dir, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".claude", "projects", projectName), nil
+}
+
+// parseClaudeTranscript sums token usage from a transcript's assistant messages.
+func parseClaudeTranscript(path string) (*Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	scanner := newLineScanner(file)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg claudeTranscriptLine
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // Skip malformed lines
+		}
+
+		// Only process assistant messages with usage info
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+
+		// Capture the model (use first one found, they should all be the same)
+		if usage.Model == "" && msg.Message.Model != "" {
+			usage.Model = msg.Message.Model
+		}
+
+		u := msg.Message.Usage
+		usage.InputTokens += u.InputTokens
+		usage.CacheWriteTokens += u.CacheCreationInputTokens
+		usage.CacheReadTokens += u.CacheReadInputTokens
+		usage.OutputTokens += u.OutputTokens
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// newLineScanner returns a scanner sized for long JSONL lines.
+func newLineScanner(f *os.File) *bufio.Scanner {
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 4*1024*1024)
+	return scanner
+}
+
+// latestFile returns the most recently modified file with the given suffix
+// directly inside dir. Returns ErrNoSessionLog if there is none.
+func latestFile(dir, suffix string) (string, error) {
+	var latestPath string
+	var latestTime time.Time
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir {
+			return fs.SkipDir // Don't recurse into subdirectories
+		}
+		if !d.IsDir() && strings.HasSuffix(path, suffix) {
+			info, err := d.Info()
+			if err != nil {
+				return nil // Skip files we can't stat
+			}
+			if info.ModTime().After(latestTime) {
+				latestTime = info.ModTime()
+				latestPath = path
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w in %s", ErrNoSessionLog, dir)
+		}
+		return "", err
+	}
+	if latestPath == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoSessionLog, dir)
+	}
+	return latestPath, nil
+}
+
+// UsageEvent is the token usage of one model response.
+type UsageEvent struct {
+	Time time.Time
+	Usage
+}
+
+// ClaudeUsageSince returns the usage of every response Claude Code recorded
+// under a config dir (all projects) at or after since. Claude Code writes a
+// line per content block, repeating the response's usage, so responses are
+// counted once by message ID. Transcripts untouched since then are skipped.
+func ClaudeUsageSince(configDir string, since time.Time) ([]UsageEvent, error) {
+	root := filepath.Join(configDir, "projects")
+	var events []UsageEvent
+	seen := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return fs.SkipDir
+			}
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.ModTime().Before(since) {
+			return nil
+		}
+
+		file, err := os.Open(path) //nolint:gosec // G304: path is under the agent's own log dir
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		scanner := newLineScanner(file)
+		for scanner.Scan() {
+			var msg claudeTranscriptLine
+			if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+				continue
+			}
+			if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+				continue
+			}
+			ts, err := time.Parse(time.RFC3339Nano, msg.Timestamp)
+			if err != nil || ts.Before(since) {
+				continue
+			}
+			if id := msg.Message.ID; id != "" {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+			}
+			u := msg.Message.Usage
+			events = append(events, UsageEvent{Time: ts, Usage: Usage{
+				Model:            msg.Message.Model,
+				InputTokens:      u.InputTokens,
+				CacheWriteTokens: u.CacheCreationInputTokens,
+				CacheReadTokens:  u.CacheReadInputTokens,
+				OutputTokens:     u.OutputTokens,
+			}})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,132 @@
+package costs
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// codexExtractor reads Codex CLI rollouts from
+// $CODEX_HOME/sessions/YYYY/MM/DD/rollout-*.jsonl (default ~/.codex).
+// Rollouts aren't grouped by directory, so the newest one whose session
+// metadata names workDir is used.
+type codexExtractor struct{}
+
+// codexRolloutLine is one line of a Codex rollout.
+type codexRolloutLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		// session_meta and turn_context
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		// event_msg
+		Type string `json:"type"`
+		Info *struct {
+			TotalTokenUsage *struct {
+				InputTokens       int `json:"input_tokens"`
+				CachedInputTokens int `json:"cached_input_tokens"`
+				OutputTokens      int `json:"output_tokens"`
+			} `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+// codexMaxRollouts bounds how many recent rollouts are searched for workDir.
+const codexMaxRollouts = 200
+
+func (codexExtractor) SessionUsage(workDir string) (*Usage, error) {
+	home := os.Getenv("CODEX_HOME")
+	if home == "" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		home = filepath.Join(userHome, ".codex")
+	}
+	sessionsDir := filepath.Join(home, "sessions")
+
+	type rollout struct {
+		path    string
+		modTime time.Time
+	}
+	var rollouts []rollout
+	err := filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || !strings.HasPrefix(name, "rollout-") || !strings.HasSuffix(name, ".jsonl") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			rollouts = append(rollouts, rollout{path, info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sort.Slice(rollouts, func(i, j int) bool {
+		return rollouts[i].modTime.After(rollouts[j].modTime)
+	})
+	if len(rollouts) > codexMaxRollouts {
+		rollouts = rollouts[:codexMaxRollouts]
+	}
+
+	for _, r := range rollouts {
+		usage, cwd, err := parseCodexRollout(r.path)
+		if err != nil {
+			continue
+		}
+		if filepath.Clean(cwd) == filepath.Clean(workDir) {
+			return usage, nil
+		}
+	}
+	return nil, ErrNoSessionLog
+}
+
+// parseCodexRollout returns a rollout's usage and working directory.
+// token_count events carry running totals, so the last one wins.
+func parseCodexRollout(path string) (*Usage, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	var cwd string
+	scanner := newLineScanner(file)
+	for scanner.Scan() {
+		var line codexRolloutLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines
+		}
+		switch line.Type {
+		case "session_meta", "turn_context":
+			if cwd == "" {
+				cwd = line.Payload.CWD
+			}
+			if usage.Model == "" {
+				usage.Model = line.Payload.Model
+			}
+		case "event_msg":
+			if line.Payload.Type != "token_count" || line.Payload.Info == nil || line.Payload.Info.TotalTokenUsage == nil {
+				continue
+			}
+			total := line.Payload.Info.TotalTokenUsage
+			// Codex counts cached tokens inside input_tokens.
+			usage.InputTokens = total.InputTokens - total.CachedInputTokens
+			usage.CacheReadTokens = total.CachedInputTokens
+			usage.OutputTokens = total.OutputTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return usage, cwd, nil
+}
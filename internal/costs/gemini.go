@@ -0,0 +1,69 @@
+package costs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// geminiExtractor reads Gemini CLI chat recordings from
+// ~/.gemini/tmp/<sha256 of project root>/chats/session-*.json.
+type geminiExtractor struct{}
+
+// geminiChat is a Gemini CLI chat recording.
+type geminiChat struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+func (geminiExtractor) SessionUsage(workDir string) (*Usage, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(workDir))
+	chatsDir := filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(hash[:]), "chats")
+
+	chat, err := latestFile(chatsDir, ".json")
+	if err != nil {
+		return nil, err
+	}
+	return parseGeminiChat(chat)
+}
+
+// parseGeminiChat sums token usage from a chat recording's model turns.
+func parseGeminiChat(path string) (*Usage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the agent's own log dir
+	if err != nil {
+		return nil, err
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{}
+	for _, m := range chat.Messages {
+		if m.Type != "gemini" || m.Tokens == nil {
+			continue
+		}
+		if usage.Model == "" {
+			usage.Model = m.Model
+		}
+		// Gemini counts cached tokens inside the prompt; thoughts bill as output.
+		usage.InputTokens += m.Tokens.Input - m.Tokens.Cached
+		usage.CacheReadTokens += m.Tokens.Cached
+		usage.OutputTokens += m.Tokens.Output + m.Tokens.Thoughts
+	}
+	return usage, nil
+}
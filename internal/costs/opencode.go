@@ -0,0 +1,98 @@
+package costs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// opencodeExtractor reads OpenCode's storage at
+// $XDG_DATA_HOME/opencode/storage (default ~/.local/share/opencode/storage):
+// session/<project>/<session>.json records the session's directory, and
+// message/<session>/<message>.json holds each message with its token counts.
+type opencodeExtractor struct{}
+
+// opencodeSession is an OpenCode session record.
+type opencodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      struct {
+		Updated int64 `json:"updated"`
+	} `json:"time"`
+}
+
+// opencodeMessage is an OpenCode message record.
+type opencodeMessage struct {
+	Role    string `json:"role"`
+	ModelID string `json:"modelID"`
+	Tokens  *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+func (opencodeExtractor) SessionUsage(workDir string) (*Usage, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	storage := filepath.Join(dataHome, "opencode", "storage")
+
+	sessionFiles, err := filepath.Glob(filepath.Join(storage, "session", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var latest *opencodeSession
+	for _, path := range sessionFiles {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the agent's own log dir
+		if err != nil {
+			continue
+		}
+		var s opencodeSession
+		if err := json.Unmarshal(data, &s); err != nil || s.ID == "" {
+			continue
+		}
+		if filepath.Clean(s.Directory) != filepath.Clean(workDir) {
+			continue
+		}
+		if latest == nil || s.Time.Updated > latest.Time.Updated {
+			latest = &s
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoSessionLog
+	}
+
+	messageFiles, err := filepath.Glob(filepath.Join(storage, "message", latest.ID, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	usage := &Usage{}
+	for _, path := range messageFiles {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the agent's own log dir
+		if err != nil {
+			continue
+		}
+		var m opencodeMessage
+		if err := json.Unmarshal(data, &m); err != nil || m.Role != "assistant" || m.Tokens == nil {
+			continue
+		}
+		if usage.Model == "" {
+			usage.Model = m.ModelID
+		}
+		usage.InputTokens += m.Tokens.Input
+		usage.CacheReadTokens += m.Tokens.Cache.Read
+		usage.CacheWriteTokens += m.Tokens.Cache.Write
+		usage.OutputTokens += m.Tokens.Output + m.Tokens.Reasoning
+	}
+	return usage, nil
+}
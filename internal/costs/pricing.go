@@ -0,0 +1,137 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModelPrice is a model's price in USD per million tokens.
+type ModelPrice struct {
+	InputPerMillion      float64 `json:"input_per_million"`
+	OutputPerMillion     float64 `json:"output_per_million"`
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty"`
+}
+
+// PricingTable maps model names (or name prefixes) to prices. The "default"
+// entry prices models that match nothing else.
+type PricingTable map[string]ModelPrice
+
+// DefaultModel is the pricing table key used for unknown models.
+const DefaultModel = "default"
+
+// PricingFile is the on-disk format of settings/pricing.json. Its models
+// override (or add to) the built-in table entry by entry.
+type PricingFile struct {
+	Type    string                `json:"type"`    // "pricing"
+	Version int                   `json:"version"` // schema version
+	Models  map[string]ModelPrice `json:"models"`
+}
+
+// CurrentPricingVersion is the current pricing file schema version.
+const CurrentPricingVersion = 1
+
+// builtinPricing holds list prices per million tokens (as of Jan 2025 for
+// Claude). Keys match exactly or as a model-name prefix, so "gpt-5" prices
+// "gpt-5-codex" unless that has its own entry. Override in settings/pricing.json.
+var builtinPricing = PricingTable{
+	// Anthropic. See: https://www.anthropic.com/pricing
+	"claude-opus-4-5-20251101":  {15.0, 75.0, 1.5, 18.75},
+	"claude-opus-4":             {15.0, 75.0, 1.5, 18.75},
+	"claude-sonnet-4-20250514":  {3.0, 15.0, 0.3, 3.75},
+	"claude-sonnet-4":           {3.0, 15.0, 0.3, 3.75},
+	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
+	"claude-haiku-4":            {1.0, 5.0, 0.1, 1.25},
+	// OpenAI (codex).
+	"gpt-5":      {1.25, 10.0, 0.125, 0},
+	"gpt-5-mini": {0.25, 2.0, 0.025, 0},
+	// Google (gemini).
+	"gemini-2.5-pro":   {1.25, 10.0, 0.31, 0},
+	"gemini-2.5-flash": {0.30, 2.50, 0.075, 0},
+	// Fallback for unknown models (use Sonnet pricing)
+	DefaultModel: {3.0, 15.0, 0.3, 3.75},
+}
+
+// DefaultPricing returns a copy of the built-in pricing table.
+func DefaultPricing() PricingTable {
+	table := make(PricingTable, len(builtinPricing))
+	for model, price := range builtinPricing {
+		table[model] = price
+	}
+	return table
+}
+
+// PricingPath returns the path of a town's pricing overrides.
+func PricingPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "pricing.json")
+}
+
+// LoadPricing returns the built-in pricing table with the town's overrides
+// from settings/pricing.json applied. A missing file is not an error.
+func LoadPricing(townRoot string) (PricingTable, error) {
+	table := DefaultPricing()
+	if townRoot == "" {
+		return table, nil
+	}
+
+	path := PricingPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return table, nil
+		}
+		return table, fmt.Errorf("reading pricing: %w", err)
+	}
+
+	var file PricingFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return table, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if file.Type != "pricing" && file.Type != "" {
+		return table, fmt.Errorf("parsing %s: expected type 'pricing', got '%s'", path, file.Type)
+	}
+	if file.Version > CurrentPricingVersion {
+		return table, fmt.Errorf("parsing %s: version %d, max supported %d", path, file.Version, CurrentPricingVersion)
+	}
+	for model, price := range file.Models {
+		table[model] = price
+	}
+	return table, nil
+}
+
+// Lookup returns the price for a model: an exact entry, else the longest
+// entry that prefixes the model name, else the default entry.
+func (t PricingTable) Lookup(model string) (ModelPrice, string) {
+	if price, ok := t[model]; ok && model != "" {
+		return price, model
+	}
+	best := ""
+	for key := range t {
+		if key != DefaultModel && strings.HasPrefix(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best != "" {
+		return t[best], best
+	}
+	return t[DefaultModel], DefaultModel
+}
+
+// Cost converts token usage to USD.
+func (t PricingTable) Cost(u *Usage) float64 {
+	if u == nil {
+		return 0.0
+	}
+	price, _ := t.Lookup(u.Model)
+
+	// Prices are per million tokens
+	inputCost := float64(u.InputTokens) / 1_000_000 * price.InputPerMillion
+	cacheReadCost := float64(u.CacheReadTokens) / 1_000_000 * price.CacheReadPerMillion
+	cacheWriteCost := float64(u.CacheWriteTokens) / 1_000_000 * price.CacheWritePerMillion
+	outputCost := float64(u.OutputTokens) / 1_000_000 * price.OutputPerMillion
+
+	return inputCost + cacheReadCost + cacheWriteCost + outputCost
+}
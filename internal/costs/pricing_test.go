@@ -0,0 +1,87 @@
+package costs
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestPricingLookup(t *testing.T) {
+	table := DefaultPricing()
+
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"claude-sonnet-4-20250514", "claude-sonnet-4-20250514"},
+		{"claude-sonnet-4-5-20250929", "claude-sonnet-4"},
+		{"gpt-5-codex", "gpt-5"},
+		{"gpt-5-mini-2025", "gpt-5-mini"},
+		{"gemini-2.5-flash-lite", "gemini-2.5-flash"},
+		{"mystery-model", DefaultModel},
+		{"", DefaultModel},
+	}
+	for _, tt := range tests {
+		if _, got := table.Lookup(tt.model); got != tt.want {
+			t.Errorf("Lookup(%q) matched %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestPricingCost(t *testing.T) {
+	table := PricingTable{
+		DefaultModel: {InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+	}
+	usage := &Usage{InputTokens: 1_000_000, CacheReadTokens: 1_000_000, CacheWriteTokens: 1_000_000, OutputTokens: 1_000_000}
+	if got, want := table.Cost(usage), 3+15+0.3+3.75; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost = %v, want %v", got, want)
+	}
+	if got := table.Cost(nil); got != 0 {
+		t.Errorf("Cost(nil) = %v, want 0", got)
+	}
+}
+
+func TestLoadPricing(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// No overrides file: built-ins.
+	table, err := LoadPricing(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPricing: %v", err)
+	}
+	if len(table) != len(builtinPricing) {
+		t.Errorf("table has %d models, want %d", len(table), len(builtinPricing))
+	}
+
+	writeFile(t, PricingPath(townRoot), `{
+  "type": "pricing",
+  "version": 1,
+  "models": {
+    "gpt-5": {"input_per_million": 2, "output_per_million": 20},
+    "local-llama": {"input_per_million": 0, "output_per_million": 0}
+  }
+}`)
+	table, err = LoadPricing(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPricing: %v", err)
+	}
+	if p, _ := table.Lookup("gpt-5-codex"); p.OutputPerMillion != 20 {
+		t.Errorf("override not applied: %+v", p)
+	}
+	if _, key := table.Lookup("local-llama-3"); key != "local-llama" {
+		t.Errorf("added model not matched, got %q", key)
+	}
+	if p, _ := table.Lookup("claude-opus-4-5-20251101"); p.InputPerMillion != 15 {
+		t.Errorf("built-in lost: %+v", p)
+	}
+
+	// Overrides never leak into the built-in table.
+	if builtinPricing["gpt-5"].OutputPerMillion == 20 {
+		t.Error("LoadPricing modified the built-in table")
+	}
+
+	writeFile(t, filepath.Join(townRoot, "settings", "pricing.json"), `{"type": "escalation"}`)
+	if _, err := LoadPricing(townRoot); err == nil {
+		t.Error("wrong file type should fail")
+	}
+}
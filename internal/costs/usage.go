@@ -0,0 +1,119 @@
+// Package costs extracts token usage from agent session logs and prices it.
+//
+// Each agent runtime keeps its own session log format. An Extractor reads one
+// format; agent presets name the format they write via UsageFormat, so adding
+// an agent never needs a switch on its name here.
+package costs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNoSessionLog is returned when an agent has no session log for a
+// working directory (it hasn't run there, or logs were cleaned up).
+var ErrNoSessionLog = errors.New("no session log found")
+
+// ErrUnsupportedFormat is returned for agents whose usage can't be read.
+var ErrUnsupportedFormat = errors.New("usage extraction not supported")
+
+// Usage is the token usage of one agent session.
+type Usage struct {
+	// Model is the model the session ran on (the first one seen).
+	Model string `json:"model,omitempty"`
+	// InputTokens excludes tokens served from the prompt cache.
+	InputTokens      int `json:"input_tokens"`
+	CacheReadTokens  int `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+	// OutputTokens includes reasoning/thinking tokens, which bill as output.
+	OutputTokens int `json:"output_tokens"`
+}
+
+// Extractor reads token usage from one agent's session logs.
+type Extractor interface {
+	// SessionUsage returns the usage of the most recent session the agent
+	// ran in workDir. Returns ErrNoSessionLog if there is none.
+	SessionUsage(workDir string) (*Usage, error)
+}
+
+// extractors maps a preset's UsageFormat to its extractor.
+var extractors = map[string]Extractor{
+	"claude":   claudeExtractor{},
+	"codex":    codexExtractor{},
+	"gemini":   geminiExtractor{},
+	"opencode": opencodeExtractor{},
+}
+
+// Formats returns the usage formats that can be extracted, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(extractors))
+	for f := range extractors {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ExtractorFor returns the extractor for a usage format.
+func ExtractorFor(format string) (Extractor, error) {
+	if e, ok := extractors[format]; ok {
+		return e, nil
+	}
+	if format == "" {
+		return nil, ErrUnsupportedFormat
+	}
+	return nil, fmt.Errorf("%w: unknown usage format %q", ErrUnsupportedFormat, format)
+}
+
+// FormatForAgent returns the usage format for an agent name as set in
+// GT_AGENT. Custom agents defined in town settings (such as the cost-tier
+// claude-sonnet) inherit the format of the preset they run, matched by
+// provider or command name. Returns "" if the agent's usage can't be read.
+func FormatForAgent(townRoot, agent string) string {
+	if agent == "" {
+		agent = string(config.DefaultAgentPreset())
+	}
+	if townRoot != "" {
+		_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot))
+	}
+	if preset := config.GetAgentPresetByName(agent); preset != nil {
+		return preset.UsageFormat
+	}
+	if townRoot == "" {
+		return ""
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Agents == nil {
+		return ""
+	}
+	rc, ok := settings.Agents[agent]
+	if !ok || rc == nil {
+		return ""
+	}
+	presetName := rc.Provider
+	if presetName == "" && rc.Command != "" {
+		presetName = filepath.Base(rc.Command)
+	}
+	if preset := config.GetAgentPresetByName(presetName); preset != nil {
+		return preset.UsageFormat
+	}
+	return ""
+}
+
+// SessionUsage reads the usage of an agent's latest session in workDir.
+func SessionUsage(townRoot, agent, workDir string) (*Usage, error) {
+	format := FormatForAgent(townRoot, agent)
+	e, err := ExtractorFor(format)
+	if err != nil {
+		if format == "" {
+			return nil, fmt.Errorf("%w for agent %q", ErrUnsupportedFormat, agent)
+		}
+		return nil, err
+	}
+	return e.SessionUsage(workDir)
+}
@@ -0,0 +1,200 @@
+package costs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeExtractor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+
+	workDir := "/town/gastown/polecats/toast"
+	transcript := strings.Join([]string{
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"cache_creation_input_tokens":1000,"cache_read_input_tokens":5000,"output_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":20,"cache_read_input_tokens":6000,"output_tokens":30}}}`,
+	}, "\n")
+	writeFile(t, filepath.Join(home, ".claude", "projects", "-town-gastown-polecats-toast", "abc.jsonl"), transcript)
+
+	usage, err := claudeExtractor{}.SessionUsage(workDir)
+	if err != nil {
+		t.Fatalf("SessionUsage: %v", err)
+	}
+	want := Usage{Model: "claude-sonnet-4-20250514", InputTokens: 120, CacheWriteTokens: 1000, CacheReadTokens: 11000, OutputTokens: 80}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+
+	if _, err := (claudeExtractor{}).SessionUsage("/nowhere"); !errors.Is(err, ErrNoSessionLog) {
+		t.Errorf("missing project err = %v, want ErrNoSessionLog", err)
+	}
+}
+
+func TestClaudeUsageSince(t *testing.T) {
+	configDir := t.TempDir()
+	transcript := strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-10-16T08:00:00Z","message":{"id":"msg_old","usage":{"input_tokens":999,"output_tokens":999}}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T10:00:00Z","message":{"id":"msg_1","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T10:00:01Z","message":{"id":"msg_1","usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"user","timestamp":"2026-10-16T10:01:00Z","message":{"role":"user"}}`,
+		`{"type":"assistant","timestamp":"2026-10-16T10:02:00.5Z","message":{"id":"msg_2","usage":{"input_tokens":10,"cache_creation_input_tokens":20,"output_tokens":5}}}`,
+	}, "\n")
+	writeFile(t, filepath.Join(configDir, "projects", "-town-gastown", "a.jsonl"), transcript)
+
+	since := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	events, err := ClaudeUsageSince(configDir, since)
+	if err != nil {
+		t.Fatalf("ClaudeUsageSince: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %+v, want msg_1 once and msg_2", events)
+	}
+	if events[0].Usage.InputTokens != 100 || events[1].Usage.CacheWriteTokens != 20 {
+		t.Errorf("events = %+v", events)
+	}
+
+	if events, err := ClaudeUsageSince(t.TempDir(), since); err != nil || len(events) != 0 {
+		t.Errorf("no projects dir: events=%v err=%v", events, err)
+	}
+}
+
+func TestCodexExtractor(t *testing.T) {
+	codexHome := t.TempDir()
+	t.Setenv("CODEX_HOME", codexHome)
+
+	workDir := "/town/gastown/polecats/nux"
+	day := filepath.Join(codexHome, "sessions", "2026", "10", "16")
+	writeFile(t, filepath.Join(day, "rollout-a.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"cwd":"/town/gastown/polecats/nux"}}`,
+		`{"type":"turn_context","payload":{"cwd":"/town/gastown/polecats/nux","model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":400,"output_tokens":200}}}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":3000,"cached_input_tokens":1000,"output_tokens":500}}}}`,
+	}, "\n"))
+	writeFile(t, filepath.Join(day, "rollout-b.jsonl"),
+		`{"type":"session_meta","payload":{"cwd":"/somewhere/else"}}`)
+
+	usage, err := codexExtractor{}.SessionUsage(workDir)
+	if err != nil {
+		t.Fatalf("SessionUsage: %v", err)
+	}
+	want := Usage{Model: "gpt-5-codex", InputTokens: 2000, CacheReadTokens: 1000, OutputTokens: 500}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+
+	if _, err := (codexExtractor{}).SessionUsage("/town/other"); !errors.Is(err, ErrNoSessionLog) {
+		t.Errorf("unknown workdir err = %v, want ErrNoSessionLog", err)
+	}
+}
+
+func TestGeminiExtractor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	workDir := "/town/gastown/crew/max"
+	hash := sha256.Sum256([]byte(workDir))
+	writeFile(t, filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(hash[:]), "chats", "session-1.json"), `{
+  "sessionId": "s1",
+  "messages": [
+    {"type": "user", "content": "hi"},
+    {"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 1000, "output": 100, "cached": 600, "thoughts": 50}},
+    {"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 500, "output": 10, "cached": 0, "thoughts": 0}}
+  ]
+}`)
+
+	usage, err := geminiExtractor{}.SessionUsage(workDir)
+	if err != nil {
+		t.Fatalf("SessionUsage: %v", err)
+	}
+	want := Usage{Model: "gemini-2.5-pro", InputTokens: 900, CacheReadTokens: 600, OutputTokens: 160}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
+func TestOpenCodeExtractor(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	storage := filepath.Join(dataHome, "opencode", "storage")
+
+	workDir := "/town/gastown/polecats/slit"
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_old.json"),
+		`{"id":"ses_old","directory":"/town/gastown/polecats/slit","time":{"updated":100}}`)
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_new.json"),
+		`{"id":"ses_new","directory":"/town/gastown/polecats/slit","time":{"updated":200}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_old", "msg_1.json"),
+		`{"role":"assistant","modelID":"old","tokens":{"input":9999,"output":9999}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_1.json"),
+		`{"role":"user"}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_2.json"),
+		`{"role":"assistant","modelID":"claude-sonnet-4","tokens":{"input":10,"output":20,"reasoning":5,"cache":{"read":300,"write":40}}}`)
+
+	usage, err := opencodeExtractor{}.SessionUsage(workDir)
+	if err != nil {
+		t.Fatalf("SessionUsage: %v", err)
+	}
+	want := Usage{Model: "claude-sonnet-4", InputTokens: 10, CacheReadTokens: 300, CacheWriteTokens: 40, OutputTokens: 25}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
+func TestFormatForAgent(t *testing.T) {
+	townRoot := t.TempDir()
+	writeFile(t, config.TownSettingsPath(townRoot), `{
+  "type": "town-settings",
+  "version": 1,
+  "agents": {
+    "claude-sonnet": {"command": "claude", "args": ["--model", "sonnet"]},
+    "my-codex": {"provider": "codex", "command": "/opt/bin/codex-wrapper"},
+    "aider": {"command": "aider"}
+  }
+}`)
+
+	tests := []struct {
+		agent string
+		want  string
+	}{
+		{"", "claude"},
+		{"claude", "claude"},
+		{"codex", "codex"},
+		{"gemini", "gemini"},
+		{"opencode", "opencode"},
+		{"cursor", ""},
+		{"claude-sonnet", "claude"},
+		{"my-codex", "codex"},
+		{"aider", ""},
+		{"nonexistent", ""},
+	}
+	for _, tt := range tests {
+		if got := FormatForAgent(townRoot, tt.agent); got != tt.want {
+			t.Errorf("FormatForAgent(%q) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+
+	if _, err := SessionUsage(townRoot, "cursor", "/x"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("SessionUsage(cursor) err = %v, want ErrUnsupportedFormat", err)
+	}
+}
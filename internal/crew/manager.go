@@ -756,7 +756,7 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists — kill AFTER command is fully built
@@ -795,16 +795,27 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// initial shell inherits the correct GT_ROLE (not the parent's).
 	// See: https://github.com/anthropics/gastown/issues/280 (race condition fix)
 	// See: https://github.com/steveyegge/gastown/issues/1289 (env inheritance fix)
-	if err := t.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
+	// Other backends rely on the startup command's env exports and get the
+	// session env (used for liveness checks) set afterwards.
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		if err := realTmux.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
 
-	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
+		// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = realTmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
 
-	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
-	_ = t.SetCrewCycleBindings(sessionID)
+		// Set up C-b n/p keybindings for crew session cycling (non-fatal)
+		_ = realTmux.SetCrewCycleBindings(sessionID)
+	} else {
+		if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		for k, v := range envVars {
+			_ = t.SetEnvironment(sessionID, k, v)
+		}
+	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
 	_ = session.TrackSessionPID(townRoot, sessionID, t)
@@ -823,7 +834,7 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(filepath.Dir(m.rig.Path))
 	sessionID := m.SessionName(name)
 
 	// Check if session exists
@@ -847,7 +858,7 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend(filepath.Dir(m.rig.Path))
 	sessionID := m.SessionName(name)
 	return t.HasSession(sessionID)
 }
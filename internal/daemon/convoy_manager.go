@@ -73,6 +73,10 @@ type ConvoyManager struct {
 	// The first cycle advances high-water marks without processing events,
 	// preventing a burst of historical event replay on daemon restart.
 	seeded bool
+
+	// scans and scanFailures count stranded scans for the daemon state file.
+	scans        atomic.Int64
+	scanFailures atomic.Int64
 }
 
 // NewConvoyManager creates a new convoy manager.
@@ -262,8 +266,10 @@ func (m *ConvoyManager) runStrandedScan() {
 
 // scan runs one stranded scan cycle: find stranded convoys, feed or close each.
 func (m *ConvoyManager) scan() {
+	m.scans.Add(1)
 	stranded, err := m.findStranded()
 	if err != nil {
+		m.scanFailures.Add(1)
 		m.logger("Convoy: stranded scan failed: %s", util.FirstLine(err.Error()))
 		return
 	}
@@ -283,6 +289,11 @@ func (m *ConvoyManager) scan() {
 	}
 }
 
+// ScanCounts returns how many stranded scans have run and how many failed.
+func (m *ConvoyManager) ScanCounts() (scans, failures int64) {
+	return m.scans.Load(), m.scanFailures.Load()
+}
+
 // findStranded runs `gt convoy stranded --json` and parses the output.
 func (m *ConvoyManager) findStranded() ([]strandedConvoyInfo, error) {
 	cmd := exec.CommandContext(m.ctx, m.gtPath, "convoy", "stranded", "--json")
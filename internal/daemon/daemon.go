@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -19,6 +20,7 @@ import (
 	beadsdk "github.com/steveyegge/beads"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -26,6 +28,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/headless"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
@@ -44,7 +47,8 @@ import (
 type Daemon struct {
 	config        *Config
 	patrolConfig  *DaemonPatrolConfig
-	tmux          *tmux.Tmux
+	sessions      session.SessionBackend // Backend agent sessions run on (tmux or headless)
+	headless      *headless.Server       // Hosts headless sessions when the town uses the pty backend
 	logger        *log.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -53,10 +57,12 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	pluginSched   *PluginScheduler
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
+	totalDeaths  int64 // all deaths since start, reported in state.json
 
 	// Deacon startup tracking: prevents race condition where newly started
 	// sessions are immediately killed by the heartbeat check.
@@ -152,7 +158,7 @@ func New(config *Config) (*Daemon, error) {
 	return &Daemon{
 		config:         config,
 		patrolConfig:   patrolConfig,
-		tmux:           tmux.NewTmux(),
+		sessions:       session.NewBackend(config.TownRoot),
 		logger:         logger,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -225,6 +231,18 @@ func (d *Daemon) Run() error {
 
 	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
 
+	// Host headless agent sessions if the town doesn't use tmux. Started
+	// before anything that might launch an agent.
+	if session.BackendName(d.config.TownRoot) == config.SessionBackendPTY {
+		d.headless = headless.NewServer(d.config.TownRoot, d.logger.Printf)
+		if err := d.headless.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start headless session server: %v", err)
+			d.headless = nil
+		} else {
+			d.logger.Printf("Headless session server listening on %s", headless.SocketPath(d.config.TownRoot))
+		}
+	}
+
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
 	if err := d.curator.Start(); err != nil {
@@ -265,6 +283,19 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start the plugin scheduler so gated plugins run even when the Deacon
+	// is paused or busy.
+	if IsPatrolEnabled(d.patrolConfig, "plugins") {
+		rigs := func() []string { return d.getPatrolRigs("plugins") }
+		d.pluginSched = NewPluginScheduler(d.config.TownRoot, d.gtPath, rigs, d.logger.Printf)
+		if err := d.pluginSched.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start plugin scheduler: %v", err)
+			d.pluginSched = nil
+		} else {
+			d.logger.Println("Plugin scheduler started")
+		}
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -445,9 +476,27 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 14. Escalate queued nudges that expired without being picked up.
+	// Agents that never reach a turn boundary won't see re-nudges either.
+	d.escalateExpiredNudges()
+
+	// 15. Index new event log lines into the event store (gt events query,
+	// gt audit, gt feed). Best-effort: without Dolt, readers scan the logs.
+	d.syncEventStore()
+
+	// 16. Back up the Dolt databases when the last backup is older than the
+	// backup interval (default hourly), applying retention.
+	d.backupDoltDatabases()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
+	d.deathsMu.Lock()
+	state.SessionDeaths = d.totalDeaths
+	d.deathsMu.Unlock()
+	if d.convoyManager != nil {
+		state.ConvoyScans, state.ConvoyScanFailures = d.convoyManager.ScanCounts()
+	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
@@ -538,7 +587,7 @@ func (d *Daemon) ensureBootRunning() {
 
 	// Check for degraded mode
 	degraded := os.Getenv("GT_DEGRADED") == "true"
-	if degraded || !d.tmux.IsAvailable() {
+	if degraded || !d.sessionsAvailable() {
 		// In degraded mode, run mechanical triage directly
 		d.logger.Println("Degraded mode: running mechanical Boot triage")
 		d.runDegradedBootTriage(b)
@@ -557,6 +606,15 @@ func (d *Daemon) ensureBootRunning() {
 	d.logger.Println("Boot spawned successfully")
 }
 
+// sessionsAvailable reports whether agent sessions can be started: tmux is
+// installed, or this daemon hosts headless sessions.
+func (d *Daemon) sessionsAvailable() bool {
+	if t, ok := d.sessions.(*tmux.Tmux); ok {
+		return t.IsAvailable()
+	}
+	return d.headless != nil
+}
+
 // runDegradedBootTriage performs mechanical Boot logic without AI reasoning.
 // This is for degraded mode when tmux is unavailable.
 func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
@@ -567,7 +625,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	// Simple check: is Deacon session alive?
-	hasDeacon, err := d.tmux.HasSession(d.getDeaconSessionName())
+	hasDeacon, err := d.sessions.HasSession(d.getDeaconSessionName())
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		status.LastAction = "error"
@@ -708,7 +766,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	d.logger.Printf("Deacon heartbeat is stale (%s old), checking session...", age.Round(time.Minute))
 
 	// Check if session exists
-	hasSession, err := d.tmux.HasSession(sessionName)
+	hasSession, err := d.sessions.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		return
@@ -728,7 +786,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
+		if err := d.sessions.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
 		}
 	}
@@ -738,10 +796,10 @@ func (d *Daemon) checkDeaconHeartbeat() {
 // Extracted for reuse by PATCH-005 grace period logic.
 func (d *Daemon) restartStuckDeacon(sessionName string) {
 	// Check if session exists before trying to kill
-	hasSession, _ := d.tmux.HasSession(sessionName)
+	hasSession, _ := d.sessions.HasSession(sessionName)
 	if hasSession {
 		d.logger.Printf("Killing stuck Deacon session %s", sessionName)
-		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		if err := d.sessions.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 	}
@@ -782,8 +840,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	// indicating Claude is stuck. Kill it so Start() can recreate a fresh one.
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Witness for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = d.sessions.KillSession(mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
@@ -832,8 +889,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// can recreate a fresh one. See: gt-tr3d
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Refinery for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = d.sessions.KillSession(mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, ""); err != nil {
@@ -871,10 +927,10 @@ func (d *Daemon) ensureMayorRunning() {
 // running their own patrol loops and spawning agents. (hq-2mstj)
 func (d *Daemon) killDeaconSessions() {
 	for _, name := range []string{session.DeaconSessionName(), session.BootSessionName()} {
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.sessions.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.sessions.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -886,10 +942,10 @@ func (d *Daemon) killDeaconSessions() {
 func (d *Daemon) killWitnessSessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.WitnessSessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.sessions.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.sessions.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -901,10 +957,10 @@ func (d *Daemon) killWitnessSessions() {
 func (d *Daemon) killRefinerySessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.RefinerySessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.sessions.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.sessions.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -1119,6 +1175,12 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop plugin scheduler
+	if d.pluginSched != nil {
+		d.pluginSched.Stop()
+		d.logger.Println("Plugin scheduler stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1128,6 +1190,12 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		}
 	}
 
+	// Headless sessions can't outlive the daemon that hosts them.
+	if d.headless != nil {
+		d.headless.Stop()
+		d.logger.Println("Headless session server stopped")
+	}
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -1432,7 +1500,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
 	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	sessionAlive, err := d.sessions.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1464,7 +1532,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
+	sessionRevived, err := d.sessions.HasSession(sessionName)
 	if err == nil && sessionRevived {
 		return // Session came back - no restart needed
 	}
@@ -1476,10 +1544,16 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
 
-	// Auto-restart the polecat
-	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
+	// Auto-restart the polecat, resuming from its checkpoint
+	if err := d.restartPolecatSession(rigName, polecatName, sessionName, info.HookBead); err != nil {
 		d.logger.Printf("Error restarting polecat %s/%s: %v", rigName, polecatName, err)
-		// Notify witness as fallback
+		// Notify witness as fallback. A bead that keeps crashing its polecat
+		// is only escalated once, not on every heartbeat.
+		if errors.Is(err, checkpoint.ErrResumeBudgetExhausted) {
+			if first, _ := checkpoint.MarkEscalated(d.config.TownRoot, info.HookBead); !first {
+				return
+			}
+		}
 		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
 	} else {
 		d.logger.Printf("Successfully restarted crashed polecat %s/%s", rigName, polecatName)
@@ -1494,6 +1568,7 @@ func (d *Daemon) recordSessionDeath(sessionName string) {
 	now := time.Now()
 
 	// Add this death
+	d.totalDeaths++
 	d.recentDeaths = append(d.recentDeaths, sessionDeath{
 		sessionName: sessionName,
 		timestamp:   now,
@@ -1536,8 +1611,10 @@ func (d *Daemon) emitMassDeathEvent() {
 	d.recentDeaths = nil
 }
 
-// restartPolecatSession restarts a crashed polecat session.
-func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
+// restartPolecatSession restarts a crashed polecat session in its worktree.
+// The agent is told what it was doing from the polecat's checkpoint, and the
+// restart is charged to the hooked bead's resume budget.
+func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName, hookBead string) error {
 	// Check rig operational state before auto-restarting
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		return fmt.Errorf("cannot restart polecat: %s", reason)
@@ -1560,15 +1637,14 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		return fmt.Errorf("polecat worktree does not exist: %s", workDir)
 	}
 
+	resume, err := checkpoint.PlanResume(d.config.TownRoot, workDir, polecatName, hookBead, checkpoint.DefaultMaxResumes)
+	if err != nil {
+		return fmt.Errorf("cannot resume polecat: %w", err)
+	}
+
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
-	// Create new tmux session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
-
 	// Set environment variables using centralized AgentEnv
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:      "polecat",
@@ -1577,41 +1653,51 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		TownRoot:  d.config.TownRoot,
 	})
 
-	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
-	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
+	// Launch Claude with environment exported inline
+	// Pass rigPath so rig agent settings are honored (not town-level defaults)
+	startCmd := config.BuildStartupCommand(envVars, rigPath, resume.Prompt())
+	if resume.ContinueConversation() {
+		startCmd = config.BuildResumeStartupCommand(envVars, rigPath, resume.SessionID(), resume.Prompt())
 	}
+	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
 
-	// Set GT_AGENT in tmux session env so tools querying tmux environment
-	// (e.g., witness patrol) can detect non-Claude agents.
-	// BuildStartupCommand sets GT_AGENT in process env via exec env, but that
-	// isn't visible to tmux show-environment.
-	rc := config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath)
-	if rc.ResolvedAgent != "" {
-		_ = d.tmux.SetEnvironment(sessionName, "GT_AGENT", rc.ResolvedAgent)
-	}
+	// Create new session, replacing a zombie (session alive, Claude dead)
+	err = d.startAgentSession(sessionName, workDir, startCmd, func() {
+		// Set all env vars in the session (for debugging) and they'll also be exported to Claude
+		for k, v := range envVars {
+			_ = d.sessions.SetEnvironment(sessionName, k, v)
+		}
 
-	// Apply theme
-	theme := tmux.AssignTheme(rigName)
-	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
+		// Set GT_AGENT in session env so tools querying the session environment
+		// (e.g., witness patrol) can detect non-Claude agents.
+		// BuildStartupCommand sets GT_AGENT in process env via exec env, but that
+		// isn't visible to tmux show-environment.
+		rc := config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath)
+		if rc.ResolvedAgent != "" {
+			_ = d.sessions.SetEnvironment(sessionName, "GT_AGENT", rc.ResolvedAgent)
+		}
 
-	// Set pane-died hook for future crash detection
-	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
-	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
+		if t, ok := d.sessions.(*tmux.Tmux); ok {
+			// Apply theme
+			theme := tmux.AssignTheme(rigName)
+			_ = t.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
 
-	// Launch Claude with environment exported inline
-	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
-	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
-		return fmt.Errorf("sending startup command: %w", err)
+			// Set pane-died hook for future crash detection
+			_ = t.SetPaneDiedHook(sessionName, agentID)
+		}
+	})
+	if err != nil {
+		return err
 	}
+	_ = events.LogFeed(events.TypeSessionResume, agentID,
+		events.SessionResumePayload(sessionName, agentID, resume.Bead, "daemon", resume.Attempt, resume.Max))
 
 	// Wait for Claude to start, then accept bypass permissions warning if it appears.
 	// This ensures automated restarts aren't blocked by the warning dialog.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := d.sessions.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
+	_ = d.sessions.AcceptBypassPermissionsWarning(sessionName)
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// DoltBackupInterval returns the configured backup interval, or the default (1h).
+func DoltBackupInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltBackups != nil {
+		if config.Patrols.DoltBackups.Interval > 0 {
+			return config.Patrols.DoltBackups.Interval
+		}
+	}
+	return doltserver.DefaultBackupInterval
+}
+
+// DoltBackupRetention returns the configured backup retention policy. Periods
+// left unset use doltserver.DefaultRetention.
+func DoltBackupRetention(config *DaemonPatrolConfig) doltserver.RetentionPolicy {
+	policy := doltserver.DefaultRetention
+	if config == nil || config.Patrols == nil || config.Patrols.DoltBackups == nil {
+		return policy
+	}
+	c := config.Patrols.DoltBackups
+	if c.Hourly > 0 {
+		policy.Hourly = c.Hourly
+	}
+	if c.Daily > 0 {
+		policy.Daily = c.Daily
+	}
+	if c.Weekly > 0 {
+		policy.Weekly = c.Weekly
+	}
+	return policy
+}
+
+// backupDoltDatabases takes a scheduled backup when the newest one is older
+// than the backup interval. The schedule lives in the backup manifest rather
+// than in a ticker, so daemon restarts neither skip nor repeat backups.
+// Non-fatal: failures are logged and recorded in the manifest.
+func (d *Daemon) backupDoltDatabases() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_backups") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		return
+	}
+
+	manifest, err := doltserver.LoadBackupManifest(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("dolt_backups: %v", err)
+		return
+	}
+	for _, snap := range manifest.Snapshots {
+		if snap.Partial {
+			continue // a single-database backup doesn't cover the town
+		}
+		if time.Since(snap.Time) < DoltBackupInterval(d.patrolConfig) {
+			return
+		}
+		break
+	}
+
+	result, err := doltserver.BackupDatabases(d.config.TownRoot, doltserver.BackupOptions{
+		Retention: DoltBackupRetention(d.patrolConfig),
+	})
+	if err != nil {
+		d.logger.Printf("dolt_backups: backup failed: %v", err)
+		return
+	}
+
+	snap := result.Snapshot
+	for db, reason := range snap.Errors {
+		d.logger.Printf("dolt_backups: %s: %s", db, reason)
+	}
+	if len(snap.Missing) > 0 {
+		d.logger.Printf("dolt_backups: server not serving %v; backup not verified", snap.Missing)
+	}
+	d.logger.Printf("dolt_backups: backup %s: %d database(s), verified=%v, pruned %d restore point(s)",
+		snap.ID, len(snap.Commits), snap.Verified, len(result.Pruned))
+}
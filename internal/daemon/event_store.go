@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"errors"
+
+	"github.com/steveyegge/gastown/internal/eventstore"
+)
+
+// syncEventStore indexes new event log lines into the event store so
+// queries rarely have catching up to do. A town whose Dolt server is down
+// or not set up just skips it; queries fall back to reading the logs.
+func (d *Daemon) syncEventStore() {
+	store, err := eventstore.OpenTown(d.config.TownRoot)
+	if err != nil {
+		if !errors.Is(err, eventstore.ErrUnavailable) {
+			d.logger.Printf("Warning: opening event store: %v", err)
+		}
+		return
+	}
+	defer store.Close()
+
+	n, err := store.Sync(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: syncing event store: %v", err)
+		return
+	}
+	if n > 0 {
+		d.logger.Printf("Indexed %d event log line(s) into the event store", n)
+	}
+}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// escalateExpiredNudges finds queued nudges whose TTL passed without the
+// agent draining them and mails the agent's supervisor, once per nudge.
+// An agent that never reaches a turn boundary is stuck or dead; re-nudging
+// it won't help, so someone needs to look at it.
+func (d *Daemon) escalateExpiredNudges() {
+	expired, err := nudge.SweepExpired(d.config.TownRoot, time.Now())
+	if err != nil {
+		d.logger.Printf("Warning: sweeping nudge receipts: %v", err)
+		return
+	}
+	if len(expired) == 0 {
+		return
+	}
+
+	// One mail per session, listing every nudge it missed.
+	bySession := make(map[string][]nudge.QueuedNudge)
+	for _, n := range expired {
+		bySession[n.Session] = append(bySession[n.Session], n)
+	}
+	sessions := make([]string, 0, len(bySession))
+	for s := range bySession {
+		sessions = append(sessions, s)
+	}
+	sort.Strings(sessions)
+
+	for _, sessionName := range sessions {
+		missed := bySession[sessionName]
+		addr := nudgeEscalationTarget(sessionName)
+		subject := fmt.Sprintf("NUDGE_EXPIRED: %s missed %d nudge(s)", sessionName, len(missed))
+
+		var body strings.Builder
+		fmt.Fprintf(&body, "Session %s never picked up these queued nudges before they expired:\n\n", sessionName)
+		for _, n := range missed {
+			fmt.Fprintf(&body, "  %s [from %s, %s] %s\n", n.ID, n.Sender, n.Timestamp.Format(time.RFC3339), n.Message)
+		}
+		body.WriteString("\nAction needed: Check if the agent is alive and reaching turn boundaries. Resend if still relevant.")
+
+		cmd := exec.Command(d.gtPath, "mail", "send", addr, "-s", subject, "-m", body.String())
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ() // Inherit PATH to find gt executable
+
+		if err := cmd.Run(); err != nil {
+			d.logger.Printf("Warning: failed to escalate expired nudges for %s: %v", sessionName, err)
+		} else {
+			d.logger.Printf("Escalated %d expired nudge(s) for %s to %s", len(missed), sessionName, addr)
+		}
+	}
+}
+
+// nudgeEscalationTarget returns who to tell when a session misses its nudges:
+// the rig's witness for polecats, crew and the refinery, the mayor for the
+// deacon, and the deacon for everyone else.
+func nudgeEscalationTarget(sessionName string) string {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return "deacon/"
+	}
+	switch id.Role {
+	case session.RolePolecat, session.RoleCrew, session.RoleRefinery:
+		if id.Rig != "" {
+			return id.Rig + "/witness"
+		}
+	case session.RoleDeacon:
+		return "mayor/"
+	}
+	return "deacon/"
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestNudgeEscalationTarget(t *testing.T) {
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+
+	tests := []struct {
+		session string
+		want    string
+	}{
+		{"gt-toast", "gastown/witness"},
+		{"gt-crew-max", "gastown/witness"},
+		{"gt-refinery", "gastown/witness"},
+		{"gt-witness", "deacon/"},
+		{"hq-mayor", "deacon/"},
+		{"hq-deacon", "mayor/"},
+		{"not-a-session", "deacon/"},
+	}
+	for _, tt := range tests {
+		if got := nudgeEscalationTarget(tt.session); got != tt.want {
+			t.Errorf("nudgeEscalationTarget(%q) = %q, want %q", tt.session, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -98,3 +101,58 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_DoltBackups(t *testing.T) {
+	// dolt_backups defaults to enabled
+	if !IsPatrolEnabled(nil, "dolt_backups") {
+		t.Error("expected dolt_backups to be enabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{DoltBackups: &DoltBackupsConfig{Enabled: false}},
+	}
+	if IsPatrolEnabled(config, "dolt_backups") {
+		t.Error("expected dolt_backups to be disabled when explicitly disabled")
+	}
+}
+
+func TestDoltBackupsSchedule(t *testing.T) {
+	if got := DoltBackupInterval(nil); got != doltserver.DefaultBackupInterval {
+		t.Errorf("expected default interval %v, got %v", doltserver.DefaultBackupInterval, got)
+	}
+	if got := DoltBackupRetention(nil); got != doltserver.DefaultRetention {
+		t.Errorf("expected default retention, got %+v", got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			DoltBackups: &DoltBackupsConfig{
+				Enabled:  true,
+				Interval: 6 * time.Hour,
+				Daily:    14,
+			},
+		},
+	}
+	if got := DoltBackupInterval(config); got != 6*time.Hour {
+		t.Errorf("expected 6h interval, got %v", got)
+	}
+	want := doltserver.DefaultRetention
+	want.Daily = 14
+	if got := DoltBackupRetention(config); got != want {
+		t.Errorf("DoltBackupRetention = %+v, want %+v", got, want)
+	}
+}
+
+func TestIsPatrolEnabled_Plugins(t *testing.T) {
+	// plugins defaults to enabled
+	if !IsPatrolEnabled(nil, "plugins") {
+		t.Error("expected plugins to be enabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{Plugins: &PatrolConfig{Enabled: false}},
+	}
+	if IsPatrolEnabled(config, "plugins") {
+		t.Error("expected plugins to be disabled when explicitly disabled")
+	}
+}
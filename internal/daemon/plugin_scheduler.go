@@ -0,0 +1,283 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/plugin"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// pluginSchedulerInterval is how often plugin gates are evaluated.
+	// One minute is the resolution of cron schedules.
+	pluginSchedulerInterval = time.Minute
+
+	// pluginCheckTimeout bounds a condition gate's check command.
+	pluginCheckTimeout = 30 * time.Second
+
+	// pluginDispatchTimeout bounds one gt dog dispatch call.
+	pluginDispatchTimeout = 2 * time.Minute
+)
+
+// PluginScheduler dispatches gated plugins to dogs without the Deacon.
+// Each minute it scans the town and rig plugin directories, evaluates every
+// gate (cron schedules, cooldowns, condition checks and the event types seen
+// in .events.jsonl, which an event-gated plugin keeps until it is dispatched)
+// and dispatches due plugins through
+// 'gt dog dispatch'. The dog records the plugin run on the ledger when it
+// finishes ('gt dog done'), which is where gates read the last run from.
+type PluginScheduler struct {
+	townRoot string
+	gtPath   string
+	rigs     func() []string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	sched *plugin.Scheduler
+
+	// eventsOffset is how far .events.jsonl has been read. It starts at the
+	// end of the file, so a restart doesn't replay old events.
+	eventsOffset int64
+	started      bool
+}
+
+// NewPluginScheduler creates a plugin scheduler. rigs returns the rigs whose
+// plugin directories are scanned.
+func NewPluginScheduler(townRoot, gtPath string, rigs func() []string, logger func(format string, args ...interface{})) *PluginScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &PluginScheduler{
+		townRoot: townRoot,
+		gtPath:   gtPath,
+		rigs:     rigs,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	recorder := plugin.NewRecorder(townRoot)
+	s.sched = &plugin.Scheduler{
+		LastRun:  s.lastRun(recorder),
+		Check:    s.runCheck,
+		Busy:     s.busy,
+		Dispatch: s.dispatch,
+	}
+	return s
+}
+
+// Start begins the scheduler goroutine. The first tick runs immediately and
+// carries the startup event.
+func (s *PluginScheduler) Start() error {
+	s.sched.Since = time.Now()
+	s.eventsOffset = fileSize(s.eventsPath())
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop gracefully stops the scheduler, waiting for an in-flight tick.
+func (s *PluginScheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *PluginScheduler) run() {
+	defer s.wg.Done()
+
+	s.tick(time.Now())
+
+	ticker := time.NewTicker(pluginSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(now)
+		}
+	}
+}
+
+// tick runs one scheduling pass.
+func (s *PluginScheduler) tick(now time.Time) {
+	seen := s.readEvents()
+	if !s.started {
+		seen[plugin.EventStartup] = true
+		s.started = true
+	}
+
+	plugins, err := plugin.NewScanner(s.townRoot, s.rigs()).DiscoverAll()
+	if err != nil {
+		s.logger("plugins: scanning: %v", err)
+		return
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	for _, d := range s.sched.Tick(plugins, now, seen) {
+		switch {
+		case d.Dispatched:
+			s.logger("plugins: dispatched %s to dog %s (%s)", d.Plugin, d.Dog, d.Reason)
+		case errors.Is(d.Err, plugin.ErrNoIdleDog):
+			s.logger("plugins: %s is due (%s) but no dog is idle; will retry", d.Plugin, d.Reason)
+		case d.Err != nil:
+			s.logger("plugins: %s: %v", d.Plugin, d.Err)
+		}
+	}
+}
+
+func (s *PluginScheduler) eventsPath() string {
+	return filepath.Join(s.townRoot, events.EventsFile)
+}
+
+// readEvents returns the event types appended to .events.jsonl since the
+// last call. If the file shrank (pruned or rotated), reading restarts at its
+// new end; events lost that way are not replayed.
+func (s *PluginScheduler) readEvents() map[string]bool {
+	seen := make(map[string]bool)
+
+	f, err := os.Open(s.eventsPath())
+	if err != nil {
+		return seen
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return seen
+	}
+	if info.Size() < s.eventsOffset {
+		s.eventsOffset = info.Size()
+		return seen
+	}
+	if _, err := f.Seek(s.eventsOffset, io.SeekStart); err != nil {
+		return seen
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // EOF or a partial last line: read it next tick
+		}
+		s.eventsOffset += int64(len(line))
+
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(line, &ev) == nil && ev.Type != "" {
+			seen[ev.Type] = true
+		}
+	}
+	return seen
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// lastRun reads a plugin's newest run from the ledger.
+func (s *PluginScheduler) lastRun(recorder *plugin.Recorder) func(string) (time.Time, error) {
+	return func(name string) (time.Time, error) {
+		run, err := recorder.GetLastRun(name)
+		if err != nil || run == nil {
+			return time.Time{}, err
+		}
+		return run.CreatedAt, nil
+	}
+}
+
+// runCheck runs a condition gate's check command from the town root.
+// Exit 0 opens the gate; any other exit status keeps it closed.
+func (s *PluginScheduler) runCheck(p *plugin.Plugin) (bool, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, pluginCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Gate.Check) //nolint:gosec // G204: check comes from a plugin.md the town installed
+	cmd.Dir = s.townRoot
+	cmd.Env = append(os.Environ(), "GT_ROOT="+s.townRoot, "GT_PLUGIN_DIR="+p.Path)
+	util.SetProcessGroup(cmd)
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, fmt.Errorf("check timed out after %s", pluginCheckTimeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// busy reports whether a dog is already working on the plugin, so a slow
+// run isn't dispatched a second time.
+func (s *PluginScheduler) busy(p *plugin.Plugin) bool {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(s.townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	dogs, err := dog.NewManager(s.townRoot, rigsConfig).List()
+	if err != nil {
+		return false
+	}
+	work := "plugin:" + p.Name
+	for _, d := range dogs {
+		if d.State == dog.StateWorking && d.Work == work {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch hands the plugin to an idle dog via gt dog dispatch, the same
+// path the Deacon uses.
+func (s *PluginScheduler) dispatch(p *plugin.Plugin) (string, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, pluginDispatchTimeout)
+	defer cancel()
+
+	args := []string{"dog", "dispatch", "--plugin", p.Name, "--json"}
+	if p.RigName != "" {
+		args = append(args, "--rig", p.RigName)
+	}
+	cmd := exec.CommandContext(ctx, s.gtPath, args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = s.townRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "no idle dogs") {
+			return "", plugin.ErrNoIdleDog
+		}
+		return "", fmt.Errorf("gt dog dispatch: %w (%s)", err, util.FirstLine(msg))
+	}
+
+	var result struct {
+		Dog string `json:"dog"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("parsing gt dog dispatch output: %w", err)
+	}
+	return result.Dog, nil
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestPluginScheduler_ReadEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	old := `{"type":"sling"}` + "\n"
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &PluginScheduler{townRoot: townRoot}
+	s.eventsOffset = fileSize(path)
+
+	appendLine := func(line string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Events from before start are not replayed.
+	if seen := s.readEvents(); len(seen) != 0 {
+		t.Errorf("expected no events at start, got %v", seen)
+	}
+
+	// A partial last line waits for the next tick.
+	appendLine(`{"type":"merged"}` + "\n" + `not json` + "\n" + `{"type":"done"`)
+	seen := s.readEvents()
+	if !seen["merged"] || seen["done"] || seen["sling"] || len(seen) != 1 {
+		t.Errorf("first read = %v, want only merged", seen)
+	}
+	appendLine(`}` + "\n")
+	if seen := s.readEvents(); !seen["done"] || len(seen) != 1 {
+		t.Errorf("second read = %v, want only done", seen)
+	}
+
+	// A truncated file restarts reading at its new end.
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if seen := s.readEvents(); len(seen) != 0 {
+		t.Errorf("read after truncate = %v, want none", seen)
+	}
+	appendLine(`{"type":"merge_failed"}` + "\n")
+	if seen := s.readEvents(); !seen["merge_failed"] || len(seen) != 1 {
+		t.Errorf("read after truncate+append = %v, want merge_failed", seen)
+	}
+}
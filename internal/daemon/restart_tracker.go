@@ -68,6 +68,20 @@ func (rt *RestartTracker) Load() error {
 	return json.Unmarshal(data, rt.state)
 }
 
+// Agents returns a copy of the per-agent restart info.
+func (rt *RestartTracker) Agents() map[string]AgentRestartInfo {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	agents := make(map[string]AgentRestartInfo, len(rt.state.Agents))
+	for id, info := range rt.state.Agents {
+		if info != nil {
+			agents[id] = *info
+		}
+	}
+	return agents
+}
+
 // Save persists the restart state to disk.
 func (rt *RestartTracker) Save() error {
 	rt.mu.RLock()
@@ -59,6 +59,15 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// SessionDeaths is how many crashed sessions were detected since start.
+	SessionDeaths int64 `json:"session_deaths,omitempty"`
+
+	// ConvoyScans is how many stranded-convoy scans have run since start.
+	ConvoyScans int64 `json:"convoy_scans,omitempty"`
+
+	// ConvoyScanFailures is how many stranded-convoy scans failed since start.
+	ConvoyScanFailures int64 `json:"convoy_scan_failures,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -142,6 +151,11 @@ type PatrolsConfig struct {
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	DoltBackups *DoltBackupsConfig `json:"dolt_backups,omitempty"`
+
+	// Plugins controls the daemon's plugin scheduler, which dispatches
+	// plugins with cron, cooldown, condition and event gates to dogs.
+	Plugins *PatrolConfig `json:"plugins,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -164,6 +178,24 @@ type DoltRemotesConfig struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// DoltBackupsConfig holds configuration for the dolt_backups patrol.
+// This patrol takes scheduled local backups of the Dolt databases
+// (see gt dolt backup). Enabled by default when the daemon manages a
+// Dolt server.
+type DoltBackupsConfig struct {
+	// Enabled controls whether scheduled backups run.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to back up (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Hourly, Daily and Weekly are how many restore points to keep per
+	// period (defaults 24, 7 and 4).
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
 	Type      string         `json:"type"`
@@ -224,6 +256,14 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "dolt_backups":
+		if config.Patrols.DoltBackups != nil {
+			return config.Patrols.DoltBackups.Enabled
+		}
+	case "plugins":
+		if config.Patrols.Plugins != nil {
+			return config.Patrols.Plugins.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -243,6 +283,10 @@ func GetPatrolRigs(config *DaemonPatrolConfig, patrol string) []string {
 		if config.Patrols.Witness != nil {
 			return config.Patrols.Witness.Rigs
 		}
+	case "plugins":
+		if config.Patrols.Plugins != nil {
+			return config.Patrols.Plugins.Rigs
+		}
 	}
 	return nil // All rigs
 }
@@ -0,0 +1,161 @@
+package deacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+)
+
+// BudgetState tracks which budget caps the Deacon has escalated today, so
+// each scope is reported once when it crosses its soft cap and once more if
+// it crosses its hard cap. Persisted to deacon/budget-state.json.
+type BudgetState struct {
+	// Day is the local date (YYYY-MM-DD) the alerts are for. Budgets are
+	// daily, so state from an earlier day is discarded.
+	Day string `json:"day"`
+
+	// Escalated maps a scope ("rig:gastown") to the highest level escalated.
+	Escalated map[string]budget.Level `json:"escalated"`
+
+	// LastUpdated is when this state was last written.
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// BudgetAlert is a cap crossing the Deacon escalated (or would, in a dry run).
+type BudgetAlert struct {
+	budget.Status
+	Error string `json:"error,omitempty"`
+}
+
+// BudgetStateFile returns the path to the budget alert state file.
+func BudgetStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "budget-state.json")
+}
+
+// LoadBudgetState loads the budget alert state for day, starting fresh when
+// the file is missing or from another day.
+func LoadBudgetState(townRoot, day string) (*BudgetState, error) {
+	fresh := &BudgetState{Day: day, Escalated: make(map[string]budget.Level)}
+
+	data, err := os.ReadFile(BudgetStateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return nil, fmt.Errorf("reading budget state: %w", err)
+	}
+
+	var state BudgetState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	if state.Day != day {
+		return fresh, nil
+	}
+	if state.Escalated == nil {
+		state.Escalated = make(map[string]budget.Level)
+	}
+	return &state, nil
+}
+
+// SaveBudgetState saves the budget alert state to disk.
+func SaveBudgetState(townRoot string, state *BudgetState) error {
+	stateFile := BudgetStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return fmt.Errorf("creating deacon directory: %w", err)
+	}
+
+	state.LastUpdated = time.Now().UTC()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling budget state: %w", err)
+	}
+	return os.WriteFile(stateFile, data, 0600)
+}
+
+// sendBudgetMail mails an escalation to the Mayor. Replaced in tests.
+var sendBudgetMail = func(townRoot, subject, body string) error {
+	cmd := exec.Command("gt", "mail", "send", "mayor/", "-s", subject, "-m", body)
+	cmd.Dir = townRoot
+	return cmd.Run()
+}
+
+// CheckBudgets escalates to the Mayor every scope that has crossed a cap
+// since it was last escalated today. In a dry run nothing is sent or saved.
+func CheckBudgets(townRoot string, statuses []budget.Status, now time.Time, dryRun bool) ([]BudgetAlert, error) {
+	day := now.Format("2006-01-02")
+	state, err := LoadBudgetState(townRoot, day)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []BudgetAlert
+	for _, st := range statuses {
+		key := st.Scope.String()
+		if levelRank(st.Level) <= levelRank(state.Escalated[key]) {
+			continue
+		}
+		alert := BudgetAlert{Status: st}
+		if !dryRun {
+			subject, body := budgetAlertMail(st, day)
+			if err := sendBudgetMail(townRoot, subject, body); err != nil {
+				alert.Error = err.Error()
+			} else {
+				state.Escalated[key] = st.Level
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if dryRun || len(alerts) == 0 {
+		return alerts, nil
+	}
+	return alerts, SaveBudgetState(townRoot, state)
+}
+
+func levelRank(l budget.Level) int {
+	switch l {
+	case budget.LevelSoft:
+		return 1
+	case budget.LevelHard:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// budgetAlertMail builds the escalation mail for a cap crossing.
+func budgetAlertMail(st budget.Status, day string) (subject, body string) {
+	if st.Level == budget.LevelHard {
+		subject = fmt.Sprintf("BUDGET_HARD_CAP: %s %s $%.2f/$%.2f", st.Scope.Kind, st.Scope.Name, st.SpentUSD, st.Limit.HardUSD)
+		body = fmt.Sprintf(`%s %s has spent $%.2f today (%s), over its $%.2f hard cap.
+
+gt sling and the convoy dispatcher now refuse to spawn polecats for it.
+To let dispatch continue until midnight, approve its override request:
+
+  gt approvals list --status pending
+  gt approvals approve <id> --reason "..."
+
+Or raise the cap in settings/budgets.json.`,
+			st.Scope.Kind, st.Scope.Name, st.SpentUSD, day, st.Limit.HardUSD)
+		return subject, body
+	}
+
+	subject = fmt.Sprintf("BUDGET_SOFT_CAP: %s %s $%.2f/$%.2f", st.Scope.Kind, st.Scope.Name, st.SpentUSD, st.Limit.SoftUSD)
+	body = fmt.Sprintf(`%s %s has spent $%.2f today (%s), over its $%.2f soft cap.`,
+		st.Scope.Kind, st.Scope.Name, st.SpentUSD, day, st.Limit.SoftUSD)
+	if st.Limit.HardUSD > 0 {
+		body += fmt.Sprintf(`
+At $%.2f (the hard cap) no new polecats will be dispatched for it without approval.`, st.Limit.HardUSD)
+	}
+	body += `
+
+Check what is running with: gt costs --today --by convoy`
+	return subject, body
+}
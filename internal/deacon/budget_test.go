@@ -0,0 +1,64 @@
+package deacon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+)
+
+func TestCheckBudgets(t *testing.T) {
+	townRoot := t.TempDir()
+	var subjects []string
+	orig := sendBudgetMail
+	sendBudgetMail = func(_, subject, _ string) error {
+		subjects = append(subjects, subject)
+		return nil
+	}
+	t.Cleanup(func() { sendBudgetMail = orig })
+
+	rig := budget.Scope{Kind: budget.KindRig, Name: "gastown"}
+	convoy := budget.Scope{Kind: budget.KindConvoy, Name: "hq-cv-1"}
+	limit := budget.Limit{SoftUSD: 20, HardUSD: 40}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+
+	statuses := []budget.Status{
+		{Scope: rig, SpentUSD: 25, Limit: limit, Level: budget.LevelSoft},
+		{Scope: convoy, SpentUSD: 5, Limit: limit, Level: budget.LevelOK},
+	}
+
+	// Dry run reports without sending or saving.
+	alerts, err := CheckBudgets(townRoot, statuses, now, true)
+	if err != nil || len(alerts) != 1 || len(subjects) != 0 {
+		t.Fatalf("dry run: alerts=%v err=%v mails=%v", alerts, err, subjects)
+	}
+
+	alerts, err = CheckBudgets(townRoot, statuses, now, false)
+	if err != nil || len(alerts) != 1 || alerts[0].Scope != rig {
+		t.Fatalf("soft crossing: alerts=%v err=%v", alerts, err)
+	}
+	if len(subjects) != 1 || !strings.HasPrefix(subjects[0], "BUDGET_SOFT_CAP: rig gastown") {
+		t.Fatalf("mails = %v", subjects)
+	}
+
+	// Same level again: already escalated.
+	if alerts, _ := CheckBudgets(townRoot, statuses, now.Add(time.Hour), false); len(alerts) != 0 {
+		t.Errorf("re-escalated soft crossing: %v", alerts)
+	}
+
+	// Crossing the hard cap escalates again.
+	statuses[0].SpentUSD, statuses[0].Level = 45, budget.LevelHard
+	if alerts, _ := CheckBudgets(townRoot, statuses, now.Add(2*time.Hour), false); len(alerts) != 1 {
+		t.Errorf("hard crossing alerts = %v", alerts)
+	}
+	if len(subjects) != 2 || !strings.HasPrefix(subjects[1], "BUDGET_HARD_CAP: rig gastown") {
+		t.Fatalf("mails = %v", subjects)
+	}
+
+	// A new day starts fresh.
+	statuses[0].SpentUSD, statuses[0].Level = 21, budget.LevelSoft
+	if alerts, _ := CheckBudgets(townRoot, statuses, now.AddDate(0, 0, 1), false); len(alerts) != 1 {
+		t.Errorf("next day alerts = %v", alerts)
+	}
+}
@@ -20,7 +20,8 @@ var (
 	ErrAlreadyRunning = errors.New("deacon already running")
 )
 
-// tmuxOps abstracts tmux operations for testing.
+// tmuxOps is the subset of session.SessionBackend the deacon manager uses,
+// kept small so tests can mock it.
 type tmuxOps interface {
 	HasSession(name string) (bool, error)
 	IsAgentAlive(session string) bool
@@ -28,7 +29,6 @@ type tmuxOps interface {
 	NewSessionWithCommand(name, workDir, command string) error
 	SetRemainOnExit(pane string, on bool) error
 	SetEnvironment(session, key, value string) error
-	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
 	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
 	SetAutoRespawnHook(session string) error
 	AcceptBypassPermissionsWarning(session string) error
@@ -46,7 +46,7 @@ type Manager struct {
 func NewManager(townRoot string) *Manager {
 	return &Manager{
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     session.NewBackend(townRoot),
 	}
 }
 
@@ -131,8 +131,10 @@ func (m *Manager) Start(agentOverride string) error {
 	}
 
 	// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		theme := tmux.DeaconTheme()
+		_ = realTmux.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	}
 
 	// Wait for Claude to start - fatal if Claude fails to launch
 	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -142,8 +144,8 @@ func (m *Manager) Start(agentOverride string) error {
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if realTmux, ok := t.(*tmux.Tmux); ok {
-		_ = session.TrackSessionPID(m.townRoot, sessionID, realTmux)
+	if backend, ok := t.(session.SessionBackend); ok {
+		_ = session.TrackSessionPID(m.townRoot, sessionID, backend)
 	}
 
 	// PATCH-010: Set auto-respawn hook for Deacon resilience.
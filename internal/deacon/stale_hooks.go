@@ -12,7 +12,6 @@ import (
 
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // StaleHookConfig holds configurable parameters for stale hook detection.
@@ -69,7 +68,7 @@ type StaleHookScanResult struct {
 // ScanStaleHooks finds hooked beads with dead agents and optionally unhooks them.
 // Session liveness is checked for ALL hooked beads regardless of age (gt-pqf9x).
 // A hooked bead is considered stale if:
-//  1. The assignee's tmux session is dead (immediate unhook), OR
+//  1. The assignee's session is dead (immediate unhook), OR
 //  2. The bead is older than MaxAge AND we can't determine session liveness
 //     (e.g., unknown assignee format)
 func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult, error) {
@@ -91,7 +90,7 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 	result.TotalHooked = len(hookedBeads)
 
 	threshold := time.Now().Add(-cfg.MaxAge)
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, bead := range hookedBeads {
 		hookResult := &StaleHookResult{
@@ -0,0 +1,130 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// DoltBackupCheck warns when the town's Dolt databases have no recent good
+// backup. A backup is good when every database was backed up and
+// VerifyDatabases found the server serving all of them.
+type DoltBackupCheck struct {
+	BaseCheck
+	now func() time.Time
+}
+
+// NewDoltBackupCheck creates a new Dolt backup freshness check.
+func NewDoltBackupCheck() *DoltBackupCheck {
+	return &DoltBackupCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "dolt-backup",
+			CheckDescription: "Check that the Dolt databases have a recent verified backup",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		now: time.Now,
+	}
+}
+
+// Run checks the age of the last good backup in the backup manifest.
+func (c *DoltBackupCheck) Run(ctx *CheckContext) *CheckResult {
+	config := doltserver.DefaultConfig(ctx.TownRoot)
+	if config.IsRemote() {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  fmt.Sprintf("Dolt server is remote (%s); back it up where it runs", config.HostPort()),
+			Category: c.CheckCategory,
+		}
+	}
+	databases, err := doltserver.ListRigDatabases(ctx.TownRoot)
+	if err != nil || len(databases) == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No local Dolt databases to back up",
+			Category: c.CheckCategory,
+		}
+	}
+
+	manifest, err := doltserver.LoadBackupManifest(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Could not read Dolt backup manifest: %v", err),
+			FixHint:  "Run 'gt dolt backup' to record a fresh backup",
+			Category: c.CheckCategory,
+		}
+	}
+
+	patrolConfig := daemon.LoadPatrolConfig(ctx.TownRoot)
+	var details []string
+	if !daemon.IsPatrolEnabled(patrolConfig, "dolt_backups") {
+		details = append(details, "Scheduled backups are disabled (patrols.dolt_backups in mayor/daemon.json)")
+	}
+	latestFailed := len(manifest.Snapshots) > 0 && !manifest.Snapshots[0].Good() && !manifest.Snapshots[0].Partial
+	if latestFailed {
+		details = append(details, latestFailureDetails(manifest.Snapshots[0])...)
+	}
+
+	last := manifest.LastGood()
+	if last == nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("No verified backup of %d Dolt database(s)", len(databases)),
+			Details:  append(details, "A damaged database cannot be recovered without one"),
+			FixHint:  "Run 'gt dolt backup' (the daemon backs up hourly while it runs)",
+			Category: c.CheckCategory,
+		}
+	}
+
+	staleAfter := doltserver.BackupStaleAfter
+	if interval := daemon.DoltBackupInterval(patrolConfig); 2*interval > staleAfter {
+		staleAfter = 2 * interval
+	}
+	age := c.now().Sub(last.Time)
+	if age > staleAfter {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Last good Dolt backup is %s old (%s)", formatDuration(age.Round(time.Minute)), last.ID),
+			Details:  details,
+			FixHint:  "Run 'gt dolt backup', and check that the daemon is running ('gt daemon status')",
+			Category: c.CheckCategory,
+		}
+	}
+
+	status := StatusOK
+	if latestFailed {
+		status = StatusWarning
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   status,
+		Message:  fmt.Sprintf("Last good Dolt backup %s ago (%d restore point(s))", formatDuration(age.Round(time.Minute)), len(manifest.Snapshots)),
+		Details:  details,
+		Category: c.CheckCategory,
+	}
+}
+
+// latestFailureDetails explains why the newest backup isn't good.
+func latestFailureDetails(s doltserver.BackupSnapshot) []string {
+	details := []string{fmt.Sprintf("Latest backup %s failed verification", s.ID)}
+	dbs := make([]string, 0, len(s.Errors))
+	for db := range s.Errors {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+	for _, db := range dbs {
+		details = append(details, fmt.Sprintf("%s: %s", db, s.Errors[db]))
+	}
+	for _, db := range s.Missing {
+		details = append(details, fmt.Sprintf("%s: on disk but not served by the Dolt server", db))
+	}
+	return details
+}
@@ -0,0 +1,79 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestDoltBackupCheck_NoDatabases(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "")
+	check := NewDoltBackupCheck()
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK for a town without databases, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestDoltBackupCheck_BackupAge(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "")
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "hq", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	check := NewDoltBackupCheck()
+	check.now = func() time.Time { return now }
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "No verified backup") {
+		t.Errorf("never backed up: got %v: %s", result.Status, result.Message)
+	}
+
+	snapshot := func(age time.Duration) doltserver.BackupSnapshot {
+		ts := now.Add(-age)
+		return doltserver.BackupSnapshot{
+			ID:       ts.Format("20060102-150405"),
+			Time:     ts,
+			Commits:  map[string]string{"hq": strings.Repeat("a", 32)},
+			Verified: true,
+		}
+	}
+	save := func(snaps ...doltserver.BackupSnapshot) {
+		t.Helper()
+		if err := doltserver.SaveBackupManifest(townRoot, &doltserver.BackupManifest{Snapshots: snaps}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(snapshot(30 * time.Minute))
+	result = check.Run(ctx)
+	if result.Status != StatusOK {
+		t.Errorf("fresh backup: got %v: %s", result.Status, result.Message)
+	}
+
+	save(snapshot(3 * 24 * time.Hour))
+	result = check.Run(ctx)
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "old") {
+		t.Errorf("stale backup: got %v: %s", result.Status, result.Message)
+	}
+
+	failed := snapshot(10 * time.Minute)
+	failed.Verified = false
+	failed.Missing = []string{"gastown"}
+	save(failed, snapshot(time.Hour))
+	result = check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Errorf("failed latest backup: got %v: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(strings.Join(result.Details, "\n"), "gastown: on disk but not served") {
+		t.Errorf("details = %v", result.Details)
+	}
+}
@@ -0,0 +1,663 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Scheduled backups use Dolt's own machinery. Each backup run commits every
+// database's working set, tags HEAD as a restore point (gt-backup-<id>), and
+// syncs the database to a Dolt backup under .dolt-backups/mirrors/. The
+// mirror holds the full commit history including the tags, so a database
+// that is damaged on disk can be rebuilt from it, and any restore point (or
+// any commit at all) can be reset to with gt dolt restore --at.
+//
+// The backup manifest (.dolt-backups/snapshots.json) lists restore points
+// newest first. Retention thins it out to hourly, daily and weekly restore
+// points and deletes the tags of the ones it drops; the commits themselves
+// stay in Dolt history.
+
+const (
+	// BackupDirName is the town-root directory holding Dolt backups.
+	BackupDirName = ".dolt-backups"
+
+	// DefaultBackupInterval is how often the daemon takes a backup.
+	DefaultBackupInterval = time.Hour
+
+	// BackupStaleAfter is the age at which the last good backup is
+	// considered too old (gt doctor warns).
+	BackupStaleAfter = 24 * time.Hour
+
+	backupManifestFile  = "snapshots.json"
+	backupTagPrefix     = "gt-backup-"
+	restoreBranchPrefix = "gt-pre-restore-"
+	backupIDFormat      = "20060102-150405"
+	backupTimeout       = 10 * time.Minute
+)
+
+// doltHashPattern matches a Dolt commit hash (32 base32 characters).
+var doltHashPattern = regexp.MustCompile(`^[0-9a-v]{32}$`)
+
+// BackupDir returns the directory holding the town's Dolt backups.
+func BackupDir(townRoot string) string {
+	return filepath.Join(townRoot, BackupDirName)
+}
+
+// BackupMirrorDir returns the Dolt backup directory for one database.
+func BackupMirrorDir(townRoot, db string) string {
+	return filepath.Join(BackupDir(townRoot), "mirrors", db)
+}
+
+// BackupSnapshot is one restore point: the HEAD commit of each database at
+// the time of a backup run.
+type BackupSnapshot struct {
+	// ID is the backup timestamp (UTC, YYYYMMDD-HHMMSS). Each database
+	// carries a gt-backup-<ID> tag on the recorded commit.
+	ID string `json:"id"`
+
+	// Time is when the backup ran.
+	Time time.Time `json:"time"`
+
+	// Commits maps database name to the commit hash that was backed up.
+	Commits map[string]string `json:"commits"`
+
+	// Errors maps database name to the reason its backup failed.
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// Missing lists databases on disk that the server was not serving
+	// when the backup was verified.
+	Missing []string `json:"missing,omitempty"`
+
+	// Verified is true when every database was backed up, every mirror is
+	// present, and VerifyDatabases found the server serving all of them.
+	Verified bool `json:"verified"`
+
+	// Partial is true for a backup of a single database (--db). Partial
+	// snapshots are restore points for that database only; they never
+	// count as the town's last good backup.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Good reports whether the snapshot is a complete, verified backup.
+func (s BackupSnapshot) Good() bool {
+	return s.Verified && !s.Partial && len(s.Errors) == 0
+}
+
+func (s *BackupSnapshot) fail(db, reason string) {
+	if s.Errors == nil {
+		s.Errors = make(map[string]string)
+	}
+	s.Errors[db] = reason
+	s.Verified = false
+}
+
+// BackupManifest is the list of restore points, newest first.
+type BackupManifest struct {
+	Snapshots []BackupSnapshot `json:"snapshots"`
+}
+
+// LoadBackupManifest reads the town's backup manifest. A town that has never
+// been backed up has an empty manifest.
+func LoadBackupManifest(townRoot string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(BackupDir(townRoot), backupManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &BackupManifest{}, nil
+		}
+		return nil, err
+	}
+	var m BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing backup manifest: %w", err)
+	}
+	sortSnapshots(m.Snapshots)
+	return &m, nil
+}
+
+// SaveBackupManifest writes the town's backup manifest.
+func SaveBackupManifest(townRoot string, m *BackupManifest) error {
+	if err := os.MkdirAll(BackupDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+	sortSnapshots(m.Snapshots)
+	return util.AtomicWriteJSON(filepath.Join(BackupDir(townRoot), backupManifestFile), m)
+}
+
+func sortSnapshots(snaps []BackupSnapshot) {
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].Time.After(snaps[j].Time)
+	})
+}
+
+// LastGood returns the newest good snapshot, or nil if there is none.
+func (m *BackupManifest) LastGood() *BackupSnapshot {
+	for i := range m.Snapshots {
+		if m.Snapshots[i].Good() {
+			return &m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// Find returns the snapshot with the given ID, or nil.
+func (m *BackupManifest) Find(id string) *BackupSnapshot {
+	for i := range m.Snapshots {
+		if m.Snapshots[i].ID == id {
+			return &m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// RetentionPolicy is how many restore points to keep per period. Each
+// period keeps its newest good snapshot; the newest snapshot and the newest
+// good one are always kept.
+type RetentionPolicy struct {
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
+// DefaultRetention keeps a day of hourly, a week of daily and a month of
+// weekly restore points.
+var DefaultRetention = RetentionPolicy{Hourly: 24, Daily: 7, Weekly: 4}
+
+// Select splits snapshots into those the policy keeps and those it drops.
+// Both are returned newest first.
+func (p RetentionPolicy) Select(snaps []BackupSnapshot) (keep, drop []BackupSnapshot) {
+	sorted := append([]BackupSnapshot(nil), snaps...)
+	sortSnapshots(sorted)
+	if len(sorted) == 0 {
+		return nil, nil
+	}
+
+	kept := make([]bool, len(sorted))
+	kept[0] = true
+	period := func(n int, key func(time.Time) string) {
+		seen := make(map[string]bool)
+		for i, s := range sorted {
+			if len(seen) >= n {
+				return
+			}
+			if !s.Good() {
+				continue
+			}
+			k := key(s.Time.Local())
+			if !seen[k] {
+				seen[k] = true
+				kept[i] = true
+			}
+		}
+	}
+	period(p.Hourly, func(t time.Time) string { return t.Format("2006-01-02 15") })
+	period(p.Daily, func(t time.Time) string { return t.Format("2006-01-02") })
+	period(p.Weekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	for i, s := range sorted {
+		if s.Good() {
+			kept[i] = true
+			break
+		}
+	}
+
+	for i, s := range sorted {
+		if kept[i] {
+			keep = append(keep, s)
+		} else {
+			drop = append(drop, s)
+		}
+	}
+	return keep, drop
+}
+
+// BackupOptions controls BackupDatabases.
+type BackupOptions struct {
+	// Filter restricts the backup to a single database. Empty means all.
+	Filter string
+
+	// Retention is applied after the backup. Zero means DefaultRetention.
+	Retention RetentionPolicy
+
+	// NoPrune skips retention.
+	NoPrune bool
+}
+
+// BackupResult records the outcome of a backup run.
+type BackupResult struct {
+	// Snapshot is the restore point the run recorded.
+	Snapshot BackupSnapshot
+
+	// Pruned lists the restore points retention dropped.
+	Pruned []BackupSnapshot
+}
+
+// BackupDatabases backs up the town's databases through the running Dolt
+// server, verifies the result, records the restore point and applies
+// retention. Per-database failures are recorded in the snapshot rather than
+// returned; the error is for failures that prevent a backup altogether.
+func BackupDatabases(townRoot string, opts BackupOptions) (*BackupResult, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — backups require local server access", config.HostPort())
+	}
+	if err := CheckServerReachable(townRoot); err != nil {
+		return nil, fmt.Errorf("Dolt server not reachable: %w", err)
+	}
+
+	if err := os.MkdirAll(BackupDir(townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating backup directory: %w", err)
+	}
+	fileLock := flock.New(filepath.Join(BackupDir(townRoot), "backup.lock"))
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring backup lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("another Dolt backup is in progress")
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	databases, err := backupDatabaseNames(townRoot, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no databases to back up")
+	}
+
+	manifest, err := LoadBackupManifest(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	snap := BackupSnapshot{
+		ID:      now.Format(backupIDFormat),
+		Time:    now,
+		Commits: make(map[string]string),
+		Partial: opts.Filter != "",
+	}
+	if manifest.Find(snap.ID) != nil {
+		return nil, fmt.Errorf("backup %s already exists; try again in a second", snap.ID)
+	}
+
+	for _, db := range databases {
+		hash, err := backupDatabase(townRoot, db, snap.ID)
+		if err != nil {
+			snap.fail(db, err.Error())
+			continue
+		}
+		snap.Commits[db] = hash
+	}
+
+	// Verify: every database backed up and mirrored, and the server serving
+	// everything on disk. A database the server silently skipped cannot
+	// have been backed up from its real state.
+	snap.Verified = len(snap.Errors) == 0
+	for db := range snap.Commits {
+		if !mirrorPresent(BackupMirrorDir(townRoot, db)) {
+			snap.fail(db, "backup mirror missing after sync")
+		}
+	}
+	_, missing, verifyErr := VerifyDatabases(townRoot)
+	if verifyErr != nil {
+		snap.fail("(verify)", verifyErr.Error())
+	}
+	if len(missing) > 0 {
+		snap.Verified = false
+		snap.Missing = missing
+	}
+
+	result := &BackupResult{Snapshot: snap}
+	manifest.Snapshots = append([]BackupSnapshot{snap}, manifest.Snapshots...)
+	if !opts.NoPrune {
+		policy := opts.Retention
+		if policy == (RetentionPolicy{}) {
+			policy = DefaultRetention
+		}
+		keep, drop := policy.Select(manifest.Snapshots)
+		for _, s := range drop {
+			for db := range s.Commits {
+				// Best-effort: the tag may already be gone (restored
+				// database, removed rig).
+				_ = doltSQL(townRoot, db, fmt.Sprintf("CALL DOLT_TAG('-d', '%s%s')", backupTagPrefix, s.ID))
+			}
+		}
+		manifest.Snapshots = keep
+		result.Pruned = drop
+	}
+
+	if err := SaveBackupManifest(townRoot, manifest); err != nil {
+		return result, fmt.Errorf("saving backup manifest: %w", err)
+	}
+	return result, nil
+}
+
+// backupDatabaseNames lists the databases to back up. The event store is
+// left out: it is an index rebuilt from the town's logs.
+func backupDatabaseNames(townRoot, filter string) ([]string, error) {
+	all, err := ListRigDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	var databases []string
+	for _, db := range all {
+		if filter != "" && db != filter {
+			continue
+		}
+		databases = append(databases, db)
+	}
+	if filter != "" && len(databases) == 0 {
+		return nil, fmt.Errorf("database %q not found", filter)
+	}
+	return databases, nil
+}
+
+// backupDatabase commits the database's working set, tags HEAD as restore
+// point id and syncs the database to its mirror. Returns the tagged commit.
+func backupDatabase(townRoot, db, id string) (string, error) {
+	if err := doltSQLWithRetry(townRoot, db, "CALL DOLT_ADD('-A')"); err != nil {
+		return "", fmt.Errorf("staging working set: %w", err)
+	}
+	commit := fmt.Sprintf("CALL DOLT_COMMIT('-m', 'gt dolt backup %s')", id)
+	if err := doltSQLWithRetry(townRoot, db, commit); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "nothing to commit") {
+			return "", fmt.Errorf("committing working set: %w", err)
+		}
+	}
+
+	tag := backupTagPrefix + id
+	if err := doltSQLWithRetry(townRoot, db, fmt.Sprintf("CALL DOLT_TAG('%s', 'HEAD')", tag)); err != nil {
+		return "", fmt.Errorf("tagging restore point: %w", err)
+	}
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf("SELECT tag_hash FROM `%s`.dolt_tags WHERE tag_name = '%s'", db, tag))
+	if err != nil {
+		return "", fmt.Errorf("reading restore point: %w", err)
+	}
+	rows := parseSimpleCSV(out)
+	if len(rows) == 0 || !doltHashPattern.MatchString(rows[0]["tag_hash"]) {
+		return "", fmt.Errorf("reading restore point: unexpected output %q", strings.TrimSpace(out))
+	}
+	hash := rows[0]["tag_hash"]
+
+	mirror := BackupMirrorDir(townRoot, db)
+	if err := os.MkdirAll(mirror, 0755); err != nil {
+		return "", fmt.Errorf("creating backup mirror: %w", err)
+	}
+	sync := fmt.Sprintf("CALL DOLT_BACKUP('sync-url', '%s')", fileURL(mirror))
+	if err := doltBackupSQL(townRoot, db, sync); err != nil {
+		return "", fmt.Errorf("syncing backup: %w", err)
+	}
+	return hash, nil
+}
+
+// doltBackupSQL is doltSQL with a timeout long enough for a first full sync
+// of a large database.
+func doltBackupSQL(townRoot, db, query string) error {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	cmd := buildDoltSQLCmd(ctx, config, "-q", fmt.Sprintf("USE %s; %s", db, query))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// fileURL returns the file:// URL Dolt uses for a local backup directory.
+func fileURL(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs // Windows drive paths: file:///C:/...
+	}
+	return "file://" + abs
+}
+
+// mirrorPresent reports whether a backup mirror directory has content.
+func mirrorPresent(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// BackupMirrors returns the databases that have a backup mirror.
+func BackupMirrors(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(BackupDir(townRoot), "mirrors"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var databases []string
+	for _, entry := range entries {
+		if entry.IsDir() && mirrorPresent(filepath.Join(BackupDir(townRoot), "mirrors", entry.Name())) {
+			databases = append(databases, entry.Name())
+		}
+	}
+	return databases, nil
+}
+
+// RestoreOptions controls RestoreDatabases.
+type RestoreOptions struct {
+	// At is a backup ID, a commit hash or a time (see ParseRestoreTime).
+	At string
+
+	// Filter restricts the restore to a single database. Empty means all.
+	Filter string
+
+	// DryRun resolves targets without resetting anything.
+	DryRun bool
+}
+
+// RestoreResult records the outcome of restoring a single database.
+type RestoreResult struct {
+	// Database is the database name.
+	Database string
+
+	// Target is the commit the database was (or would be) reset to.
+	Target string
+
+	// SafetyBranch holds the database's state from before the restore.
+	SafetyBranch string
+
+	// Restored is true if the reset succeeded.
+	Restored bool
+
+	// DryRun is true if this was a dry-run.
+	DryRun bool
+
+	// Error is non-nil if resolving or restoring failed.
+	Error error
+}
+
+// RestoreDatabases resets each database's main branch to its state at
+// opts.At, through the running Dolt server. The state being replaced,
+// including uncommitted changes, is first committed and kept on a
+// gt-pre-restore-<timestamp> branch, so a restore can itself be undone.
+// Never fails fast — collects all results.
+func RestoreDatabases(townRoot string, opts RestoreOptions) []RestoreResult {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return []RestoreResult{{
+			Database: "(server)",
+			Error:    fmt.Errorf("Dolt server is remote (%s) — restore requires local server access", config.HostPort()),
+		}}
+	}
+	if err := CheckServerReachable(townRoot); err != nil {
+		return []RestoreResult{{Database: "(server)", Error: fmt.Errorf("Dolt server not reachable: %w", err)}}
+	}
+	databases, err := backupDatabaseNames(townRoot, opts.Filter)
+	if err != nil {
+		return []RestoreResult{{Database: "(list)", Error: err}}
+	}
+	manifest, err := LoadBackupManifest(townRoot)
+	if err != nil {
+		return []RestoreResult{{Database: "(manifest)", Error: err}}
+	}
+
+	now := time.Now()
+	branch := restoreBranchPrefix + now.UTC().Format(backupIDFormat)
+	var results []RestoreResult
+	for _, db := range databases {
+		result := RestoreResult{Database: db, DryRun: opts.DryRun}
+		target, err := resolveRestoreTarget(opts.At, manifest, db, now, func(t time.Time) (string, error) {
+			return commitAt(townRoot, db, t)
+		})
+		if err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		result.Target = target
+		if opts.DryRun {
+			results = append(results, result)
+			continue
+		}
+
+		script := fmt.Sprintf(`USE %s;
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'gt dolt restore: state before restoring to %s');
+CALL DOLT_BRANCH('%s');
+CALL DOLT_RESET('--hard', '%s');
+`, db, target, branch, target)
+		if err := doltSQLScriptWithRetry(townRoot, script); err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		result.SafetyBranch = branch
+		result.Restored = true
+		results = append(results, result)
+	}
+	return results
+}
+
+// resolveRestoreTarget turns a --at value into a commit hash for db. A
+// backup ID resolves to that restore point, a commit hash to itself, and
+// anything else is parsed as a time and resolved by commitAt.
+func resolveRestoreTarget(at string, manifest *BackupManifest, db string, now time.Time, commitAt func(time.Time) (string, error)) (string, error) {
+	at = strings.TrimSpace(at)
+	if s := manifest.Find(at); s != nil {
+		hash, ok := s.Commits[db]
+		if !ok {
+			return "", fmt.Errorf("backup %s has no restore point for %s", at, db)
+		}
+		return hash, nil
+	}
+	if doltHashPattern.MatchString(at) {
+		return at, nil
+	}
+	t, err := ParseRestoreTime(at, now)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a backup ID, commit hash or time", at)
+	}
+	return commitAt(t)
+}
+
+// commitAt returns the newest commit on db's main branch made at or before t.
+func commitAt(townRoot, db string, t time.Time) (string, error) {
+	query := fmt.Sprintf("SELECT commit_hash FROM `%s`.dolt_log WHERE date <= '%s' ORDER BY date DESC LIMIT 1",
+		db, t.UTC().Format("2006-01-02 15:04:05.000"))
+	out, err := doltSQLQuery(townRoot, query)
+	if err != nil {
+		return "", err
+	}
+	rows := parseSimpleCSV(out)
+	if len(rows) == 0 || !doltHashPattern.MatchString(rows[0]["commit_hash"]) {
+		return "", fmt.Errorf("no commit in %s at or before %s", db, t.Local().Format("2006-01-02 15:04:05"))
+	}
+	return rows[0]["commit_hash"], nil
+}
+
+// ParseRestoreTime parses a restore time: a duration ago (90m, 6h, 2d), a
+// date (2026-03-09, local midnight), a local time (2026-03-09T14:00 or
+// "2026-03-09 14:00:05") or an RFC 3339 timestamp.
+func ParseRestoreTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+// RestoreFromMirror rebuilds a database from its backup mirror, for when the
+// database on disk is damaged. The damaged directory is moved aside under
+// .dolt-backups/damaged/ and its path returned. The Dolt server must be
+// stopped. The rebuilt database is at its last backed-up state; use
+// RestoreDatabases afterwards to go further back.
+func RestoreFromMirror(townRoot, db string) (string, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return "", fmt.Errorf("Dolt server is remote (%s) — restore requires local server access", config.HostPort())
+	}
+	if running, _, _ := IsRunning(townRoot); running {
+		return "", fmt.Errorf("Dolt server is running; stop it before restoring from a backup mirror")
+	}
+	mirror := BackupMirrorDir(townRoot, db)
+	if !mirrorPresent(mirror) {
+		return "", fmt.Errorf("no backup mirror for %s", db)
+	}
+
+	dbDir := filepath.Join(config.DataDir, db)
+	aside := ""
+	if _, err := os.Stat(dbDir); err == nil {
+		damaged := filepath.Join(BackupDir(townRoot), "damaged")
+		if err := os.MkdirAll(damaged, 0755); err != nil {
+			return "", fmt.Errorf("creating damaged directory: %w", err)
+		}
+		aside = filepath.Join(damaged, db+"-"+time.Now().UTC().Format(backupIDFormat))
+		if err := moveDir(dbDir, aside); err != nil {
+			return "", fmt.Errorf("moving %s aside: %w", db, err)
+		}
+	}
+	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+		return aside, fmt.Errorf("creating data directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", "backup", "restore", fileURL(mirror), db)
+	cmd.Dir = config.DataDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// Put the original back rather than leave the rig without a database.
+		_ = os.RemoveAll(dbDir)
+		if aside != "" {
+			_ = moveDir(aside, dbDir)
+			aside = ""
+		}
+		return aside, fmt.Errorf("dolt backup restore: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return aside, nil
+}
@@ -0,0 +1,203 @@
+package doltserver
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func goodSnapshot(t time.Time) BackupSnapshot {
+	return BackupSnapshot{
+		ID:       t.UTC().Format(backupIDFormat),
+		Time:     t,
+		Commits:  map[string]string{"hq": strings.Repeat("a", 32)},
+		Verified: true,
+	}
+}
+
+func TestRetentionPolicy_Select(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 30, 0, 0, time.Local)
+
+	// Two backups an hour for three days, newest first.
+	var snaps []BackupSnapshot
+	for i := 0; i < 6*24; i++ {
+		snaps = append(snaps, goodSnapshot(now.Add(-time.Duration(i)*30*time.Minute)))
+	}
+
+	keep, drop := RetentionPolicy{Hourly: 3, Daily: 2, Weekly: 1}.Select(snaps)
+	if len(keep)+len(drop) != len(snaps) {
+		t.Fatalf("keep %d + drop %d != %d", len(keep), len(drop), len(snaps))
+	}
+
+	// Newest of each of the last 3 hours (12:30, 11:30, 10:30); today's and
+	// yesterday's newest are 12:30 and 23:30; this week's newest is 12:30.
+	want := []time.Time{
+		now,
+		now.Add(-1 * time.Hour),
+		now.Add(-2 * time.Hour),
+		time.Date(2026, 3, 8, 23, 30, 0, 0, time.Local),
+	}
+	if len(keep) != len(want) {
+		t.Fatalf("kept %d snapshots, want %d: %v", len(keep), len(want), snapshotTimes(keep))
+	}
+	for i, w := range want {
+		if !keep[i].Time.Equal(w) {
+			t.Errorf("keep[%d] = %v, want %v", i, keep[i].Time, w)
+		}
+	}
+}
+
+func TestRetentionPolicy_SelectKeepsNewestAndLastGood(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.Local)
+	failed := goodSnapshot(now)
+	failed.Verified = false
+	failed.Errors = map[string]string{"hq": "sync failed"}
+	partial := goodSnapshot(now.Add(-time.Hour))
+	partial.Partial = true
+	good := goodSnapshot(now.AddDate(0, 0, -30))
+
+	keep, drop := RetentionPolicy{Hourly: 1}.Select([]BackupSnapshot{good, partial, failed})
+	if len(keep) != 2 || keep[0].ID != failed.ID || keep[1].ID != good.ID {
+		t.Errorf("keep = %v, want the failed newest and the month-old good one", snapshotTimes(keep))
+	}
+	if len(drop) != 1 || drop[0].ID != partial.ID {
+		t.Errorf("drop = %v, want the partial snapshot", snapshotTimes(drop))
+	}
+
+	if keep, drop := DefaultRetention.Select(nil); keep != nil || drop != nil {
+		t.Errorf("Select(nil) = %v, %v", keep, drop)
+	}
+}
+
+func snapshotTimes(snaps []BackupSnapshot) []string {
+	var out []string
+	for _, s := range snaps {
+		out = append(out, s.Time.Format("01-02 15:04"))
+	}
+	return out
+}
+
+func TestBackupManifest_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	m, err := LoadBackupManifest(townRoot)
+	if err != nil {
+		t.Fatalf("LoadBackupManifest on a fresh town: %v", err)
+	}
+	if len(m.Snapshots) != 0 || m.LastGood() != nil {
+		t.Fatalf("fresh manifest = %+v", m)
+	}
+
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	older := goodSnapshot(now.Add(-2 * time.Hour))
+	newer := goodSnapshot(now)
+	newer.Verified = false
+	newer.Missing = []string{"gastown"}
+	m.Snapshots = []BackupSnapshot{older, newer}
+	if err := SaveBackupManifest(townRoot, m); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadBackupManifest(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Snapshots) != 2 || m.Snapshots[0].ID != newer.ID {
+		t.Fatalf("loaded snapshots not newest first: %+v", m.Snapshots)
+	}
+	if last := m.LastGood(); last == nil || last.ID != older.ID {
+		t.Errorf("LastGood = %+v, want %s", last, older.ID)
+	}
+	if s := m.Find(newer.ID); s == nil || len(s.Missing) != 1 {
+		t.Errorf("Find(%s) = %+v", newer.ID, s)
+	}
+	if m.Find("20200101-000000") != nil {
+		t.Error("Find returned a snapshot for an unknown ID")
+	}
+}
+
+func TestParseRestoreTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2d", now.AddDate(0, 0, -2)},
+		{"2026-03-09", time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)},
+		{"2026-03-09T14:05", time.Date(2026, 3, 9, 14, 5, 0, 0, time.Local)},
+		{"2026-03-09 14:05:30", time.Date(2026, 3, 9, 14, 5, 30, 0, time.Local)},
+		{"2026-03-09T14:05:00Z", time.Date(2026, 3, 9, 14, 5, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseRestoreTime(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseRestoreTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseRestoreTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "soon", "-2h", "2026-13-01"} {
+		if _, err := ParseRestoreTime(bad, now); err == nil {
+			t.Errorf("ParseRestoreTime(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestResolveRestoreTarget(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+	snap := goodSnapshot(now.Add(-time.Hour))
+	manifest := &BackupManifest{Snapshots: []BackupSnapshot{snap}}
+
+	var askedFor time.Time
+	commitAt := func(t time.Time) (string, error) {
+		askedFor = t
+		return strings.Repeat("c", 32), nil
+	}
+
+	got, err := resolveRestoreTarget(snap.ID, manifest, "hq", now, commitAt)
+	if err != nil || got != snap.Commits["hq"] {
+		t.Errorf("backup ID resolved to %q, %v", got, err)
+	}
+	if _, err := resolveRestoreTarget(snap.ID, manifest, "gastown", now, commitAt); err == nil {
+		t.Error("backup ID resolved for a database it does not cover")
+	}
+
+	hash := "0123456789abcdefghijklmnopqrstuv"
+	if got, err := resolveRestoreTarget(hash, manifest, "hq", now, commitAt); err != nil || got != hash {
+		t.Errorf("commit hash resolved to %q, %v", got, err)
+	}
+
+	got, err = resolveRestoreTarget("2h", manifest, "hq", now, commitAt)
+	if err != nil || got != strings.Repeat("c", 32) {
+		t.Errorf("time resolved to %q, %v", got, err)
+	}
+	if !askedFor.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("commitAt asked for %v", askedFor)
+	}
+
+	if _, err := resolveRestoreTarget("main; DROP TABLE issues", manifest, "hq", now, commitAt); err == nil {
+		t.Error("resolved an arbitrary string")
+	}
+
+	noCommit := func(time.Time) (string, error) { return "", errors.New("no commit") }
+	if _, err := resolveRestoreTarget("2026-01-01", manifest, "hq", now, noCommit); err == nil {
+		t.Error("expected the commitAt error to propagate")
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix paths")
+	}
+	got := fileURL("/town/.dolt-backups/mirrors/hq")
+	if got != "file:///town/.dolt-backups/mirrors/hq" {
+		t.Errorf("fileURL = %q", got)
+	}
+}
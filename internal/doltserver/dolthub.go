@@ -51,53 +51,18 @@ func DoltHubRemoteURL(org, repo string) string {
 // CreateDoltHubRepo creates a private repository on DoltHub via the API.
 // Returns nil if the repo was created or already exists.
 func CreateDoltHubRepo(org, repo, token string) error {
-	body := map[string]string{
-		"ownerName":  org,
-		"repoName":   repo,
-		"visibility": "private",
-	}
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("marshaling request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", dolthubAPIBase+"/database", bytes.NewReader(payload))
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("authorization", "token "+token)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("DoltHub API request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	// 200 = created, 409 or similar = already exists (both are fine)
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil
-	}
-
-	// Parse error response for better messaging
-	var errResp struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-	}
-	if decErr := json.NewDecoder(resp.Body).Decode(&errResp); decErr == nil {
-		// "already exists" is not an error for our purposes
-		if strings.Contains(strings.ToLower(errResp.Message), "already exists") {
-			return nil
-		}
-		return fmt.Errorf("DoltHub API error (HTTP %d): %s", resp.StatusCode, errResp.Message)
-	}
-	return fmt.Errorf("DoltHub API error (HTTP %d)", resp.StatusCode)
+	return (&DoltHubProvider{Token: token}).CreateRepo(org, repo)
 }
 
 // AddRemote adds a DoltHub origin remote to a local Dolt database directory.
 // Skips if an origin remote already exists.
 func AddRemote(dbDir, org, repo string) error {
+	return AddRemoteURL(dbDir, DoltHubRemoteURL(org, repo))
+}
+
+// AddRemoteURL adds an origin remote with the given URL to a local Dolt
+// database directory. Skips if an origin remote already exists.
+func AddRemoteURL(dbDir, url string) error {
 	// Check if origin already exists
 	existing, err := HasRemote(dbDir)
 	if err != nil {
@@ -107,7 +72,6 @@ func AddRemote(dbDir, org, repo string) error {
 		return nil // Already has a remote
 	}
 
-	url := DoltHubRemoteURL(org, repo)
 	cmd := exec.Command("dolt", "remote", "add", "origin", url)
 	cmd.Dir = dbDir
 	output, err := cmd.CombinedOutput()
@@ -122,20 +86,20 @@ func AddRemote(dbDir, org, repo string) error {
 	return nil
 }
 
-// SetupDoltHubRemote creates a DoltHub repo, adds the remote, and does an
-// initial push. Each step is fail-fast — the function returns on the first
-// error because each step requires the previous to succeed (can't add a
+// SetupRemote creates a repo with the remote provider, adds it as origin, and
+// does an initial push. Each step is fail-fast — the function returns on the
+// first error because each step requires the previous to succeed (can't add a
 // remote if repo creation failed, can't push if the remote wasn't added).
-func SetupDoltHubRemote(dbDir, org, dbName, token string) error {
+func SetupRemote(dbDir string, provider RemoteProvider, org, dbName string) error {
 	repo := DoltHubRepoName(dbName)
 
-	// Step 1: Create the DoltHub repo
-	if err := CreateDoltHubRepo(org, repo, token); err != nil {
-		return fmt.Errorf("creating DoltHub repo %s/%s: %w", org, repo, err)
+	// Step 1: Create the repo
+	if err := provider.CreateRepo(org, repo); err != nil {
+		return fmt.Errorf("creating %s repo %s/%s: %w", provider.Name(), org, repo, err)
 	}
 
 	// Step 2: Add the remote locally
-	if err := AddRemote(dbDir, org, repo); err != nil {
+	if err := AddRemoteURL(dbDir, provider.URL(org, repo)); err != nil {
 		return fmt.Errorf("adding remote for %s/%s: %w", org, repo, err)
 	}
 
@@ -146,3 +110,96 @@ func SetupDoltHubRemote(dbDir, org, dbName, token string) error {
 
 	return nil
 }
+
+// DoltHubProvider is the RemoteProvider for DoltHub. Repos are created and
+// forked through the DoltHub REST API, and pushed to over its remote API.
+type DoltHubProvider struct {
+	// Token is the DoltHub API token (DOLTHUB_TOKEN).
+	Token string
+
+	// APIBase overrides the DoltHub REST API base URL (tests only).
+	APIBase string
+}
+
+// Name implements RemoteProvider.
+func (p *DoltHubProvider) Name() string { return RemoteProviderDoltHub }
+
+// URL implements RemoteProvider.
+func (p *DoltHubProvider) URL(org, repo string) string {
+	return DoltHubRemoteURL(org, repo)
+}
+
+// Check implements RemoteProvider. Creating and forking need an API token.
+func (p *DoltHubProvider) Check() error {
+	if p.Token == "" {
+		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+	}
+	return nil
+}
+
+// CreateRepo implements RemoteProvider by creating a private repository.
+func (p *DoltHubProvider) CreateRepo(org, repo string) error {
+	body := map[string]string{
+		"ownerName":  org,
+		"repoName":   repo,
+		"visibility": "private",
+	}
+	return p.post("/database", body, 30*time.Second, "DoltHub API")
+}
+
+// ForkRepo implements RemoteProvider using the DoltHub fork API.
+func (p *DoltHubProvider) ForkRepo(fromOrg, repo, toOrg string) error {
+	body := map[string]string{
+		"owner_name":     toOrg,
+		"new_repo_name":  repo,
+		"from_owner":     fromOrg,
+		"from_repo_name": repo,
+	}
+	return p.post("/database/fork", body, 60*time.Second, "DoltHub fork API")
+}
+
+// post sends an authenticated JSON request to the DoltHub API. A response
+// saying the repo already exists counts as success.
+func (p *DoltHubProvider) post(path string, body map[string]string, timeout time.Duration, what string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	base := p.APIBase
+	if base == "" {
+		base = dolthubAPIBase
+	}
+	req, err := http.NewRequest("POST", base+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("authorization", "token "+p.Token)
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", what, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// 200 = created, 409 or similar = already exists (both are fine)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	// Parse error response for better messaging
+	var errResp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if decErr := json.NewDecoder(resp.Body).Decode(&errResp); decErr == nil {
+		// "already exists" is not an error for our purposes
+		if strings.Contains(strings.ToLower(errResp.Message), "already exists") {
+			return nil
+		}
+		return fmt.Errorf("%s error (HTTP %d): %s", what, resp.StatusCode, errResp.Message)
+	}
+	return fmt.Errorf("%s error (HTTP %d)", what, resp.StatusCode)
+}
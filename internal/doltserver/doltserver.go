@@ -128,6 +128,9 @@ const (
 	DefaultMaxConnections = 50     // Conservative default to prevent connection storms
 )
 
+// EventsDB is the database name for the town's event store (see eventstore).
+const EventsDB = "gt_events"
+
 // metadataMu provides per-path mutexes for EnsureMetadata goroutine synchronization.
 // flock is inter-process only and cannot reliably synchronize goroutines within the
 // same process (the same process may acquire the same flock twice without blocking).
@@ -242,6 +245,16 @@ func (c *Config) userDSN() string {
 	return c.User
 }
 
+// DSN returns a go-sql-driver/mysql data source name for database (empty for
+// none), with the password in clear. params are appended as the query string.
+func (c *Config) DSN(database, params string) string {
+	dsn := fmt.Sprintf("%s@tcp(%s)/%s", c.userDSN(), c.HostPort(), database)
+	if params != "" {
+		dsn += "?" + params
+	}
+	return dsn
+}
+
 // HostPort returns "host:port", defaulting host to "127.0.0.1" when empty.
 func (c *Config) HostPort() string {
 	host := c.Host
@@ -767,7 +780,8 @@ func (c *Config) displayDSN() string {
 	return c.User
 }
 
-// ListDatabases returns the list of available rig databases.
+// ListDatabases returns the list of available databases, including the
+// town's event store. Use ListRigDatabases for beads databases only.
 // For local servers, scans the data directory on disk.
 // For remote servers, queries SHOW DATABASES via SQL.
 func ListDatabases(townRoot string) ([]string, error) {
@@ -800,6 +814,22 @@ func ListDatabases(townRoot string) ([]string, error) {
 	return databases, nil
 }
 
+// ListRigDatabases returns the available beads databases: every database
+// except the event store, which belongs to no rig.
+func ListRigDatabases(townRoot string) ([]string, error) {
+	all, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var databases []string
+	for _, db := range all {
+		if db != EventsDB {
+			databases = append(databases, db)
+		}
+	}
+	return databases, nil
+}
+
 // listDatabasesRemote queries SHOW DATABASES on a remote Dolt server.
 func listDatabasesRemote(config *Config) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -1317,7 +1347,7 @@ type OrphanedDatabase struct {
 // by any rig's metadata.json dolt_database field. These orphans consume disk space
 // and are served by the Dolt server unnecessarily.
 func FindOrphanedDatabases(townRoot string) ([]OrphanedDatabase, error) {
-	databases, err := ListDatabases(townRoot)
+	databases, err := ListRigDatabases(townRoot)
 	if err != nil {
 		return nil, fmt.Errorf("listing databases: %w", err)
 	}
@@ -1607,7 +1637,7 @@ func EnsureMetadata(townRoot, rigName string) error {
 // Dolt server. This is the fix for the split-brain problem where worktrees
 // each have their own isolated database.
 func EnsureAllMetadata(townRoot string) (updated []string, errs []error) {
-	databases, err := ListDatabases(townRoot)
+	databases, err := ListRigDatabases(townRoot)
 	if err != nil {
 		return nil, []error{fmt.Errorf("listing databases: %w", err)}
 	}
@@ -462,6 +462,38 @@ func TestEnsureAllMetadata(t *testing.T) {
 	}
 }
 
+func TestRigDatabaseEnumerationSkipsEventStore(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, name := range []string{"hq", EventsDB} {
+		doltDir := filepath.Join(townRoot, ".dolt-data", name, ".dolt")
+		if err := os.MkdirAll(doltDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if all, _ := ListDatabases(townRoot); len(all) != 2 {
+		t.Fatalf("ListDatabases = %v, want hq and %s", all, EventsDB)
+	}
+	if rigs, _ := ListRigDatabases(townRoot); len(rigs) != 1 || rigs[0] != "hq" {
+		t.Errorf("ListRigDatabases = %v, want [hq]", rigs)
+	}
+
+	updated, errs := EnsureAllMetadata(townRoot)
+	if len(errs) > 0 || len(updated) != 1 || updated[0] != "hq" {
+		t.Errorf("EnsureAllMetadata = %v, %v; want only hq", updated, errs)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, EventsDB)); !os.IsNotExist(err) {
+		t.Errorf("EnsureAllMetadata created a %s rig directory", EventsDB)
+	}
+
+	for _, r := range SyncDatabases(townRoot, SyncOptions{Filter: EventsDB}) {
+		t.Errorf("SyncDatabases synced %s: %+v", r.Database, r)
+	}
+}
+
 func TestFindRigBeadsDir(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -0,0 +1,155 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Remote provider names, as accepted by GT_DOLT_REMOTE.
+const (
+	RemoteProviderDoltHub = "dolthub"
+	RemoteProviderFile    = "file"
+)
+
+// RemoteProvider hosts the Dolt repos that towns push to, fork and clone from:
+// the rig database remotes set up by 'gt dolt sync' and the wasteland commons.
+// DoltHub is the default. FileRemote keeps every repo under a local or shared
+// directory, so a team can run a private wasteland on an internal box and the
+// whole flow works without network access.
+type RemoteProvider interface {
+	// Name identifies the provider ("dolthub", "file").
+	Name() string
+
+	// URL returns the dolt remote URL for org/repo, usable with
+	// 'dolt clone', 'dolt remote add', push and pull.
+	URL(org, repo string) string
+
+	// Check reports why the provider can't create or fork repos (for
+	// example, missing credentials). Cloning may still work.
+	Check() error
+
+	// CreateRepo creates an empty repo. Returns nil if it already exists.
+	CreateRepo(org, repo string) error
+
+	// ForkRepo copies fromOrg/repo to toOrg/repo. Returns nil if the fork
+	// already exists.
+	ForkRepo(fromOrg, repo, toOrg string) error
+}
+
+// ParseRemoteProvider returns the provider for a GT_DOLT_REMOTE value:
+// "" or "dolthub" for DoltHub, or a file:// URL or absolute path for a
+// FileRemote rooted there.
+func ParseRemoteProvider(spec string) (RemoteProvider, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "" || spec == RemoteProviderDoltHub:
+		return &DoltHubProvider{Token: DoltHubToken()}, nil
+	case strings.HasPrefix(spec, "file://"):
+		root := filepath.FromSlash(strings.TrimPrefix(spec, "file://"))
+		if !filepath.IsAbs(root) {
+			return nil, fmt.Errorf("remote %q: file:// URL must hold an absolute path", spec)
+		}
+		return &FileRemote{Root: filepath.Clean(root)}, nil
+	case filepath.IsAbs(spec):
+		return &FileRemote{Root: filepath.Clean(spec)}, nil
+	}
+	return nil, fmt.Errorf("unknown remote %q: want %q, a file:// URL or an absolute path", spec, RemoteProviderDoltHub)
+}
+
+// RemoteProviderFromEnv returns the provider selected by GT_DOLT_REMOTE
+// (DoltHub when unset).
+func RemoteProviderFromEnv() (RemoteProvider, error) {
+	return ParseRemoteProvider(os.Getenv("GT_DOLT_REMOTE"))
+}
+
+// RemoteOrg returns the org that owns this town's repos on the remote
+// provider: GT_DOLT_REMOTE_ORG, falling back to DOLTHUB_ORG.
+// Returns empty string if neither is set.
+func RemoteOrg() string {
+	if org := os.Getenv("GT_DOLT_REMOTE_ORG"); org != "" {
+		return org
+	}
+	return DoltHubOrg()
+}
+
+// FileRemote is a RemoteProvider that stores each repo as a dolt file remote
+// at <Root>/<org>/<repo>. Root can be a local directory or a shared mount.
+type FileRemote struct {
+	Root string
+}
+
+// Name implements RemoteProvider.
+func (p *FileRemote) Name() string { return RemoteProviderFile }
+
+// URL implements RemoteProvider.
+func (p *FileRemote) URL(org, repo string) string {
+	return fileURL(p.repoDir(org, repo))
+}
+
+// Check implements RemoteProvider. The root must exist or be creatable.
+func (p *FileRemote) Check() error {
+	if !filepath.IsAbs(p.Root) {
+		return fmt.Errorf("file remote root %q is not an absolute path", p.Root)
+	}
+	if err := os.MkdirAll(p.Root, 0755); err != nil {
+		return fmt.Errorf("file remote root: %w", err)
+	}
+	return nil
+}
+
+// CreateRepo implements RemoteProvider. Dolt initializes the remote's storage
+// on the first push to the empty directory.
+func (p *FileRemote) CreateRepo(org, repo string) error {
+	if err := os.MkdirAll(p.repoDir(org, repo), 0755); err != nil {
+		return fmt.Errorf("creating %s/%s: %w", org, repo, err)
+	}
+	return nil
+}
+
+// ForkRepo implements RemoteProvider by copying the upstream's storage. The
+// copy is staged beside the destination and renamed into place, so an
+// interrupted fork never leaves a half-copied repo behind.
+func (p *FileRemote) ForkRepo(fromOrg, repo, toOrg string) error {
+	src := p.repoDir(fromOrg, repo)
+	dst := p.repoDir(toOrg, repo)
+	if src == dst || !isEmptyDir(dst) {
+		return nil // already forked
+	}
+	if isEmptyDir(src) {
+		return fmt.Errorf("upstream %s/%s not found under %s", fromOrg, repo, p.Root)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", toOrg, err)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(dst), "."+repo+"-fork-")
+	if err != nil {
+		return fmt.Errorf("staging fork: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	if err := os.Chmod(staging, 0755); err != nil {
+		return fmt.Errorf("staging fork: %w", err)
+	}
+
+	if err := copyDir(staging, src); err != nil {
+		return fmt.Errorf("copying %s/%s: %w", fromOrg, repo, err)
+	}
+	// An empty destination left by CreateRepo is replaced by the fork.
+	_ = os.Remove(dst)
+	if err := os.Rename(staging, dst); err != nil {
+		return fmt.Errorf("forking %s/%s to %s: %w", fromOrg, repo, toOrg, err)
+	}
+	return nil
+}
+
+func (p *FileRemote) repoDir(org, repo string) string {
+	return filepath.Join(p.Root, org, repo)
+}
+
+// isEmptyDir reports whether dir is missing or has no entries.
+func isEmptyDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err != nil || len(entries) == 0
+}
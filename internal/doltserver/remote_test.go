@@ -0,0 +1,137 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseRemoteProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix paths")
+	}
+	t.Setenv("DOLTHUB_TOKEN", "tok")
+
+	tests := []struct {
+		spec     string
+		wantName string
+		wantRoot string
+	}{
+		{"", RemoteProviderDoltHub, ""},
+		{"dolthub", RemoteProviderDoltHub, ""},
+		{"file:///srv/wasteland", RemoteProviderFile, "/srv/wasteland"},
+		{"/srv/wasteland/", RemoteProviderFile, "/srv/wasteland"},
+	}
+	for _, tt := range tests {
+		p, err := ParseRemoteProvider(tt.spec)
+		if err != nil {
+			t.Errorf("ParseRemoteProvider(%q): %v", tt.spec, err)
+			continue
+		}
+		if p.Name() != tt.wantName {
+			t.Errorf("ParseRemoteProvider(%q).Name() = %q, want %q", tt.spec, p.Name(), tt.wantName)
+		}
+		if fr, ok := p.(*FileRemote); ok && fr.Root != tt.wantRoot {
+			t.Errorf("ParseRemoteProvider(%q).Root = %q, want %q", tt.spec, fr.Root, tt.wantRoot)
+		}
+		if dh, ok := p.(*DoltHubProvider); ok && dh.Token != "tok" {
+			t.Errorf("ParseRemoteProvider(%q) did not pick up DOLTHUB_TOKEN", tt.spec)
+		}
+	}
+
+	for _, bad := range []string{"relative/dir", "file://relative", "https://example.com"} {
+		if _, err := ParseRemoteProvider(bad); err == nil {
+			t.Errorf("ParseRemoteProvider(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRemoteOrg(t *testing.T) {
+	t.Setenv("DOLTHUB_ORG", "hub-org")
+	t.Setenv("GT_DOLT_REMOTE_ORG", "")
+	if got := RemoteOrg(); got != "hub-org" {
+		t.Errorf("RemoteOrg() = %q, want fallback to DOLTHUB_ORG", got)
+	}
+	t.Setenv("GT_DOLT_REMOTE_ORG", "box-org")
+	if got := RemoteOrg(); got != "box-org" {
+		t.Errorf("RemoteOrg() = %q, want GT_DOLT_REMOTE_ORG", got)
+	}
+}
+
+func TestDoltHubProvider_CheckNeedsToken(t *testing.T) {
+	if err := (&DoltHubProvider{}).Check(); err == nil || !strings.Contains(err.Error(), "DOLTHUB_TOKEN") {
+		t.Errorf("Check() without token = %v, want DOLTHUB_TOKEN error", err)
+	}
+	if err := (&DoltHubProvider{Token: "tok"}).Check(); err != nil {
+		t.Errorf("Check() with token = %v", err)
+	}
+}
+
+func TestFileRemote_CreateAndFork(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "remotes")
+	p := &FileRemote{Root: root}
+	if err := p.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if err := p.ForkRepo("ops", "wl-commons", "alice"); err == nil {
+		t.Fatal("forking a missing upstream succeeded")
+	}
+
+	if err := p.CreateRepo("ops", "wl-commons"); err != nil {
+		t.Fatalf("CreateRepo: %v", err)
+	}
+	if err := p.CreateRepo("ops", "wl-commons"); err != nil {
+		t.Fatalf("CreateRepo should be idempotent: %v", err)
+	}
+	upstream := filepath.Join(root, "ops", "wl-commons")
+	if got, want := p.URL("ops", "wl-commons"), fileURL(upstream); got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+
+	// Stand-in for the table files a push leaves in the remote.
+	writeFile := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(upstream, "manifest"), "v1")
+	writeFile(filepath.Join(upstream, "oldgen", "chunks"), "abc")
+
+	// A fork into an org dir created ahead of time still lands.
+	if err := p.CreateRepo("alice", "wl-commons"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ForkRepo("ops", "wl-commons", "alice"); err != nil {
+		t.Fatalf("ForkRepo: %v", err)
+	}
+	fork := filepath.Join(root, "alice", "wl-commons")
+	if data, err := os.ReadFile(filepath.Join(fork, "oldgen", "chunks")); err != nil || string(data) != "abc" {
+		t.Errorf("fork contents = %q, %v", data, err)
+	}
+	if info, err := os.Stat(fork); err != nil {
+		t.Error(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+		t.Errorf("fork dir mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	// Forking again leaves the existing fork alone.
+	writeFile(filepath.Join(upstream, "manifest"), "v2")
+	if err := p.ForkRepo("ops", "wl-commons", "alice"); err != nil {
+		t.Fatalf("second ForkRepo: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(fork, "manifest")); string(data) != "v1" {
+		t.Errorf("existing fork was overwritten: manifest = %q", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(root, "alice"))
+	if len(entries) != 1 {
+		t.Errorf("staging dirs left behind: %v", entries)
+	}
+}
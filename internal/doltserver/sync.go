@@ -117,10 +117,11 @@ func PushDatabase(dbDir string, force bool) error {
 	return nil
 }
 
-// SyncDatabases iterates all databases (or a filtered subset), checks for remotes,
+// SyncDatabases iterates all rig databases (or a filtered subset), checks for remotes,
 // commits working changes, and pushes to origin. Never fails fast — collects all results.
+// The event store is never synced: it is an index rebuilt from the town's logs.
 func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
-	databases, err := ListDatabases(townRoot)
+	databases, err := ListRigDatabases(townRoot)
 	if err != nil {
 		return []SyncResult{{
 			Database: "(list)",
@@ -149,13 +150,14 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 		result.Remote = remote
 
 		if remote == "" {
-			// Auto-setup DoltHub remote if credentials are available.
-			token := DoltHubToken()
-			org := DoltHubOrg()
-			if token != "" && org != "" {
-				if err := SetupDoltHubRemote(dbDir, org, db, token); err != nil {
+			// Auto-setup a remote if the provider and org are configured
+			// (DOLTHUB_TOKEN/DOLTHUB_ORG, or GT_DOLT_REMOTE for a file remote).
+			provider, perr := RemoteProviderFromEnv()
+			org := RemoteOrg()
+			if perr == nil && provider.Check() == nil && org != "" {
+				if err := SetupRemote(dbDir, provider, org, db); err != nil {
 					// Setup failed — skip this database for now.
-					result.Error = fmt.Errorf("auto-setup DoltHub remote: %w", err)
+					result.Error = fmt.Errorf("auto-setup %s remote: %w", provider.Name(), err)
 					results = append(results, result)
 					continue
 				}
@@ -167,6 +169,10 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 					continue
 				}
 				result.Remote = remote
+			} else if perr != nil {
+				result.Error = perr
+				results = append(results, result)
+				continue
 			} else {
 				result.Skipped = true
 				results = append(results, result)
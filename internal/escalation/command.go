@@ -0,0 +1,54 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandNotifier bridges notifications to an external program, typically an
+// SMS gateway CLI. The command runs via "sh -c" with the message text on
+// stdin and the escalation described in GT_SMS_TO and GT_ESCALATION_*
+// environment variables. A non-zero exit status is a delivery failure.
+type CommandNotifier struct {
+	Command string
+	To      string // destination (phone number for SMS)
+	Dir     string // working directory (town root)
+}
+
+// Channel implements Notifier.
+func (c *CommandNotifier) Channel() string { return "sms" }
+
+// Notify implements Notifier.
+func (c *CommandNotifier) Notify(ctx context.Context, n *Notification) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command) //nolint:gosec // G204: command comes from town settings
+	cmd.Dir = c.Dir
+	cmd.Stdin = strings.NewReader(n.Subject())
+	cmd.Env = append(os.Environ(),
+		"GT_SMS_TO="+c.To,
+		"GT_ESCALATION_ID="+n.ID,
+		"GT_ESCALATION_SEVERITY="+n.Severity,
+		"GT_ESCALATION_TITLE="+n.Title,
+	)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if msg := oneLine(out.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, truncate(msg, 200))
+		}
+		return err
+	}
+	return nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,40 @@
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LogNotifier appends notifications as JSON lines to an append-only log file.
+type LogNotifier struct {
+	Path string
+}
+
+// Channel implements Notifier.
+func (l *LogNotifier) Channel() string { return "log" }
+
+// Notify implements Notifier.
+func (l *LogNotifier) Notify(_ context.Context, n *Notification) error {
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("encoding log entry: %w", err)
+	}
+
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: log file is not secret
+	if err != nil {
+		return fmt.Errorf("opening escalation log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing escalation log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Package escalation delivers escalations to external channels.
+//
+// Escalation routes in settings/escalation.json name actions such as
+// "email:human", "sms:human", "slack", "webhook:<name>" and "log". The
+// Dispatcher resolves each action to a Notifier using the configured contacts
+// and delivery settings, sends the notification, and reports a Result per
+// action so callers can record on the escalation bead whether a human was
+// actually paged.
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Notification is the payload delivered to external channels.
+type Notification struct {
+	ID          string    `json:"id"`
+	Severity    string    `json:"severity"`
+	Title       string    `json:"title"`
+	Reason      string    `json:"reason,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	EscalatedBy string    `json:"escalated_by,omitempty"`
+	RelatedBead string    `json:"related_bead,omitempty"`
+	Reescalated bool      `json:"reescalated,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Subject returns a one-line summary suitable for email subjects and SMS.
+func (n *Notification) Subject() string {
+	prefix := strings.ToUpper(n.Severity)
+	if n.Reescalated {
+		prefix += " re-escalated"
+	}
+	return fmt.Sprintf("[%s] %s (%s)", prefix, n.Title, n.ID)
+}
+
+// Text returns a plain-text rendering of the notification.
+func (n *Notification) Text() string {
+	var lines []string
+	lines = append(lines, n.Subject())
+	if n.Reason != "" {
+		lines = append(lines, "", n.Reason)
+	}
+	lines = append(lines, "")
+	if n.EscalatedBy != "" {
+		lines = append(lines, "From: "+n.EscalatedBy)
+	}
+	if n.Source != "" {
+		lines = append(lines, "Source: "+n.Source)
+	}
+	if n.RelatedBead != "" {
+		lines = append(lines, "Related: "+n.RelatedBead)
+	}
+	lines = append(lines, "To acknowledge: gt escalate ack "+n.ID)
+	return strings.Join(lines, "\n")
+}
+
+// Notifier delivers a notification to a single external channel.
+type Notifier interface {
+	// Channel returns a short channel name ("email", "sms", "slack", ...).
+	Channel() string
+	// Notify sends the notification. Implementations must honour ctx.
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// Result records the outcome of a single route action.
+type Result struct {
+	Action  string `json:"action"`
+	Channel string `json:"channel"`
+	Target  string `json:"target,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OK reports whether the action was delivered.
+func (r Result) OK() bool {
+	return !r.Skipped && r.Error == ""
+}
+
+// Status returns a short human-readable status ("sent", "skipped: ...", "failed: ...").
+func (r Result) Status() string {
+	switch {
+	case r.Skipped:
+		return "skipped: " + r.Error
+	case r.Error != "":
+		return "failed: " + r.Error
+	default:
+		return "sent"
+	}
+}
+
+// Dispatcher resolves route actions to notifiers and delivers notifications.
+type Dispatcher struct {
+	townRoot string
+	cfg      *config.EscalationConfig
+	timeout  time.Duration
+}
+
+// NewDispatcher creates a dispatcher for the given town and escalation config.
+func NewDispatcher(townRoot string, cfg *config.EscalationConfig) *Dispatcher {
+	if cfg == nil {
+		cfg = config.NewEscalationConfig()
+	}
+	return &Dispatcher{
+		townRoot: townRoot,
+		cfg:      cfg,
+		timeout:  cfg.GetDeliveryTimeout(),
+	}
+}
+
+// IsExternalAction reports whether an action is delivered by the Dispatcher
+// (as opposed to "bead" and "mail:" actions handled by gt itself).
+func IsExternalAction(action string) bool {
+	switch {
+	case strings.HasPrefix(action, "email:"),
+		strings.HasPrefix(action, "sms:"),
+		strings.HasPrefix(action, "webhook:"),
+		action == "slack",
+		action == "log":
+		return true
+	default:
+		return false
+	}
+}
+
+// Dispatch delivers the notification for every external action in actions.
+// Non-external actions are ignored. One Result is returned per external
+// action, in order. Dispatch never returns early: a failing channel must not
+// prevent the remaining channels from being tried.
+func (d *Dispatcher) Dispatch(ctx context.Context, actions []string, n *Notification) []Result {
+	if n.Time.IsZero() {
+		n.Time = time.Now().UTC()
+	}
+
+	var results []Result
+	for _, action := range actions {
+		if !IsExternalAction(action) {
+			continue
+		}
+
+		notifier, target, err := d.notifierFor(action)
+		result := Result{Action: action, Target: target}
+		if err != nil {
+			result.Skipped = true
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Channel = notifier.Channel()
+
+		actx, cancel := context.WithTimeout(ctx, d.timeout)
+		if err := notifier.Notify(actx, n); err != nil {
+			result.Error = err.Error()
+		}
+		cancel()
+		results = append(results, result)
+	}
+	return results
+}
+
+// notifierFor builds the notifier for an action from config.
+// Returns an error (recorded as skipped) when the action isn't configured.
+func (d *Dispatcher) notifierFor(action string) (Notifier, string, error) {
+	contacts := d.cfg.Contacts
+	delivery := d.cfg.Delivery
+
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		if contacts.HumanEmail == "" {
+			return nil, "", fmt.Errorf("contacts.human_email not configured")
+		}
+		if delivery.SMTP == nil {
+			return nil, contacts.HumanEmail, fmt.Errorf("delivery.smtp not configured")
+		}
+		smtp := delivery.SMTP
+		port := smtp.Port
+		if port == 0 {
+			port = 587
+		}
+		password := ""
+		if smtp.PasswordEnv != "" {
+			password = os.Getenv(smtp.PasswordEnv)
+		}
+		return &SMTPNotifier{
+			Addr:     fmt.Sprintf("%s:%d", smtp.Host, port),
+			Username: smtp.Username,
+			Password: password,
+			From:     smtp.From,
+			To:       []string{contacts.HumanEmail},
+		}, contacts.HumanEmail, nil
+
+	case strings.HasPrefix(action, "sms:"):
+		if contacts.HumanSMS == "" {
+			return nil, "", fmt.Errorf("contacts.human_sms not configured")
+		}
+		if delivery.SMSCommand == "" {
+			return nil, contacts.HumanSMS, fmt.Errorf("delivery.sms_command not configured")
+		}
+		return &CommandNotifier{
+			Command: delivery.SMSCommand,
+			To:      contacts.HumanSMS,
+			Dir:     d.townRoot,
+		}, contacts.HumanSMS, nil
+
+	case action == "slack":
+		if contacts.SlackWebhook == "" {
+			return nil, "", fmt.Errorf("contacts.slack_webhook not configured")
+		}
+		return &WebhookNotifier{URL: contacts.SlackWebhook, Name: "slack"}, "slack", nil
+
+	case strings.HasPrefix(action, "webhook:"):
+		name := strings.TrimPrefix(action, "webhook:")
+		url := contacts.Webhooks[name]
+		if url == "" {
+			return nil, name, fmt.Errorf("contacts.webhooks[%q] not configured", name)
+		}
+		return &WebhookNotifier{URL: url, Name: "webhook"}, name, nil
+
+	case action == "log":
+		path := d.cfg.GetLogPath(d.townRoot)
+		return &LogNotifier{Path: path}, path, nil
+	}
+
+	return nil, "", fmt.Errorf("unknown action %q", action)
+}
+
+// FormatResults renders results as a single line for the escalation bead,
+// e.g. "email:human=sent; sms:human=failed: exit status 1".
+func FormatResults(results []Result) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, r.Action+"="+r.Status())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// AnyFailed reports whether any configured channel failed to deliver.
+// Skipped (unconfigured) actions do not count as failures.
+func AnyFailed(results []Result) bool {
+	for _, r := range results {
+		if !r.Skipped && r.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// oneLine collapses whitespace so errors and command output fit on one line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,275 @@
+package escalation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		ID:          "hq-esc1",
+		Severity:    "critical",
+		Title:       "Build failing",
+		Reason:      "CI blocked",
+		EscalatedBy: "gastown/Toast",
+	}
+}
+
+// fakeSMTP is a minimal SMTP stand-in that records one message.
+type fakeSMTP struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	from string
+	rcpt []string
+	data string
+	done chan struct{}
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer close(s.done)
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var body strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				body.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = body.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	srv := newFakeSMTP(t)
+	host, port, _ := net.SplitHostPort(srv.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanEmail = "oncall@example.com"
+	cfg.Delivery.SMTP = &config.EscalationSMTP{Host: host, Port: portNum, From: "gt@example.com"}
+
+	results := NewDispatcher(t.TempDir(), cfg).Dispatch(context.Background(), []string{"bead", "email:human"}, testNotification())
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if !results[0].OK() {
+		t.Fatalf("email delivery failed: %s", results[0].Status())
+	}
+
+	<-srv.done
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.from != "gt@example.com" {
+		t.Errorf("MAIL FROM = %q", srv.from)
+	}
+	if len(srv.rcpt) != 1 || srv.rcpt[0] != "oncall@example.com" {
+		t.Errorf("RCPT TO = %v", srv.rcpt)
+	}
+	if !strings.Contains(srv.data, "Subject: [CRITICAL] Build failing (hq-esc1)") {
+		t.Errorf("message missing subject:\n%s", srv.data)
+	}
+	if !strings.Contains(srv.data, "CI blocked") {
+		t.Errorf("message missing reason:\n%s", srv.data)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+
+	results := NewDispatcher(t.TempDir(), cfg).Dispatch(context.Background(), []string{"slack"}, testNotification())
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("slack delivery failed: %+v", results)
+	}
+	if !strings.HasPrefix(got.Text, "[CRITICAL] Build failing") {
+		t.Errorf("text = %q", got.Text)
+	}
+	if got.Escalation == nil || got.Escalation.ID != "hq-esc1" {
+		t.Errorf("escalation = %+v", got.Escalation)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.Webhooks = map[string]string{"pager": srv.URL}
+
+	results := NewDispatcher(t.TempDir(), cfg).Dispatch(context.Background(), []string{"webhook:pager"}, testNotification())
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].OK() || results[0].Skipped {
+		t.Fatalf("expected failure, got %s", results[0].Status())
+	}
+	if !strings.Contains(results[0].Error, "403") || !strings.Contains(results[0].Error, "invalid_token") {
+		t.Errorf("error = %q", results[0].Error)
+	}
+	if !AnyFailed(results) {
+		t.Error("AnyFailed = false, want true")
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sms.txt")
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanSMS = "+15551234567"
+	cfg.Delivery.SMSCommand = `{ echo "$GT_SMS_TO"; cat; } > ` + out
+
+	results := NewDispatcher(dir, cfg).Dispatch(context.Background(), []string{"sms:human"}, testNotification())
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("sms delivery failed: %+v", results)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading bridge output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "+15551234567\n[CRITICAL] Build failing") {
+		t.Errorf("bridge output = %q", data)
+	}
+
+	cfg.Delivery.SMSCommand = "echo gateway down >&2; exit 3"
+	results = NewDispatcher(dir, cfg).Dispatch(context.Background(), []string{"sms:human"}, testNotification())
+	if results[0].OK() || !strings.Contains(results[0].Error, "gateway down") {
+		t.Errorf("expected failure with bridge output, got %s", results[0].Status())
+	}
+}
+
+func TestLogNotifierAppends(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.NewEscalationConfig()
+	d := NewDispatcher(townRoot, cfg)
+
+	for i := 0; i < 2; i++ {
+		results := d.Dispatch(context.Background(), []string{"log"}, testNotification())
+		if len(results) != 1 || !results[0].OK() {
+			t.Fatalf("log delivery failed: %+v", results)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, "logs", "escalations.log"))
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2", len(lines))
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(lines[0]), &n); err != nil {
+		t.Fatalf("parsing log line: %v", err)
+	}
+	if n.ID != "hq-esc1" || n.Time.IsZero() {
+		t.Errorf("log entry = %+v", n)
+	}
+}
+
+func TestDispatchUnconfiguredActionsSkipped(t *testing.T) {
+	cfg := &config.EscalationConfig{}
+	cfg.Contacts.HumanEmail = "oncall@example.com" // email set, but no SMTP server
+
+	actions := []string{"bead", "mail:mayor", "email:human", "sms:human", "slack", "webhook:nope"}
+	results := NewDispatcher(t.TempDir(), cfg).Dispatch(context.Background(), actions, testNotification())
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4: %+v", len(results), results)
+	}
+	for _, r := range results {
+		if !r.Skipped {
+			t.Errorf("%s: expected skipped, got %s", r.Action, r.Status())
+		}
+	}
+	if AnyFailed(results) {
+		t.Error("skipped actions should not count as failures")
+	}
+
+	got := FormatResults(results[:1])
+	if got != "email:human=skipped: delivery.smtp not configured" {
+		t.Errorf("FormatResults = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookNotifier POSTs notifications as JSON.
+//
+// The payload carries a top-level "text" field so it is accepted by Slack
+// incoming webhooks (and Slack-compatible receivers such as Mattermost or
+// Discord's /slack endpoint), plus an "escalation" object with the
+// structured fields for generic receivers.
+type WebhookNotifier struct {
+	URL    string
+	Name   string       // channel name reported in results ("slack", "webhook")
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+type webhookPayload struct {
+	Text       string        `json:"text"`
+	Escalation *Notification `json:"escalation"`
+}
+
+// Channel implements Notifier.
+func (w *WebhookNotifier) Channel() string {
+	if w.Name == "" {
+		return "webhook"
+	}
+	return w.Name
+}
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(webhookPayload{Text: n.Text(), Escalation: n})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-escalation")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, oneLine(string(msg)))
+	}
+	return nil
+}
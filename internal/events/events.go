@@ -54,6 +54,9 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// TypeSessionResume is a crashed polecat session restarted from its checkpoint.
+	TypeSessionResume = "session_resume"
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -68,6 +71,7 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+	TypeGateResult   = "gate_result" // One quality gate run (audit only)
 )
 
 // EventsFile is the name of the raw events log.
@@ -215,6 +219,21 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// GatePayload creates a payload for quality gate results.
+// result is "pass" or "fail"; duration is recorded in milliseconds.
+func GatePayload(rig, gate string, passed bool, duration time.Duration) map[string]interface{} {
+	result := "fail"
+	if passed {
+		result = "pass"
+	}
+	return map[string]interface{}{
+		"rig":         rig,
+		"gate":        gate,
+		"result":      result,
+		"duration_ms": duration.Milliseconds(),
+	}
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -296,6 +315,19 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// SessionResumePayload creates a payload for an automatic resume of a
+// crashed polecat session. attempt counts resumes of the bead, up to max.
+func SessionResumePayload(session, agent, bead, caller string, attempt, max int) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"agent":   agent,
+		"bead":    bead,
+		"caller":  caller,
+		"attempt": attempt,
+		"max":     max,
+	}
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
@@ -0,0 +1,359 @@
+package eventstore
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/runlog"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// syncBatch is the number of journal events appended between saved offsets.
+const syncBatch = 1000
+
+// maxLine bounds a single journal line; longer lines are skipped.
+const maxLine = 4 * 1024 * 1024
+
+// journal is one append-only log the store ingests.
+type journal struct {
+	source string
+	path   string
+	parse  func(line string) (Event, bool)
+}
+
+func journals(townRoot string) []journal {
+	return []journal{
+		{SourceEvents, filepath.Join(townRoot, events.EventsFile), parseEventsLine},
+		{SourceTownlog, townlog.LogPath(townRoot), parseTownlogLine},
+		{SourceRunlog, runlog.NewStore(townRoot).Path(), parseRunlogLine},
+	}
+}
+
+// Sync appends the events the town's journals gained since the last Sync and
+// returns how many journal lines it read. A journal that was rewritten (its
+// first line changed, or it shrank) is read again from the start.
+func (s *Store) Sync(townRoot string) (int, error) {
+	total := 0
+	for _, j := range journals(townRoot) {
+		n, err := s.syncJournal(j)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("syncing %s: %w", j.source, err)
+		}
+	}
+	return total, nil
+}
+
+func (s *Store) syncJournal(j journal) (int, error) {
+	f, err := os.Open(j.path) //nolint:gosec // G304: journal paths are under the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	head, err := journalHead(f)
+	if err != nil {
+		return 0, err
+	}
+
+	key := j.key()
+	pos, savedHead, err := s.offset(key)
+	if err != nil {
+		return 0, err
+	}
+	if head != savedHead || info.Size() < pos {
+		pos = 0
+	}
+	if pos == info.Size() {
+		return 0, nil
+	}
+	if _, err := f.Seek(pos, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	read := 0
+	var batch []Event
+	flush := func() error {
+		if err := s.Append(batch...); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return s.saveOffset(key, j.path, pos, head)
+	}
+
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			// A line without its newline is still being written; leave it
+			// for the next Sync.
+			if err == io.EOF {
+				break
+			}
+			return read, err
+		}
+		pos += int64(len(line))
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" || len(line) > maxLine {
+			continue
+		}
+		read++
+		if e, ok := j.parse(line); ok {
+			e.Source = j.source
+			e.ID = eventID(j.source, line)
+			batch = append(batch, e)
+		}
+		if len(batch) >= syncBatch {
+			if err := flush(); err != nil {
+				return read, err
+			}
+		}
+	}
+	return read, flush()
+}
+
+// journalHead fingerprints a journal by its first line, to notice when it is
+// replaced rather than appended to.
+func journalHead(f *os.File) (string, error) {
+	buf := make([]byte, 4096)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	buf = buf[:n]
+	if i := strings.IndexByte(string(buf), '\n'); i >= 0 {
+		buf = buf[:i]
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// key identifies the journal's read offset. It includes the path so towns
+// sharing a Dolt server keep separate offsets.
+func (j journal) key() string {
+	sum := sha256.Sum256([]byte(j.path))
+	return j.source + ":" + hex.EncodeToString(sum[:8])
+}
+
+func (s *Store) offset(key string) (int64, string, error) {
+	var pos int64
+	var head string
+	err := s.db.QueryRow(fmt.Sprintf("SELECT pos, head FROM %s.sources WHERE name = ?", Database), key).Scan(&pos, &head)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("reading offset: %w", err)
+	}
+	return pos, head, nil
+}
+
+func (s *Store) saveOffset(key, path string, pos int64, head string) error {
+	_, err := s.db.Exec(fmt.Sprintf(
+		"INSERT INTO %s.sources (name, path, pos, head, synced_at) VALUES (?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE pos = VALUES(pos), head = VALUES(head), synced_at = VALUES(synced_at)", Database),
+		key, path, pos, head, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("saving offset: %w", err)
+	}
+	return nil
+}
+
+// Scan answers q by reading the town's journals directly. It is the
+// fallback when the Dolt server is down, and as slow as that sounds on a
+// busy town.
+func Scan(townRoot string, q Query) ([]Event, error) {
+	var out []Event
+	for _, j := range journals(townRoot) {
+		if len(q.Sources) > 0 && !matchAny(q.Sources, j.source) {
+			continue
+		}
+		f, err := os.Open(j.path) //nolint:gosec // G304: journal paths are under the town root
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			e, ok := j.parse(line)
+			if !ok {
+				continue
+			}
+			e.Source = j.source
+			e.ID = eventID(j.source, line)
+			if q.Match(e) {
+				out = append(out, e)
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", j.path, err)
+		}
+	}
+
+	sort.SliceStable(out, func(i, k int) bool {
+		return out[i].Time.After(out[k].Time)
+	})
+	if len(out) > q.limit() {
+		out = out[:q.limit()]
+	}
+	return out, nil
+}
+
+// parseEventsLine parses a line of .events.jsonl.
+func parseEventsLine(line string) (Event, bool) {
+	var ev events.Event
+	if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Type == "" {
+		return Event{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+	if err != nil {
+		return Event{}, false
+	}
+	rig, _ := ev.Payload["rig"].(string)
+	if rig == "" {
+		rig = actorRig(ev.Actor)
+	}
+	return Event{
+		Time:       ts,
+		Type:       ev.Type,
+		Actor:      ev.Actor,
+		Bead:       payloadBead(ev.Payload),
+		Rig:        rig,
+		Visibility: ev.Visibility,
+		Summary:    summarizePayload(ev.Payload),
+		Payload:    ev.Payload,
+	}, true
+}
+
+// parseTownlogLine parses a line of logs/town.log.
+func parseTownlogLine(line string) (Event, bool) {
+	parsed, _ := townlog.ParseLogLines(line)
+	if len(parsed) != 1 {
+		return Event{}, false
+	}
+	tl := parsed[0]
+
+	// The detail follows "<timestamp> [<type>] <agent> ".
+	detail := ""
+	if i := strings.Index(line, "] "+tl.Agent); i >= 0 {
+		detail = strings.TrimSpace(line[i+2+len(tl.Agent):])
+	}
+	return Event{
+		Time:    tl.Timestamp,
+		Type:    string(tl.Type),
+		Actor:   tl.Agent,
+		Bead:    beadIDPattern.FindString(detail),
+		Rig:     actorRig(tl.Agent),
+		Summary: detail,
+	}, true
+}
+
+// beadIDPattern finds a bead ID such as gt-abc12 or gt-abc12.3 in free text.
+var beadIDPattern = regexp.MustCompile(`\b[a-z]{1,8}-[a-z0-9]{3,}(?:\.[0-9]+)*\b`)
+
+// parseRunlogLine parses a line of daemon/command-events.jsonl.
+func parseRunlogLine(line string) (Event, bool) {
+	var ev runlog.Event
+	if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.EventType == "" {
+		return Event{}, false
+	}
+	payload := runlog.RedactPayload(ev.Payload)
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payload["run_id"] = ev.RunID
+	for k, v := range map[string]string{"state": ev.State, "policy_decision": ev.PolicyDecision, "tenant_id": ev.TenantID} {
+		if v != "" {
+			payload[k] = v
+		}
+	}
+	if ev.Attempt > 0 {
+		payload["attempt"] = ev.Attempt
+	}
+	return Event{
+		Time:    ev.Timestamp,
+		Type:    ev.EventType,
+		Actor:   ev.AgentID,
+		Bead:    payloadBead(ev.Payload),
+		Rig:     actorRig(ev.AgentID),
+		Summary: summarizePayload(payload),
+		Payload: payload,
+	}, true
+}
+
+// payloadBead returns the bead an event payload refers to, if any.
+func payloadBead(payload map[string]interface{}) string {
+	for _, key := range []string{"bead", "issue"} {
+		if v, ok := payload[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// actorRig returns the rig of an actor address like "gastown/polecats/Toast".
+// Town-level agents have none.
+func actorRig(actor string) string {
+	rig, _, ok := strings.Cut(actor, "/")
+	if !ok || rig == "mayor" || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
+// summarizePayload renders a payload as sorted key=value pairs, for display
+// and text search.
+func summarizePayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		var v string
+		switch val := payload[k].(type) {
+		case string:
+			v = val
+		case nil:
+			continue
+		default:
+			data, _ := json.Marshal(val)
+			v = string(data)
+		}
+		if len(v) > 80 {
+			v = v[:77] + "..."
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
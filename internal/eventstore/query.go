@@ -0,0 +1,268 @@
+package eventstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query selects events from the store. Empty fields match everything; a
+// field with several values matches any of them.
+type Query struct {
+	// Actors match an exact address ("gastown/polecats/Toast"), a bare name
+	// in any rig or role ("Toast", case-insensitive), or an address prefix
+	// ending in "/" or "/*" ("gastown/polecats/*").
+	Actors []string
+
+	Types      []string
+	Beads      []string
+	Rigs       []string
+	Sources    []string
+	Visibility []string
+
+	// Since and Until bound the event time: Since <= t < Until.
+	Since time.Time
+	Until time.Time
+
+	// Text matches a case-insensitive substring of the summary.
+	Text string
+
+	// Limit caps the number of events returned (the newest win).
+	// Zero means DefaultLimit.
+	Limit int
+}
+
+func (q Query) limit() int {
+	if q.Limit > 0 {
+		return q.Limit
+	}
+	return DefaultLimit
+}
+
+// ParseQuery parses the event query language: whitespace-separated terms of
+// the form key:value, where a value may list alternatives separated by
+// commas. Words without a key are matched against the event summary.
+//
+//	actor:Toast on:yesterday
+//	type:hook,unhook rig:gastown since:2h
+//	bead:gt-abc source:townlog limit:20
+//
+// Keys are actor, type, bead, rig, source, vis, since, until, on and limit.
+// since and until take a duration ago (90m, 24h, 7d), a date (2006-01-02),
+// a local time (2006-01-02T15:04), an RFC 3339 timestamp, now, today or
+// yesterday. on takes a day and sets both bounds to it.
+func ParseQuery(s string, now time.Time) (Query, error) {
+	var q Query
+	var text []string
+	for _, term := range strings.Fields(s) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" || !isQueryKey(key) {
+			text = append(text, term)
+			continue
+		}
+		values := splitValues(value)
+		switch key {
+		case "actor":
+			q.Actors = append(q.Actors, values...)
+		case "type":
+			q.Types = append(q.Types, values...)
+		case "bead":
+			q.Beads = append(q.Beads, values...)
+		case "rig":
+			q.Rigs = append(q.Rigs, values...)
+		case "source":
+			q.Sources = append(q.Sources, values...)
+		case "vis":
+			q.Visibility = append(q.Visibility, values...)
+		case "since":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return Query{}, fmt.Errorf("since: %w", err)
+			}
+			q.Since = t
+		case "until":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return Query{}, fmt.Errorf("until: %w", err)
+			}
+			q.Until = t
+		case "on":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return Query{}, fmt.Errorf("on: %w", err)
+			}
+			q.Since = startOfDay(t)
+			q.Until = q.Since.AddDate(0, 0, 1)
+		case "limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return Query{}, fmt.Errorf("limit: %q is not a positive number", value)
+			}
+			q.Limit = n
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+func isQueryKey(key string) bool {
+	switch key {
+	case "actor", "type", "bead", "rig", "source", "vis", "since", "until", "on", "limit":
+		return true
+	}
+	return false
+}
+
+func splitValues(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseQueryTime parses a since/until/on value relative to now.
+func parseQueryTime(s string, now time.Time) (time.Time, error) {
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("can't parse time %q", s)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// where returns the SQL condition and arguments for q.
+func (q Query) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if len(q.Actors) > 0 {
+		var ors []string
+		for _, a := range q.Actors {
+			switch {
+			case strings.HasSuffix(a, "/*") || strings.HasSuffix(a, "/"):
+				ors = append(ors, "actor LIKE ?")
+				args = append(args, escapeLike(strings.TrimSuffix(a, "*"))+"%")
+			case strings.Contains(a, "/"):
+				ors = append(ors, "actor = ?")
+				args = append(args, a)
+			default:
+				ors = append(ors, "actor_name = ?")
+				args = append(args, strings.ToLower(a))
+			}
+		}
+		conds = append(conds, "("+strings.Join(ors, " OR ")+")")
+	}
+	for _, f := range []struct {
+		column string
+		values []string
+	}{
+		{"type", q.Types},
+		{"bead", q.Beads},
+		{"rig", q.Rigs},
+		{"source", q.Sources},
+		{"visibility", q.Visibility},
+	} {
+		if len(f.values) == 0 {
+			continue
+		}
+		conds = append(conds, f.column+" IN (?"+strings.Repeat(", ?", len(f.values)-1)+")")
+		for _, v := range f.values {
+			args = append(args, v)
+		}
+	}
+	if !q.Since.IsZero() {
+		conds = append(conds, "ts >= ?")
+		args = append(args, q.Since.UTC())
+	}
+	if !q.Until.IsZero() {
+		conds = append(conds, "ts < ?")
+		args = append(args, q.Until.UTC())
+	}
+	if q.Text != "" {
+		conds = append(conds, "LOWER(summary) LIKE ?")
+		args = append(args, "%"+escapeLike(strings.ToLower(q.Text))+"%")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Match reports whether e satisfies q. It applies the same rules as the SQL
+// query, for reading the journals directly when the store is unavailable.
+func (q Query) Match(e Event) bool {
+	if len(q.Actors) > 0 {
+		matched := false
+		for _, a := range q.Actors {
+			switch {
+			case strings.HasSuffix(a, "/*") || strings.HasSuffix(a, "/"):
+				matched = strings.HasPrefix(e.Actor, strings.TrimSuffix(a, "*"))
+			case strings.Contains(a, "/"):
+				matched = e.Actor == a
+			default:
+				matched = e.ActorName() == strings.ToLower(a)
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !matchAny(q.Types, e.Type) || !matchAny(q.Beads, e.Bead) || !matchAny(q.Rigs, e.Rig) ||
+		!matchAny(q.Sources, e.Source) || !matchAny(q.Visibility, e.Visibility) {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	if q.Text != "" && !strings.Contains(strings.ToLower(e.Summary), strings.ToLower(q.Text)) {
+		return false
+	}
+	return true
+}
+
+func matchAny(values []string, v string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, want := range values {
+		if want == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package eventstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+
+	q, err := ParseQuery("actor:Toast,gastown/crew/* type:hook,unhook bead:gt-abc since:2h limit:20 merge conflict", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Actors) != 2 || q.Actors[0] != "Toast" || q.Actors[1] != "gastown/crew/*" {
+		t.Errorf("Actors = %v", q.Actors)
+	}
+	if len(q.Types) != 2 || q.Types[1] != "unhook" {
+		t.Errorf("Types = %v", q.Types)
+	}
+	if len(q.Beads) != 1 || q.Beads[0] != "gt-abc" {
+		t.Errorf("Beads = %v", q.Beads)
+	}
+	if !q.Since.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("Since = %v", q.Since)
+	}
+	if q.Limit != 20 {
+		t.Errorf("Limit = %d", q.Limit)
+	}
+	if q.Text != "merge conflict" {
+		t.Errorf("Text = %q", q.Text)
+	}
+
+	q, err = ParseQuery("on:yesterday", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSince := time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)
+	if !q.Since.Equal(wantSince) || !q.Until.Equal(wantSince.AddDate(0, 0, 1)) {
+		t.Errorf("on:yesterday = [%v, %v)", q.Since, q.Until)
+	}
+
+	q, err = ParseQuery("since:7d until:2026-03-09", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Since.Equal(now.AddDate(0, 0, -7)) || !q.Until.Equal(wantSince) {
+		t.Errorf("since/until = [%v, %v)", q.Since, q.Until)
+	}
+
+	// Unknown keys are plain text, so URLs and "a:b" words still search.
+	q, err = ParseQuery("https://example.com", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Text != "https://example.com" {
+		t.Errorf("Text = %q", q.Text)
+	}
+
+	for _, bad := range []string{"since:whenever", "limit:0", "on:soon"} {
+		if _, err := ParseQuery(bad, now); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	ts := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	e := Event{
+		Time:    ts,
+		Source:  SourceEvents,
+		Type:    "hook",
+		Actor:   "gastown/polecats/Toast",
+		Bead:    "gt-abc",
+		Rig:     "gastown",
+		Summary: "bead=gt-abc rig=gastown",
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want bool
+	}{
+		{"empty", Query{}, true},
+		{"bare name", Query{Actors: []string{"toast"}}, true},
+		{"address", Query{Actors: []string{"gastown/polecats/Toast"}}, true},
+		{"prefix", Query{Actors: []string{"gastown/polecats/*"}}, true},
+		{"other actor", Query{Actors: []string{"Nux"}}, false},
+		{"partial name", Query{Actors: []string{"toa"}}, false},
+		{"type", Query{Types: []string{"sling", "hook"}}, true},
+		{"wrong type", Query{Types: []string{"sling"}}, false},
+		{"bead", Query{Beads: []string{"gt-abc"}}, true},
+		{"rig", Query{Rigs: []string{"beads"}}, false},
+		{"source", Query{Sources: []string{SourceTownlog}}, false},
+		{"since", Query{Since: ts}, true},
+		{"until is exclusive", Query{Until: ts}, false},
+		{"text", Query{Text: "GT-ABC"}, true},
+		{"missing text", Query{Text: "merge"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Package eventstore keeps an indexed, queryable copy of the town's event
+// logs in the Dolt server.
+//
+// Gas Town writes events to several append-only journals: the activity log
+// (.events.jsonl, via the events package), the town log (logs/town.log, via
+// townlog) and the command run log (daemon/command-events.jsonl, via runlog).
+// Those journals stay the write path because they must work when the Dolt
+// server is down. The store tails them into one `events` table in the
+// gt_events database, indexed by time, actor, type and bead, so questions like
+// "what did Toast do yesterday" are an index lookup instead of a full rescan
+// of every file.
+//
+// Sync ingests whatever the journals gained since the last call; the daemon
+// runs it every heartbeat and queries run it first. Rows are keyed by a hash
+// of their journal line, so re-reading a journal (after a crash, or after
+// krc rewrites it) never duplicates events. Pruning a journal does not prune
+// the store: it is the town's long-term event history.
+package eventstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL protocol driver for the Dolt server
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// Database is the name of the Dolt database that holds the event store.
+const Database = doltserver.EventsDB
+
+// Event sources, one per journal.
+const (
+	SourceEvents  = "events"  // .events.jsonl activity log
+	SourceTownlog = "townlog" // logs/town.log lifecycle log
+	SourceRunlog  = "runlog"  // daemon/command-events.jsonl command runs
+)
+
+// DefaultLimit is the number of events a query returns when it sets no limit.
+const DefaultLimit = 100
+
+// appendBatch is the number of rows sent per INSERT.
+const appendBatch = 200
+
+// ErrUnavailable is returned when the Dolt server can't be reached.
+var ErrUnavailable = errors.New("event store unavailable")
+
+// Event is one row of the event store.
+type Event struct {
+	// ID identifies the event; events with the same ID are stored once.
+	ID string `json:"id"`
+
+	Time       time.Time              `json:"time"`
+	Source     string                 `json:"source"`
+	Type       string                 `json:"type"`
+	Actor      string                 `json:"actor,omitempty"`
+	Bead       string                 `json:"bead,omitempty"`
+	Rig        string                 `json:"rig,omitempty"`
+	Visibility string                 `json:"visibility,omitempty"`
+	Summary    string                 `json:"summary,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+}
+
+// ActorName returns the last component of the event's actor address, in
+// lower case ("Toast" for "gastown/polecats/Toast").
+func (e *Event) ActorName() string {
+	return actorName(e.Actor)
+}
+
+func actorName(actor string) string {
+	if i := strings.LastIndex(actor, "/"); i >= 0 {
+		actor = actor[i+1:]
+	}
+	return strings.ToLower(actor)
+}
+
+// eventID derives a stable event ID from the journal line it came from.
+func eventID(source, line string) string {
+	sum := sha256.Sum256([]byte(source + "\n" + line))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Store is a connection to the event store.
+type Store struct {
+	db *sql.DB
+}
+
+const schema = `CREATE TABLE IF NOT EXISTS %[1]s.events (
+    id VARCHAR(32) PRIMARY KEY,
+    ts DATETIME(6) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    actor_name VARCHAR(128) NOT NULL DEFAULT '',
+    bead VARCHAR(64) NOT NULL DEFAULT '',
+    rig VARCHAR(64) NOT NULL DEFAULT '',
+    visibility VARCHAR(8) NOT NULL DEFAULT '',
+    summary TEXT,
+    payload JSON,
+    INDEX idx_ts (ts),
+    INDEX idx_actor_ts (actor, ts),
+    INDEX idx_actor_name_ts (actor_name, ts),
+    INDEX idx_type_ts (type, ts),
+    INDEX idx_bead_ts (bead, ts)
+)`
+
+const sourcesSchema = `CREATE TABLE IF NOT EXISTS %[1]s.sources (
+    name VARCHAR(64) PRIMARY KEY,
+    path TEXT,
+    pos BIGINT NOT NULL,
+    head VARCHAR(64) NOT NULL DEFAULT '',
+    synced_at DATETIME(6)
+)`
+
+// schemaReady records the servers whose schema this process has already
+// ensured, so repeated Opens don't re-run DDL.
+var (
+	schemaMu    sync.Mutex
+	schemaReady = map[string]bool{}
+)
+
+// Open connects to the event store on the Dolt server described by config,
+// creating the database and tables if needed. Returns an error wrapping
+// ErrUnavailable if the server can't be reached.
+func Open(config *doltserver.Config) (*Store, error) {
+	db, err := sql.Open("mysql", config.DSN("", "parseTime=true&loc=UTC&timeout=2s"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	db.SetMaxOpenConns(4)
+	db.SetConnMaxIdleTime(30 * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	s := &Store{db: db}
+	if err := s.ensureSchema(ctx, config.HostPort()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenTown connects to the event store on the town's Dolt server. A town
+// without a local data directory or remote server has no store.
+func OpenTown(townRoot string) (*Store, error) {
+	config := doltserver.DefaultConfig(townRoot)
+	if !config.IsRemote() {
+		if _, err := os.Stat(config.DataDir); err != nil {
+			return nil, fmt.Errorf("%w: no Dolt data directory", ErrUnavailable)
+		}
+	}
+	return Open(config)
+}
+
+// QueryTown answers q for the town. When the event store is reachable it is
+// synced and queried; otherwise the journals are scanned. indexed reports
+// whether the store answered.
+func QueryTown(townRoot string, q Query) (events []Event, indexed bool, err error) {
+	s, err := OpenTown(townRoot)
+	if err == nil {
+		defer s.Close()
+		if _, err = s.Sync(townRoot); err == nil {
+			if events, err = s.Query(q); err == nil {
+				return events, true, nil
+			}
+		}
+	}
+	events, err = Scan(townRoot, q)
+	return events, false, err
+}
+
+func (s *Store) ensureSchema(ctx context.Context, server string) error {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	if schemaReady[server] {
+		return nil
+	}
+	stmts := []string{
+		"CREATE DATABASE IF NOT EXISTS " + Database,
+		fmt.Sprintf(schema, Database),
+		fmt.Sprintf(sourcesSchema, Database),
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("creating event store schema: %w", err)
+		}
+	}
+	schemaReady[server] = true
+	return nil
+}
+
+// Close closes the connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append adds events to the store. Events without an ID get one derived from
+// their content; events whose ID is already stored are skipped.
+func (s *Store) Append(events ...Event) error {
+	for start := 0; start < len(events); start += appendBatch {
+		end := start + appendBatch
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := s.appendBatch(events[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) appendBatch(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT IGNORE INTO %s.events (id, ts, source, type, actor, actor_name, bead, rig, visibility, summary, payload) VALUES ", Database)
+	args := make([]interface{}, 0, len(events)*11)
+	for i, e := range events {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+
+		var payload interface{}
+		if len(e.Payload) > 0 {
+			data, err := json.Marshal(e.Payload)
+			if err != nil {
+				return fmt.Errorf("marshaling payload: %w", err)
+			}
+			payload = string(data)
+		}
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+		if e.ID == "" {
+			data, _ := json.Marshal(e)
+			e.ID = eventID(e.Source, string(data))
+		}
+		args = append(args, e.ID, e.Time.UTC(), e.Source, e.Type, e.Actor, e.ActorName(),
+			e.Bead, e.Rig, e.Visibility, e.Summary, payload)
+	}
+	if _, err := s.db.Exec(b.String(), args...); err != nil {
+		return fmt.Errorf("appending events: %w", err)
+	}
+	return nil
+}
+
+// Query returns the events matching q, newest first.
+func (s *Store) Query(q Query) ([]Event, error) {
+	where, args := q.where()
+	stmt := fmt.Sprintf("SELECT id, ts, source, type, actor, bead, rig, visibility, summary, payload FROM %s.events", Database)
+	if where != "" {
+		stmt += " WHERE " + where
+	}
+	stmt += " ORDER BY ts DESC, id LIMIT ?"
+	args = append(args, q.limit())
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Event
+	for rows.Next() {
+		var e Event
+		var summary, payload sql.NullString
+		if err := rows.Scan(&e.ID, &e.Time, &e.Source, &e.Type, &e.Actor, &e.Bead, &e.Rig, &e.Visibility, &summary, &payload); err != nil {
+			return nil, fmt.Errorf("reading event: %w", err)
+		}
+		e.Summary = summary.String
+		if payload.Valid && payload.String != "" {
+			_ = json.Unmarshal([]byte(payload.String), &e.Payload)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	return out, nil
+}
+
+// Count returns the number of stored events.
+func (s *Store) Count() (int64, error) {
+	var n int64
+	if err := s.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s.events", Database)).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting events: %w", err)
+	}
+	return n, nil
+}
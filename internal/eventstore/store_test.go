@@ -0,0 +1,239 @@
+package eventstore
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// startTestServer serves an empty in-memory MySQL-compatible database and
+// returns a config pointing at it.
+func startTestServer(t *testing.T) *doltserver.Config {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().(*net.TCPAddr)
+	_ = l.Close()
+
+	pro := memory.NewDBProvider()
+	engine := sqle.NewDefault(pro)
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Address: addr.String()},
+		engine, gmssql.NewContext, memory.NewSessionBuilder(pro), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	return &doltserver.Config{Host: "127.0.0.1", Port: addr.Port, User: "root"}
+}
+
+// writeTown creates a town with one line in each journal.
+func writeTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".events.jsonl",
+		`{"ts":"2026-03-09T10:00:00Z","source":"gt","type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/polecats/Toast"},"visibility":"feed"}`+"\n"+
+			`{"ts":"2026-03-09T10:01:00Z","source":"gt","type":"hook","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc"},"visibility":"feed"}`+"\n")
+	write("logs/town.log", "2026-03-09 10:00:30 [spawn] gastown/polecats/Toast spawned for gt-abc\n")
+	write("daemon/command-events.jsonl",
+		`{"event_id":"evt-1","run_id":"run-1","agent_id":"gastown/polecats/Toast","event_type":"policy_evaluated","payload":{"command":"deploy token=secret123"},"timestamp":"2026-03-09T10:02:00Z"}`+"\n")
+	return town
+}
+
+func appendLine(t *testing.T, path, line string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreSyncAndQuery(t *testing.T) {
+	s, err := Open(startTestServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	town := writeTown(t)
+
+	n, err := s.Sync(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("first Sync read %d lines, want 4", n)
+	}
+
+	got, err := s.Query(Query{Actors: []string{"toast"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("actor:toast returned %d events, want 3: %+v", len(got), got)
+	}
+	if got[0].Type != "policy_evaluated" || got[2].Type != "spawn" {
+		t.Errorf("events not newest first: %s, %s, %s", got[0].Type, got[1].Type, got[2].Type)
+	}
+	if got[0].Payload["command"] != "deploy token=[REDACTED]" {
+		t.Error("runlog secret was not redacted")
+	}
+	if got[2].Bead != "gt-abc" || got[2].Rig != "gastown" || got[2].Source != SourceTownlog {
+		t.Errorf("town log event = %+v", got[2])
+	}
+
+	got, err = s.Query(Query{Beads: []string{"gt-abc"}, Types: []string{"sling"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Actor != "mayor" || got[0].Rig != "" {
+		t.Errorf("bead+type query = %+v", got)
+	}
+
+	// A second Sync with nothing new reads nothing.
+	if n, err := s.Sync(town); err != nil || n != 0 {
+		t.Errorf("idle Sync = %d, %v", n, err)
+	}
+
+	// Only appended lines are read; a partial line waits for its newline.
+	eventsPath := filepath.Join(town, ".events.jsonl")
+	appendLine(t, eventsPath, `{"ts":"2026-03-09T11:00:00Z","source":"gt","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc"},"visibility":"feed"}`+"\n"+`{"ts":"2026-03-09T11:`)
+	if n, err := s.Sync(town); err != nil || n != 1 {
+		t.Errorf("Sync after append = %d, %v; want 1", n, err)
+	}
+	appendLine(t, eventsPath, `05:00Z","source":"gt","type":"kill","actor":"gastown/polecats/Toast","visibility":"feed"}`+"\n")
+	if n, err := s.Sync(town); err != nil || n != 1 {
+		t.Errorf("Sync after completing line = %d, %v; want 1", n, err)
+	}
+
+	// Rewriting the journal (as krc prune does) re-reads it without duplicates.
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(eventsPath, data[bytes.IndexByte(data, '\n')+1:], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Sync(town); err != nil || n != 3 {
+		t.Errorf("Sync after rewrite = %d, %v; want 3", n, err)
+	}
+	count, err := s.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 6 {
+		t.Errorf("store holds %d events, want 6", count)
+	}
+
+	got, err = s.Query(Query{Since: time.Date(2026, 3, 9, 10, 30, 0, 0, time.UTC), Text: "gt-abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Type != "done" {
+		t.Errorf("since+text query = %+v", got)
+	}
+}
+
+func TestStoreAppend(t *testing.T) {
+	s, err := Open(startTestServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ts := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)
+	var batch []Event
+	for i := 0; i < appendBatch+5; i++ {
+		batch = append(batch, Event{
+			Time:    ts.Add(time.Duration(i) * time.Second),
+			Source:  "test",
+			Type:    "ping",
+			Actor:   "gastown/witness",
+			Payload: map[string]interface{}{"n": strconv.Itoa(i)},
+		})
+	}
+	if err := s.Append(batch...); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(batch[:10]...); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := s.Count(); count != int64(len(batch)) {
+		t.Errorf("store holds %d events, want %d", count, len(batch))
+	}
+
+	got, err := s.Query(Query{Actors: []string{"gastown/*"}, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Payload["n"] != strconv.Itoa(len(batch)-1) {
+		t.Errorf("limited query = %+v", got)
+	}
+}
+
+func TestScanMatchesJournals(t *testing.T) {
+	town := writeTown(t)
+
+	got, err := Scan(town, Query{Actors: []string{"Toast"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Scan returned %d events, want 3: %+v", len(got), got)
+	}
+	if got[0].Source != SourceRunlog || got[1].Source != SourceEvents || got[2].Source != SourceTownlog {
+		t.Errorf("Scan order = %s, %s, %s", got[0].Source, got[1].Source, got[2].Source)
+	}
+	if got[2].Summary != "spawned for gt-abc" {
+		t.Errorf("town log summary = %q", got[2].Summary)
+	}
+
+	got, err = Scan(town, Query{Sources: []string{SourceEvents}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Type != "hook" {
+		t.Errorf("Scan with source and limit = %+v", got)
+	}
+}
+
+func TestQueryTownFallsBackToScan(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "")
+	town := writeTown(t)
+
+	got, indexed, err := QueryTown(town, Query{Types: []string{"sling"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexed {
+		t.Error("QueryTown used the store for a town without a Dolt data directory")
+	}
+	if len(got) != 1 || got[0].Actor != "mayor" {
+		t.Errorf("QueryTown = %+v", got)
+	}
+}
@@ -496,6 +496,15 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Session terminated"
 
+	case events.TypeSessionResume:
+		agent, _ := event.Payload["agent"].(string)
+		attempt, _ := event.Payload["attempt"].(float64)
+		maxAttempts, _ := event.Payload["max"].(float64)
+		if agent != "" && attempt > 0 {
+			return fmt.Sprintf("Resumed crashed %s (attempt %d/%d)", agent, int(attempt), int(maxAttempts))
+		}
+		return "Crashed session resumed"
+
 	case events.TypeMassDeath:
 		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
 		possibleCause, _ := event.Payload["possible_cause"].(string)
@@ -0,0 +1,527 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Loader returns the source of a formula by name. It resolves the
+// formulas named by extends, include and compose.
+type Loader func(name string) ([]byte, error)
+
+// EmbeddedLoader loads the formulas built into gt.
+func EmbeddedLoader(name string) ([]byte, error) {
+	if !validFormulaName(name) {
+		return nil, fmt.Errorf("invalid formula name %q", name)
+	}
+	return formulasFS.ReadFile("formulas/" + name + ".formula.toml")
+}
+
+// DirLoader loads formulas from dir, falling back to the embedded formulas.
+func DirLoader(dir string) Loader {
+	return func(name string) ([]byte, error) {
+		if !validFormulaName(name) {
+			return nil, fmt.Errorf("invalid formula name %q", name)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name+".formula.toml")) //nolint:gosec // G304: name is a validated formula name
+		if err == nil {
+			return data, nil
+		}
+		if embedded, embErr := EmbeddedLoader(name); embErr == nil {
+			return embedded, nil
+		}
+		return nil, fmt.Errorf("formula %q not found in %s: %w", name, dir, err)
+	}
+}
+
+func validFormulaName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// maxComposeDepth bounds extends/include nesting.
+const maxComposeDepth = 16
+
+// IsComposed reports whether the formula was built from other formulas.
+func (f *Formula) IsComposed() bool {
+	return len(f.Extends) > 0 || len(f.Include) > 0 ||
+		(f.Compose != nil && (len(f.Compose.Aspects) > 0 || len(f.Compose.Expand) > 0))
+}
+
+// StepOrigin describes where a step of a composed formula came from
+// ("" for the formula's own steps).
+func (f *Formula) StepOrigin(id string) string {
+	return f.origins[id]
+}
+
+// composer flattens one formula, tracking the formulas being composed to
+// report cycles like "a -> b -> a".
+type composer struct {
+	load  Loader
+	stack []string
+}
+
+// flatten resolves extends, include, compose.expand and compose.aspects
+// (in that order) into f.Steps. Aspects run last so they also wrap
+// inherited, included and expanded steps.
+func (c *composer) flatten(f *Formula) error {
+	if !f.IsComposed() {
+		return nil
+	}
+	if c.load == nil {
+		return fmt.Errorf("formula %s uses composition but no loader was given", f.Name)
+	}
+	f.origins = make(map[string]string)
+
+	// Inherited steps, overridden by the formula's own steps with the same ID
+	var steps []Step
+	vars := make(map[string]Var)
+	for _, name := range f.Extends {
+		parent, err := c.child(f.Name, name)
+		if err != nil {
+			return err
+		}
+		if parent.Type != TypeWorkflow {
+			return fmt.Errorf("formula %s extends %s, which is a %s formula (must be workflow)", f.Name, name, parent.Type)
+		}
+		for _, ps := range parent.Steps {
+			steps = overrideSteps(steps, ps)
+			if origin := parent.StepOrigin(ps.ID); origin != "" {
+				f.origins[ps.ID] = origin
+			} else {
+				f.origins[ps.ID] = "extends " + name
+			}
+		}
+		for k, v := range parent.Vars {
+			vars[k] = v
+		}
+		if f.Description == "" {
+			f.Description = parent.Description
+		}
+	}
+	for _, own := range f.Steps {
+		if _, inherited := f.origins[own.ID]; inherited {
+			f.origins[own.ID] += " (overridden)"
+		}
+		steps = overrideSteps(steps, own)
+	}
+	for k, v := range f.Vars {
+		vars[k] = v
+	}
+
+	for _, inc := range f.Include {
+		if inc.Formula == "" {
+			return fmt.Errorf("formula %s: include missing required formula field", f.Name)
+		}
+		g, err := c.child(f.Name, inc.Formula)
+		if err != nil {
+			return err
+		}
+		if g.Type != TypeWorkflow {
+			return fmt.Errorf("formula %s includes %s, which is a %s formula (must be workflow)", f.Name, inc.Formula, g.Type)
+		}
+		included, err := includeSteps(g, inc)
+		if err != nil {
+			return fmt.Errorf("formula %s: include %s: %w", f.Name, inc.Formula, err)
+		}
+		for _, s := range included {
+			f.origins[s.ID] = fmt.Sprintf("include %s as %s", inc.Formula, includePrefix(g, inc))
+		}
+		steps = append(steps, included...)
+		for k, v := range g.Vars {
+			if _, bound := inc.Vars[k]; bound {
+				continue
+			}
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+	}
+
+	if f.Compose != nil {
+		for _, e := range f.Compose.Expand {
+			g, err := c.child(f.Name, e.With)
+			if err != nil {
+				return err
+			}
+			if g.Type != TypeExpansion {
+				return fmt.Errorf("formula %s expands with %s, which is a %s formula (must be expansion)", f.Name, e.With, g.Type)
+			}
+			var added []string
+			if steps, added, err = expandStep(steps, e.Target, g); err != nil {
+				return fmt.Errorf("formula %s: expand %s with %s: %w", f.Name, e.Target, e.With, err)
+			}
+			for _, id := range added {
+				f.origins[id] = fmt.Sprintf("expand %s with %s", e.Target, e.With)
+			}
+		}
+
+		for _, name := range f.Compose.Aspects {
+			g, err := c.child(f.Name, name)
+			if err != nil {
+				return err
+			}
+			if g.Type != TypeAspect || len(g.Advice) == 0 {
+				return fmt.Errorf("formula %s applies %s, which is not an aspect formula with advice", f.Name, name)
+			}
+			var added []string
+			steps, added = applyAspect(steps, g)
+			for _, id := range added {
+				f.origins[id] = "aspect " + name
+			}
+		}
+	}
+
+	f.Steps = steps
+	f.Vars = vars
+	if f.Type == "" {
+		f.Type = TypeWorkflow
+	}
+	return nil
+}
+
+// sortSteps puts the steps of a flattened workflow in dependency order.
+func (f *Formula) sortSteps() error {
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]Step, len(f.Steps))
+	for _, step := range f.Steps {
+		byID[step.ID] = step
+	}
+	sorted := make([]Step, 0, len(order))
+	for _, id := range order {
+		sorted = append(sorted, byID[id])
+	}
+	f.Steps = sorted
+	return nil
+}
+
+// child loads, flattens and validates a formula referenced by parent.
+func (c *composer) child(parent, name string) (*Formula, error) {
+	for i, s := range c.stack {
+		if s == name {
+			chain := append(append([]string{}, c.stack[i:]...), name)
+			return nil, fmt.Errorf("formula composition cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+	if len(c.stack) >= maxComposeDepth {
+		return nil, fmt.Errorf("formula composition nested deeper than %d", maxComposeDepth)
+	}
+	data, err := c.load(name)
+	if err != nil {
+		return nil, fmt.Errorf("formula %s: loading %s: %w", parent, name, err)
+	}
+	g, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("formula %s: %s: %w", parent, name, err)
+	}
+	if g.Name == "" {
+		g.Name = name
+	}
+
+	sub := &composer{load: c.load, stack: append(append([]string{}, c.stack...), name)}
+	if err := sub.flatten(g); err != nil {
+		return nil, err
+	}
+	if err := g.Validate(); err != nil {
+		return nil, fmt.Errorf("formula %s: %s: %w", parent, name, err)
+	}
+	return g, nil
+}
+
+// overrideSteps adds step to steps, or merges it into the step with the
+// same ID: the override's non-empty fields replace the inherited ones.
+func overrideSteps(steps []Step, step Step) []Step {
+	for i := range steps {
+		if steps[i].ID != step.ID {
+			continue
+		}
+		base := &steps[i]
+		if step.Title != "" {
+			base.Title = step.Title
+		}
+		if step.Description != "" {
+			base.Description = step.Description
+		}
+		if step.Needs != nil {
+			base.Needs = step.Needs
+		}
+		if step.Parallel {
+			base.Parallel = true
+		}
+		if step.Acceptance != "" {
+			base.Acceptance = step.Acceptance
+		}
+		if step.Retry != 0 {
+			base.Retry = step.Retry
+		}
+		if step.Timeout != "" {
+			base.Timeout = step.Timeout
+		}
+		if step.When != "" {
+			base.When = step.When
+		}
+		if step.OnFailure != "" {
+			base.OnFailure = step.OnFailure
+		}
+		return steps
+	}
+	return append(steps, step)
+}
+
+func includePrefix(g *Formula, inc Include) string {
+	if inc.Prefix != "" {
+		return inc.Prefix
+	}
+	return g.Name
+}
+
+// bindingVarRegex matches a binding that is just an outer var, {{name}}.
+var bindingVarRegex = regexp.MustCompile(`^\{\{(\w+)\}\}$`)
+
+// includeSteps returns g's steps prefixed and with the include's var
+// bindings substituted.
+func includeSteps(g *Formula, inc Include) ([]Step, error) {
+	prefix := includePrefix(g, inc)
+	for name := range inc.Vars {
+		if _, ok := g.Vars[name]; !ok {
+			return nil, fmt.Errorf("binds unknown var %q", name)
+		}
+	}
+	rename := func(id string) string { return prefix + "." + id }
+
+	bind := func(s string) string {
+		for name, value := range inc.Vars {
+			s = strings.ReplaceAll(s, "{{"+name+"}}", value)
+		}
+		return s
+	}
+
+	out := make([]Step, 0, len(g.Steps))
+	for _, s := range g.Steps {
+		s.ID = rename(s.ID)
+		s.Title = bind(s.Title)
+		s.Description = bind(s.Description)
+		s.Acceptance = bind(s.Acceptance)
+
+		needs := make([]string, 0, len(s.Needs)+len(inc.Needs))
+		for _, n := range s.Needs {
+			needs = append(needs, rename(n))
+		}
+		if len(s.Needs) == 0 {
+			needs = append(needs, inc.Needs...)
+		}
+		s.Needs = needs
+
+		if action, target := s.FailureAction(); action == "goto" {
+			s.OnFailure = onFailureGoto + rename(target)
+		}
+		if s.When != "" {
+			when, err := rewriteCondition(s.When, func(ref CondRef) (string, bool) {
+				if ref.Kind == "steps" {
+					return "steps." + rename(ref.Name) + "." + ref.Field, false
+				}
+				value, bound := inc.Vars[ref.Name]
+				if !bound {
+					return "", false
+				}
+				if m := bindingVarRegex.FindStringSubmatch(value); m != nil {
+					return "vars." + m[1], false
+				}
+				return value, true
+			})
+			if err != nil {
+				return nil, fmt.Errorf("step %q: invalid when: %w", s.ID, err)
+			}
+			s.When = when
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// expandStep replaces the target step with the expansion's templates,
+// returning the new steps and the IDs it added. The first templates take
+// over the target's needs, and steps that needed the target need the last
+// templates instead.
+func expandStep(steps []Step, target string, g *Formula) ([]Step, []string, error) {
+	idx := -1
+	for i := range steps {
+		if steps[i].ID == target {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("no step %q", target)
+	}
+	t := steps[idx]
+	sub := strings.NewReplacer("{target}", t.ID, "{target.title}", t.Title, "{target.description}", t.Description)
+
+	var expanded []Step
+	needed := make(map[string]bool)
+	for _, tmpl := range g.Template {
+		s := Step{
+			ID:          sub.Replace(tmpl.ID),
+			Title:       sub.Replace(tmpl.Title),
+			Description: sub.Replace(tmpl.Description),
+		}
+		for _, n := range tmpl.Needs {
+			id := sub.Replace(n)
+			s.Needs = append(s.Needs, id)
+			needed[id] = true
+		}
+		if len(tmpl.Needs) == 0 {
+			s.Needs = append([]string(nil), t.Needs...)
+		}
+		expanded = append(expanded, s)
+	}
+	var sinks, added []string
+	for _, s := range expanded {
+		added = append(added, s.ID)
+		if !needed[s.ID] {
+			sinks = append(sinks, s.ID)
+		}
+	}
+
+	out := make([]Step, 0, len(steps)+len(expanded)-1)
+	out = append(out, steps[:idx]...)
+	out = append(out, expanded...)
+	out = append(out, steps[idx+1:]...)
+	for i := range out {
+		out[i].Needs = replaceNeed(out[i].Needs, target, sinks)
+	}
+	return out, added, nil
+}
+
+// applyAspect wraps the steps matched by each advice with its before and
+// after steps, returning the new steps and the IDs it added. Before steps
+// run in order ahead of the target and take over its needs; after steps
+// run in order behind it, and the target's dependents wait for the last.
+func applyAspect(steps []Step, g *Formula) ([]Step, []string) {
+	var added []string
+	for _, adv := range g.Advice {
+		if adv.Around == nil || (len(adv.Around.Before) == 0 && len(adv.Around.After) == 0) {
+			continue
+		}
+		globs := []string{adv.Target}
+		if adv.Target == "" {
+			globs = nil
+			for _, pc := range g.Pointcuts {
+				globs = append(globs, pc.Glob)
+			}
+		}
+
+		var targets []string
+		for _, s := range steps {
+			if matchesAny(globs, s.ID) && !contains(added, s.ID) {
+				targets = append(targets, s.ID)
+			}
+		}
+
+		for _, id := range targets {
+			idx := -1
+			for i := range steps {
+				if steps[i].ID == id {
+					idx = i
+					break
+				}
+			}
+			t := steps[idx]
+			sub := strings.NewReplacer("{step.id}", t.ID, "{step.title}", t.Title, "{step.description}", t.Description)
+			instantiate := func(tmpl Step) Step {
+				s := tmpl
+				s.ID = sub.Replace(tmpl.ID)
+				s.Title = sub.Replace(tmpl.Title)
+				s.Description = sub.Replace(tmpl.Description)
+				s.Acceptance = sub.Replace(tmpl.Acceptance)
+				s.Needs = nil
+				return s
+			}
+
+			var before, after []Step
+			prev := t.Needs
+			for _, tmpl := range adv.Around.Before {
+				s := instantiate(tmpl)
+				s.Needs = append([]string(nil), prev...)
+				prev = []string{s.ID}
+				before = append(before, s)
+			}
+			t.Needs = append([]string(nil), prev...)
+
+			prev = []string{t.ID}
+			for _, tmpl := range adv.Around.After {
+				s := instantiate(tmpl)
+				s.Needs = prev
+				prev = []string{s.ID}
+				after = append(after, s)
+			}
+			if len(after) > 0 {
+				last := after[len(after)-1].ID
+				for i := range steps {
+					if i != idx {
+						steps[i].Needs = replaceNeed(steps[i].Needs, t.ID, []string{last})
+					}
+				}
+			}
+
+			out := make([]Step, 0, len(steps)+len(before)+len(after))
+			out = append(out, steps[:idx]...)
+			out = append(out, before...)
+			out = append(out, t)
+			out = append(out, after...)
+			out = append(out, steps[idx+1:]...)
+			steps = out
+			for _, s := range before {
+				added = append(added, s.ID)
+			}
+			for _, s := range after {
+				added = append(added, s.ID)
+			}
+		}
+	}
+	return steps, added
+}
+
+// replaceNeed replaces old in needs with repl, keeping needs unique.
+func replaceNeed(needs []string, old string, repl []string) []string {
+	if !contains(needs, old) {
+		return needs
+	}
+	var out []string
+	for _, n := range needs {
+		if n != old {
+			out = append(out, n)
+			continue
+		}
+		for _, r := range repl {
+			if !contains(out, r) && !contains(needs, r) {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+func matchesAny(globs []string, id string) bool {
+	for _, g := range globs {
+		if ok, err := path.Match(g, id); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mapLoader(files map[string]string) Loader {
+	return func(name string) ([]byte, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("no formula %s", name)
+		}
+		return []byte(data), nil
+	}
+}
+
+func stepIDs(f *Formula) []string {
+	var ids []string
+	for _, s := range f.Steps {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestCompose_EmbeddedVariants(t *testing.T) {
+	for _, name := range []string{"shiny-secure", "shiny-enterprise", "security-audit"} {
+		data, err := EmbeddedLoader(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Parse(data); err != nil {
+			t.Errorf("Parse(%s): %v", name, err)
+		}
+	}
+
+	data, _ := EmbeddedLoader("shiny-secure")
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"design", "implement-security-prescan", "implement", "implement-security-postscan",
+		"review", "test", "submit-security-prescan", "submit", "submit-security-postscan"}
+	if got := stepIDs(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("shiny-secure steps = %v\nwant %v", got, want)
+	}
+	if got := f.GetStep("review").Needs; !reflect.DeepEqual(got, []string{"implement-security-postscan"}) {
+		t.Errorf("review needs = %v, want the post-scan", got)
+	}
+	if f.StepOrigin("design") != "extends shiny" || f.StepOrigin("implement-security-prescan") != "aspect security-audit" {
+		t.Errorf("origins = %q, %q", f.StepOrigin("design"), f.StepOrigin("implement-security-prescan"))
+	}
+
+	data, _ = EmbeddedLoader("shiny-enterprise")
+	f, err = Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.GetStep("implement") != nil || f.GetStep("implement.draft") == nil {
+		t.Errorf("implement should be expanded, steps = %v", stepIDs(f))
+	}
+	if got := f.GetStep("review").Needs; !reflect.DeepEqual(got, []string{"implement.refine-4"}) {
+		t.Errorf("review needs = %v, want the last refinement", got)
+	}
+}
+
+var composeFiles = map[string]string{
+	"base": `
+formula = "base"
+description = "Base workflow"
+[vars.issue]
+required = true
+[[steps]]
+id = "setup"
+title = "Set up {{issue}}"
+[[steps]]
+id = "work"
+title = "Work"
+needs = ["setup"]
+[[steps]]
+id = "done"
+title = "Done"
+needs = ["work"]
+`,
+	"review": `
+formula = "review"
+[vars.target]
+required = true
+[vars.depth]
+default = "quick"
+[[steps]]
+id = "diff"
+title = "Diff {{target}}"
+[[steps]]
+id = "check"
+title = "Check {{target}} ({{depth}})"
+needs = ["diff"]
+when = 'vars.target != "" && steps.diff.output contains "go"'
+on_failure = "goto:diff"
+`,
+	"audit": `
+formula = "audit"
+type = "aspect"
+[[advice]]
+target = "rev.*"
+[advice.around]
+[[advice.around.before]]
+id = "{step.id}-pre"
+title = "Audit before {step.title}"
+`,
+}
+
+func TestCompose_ExtendsOverride(t *testing.T) {
+	f, err := ParseWithLoader([]byte(`
+formula = "child"
+extends = "base"
+[[steps]]
+id = "work"
+title = "Work carefully"
+retry = 2
+[[steps]]
+id = "ship"
+needs = ["done"]
+`), mapLoader(composeFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type != TypeWorkflow || f.Description != "Base workflow" {
+		t.Errorf("type=%s description=%q", f.Type, f.Description)
+	}
+	if got := stepIDs(f); !reflect.DeepEqual(got, []string{"setup", "work", "done", "ship"}) {
+		t.Errorf("steps = %v", got)
+	}
+	work := f.GetStep("work")
+	if work.Title != "Work carefully" || work.Retry != 2 || !reflect.DeepEqual(work.Needs, []string{"setup"}) {
+		t.Errorf("override = %+v, want new title and retry with inherited needs", work)
+	}
+	if _, ok := f.Vars["issue"]; !ok {
+		t.Error("inherited var issue missing")
+	}
+	if f.StepOrigin("work") != "extends base (overridden)" || f.StepOrigin("ship") != "" {
+		t.Errorf("origins = %q, %q", f.StepOrigin("work"), f.StepOrigin("ship"))
+	}
+}
+
+func TestCompose_IncludeWithAspect(t *testing.T) {
+	f, err := ParseWithLoader([]byte(`
+formula = "outer"
+[vars.branch]
+required = true
+[[steps]]
+id = "implement"
+title = "Implement"
+[[include]]
+formula = "review"
+prefix = "rev"
+needs = ["implement"]
+[include.vars]
+target = "{{branch}}"
+[[steps]]
+id = "merge"
+needs = ["rev.check"]
+[compose]
+aspects = ["audit"]
+`), mapLoader(composeFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"implement", "rev.diff-pre", "rev.diff", "rev.check-pre", "rev.check", "merge"}
+	if got := stepIDs(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v\nwant %v", got, want)
+	}
+
+	diff := f.GetStep("rev.diff")
+	if diff.Title != "Diff {{branch}}" || !reflect.DeepEqual(diff.Needs, []string{"rev.diff-pre"}) {
+		t.Errorf("rev.diff = %+v", diff)
+	}
+	if pre := f.GetStep("rev.diff-pre"); pre.Title != "Audit before Diff {{branch}}" || !reflect.DeepEqual(pre.Needs, []string{"implement"}) {
+		t.Errorf("rev.diff-pre = %+v", pre)
+	}
+	check := f.GetStep("rev.check")
+	if check.Title != "Check {{branch}} ({{depth}})" {
+		t.Errorf("check title = %q", check.Title)
+	}
+	if check.When != `vars.branch != "" && steps.rev.diff.output contains "go"` {
+		t.Errorf("check when = %q", check.When)
+	}
+	if check.OnFailure != "goto:rev.diff" {
+		t.Errorf("check on_failure = %q", check.OnFailure)
+	}
+
+	// Bound vars are not inherited; unbound ones keep their definition.
+	if _, ok := f.Vars["target"]; ok {
+		t.Error("bound var target should not be added")
+	}
+	if f.Vars["depth"].Default != "quick" {
+		t.Errorf("vars = %v, want depth from the included formula", f.Vars)
+	}
+	if f.StepOrigin("rev.check") != "include review as rev" {
+		t.Errorf("origin = %q", f.StepOrigin("rev.check"))
+	}
+}
+
+func TestCompose_Errors(t *testing.T) {
+	files := map[string]string{
+		"a": "formula = \"a\"\nextends = \"b\"\n",
+		"b": "formula = \"b\"\n[[include]]\nformula = \"a\"\n",
+	}
+	for k, v := range composeFiles {
+		files[k] = v
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"cycle", `formula = "top"
+extends = "a"`, "cycle: a -> b -> a"},
+		{"self", `formula = "top"
+extends = "top"`, "cycle: top -> top"},
+		{"missing", `formula = "top"
+extends = "nope"`, "loading nope"},
+		{"unknown binding", `formula = "top"
+[[include]]
+formula = "review"
+[include.vars]
+nope = "x"`, `binds unknown var "nope"`},
+		{"duplicate id", `formula = "top"
+[[include]]
+formula = "base"
+prefix = "p"
+[[include]]
+formula = "base"
+prefix = "p"`, "duplicate step id"},
+		{"dag cycle", `formula = "top"
+extends = "base"
+[[steps]]
+id = "setup"
+needs = ["done"]`, "cycle"},
+		{"extend aspect", `formula = "top"
+extends = "audit"`, "must be workflow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithLoader([]byte(tt.src), mapLoader(files))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFile_ComposesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "base.formula.toml"), []byte(composeFiles["base"]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	child := filepath.Join(dir, "child.formula.toml")
+	src := "formula = \"child\"\nextends = [\"base\", \"shiny\"]\n"
+	if err := os.WriteFile(child, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ParseFile(child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// base from the directory, shiny from the built-ins
+	if f.GetStep("setup") == nil || f.GetStep("design") == nil {
+		t.Errorf("steps = %v", stepIDs(f))
+	}
+}
@@ -0,0 +1,406 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Condition is a parsed step `when` expression. The grammar is small:
+//
+//	expr    := and ("||" and)*
+//	and     := unary ("&&" unary)*
+//	unary   := "!" unary | "(" expr ")" | operand [op operand]
+//	op      := "==" | "!=" | "contains" | "matches"
+//	operand := ref | "string" | 'string' | bare-word
+//	ref     := vars.<name> | steps.<id>.output | steps.<id>.status
+//
+// Every value is a string. An operand on its own is true when it is
+// non-empty and not "false", "0" or "no". matches takes a Go regexp.
+//
+// Examples:
+//
+//	vars.mode == "release"
+//	steps.diff.output matches "(^|\\s)auth/" && !vars.skip_security
+//	steps.lint.status != "skipped"
+type Condition struct {
+	src  string
+	root condNode
+	refs []CondRef
+}
+
+// CondRef is a variable or step reference in a condition.
+type CondRef struct {
+	Kind  string // "vars" or "steps"
+	Name  string // var name or step id
+	Field string // "output" or "status" for step refs
+}
+
+func (r CondRef) String() string {
+	if r.Kind == "steps" {
+		return "steps." + r.Name + "." + r.Field
+	}
+	return "vars." + r.Name
+}
+
+// ParseCondition parses a `when` expression.
+func ParseCondition(src string) (*Condition, error) {
+	toks, err := lexCondition(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return &Condition{src: src, root: root, refs: p.refs}, nil
+}
+
+// String returns the source expression.
+func (c *Condition) String() string { return c.src }
+
+// Refs returns the var and step references in the condition.
+func (c *Condition) Refs() []CondRef { return c.refs }
+
+// Eval evaluates the condition against a run's vars and step states.
+func (c *Condition) Eval(st *RunState) bool {
+	return c.root.eval(st)
+}
+
+// truthy reports whether a bare operand counts as true.
+func truthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no":
+		return false
+	}
+	return true
+}
+
+type condNode interface {
+	eval(st *RunState) bool
+}
+
+type condOr struct{ left, right condNode }
+
+func (n condOr) eval(st *RunState) bool { return n.left.eval(st) || n.right.eval(st) }
+
+type condAnd struct{ left, right condNode }
+
+func (n condAnd) eval(st *RunState) bool { return n.left.eval(st) && n.right.eval(st) }
+
+type condNot struct{ inner condNode }
+
+func (n condNot) eval(st *RunState) bool { return !n.inner.eval(st) }
+
+type condTruthy struct{ v condOperand }
+
+func (n condTruthy) eval(st *RunState) bool { return truthy(n.v.value(st)) }
+
+type condCompare struct {
+	op          string
+	left, right condOperand
+	re          *regexp.Regexp // precompiled when the pattern is a literal
+}
+
+func (n condCompare) eval(st *RunState) bool {
+	l, r := n.left.value(st), n.right.value(st)
+	switch n.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "contains":
+		return strings.Contains(l, r)
+	case "matches":
+		re := n.re
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(r); err != nil {
+				return false
+			}
+		}
+		return re.MatchString(l)
+	}
+	return false
+}
+
+// condOperand is a literal or a reference resolved at evaluation time.
+type condOperand struct {
+	ref *CondRef
+	lit string
+}
+
+func (o condOperand) value(st *RunState) string {
+	if o.ref == nil {
+		return o.lit
+	}
+	if st == nil {
+		return ""
+	}
+	if o.ref.Kind == "vars" {
+		return st.Vars[o.ref.Name]
+	}
+	s := st.Steps[o.ref.Name]
+	if s == nil {
+		if o.ref.Field == "status" {
+			return string(StepPending)
+		}
+		return ""
+	}
+	if o.ref.Field == "status" {
+		return string(s.Status)
+	}
+	return s.Output
+}
+
+type condToken struct {
+	text   string
+	quoted bool // a string literal
+}
+
+// lexCondition splits a condition into operators, string literals and words.
+func lexCondition(src string) ([]condToken, error) {
+	var toks []condToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, condToken{text: string(c)})
+			i++
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="):
+			toks = append(toks, condToken{text: src[i : i+2]})
+			i += 2
+		case c == '!':
+			toks = append(toks, condToken{text: "!"})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' && c == '"' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			lit := src[i+1 : end]
+			if c == '"' {
+				s, err := strconv.Unquote(src[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("bad string %s: %w", src[i:end+1], err)
+				}
+				lit = s
+			}
+			toks = append(toks, condToken{text: lit, quoted: true})
+			i = end + 1
+		case isCondWordChar(c):
+			end := i
+			for end < len(src) && isCondWordChar(src[end]) {
+				end++
+			}
+			toks = append(toks, condToken{text: src[i:end]})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return toks, nil
+}
+
+func isCondWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == '/'
+}
+
+type condParser struct {
+	toks []condToken
+	pos  int
+	refs []CondRef
+}
+
+func (p *condParser) peek() (condToken, bool) {
+	if p.pos >= len(p.toks) {
+		return condToken{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// peekOp reports whether the next token is the unquoted operator op.
+func (p *condParser) peekOp(op string) bool {
+	t, ok := p.peek()
+	return ok && !t.quoted && t.text == op
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = condOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = condAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.peekOp("!") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return condNot{inner}, nil
+	}
+	if p.peekOp("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOp(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.peek()
+	if !ok || t.quoted {
+		return condTruthy{left}, nil
+	}
+	switch t.text {
+	case "==", "!=", "contains", "matches":
+	default:
+		return condTruthy{left}, nil
+	}
+	p.pos++
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	n := condCompare{op: t.text, left: left, right: right}
+	if t.text == "matches" && right.ref == nil {
+		if n.re, err = regexp.Compile(right.lit); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", right.lit, err)
+		}
+	}
+	return n, nil
+}
+
+func (p *condParser) parseOperand() (condOperand, error) {
+	t, ok := p.peek()
+	if !ok {
+		return condOperand{}, fmt.Errorf("unexpected end of condition")
+	}
+	p.pos++
+	if t.quoted {
+		return condOperand{lit: t.text}, nil
+	}
+	switch t.text {
+	case "(", ")", "!", "&&", "||", "==", "!=", "contains", "matches":
+		return condOperand{}, fmt.Errorf("unexpected %q", t.text)
+	}
+
+	switch {
+	case strings.HasPrefix(t.text, "vars."):
+		name := strings.TrimPrefix(t.text, "vars.")
+		if name == "" || strings.Contains(name, ".") {
+			return condOperand{}, fmt.Errorf("bad var reference %q", t.text)
+		}
+		ref := CondRef{Kind: "vars", Name: name}
+		p.refs = append(p.refs, ref)
+		return condOperand{ref: &ref}, nil
+	case strings.HasPrefix(t.text, "steps."):
+		rest := strings.TrimPrefix(t.text, "steps.")
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 {
+			return condOperand{}, fmt.Errorf("bad step reference %q (want steps.<id>.output or steps.<id>.status)", t.text)
+		}
+		ref := CondRef{Kind: "steps", Name: rest[:dot], Field: rest[dot+1:]}
+		if ref.Field != "output" && ref.Field != "status" {
+			return condOperand{}, fmt.Errorf("bad step reference %q (want steps.<id>.output or steps.<id>.status)", t.text)
+		}
+		p.refs = append(p.refs, ref)
+		return condOperand{ref: &ref}, nil
+	}
+	return condOperand{lit: t.text}, nil
+}
+
+// rewriteCondition rewrites the var and step references in a condition.
+// replace returns the new text of a reference, and whether it is a literal
+// value (quoted) rather than a reference; an empty result keeps it as is.
+func rewriteCondition(src string, replace func(CondRef) (string, bool)) (string, error) {
+	if _, err := ParseCondition(src); err != nil {
+		return "", err
+	}
+	toks, err := lexCondition(src)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(toks))
+	for i, t := range toks {
+		switch {
+		case t.quoted:
+			parts[i] = strconv.Quote(t.text)
+		case strings.HasPrefix(t.text, "vars.") || strings.HasPrefix(t.text, "steps."):
+			parts[i] = t.text
+			ref := parseCondRef(t.text)
+			if text, literal := replace(ref); text != "" {
+				if literal {
+					text = strconv.Quote(text)
+				}
+				parts[i] = text
+			}
+		default:
+			parts[i] = t.text
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// parseCondRef splits an already validated vars.x or steps.<id>.field word.
+func parseCondRef(word string) CondRef {
+	if name, ok := strings.CutPrefix(word, "vars."); ok {
+		return CondRef{Kind: "vars", Name: name}
+	}
+	rest := strings.TrimPrefix(word, "steps.")
+	dot := strings.LastIndex(rest, ".")
+	return CondRef{Kind: "steps", Name: rest[:dot], Field: rest[dot+1:]}
+}
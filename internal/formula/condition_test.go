@@ -0,0 +1,86 @@
+package formula
+
+import "testing"
+
+func TestCondition_Eval(t *testing.T) {
+	st := &RunState{
+		Vars: map[string]string{"mode": "release", "skip_security": "false", "empty": ""},
+		Steps: map[string]*StepState{
+			"diff": {Status: StepDone, Output: "internal/auth/token.go\ndocs/README.md"},
+			"lint": {Status: StepSkipped},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`vars.mode == "release"`, true},
+		{`vars.mode != "release"`, false},
+		{`vars.mode == release`, true},
+		{`vars.mode == 'release'`, true},
+		{`vars.empty`, false},
+		{`!vars.empty`, true},
+		{`vars.skip_security`, false},
+		{`vars.unset == ""`, true},
+		{`steps.diff.output contains "auth/"`, true},
+		{`steps.diff.output matches "(^|\\s)internal/auth/"`, true},
+		{`steps.diff.output matches "^cmd/"`, false},
+		{`steps.lint.status == skipped`, true},
+		{`steps.missing.status == pending`, true},
+		{`steps.diff.output contains "auth/" && !vars.skip_security`, true},
+		{`vars.mode == "dev" || steps.lint.status == "skipped"`, true},
+		{`!(vars.mode == "release" && vars.empty)`, true},
+		{`vars.mode == "dev" || vars.mode == "test" && vars.empty`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCondition(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCondition: %v", err)
+			}
+			if got := c.Eval(st); got != tt.want {
+				t.Errorf("Eval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`vars.mode ==`,
+		`(vars.mode == "x"`,
+		`vars.mode == "x")`,
+		`"unterminated`,
+		`steps.diff`,
+		`steps.diff.result == "x"`,
+		`vars. == "x"`,
+		`vars.mode matches "("`,
+		`vars.mode $ "x"`,
+	} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCondition_Refs(t *testing.T) {
+	c, err := ParseCondition(`vars.mode == "x" && steps.build-all.output contains steps.lint.status`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range c.Refs() {
+		got = append(got, r.String())
+	}
+	want := []string{"vars.mode", "steps.build-all.output", "steps.lint.status"}
+	if len(got) != len(want) {
+		t.Fatalf("Refs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Refs[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -27,13 +27,12 @@
 //	    log.Fatal(err)
 //	}
 //
-//	// Execute steps, tracking completion
-//	completed := make(map[string]bool)
-//	for len(completed) < len(order) {
-//	    ready := f.ReadySteps(completed)
+//	// Execute steps, tracking their state
+//	run := f.NewRunState(map[string]string{"issue": "gt-123"})
+//	for ready := f.Advance(run); len(ready) > 0; ready = f.Advance(run) {
 //	    // Execute ready steps in parallel...
 //	    for _, id := range ready {
-//	        completed[id] = true
+//	        run.Step(id).Status = formula.StepDone
 //	    }
 //	}
 //
@@ -114,6 +113,44 @@
 //	ready := f.ReadySteps(completed)
 //	// Returns: ["build"] (test is done, build can run)
 //
+// # Step Policy
+//
+// Workflow steps may declare retry, timeout, when and on_failure:
+//
+//	[[steps]]
+//	id = "test"
+//	needs = ["implement"]
+//	retry = 2
+//	timeout = "45m"
+//	when = 'vars.mode != "docs-only"'
+//	on_failure = "goto:implement"
+//
+// Advance skips steps whose when condition (see ParseCondition) is false and
+// treats them as done. OnStepFailed applies retry and on_failure to a failed
+// step: retry re-runs it in place, then it escalates (the default), is
+// skipped, or jumps back to an earlier step, re-running it and its
+// dependents.
+//
+// # Composition
+//
+// Workflows can be built from other formulas. Parse flattens them into
+// plain steps before validation, in this order:
+//
+//	extends = "shiny"        # inherit steps and vars; same-id steps override
+//
+//	[[include]]              # add review's steps as rev.<id>
+//	formula = "review"
+//	prefix = "rev"
+//	needs = ["implement"]
+//	[include.vars]
+//	target = "{{branch}}"
+//
+//	[compose]
+//	aspects = ["security-audit"]  # applied last, so they see every step
+//
+// Referenced formulas are loaded through a Loader: ParseFile looks next to
+// the file, then in the embedded formulas. Composition cycles are errors.
+//
 // # Embedded Formulas
 //
 // The package includes embedded formula files that can be provisioned
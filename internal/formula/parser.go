@@ -3,22 +3,57 @@ package formula
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
-// ParseFile reads and parses a formula.toml file.
+// ParseFile reads and parses a formula.toml file. Formulas it extends or
+// includes are loaded from the same directory, then from the built-ins.
 func ParseFile(path string) (*Formula, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted formula directory
 	if err != nil {
 		return nil, fmt.Errorf("reading formula file: %w", err)
 	}
-	return Parse(data)
+	return ParseWithLoader(data, DirLoader(filepath.Dir(path)))
 }
 
-// Parse parses formula.toml content from bytes.
+// Parse parses formula.toml content from bytes. Formulas it extends or
+// includes are loaded from the built-ins.
 func Parse(data []byte) (*Formula, error) {
+	return ParseWithLoader(data, EmbeddedLoader)
+}
+
+// ParseWithLoader parses formula.toml content, resolving extends, include
+// and compose through load, and validates the flattened result.
+func ParseWithLoader(data []byte, load Loader) (*Formula, error) {
+	f, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &composer{load: load, stack: []string{f.Name}}
+	if err := c.flatten(f); err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	if f.IsComposed() && f.Type == TypeWorkflow {
+		if err := f.sortSteps(); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// decode unmarshals formula.toml content without validating it.
+func decode(data []byte) (*Formula, error) {
 	var f Formula
 	if _, err := toml.Decode(string(data), &f); err != nil {
 		return nil, fmt.Errorf("parsing TOML: %w", err)
@@ -27,10 +62,6 @@ func Parse(data []byte) (*Formula, error) {
 	// Infer type from content if not explicitly set
 	f.inferType()
 
-	if err := f.Validate(); err != nil {
-		return nil, err
-	}
-
 	return &f, nil
 }
 
@@ -47,8 +78,10 @@ func (f *Formula) inferType() {
 		f.Type = TypeConvoy
 	} else if len(f.Template) > 0 {
 		f.Type = TypeExpansion
-	} else if len(f.Aspects) > 0 {
+	} else if len(f.Aspects) > 0 || len(f.Advice) > 0 {
 		f.Type = TypeAspect
+	} else if len(f.Extends) > 0 || len(f.Include) > 0 {
+		f.Type = TypeWorkflow
 	}
 }
 
@@ -147,6 +180,76 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
+	// Validate retry/timeout/when/on_failure (needs an acyclic graph)
+	for i := range f.Steps {
+		if err := f.validateStepPolicy(&f.Steps[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateStepPolicy checks a step's execution policy fields. Conditions may
+// only reference declared vars and steps the step needs (directly or
+// transitively), since only those have finished when it is evaluated. A
+// goto must jump back to such a step, so the failed step runs again.
+func (f *Formula) validateStepPolicy(step *Step) error {
+	if step.Retry < 0 {
+		return fmt.Errorf("step %q: retry must not be negative", step.ID)
+	}
+
+	if step.Timeout != "" {
+		d, err := time.ParseDuration(step.Timeout)
+		if err != nil {
+			return fmt.Errorf("step %q: invalid timeout %q: %w", step.ID, step.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("step %q: timeout must be positive", step.ID)
+		}
+	}
+
+	var needs map[string]bool
+	if step.When != "" || step.OnFailure != "" {
+		needs = f.transitiveNeeds(step.ID)
+	}
+
+	if step.When != "" {
+		cond, err := ParseCondition(step.When)
+		if err != nil {
+			return fmt.Errorf("step %q: invalid when: %w", step.ID, err)
+		}
+		for _, ref := range cond.Refs() {
+			switch ref.Kind {
+			case "vars":
+				if _, ok := f.Vars[ref.Name]; !ok {
+					return fmt.Errorf("step %q: when references undeclared var: %s", step.ID, ref.Name)
+				}
+			case "steps":
+				if !needs[ref.Name] {
+					return fmt.Errorf("step %q: when references %s, but %q is not a step it needs", step.ID, ref, ref.Name)
+				}
+			}
+		}
+	}
+
+	action, target := step.FailureAction()
+	switch action {
+	case OnFailureEscalate, OnFailureSkip:
+	case "goto":
+		if target == step.ID {
+			return fmt.Errorf("step %q: on_failure cannot jump to itself (use retry)", step.ID)
+		}
+		if f.GetStep(target) == nil {
+			return fmt.Errorf("step %q: on_failure jumps to unknown step: %s", step.ID, target)
+		}
+		if !needs[target] {
+			return fmt.Errorf("step %q: on_failure must jump back to a step it needs, not %s", step.ID, target)
+		}
+	default:
+		return fmt.Errorf("step %q: invalid on_failure %q (must be escalate, skip, or goto:<step-id>)", step.ID, step.OnFailure)
+	}
+
 	return nil
 }
 
@@ -185,8 +288,8 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
 	}
 
 	// Check aspect IDs are unique
@@ -357,26 +460,23 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+//
+// For workflows, `when` conditions are evaluated against the var defaults:
+// a step whose condition is false is skipped rather than returned, and
+// counts as done for its dependents. Use Advance with a RunState to
+// evaluate conditions against run vars and step outputs.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
-			if completed[step.ID] {
-				continue
-			}
-			allMet := true
-			for _, need := range step.Needs {
-				if !completed[need] {
-					allMet = false
-					break
-				}
-			}
-			if allMet {
-				ready = append(ready, step.ID)
+		st := f.NewRunState(nil)
+		for id, done := range completed {
+			if done {
+				st.Step(id).Status = StepDone
 			}
 		}
+		ready = f.Advance(st)
 	case TypeExpansion:
 		for _, tmpl := range f.Template {
 			if completed[tmpl.ID] {
@@ -428,7 +528,12 @@ func (f *Formula) GetStep(id string) *Step {
 // - sequentialStep: the first non-parallel ready step, or nil if all are parallel
 // If multiple parallel steps are ready, they should all be executed concurrently.
 func (f *Formula) ParallelReadySteps(completed map[string]bool) (parallel []string, sequential string) {
-	ready := f.ReadySteps(completed)
+	return f.groupReady(f.ReadySteps(completed))
+}
+
+// groupReady splits ready steps into a parallel group or a single
+// sequential step, as ParallelReadySteps returns them.
+func (f *Formula) groupReady(ready []string) (parallel []string, sequential string) {
 	if len(ready) == 0 {
 		return nil, ""
 	}
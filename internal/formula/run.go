@@ -0,0 +1,249 @@
+package formula
+
+import "time"
+
+// StepStatus is the execution state of a workflow step.
+type StepStatus string
+
+const (
+	StepPending StepStatus = "pending"
+	StepRunning StepStatus = "running"
+	StepDone    StepStatus = "done"
+	StepSkipped StepStatus = "skipped" // when was false, or on_failure = "skip"
+	StepFailed  StepStatus = "failed"  // failed and escalated; dependents stay blocked
+)
+
+// MaxStepJumps bounds how often one step's on_failure goto can send a run
+// back to an earlier step before the failure is escalated instead.
+const MaxStepJumps = 3
+
+// StepState is one step's progress in a run.
+type StepState struct {
+	Status    StepStatus
+	Attempts  int // failed attempts so far
+	Jumps     int // on_failure gotos taken from this step
+	Output    string
+	StartedAt time.Time
+}
+
+// RunState is the progress of one run of a workflow formula: the vars it
+// was started with and the state of each step. It is what `when`
+// conditions are evaluated against.
+type RunState struct {
+	Vars  map[string]string
+	Steps map[string]*StepState
+}
+
+// NewRunState returns an empty run of f with the formula's var defaults
+// overridden by vars.
+func (f *Formula) NewRunState(vars map[string]string) *RunState {
+	st := &RunState{Vars: make(map[string]string), Steps: make(map[string]*StepState)}
+	for name, v := range f.Vars {
+		if v.Default != "" {
+			st.Vars[name] = v.Default
+		}
+	}
+	for name, v := range vars {
+		st.Vars[name] = v
+	}
+	return st
+}
+
+// HasStepPolicy reports whether any step declares retry, timeout, when or
+// on_failure.
+func (f *Formula) HasStepPolicy() bool {
+	for _, step := range f.Steps {
+		if step.Retry > 0 || step.Timeout != "" || step.When != "" || step.OnFailure != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Step returns the state of step id, adding a pending entry if needed.
+func (st *RunState) Step(id string) *StepState {
+	s, ok := st.Steps[id]
+	if !ok {
+		s = &StepState{Status: StepPending}
+		st.Steps[id] = s
+	}
+	return s
+}
+
+func (st *RunState) status(id string) StepStatus {
+	if s, ok := st.Steps[id]; ok {
+		return s.Status
+	}
+	return StepPending
+}
+
+// settled reports whether a step no longer holds up its dependents.
+func (st *RunState) settled(id string) bool {
+	switch st.status(id) {
+	case StepDone, StepSkipped:
+		return true
+	}
+	return false
+}
+
+// Advance computes the ready steps of a workflow run. Pending steps whose
+// needs are all done or skipped and whose `when` condition is false are
+// marked skipped (which can unblock further steps); the remaining pending
+// steps with all needs settled are returned in formula order. A failed
+// step blocks its dependents.
+func (f *Formula) Advance(st *RunState) []string {
+	if f.Type != TypeWorkflow {
+		return nil
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := range f.Steps {
+			step := &f.Steps[i]
+			if st.status(step.ID) != StepPending || !st.needsSettled(step) {
+				continue
+			}
+			if step.When == "" {
+				continue
+			}
+			cond, err := ParseCondition(step.When)
+			if err != nil {
+				// Validated formulas can't get here; run the step rather
+				// than silently dropping it.
+				continue
+			}
+			if !cond.Eval(st) {
+				st.Step(step.ID).Status = StepSkipped
+				changed = true
+			}
+		}
+	}
+
+	var ready []string
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if st.status(step.ID) == StepPending && st.needsSettled(step) {
+			ready = append(ready, step.ID)
+		}
+	}
+	return ready
+}
+
+func (st *RunState) needsSettled(step *Step) bool {
+	for _, need := range step.Needs {
+		if !st.settled(need) {
+			return false
+		}
+	}
+	return true
+}
+
+// FailureKind is what happens to a run when a step fails.
+type FailureKind string
+
+const (
+	FailureRetry    FailureKind = "retry"
+	FailureSkip     FailureKind = "skip"
+	FailureEscalate FailureKind = "escalate"
+	FailureGoto     FailureKind = "goto"
+)
+
+// FailureResult is the outcome of OnStepFailed.
+type FailureResult struct {
+	Kind FailureKind
+
+	// Attempt is the failed step's failure count, including this one.
+	Attempt int
+
+	// Target is the step a goto jumped to; Reset lists every step set back
+	// to pending for it (the target and its dependents, in formula order).
+	Target string
+	Reset  []string
+}
+
+// OnStepFailed records a failed attempt of step id and applies the step's
+// policy to st: the step is retried while it has retries left, then its
+// on_failure action runs. A goto resets the target and everything that
+// depends on it; after MaxStepJumps gotos the failure is escalated.
+func (f *Formula) OnStepFailed(st *RunState, id string) FailureResult {
+	s := st.Step(id)
+	s.Attempts++
+	s.StartedAt = time.Time{}
+	res := FailureResult{Kind: FailureEscalate, Attempt: s.Attempts}
+
+	step := f.GetStep(id)
+	if step == nil {
+		s.Status = StepFailed
+		return res
+	}
+	if s.Attempts <= step.Retry {
+		s.Status = StepPending
+		res.Kind = FailureRetry
+		return res
+	}
+
+	action, target := step.FailureAction()
+	switch {
+	case action == OnFailureSkip:
+		s.Status = StepSkipped
+		res.Kind = FailureSkip
+	case action == "goto" && s.Jumps < MaxStepJumps:
+		s.Jumps++
+		res.Kind, res.Target = FailureGoto, target
+		for _, rid := range f.dependentsOf(target) {
+			r := st.Step(rid)
+			r.Status, r.Output, r.StartedAt = StepPending, "", time.Time{}
+			res.Reset = append(res.Reset, rid)
+		}
+	default:
+		s.Status = StepFailed
+	}
+	return res
+}
+
+// dependentsOf returns id and every step that transitively needs it, in
+// formula order.
+func (f *Formula) dependentsOf(id string) []string {
+	hit := map[string]bool{id: true}
+	for changed := true; changed; {
+		changed = false
+		for _, step := range f.Steps {
+			if hit[step.ID] {
+				continue
+			}
+			for _, need := range step.Needs {
+				if hit[need] {
+					hit[step.ID], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	var ids []string
+	for _, step := range f.Steps {
+		if hit[step.ID] {
+			ids = append(ids, step.ID)
+		}
+	}
+	return ids
+}
+
+// transitiveNeeds returns every step id reaches through needs.
+func (f *Formula) transitiveNeeds(id string) map[string]bool {
+	seen := make(map[string]bool)
+	var visit func(string)
+	visit = func(sid string) {
+		step := f.GetStep(sid)
+		if step == nil {
+			return
+		}
+		for _, need := range step.Needs {
+			if !seen[need] {
+				seen[need] = true
+				visit(need)
+			}
+		}
+	}
+	visit(id)
+	return seen
+}
@@ -0,0 +1,242 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const policyFormula = `
+formula = "review"
+type = "workflow"
+version = 1
+
+[vars.mode]
+default = "full"
+
+[[steps]]
+id = "implement"
+title = "Implement"
+
+[[steps]]
+id = "diff"
+title = "Collect changed files"
+needs = ["implement"]
+
+[[steps]]
+id = "security"
+title = "Security review"
+needs = ["diff"]
+when = 'steps.diff.output contains "auth/"'
+parallel = true
+
+[[steps]]
+id = "docs"
+title = "Docs review"
+needs = ["diff"]
+when = 'vars.mode == "full"'
+parallel = true
+
+[[steps]]
+id = "test"
+title = "Run tests"
+needs = ["security", "docs"]
+retry = 2
+timeout = "45m"
+on_failure = "goto:implement"
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["test"]
+on_failure = "skip"
+`
+
+func TestStepPolicy_Parse(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	step := f.GetStep("test")
+	if step.Retry != 2 || step.TimeoutDuration() != 45*time.Minute {
+		t.Errorf("test step retry=%d timeout=%v", step.Retry, step.TimeoutDuration())
+	}
+	if action, target := step.FailureAction(); action != "goto" || target != "implement" {
+		t.Errorf("FailureAction = %s %s, want goto implement", action, target)
+	}
+	if action, _ := f.GetStep("diff").FailureAction(); action != OnFailureEscalate {
+		t.Errorf("default FailureAction = %s, want escalate", action)
+	}
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if step.TimedOut(start, start.Add(44*time.Minute)) || !step.TimedOut(start, start.Add(46*time.Minute)) {
+		t.Error("TimedOut should trip after 45m")
+	}
+	if step.TimedOut(time.Time{}, start) {
+		t.Error("TimedOut with no start time should be false")
+	}
+}
+
+func TestStepPolicy_Validation(t *testing.T) {
+	base := `
+formula = "v"
+type = "workflow"
+[vars.mode]
+default = "x"
+[[steps]]
+id = "a"
+[[steps]]
+id = "b"
+[[steps]]
+id = "c"
+needs = ["a"]
+`
+	tests := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"negative retry", `retry = -1`, "retry must not be negative"},
+		{"bad timeout", `timeout = "soon"`, "invalid timeout"},
+		{"zero timeout", `timeout = "0s"`, "timeout must be positive"},
+		{"bad when", `when = 'vars.mode =='`, "invalid when"},
+		{"undeclared var", `when = 'vars.nope'`, "undeclared var"},
+		{"unrelated step", `when = 'steps.b.output contains "x"'`, `"b" is not a step it needs`},
+		{"bad on_failure", `on_failure = "retry"`, "invalid on_failure"},
+		{"goto unknown", `on_failure = "goto:zzz"`, "unknown step"},
+		{"goto self", `on_failure = "goto:c"`, "cannot jump to itself"},
+		{"goto forward", `on_failure = "goto:b"`, "must jump back"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(base + tt.step + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	ok := base + "retry = 1\ntimeout = \"10m\"\nwhen = 'steps.a.status == \"done\" && vars.mode'\non_failure = \"goto:a\"\n"
+	if _, err := Parse([]byte(ok)); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+}
+
+func TestAdvance_When(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := f.NewRunState(nil)
+	st.Step("implement").Status = StepDone
+	st.Step("diff").Status = StepDone
+	st.Step("diff").Output = "docs/guide.md"
+
+	// security is skipped (no auth/ change), docs runs (mode defaults to full)
+	if ready := f.Advance(st); !reflect.DeepEqual(ready, []string{"docs"}) {
+		t.Errorf("Advance = %v, want [docs]", ready)
+	}
+	if st.Steps["security"].Status != StepSkipped {
+		t.Errorf("security status = %s, want skipped", st.Steps["security"].Status)
+	}
+
+	st.Step("docs").Status = StepDone
+	if ready := f.Advance(st); !reflect.DeepEqual(ready, []string{"test"}) {
+		t.Errorf("Advance after docs = %v, want [test] (skipped steps satisfy needs)", ready)
+	}
+
+	// Vars override defaults; a touched auth/ path runs the security step.
+	st = f.NewRunState(map[string]string{"mode": "quick"})
+	st.Step("implement").Status = StepDone
+	st.Step("diff").Status = StepDone
+	st.Step("diff").Output = "internal/auth/login.go"
+	if ready := f.Advance(st); !reflect.DeepEqual(ready, []string{"security"}) {
+		t.Errorf("Advance = %v, want [security]", ready)
+	}
+}
+
+func TestReadySteps_When(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without outputs, the security condition is false at its default
+	// and the step is skipped; docs runs on the mode default.
+	completed := map[string]bool{"implement": true, "diff": true}
+	if ready := f.ReadySteps(completed); !reflect.DeepEqual(ready, []string{"docs"}) {
+		t.Errorf("ReadySteps = %v, want [docs]", ready)
+	}
+	parallel, sequential := f.ParallelReadySteps(completed)
+	if !reflect.DeepEqual(parallel, []string{"docs"}) || sequential != "" {
+		t.Errorf("ParallelReadySteps = %v, %q", parallel, sequential)
+	}
+	completed["docs"] = true
+	if ready := f.ReadySteps(completed); !reflect.DeepEqual(ready, []string{"test"}) {
+		t.Errorf("ReadySteps = %v, want [test]", ready)
+	}
+}
+
+func TestOnStepFailed(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := f.NewRunState(nil)
+	for _, id := range []string{"implement", "diff", "security", "docs"} {
+		st.Step(id).Status = StepDone
+	}
+	st.Step("diff").Output = "x"
+	st.Step("test").Status = StepRunning
+
+	// retry = 2: two re-runs in place
+	for attempt := 1; attempt <= 2; attempt++ {
+		res := f.OnStepFailed(st, "test")
+		if res.Kind != FailureRetry || res.Attempt != attempt {
+			t.Fatalf("failure %d = %+v, want retry", attempt, res)
+		}
+		if st.Steps["test"].Status != StepPending {
+			t.Fatalf("retried step status = %s", st.Steps["test"].Status)
+		}
+	}
+
+	// Retries used up: jump back to implement, resetting it and its dependents.
+	res := f.OnStepFailed(st, "test")
+	if res.Kind != FailureGoto || res.Target != "implement" {
+		t.Fatalf("third failure = %+v, want goto implement", res)
+	}
+	wantReset := []string{"implement", "diff", "security", "docs", "test", "publish"}
+	if !reflect.DeepEqual(res.Reset, wantReset) {
+		t.Errorf("Reset = %v, want %v", res.Reset, wantReset)
+	}
+	if st.Steps["diff"].Output != "" || st.Steps["implement"].Status != StepPending {
+		t.Error("goto should clear reset steps")
+	}
+	if ready := f.Advance(st); !reflect.DeepEqual(ready, []string{"implement"}) {
+		t.Errorf("Advance after goto = %v, want [implement]", ready)
+	}
+
+	// Jumps are bounded; then the failure escalates.
+	for i := 1; i < MaxStepJumps; i++ {
+		if res := f.OnStepFailed(st, "test"); res.Kind != FailureGoto {
+			t.Fatalf("jump %d = %+v, want goto", i+1, res)
+		}
+	}
+	if res := f.OnStepFailed(st, "test"); res.Kind != FailureEscalate {
+		t.Errorf("after %d jumps = %+v, want escalate", MaxStepJumps, res)
+	}
+	if st.Steps["test"].Status != StepFailed {
+		t.Errorf("escalated step status = %s, want failed", st.Steps["test"].Status)
+	}
+
+	// A failed step blocks its dependents.
+	if ready := f.Advance(st); len(ready) != 1 || ready[0] != "implement" {
+		t.Errorf("Advance = %v, publish must stay blocked", ready)
+	}
+
+	// on_failure = "skip" settles the step so the run carries on.
+	if res := f.OnStepFailed(st, "publish"); res.Kind != FailureSkip || st.Steps["publish"].Status != StepSkipped {
+		t.Errorf("publish failure = %+v, status %s", res, st.Steps["publish"].Status)
+	}
+}
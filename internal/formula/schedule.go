@@ -0,0 +1,46 @@
+package formula
+
+// Schedule simulates a run with the given vars in which every step
+// succeeds, returning the waves of work in execution order as
+// ParallelReadySteps hands them out: a wave is either a group of parallel
+// steps or a single sequential step. Convoy legs and aspects form a single
+// wave. Workflow steps whose `when` condition is false for vars (over the
+// formula defaults) never run and are returned in skipped, in formula order.
+func (f *Formula) Schedule(vars map[string]string) (waves [][]string, skipped []string) {
+	if f.Type != TypeWorkflow {
+		completed := make(map[string]bool)
+		for {
+			wave, _ := f.ParallelReadySteps(completed)
+			if len(wave) == 0 {
+				return waves, nil
+			}
+			for _, id := range wave {
+				completed[id] = true
+			}
+			waves = append(waves, wave)
+		}
+	}
+
+	st := f.NewRunState(vars)
+	for {
+		parallel, sequential := f.groupReady(f.Advance(st))
+		wave := parallel
+		if sequential != "" {
+			wave = []string{sequential}
+		}
+		if len(wave) == 0 {
+			break
+		}
+		for _, id := range wave {
+			st.Step(id).Status = StepDone
+		}
+		waves = append(waves, wave)
+	}
+
+	for _, step := range f.Steps {
+		if st.status(step.ID) != StepDone {
+			skipped = append(skipped, step.ID)
+		}
+	}
+	return waves, skipped
+}
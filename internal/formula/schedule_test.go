@@ -0,0 +1,89 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedule_Workflow(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waves, skipped := f.Schedule(nil)
+
+	// security's condition reads a step output, which is empty in a
+	// simulated run, so only docs runs in the parallel review wave.
+	want := [][]string{{"implement"}, {"diff"}, {"docs"}, {"test"}, {"publish"}}
+	if !reflect.DeepEqual(waves, want) {
+		t.Errorf("waves = %v, want %v", waves, want)
+	}
+	if !reflect.DeepEqual(skipped, []string{"security"}) {
+		t.Errorf("skipped = %v, want [security]", skipped)
+	}
+}
+
+func TestSchedule_Vars(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "release"
+type = "workflow"
+[vars.channel]
+default = "stable"
+[[steps]]
+id = "build"
+[[steps]]
+id = "announce"
+needs = ["build"]
+when = 'vars.channel == "beta"'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waves, skipped := f.Schedule(nil); !reflect.DeepEqual(waves, [][]string{{"build"}}) || !reflect.DeepEqual(skipped, []string{"announce"}) {
+		t.Errorf("Schedule(defaults) = %v, %v", waves, skipped)
+	}
+	if waves, skipped := f.Schedule(map[string]string{"channel": "beta"}); !reflect.DeepEqual(waves, [][]string{{"build"}, {"announce"}}) || skipped != nil {
+		t.Errorf("Schedule(channel=beta) = %v, %v", waves, skipped)
+	}
+}
+
+func TestSchedule_ParallelAndConvoy(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "fan"
+[[steps]]
+id = "a"
+[[steps]]
+id = "b"
+needs = ["a"]
+parallel = true
+[[steps]]
+id = "c"
+needs = ["a"]
+parallel = true
+[[steps]]
+id = "d"
+needs = ["b", "c"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waves, skipped := f.Schedule(nil)
+	if want := [][]string{{"a"}, {"b", "c"}, {"d"}}; !reflect.DeepEqual(waves, want) || skipped != nil {
+		t.Errorf("Schedule = %v, %v; want %v", waves, skipped, want)
+	}
+
+	convoy, err := Parse([]byte(`
+formula = "review"
+type = "convoy"
+[[legs]]
+id = "x"
+[[legs]]
+id = "y"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waves, _ := convoy.Schedule(nil); !reflect.DeepEqual(waves, [][]string{{"x", "y"}}) {
+		t.Errorf("convoy waves = %v", waves)
+	}
+}
@@ -8,7 +8,11 @@
 //   - aspect: Multi-aspect parallel analysis (like convoy but for analysis)
 package formula
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // FormulaType represents the type of formula.
 type FormulaType string
@@ -48,6 +52,84 @@ type Formula struct {
 
 	// Aspect-specific (similar to convoy but for analysis)
 	Aspects []Aspect `toml:"aspects"`
+
+	// Aspect advice, applied to other workflows through compose.aspects
+	Advice    []Advice   `toml:"advice"`
+	Pointcuts []Pointcut `toml:"pointcuts"`
+
+	// Composition, flattened into Steps by Parse (see compose.go)
+	Extends Extends   `toml:"extends"`
+	Include []Include `toml:"include"`
+	Compose *Compose  `toml:"compose"`
+
+	// origins records where each composed step came from, for display.
+	origins map[string]string
+}
+
+// Extends lists the formulas a workflow inherits steps and vars from.
+// It accepts a single name (extends = "shiny") or a list.
+type Extends []string
+
+// UnmarshalTOML decodes extends from a string or an array of strings.
+func (e *Extends) UnmarshalTOML(data any) error {
+	switch val := data.(type) {
+	case string:
+		*e = Extends{val}
+		return nil
+	case []any:
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("extends: expected formula name, got %T", item)
+			}
+			*e = append(*e, s)
+		}
+		return nil
+	default:
+		return fmt.Errorf("extends: expected string or array, got %T", data)
+	}
+}
+
+// Include pulls another workflow's steps into this one. Included step IDs
+// (and the needs and step references between them) get Prefix and a dot,
+// so "review" included with prefix "sec" yields step "sec.review".
+type Include struct {
+	Formula string            `toml:"formula"`
+	Prefix  string            `toml:"prefix"` // Step-ID prefix (default: the included formula's name)
+	Needs   []string          `toml:"needs"`  // Steps the included formula's first steps wait for
+	Vars    map[string]string `toml:"vars"`   // Values for the included formula's vars; may use {{outer_var}}
+}
+
+// Compose applies aspect and expansion formulas to a workflow's steps.
+type Compose struct {
+	Aspects []string `toml:"aspects"` // Aspect formulas whose advice wraps matching steps
+	Expand  []Expand `toml:"expand"`
+}
+
+// Expand replaces a step with the templates of an expansion formula.
+type Expand struct {
+	Target string `toml:"target"` // Step to replace
+	With   string `toml:"with"`   // Expansion formula
+}
+
+// Advice inserts steps around the steps an aspect targets. Target is a
+// glob over step IDs (path.Match syntax); an empty target uses the
+// aspect's pointcuts. Advice step fields may use {step.id}, {step.title}
+// and {step.description}.
+type Advice struct {
+	Target string  `toml:"target"`
+	Around *Around `toml:"around"`
+}
+
+// Around holds the steps advice runs before and after a target step.
+type Around struct {
+	Before []Step `toml:"before"`
+	After  []Step `toml:"after"`
+}
+
+// Pointcut selects the steps an aspect applies to.
+type Pointcut struct {
+	Glob string `toml:"glob"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
@@ -97,9 +179,51 @@ type Step struct {
 	Needs       []string `toml:"needs"`
 	Parallel    bool     `toml:"parallel"`   // If true, this step can run concurrently with other parallel steps that share the same needs
 	Acceptance  string   `toml:"acceptance"` // Exit criteria for this step (used by Ralph loop mode)
+
+	// Execution policy, enforced by gt mol step done.
+	Retry     int    `toml:"retry"`      // Times the step is re-run after failing before on_failure applies
+	Timeout   string `toml:"timeout"`    // Time allowed per attempt (Go duration, e.g. "45m"); overrunning is a failure
+	When      string `toml:"when"`       // Condition over vars and earlier steps; the step is skipped when false (see ParseCondition)
+	OnFailure string `toml:"on_failure"` // "escalate" (default), "skip", or "goto:<step-id>" to re-run from an earlier step
+}
+
+// On-failure actions for a step whose retries are used up.
+const (
+	OnFailureEscalate = "escalate"
+	OnFailureSkip     = "skip"
+	onFailureGoto     = "goto:"
+)
+
+// TimeoutDuration returns the step's timeout, or 0 if it has none.
+func (s *Step) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// TimedOut reports whether an attempt started at started has overrun the
+// step's timeout by now.
+func (s *Step) TimedOut(started, now time.Time) bool {
+	d := s.TimeoutDuration()
+	return d > 0 && !started.IsZero() && now.Sub(started) > d
+}
+
+// FailureAction returns the step's on_failure action and, for a goto, the
+// step to jump to.
+func (s *Step) FailureAction() (action, target string) {
+	switch {
+	case s.OnFailure == "":
+		return OnFailureEscalate, ""
+	case strings.HasPrefix(s.OnFailure, onFailureGoto):
+		return "goto", strings.TrimSpace(strings.TrimPrefix(s.OnFailure, onFailureGoto))
+	}
+	return s.OnFailure, ""
 }
 
 // Template represents a template step in an expansion formula.
+// Fields may use {target}, {target.title} and {target.description}.
 type Template struct {
 	ID          string   `toml:"id"`
 	Title       string   `toml:"title"`
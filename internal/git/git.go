@@ -369,6 +369,12 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// CheckoutBranchAt checks out branch, creating it or resetting it to ref.
+func (g *Git) CheckoutBranchAt(branch, ref string) error {
+	_, err := g.run("checkout", "-B", branch, ref)
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -919,6 +925,36 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// ChangedFiles returns the paths that differ between two refs, including
+// both sides of renames.
+func (g *Git) ChangedFiles(base, head string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", "--no-renames", base, head)
+	if err != nil {
+		return nil, err
+	}
+	return splitFileList(out), nil
+}
+
+// BranchChangedFiles returns the paths branch changes since it forked from
+// base (git diff base...branch), ignoring what base has done since.
+func (g *Git) BranchChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", "--no-renames", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	return splitFileList(out), nil
+}
+
+func splitFileList(out string) []string {
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
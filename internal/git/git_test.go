@@ -1209,6 +1209,72 @@ func TestSubmoduleChanges_NoSubmodules(t *testing.T) {
 	}
 }
 
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+
+	runGit(t, dir, "checkout", "-b", "feature")
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("guide\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "mv", "README.md", "README.txt")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "docs")
+
+	files, err := g.ChangedFiles(base, "feature")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	want := []string{"README.md", "README.txt", "docs/guide.md"}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("ChangedFiles = %v, want %v", files, want)
+	}
+
+	files, err = g.ChangedFiles("feature", "feature")
+	if err != nil || len(files) != 0 {
+		t.Errorf("ChangedFiles(same) = %v, %v; want empty", files, err)
+	}
+}
+
+func TestBranchChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+
+	runGit(t, dir, "checkout", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package f\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "feature")
+
+	// Changes on base after the fork point are not the branch's.
+	runGit(t, dir, "checkout", base)
+	if err := os.WriteFile(filepath.Join(dir, "later.go"), []byte("package l\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "later")
+
+	files, err := g.BranchChangedFiles(base, "feature")
+	if err != nil {
+		t.Fatalf("BranchChangedFiles: %v", err)
+	}
+	if strings.Join(files, ",") != "feature.go" {
+		t.Errorf("BranchChangedFiles = %v, want [feature.go]", files)
+	}
+}
+
 func TestPushSubmoduleCommit(t *testing.T) {
 	parent, subRemote := initTestRepoWithSubmodule(t)
 
@@ -0,0 +1,327 @@
+package headless
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ErrNoServer is returned when the town's headless session server (the
+// daemon) isn't running.
+var ErrNoServer = errors.New("headless session server not running (start it with: gt daemon start)")
+
+// dialTimeout bounds connecting to the session server.
+const dialTimeout = 2 * time.Second
+
+// Client operates headless sessions through the daemon's session server.
+// Its methods mirror *tmux.Tmux, so it can stand in wherever a session
+// backend is expected. Missing sessions are reported as
+// tmux.ErrSessionNotFound so callers need not care which backend they use.
+type Client struct {
+	townRoot string
+}
+
+// NewClient creates a client for a town's headless sessions.
+func NewClient(townRoot string) *Client {
+	return &Client{townRoot: townRoot}
+}
+
+// call sends one request and waits for the response.
+func (c *Client) call(req request) (response, error) {
+	conn, err := net.DialTimeout("unix", SocketPath(c.townRoot), dialTimeout)
+	if err != nil {
+		return response{}, ErrNoServer
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(connTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, fmt.Errorf("headless %s: %w", req.Op, err)
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, fmt.Errorf("headless %s: %w", req.Op, err)
+	}
+	if resp.NotFound {
+		return resp, tmux.ErrSessionNotFound
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("headless %s: %s", req.Op, resp.Error)
+	}
+	return resp, nil
+}
+
+// NewSessionWithCommand starts a session running command in workDir.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	_, err := c.call(request{Op: opNew, Session: name, WorkDir: workDir, Command: command})
+	return err
+}
+
+// HasSession reports whether a session exists. A server that isn't running
+// has no sessions.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: opHas, Session: name})
+	if errors.Is(err, ErrNoServer) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Value == "1", nil
+}
+
+// ListSessions returns the names of all sessions.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: opList})
+	if errors.Is(err, ErrNoServer) {
+		return nil, nil
+	}
+	return resp.Sessions, err
+}
+
+// KillSessionWithProcesses kills a session's process group and removes the
+// session.
+func (c *Client) KillSessionWithProcesses(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name})
+	return err
+}
+
+// KillSession removes a session. Headless sessions own their process
+// group, so this is the same as KillSessionWithProcesses.
+func (c *Client) KillSession(name string) error {
+	return c.KillSessionWithProcesses(name)
+}
+
+// Status returns a session's status.
+func (c *Client) Status(name string) (*SessionStatus, error) {
+	resp, err := c.call(request{Op: opStatus, Session: name})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// GetSessionInfo returns session information in tmux's shape.
+func (c *Client) GetSessionInfo(name string) (*tmux.SessionInfo, error) {
+	st, err := c.Status(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tmux.SessionInfo{
+		Name:     st.Name,
+		Windows:  1,
+		Created:  st.Created.Format("2006-01-02 15:04:05"),
+		Activity: strconv.FormatInt(st.Activity.Unix(), 10),
+	}, nil
+}
+
+// GetSessionActivity returns when the session last produced output.
+func (c *Client) GetSessionActivity(session string) (time.Time, error) {
+	st, err := c.Status(session)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return st.Activity, nil
+}
+
+// CheckSessionHealth classifies a session like tmux.CheckSessionHealth:
+// missing session, dead agent, or no output for longer than maxInactivity
+// (when positive).
+func (c *Client) CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus {
+	alive, err := c.HasSession(session)
+	if err != nil || !alive {
+		return tmux.SessionDead
+	}
+	if !c.IsAgentAlive(session) {
+		return tmux.AgentDead
+	}
+	if maxInactivity > 0 {
+		lastActivity, err := c.GetSessionActivity(session)
+		if err == nil && !lastActivity.IsZero() && time.Since(lastActivity) > maxInactivity {
+			return tmux.AgentHung
+		}
+	}
+	return tmux.SessionHealthy
+}
+
+// GetPaneID returns the session's pane. Headless sessions have exactly one,
+// addressed by the session name.
+func (c *Client) GetPaneID(session string) (string, error) {
+	if _, err := c.Status(session); err != nil {
+		return "", err
+	}
+	return session, nil
+}
+
+// GetPanePID returns the PID of the session's process, or "" once it has
+// exited.
+func (c *Client) GetPanePID(session string) (string, error) {
+	st, err := c.Status(session)
+	if err != nil {
+		return "", err
+	}
+	if st.PID == 0 {
+		return "", nil
+	}
+	return strconv.Itoa(st.PID), nil
+}
+
+// GetPaneCommand returns the name of the session's foreground process.
+func (c *Client) GetPaneCommand(session string) (string, error) {
+	st, err := c.Status(session)
+	if err != nil {
+		return "", err
+	}
+	return st.Current, nil
+}
+
+// CapturePane returns the last lines of the session's output.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: opCapture, Session: session, Lines: lines})
+	return resp.Output, err
+}
+
+// CapturePaneAll returns all the output held for capture.
+func (c *Client) CapturePaneAll(session string) (string, error) {
+	return c.CapturePane(session, 0)
+}
+
+// ClearHistory forgets the output held for capture. The scrollback file is
+// kept.
+func (c *Client) ClearHistory(pane string) error {
+	_, err := c.call(request{Op: opClear, Session: pane})
+	return err
+}
+
+// SendKeysRaw sends a key ("Enter", "C-c", "Down") or literal text without
+// pressing Enter.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	_, err := c.call(request{Op: opSendKeys, Session: session, Keys: keys})
+	return err
+}
+
+// SendKeys types text and presses Enter.
+func (c *Client) SendKeys(session, keys string) error {
+	if _, err := c.call(request{Op: opSendText, Session: session, Text: keys}); err != nil {
+		return err
+	}
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// NudgeSession delivers a message to the agent: the text, a pause so the
+// agent's input handling catches up, then Enter. This mirrors
+// tmux.NudgeSession without tmux's copy-mode and detached-pane workarounds.
+func (c *Client) NudgeSession(session, message string) error {
+	if _, err := c.call(request{Op: opSendText, Session: session, Text: message}); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// AcceptBypassPermissionsWarning dismisses the bypass permissions dialog if
+// the agent shows it; see tmux.AcceptBypassPermissionsWarning.
+func (c *Client) AcceptBypassPermissionsWarning(session string) error {
+	time.Sleep(1 * time.Second)
+	content, err := c.CapturePane(session, 30)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(content, "Bypass Permissions mode") {
+		return nil
+	}
+	if err := c.SendKeysRaw(session, "Down"); err != nil {
+		return err
+	}
+	time.Sleep(200 * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// SetEnvironment sets a session environment variable. Like tmux's
+// set-environment, it applies to processes started afterwards (respawns)
+// and is readable with GetEnvironment.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: opSetEnv, Session: session, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment reads a session environment variable.
+func (c *Client) GetEnvironment(session, key string) (string, error) {
+	resp, err := c.call(request{Op: opGetEnv, Session: session, Key: key})
+	return resp.Value, err
+}
+
+// IsAgentAlive reports whether the session's agent process is running, using
+// the same GT_AGENT-based process detection as tmux.IsAgentAlive.
+func (c *Client) IsAgentAlive(session string) bool {
+	st, err := c.Status(session)
+	if err != nil || !st.Alive || st.PID == 0 {
+		return false
+	}
+	agentName, _ := c.GetEnvironment(session, "GT_AGENT")
+	names := config.GetProcessNames(agentName)
+	for _, name := range names {
+		if st.Current == name {
+			return true
+		}
+	}
+	return tmux.ProcessTreeMatches(strconv.Itoa(st.PID), names)
+}
+
+// WaitForCommand polls until the session's foreground process is not one of
+// excludeCommands (typically shells), like tmux.WaitForCommand.
+func (c *Client) WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if st, err := c.Status(session); err == nil && st.Current != "" {
+			excluded := false
+			for _, exc := range excludeCommands {
+				if st.Current == exc {
+					excluded = true
+					break
+				}
+			}
+			if !excluded {
+				return nil
+			}
+		}
+		time.Sleep(constants.PollInterval)
+	}
+	return fmt.Errorf("timeout waiting for command (still running excluded command)")
+}
+
+// SetRemainOnExit keeps the session after its process exits, so it can be
+// inspected or respawned.
+func (c *Client) SetRemainOnExit(pane string, on bool) error {
+	_, err := c.call(request{Op: opRemain, Session: pane, On: on})
+	return err
+}
+
+// SetAutoRespawnHook restarts the session's command a few seconds after it
+// exits, like the tmux pane-died hook of the same name.
+func (c *Client) SetAutoRespawnHook(session string) error {
+	_, err := c.call(request{Op: opAutoRespawn, Session: session, On: true})
+	return err
+}
+
+// KillPaneProcesses kills the session's process but keeps the session for a
+// RespawnPane.
+func (c *Client) KillPaneProcesses(pane string) error {
+	_, err := c.call(request{Op: opKillPane, Session: pane})
+	return err
+}
+
+// RespawnPane restarts the session with command, killing any process still
+// running.
+func (c *Client) RespawnPane(pane, command string) error {
+	_, err := c.call(request{Op: opRespawnPane, Session: pane, Command: command})
+	return err
+}
@@ -0,0 +1,47 @@
+package headless
+
+import "strings"
+
+// namedKeys maps tmux send-keys key names to the bytes a terminal sends.
+var namedKeys = map[string]string{
+	"Enter":    "\r",
+	"Escape":   "\x1b",
+	"Tab":      "\t",
+	"BTab":     "\x1b[Z",
+	"BSpace":   "\x7f",
+	"Space":    " ",
+	"Up":       "\x1b[A",
+	"Down":     "\x1b[B",
+	"Right":    "\x1b[C",
+	"Left":     "\x1b[D",
+	"Home":     "\x1b[H",
+	"End":      "\x1b[F",
+	"PageUp":   "\x1b[5~",
+	"PPage":    "\x1b[5~",
+	"PageDown": "\x1b[6~",
+	"NPage":    "\x1b[6~",
+	"DC":       "\x1b[3~",
+	"Delete":   "\x1b[3~",
+}
+
+// keyBytes translates a tmux key name ("Enter", "C-c", "M-x", "Down") into
+// the bytes to write to the PTY. Like tmux send-keys, anything that isn't a
+// key name is sent as literal text.
+func keyBytes(keys string) []byte {
+	if seq, ok := namedKeys[keys]; ok {
+		return []byte(seq)
+	}
+	if rest, ok := strings.CutPrefix(keys, "C-"); ok && len(rest) == 1 {
+		c := rest[0]
+		switch {
+		case c >= 'a' && c <= 'z':
+			return []byte{c - 'a' + 1}
+		case c >= '@' && c <= '_': // C-@, C-A..C-Z, C-[, C-\, C-], C-^, C-_
+			return []byte{c - '@'}
+		}
+	}
+	if rest, ok := strings.CutPrefix(keys, "M-"); ok && rest != "" {
+		return append([]byte{0x1b}, keyBytes(rest)...)
+	}
+	return []byte(keys)
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package headless
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// signalGroup signals a session's process group. The PTY makes the process
+// a session leader, so its PID is also its process group ID.
+func signalGroup(pid int, force bool) {
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	if err := syscall.Kill(-pid, sig); err != nil {
+		_ = syscall.Kill(pid, sig)
+	}
+}
+
+// foregroundCommand returns the name of the process in the foreground of the
+// terminal that pid leads, like tmux's #{pane_current_command}.
+func foregroundCommand(pid int) string {
+	fg := pid
+	if out, err := exec.Command("ps", "-o", "tpgid=", "-p", strconv.Itoa(pid)).Output(); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil && n > 0 {
+			fg = n
+		}
+	}
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(fg)).Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package headless
+
+import "os"
+
+// signalGroup kills the session's process. Windows has no process groups to
+// signal, and PTYs aren't supported there anyway.
+func signalGroup(pid int, _ bool) {
+	if p, err := os.FindProcess(pid); err == nil {
+		_ = p.Kill()
+	}
+}
+
+// foregroundCommand is not available on Windows.
+func foregroundCommand(int) string {
+	return ""
+}
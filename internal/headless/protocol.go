@@ -0,0 +1,92 @@
+// Package headless hosts agent sessions on pseudo-terminals owned by the
+// daemon, for hosts where tmux isn't installed or allowed (CI containers,
+// locked-down servers).
+//
+// The daemon runs a Server on a unix socket in <town>/daemon/. gt commands
+// talk to it through a Client, which implements the same session operations
+// as *tmux.Tmux (start, kill, capture, send-keys, environment, liveness).
+// Each session's output is appended to a scrollback file under
+// <town>/daemon/sessions/ so it survives the session and can be inspected
+// after a crash.
+//
+// Headless sessions live as long as the daemon: stopping the daemon stops
+// them. There is no terminal emulation; captures are the raw output with
+// escape sequences stripped, which is enough for prompt and dialog detection.
+package headless
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Request operations.
+const (
+	opNew         = "new"
+	opHas         = "has"
+	opList        = "list"
+	opKill        = "kill"
+	opStatus      = "status"
+	opCapture     = "capture"
+	opClear       = "clear"
+	opSendKeys    = "send-keys"
+	opSendText    = "send-text"
+	opSetEnv      = "setenv"
+	opGetEnv      = "getenv"
+	opRemain      = "remain-on-exit"
+	opAutoRespawn = "auto-respawn"
+	opKillPane    = "kill-pane"
+	opRespawnPane = "respawn-pane"
+)
+
+// request is one call from a Client to the Server, sent as a JSON line.
+type request struct {
+	Op      string `json:"op"`
+	Session string `json:"session,omitempty"`
+	WorkDir string `json:"work_dir,omitempty"`
+	Command string `json:"command,omitempty"`
+	Keys    string `json:"keys,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Lines   int    `json:"lines,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Value   string `json:"value,omitempty"`
+	On      bool   `json:"on,omitempty"`
+}
+
+// response is the Server's reply to a request.
+type response struct {
+	Error    string         `json:"error,omitempty"`
+	NotFound bool           `json:"not_found,omitempty"`
+	OK       bool           `json:"ok,omitempty"`
+	Sessions []string       `json:"sessions,omitempty"`
+	Output   string         `json:"output,omitempty"`
+	Value    string         `json:"value,omitempty"`
+	Status   *SessionStatus `json:"status,omitempty"`
+}
+
+// SessionStatus describes a headless session.
+type SessionStatus struct {
+	Name     string    `json:"name"`
+	WorkDir  string    `json:"work_dir"`
+	Command  string    `json:"command"`           // Startup command
+	Current  string    `json:"current,omitempty"` // Foreground process name, like tmux's pane_current_command
+	PID      int       `json:"pid,omitempty"`     // 0 once the process has exited
+	Alive    bool      `json:"alive"`
+	Created  time.Time `json:"created"`
+	Activity time.Time `json:"activity"`
+	Restarts int       `json:"restarts,omitempty"`
+}
+
+// SocketPath returns the path of the headless session server's socket.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "sessions.sock")
+}
+
+// ScrollbackDir returns the directory holding session scrollback files.
+func ScrollbackDir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "sessions")
+}
+
+// ScrollbackPath returns the scrollback file for a session.
+func ScrollbackPath(townRoot, session string) string {
+	return filepath.Join(ScrollbackDir(townRoot), session+".log")
+}
@@ -0,0 +1,276 @@
+package headless
+
+import (
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+const (
+	// maxScrollback is the size at which a scrollback file is rotated to
+	// <session>.log.1, so a chatty agent can't fill the disk.
+	maxScrollback = 8 << 20
+
+	// tailSize is how much recent output is kept in memory for captures.
+	tailSize = 256 << 10
+
+	// respawnDelay debounces auto-respawn, like the sleep in tmux's
+	// pane-died hook (see tmux.SetAutoRespawnHook).
+	respawnDelay = 3 * time.Second
+
+	// killGrace is how long a killed process gets between SIGTERM and SIGKILL.
+	killGrace = 2 * time.Second
+)
+
+// Terminal size for headless sessions. Agents lay out their TUI for it, so
+// it matches a typical wide tmux window.
+const (
+	ptyRows = 50
+	ptyCols = 200
+)
+
+// ptySession is one headless session: a command running on a PTY, its
+// environment and its output.
+type ptySession struct {
+	name    string
+	workDir string
+	logPath string
+
+	mu           sync.Mutex
+	command      string
+	env          map[string]string
+	created      time.Time
+	activity     time.Time
+	cmd          *exec.Cmd
+	pty          *os.File
+	log          *os.File
+	logSize      int64
+	tail         []byte
+	alive        bool
+	remainOnExit bool
+	autoRespawn  bool
+	killed       bool // Session is being torn down
+	paneKilled   bool // Process killed on request; keep the session for a respawn
+	restarts     int
+	exited       chan struct{} // Closed when the current process exits
+}
+
+func newPTYSession(name, workDir, command, logPath string) *ptySession {
+	now := time.Now()
+	return &ptySession{
+		name:     name,
+		workDir:  workDir,
+		command:  command,
+		logPath:  logPath,
+		env:      make(map[string]string),
+		created:  now,
+		activity: now,
+	}
+}
+
+// spawn starts the session's command on a new PTY. Caller holds s.mu.
+func (s *ptySession) spawn(onExit func(*ptySession)) error {
+	if s.log == nil {
+		f, err := os.OpenFile(s.logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		info, _ := f.Stat()
+		if info != nil {
+			s.logSize = info.Size()
+		}
+		s.log = f
+	}
+
+	cmd := exec.Command(shell(), "-c", s.command) //nolint:gosec // G204: command is the agent startup command
+	cmd.Dir = s.workDir
+	cmd.Env = s.environ()
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: ptyRows, Cols: ptyCols})
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	s.cmd = cmd
+	s.pty = f
+	s.alive = true
+	s.paneKilled = false
+	s.exited = exited
+	go s.pump(cmd, f, exited, onExit)
+	return nil
+}
+
+// pump copies the process's output into the scrollback until it exits.
+func (s *ptySession) pump(cmd *exec.Cmd, f *os.File, exited chan struct{}, onExit func(*ptySession)) {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			s.output(buf[:n])
+		}
+		if err != nil {
+			break // EIO once the process (and its children) close the PTY
+		}
+	}
+	_ = cmd.Wait()
+
+	s.mu.Lock()
+	_ = f.Close()
+	if s.cmd == cmd {
+		s.alive = false
+		s.pty = nil
+	}
+	s.mu.Unlock()
+	// Settle the session's fate before waking stop(), so a respawn that
+	// follows a stop never races the exit handling of the old process.
+	onExit(s)
+	close(exited)
+}
+
+// output records process output in the scrollback file and the capture tail.
+func (s *ptySession) output(b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.activity = time.Now()
+	s.tail = append(s.tail, b...)
+	if len(s.tail) > tailSize {
+		s.tail = append([]byte(nil), s.tail[len(s.tail)-tailSize:]...)
+	}
+
+	if s.log == nil {
+		return
+	}
+	if s.logSize+int64(len(b)) > maxScrollback {
+		_ = s.log.Close()
+		_ = os.Rename(s.logPath, s.logPath+".1")
+		f, err := os.OpenFile(s.logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			s.log = nil
+			return
+		}
+		s.log = f
+		s.logSize = 0
+	}
+	n, _ := s.log.Write(b)
+	s.logSize += int64(n)
+}
+
+// environ is the process environment: the daemon's, minus anything that
+// would make the agent think it's inside tmux, plus the session's own.
+func (s *ptySession) environ() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "TMUX=") || strings.HasPrefix(kv, "TMUX_PANE=") || strings.HasPrefix(kv, "TERM=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env, "TERM=xterm-256color")
+	keys := make([]string, 0, len(s.env))
+	for k := range s.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+s.env[k])
+	}
+	return env
+}
+
+// write sends input to the process.
+func (s *ptySession) write(b []byte) error {
+	s.mu.Lock()
+	f := s.pty
+	s.mu.Unlock()
+	if f == nil {
+		return errNotRunning
+	}
+	_, err := f.Write(b)
+	return err
+}
+
+// stop kills the current process (and its process group), waiting for it to
+// exit. keep marks the session to stay around afterwards for a respawn.
+func (s *ptySession) stop(keep bool) {
+	s.mu.Lock()
+	if keep {
+		s.paneKilled = true
+	} else {
+		s.killed = true
+	}
+	cmd, exited, alive := s.cmd, s.exited, s.alive
+	s.mu.Unlock()
+	if !alive || cmd == nil || cmd.Process == nil {
+		return
+	}
+
+	signalGroup(cmd.Process.Pid, false)
+	select {
+	case <-exited:
+		return
+	case <-time.After(killGrace):
+	}
+	signalGroup(cmd.Process.Pid, true)
+	select {
+	case <-exited:
+	case <-time.After(killGrace):
+	}
+}
+
+// close releases the scrollback file.
+func (s *ptySession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.log != nil {
+		_ = s.log.Close()
+		s.log = nil
+	}
+}
+
+// capture renders the last lines of output; lines <= 0 means all of the
+// output still held in memory.
+func (s *ptySession) capture(lines int) string {
+	s.mu.Lock()
+	out := renderLines(s.tail)
+	s.mu.Unlock()
+	if lines > 0 && len(out) > lines {
+		out = out[len(out)-lines:]
+	}
+	return strings.Join(out, "\n")
+}
+
+func (s *ptySession) status() *SessionStatus {
+	s.mu.Lock()
+	st := &SessionStatus{
+		Name:     s.name,
+		WorkDir:  s.workDir,
+		Command:  s.command,
+		Alive:    s.alive,
+		Created:  s.created,
+		Activity: s.activity,
+		Restarts: s.restarts,
+	}
+	if s.alive && s.cmd != nil && s.cmd.Process != nil {
+		st.PID = s.cmd.Process.Pid
+	}
+	s.mu.Unlock()
+	if st.PID != 0 {
+		st.Current = foregroundCommand(st.PID)
+	}
+	return st
+}
+
+// shell returns the shell that runs session commands. Like tmux's
+// default-shell, it is the user's $SHELL.
+func shell() string {
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return sh
+	}
+	return "/bin/sh"
+}
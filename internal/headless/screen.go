@@ -0,0 +1,114 @@
+package headless
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// renderLines turns raw terminal output into text lines. It is not a
+// terminal emulator: escape sequences are dropped, carriage returns rewind to
+// the start of the line and overwrite it, and erase-line truncates. That is
+// enough for the prompt, dialog and rate-limit checks run against captures.
+// Trailing blank lines are trimmed, as tmux capture-pane does.
+func renderLines(b []byte) []string {
+	var lines []string
+	var line []rune
+	col := 0
+
+	put := func(r rune) {
+		if col < len(line) {
+			line[col] = r
+		} else {
+			for len(line) < col {
+				line = append(line, ' ')
+			}
+			line = append(line, r)
+		}
+		col++
+	}
+	flush := func() {
+		lines = append(lines, strings.TrimRight(string(line), " "))
+		line = line[:0]
+		col = 0
+	}
+
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == 0x1b: // ESC
+			n := escapeLen(b[i:])
+			if seq := string(b[i : i+n]); seq == "\x1b[K" || seq == "\x1b[0K" {
+				line = line[:min(col, len(line))] // Erase to end of line
+			}
+			i += n
+			continue
+		case c == '\n':
+			flush()
+		case c == '\r':
+			col = 0
+		case c == '\b':
+			if col > 0 {
+				col--
+			}
+		case c == '\t':
+			for {
+				put(' ')
+				if col%8 == 0 {
+					break
+				}
+			}
+		case c < 0x20 || c == 0x7f:
+			// Other control characters (bell, shift in/out) don't print.
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r != utf8.RuneError || size > 1 {
+				put(r)
+			}
+			i += size
+			continue
+		}
+		i++
+	}
+	if len(line) > 0 {
+		flush()
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// escapeLen returns the length of the escape sequence at the start of b,
+// which begins with ESC. Incomplete sequences consume the rest of b.
+func escapeLen(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+	switch b[1] {
+	case '[': // CSI: parameters, then a final byte in 0x40-0x7e
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	case ']', 'P', '_', '^': // OSC, DCS, APC, PM: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(b); i++ {
+			if b[i] == 0x07 {
+				return i + 1
+			}
+			if b[i] == 0x1b && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(b)
+	case '(', ')', '*', '+', '#', '%': // Charset selection and friends take one more byte
+		if len(b) < 3 {
+			return len(b)
+		}
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,56 @@
+package headless
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"plain", "hello\nworld\n", []string{"hello", "world"}},
+		{"crlf", "one\r\ntwo\r\n", []string{"one", "two"}},
+		{"colors", "\x1b[1;32mok\x1b[0m done", []string{"ok done"}},
+		{"carriage return overwrites", "50%\r100%", []string{"100%"}},
+		{"erase line", "spinner |\r\x1b[Kready", []string{"ready"}},
+		{"osc title", "\x1b]0;claude\x07prompt>", []string{"prompt>"}},
+		{"charset", "\x1b(Bbox", []string{"box"}},
+		{"backspace", "abd\bc", []string{"abc"}},
+		{"tab", "a\tb", []string{"a       b"}},
+		{"trailing blank lines", "x\n\n\n", []string{"x"}},
+		{"utf8", "❯ ready", []string{"❯ ready"}},
+		{"incomplete escape", "text\x1b[3", []string{"text"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLines([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderLines(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	tests := []struct {
+		keys string
+		want string
+	}{
+		{"Enter", "\r"},
+		{"Escape", "\x1b"},
+		{"Down", "\x1b[B"},
+		{"C-c", "\x03"},
+		{"C-[", "\x1b"},
+		{"M-x", "\x1bx"},
+		{"M-Enter", "\x1b\r"},
+		{"hello", "hello"},
+		{"C-", "C-"},
+	}
+	for _, tt := range tests {
+		if got := string(keyBytes(tt.keys)); got != tt.want {
+			t.Errorf("keyBytes(%q) = %q, want %q", tt.keys, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	errNotRunning = errors.New("session process is not running")
+
+	// validSessionName matches tmux's rule; names also become file names.
+	validSessionName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// connTimeout bounds a single request/response exchange.
+const connTimeout = 30 * time.Second
+
+// Server hosts headless sessions for a town. The daemon runs one when the
+// town's session backend is "pty".
+type Server struct {
+	townRoot string
+	logf     func(format string, args ...interface{})
+
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+	listener net.Listener
+	stopped  bool
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a headless session server for a town.
+func NewServer(townRoot string, logf func(format string, args ...interface{})) *Server {
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+	return &Server{
+		townRoot: townRoot,
+		logf:     logf,
+		sessions: make(map[string]*ptySession),
+	}
+}
+
+// Start listens on the town's session socket and serves requests in the
+// background until Stop.
+func (s *Server) Start() error {
+	if err := os.MkdirAll(ScrollbackDir(s.townRoot), 0700); err != nil {
+		return fmt.Errorf("creating scrollback directory: %w", err)
+	}
+	sock := SocketPath(s.townRoot)
+	// A socket left behind by a crashed daemon would make Listen fail. The
+	// daemon lock guarantees no other server owns it.
+	_ = os.Remove(sock)
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", sock, err)
+	}
+	_ = os.Chmod(sock, 0600)
+
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.serve(l)
+	return nil
+}
+
+// Stop stops serving and kills every session.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	l := s.listener
+	sessions := make([]*ptySession, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.sessions = make(map[string]*ptySession)
+	s.mu.Unlock()
+
+	if l != nil {
+		_ = l.Close()
+	}
+	s.wg.Wait()
+
+	var wg sync.WaitGroup
+	for _, sess := range sessions {
+		wg.Add(1)
+		go func(sess *ptySession) {
+			defer wg.Done()
+			sess.stop(false)
+			sess.close()
+		}(sess)
+	}
+	wg.Wait()
+	_ = os.Remove(SocketPath(s.townRoot))
+}
+
+func (s *Server) serve(l net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return // Listener closed by Stop
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(connTimeout))
+
+	var req request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: fmt.Sprintf("bad request: %v", err)})
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.handle(req))
+}
+
+// handle executes one request.
+func (s *Server) handle(req request) response {
+	switch req.Op {
+	case opList:
+		return response{OK: true, Sessions: s.names()}
+	case opNew:
+		if err := s.create(req.Session, req.WorkDir, req.Command); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{OK: true}
+	}
+
+	sess := s.lookup(req.Session)
+	if sess == nil {
+		if req.Op == opHas {
+			return response{OK: true}
+		}
+		return response{NotFound: true, Error: fmt.Sprintf("session not found: %s", req.Session)}
+	}
+
+	switch req.Op {
+	case opHas:
+		return response{OK: true, Value: "1"}
+	case opStatus:
+		return response{OK: true, Status: sess.status()}
+	case opKill:
+		s.remove(sess)
+		sess.stop(false)
+		sess.close()
+		return response{OK: true}
+	case opKillPane:
+		sess.stop(true)
+		return response{OK: true}
+	case opRespawnPane:
+		return s.respawnPane(sess, req.Command)
+	case opCapture:
+		return response{OK: true, Output: sess.capture(req.Lines)}
+	case opClear:
+		sess.mu.Lock()
+		sess.tail = nil
+		sess.mu.Unlock()
+		return response{OK: true}
+	case opSendKeys:
+		return writeResponse(sess.write(keyBytes(req.Keys)))
+	case opSendText:
+		return writeResponse(sess.write([]byte(req.Text)))
+	case opSetEnv:
+		sess.mu.Lock()
+		sess.env[req.Key] = req.Value
+		sess.mu.Unlock()
+		return response{OK: true}
+	case opGetEnv:
+		sess.mu.Lock()
+		v, ok := sess.env[req.Key]
+		sess.mu.Unlock()
+		if !ok {
+			return response{Error: fmt.Sprintf("unknown variable: %s", req.Key)}
+		}
+		return response{OK: true, Value: v}
+	case opRemain:
+		sess.mu.Lock()
+		sess.remainOnExit = req.On
+		sess.mu.Unlock()
+		return response{OK: true}
+	case opAutoRespawn:
+		sess.mu.Lock()
+		sess.autoRespawn = req.On
+		sess.remainOnExit = sess.remainOnExit || req.On
+		sess.mu.Unlock()
+		return response{OK: true}
+	}
+	return response{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+}
+
+func writeResponse(err error) response {
+	if err != nil {
+		return response{Error: err.Error()}
+	}
+	return response{OK: true}
+}
+
+// create starts a new session running command in workDir.
+func (s *Server) create(name, workDir, command string) error {
+	if !validSessionName.MatchString(name) {
+		return fmt.Errorf("invalid session name %q: must match %s", name, validSessionName)
+	}
+	if command == "" {
+		command = shell()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return errors.New("session server is shutting down")
+	}
+	if _, exists := s.sessions[name]; exists {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+
+	sess := newPTYSession(name, workDir, command, ScrollbackPath(s.townRoot, name))
+	sess.mu.Lock()
+	err := sess.spawn(s.exited)
+	sess.mu.Unlock()
+	if err != nil {
+		sess.close()
+		return fmt.Errorf("starting session %s: %w", name, err)
+	}
+	s.sessions[name] = sess
+	s.logf("headless: started session %s in %s", name, workDir)
+	return nil
+}
+
+// exited runs when a session's process exits. Like a tmux session, the
+// session goes away unless remain-on-exit is set, and comes back after a
+// short delay if auto-respawn is set.
+func (s *Server) exited(sess *ptySession) {
+	sess.mu.Lock()
+	killed, paneKilled := sess.killed, sess.paneKilled
+	respawn := sess.autoRespawn && !killed && !paneKilled
+	remain := sess.remainOnExit || paneKilled
+	sess.mu.Unlock()
+	if killed {
+		return
+	}
+
+	if respawn {
+		s.logf("headless: session %s exited, respawning in %v", sess.name, respawnDelay)
+		time.AfterFunc(respawnDelay, func() {
+			if s.lookup(sess.name) != sess {
+				return // Killed or replaced meanwhile
+			}
+			sess.mu.Lock()
+			defer sess.mu.Unlock()
+			if sess.killed || sess.alive {
+				return
+			}
+			sess.restarts++
+			if err := sess.spawn(s.exited); err != nil {
+				s.logf("headless: respawning session %s: %v", sess.name, err)
+			}
+		})
+		return
+	}
+	if !remain {
+		s.logf("headless: session %s exited", sess.name)
+		s.remove(sess)
+		sess.close()
+	}
+}
+
+// respawnPane restarts a session's process, optionally with a new command,
+// like tmux respawn-pane -k.
+func (s *Server) respawnPane(sess *ptySession, command string) response {
+	sess.stop(true)
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if command != "" {
+		sess.command = command
+	}
+	sess.restarts++
+	if err := sess.spawn(s.exited); err != nil {
+		return response{Error: err.Error()}
+	}
+	return response{OK: true}
+}
+
+func (s *Server) lookup(name string) *ptySession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[name]
+}
+
+func (s *Server) remove(sess *ptySession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+}
+
+func (s *Server) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsServing reports whether a headless session server is listening for the
+// town.
+func IsServing(townRoot string) bool {
+	conn, err := net.DialTimeout("unix", SocketPath(townRoot), time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
@@ -0,0 +1,150 @@
+//go:build !windows
+
+package headless
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// startServer runs a session server for a temporary town. Unix socket paths
+// are limited to ~100 bytes, so the town lives under a short temp dir.
+func startServer(t *testing.T) *Client {
+	t.Helper()
+	townRoot, err := os.MkdirTemp("", "gt-hl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(townRoot) })
+	t.Setenv("SHELL", "/bin/sh")
+
+	srv := NewServer(townRoot, t.Logf)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(srv.Stop)
+	return NewClient(townRoot)
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestClientWithoutServer(t *testing.T) {
+	c := NewClient(t.TempDir())
+
+	has, err := c.HasSession("gt-x")
+	if err != nil || has {
+		t.Errorf("HasSession = %v, %v; want false, nil", has, err)
+	}
+	if err := c.NewSessionWithCommand("gt-x", t.TempDir(), "true"); !errors.Is(err, ErrNoServer) {
+		t.Errorf("NewSessionWithCommand err = %v, want ErrNoServer", err)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	c := startServer(t)
+	workDir := t.TempDir()
+
+	if err := c.NewSessionWithCommand("gt-test", workDir, "cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.NewSessionWithCommand("gt-test", workDir, "cat"); err == nil {
+		t.Error("expected error creating a duplicate session")
+	}
+	if err := c.NewSessionWithCommand("bad name", workDir, "cat"); err == nil {
+		t.Error("expected error for an invalid session name")
+	}
+
+	has, err := c.HasSession("gt-test")
+	if err != nil || !has {
+		t.Fatalf("HasSession = %v, %v; want true", has, err)
+	}
+	names, err := c.ListSessions()
+	if err != nil || len(names) != 1 || names[0] != "gt-test" {
+		t.Errorf("ListSessions = %v, %v; want [gt-test]", names, err)
+	}
+	if pid, err := c.GetPanePID("gt-test"); err != nil || pid == "" {
+		t.Errorf("GetPanePID = %q, %v; want a pid", pid, err)
+	}
+
+	if err := c.SendKeys("gt-test", "hello headless"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitFor(t, "echoed output", func() bool {
+		out, _ := c.CapturePane("gt-test", 10)
+		return strings.Count(out, "hello headless") >= 2 // Terminal echo + cat
+	})
+
+	if err := c.SetEnvironment("gt-test", "GT_ROLE", "polecat"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	if v, err := c.GetEnvironment("gt-test", "GT_ROLE"); err != nil || v != "polecat" {
+		t.Errorf("GetEnvironment = %q, %v; want polecat", v, err)
+	}
+	if _, err := c.GetEnvironment("gt-test", "NOPE"); err == nil {
+		t.Error("expected error reading an unset variable")
+	}
+
+	if err := c.KillSessionWithProcesses("gt-test"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	if has, _ := c.HasSession("gt-test"); has {
+		t.Error("session still exists after kill")
+	}
+	if _, err := c.CapturePane("gt-test", 10); !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("CapturePane after kill err = %v, want ErrSessionNotFound", err)
+	}
+
+	log, err := os.ReadFile(ScrollbackPath(c.townRoot, "gt-test"))
+	if err != nil {
+		t.Fatalf("reading scrollback: %v", err)
+	}
+	if !strings.Contains(string(log), "hello headless") {
+		t.Errorf("scrollback missing output: %q", log)
+	}
+}
+
+func TestSessionExit(t *testing.T) {
+	c := startServer(t)
+
+	if err := c.NewSessionWithCommand("gt-exit", t.TempDir(), "exit 0"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	waitFor(t, "session removal", func() bool {
+		has, _ := c.HasSession("gt-exit")
+		return !has
+	})
+
+	if err := c.NewSessionWithCommand("gt-remain", t.TempDir(), "sleep 0.2"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.SetRemainOnExit("gt-remain", true); err != nil {
+		t.Fatalf("SetRemainOnExit: %v", err)
+	}
+	waitFor(t, "process exit", func() bool {
+		st, err := c.Status("gt-remain")
+		return err == nil && !st.Alive
+	})
+	if err := c.RespawnPane("gt-remain", "sleep 30"); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+	st, err := c.Status("gt-remain")
+	if err != nil || !st.Alive || st.Restarts != 1 || st.Command != "sleep 30" {
+		t.Errorf("Status after respawn = %+v, %v", st, err)
+	}
+}
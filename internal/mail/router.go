@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -11,8 +12,10 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -37,7 +40,7 @@ const DefaultIdleNotifyTimeout = 1 * time.Second
 type Router struct {
 	workDir  string // fallback directory to run bd commands in
 	townRoot string // town root directory (e.g., ~/gt)
-	tmux     *tmux.Tmux
+	sessions session.SessionBackend
 
 	// IdleNotifyTimeout controls how long to wait for a session to become
 	// idle before falling back to a queued nudge. Zero uses the default.
@@ -56,7 +59,7 @@ func NewRouter(workDir string) *Router {
 	return &Router{
 		workDir:  workDir,
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		sessions: session.NewBackend(townRoot),
 	}
 }
 
@@ -65,7 +68,7 @@ func NewRouterWithTownRoot(workDir, townRoot string) *Router {
 	return &Router{
 		workDir:  workDir,
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		sessions: session.NewBackend(townRoot),
 	}
 }
 
@@ -767,6 +770,18 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	// Mail sent from inside a traced session belongs to that trace.
+	if msg.TraceParent == "" {
+		msg.TraceParent = os.Getenv(telemetry.EnvTraceParent)
+	}
+
+	// Check for machine-qualified address (e.g., "buildbox:gastown/rictus")
+	if remote, rest, err := r.resolveRemote(msg.To); err != nil {
+		return err
+	} else if remote != nil {
+		return r.sendToRemote(remote, rest, msg)
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -796,6 +811,53 @@ func (r *Router) Send(msg *Message) error {
 	return r.sendToSingle(msg)
 }
 
+// resolveRemote returns the remote town for a machine-qualified address.
+// Returns nil when the address is local or no machine registry exists.
+func (r *Router) resolveRemote(address string) (*connection.RemoteTown, string, error) {
+	if r.townRoot == "" || !strings.Contains(address, ":") {
+		return nil, address, nil
+	}
+	registry, err := connection.LoadRegistry(r.townRoot)
+	if err != nil {
+		return nil, address, fmt.Errorf("loading machine registry: %w", err)
+	}
+	return registry.Resolve(address)
+}
+
+// sendToRemote delivers a message to an agent in another town by running
+// "gt mail send" on that machine. The remote town stores the message in its
+// own beads and handles recipient notification.
+func (r *Router) sendToRemote(remote *connection.RemoteTown, to string, msg *Message) error {
+	args := []string{"mail", "send", to,
+		"-s", msg.Subject,
+		"-m", msg.Body,
+		"--priority", fmt.Sprintf("%d", PriorityToBeads(msg.Priority)),
+	}
+	if msg.Type != "" {
+		args = append(args, "--type", string(msg.Type))
+	}
+	if msg.ReplyTo != "" {
+		args = append(args, "--reply-to", msg.ReplyTo)
+	}
+	if msg.Pinned {
+		args = append(args, "--pinned")
+	}
+	if !msg.Wisp {
+		args = append(args, "--permanent")
+	}
+	if msg.SuppressNotify {
+		args = append(args, "--no-notify")
+	}
+	for _, cc := range msg.CC {
+		args = append(args, "--cc", cc)
+	}
+
+	if _, err := remote.Gt(msg.From, args...); err != nil {
+		return fmt.Errorf("sending to %s:%s: %w", remote.Machine.Name, to, err)
+	}
+	return nil
+}
+
 // sendToGroup resolves a @group address and sends individual messages to each member.
 func (r *Router) sendToGroup(msg *Message) error {
 	group := parseGroupAddress(msg.To)
@@ -908,6 +970,9 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.TraceParent != "" {
+		labels = append(labels, "traceparent:"+msg.TraceParent)
+	}
 	// Add CC labels (one per recipient)
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
@@ -1037,6 +1102,9 @@ func (r *Router) sendToQueue(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.TraceParent != "" {
+		labels = append(labels, "traceparent:"+msg.TraceParent)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
@@ -1121,6 +1189,9 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.TraceParent != "" {
+		labels = append(labels, "traceparent:"+msg.TraceParent)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
@@ -1207,6 +1278,9 @@ func (r *Router) sendToChannel(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.TraceParent != "" {
+		labels = append(labels, "traceparent:"+msg.TraceParent)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
@@ -1352,7 +1426,7 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	return NewMailboxFromAddress(address, workDir), nil
 }
 
-// notifyRecipient sends a notification to a recipient's tmux session.
+// notifyRecipient sends a notification to a recipient's session.
 //
 // Notification strategy (idle-aware):
 //  1. If the session is idle (prompt visible), send an immediate nudge.
@@ -1384,38 +1458,45 @@ func (r *Router) notifyRecipient(msg *Message) error {
 	// This handles the ambiguity where canonical addresses (rig/name) don't
 	// distinguish between crew workers (gt-rig-crew-name) and polecats (gt-rig-name).
 	for _, sessionID := range sessionIDs {
-		hasSession, err := r.tmux.HasSession(sessionID)
+		hasSession, err := r.sessions.HasSession(sessionID)
 		if err != nil || !hasSession {
 			continue
 		}
+		t, isTmux := r.sessions.(*tmux.Tmux)
 
 		// Overseer is a human operator - use a visible banner instead of NudgeSession
 		// (which types into Claude's input and would disrupt the human's terminal).
 		if msg.To == "overseer" {
-			return r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject)
+			if !isTmux {
+				return nil
+			}
+			return t.SendNotificationBanner(sessionID, msg.From, msg.Subject)
 		}
 
 		notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
 
 		// Idle-aware notification: try immediate nudge first, fall back to queue.
-		waitErr := r.tmux.WaitForIdle(sessionID, timeout)
-		if waitErr == nil {
-			// Session is idle → send immediate nudge
-			if err := r.tmux.NudgeSession(sessionID, notification); err == nil {
+		// Idle detection needs tmux; other backends always queue.
+		if isTmux {
+			waitErr := t.WaitForIdle(sessionID, timeout)
+			if waitErr == nil {
+				// Session is idle → send immediate nudge
+				if err := t.NudgeSession(sessionID, notification); err == nil {
+					return nil
+				} else if errors.Is(err, tmux.ErrSessionNotFound) {
+					// Session disappeared between idle check and nudge — try next candidate
+					continue
+				} else if errors.Is(err, tmux.ErrNoServer) {
+					return nil
+				}
+				// NudgeSession failed for non-terminal reason — fall through to queue
+			} else if errors.Is(waitErr, tmux.ErrNoServer) {
+				// No tmux server — no point trying other candidates
 				return nil
-			} else if errors.Is(err, tmux.ErrSessionNotFound) {
-				// Session disappeared between idle check and nudge — try next candidate
+			} else if errors.Is(waitErr, tmux.ErrSessionNotFound) {
+				// Session disappeared — try next candidate
 				continue
-			} else if errors.Is(err, tmux.ErrNoServer) {
-				return nil
 			}
-			// NudgeSession failed for non-terminal reason — fall through to queue
-		} else if errors.Is(waitErr, tmux.ErrNoServer) {
-			// No tmux server — no point trying other candidates
-			return nil
-		} else if errors.Is(waitErr, tmux.ErrSessionNotFound) {
-			// Session disappeared — try next candidate
-			continue
 		}
 
 		// Busy or nudge failed → enqueue for cooperative delivery at the
@@ -1427,7 +1508,7 @@ func (r *Router) notifyRecipient(msg *Message) error {
 			})
 		}
 		// Fallback to direct nudge if town root unavailable
-		return r.tmux.NudgeSession(sessionID, notification)
+		return r.sessions.NudgeSession(sessionID, notification)
 	}
 
 	return nil // No active session found
@@ -1557,4 +1638,3 @@ func AddressToSessionIDs(address string) []string {
 		session.PolecatSessionName(rigPrefix, target), // <prefix>-name
 	}
 }
-
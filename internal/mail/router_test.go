@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -1154,3 +1155,74 @@ func TestAddressToAgentBeadID(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveRemote(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	// No registry: everything is local.
+	remote, rest, err := r.resolveRemote("gastown/rictus")
+	if err != nil || remote != nil || rest != "gastown/rictus" {
+		t.Fatalf("resolveRemote(local) = (%v, %q, %v)", remote, rest, err)
+	}
+
+	registry, err := connection.LoadRegistry(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Add(&connection.Machine{Name: "buildbox", Type: "ssh", Host: "gt@buildbox", TownPath: "/srv/gt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	remote, rest, err = r.resolveRemote("buildbox:gastown/rictus")
+	if err != nil {
+		t.Fatalf("resolveRemote: %v", err)
+	}
+	if remote == nil || remote.Machine.Name != "buildbox" || rest != "gastown/rictus" {
+		t.Errorf("resolveRemote = (%+v, %q)", remote, rest)
+	}
+
+	// Mail prefixes are never mistaken for machines.
+	remote, rest, err = r.resolveRemote("list:oncall")
+	if err != nil || remote != nil || rest != "list:oncall" {
+		t.Errorf("resolveRemote(list:) = (%v, %q, %v)", remote, rest, err)
+	}
+}
+
+func TestSendToRemote(t *testing.T) {
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$GT_ROLE\" > " + argsFile + "\nfor a in \"$@\"; do echo \"$a\" >> " + argsFile + "; done\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	townPath := t.TempDir()
+	remote := &connection.RemoteTown{
+		Machine: &connection.Machine{Name: "buildbox", TownPath: townPath},
+		Conn:    connection.NewLocalConnection(),
+	}
+
+	msg := NewMessage("gastown/Toast", "buildbox:mayor/", "Build done", "All green")
+	msg.Priority = PriorityHigh
+	msg.Type = TypeTask
+	msg.Wisp = false
+	msg.CC = []string{"gastown/witness"}
+
+	r := NewRouterWithTownRoot(townPath, townPath)
+	if err := r.sendToRemote(remote, "mayor/", msg); err != nil {
+		t.Fatalf("sendToRemote: %v", err)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{"gastown/Toast", "mail", "send", "mayor/", "-s", "Build done", "-m", "All green",
+		"--priority", "1", "--type", "task", "--permanent", "--cc", "gastown/witness"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("remote invocation = %q\nwant %q", got, want)
+	}
+}
@@ -282,6 +282,20 @@ func TestBeadsMessageToMessageWithReplyTo(t *testing.T) {
 	}
 }
 
+func TestBeadsMessageToMessageWithTraceParent(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	bm := BeadsMessage{
+		ID:       "hq-traced",
+		Title:    "POLECAT_DONE nux",
+		Assignee: "gastown/witness",
+		Labels:   []string{"from:gastown/nux", "traceparent:" + tp},
+	}
+
+	if got := bm.ToMessage().TraceParent; got != tp {
+		t.Errorf("TraceParent = %q, want %q", got, tp)
+	}
+}
+
 func TestBeadsMessageToMessagePriorities(t *testing.T) {
 	tests := []struct {
 		priority int
@@ -0,0 +1,391 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Collector gathers metrics for one town. It is safe for concurrent scrapes.
+type Collector struct {
+	townRoot string
+
+	// Sources that shell out or dial the network. Tests replace these.
+	listSessions  func() ([]string, error)
+	listMRs       func(rigPath string) ([]*beads.Issue, error)
+	doltReachable func() error
+	now           func() time.Time
+
+	mu   sync.Mutex
+	tail eventTail
+}
+
+// NewCollector creates a collector for the given town root.
+func NewCollector(townRoot string) *Collector {
+	return &Collector{
+		townRoot:     townRoot,
+		listSessions: tmux.NewTmux().ListSessions,
+		listMRs: func(rigPath string) ([]*beads.Issue, error) {
+			return beads.New(rigPath).List(beads.ListOptions{
+				Label:    "gt:merge-request",
+				Status:   "open",
+				Priority: -1,
+			})
+		},
+		doltReachable: func() error { return doltserver.CheckServerReachable(townRoot) },
+		now:           time.Now,
+		tail:          newEventTail(),
+	}
+}
+
+// Collect reads the current town state and returns it as a metric set.
+// A source that cannot be read is reported through gastown_source_up rather
+// than failing the whole scrape.
+func (c *Collector) Collect() *Set {
+	s := NewSet()
+	up := s.Gauge("gastown_source_up", "Whether a metrics source could be read (1) or not (0).")
+	record := func(source string, err error) {
+		v := 1.0
+		if err != nil {
+			v = 0
+		}
+		up.Add(v, "source", source)
+	}
+
+	record("daemon", c.collectDaemon(s))
+	record("restarts", c.collectRestarts(s))
+	record("tmux", c.collectAgents(s))
+	record("merge_queue", c.collectMergeQueue(s))
+	record("events", c.collectEvents(s))
+	record("nudge_queue", c.collectNudges(s))
+	record("quota", c.collectQuota(s))
+	c.collectDolt(s)
+
+	return s
+}
+
+// collectDaemon exports daemon liveness and the counters it persists at each
+// heartbeat. The counters restart from zero when the daemon restarts.
+func (c *Collector) collectDaemon(s *Set) error {
+	running, _, err := daemon.IsRunning(c.townRoot)
+	if err != nil {
+		return err
+	}
+	s.Gauge("gastown_daemon_up", "Whether the town daemon process is running.").Add(boolValue(running))
+
+	state, err := daemon.LoadState(c.townRoot)
+	if err != nil {
+		return err
+	}
+	s.Counter("gastown_daemon_heartbeats_total", "Daemon heartbeats completed since the daemon started.").
+		Add(float64(state.HeartbeatCount))
+	if !state.LastHeartbeat.IsZero() {
+		s.Gauge("gastown_daemon_last_heartbeat_timestamp_seconds", "Unix time of the last completed daemon heartbeat.").
+			Add(float64(state.LastHeartbeat.Unix()))
+	}
+	s.Counter("gastown_daemon_session_deaths_total", "Crashed agent sessions detected by the daemon since it started.").
+		Add(float64(state.SessionDeaths))
+	s.Counter("gastown_daemon_convoy_scans_total", "Stranded convoy scans run by the daemon since it started.").
+		Add(float64(state.ConvoyScans))
+	s.Counter("gastown_daemon_convoy_scan_failures_total", "Stranded convoy scans that failed since the daemon started.").
+		Add(float64(state.ConvoyScanFailures))
+	return nil
+}
+
+// collectRestarts exports the daemon's restart tracker.
+func (c *Collector) collectRestarts(s *Set) error {
+	tracker := daemon.NewRestartTracker(c.townRoot)
+	if err := tracker.Load(); err != nil {
+		return err
+	}
+	restarts := s.Gauge("gastown_agent_restarts", "Restarts in the agent's current backoff window.")
+	looping := s.Gauge("gastown_agent_crash_looping", "Whether the agent is in a crash loop and no longer auto-restarted.")
+	for id, info := range tracker.Agents() {
+		restarts.Add(float64(info.RestartCount), "agent", id)
+		looping.Add(boolValue(!info.CrashLoopSince.IsZero()), "agent", id)
+	}
+	return nil
+}
+
+// collectAgents counts live Gas Town tmux sessions by role.
+func (c *Collector) collectAgents(s *Set) error {
+	alive := s.Gauge("gastown_agents_alive", "Running agent sessions by role.")
+	counts := map[session.Role]int{
+		session.RoleMayor:    0,
+		session.RoleDeacon:   0,
+		session.RoleWitness:  0,
+		session.RoleRefinery: 0,
+		session.RoleCrew:     0,
+		session.RolePolecat:  0,
+	}
+	defer func() {
+		for role, n := range counts {
+			alive.Add(float64(n), "role", string(role))
+		}
+	}()
+
+	registry, err := session.BuildPrefixRegistryFromTown(c.townRoot)
+	if err != nil {
+		return err
+	}
+	sessions, err := c.listSessions()
+	if err != nil {
+		return err
+	}
+	for _, name := range sessions {
+		id, err := session.ParseSessionNameWithRegistry(name, registry)
+		if err != nil {
+			continue // not a Gas Town session
+		}
+		if _, tracked := counts[id.Role]; tracked {
+			counts[id.Role]++
+		}
+	}
+	return nil
+}
+
+// collectMergeQueue exports open MR count and the age of the oldest MR per rig.
+func (c *Collector) collectMergeQueue(s *Set) error {
+	rigs, err := config.LoadRigsConfig(filepath.Join(c.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	depth := s.Gauge("gastown_mq_depth", "Open merge requests waiting in the refinery queue.")
+	oldest := s.Gauge("gastown_mq_oldest_age_seconds", "Age of the oldest open merge request.")
+
+	now := c.now()
+	var firstErr error
+	for _, rigName := range sortedKeys(rigs.Rigs) {
+		issues, err := c.listMRs(filepath.Join(c.townRoot, rigName))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", rigName, err)
+			}
+			continue
+		}
+		var n int
+		var oldestAt time.Time
+		for _, issue := range issues {
+			if issue == nil || issue.Status != "open" {
+				continue
+			}
+			n++
+			if created := parseTime(issue.CreatedAt); !created.IsZero() && (oldestAt.IsZero() || created.Before(oldestAt)) {
+				oldestAt = created
+			}
+		}
+		depth.Add(float64(n), "rig", rigName)
+		age := 0.0
+		if !oldestAt.IsZero() {
+			age = now.Sub(oldestAt).Seconds()
+		}
+		oldest.Add(age, "rig", rigName)
+	}
+	return firstErr
+}
+
+// collectEvents exports counters derived from the town events log.
+func (c *Collector) collectEvents(s *Set) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.tail.update(filepath.Join(c.townRoot, events.EventsFile))
+
+	byType := s.Counter("gastown_events_total", "Events recorded in the town events log, by type.")
+	for typ, n := range c.tail.byType {
+		byType.Add(float64(n), "type", typ)
+	}
+
+	runs := s.Counter("gastown_gate_runs_total", "Refinery quality gate runs by outcome.")
+	durations := s.Summary("gastown_gate_duration_seconds", "Refinery quality gate run time.")
+	type gateID struct{ rig, gate string }
+	totals := make(map[gateID]*gateStats)
+	for key, st := range c.tail.gates {
+		runs.Add(float64(st.count), "rig", key.rig, "gate", key.gate, "result", key.result)
+		id := gateID{key.rig, key.gate}
+		if totals[id] == nil {
+			totals[id] = &gateStats{}
+		}
+		totals[id].count += st.count
+		totals[id].seconds += st.seconds
+	}
+	for id, st := range totals {
+		durations.Observe(st.seconds, st.count, "rig", id.rig, "gate", id.gate)
+	}
+	return err
+}
+
+// collectNudges exports nudge queue depth per session.
+func (c *Collector) collectNudges(s *Set) error {
+	pending, err := nudge.PendingAll(c.townRoot)
+	if err != nil {
+		return err
+	}
+	depth := s.Gauge("gastown_nudge_queue_depth", "Queued nudges waiting for delivery, by session.")
+	for sess, n := range pending {
+		depth.Add(float64(n), "session", sess)
+	}
+	return nil
+}
+
+// collectQuota exports account rate-limit status.
+func (c *Collector) collectQuota(s *Set) error {
+	state, err := quota.NewManager(c.townRoot).Load()
+	if err != nil {
+		return err
+	}
+	byStatus := map[string]int{
+		string(config.QuotaStatusAvailable): 0,
+		string(config.QuotaStatusLimited):   0,
+	}
+	limited := s.Gauge("gastown_quota_account_limited", "Whether the account is currently rate-limited.")
+	for handle, acct := range state.Accounts {
+		status := string(acct.Status)
+		if status == "" {
+			status = string(config.QuotaStatusAvailable)
+		}
+		byStatus[status]++
+		limited.Add(boolValue(acct.Status == config.QuotaStatusLimited), "account", handle)
+	}
+	accounts := s.Gauge("gastown_quota_accounts", "Tracked accounts by quota status.")
+	for status, n := range byStatus {
+		accounts.Add(float64(n), "status", status)
+	}
+	return nil
+}
+
+// collectDolt exports whether the Dolt SQL server accepts connections.
+func (c *Collector) collectDolt(s *Set) {
+	s.Gauge("gastown_dolt_up", "Whether the Dolt SQL server is reachable.").
+		Add(boolValue(c.doltReachable() == nil))
+}
+
+// eventTail incrementally reads the events log so each scrape only parses
+// lines appended since the previous one.
+type eventTail struct {
+	offset int64
+	byType map[string]int64
+	gates  map[gateKey]*gateStats
+}
+
+type gateKey struct{ rig, gate, result string }
+
+type gateStats struct {
+	count   int64
+	seconds float64
+}
+
+func newEventTail() eventTail {
+	return eventTail{
+		byType: make(map[string]int64),
+		gates:  make(map[gateKey]*gateStats),
+	}
+}
+
+func (t *eventTail) update(path string) error {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town events log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < t.offset {
+		// Log was truncated or rotated: start over. Counters reset, which
+		// Prometheus handles as a counter reset.
+		*t = newEventTail()
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// A partial trailing line is re-read on the next scrape.
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		t.offset += int64(len(line))
+		t.record(line)
+	}
+}
+
+func (t *eventTail) record(line []byte) {
+	var ev events.Event
+	if err := json.Unmarshal(line, &ev); err != nil || ev.Type == "" {
+		return
+	}
+	t.byType[ev.Type]++
+
+	if ev.Type != events.TypeGateResult {
+		return
+	}
+	rig, _ := ev.Payload["rig"].(string)
+	gate, _ := ev.Payload["gate"].(string)
+	result, _ := ev.Payload["result"].(string)
+	ms, _ := ev.Payload["duration_ms"].(float64)
+	key := gateKey{rig: rig, gate: gate, result: result}
+	st := t.gates[key]
+	if st == nil {
+		st = &gateStats{}
+		t.gates[key] = st
+	}
+	st.count++
+	st.seconds += ms / 1000
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseTime parses a bead timestamp, returning zero time on error.
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, _ = time.Parse("2006-01-02", s)
+	}
+	return t
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
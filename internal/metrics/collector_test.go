@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestTown lays out the on-disk state the collector reads.
+func newTestTown(t *testing.T) (string, *Collector) {
+	t.Helper()
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	writeFile(t, filepath.Join(town, "mayor", "rigs.json"),
+		`{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/g.git","beads":{"prefix":"gt"}}}}`)
+	writeFile(t, filepath.Join(town, "daemon", "state.json"), fmt.Sprintf(
+		`{"running":true,"pid":1,"last_heartbeat":%q,"heartbeat_count":7,"session_deaths":2,"convoy_scans":30,"convoy_scan_failures":1}`,
+		now.Add(-time.Minute).Format(time.RFC3339)))
+	writeFile(t, filepath.Join(town, "daemon", "restart_state.json"),
+		`{"agents":{"deacon":{"restart_count":5,"crash_loop_since":"2026-03-01T11:00:00Z"},"gastown-witness":{"restart_count":1}}}`)
+	writeFile(t, filepath.Join(town, "mayor", "quota.json"),
+		`{"version":1,"accounts":{"work":{"status":"limited"},"personal":{"status":"available"}}}`)
+	writeFile(t, filepath.Join(town, events.EventsFile),
+		`{"type":"sling","actor":"mayor"}
+{"type":"merged","actor":"gastown/refinery"}
+{"type":"gate_result","payload":{"rig":"gastown","gate":"test","result":"pass","duration_ms":1500}}
+{"type":"gate_result","payload":{"rig":"gastown","gate":"test","result":"fail","duration_ms":500}}
+`)
+	if err := nudge.Enqueue(town, "gt-alpha", nudge.QueuedNudge{Sender: "mayor", Message: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCollector(town)
+	c.now = func() time.Time { return now }
+	c.listSessions = func() ([]string, error) {
+		return []string{"hq-mayor", "hq-deacon", "gt-witness", "gt-refinery", "gt-alpha", "gt-bravo", "gt-crew-joe", "unrelated"}, nil
+	}
+	c.listMRs = func(rigPath string) ([]*beads.Issue, error) {
+		if filepath.Base(rigPath) != "gastown" {
+			t.Errorf("listMRs called with %s", rigPath)
+		}
+		return []*beads.Issue{
+			{ID: "gt-mr1", Status: "open", CreatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+			{ID: "gt-mr2", Status: "open", CreatedAt: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+			{ID: "gt-mr3", Status: "closed", CreatedAt: now.Add(-48 * time.Hour).Format(time.RFC3339)},
+		}, nil
+	}
+	c.doltReachable = func() error { return nil }
+	return town, c
+}
+
+func render(t *testing.T, c *Collector) string {
+	t.Helper()
+	var b strings.Builder
+	if err := c.Collect().Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return b.String()
+}
+
+func TestCollect(t *testing.T) {
+	_, c := newTestTown(t)
+	out := render(t, c)
+
+	for _, want := range []string{
+		`gastown_daemon_up 0`, // state says running, but there is no live PID file
+		`gastown_daemon_heartbeats_total 7`,
+		`gastown_daemon_session_deaths_total 2`,
+		`gastown_daemon_convoy_scans_total 30`,
+		`gastown_daemon_convoy_scan_failures_total 1`,
+		`gastown_agent_restarts{agent="deacon"} 5`,
+		`gastown_agent_crash_looping{agent="deacon"} 1`,
+		`gastown_agent_crash_looping{agent="gastown-witness"} 0`,
+		`gastown_agents_alive{role="mayor"} 1`,
+		`gastown_agents_alive{role="polecat"} 2`,
+		`gastown_agents_alive{role="crew"} 1`,
+		`gastown_agents_alive{role="witness"} 1`,
+		`gastown_mq_depth{rig="gastown"} 2`,
+		`gastown_mq_oldest_age_seconds{rig="gastown"} 7200`,
+		`gastown_events_total{type="merged"} 1`,
+		`gastown_gate_runs_total{rig="gastown",gate="test",result="pass"} 1`,
+		`gastown_gate_runs_total{rig="gastown",gate="test",result="fail"} 1`,
+		`gastown_gate_duration_seconds_sum{rig="gastown",gate="test"} 2`,
+		`gastown_gate_duration_seconds_count{rig="gastown",gate="test"} 2`,
+		`gastown_nudge_queue_depth{session="gt-alpha"} 1`,
+		`gastown_quota_accounts{status="limited"} 1`,
+		`gastown_quota_account_limited{account="work"} 1`,
+		`gastown_dolt_up 1`,
+		`gastown_source_up{source="merge_queue"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestCollectEventsIncremental(t *testing.T) {
+	town, c := newTestTown(t)
+	_ = render(t, c)
+
+	path := filepath.Join(town, events.EventsFile)
+	appendFile(t, path, `{"type":"merged"}`+"\n"+`{"type":"merged"`) // second line incomplete
+	out := render(t, c)
+	if !strings.Contains(out, `gastown_events_total{type="merged"} 2`+"\n") {
+		t.Errorf("expected 2 merged events after append:\n%s", out)
+	}
+
+	appendFile(t, path, `}`+"\n")
+	out = render(t, c)
+	if !strings.Contains(out, `gastown_events_total{type="merged"} 3`+"\n") {
+		t.Errorf("expected partial line to be counted once complete:\n%s", out)
+	}
+
+	// Truncation resets the counters.
+	writeFile(t, path, `{"type":"sling"}`+"\n")
+	out = render(t, c)
+	if strings.Contains(out, `type="merged"`) || !strings.Contains(out, `gastown_events_total{type="sling"} 1`+"\n") {
+		t.Errorf("expected reset after truncation:\n%s", out)
+	}
+}
+
+func TestCollectSourceFailures(t *testing.T) {
+	_, c := newTestTown(t)
+	c.listSessions = func() ([]string, error) { return nil, errors.New("no tmux") }
+	c.listMRs = func(string) ([]*beads.Issue, error) { return nil, errors.New("bd unavailable") }
+	c.doltReachable = func() error { return errors.New("connection refused") }
+
+	out := render(t, c)
+	for _, want := range []string{
+		`gastown_source_up{source="tmux"} 0`,
+		`gastown_source_up{source="merge_queue"} 0`,
+		`gastown_source_up{source="quota"} 1`,
+		`gastown_agents_alive{role="polecat"} 0`,
+		`gastown_dolt_up 0`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package metrics exports town health and throughput in the Prometheus text
+// exposition format.
+//
+// Nothing here keeps its own time series: every scrape reads the state that
+// the daemon, refinery and agents already persist in the town (daemon state,
+// restart tracker, nudge queues, quota state, the events log) and renders it.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Content-Type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types.
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+	TypeSummary = "summary"
+)
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Suffix string   // appended to the family name (e.g., "_sum", "_count")
+	Labels []string // alternating label names and values
+	Value  float64
+}
+
+// Family is a named metric with a type, help text and samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Add appends a sample. labels alternate names and values:
+// Add(3, "role", "polecat").
+func (f *Family) Add(value float64, labels ...string) {
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// Observe appends the _sum and _count samples of a summary.
+func (f *Family) Observe(sum float64, count int64, labels ...string) {
+	f.Samples = append(f.Samples,
+		Sample{Suffix: "_sum", Labels: labels, Value: sum},
+		Sample{Suffix: "_count", Labels: labels, Value: float64(count)},
+	)
+}
+
+// Set is an ordered collection of metric families.
+type Set struct {
+	families []*Family
+	byName   map[string]*Family
+}
+
+// NewSet creates an empty set.
+func NewSet() *Set {
+	return &Set{byName: make(map[string]*Family)}
+}
+
+// Counter returns the counter family with the given name, creating it if needed.
+func (s *Set) Counter(name, help string) *Family {
+	return s.family(name, help, TypeCounter)
+}
+
+// Gauge returns the gauge family with the given name, creating it if needed.
+func (s *Set) Gauge(name, help string) *Family {
+	return s.family(name, help, TypeGauge)
+}
+
+// Summary returns the summary family with the given name, creating it if needed.
+func (s *Set) Summary(name, help string) *Family {
+	return s.family(name, help, TypeSummary)
+}
+
+func (s *Set) family(name, help, typ string) *Family {
+	if f, ok := s.byName[name]; ok {
+		return f
+	}
+	f := &Family{Name: name, Help: help, Type: typ}
+	s.families = append(s.families, f)
+	s.byName[name] = f
+	return f
+}
+
+// Get returns the family with the given name, or nil.
+func (s *Set) Get(name string) *Family {
+	return s.byName[name]
+}
+
+// Write renders the set in the Prometheus text exposition format.
+// Samples within a family are sorted by label values for stable output.
+func (s *Set) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range s.families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+
+		samples := append([]Sample(nil), f.Samples...)
+		sort.SliceStable(samples, func(i, j int) bool {
+			return strings.Join(samples[i].Labels, "\x00") < strings.Join(samples[j].Labels, "\x00")
+		})
+		for _, sm := range samples {
+			bw.WriteString(f.Name)
+			bw.WriteString(sm.Suffix)
+			writeLabels(bw, sm.Labels)
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(sm.Value))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+func writeLabels(w *bufio.Writer, labels []string) {
+	if len(labels) < 2 {
+		return
+	}
+	w.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(labels[i])
+		w.WriteString(`="`)
+		w.WriteString(escapeLabel(labels[i+1]))
+		w.WriteByte('"')
+	}
+	w.WriteByte('}')
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
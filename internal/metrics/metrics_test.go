@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetWrite(t *testing.T) {
+	s := NewSet()
+	g := s.Gauge("gastown_agents_alive", "Running agent sessions by role.")
+	g.Add(3, "role", "polecat")
+	g.Add(1, "role", "mayor")
+	s.Counter("gastown_heartbeats_total", "Heartbeats.\nSince start.").Add(42)
+	s.Summary("gastown_gate_duration_seconds", "Gate run time.").Observe(1.5, 2, "rig", "gastown", "gate", "test")
+	s.Gauge("gastown_weird", "Escaping.").Add(0.25, "path", `a"b\c`)
+
+	var b strings.Builder
+	if err := s.Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := `# HELP gastown_agents_alive Running agent sessions by role.
+# TYPE gastown_agents_alive gauge
+gastown_agents_alive{role="mayor"} 1
+gastown_agents_alive{role="polecat"} 3
+# HELP gastown_heartbeats_total Heartbeats.\nSince start.
+# TYPE gastown_heartbeats_total counter
+gastown_heartbeats_total 42
+# HELP gastown_gate_duration_seconds Gate run time.
+# TYPE gastown_gate_duration_seconds summary
+gastown_gate_duration_seconds_sum{rig="gastown",gate="test"} 1.5
+gastown_gate_duration_seconds_count{rig="gastown",gate="test"} 2
+# HELP gastown_weird Escaping.
+# TYPE gastown_weird gauge
+gastown_weird{path="a\"b\\c"} 0.25
+`
+	if got := b.String(); got != want {
+		t.Errorf("Write output mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestSetFamilyReuse(t *testing.T) {
+	s := NewSet()
+	s.Gauge("x", "help").Add(1, "a", "1")
+	s.Gauge("x", "help").Add(2, "a", "2")
+	if f := s.Get("x"); f == nil || len(f.Samples) != 2 {
+		t.Fatalf("expected one family with two samples, got %+v", f)
+	}
+}
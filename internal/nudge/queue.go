@@ -7,6 +7,10 @@
 //
 // Queue location: <townRoot>/.runtime/nudge_queue/<session>/
 // Each nudge is a JSON file named by timestamp for FIFO ordering.
+//
+// Every nudge also has a receipt in <townRoot>/.runtime/nudge_receipts/<session>/,
+// which outlives the queue file and tracks its delivery state (queued,
+// injected, acknowledged, expired) so senders can see what happened to it.
 package nudge
 
 import (
@@ -50,13 +54,22 @@ const (
 	staleClaimThreshold = 5 * time.Minute
 )
 
-// QueuedNudge represents a nudge message stored in the queue.
+// QueuedNudge represents a nudge message stored in the queue, and its
+// delivery receipt.
 type QueuedNudge struct {
+	ID        string    `json:"id,omitempty"`
+	Session   string    `json:"session,omitempty"`
 	Sender    string    `json:"sender"`
 	Message   string    `json:"message"`
 	Priority  string    `json:"priority"`
 	Timestamp time.Time `json:"timestamp"`
 	ExpiresAt time.Time `json:"expires_at,omitempty"`
+
+	// Delivery tracking (see the State* constants).
+	State      string    `json:"state,omitempty"`
+	InjectedAt time.Time `json:"injected_at,omitempty"`
+	AckedAt    time.Time `json:"acked_at,omitempty"`
+	AckedBy    string    `json:"acked_by,omitempty"`
 }
 
 // queueDir returns the nudge queue directory for a given session.
@@ -90,12 +103,17 @@ func Enqueue(townRoot, session string, nudge QueuedNudge) error {
 		return fmt.Errorf("nudge queue for %s is full (%d/%d pending)", session, pending, MaxQueueDepth)
 	}
 
+	if nudge.ID == "" {
+		nudge.ID = NewID()
+	}
 	if nudge.Timestamp.IsZero() {
 		nudge.Timestamp = time.Now()
 	}
 	if nudge.Priority == "" {
 		nudge.Priority = PriorityNormal
 	}
+	nudge.Session = session
+	nudge.State = StateQueued
 
 	// Set expiry if not already specified by the caller.
 	if nudge.ExpiresAt.IsZero() {
@@ -118,7 +136,16 @@ func Enqueue(townRoot, session string, nudge QueuedNudge) error {
 	filename := fmt.Sprintf("%d-%s.json", nudge.Timestamp.UnixNano(), randomSuffix())
 	path := filepath.Join(dir, filename)
 
+	// Write the receipt first so the drainer always finds one to update.
+	receipts := receiptDir(townRoot, session)
+	if err := withReceiptLock(receipts, func() error {
+		return writeReceipt(receipts, nudge)
+	}); err != nil {
+		return err
+	}
+
 	if err := os.WriteFile(path, data, 0644); err != nil {
+		_ = os.Remove(receiptPath(receipts, nudge.ID))
 		return fmt.Errorf("writing nudge to queue: %w", err)
 	}
 
@@ -134,6 +161,7 @@ func Enqueue(townRoot, session string, nudge QueuedNudge) error {
 //
 // Expired nudges (past ExpiresAt) are silently discarded during drain.
 // Orphaned .claimed files from crashed drainers are swept if older than 5 minutes.
+// Receipts are updated to injected (or expired) as nudges are drained.
 func Drain(townRoot, session string) ([]QueuedNudge, error) {
 	dir := queueDir(townRoot, session)
 
@@ -229,10 +257,12 @@ func Drain(townRoot, session string) ([]QueuedNudge, error) {
 			if rmErr := os.Remove(claimPath); rmErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to remove expired nudge %s: %v\n", entry.Name(), rmErr)
 			}
+			markDrained(townRoot, n, true, now)
 			continue
 		}
 
 		nudges = append(nudges, n)
+		markDrained(townRoot, n, false, now)
 
 		// Remove the claimed file after successful processing
 		if rmErr := os.Remove(claimPath); rmErr != nil {
@@ -266,6 +296,33 @@ func Pending(townRoot, session string) (int, error) {
 	return count, nil
 }
 
+// PendingAll returns the queued nudge count for every session that has a
+// queue directory. Keys are the on-disk (sanitized) session names.
+func PendingAll(townRoot string) (map[string]int, error) {
+	root := filepath.Join(townRoot, constants.DirRuntime, "nudge_queue")
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]int{}, nil
+		}
+		return nil, fmt.Errorf("reading nudge queues: %w", err)
+	}
+
+	counts := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		n, err := Pending(townRoot, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		counts[entry.Name()] = n
+	}
+	return counts, nil
+}
+
 // FormatForInjection formats queued nudges as a system-reminder block
 // suitable for Claude Code hook output.
 func FormatForInjection(nudges []QueuedNudge) string {
@@ -306,6 +363,16 @@ func FormatForInjection(nudges []QueuedNudge) string {
 		b.WriteString("\nThis is a background notification. Continue current work unless the nudge is higher priority.\n")
 	}
 
+	var ids []string
+	for _, n := range nudges {
+		if n.ID != "" {
+			ids = append(ids, n.ID)
+		}
+	}
+	if len(ids) > 0 {
+		b.WriteString(fmt.Sprintf("Once handled: gt nudge ack %s\n", strings.Join(ids, " ")))
+	}
+
 	b.WriteString("</system-reminder>\n")
 	return b.String()
 }
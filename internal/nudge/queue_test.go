@@ -182,6 +182,31 @@ func TestPendingNonexistentDir(t *testing.T) {
 	}
 }
 
+func TestPendingAll(t *testing.T) {
+	townRoot := t.TempDir()
+	for i := 0; i < 2; i++ {
+		if err := Enqueue(townRoot, "gt-alpha", QueuedNudge{Sender: "test", Message: "hi"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	if err := Enqueue(townRoot, "hq-mayor", QueuedNudge{Sender: "test", Message: "hi"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	counts, err := PendingAll(townRoot)
+	if err != nil {
+		t.Fatalf("PendingAll: %v", err)
+	}
+	if counts["gt-alpha"] != 2 || counts["hq-mayor"] != 1 || len(counts) != 2 {
+		t.Errorf("PendingAll = %v", counts)
+	}
+
+	counts, err = PendingAll(t.TempDir())
+	if err != nil || len(counts) != 0 {
+		t.Errorf("PendingAll(empty town) = %v, %v", counts, err)
+	}
+}
+
 func TestEnqueueDefaults(t *testing.T) {
 	townRoot := t.TempDir()
 	session := "gt-test-defaults"
@@ -0,0 +1,428 @@
+package nudge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Delivery states recorded on a nudge's receipt.
+//
+// A queued nudge becomes injected when the agent's hook drains it, or expired
+// if its TTL passes first. Nudges sent straight into the session start out
+// injected. An injected nudge becomes acknowledged when the agent runs
+// "gt nudge ack" or its stop hook reports the turn that saw it.
+const (
+	StateQueued       = "queued"
+	StateInjected     = "injected"
+	StateAcknowledged = "acknowledged"
+	StateExpired      = "expired"
+)
+
+// ReceiptRetention is how long receipts are kept after a nudge was sent.
+const ReceiptRetention = 24 * time.Hour
+
+// ErrUnknownNudge is returned for a nudge ID with no receipt.
+var ErrUnknownNudge = errors.New("unknown nudge")
+
+var validNudgeID = regexp.MustCompile(`^nd-[0-9a-f]+$`)
+
+// NewID returns a new nudge ID. Callers that need to report the ID (such as
+// gt nudge) set QueuedNudge.ID before Enqueue; Enqueue assigns one otherwise.
+func NewID() string {
+	return "nd-" + randomSuffix() + randomSuffix()[:4]
+}
+
+// receiptRoot returns the directory holding nudge receipts.
+// Path: <townRoot>/.runtime/nudge_receipts/
+func receiptRoot(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "nudge_receipts")
+}
+
+// receiptDir returns the directory holding a session's nudge receipts, so
+// per-session operations such as the stop hook's ack only touch that
+// session's files.
+// Path: <townRoot>/.runtime/nudge_receipts/<session>/
+func receiptDir(townRoot, session string) string {
+	return filepath.Join(receiptRoot(townRoot), strings.ReplaceAll(session, "/", "_"))
+}
+
+func receiptPath(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
+
+// findReceiptDir returns the session receipt directory holding the receipt
+// for id.
+func findReceiptDir(townRoot, id string) (string, error) {
+	if !validNudgeID.MatchString(id) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownNudge, id)
+	}
+	matches, _ := filepath.Glob(receiptPath(filepath.Join(receiptRoot(townRoot), "*"), id))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnknownNudge, id)
+	}
+	return filepath.Dir(matches[0]), nil
+}
+
+// receiptDirs returns every session receipt directory.
+func receiptDirs(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(receiptRoot(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading nudge receipts: %w", err)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(receiptRoot(townRoot), entry.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+// withReceiptLock runs fn holding the lock of a session's receipt
+// directory, so the drainer, acks and the expiry sweep never overwrite each
+// other's state changes.
+func withReceiptLock(dir string, fn func() error) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating nudge receipt dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, ".lock"))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking nudge receipts: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
+
+// writeReceipt stores a receipt atomically. Caller holds the receipt lock.
+func writeReceipt(dir string, n QueuedNudge) error {
+	data, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling nudge receipt: %w", err)
+	}
+	path := receiptPath(dir, n.ID)
+	tmp := path + ".tmp." + randomSuffix()
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing nudge receipt: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing nudge receipt: %w", err)
+	}
+	return nil
+}
+
+// readReceipt loads a receipt. Caller holds the receipt lock.
+func readReceipt(dir, id string) (*QueuedNudge, error) {
+	if !validNudgeID.MatchString(id) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNudge, id)
+	}
+	data, err := os.ReadFile(receiptPath(dir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNudge, id)
+		}
+		return nil, fmt.Errorf("reading nudge receipt: %w", err)
+	}
+	var n QueuedNudge
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("parsing nudge receipt %s: %w", id, err)
+	}
+	return &n, nil
+}
+
+// updateReceipt applies change to a receipt under the lock, saving it when
+// change reports a modification.
+func updateReceipt(dir, id string, change func(*QueuedNudge) (bool, error)) (*QueuedNudge, error) {
+	var result *QueuedNudge
+	err := withReceiptLock(dir, func() error {
+		n, err := readReceipt(dir, id)
+		if err != nil {
+			return err
+		}
+		changed, err := change(n)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err := writeReceipt(dir, *n); err != nil {
+				return err
+			}
+		}
+		result = n
+		return nil
+	})
+	return result, err
+}
+
+// markDrained records the outcome of draining a queued nudge: injected, or
+// expired if its TTL had passed. Nudges enqueued before receipts existed
+// have no ID and are skipped.
+func markDrained(townRoot string, n QueuedNudge, expired bool, now time.Time) {
+	if n.ID == "" {
+		return
+	}
+	_, _ = updateReceipt(receiptDir(townRoot, n.Session), n.ID, func(r *QueuedNudge) (bool, error) {
+		if r.State != StateQueued {
+			return false, nil
+		}
+		if expired {
+			r.State = StateExpired
+		} else {
+			r.State = StateInjected
+			r.InjectedAt = now
+		}
+		return true, nil
+	})
+}
+
+// Record stores a receipt for a nudge delivered straight into the session
+// (immediate mode) rather than through the queue. It starts out injected.
+// Returns the nudge ID.
+func Record(townRoot, session string, n QueuedNudge) (string, error) {
+	now := time.Now()
+	if n.ID == "" {
+		n.ID = NewID()
+	}
+	if n.Timestamp.IsZero() {
+		n.Timestamp = now
+	}
+	if n.Priority == "" {
+		n.Priority = PriorityNormal
+	}
+	n.Session = session
+	n.State = StateInjected
+	n.InjectedAt = now
+	dir := receiptDir(townRoot, session)
+	err := withReceiptLock(dir, func() error {
+		return writeReceipt(dir, n)
+	})
+	return n.ID, err
+}
+
+// Status returns a nudge's receipt. A queued nudge whose TTL has passed is
+// reported as expired even before the sweep records it.
+func Status(townRoot, id string) (*QueuedNudge, error) {
+	dir, err := findReceiptDir(townRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	var n *QueuedNudge
+	err = withReceiptLock(dir, func() error {
+		var err error
+		n, err = readReceipt(dir, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n.overdue(time.Now()) {
+		n.State = StateExpired
+	}
+	return n, nil
+}
+
+// Ack marks an injected nudge acknowledged. Acknowledging twice is a no-op;
+// a nudge that is still queued or has expired can't be acknowledged.
+func Ack(townRoot, id, by string) (*QueuedNudge, error) {
+	dir, err := findReceiptDir(townRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	return updateReceipt(dir, id, func(n *QueuedNudge) (bool, error) {
+		switch n.State {
+		case StateAcknowledged:
+			return false, nil
+		case StateQueued:
+			return false, fmt.Errorf("nudge %s has not been delivered yet", id)
+		case StateExpired:
+			return false, fmt.Errorf("nudge %s expired before delivery", id)
+		}
+		n.State = StateAcknowledged
+		n.AckedAt = time.Now()
+		n.AckedBy = by
+		return true, nil
+	})
+}
+
+// AckSession acknowledges every injected nudge for a session and returns
+// them. The stop hook calls it when a turn ends: anything injected before
+// then was in front of the agent during the turn. Only the session's own
+// receipts are read.
+func AckSession(townRoot, session, by string) ([]QueuedNudge, error) {
+	dir := receiptDir(townRoot, session)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var acked []QueuedNudge
+	err := withReceiptLock(dir, func() error {
+		receipts, err := listReceipts(dir)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, n := range receipts {
+			if n.State != StateInjected {
+				continue
+			}
+			n.State = StateAcknowledged
+			n.AckedAt = now
+			n.AckedBy = by
+			if err := writeReceipt(dir, n); err != nil {
+				return err
+			}
+			acked = append(acked, n)
+		}
+		return nil
+	})
+	return acked, err
+}
+
+// List returns the receipts for a session (every session if empty), oldest
+// first, with overdue queued nudges reported as expired.
+func List(townRoot, session string) ([]QueuedNudge, error) {
+	dirs := []string{receiptDir(townRoot, session)}
+	if session == "" {
+		var err error
+		if dirs, err = receiptDirs(townRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	var receipts []QueuedNudge
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		err := withReceiptLock(dir, func() error {
+			found, err := listReceipts(dir)
+			receipts = append(receipts, found...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sortReceipts(receipts)
+
+	now := time.Now()
+	for i := range receipts {
+		if receipts[i].overdue(now) {
+			receipts[i].State = StateExpired
+		}
+	}
+	return receipts, nil
+}
+
+// Outstanding returns the latest nudge from sender to session with the same
+// message that the agent has not finished with: still queued, or injected
+// but not acknowledged. Senders that re-nudge on a timer use it to avoid
+// repeating a message the agent already has. Returns nil if there is none.
+func Outstanding(townRoot, session, sender, message string) (*QueuedNudge, error) {
+	receipts, err := List(townRoot, session)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(receipts) - 1; i >= 0; i-- {
+		n := receipts[i]
+		if n.Sender != sender || n.Message != message {
+			continue
+		}
+		if n.State == StateQueued || n.State == StateInjected {
+			return &n, nil
+		}
+	}
+	return nil, nil
+}
+
+// SweepExpired records queued nudges whose TTL has passed as expired and
+// returns them, so whoever runs the sweep can escalate agents that never
+// picked up their nudges. Each nudge is returned by only one sweep. Receipts
+// older than ReceiptRetention are removed.
+func SweepExpired(townRoot string, now time.Time) ([]QueuedNudge, error) {
+	dirs, err := receiptDirs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []QueuedNudge
+	for _, dir := range dirs {
+		err := withReceiptLock(dir, func() error {
+			receipts, err := listReceipts(dir)
+			if err != nil {
+				return err
+			}
+			for _, n := range receipts {
+				if now.Sub(n.Timestamp) > ReceiptRetention {
+					_ = os.Remove(receiptPath(dir, n.ID))
+					continue
+				}
+				if !n.overdue(now) {
+					continue
+				}
+				n.State = StateExpired
+				if err := writeReceipt(dir, n); err != nil {
+					return err
+				}
+				expired = append(expired, n)
+			}
+			return nil
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	sortReceipts(expired)
+	return expired, nil
+}
+
+// listReceipts reads every receipt in a session receipt directory, oldest
+// first. Caller holds the directory's lock.
+func listReceipts(dir string) ([]QueuedNudge, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading nudge receipts: %w", err)
+	}
+
+	var receipts []QueuedNudge
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		n, err := readReceipt(dir, strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue // Malformed or foreign file; not worth failing the listing
+		}
+		receipts = append(receipts, *n)
+	}
+	sortReceipts(receipts)
+	return receipts, nil
+}
+
+func sortReceipts(receipts []QueuedNudge) {
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].Timestamp.Before(receipts[j].Timestamp)
+	})
+}
+
+// overdue reports whether a queued nudge's TTL has passed.
+func (n *QueuedNudge) overdue(now time.Time) bool {
+	return n.State == StateQueued && !n.ExpiresAt.IsZero() && now.After(n.ExpiresAt)
+}
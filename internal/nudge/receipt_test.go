@@ -0,0 +1,275 @@
+package nudge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+func TestReceipt_EnqueueDrainAck(t *testing.T) {
+	townRoot := t.TempDir()
+	session := "gt-gastown-toast"
+
+	id := NewID()
+	if err := Enqueue(townRoot, session, QueuedNudge{ID: id, Sender: "mayor", Message: "Check your hook"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	n, err := Status(townRoot, id)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if n.State != StateQueued || n.Session != session {
+		t.Errorf("after enqueue: state=%q session=%q, want queued/%s", n.State, n.Session, session)
+	}
+	if _, err := Ack(townRoot, id, "toast"); err == nil {
+		t.Error("Ack of a queued nudge should fail")
+	}
+
+	drained, err := Drain(townRoot, session)
+	if err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if len(drained) != 1 || drained[0].ID != id {
+		t.Fatalf("Drain = %+v, want the enqueued nudge", drained)
+	}
+
+	n, _ = Status(townRoot, id)
+	if n.State != StateInjected || n.InjectedAt.IsZero() {
+		t.Errorf("after drain: state=%q injected_at=%v, want injected", n.State, n.InjectedAt)
+	}
+
+	n, err = Ack(townRoot, id, "gastown/polecats/toast")
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if n.State != StateAcknowledged || n.AckedBy != "gastown/polecats/toast" {
+		t.Errorf("after ack: %+v", n)
+	}
+	ackedAt := n.AckedAt
+
+	// Acking again is a no-op.
+	n, err = Ack(townRoot, id, "someone-else")
+	if err != nil {
+		t.Fatalf("second Ack: %v", err)
+	}
+	if n.AckedBy != "gastown/polecats/toast" || !n.AckedAt.Equal(ackedAt) {
+		t.Errorf("second ack changed the receipt: %+v", n)
+	}
+}
+
+func TestReceipt_EnqueueAssignsID(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Enqueue(townRoot, "gt-gastown-toast", QueuedNudge{Sender: "mayor", Message: "hi"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	receipts, err := List(townRoot, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(receipts) != 1 || !validNudgeID.MatchString(receipts[0].ID) {
+		t.Fatalf("receipts = %+v, want one with a valid ID", receipts)
+	}
+}
+
+func TestReceipt_UnknownID(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, id := range []string{"nd-0123abcd", "../etc/passwd", ""} {
+		if _, err := Status(townRoot, id); !errors.Is(err, ErrUnknownNudge) {
+			t.Errorf("Status(%q) err = %v, want ErrUnknownNudge", id, err)
+		}
+	}
+}
+
+func TestReceipt_Record(t *testing.T) {
+	townRoot := t.TempDir()
+	id, err := Record(townRoot, "gt-gastown-toast", QueuedNudge{Sender: "mayor", Message: "Status?"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	n, err := Status(townRoot, id)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if n.State != StateInjected || n.Priority != PriorityNormal {
+		t.Errorf("recorded receipt = %+v, want injected/normal", n)
+	}
+}
+
+func TestReceipt_AckSession(t *testing.T) {
+	townRoot := t.TempDir()
+	a, _ := Record(townRoot, "gt-gastown-toast", QueuedNudge{Sender: "mayor", Message: "one"})
+	b, _ := Record(townRoot, "gt-gastown-toast", QueuedNudge{Sender: "mayor", Message: "two"})
+	other, _ := Record(townRoot, "gt-gastown-nux", QueuedNudge{Sender: "mayor", Message: "three"})
+	queued := NewID()
+	if err := Enqueue(townRoot, "gt-gastown-toast", QueuedNudge{ID: queued, Sender: "mayor", Message: "four"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	acked, err := AckSession(townRoot, "gt-gastown-toast", "stop-hook")
+	if err != nil {
+		t.Fatalf("AckSession: %v", err)
+	}
+	if len(acked) != 2 {
+		t.Fatalf("AckSession acked %d, want 2", len(acked))
+	}
+
+	for id, want := range map[string]string{
+		a:      StateAcknowledged,
+		b:      StateAcknowledged,
+		other:  StateInjected,
+		queued: StateQueued,
+	} {
+		n, err := Status(townRoot, id)
+		if err != nil {
+			t.Fatalf("Status(%s): %v", id, err)
+		}
+		if n.State != want {
+			t.Errorf("%s (%s) state = %q, want %q", id, n.Message, n.State, want)
+		}
+	}
+}
+
+func TestReceipt_AckSessionOnlyTouchesOwnSession(t *testing.T) {
+	townRoot := t.TempDir()
+	id, _ := Record(townRoot, "gt-gastown-toast", QueuedNudge{Sender: "mayor", Message: "one"})
+	other, _ := Record(townRoot, "gt-gastown-nux", QueuedNudge{Sender: "mayor", Message: "two"})
+
+	if _, err := os.Stat(receiptPath(receiptDir(townRoot, "gt-gastown-toast"), id)); err != nil {
+		t.Fatalf("receipt not in its session dir: %v", err)
+	}
+
+	// Another session's receipts being busy must not hold up the stop hook.
+	lock := flock.New(filepath.Join(receiptDir(townRoot, "gt-gastown-nux"), ".lock"))
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	done := make(chan []QueuedNudge, 1)
+	go func() {
+		acked, _ := AckSession(townRoot, "gt-gastown-toast", "stop-hook")
+		done <- acked
+	}()
+	select {
+	case acked := <-done:
+		if len(acked) != 1 || acked[0].ID != id {
+			t.Errorf("AckSession = %+v, want only %s", acked, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AckSession waited on another session's receipt lock")
+	}
+	_ = lock.Unlock()
+
+	if n, _ := Status(townRoot, other); n.State != StateInjected {
+		t.Errorf("other session's nudge state = %q, want injected", n.State)
+	}
+}
+
+func TestReceipt_ExpiredSweep(t *testing.T) {
+	townRoot := t.TempDir()
+	session := "gt-gastown-toast"
+	now := time.Now()
+
+	stale := NewID()
+	if err := Enqueue(townRoot, session, QueuedNudge{
+		ID: stale, Sender: "mayor", Message: "old news",
+		Timestamp: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute),
+	}); err != nil {
+		t.Fatalf("Enqueue stale: %v", err)
+	}
+	fresh := NewID()
+	if err := Enqueue(townRoot, session, QueuedNudge{ID: fresh, Sender: "mayor", Message: "new"}); err != nil {
+		t.Fatalf("Enqueue fresh: %v", err)
+	}
+
+	// Status reports overdue nudges as expired before any sweep.
+	if n, _ := Status(townRoot, stale); n.State != StateExpired {
+		t.Errorf("Status(stale) = %q, want expired", n.State)
+	}
+
+	expired, err := SweepExpired(townRoot, now)
+	if err != nil {
+		t.Fatalf("SweepExpired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != stale {
+		t.Fatalf("SweepExpired = %+v, want only the stale nudge", expired)
+	}
+
+	// Each expiry is reported once.
+	if again, _ := SweepExpired(townRoot, now); len(again) != 0 {
+		t.Errorf("second sweep returned %d, want 0", len(again))
+	}
+
+	// Draining discards the expired nudge; its receipt stays expired.
+	drained, _ := Drain(townRoot, session)
+	if len(drained) != 1 || drained[0].ID != fresh {
+		t.Errorf("Drain = %+v, want only the fresh nudge", drained)
+	}
+	if n, _ := Status(townRoot, stale); n.State != StateExpired {
+		t.Errorf("stale after drain = %q, want expired", n.State)
+	}
+
+	// Receipts past retention are pruned.
+	if _, err := SweepExpired(townRoot, now.Add(ReceiptRetention+time.Hour)); err != nil {
+		t.Fatalf("SweepExpired (prune): %v", err)
+	}
+	if receipts, _ := List(townRoot, ""); len(receipts) != 0 {
+		t.Errorf("after retention: %d receipts remain, want 0", len(receipts))
+	}
+}
+
+func TestReceipt_Outstanding(t *testing.T) {
+	townRoot := t.TempDir()
+	session := "gt-gastown-refinery"
+	msg := "MERGE_READY received - check inbox for pending work"
+
+	if n, err := Outstanding(townRoot, session, "gastown/witness", msg); err != nil || n != nil {
+		t.Fatalf("Outstanding on empty = %v, %v; want nil", n, err)
+	}
+
+	if err := Enqueue(townRoot, session, QueuedNudge{Sender: "gastown/witness", Message: msg}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	n, err := Outstanding(townRoot, session, "gastown/witness", msg)
+	if err != nil || n == nil {
+		t.Fatalf("Outstanding while queued = %v, %v; want the nudge", n, err)
+	}
+	if other, _ := Outstanding(townRoot, session, "mayor", msg); other != nil {
+		t.Error("Outstanding matched a different sender")
+	}
+
+	// Still outstanding once injected; cleared once acknowledged.
+	if _, err := Drain(townRoot, session); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if n, _ := Outstanding(townRoot, session, "gastown/witness", msg); n == nil {
+		t.Error("injected nudge should still be outstanding")
+	}
+	if _, err := AckSession(townRoot, session, "refinery"); err != nil {
+		t.Fatalf("AckSession: %v", err)
+	}
+	if n, _ := Outstanding(townRoot, session, "gastown/witness", msg); n != nil {
+		t.Errorf("acknowledged nudge still outstanding: %+v", n)
+	}
+}
+
+func TestFormatForInjection_AckHint(t *testing.T) {
+	out := FormatForInjection([]QueuedNudge{
+		{ID: "nd-aaaa", Sender: "mayor", Message: "one", Priority: PriorityNormal},
+		{ID: "nd-bbbb", Sender: "mayor", Message: "two", Priority: PriorityNormal},
+	})
+	if !strings.Contains(out, "gt nudge ack nd-aaaa nd-bbbb") {
+		t.Errorf("output missing ack hint:\n%s", out)
+	}
+
+	legacy := FormatForInjection([]QueuedNudge{{Sender: "mayor", Message: "old", Priority: PriorityNormal}})
+	if strings.Contains(legacy, "gt nudge ack") {
+		t.Errorf("ack hint shown for nudges without IDs:\n%s", legacy)
+	}
+}
@@ -0,0 +1,347 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LockfileName is the lockfile in a plugins directory. It records the
+// plugins installed there by 'gt plugin install'; plugins added by hand
+// are not in it and are never touched by upgrade or remove.
+const LockfileName = "plugins.lock.json"
+
+// Lockfile records where installed plugins came from and what was installed.
+type Lockfile struct {
+	Version int                   `json:"version"`
+	Plugins map[string]*LockEntry `json:"plugins"`
+}
+
+// LockEntry is one installed plugin.
+type LockEntry struct {
+	// Source is what was installed from, in the form ParseSource accepts.
+	Source string     `json:"source"`
+	Kind   SourceKind `json:"kind"`
+
+	// Commit is the fetched commit for git sources.
+	Commit string `json:"commit,omitempty"`
+
+	// Version is the plugin's frontmatter version.
+	Version int `json:"version"`
+
+	// Hash is the content hash of the installed directory (see HashDir).
+	Hash string `json:"hash"`
+
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// LoadLockfile reads the lockfile in a plugins directory. A missing
+// lockfile is an empty one.
+func LoadLockfile(pluginsDir string) (*Lockfile, error) {
+	lock := &Lockfile{Version: 1, Plugins: make(map[string]*LockEntry)}
+	data, err := os.ReadFile(filepath.Join(pluginsDir, LockfileName)) //nolint:gosec // G304: path is in the plugins directory
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", LockfileName, err)
+	}
+	if lock.Plugins == nil {
+		lock.Plugins = make(map[string]*LockEntry)
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile atomically.
+func (l *Lockfile) Save(pluginsDir string) error {
+	return util.AtomicWriteJSON(filepath.Join(pluginsDir, LockfileName), l)
+}
+
+// Names returns the locked plugin names, sorted.
+func (l *Lockfile) Names() []string {
+	names := make([]string, 0, len(l.Plugins))
+	for name := range l.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validPluginName keeps plugin names usable as directory names.
+var validPluginName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Installer installs, upgrades and removes plugins in one plugins
+// directory (town-level or a rig's).
+type Installer struct {
+	// Dir is the plugins directory.
+	Dir string
+
+	// Policy evaluates manifest permissions; nil uses the default policy.
+	Policy *policy.Evaluator
+
+	// Repo is the repo permissions are evaluated against (may be empty).
+	Repo string
+
+	// Force installs despite unmet requirements (policy denies still
+	// block), allows downgrades and overwrites local modifications.
+	Force bool
+}
+
+// InstallResult describes a completed install or upgrade.
+type InstallResult struct {
+	Plugin *Plugin            `json:"plugin"`
+	Entry  *LockEntry         `json:"entry"`
+	Checks []RequirementCheck `json:"checks,omitempty"`
+
+	// Previous is the replaced entry on upgrade.
+	Previous *LockEntry `json:"previous,omitempty"`
+
+	// Unchanged is set when an upgrade found nothing new.
+	Unchanged bool `json:"unchanged,omitempty"`
+}
+
+// Install fetches a plugin from source, checks its manifest and installs
+// it as Dir/<name>. The plugin must not already exist in Dir.
+func (i *Installer) Install(source string) (*InstallResult, error) {
+	src, err := ParseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return i.install(src, "")
+}
+
+// Upgrade re-fetches a plugin from its locked source, or from source if
+// given, and replaces the installed copy when its content changed.
+func (i *Installer) Upgrade(name, source string) (*InstallResult, error) {
+	lock, err := LoadLockfile(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := lock.Plugins[name]
+	if !ok {
+		return nil, fmt.Errorf("plugin %s was not installed with gt plugin install", name)
+	}
+	if source == "" {
+		source = entry.Source
+	}
+	src, err := ParseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return i.install(src, name)
+}
+
+// Remove deletes an installed plugin and its lock entry.
+func (i *Installer) Remove(name string) (*LockEntry, error) {
+	unlock, err := i.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	lock, err := LoadLockfile(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := lock.Plugins[name]
+	if !ok {
+		return nil, fmt.Errorf("plugin %s was not installed with gt plugin install; delete %s by hand",
+			name, filepath.Join(i.Dir, name))
+	}
+	if err := i.checkUnmodified(name, entry); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(filepath.Join(i.Dir, name)); err != nil {
+		return nil, err
+	}
+	delete(lock.Plugins, name)
+	return entry, lock.Save(i.Dir)
+}
+
+// Verify reports whether an installed plugin still matches its lock entry.
+func (i *Installer) Verify(name string) (bool, error) {
+	lock, err := LoadLockfile(i.Dir)
+	if err != nil {
+		return false, err
+	}
+	entry, ok := lock.Plugins[name]
+	if !ok {
+		return false, fmt.Errorf("plugin %s is not in %s", name, LockfileName)
+	}
+	hash, err := HashDir(filepath.Join(i.Dir, name))
+	if err != nil {
+		return false, err
+	}
+	return hash == entry.Hash, nil
+}
+
+// install fetches and installs src. upgrading names the installed plugin
+// being replaced; empty means a fresh install.
+func (i *Installer) install(src Source, upgrading string) (*InstallResult, error) {
+	unlock, err := i.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	workDir, err := os.MkdirTemp("", "gt-plugin-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	fetched, commit, err := src.fetch(workDir)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(fetched, "plugin.md")) //nolint:gosec // G304: path is inside the fetched plugin
+	if err != nil {
+		return nil, err
+	}
+	p, err := parsePluginMD(content, fetched, LocationTown, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	if !validPluginName.MatchString(p.Name) {
+		return nil, fmt.Errorf("plugin name %q is not a valid directory name", p.Name)
+	}
+	hash, err := HashDir(fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := LoadLockfile(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+	dest := filepath.Join(i.Dir, p.Name)
+	result := &InstallResult{Plugin: p}
+
+	if upgrading == "" {
+		if _, err := os.Stat(dest); err == nil {
+			if _, locked := lock.Plugins[p.Name]; locked {
+				return nil, fmt.Errorf("plugin %s is already installed; use gt plugin upgrade", p.Name)
+			}
+			return nil, fmt.Errorf("plugin %s already exists at %s and was not installed with gt plugin install", p.Name, dest)
+		}
+	} else {
+		if p.Name != upgrading {
+			return nil, fmt.Errorf("%s provides plugin %s, not %s", src, p.Name, upgrading)
+		}
+		prev, ok := lock.Plugins[upgrading]
+		if !ok {
+			return nil, fmt.Errorf("plugin %s was removed during the upgrade", upgrading)
+		}
+		result.Previous = prev
+		if prev.Hash == hash && prev.Source == src.String() {
+			result.Entry, result.Unchanged = prev, true
+			return result, nil
+		}
+		if p.Version < prev.Version && !i.Force {
+			return nil, fmt.Errorf("plugin %s: %s has version %d, older than installed version %d (use --force to downgrade)",
+				p.Name, src, p.Version, prev.Version)
+		}
+		if err := i.checkUnmodified(upgrading, prev); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Checks = CheckRequirements(p, i.Policy, i.Repo)
+	if failed := failedChecks(result.Checks, i.Force); len(failed) > 0 {
+		return nil, &RequirementsError{Plugin: p.Name, Failed: failed}
+	}
+
+	if err := replaceDir(i.Dir, dest, fetched); err != nil {
+		return nil, err
+	}
+
+	result.Entry = &LockEntry{
+		Source:      src.String(),
+		Kind:        src.Kind,
+		Commit:      commit,
+		Version:     p.Version,
+		Hash:        hash,
+		InstalledAt: time.Now().UTC(),
+	}
+	lock.Plugins[p.Name] = result.Entry
+	if err := lock.Save(i.Dir); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", LockfileName, err)
+	}
+	p.Path = dest
+	return result, nil
+}
+
+// checkUnmodified refuses to overwrite or delete a plugin edited since it
+// was installed, unless forced.
+func (i *Installer) checkUnmodified(name string, entry *LockEntry) error {
+	if i.Force {
+		return nil
+	}
+	hash, err := HashDir(filepath.Join(i.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hash != entry.Hash {
+		return fmt.Errorf("plugin %s was modified since it was installed (use --force to discard the changes)", name)
+	}
+	return nil
+}
+
+// lock serializes installs in one plugins directory.
+func (i *Installer) lock() (func(), error) {
+	if err := os.MkdirAll(i.Dir, 0755); err != nil {
+		return nil, err
+	}
+	fl := flock.New(filepath.Join(i.Dir, LockfileName+".lock"))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("locking %s: %w", i.Dir, err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// replaceDir copies src into dest, swapping out any existing dest only
+// once the copy is complete. Staging directories are dot-prefixed so the
+// scanner ignores them.
+func replaceDir(parent, dest, src string) error {
+	staging, err := os.MkdirTemp(parent, ".install-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	staged := filepath.Join(staging, "plugin")
+	if err := copyPlugin(staged, src); err != nil {
+		return fmt.Errorf("copying plugin: %w", err)
+	}
+
+	old := filepath.Join(staging, "old")
+	hadOld := false
+	if _, err := os.Stat(dest); err == nil {
+		if err := os.Rename(dest, old); err != nil {
+			return err
+		}
+		hadOld = true
+	}
+	if err := os.Rename(staged, dest); err != nil {
+		if hadOld {
+			_ = os.Rename(old, dest)
+		}
+		return err
+	}
+	return nil
+}
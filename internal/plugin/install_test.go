@@ -0,0 +1,397 @@
+package plugin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/policy"
+)
+
+// writeTestPlugin creates a plugin directory with the given frontmatter
+// extras (appended after name/version).
+func writeTestPlugin(t *testing.T, dir, name string, version int, extra string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	md := fmt.Sprintf("+++\nname = %q\nversion = %d\n%s+++\n\n# %s\n", name, version, extra, name)
+	if err := os.WriteFile(filepath.Join(dir, "plugin.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\necho "+name+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "sheriff.tar.gz")
+	if err := os.WriteFile(archive, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(other, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		raw    string
+		kind   SourceKind
+		loc    string
+		subdir string
+	}{
+		{"https://github.com/org/plugins.git#plugins/github-sheriff", SourceGit, "https://github.com/org/plugins.git", "plugins/github-sheriff"},
+		{"git@github.com:org/plugins.git", SourceGit, "git@github.com:org/plugins.git", ""},
+		{"git+file:///srv/plugins#sheriff/", SourceGit, "file:///srv/plugins", "sheriff"},
+		{dir, SourcePath, dir, ""},
+		{"file://" + dir, SourcePath, dir, ""},
+		{archive, SourceArchive, archive, ""},
+	}
+	for _, tt := range tests {
+		src, err := ParseSource(tt.raw)
+		if err != nil {
+			t.Errorf("ParseSource(%q): %v", tt.raw, err)
+			continue
+		}
+		if src.Kind != tt.kind || src.Location != tt.loc || src.Subdir != tt.subdir {
+			t.Errorf("ParseSource(%q) = %+v", tt.raw, src)
+		}
+	}
+
+	for _, bad := range []string{"", "--upload-pack=evil", other, filepath.Join(dir, "missing"), "https://h/r.git#../../etc"} {
+		if _, err := ParseSource(bad); err == nil {
+			t.Errorf("ParseSource(%q) should fail", bad)
+		}
+	}
+}
+
+func TestHashDir(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeTestPlugin(t, a, "p", 1, "")
+	writeTestPlugin(t, b, "p", 1, "")
+	if err := os.MkdirAll(filepath.Join(b, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(b, ".git", "HEAD"), []byte("ref"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ha, err := HashDir(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, _ := HashDir(b)
+	if ha != hb || !strings.HasPrefix(ha, "sha256:") {
+		t.Errorf("hashes differ or malformed: %s vs %s (.git must be ignored)", ha, hb)
+	}
+
+	if err := os.Chmod(filepath.Join(b, "run.sh"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hb, _ = HashDir(b); hb == ha {
+		t.Error("executable bit should change the hash")
+	}
+
+	if err := os.Symlink("/etc/passwd", filepath.Join(a, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := HashDir(a); err == nil {
+		t.Error("symlinks should be rejected")
+	}
+}
+
+func TestInstaller_InstallUpgradeRemove(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "sheriff-src")
+	writeTestPlugin(t, src, "sheriff", 1, "")
+	pluginsDir := filepath.Join(t.TempDir(), "plugins")
+	inst := &Installer{Dir: pluginsDir}
+
+	res, err := inst.Install(src)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if res.Entry.Kind != SourcePath || res.Entry.Source != src || res.Entry.Version != 1 {
+		t.Errorf("entry = %+v", res.Entry)
+	}
+	info, err := os.Stat(filepath.Join(pluginsDir, "sheriff", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Fatalf("run.sh not installed executable: %v %v", info, err)
+	}
+
+	// The installed plugin is discovered like any other, and the
+	// lockfile and staging dirs are not.
+	plugins, err := NewScanner(filepath.Dir(pluginsDir), nil).DiscoverAll()
+	if err != nil || len(plugins) != 1 || plugins[0].Name != "sheriff" {
+		t.Fatalf("DiscoverAll = %v, %v", plugins, err)
+	}
+
+	if _, err := inst.Install(src); err == nil || !strings.Contains(err.Error(), "gt plugin upgrade") {
+		t.Errorf("second install: %v", err)
+	}
+
+	// Nothing changed upstream.
+	res, err = inst.Upgrade("sheriff", "")
+	if err != nil || !res.Unchanged {
+		t.Fatalf("no-op upgrade: %+v, %v", res, err)
+	}
+
+	// A new version upgrades.
+	writeTestPlugin(t, src, "sheriff", 2, "description = \"v2\"\n")
+	res, err = inst.Upgrade("sheriff", "")
+	if err != nil || res.Unchanged || res.Previous.Version != 1 || res.Entry.Version != 2 {
+		t.Fatalf("upgrade: %+v, %v", res, err)
+	}
+	if ok, err := inst.Verify("sheriff"); !ok || err != nil {
+		t.Errorf("Verify after upgrade = %v, %v", ok, err)
+	}
+
+	// Downgrades need --force.
+	writeTestPlugin(t, src, "sheriff", 1, "")
+	if _, err := inst.Upgrade("sheriff", ""); err == nil || !strings.Contains(err.Error(), "downgrade") {
+		t.Errorf("downgrade: %v", err)
+	}
+
+	// Local edits block upgrade and remove unless forced.
+	if err := os.WriteFile(filepath.Join(pluginsDir, "sheriff", "run.sh"), []byte("edited"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := inst.Verify("sheriff"); ok {
+		t.Error("Verify should notice the local edit")
+	}
+	if _, err := inst.Remove("sheriff"); err == nil || !strings.Contains(err.Error(), "modified") {
+		t.Errorf("remove modified: %v", err)
+	}
+	inst.Force = true
+	if _, err := inst.Remove("sheriff"); err != nil {
+		t.Fatalf("forced remove: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pluginsDir, "sheriff")); !os.IsNotExist(err) {
+		t.Errorf("plugin dir still present: %v", err)
+	}
+	lock, _ := LoadLockfile(pluginsDir)
+	if len(lock.Plugins) != 0 {
+		t.Errorf("lockfile still has %v", lock.Names())
+	}
+}
+
+func TestInstaller_UnmanagedPlugin(t *testing.T) {
+	pluginsDir := t.TempDir()
+	writeTestPlugin(t, filepath.Join(pluginsDir, "handmade"), "handmade", 1, "")
+	src := filepath.Join(t.TempDir(), "handmade")
+	writeTestPlugin(t, src, "handmade", 2, "")
+	inst := &Installer{Dir: pluginsDir}
+
+	if _, err := inst.Install(src); err == nil || !strings.Contains(err.Error(), "not installed with gt plugin install") {
+		t.Errorf("install over hand-made plugin: %v", err)
+	}
+	if _, err := inst.Upgrade("handmade", ""); err == nil {
+		t.Error("upgrade of hand-made plugin should fail")
+	}
+	if _, err := inst.Remove("handmade"); err == nil {
+		t.Error("remove of hand-made plugin should fail")
+	}
+	if _, err := os.Stat(filepath.Join(pluginsDir, "handmade", "plugin.md")); err != nil {
+		t.Errorf("hand-made plugin was touched: %v", err)
+	}
+}
+
+func TestInstaller_Requirements(t *testing.T) {
+	t.Setenv("GT_TEST_PLUGIN_TOKEN", "x")
+	src := filepath.Join(t.TempDir(), "sheriff")
+	writeTestPlugin(t, src, "sheriff", 1, `
+[requires]
+binaries = ["sh", "gt-no-such-binary"]
+env = ["GT_TEST_PLUGIN_TOKEN", "GT_TEST_PLUGIN_UNSET"]
+permissions = ["gh pr list", "git push origin main"]
+`)
+
+	checks := CheckRequirements(mustParse(t, src), nil, "")
+	failed := map[string]bool{}
+	for _, c := range checks {
+		if !c.OK {
+			failed[c.Name] = true
+		}
+	}
+	want := map[string]bool{"gt-no-such-binary": true, "GT_TEST_PLUGIN_UNSET": true, "git push origin main": true}
+	if fmt.Sprint(failed) != fmt.Sprint(want) {
+		t.Errorf("failed checks = %v, want %v", failed, want)
+	}
+
+	inst := &Installer{Dir: t.TempDir()}
+	_, err := inst.Install(src)
+	var reqErr *RequirementsError
+	if !errors.As(err, &reqErr) || len(reqErr.Failed) != 3 || reqErr.Denied() {
+		t.Fatalf("install with unmet requirements: %v", err)
+	}
+
+	// --force installs anyway, but not over a policy deny.
+	deny := policy.NewEvaluator(&policy.Document{Rules: []policy.Rule{{
+		ID:       "no-dog-pushes",
+		Decision: policy.DecisionDeny,
+		Match:    policy.RuleMatch{Agents: []string{"deacon/dogs*"}, CommandPrefixes: []string{"git push"}},
+	}}})
+	inst = &Installer{Dir: t.TempDir(), Policy: deny, Force: true}
+	_, err = inst.Install(src)
+	if !errors.As(err, &reqErr) || !reqErr.Denied() || len(reqErr.Failed) != 1 {
+		t.Fatalf("forced install over deny: %v", err)
+	}
+
+	inst.Policy = nil
+	res, err := inst.Install(src)
+	if err != nil {
+		t.Fatalf("forced install: %v", err)
+	}
+	if len(res.Checks) != 6 {
+		t.Errorf("got %d checks, want 6", len(res.Checks))
+	}
+}
+
+func mustParse(t *testing.T, dir string) *Plugin {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, "plugin.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := parsePluginMD(content, dir, LocationTown, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestInstaller_Archives(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "sheriff")
+	writeTestPlugin(t, src, "sheriff", 1, "")
+	files := map[string]string{}
+	for _, name := range []string{"plugin.md", "run.sh"} {
+		data, _ := os.ReadFile(filepath.Join(src, name))
+		files["sheriff/"+name] = string(data)
+	}
+	want, _ := HashDir(src)
+
+	dir := t.TempDir()
+	tgz := filepath.Join(dir, "sheriff.tgz")
+	writeTarGz(t, tgz, files)
+	zipPath := filepath.Join(dir, "sheriff.zip")
+	writeZip(t, zipPath, files)
+
+	for _, archive := range []string{tgz, zipPath} {
+		inst := &Installer{Dir: t.TempDir()}
+		res, err := inst.Install(archive)
+		if err != nil {
+			t.Fatalf("install %s: %v", filepath.Base(archive), err)
+		}
+		if res.Entry.Kind != SourceArchive || res.Entry.Hash != want {
+			t.Errorf("%s: entry = %+v, want hash %s", filepath.Base(archive), res.Entry, want)
+		}
+	}
+
+	evil := filepath.Join(dir, "evil.tar.gz")
+	writeTarGz(t, evil, map[string]string{"../escape/plugin.md": "+++\nname = \"x\"\n+++\n"})
+	if _, err := (&Installer{Dir: t.TempDir()}).Install(evil); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Errorf("path traversal archive: %v", err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		mode := int64(0644)
+		if strings.HasSuffix(name, ".sh") {
+			mode = 0755
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		hdr := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		hdr.SetMode(0644)
+		if strings.HasSuffix(name, ".sh") {
+			hdr.SetMode(0755)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstaller_GitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	writeTestPlugin(t, filepath.Join(repo, "plugins", "sheriff"), "sheriff", 1, "")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	inst := &Installer{Dir: t.TempDir()}
+	res, err := inst.Install("git+file://" + repo + "#plugins/sheriff")
+	if err != nil {
+		t.Fatalf("install from git: %v", err)
+	}
+	if res.Entry.Kind != SourceGit || len(res.Entry.Commit) != 40 {
+		t.Errorf("entry = %+v", res.Entry)
+	}
+	if res.Entry.Source != "git+file://"+repo+"#plugins/sheriff" {
+		t.Errorf("source = %q", res.Entry.Source)
+	}
+	if _, err := os.Stat(filepath.Join(inst.Dir, "sheriff", ".git")); !os.IsNotExist(err) {
+		t.Error(".git should not be installed")
+	}
+
+	// Upgrade re-fetches from the locked git source.
+	if res, err = inst.Upgrade("sheriff", ""); err != nil || !res.Unchanged {
+		t.Errorf("upgrade from git: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/policy"
+)
+
+// PluginAgent is the agent identity plugin permissions are evaluated as:
+// plugins run on dogs.
+const PluginAgent = "deacon/dogs"
+
+// RequirementCheck is the result of checking one manifest requirement.
+type RequirementCheck struct {
+	Kind   string `json:"kind"` // binary, env or permission
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+
+	// Denied is set when town policy denies a permission. Unlike a missing
+	// binary or an approval requirement, --force doesn't override it.
+	Denied bool `json:"denied,omitempty"`
+}
+
+// CheckRequirements checks a plugin's manifest against this machine and
+// the town's policy. Binaries must be on PATH and env vars set. Each
+// permission is evaluated (as a dry run) as a command run by a dog: allow
+// and allow-with-justification pass, require-approval fails and deny
+// fails hard. A nil evaluator uses the default policy.
+func CheckRequirements(p *Plugin, eval *policy.Evaluator, repo string) []RequirementCheck {
+	if p.Requires == nil {
+		return nil
+	}
+	if eval == nil {
+		eval = policy.NewDefaultEvaluator()
+	}
+
+	var checks []RequirementCheck
+	for _, bin := range p.Requires.Binaries {
+		c := RequirementCheck{Kind: "binary", Name: bin}
+		if path, err := exec.LookPath(bin); err == nil {
+			c.OK, c.Detail = true, path
+		} else {
+			c.Detail = "not found on PATH"
+		}
+		checks = append(checks, c)
+	}
+
+	for _, name := range p.Requires.Env {
+		c := RequirementCheck{Kind: "env", Name: name}
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			c.OK, c.Detail = true, "set"
+		} else {
+			c.Detail = "not set"
+		}
+		checks = append(checks, c)
+	}
+
+	for _, perm := range p.Requires.Permissions {
+		c := RequirementCheck{Kind: "permission", Name: perm}
+		result := eval.Evaluate(policy.EvalRequest{
+			Agent:       PluginAgent,
+			Repo:        repo,
+			Command:     perm,
+			Args:        strings.Fields(perm),
+			RequestedBy: "plugin:" + p.Name,
+			DryRun:      true,
+		})
+		c.Detail = string(result.Decision)
+		if result.Reason != "" {
+			c.Detail += ": " + result.Reason
+		}
+		switch result.Decision {
+		case policy.DecisionAllow, policy.DecisionAllowWithJustification:
+			c.OK = true
+		case policy.DecisionDeny:
+			c.Denied = true
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// RequirementsError reports the requirements a plugin failed at install.
+type RequirementsError struct {
+	Plugin string
+	Failed []RequirementCheck
+}
+
+func (e *RequirementsError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, c := range e.Failed {
+		parts[i] = fmt.Sprintf("%s %s (%s)", c.Kind, c.Name, c.Detail)
+	}
+	return fmt.Sprintf("plugin %s: unmet requirements: %s", e.Plugin, strings.Join(parts, "; "))
+}
+
+// Denied reports whether any failure is a policy deny.
+func (e *RequirementsError) Denied() bool {
+	for _, c := range e.Failed {
+		if c.Denied {
+			return true
+		}
+	}
+	return false
+}
+
+// failedChecks returns the checks that block an install. With force only
+// policy denies block.
+func failedChecks(checks []RequirementCheck, force bool) []RequirementCheck {
+	var failed []RequirementCheck
+	for _, c := range checks {
+		if c.Denied || (!c.OK && !force) {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
@@ -184,6 +184,7 @@ func parsePluginMD(content []byte, pluginDir string, location Location, rigName
 		Gate:         fm.Gate,
 		Tracking:     fm.Tracking,
 		Execution:    fm.Execution,
+		Requires:     fm.Requires,
 		Instructions: body,
 	}
 
@@ -0,0 +1,269 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventStartup is the event an event gate names to run once each time the
+// scheduler starts (on = "startup").
+const EventStartup = "startup"
+
+// DefaultCooldown is the cooldown for a cooldown gate without a duration.
+const DefaultCooldown = time.Hour
+
+// ErrNoIdleDog is returned by a dispatch hook when every dog is busy. The
+// gate stays open, so the plugin is dispatched on a later tick.
+var ErrNoIdleDog = errors.New("no idle dogs available")
+
+// GateState is what a gate is evaluated against on one scheduler tick.
+type GateState struct {
+	// Now is the tick time.
+	Now time.Time
+
+	// LastRun is the newest recorded run of the plugin (zero if none).
+	LastRun time.Time
+
+	// Since is when the scheduler started. A cron gate that has never run
+	// fires at its first scheduled time after Since, not retroactively.
+	Since time.Time
+
+	// Events are the event types seen since the plugin was last dispatched.
+	Events map[string]bool
+
+	// Check runs a condition gate's check command; true means exit 0.
+	Check func(p *Plugin) (bool, error)
+}
+
+// GateOpen reports whether the plugin's gate lets it run now, with a short
+// reason for logs. Manual plugins (and plugins without a gate) never open.
+//
+// Cooldown, cron and condition gates are judged against the ledger's last
+// run, so a run recorded by 'gt plugin run' or a dog counts too. A duration
+// on a condition or event gate is the minimum time between runs.
+func (p *Plugin) GateOpen(st GateState) (bool, string, error) {
+	if p.Gate == nil {
+		return false, "manual", nil
+	}
+	g := p.Gate
+
+	switch g.Type {
+	case GateCooldown:
+		cooldown := DefaultCooldown
+		if g.Duration != "" {
+			d, err := time.ParseDuration(g.Duration)
+			if err != nil {
+				return false, "", fmt.Errorf("cooldown duration %q: %w", g.Duration, err)
+			}
+			cooldown = d
+		}
+		if !st.LastRun.IsZero() && st.Now.Sub(st.LastRun) < cooldown {
+			return false, fmt.Sprintf("cooldown %s not elapsed", cooldown), nil
+		}
+		return true, fmt.Sprintf("cooldown %s elapsed", cooldown), nil
+
+	case GateCron:
+		sched, err := ParseCron(g.Schedule)
+		if err != nil {
+			return false, "", err
+		}
+		// Slots missed since the last run (e.g. while the daemon was down)
+		// fire once. A plugin that has never run waits for its first slot
+		// after the scheduler started.
+		from := st.LastRun
+		if from.IsZero() {
+			from = st.Since.Add(-time.Minute)
+		}
+		next := sched.Next(from)
+		if next.IsZero() || st.Now.Before(next) {
+			return false, fmt.Sprintf("next run %s", next.Format(time.RFC3339)), nil
+		}
+		return true, fmt.Sprintf("cron %q due at %s", g.Schedule, next.Format(time.RFC3339)), nil
+
+	case GateCondition:
+		if g.Check == "" {
+			return false, "", fmt.Errorf("condition gate has no check command")
+		}
+		if ok, reason := p.minInterval(st); !ok {
+			return false, reason, nil
+		}
+		if st.Check == nil {
+			return false, "condition checks disabled", nil
+		}
+		ok, err := st.Check(p)
+		if err != nil {
+			return false, "", fmt.Errorf("condition check: %w", err)
+		}
+		if !ok {
+			return false, "condition not met", nil
+		}
+		return true, "condition met", nil
+
+	case GateEvent:
+		types := g.EventTypes()
+		if len(types) == 0 {
+			return false, "", fmt.Errorf("event gate has no event types (on)")
+		}
+		if ok, reason := p.minInterval(st); !ok {
+			return false, reason, nil
+		}
+		for _, t := range types {
+			if st.Events[t] {
+				return true, fmt.Sprintf("event %s", t), nil
+			}
+		}
+		return false, "no matching events", nil
+
+	case GateManual, "":
+		return false, "manual", nil
+	}
+	return false, "", fmt.Errorf("unknown gate type %q", g.Type)
+}
+
+// minInterval applies a condition or event gate's optional duration.
+func (p *Plugin) minInterval(st GateState) (bool, string) {
+	if p.Gate.Duration == "" || st.LastRun.IsZero() {
+		return true, ""
+	}
+	d, err := time.ParseDuration(p.Gate.Duration)
+	if err != nil || st.Now.Sub(st.LastRun) >= d {
+		return true, ""
+	}
+	return false, fmt.Sprintf("ran within %s", d)
+}
+
+// EventTypes returns the event types an event gate listens for. On holds
+// one type or a comma-separated list (e.g. "merged,merge_failed").
+func (g *Gate) EventTypes() []string {
+	var types []string
+	for _, t := range strings.Split(g.On, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Scheduler evaluates plugin gates and dispatches due plugins. Beyond the
+// start time, the only state it holds is the event types each event-gated
+// plugin has seen but not yet been dispatched for: last runs come from the
+// ledger, so those decisions are the same whichever process makes them and
+// survive restarts. The hooks are the only I/O, which keeps ticks
+// deterministic.
+//
+// A dispatch is not a run. The dog records the run when it finishes
+// (gt dog done), so a dispatch that found no idle dog or failed does not
+// use up a cooldown or cron slot or consume the events that opened an event
+// gate, and a run only counts once it completed.
+type Scheduler struct {
+	// Since is when the scheduler started (see GateState.Since).
+	Since time.Time
+
+	// LastRun returns the newest recorded run of a plugin (zero if none).
+	LastRun func(name string) (time.Time, error)
+
+	// Check runs a condition gate's check command.
+	Check func(p *Plugin) (bool, error)
+
+	// Busy reports whether a dog is already running the plugin.
+	Busy func(p *Plugin) bool
+
+	// Dispatch hands the plugin to an idle dog and returns the dog's name.
+	Dispatch func(p *Plugin) (string, error)
+
+	// pending holds, per event-gated plugin, the event types it listens for
+	// that were seen since it was last dispatched.
+	pending map[string]map[string]bool
+}
+
+// Decision is the outcome of one plugin on one tick.
+type Decision struct {
+	Plugin     string
+	Dispatched bool
+	Dog        string
+	Reason     string
+	Err        error
+}
+
+// Tick evaluates every plugin's gate at now against the events seen since
+// the last tick, plus those an event-gated plugin saw on earlier ticks but
+// was not dispatched for, and dispatches the due ones. Plugins are handled
+// in the order given.
+func (s *Scheduler) Tick(plugins []*Plugin, now time.Time, events map[string]bool) []Decision {
+	var decisions []Decision
+	for _, p := range plugins {
+		if p.Gate == nil || p.Gate.Type == GateManual || p.Gate.Type == "" {
+			continue
+		}
+		decisions = append(decisions, s.tickPlugin(p, now, events))
+	}
+	return decisions
+}
+
+func (s *Scheduler) tickPlugin(p *Plugin, now time.Time, events map[string]bool) Decision {
+	d := Decision{Plugin: p.Name}
+	events = s.pendingEvents(p, events)
+
+	if s.Busy != nil && s.Busy(p) {
+		d.Reason = "already running"
+		return d
+	}
+
+	st := GateState{Now: now, Since: s.Since, Events: events, Check: s.Check}
+	if s.LastRun != nil {
+		last, err := s.LastRun(p.Name)
+		if err != nil {
+			d.Err = fmt.Errorf("reading last run: %w", err)
+			return d
+		}
+		st.LastRun = last
+	}
+
+	open, reason, err := p.GateOpen(st)
+	d.Reason = reason
+	if err != nil || !open {
+		d.Err = err
+		return d
+	}
+
+	dog, err := s.Dispatch(p)
+	if err != nil {
+		d.Err = err
+		return d
+	}
+	d.Dispatched, d.Dog = true, dog
+	delete(s.pending, pluginKey(p))
+	return d
+}
+
+// pendingEvents adds the event types an event-gated plugin listens for to
+// the ones it has not yet been dispatched for, and returns them all. Other
+// gates get events unchanged.
+func (s *Scheduler) pendingEvents(p *Plugin, events map[string]bool) map[string]bool {
+	if p.Gate.Type != GateEvent {
+		return events
+	}
+	key := pluginKey(p)
+	pending := s.pending[key]
+	for _, t := range p.Gate.EventTypes() {
+		if !events[t] {
+			continue
+		}
+		if pending == nil {
+			pending = make(map[string]bool)
+			if s.pending == nil {
+				s.pending = make(map[string]map[string]bool)
+			}
+			s.pending[key] = pending
+		}
+		pending[t] = true
+	}
+	return pending
+}
+
+// pluginKey identifies a plugin across the town and rig plugin directories.
+func pluginKey(p *Plugin) string {
+	return p.RigName + "/" + p.Name
+}
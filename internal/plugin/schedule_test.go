@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func gated(name string, gate Gate) *Plugin {
+	return &Plugin{Name: name, Gate: &gate}
+}
+
+func TestGateOpen_Cooldown(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	p := gated("sheriff", Gate{Type: GateCooldown, Duration: "5m"})
+
+	tests := []struct {
+		lastRun time.Time
+		want    bool
+	}{
+		{time.Time{}, true},
+		{now.Add(-2 * time.Minute), false},
+		{now.Add(-5 * time.Minute), true},
+	}
+	for _, tt := range tests {
+		open, reason, err := p.GateOpen(GateState{Now: now, LastRun: tt.lastRun})
+		if err != nil || open != tt.want {
+			t.Errorf("last run %v: open=%v (%s), %v; want %v", tt.lastRun, open, reason, err, tt.want)
+		}
+	}
+
+	// No duration falls back to DefaultCooldown.
+	p = gated("hourly", Gate{Type: GateCooldown})
+	if open, _, _ := p.GateOpen(GateState{Now: now, LastRun: now.Add(-30 * time.Minute)}); open {
+		t.Error("default cooldown should still be closed after 30m")
+	}
+
+	p = gated("bad", Gate{Type: GateCooldown, Duration: "soon"})
+	if _, _, err := p.GateOpen(GateState{Now: now}); err == nil {
+		t.Error("bad duration should be an error")
+	}
+}
+
+func TestGateOpen_Cron(t *testing.T) {
+	since := time.Date(2026, 3, 9, 8, 30, 0, 0, time.UTC)
+	p := gated("morning", Gate{Type: GateCron, Schedule: "0 9 * * *"})
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		lastRun time.Time
+		want    bool
+	}{
+		{"never run, before first slot", since.Add(10 * time.Minute), time.Time{}, false},
+		{"never run, at first slot", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), time.Time{}, true},
+		{"ran at today's slot", time.Date(2026, 3, 9, 9, 5, 0, 0, time.UTC), time.Date(2026, 3, 9, 9, 0, 20, 0, time.UTC), false},
+		{"missed a slot while down", since, time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		open, reason, err := p.GateOpen(GateState{Now: tt.now, LastRun: tt.lastRun, Since: since})
+		if err != nil || open != tt.want {
+			t.Errorf("%s: open=%v (%s), %v; want %v", tt.name, open, reason, err, tt.want)
+		}
+	}
+
+	p = gated("broken", Gate{Type: GateCron, Schedule: "every day"})
+	if _, _, err := p.GateOpen(GateState{Now: since, Since: since}); err == nil {
+		t.Error("bad schedule should be an error")
+	}
+}
+
+func TestGateOpen_Condition(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	p := gated("stale", Gate{Type: GateCondition, Check: "gt stale -q", Duration: "10m"})
+
+	calls := 0
+	result := true
+	check := func(*Plugin) (bool, error) {
+		calls++
+		return result, nil
+	}
+
+	if open, _, err := p.GateOpen(GateState{Now: now, Check: check}); !open || err != nil {
+		t.Errorf("condition met: open=%v, %v", open, err)
+	}
+	result = false
+	if open, _, _ := p.GateOpen(GateState{Now: now, Check: check}); open {
+		t.Error("condition not met but gate opened")
+	}
+
+	// Within the minimum interval the check isn't even run.
+	calls = 0
+	if open, _, _ := p.GateOpen(GateState{Now: now, LastRun: now.Add(-time.Minute), Check: check}); open || calls != 0 {
+		t.Errorf("min interval: open=%v, check calls=%d", open, calls)
+	}
+
+	failing := func(*Plugin) (bool, error) { return false, errors.New("sh: not found") }
+	if _, _, err := p.GateOpen(GateState{Now: now, Check: failing}); err == nil {
+		t.Error("check error should propagate")
+	}
+}
+
+func TestGateOpen_Event(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	p := gated("on-merge", Gate{Type: GateEvent, On: "merged, merge_failed"})
+
+	if got := p.Gate.EventTypes(); len(got) != 2 || got[1] != "merge_failed" {
+		t.Errorf("EventTypes = %v", got)
+	}
+	if open, _, _ := p.GateOpen(GateState{Now: now, Events: map[string]bool{"sling": true}}); open {
+		t.Error("opened on an unrelated event")
+	}
+	if open, reason, _ := p.GateOpen(GateState{Now: now, Events: map[string]bool{"merge_failed": true}}); !open {
+		t.Errorf("did not open on merge_failed: %s", reason)
+	}
+
+	startup := gated("warmup", Gate{Type: GateEvent, On: EventStartup})
+	if open, _, _ := startup.GateOpen(GateState{Now: now, Events: map[string]bool{EventStartup: true}}); !open {
+		t.Error("startup event gate did not open on startup")
+	}
+}
+
+func TestGateOpen_Manual(t *testing.T) {
+	for _, p := range []*Plugin{{Name: "none"}, gated("manual", Gate{Type: GateManual})} {
+		if open, _, err := p.GateOpen(GateState{Now: time.Now()}); open || err != nil {
+			t.Errorf("%s: open=%v, %v", p.Name, open, err)
+		}
+	}
+	if _, _, err := gated("odd", Gate{Type: "weekly"}).GateOpen(GateState{Now: time.Now()}); err == nil {
+		t.Error("unknown gate type should be an error")
+	}
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	lastRuns := map[string]time.Time{
+		"cooling": now.Add(-time.Minute),
+	}
+	var dispatched []string
+
+	s := &Scheduler{
+		Since: now.Add(-time.Hour),
+		LastRun: func(name string) (time.Time, error) {
+			return lastRuns[name], nil
+		},
+		Busy: func(p *Plugin) bool { return p.Name == "running" },
+		Dispatch: func(p *Plugin) (string, error) {
+			switch p.Name {
+			case "no-dogs":
+				return "", ErrNoIdleDog
+			case "broken":
+				return "", errors.New("gt: command not found")
+			}
+			dispatched = append(dispatched, p.Name)
+			return "alpha", nil
+		},
+	}
+
+	plugins := []*Plugin{
+		{Name: "manual"},
+		gated("due", Gate{Type: GateCooldown, Duration: "5m"}),
+		gated("cooling", Gate{Type: GateCooldown, Duration: "5m"}),
+		gated("running", Gate{Type: GateCooldown, Duration: "5m"}),
+		gated("no-dogs", Gate{Type: GateCooldown, Duration: "5m"}),
+		gated("broken", Gate{Type: GateCooldown, Duration: "5m"}),
+	}
+	plugins[1].RigName = "gastown"
+
+	decisions := s.Tick(plugins, now, nil)
+	if len(decisions) != 5 {
+		t.Fatalf("got %d decisions, want 5 (manual plugins are not scheduled)", len(decisions))
+	}
+	byName := map[string]Decision{}
+	for _, d := range decisions {
+		byName[d.Plugin] = d
+	}
+
+	if d := byName["due"]; !d.Dispatched || d.Dog != "alpha" || d.Err != nil {
+		t.Errorf("due: %+v", d)
+	}
+	if d := byName["cooling"]; d.Dispatched || d.Err != nil {
+		t.Errorf("cooling: %+v", d)
+	}
+	if d := byName["running"]; d.Dispatched || d.Reason != "already running" {
+		t.Errorf("running: %+v", d)
+	}
+	if d := byName["no-dogs"]; d.Dispatched || !errors.Is(d.Err, ErrNoIdleDog) {
+		t.Errorf("no-dogs: %+v", d)
+	}
+	if d := byName["broken"]; d.Dispatched || d.Err == nil {
+		t.Errorf("broken: %+v", d)
+	}
+
+	if len(dispatched) != 1 || dispatched[0] != "due" {
+		t.Errorf("dispatched = %v", dispatched)
+	}
+
+	// Skipped and failed dispatches leave no run behind, so the next tick
+	// retries them instead of waiting out the cooldown.
+	again := s.Tick(plugins[4:], now.Add(time.Minute), nil)
+	for _, d := range again {
+		if d.Reason != "cooldown 5m0s elapsed" {
+			t.Errorf("%s on retry: %+v", d.Plugin, d)
+		}
+	}
+}
+
+func TestScheduler_TickKeepsEventsUntilDispatched(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	idle := false
+	var dispatched []string
+
+	s := &Scheduler{
+		Since: now.Add(-time.Hour),
+		Dispatch: func(p *Plugin) (string, error) {
+			if !idle {
+				return "", ErrNoIdleDog
+			}
+			dispatched = append(dispatched, p.Name)
+			return "alpha", nil
+		},
+	}
+	plugins := []*Plugin{
+		gated("on-merge", Gate{Type: GateEvent, On: "merged"}),
+		gated("warmup", Gate{Type: GateEvent, On: EventStartup}),
+	}
+
+	// Both gates open on the first tick, but no dog is idle.
+	for _, d := range s.Tick(plugins, now, map[string]bool{"merged": true, EventStartup: true}) {
+		if d.Dispatched || !errors.Is(d.Err, ErrNoIdleDog) {
+			t.Errorf("%s while dogs are busy: %+v", d.Plugin, d)
+		}
+	}
+
+	// The events are not seen again, but the gates stay open until a
+	// dispatch succeeds.
+	idle = true
+	for _, d := range s.Tick(plugins, now.Add(time.Minute), nil) {
+		if !d.Dispatched {
+			t.Errorf("%s on retry: %+v", d.Plugin, d)
+		}
+	}
+	if len(dispatched) != 2 {
+		t.Errorf("dispatched = %v, want both plugins", dispatched)
+	}
+
+	// A successful dispatch consumes the events.
+	for _, d := range s.Tick(plugins, now.Add(2*time.Minute), nil) {
+		if d.Dispatched || d.Reason != "no matching events" {
+			t.Errorf("%s after dispatch: %+v", d.Plugin, d)
+		}
+	}
+}
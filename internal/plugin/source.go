@@ -0,0 +1,404 @@
+package plugin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourceKind is where an installed plugin came from.
+type SourceKind string
+
+const (
+	// SourcePath is a plugin directory on the local filesystem.
+	SourcePath SourceKind = "path"
+
+	// SourceGit is a git repository, optionally with a subdirectory.
+	SourceGit SourceKind = "git"
+
+	// SourceArchive is a local .tar.gz, .tgz, .tar or .zip file.
+	SourceArchive SourceKind = "archive"
+)
+
+// Source is a parsed install source.
+type Source struct {
+	Kind SourceKind
+
+	// Location is the absolute path (path, archive) or the clone URL (git).
+	Location string
+
+	// Subdir is the plugin's directory inside a git repository, given
+	// after '#' (e.g., "https://github.com/org/repo.git#plugins/sheriff").
+	Subdir string
+}
+
+// String returns the source in the form ParseSource accepts.
+func (s Source) String() string {
+	loc := s.Location
+	if s.Kind == SourceGit && !isGitSource(loc) {
+		loc = "git+" + loc // e.g. git+file:///srv/plugins
+	}
+	if s.Subdir != "" {
+		return loc + "#" + s.Subdir
+	}
+	return loc
+}
+
+// ParseSource classifies an install source. Git URLs are recognized by
+// scheme (https://, ssh://, git://, git@host:), a "git+" prefix or a .git
+// suffix; local files ending in an archive extension are archives; anything
+// else is a plugin directory.
+func ParseSource(raw string) (Source, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Source{}, fmt.Errorf("empty plugin source")
+	}
+	if strings.HasPrefix(raw, "-") {
+		return Source{}, fmt.Errorf("invalid plugin source %q", raw)
+	}
+
+	if isGitSource(raw) {
+		loc, subdir, _ := strings.Cut(strings.TrimPrefix(raw, "git+"), "#")
+		subdir = strings.Trim(filepath.ToSlash(subdir), "/")
+		if subdir != "" && !filepath.IsLocal(subdir) {
+			return Source{}, fmt.Errorf("subdirectory %q escapes the repository", subdir)
+		}
+		return Source{Kind: SourceGit, Location: loc, Subdir: subdir}, nil
+	}
+
+	path, err := filepath.Abs(strings.TrimPrefix(raw, "file://"))
+	if err != nil {
+		return Source{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Source{}, fmt.Errorf("plugin source %s: %w", raw, err)
+	}
+	if !info.IsDir() {
+		if archiveFormat(path) == "" {
+			return Source{}, fmt.Errorf("plugin source %s is neither a directory nor an archive (.tar.gz, .tgz, .tar, .zip)", raw)
+		}
+		return Source{Kind: SourceArchive, Location: path}, nil
+	}
+	return Source{Kind: SourcePath, Location: path}, nil
+}
+
+func isGitSource(s string) bool {
+	if strings.HasPrefix(s, "git+") {
+		return true
+	}
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "git@"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	loc, _, _ := strings.Cut(s, "#")
+	return strings.HasSuffix(loc, ".git") && !strings.HasPrefix(s, "/") && !strings.HasPrefix(s, ".")
+}
+
+func archiveFormat(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	}
+	return ""
+}
+
+// fetch makes the source available under workDir and returns the plugin
+// directory and, for git sources, the commit that was fetched.
+func (s Source) fetch(workDir string) (dir, commit string, err error) {
+	switch s.Kind {
+	case SourcePath:
+		dir = s.Location
+	case SourceGit:
+		dir = filepath.Join(workDir, "repo")
+		if commit, err = gitFetch(s.Location, dir); err != nil {
+			return "", "", err
+		}
+		if s.Subdir != "" {
+			dir = filepath.Join(dir, filepath.FromSlash(s.Subdir))
+		}
+	case SourceArchive:
+		dir = filepath.Join(workDir, "archive")
+		if err = extractArchive(s.Location, dir); err != nil {
+			return "", "", fmt.Errorf("extracting %s: %w", s.Location, err)
+		}
+	default:
+		return "", "", fmt.Errorf("unknown plugin source kind %q", s.Kind)
+	}
+
+	dir, err = findPluginDir(dir)
+	return dir, commit, err
+}
+
+// gitFetch shallow-clones url into dest and returns the HEAD commit.
+func gitFetch(url, dest string) (string, error) {
+	cmd := exec.Command("git", "clone", "--depth", "1", "--quiet", url, dest) //nolint:gosec // G204: url was validated by ParseSource
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git clone %s: %w (%s)", url, err, strings.TrimSpace(stderr.String()))
+	}
+	out, err := exec.Command("git", "-C", dest, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// findPluginDir returns dir if it holds a plugin.md, or its only
+// subdirectory that does (archives usually wrap the plugin in a folder).
+func findPluginDir(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "plugin.md")); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var found []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), "plugin.md")); err == nil {
+			found = append(found, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(found) != 1 {
+		return "", fmt.Errorf("no plugin.md found in %s", dir)
+	}
+	return found[0], nil
+}
+
+// extractArchive unpacks a tar, gzipped tar or zip archive into dest.
+// Entries that would land outside dest, and links, are rejected.
+func extractArchive(path, dest string) error {
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	format := archiveFormat(path)
+	if format == "zip" {
+		return extractZip(path, dest)
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: path is the archive the user asked to install
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if format == "tgz" {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := archiveTarget(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, tr, fs.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			// pax metadata, no content
+		default:
+			return fmt.Errorf("unsupported archive entry %s (links are not allowed)", hdr.Name)
+		}
+	}
+}
+
+func extractZip(path, dest string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		target, err := archiveTarget(dest, zf.Name)
+		if err != nil {
+			return err
+		}
+		mode := zf.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			err = writeArchiveFile(target, rc, mode)
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported archive entry %s (links are not allowed)", zf.Name)
+		}
+	}
+	return nil
+}
+
+// archiveTarget maps an archive entry name to a path under dest.
+func archiveTarget(dest, name string) (string, error) {
+	clean := filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if clean == "" || clean == "." {
+		return dest, nil
+	}
+	if !filepath.IsLocal(clean) {
+		return "", fmt.Errorf("archive entry %q escapes the destination", name)
+	}
+	return filepath.Join(dest, clean), nil
+}
+
+func writeArchiveFile(target string, r io.Reader, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fileMode(mode)) //nolint:gosec // G304: target is confined to dest by archiveTarget
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec // G110: plugin archives are small and chosen by the user
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fileMode keeps only the executable bit: installed plugin files are 0644
+// or 0755 whatever the source said.
+func fileMode(mode fs.FileMode) fs.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
+
+// pluginFiles lists the regular files of a plugin directory as slash
+// paths relative to dir, sorted. .git directories are skipped; symlinks
+// and other special files are an error, so what is hashed is exactly what
+// gets copied.
+func pluginFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("%s: only regular files are allowed in a plugin", path)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// HashDir returns the content hash of a plugin directory ("sha256:<hex>").
+// It covers each file's relative path, executable bit and contents, so it
+// is the same wherever the plugin is installed.
+func HashDir(dir string) (string, error) {
+	files, err := pluginFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, rel := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%o\x00%d\x00", rel, fileMode(info.Mode()), info.Size())
+		f, err := os.Open(path) //nolint:gosec // G304: path is inside the plugin directory
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyPlugin copies a plugin directory's files into dst.
+func copyPlugin(dst, src string) error {
+	files, err := pluginFiles(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, rel := range files {
+		from := filepath.Join(src, filepath.FromSlash(rel))
+		info, err := os.Stat(from)
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(from) //nolint:gosec // G304: path is inside the plugin directory
+		if err != nil {
+			return err
+		}
+		err = writeArchiveFile(filepath.Join(dst, filepath.FromSlash(rel)), in, info.Mode())
+		_ = in.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
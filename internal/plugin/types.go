@@ -18,7 +18,8 @@ type Plugin struct {
 	// Description is a human-readable description.
 	Description string `json:"description"`
 
-	// Version is the schema version (for future evolution).
+	// Version is the plugin's version. 'gt plugin upgrade' won't replace
+	// an installed plugin with a lower one.
 	Version int `json:"version"`
 
 	// Location indicates where the plugin was discovered.
@@ -39,6 +40,10 @@ type Plugin struct {
 	// Execution defines timeout and notification settings.
 	Execution *Execution `json:"execution,omitempty"`
 
+	// Requires declares binaries, environment and permissions the plugin
+	// needs; checked by 'gt plugin install'.
+	Requires *Requires `json:"requires,omitempty"`
+
 	// Instructions is the markdown body (after frontmatter).
 	Instructions string `json:"instructions,omitempty"`
 }
@@ -113,6 +118,20 @@ type Execution struct {
 	Severity string `json:"severity,omitempty" toml:"severity,omitempty"`
 }
 
+// Requires is the plugin manifest: what a plugin needs from the town it is
+// installed into.
+type Requires struct {
+	// Binaries must be on PATH (e.g., "gh", "jq").
+	Binaries []string `json:"binaries,omitempty" toml:"binaries,omitempty"`
+
+	// Env lists environment variables that must be set.
+	Env []string `json:"env,omitempty" toml:"env,omitempty"`
+
+	// Permissions are the commands the plugin runs (e.g., "gh pr list",
+	// "bd create"), evaluated against the town's policy.
+	Permissions []string `json:"permissions,omitempty" toml:"permissions,omitempty"`
+}
+
 // PluginFrontmatter represents the TOML frontmatter in plugin.md files.
 type PluginFrontmatter struct {
 	Name        string     `toml:"name"`
@@ -121,6 +140,7 @@ type PluginFrontmatter struct {
 	Gate        *Gate      `toml:"gate,omitempty"`
 	Tracking    *Tracking  `toml:"tracking,omitempty"`
 	Execution   *Execution `toml:"execution,omitempty"`
+	Requires    *Requires  `toml:"requires,omitempty"`
 }
 
 // PluginSummary provides a concise overview of a plugin.
@@ -0,0 +1,93 @@
+package polecat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// BranchChanges is the set of files a working polecat has changed.
+type BranchChanges struct {
+	Name   string   `json:"name"`
+	Issue  string   `json:"issue,omitempty"`
+	Branch string   `json:"branch"`
+	Files  []string `json:"files"`
+}
+
+// ActiveChanges returns, for each working polecat, the files its branch has
+// changed since forking from origin/<default branch>, plus any uncommitted
+// edits in its worktree. Polecats that haven't changed anything are omitted.
+func (m *Manager) ActiveChanges() ([]*BranchChanges, error) {
+	polecats, err := m.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing polecats: %w", err)
+	}
+
+	base := "origin/" + m.defaultBranch()
+	var results []*BranchChanges
+	for _, p := range polecats {
+		if p.State != StateWorking {
+			continue
+		}
+
+		polecatGit := git.NewGit(p.ClonePath)
+		seen := make(map[string]bool)
+		if committed, err := polecatGit.BranchChangedFiles(base, "HEAD"); err == nil {
+			for _, f := range committed {
+				seen[f] = true
+			}
+		}
+		if status, err := polecatGit.Status(); err == nil {
+			for _, list := range [][]string{status.Modified, status.Added, status.Deleted, status.Untracked} {
+				for _, f := range list {
+					if !strings.HasPrefix(f, ".beads/") { // shared beads redirect, not work
+						seen[f] = true
+					}
+				}
+			}
+		}
+		if len(seen) == 0 {
+			continue
+		}
+
+		files := make([]string, 0, len(seen))
+		for f := range seen {
+			files = append(files, f)
+		}
+		sort.Strings(files)
+		results = append(results, &BranchChanges{
+			Name:   p.Name,
+			Issue:  p.Issue,
+			Branch: p.Branch,
+			Files:  files,
+		})
+	}
+
+	return results, nil
+}
+
+// BranchFiles returns the files branch changes relative to origin/<default
+// branch>, looked up in the rig's shared repo. The branch may be local (a
+// polecat worktree's) or only on origin (a pushed MR branch).
+func (m *Manager) BranchFiles(branch string) ([]string, error) {
+	repo, err := m.repoBase()
+	if err != nil {
+		return nil, err
+	}
+	ref := branch
+	if exists, err := repo.BranchExists(ref); err != nil || !exists {
+		ref = "origin/" + branch
+	}
+	return repo.BranchChangedFiles("origin/"+m.defaultBranch(), ref)
+}
+
+// defaultBranch returns the rig's default branch from its config, or "main".
+func (m *Manager) defaultBranch() string {
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		return rigCfg.DefaultBranch
+	}
+	return "main"
+}
@@ -9,7 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // PendingSpawn represents a polecat that has been spawned but not yet triggered.
@@ -116,13 +116,13 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 		return nil, nil
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	var results []TriggerResult
 
 	for _, ps := range pending {
 		result := TriggerResult{Spawn: ps}
 
-		// Check if session still exists (ZFC: query tmux directly)
+		// Check if session still exists (ZFC: query the session backend directly)
 		running, err := t.HasSession(ps.Session)
 		if err != nil {
 			result.Error = fmt.Errorf("checking session: %w", err)
@@ -143,7 +143,7 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 		// Check if runtime is ready (non-blocking poll)
 		rigPath := filepath.Join(townRoot, ps.Rig)
 		runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, rigPath)
-		err = t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout)
+		err = session.WaitForRuntimeReady(t, ps.Session, runtimeConfig, timeout)
 		if err != nil {
 			// Not ready yet - leave mail in inbox for next poll
 			result.Skipped = true
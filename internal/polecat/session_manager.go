@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
@@ -20,6 +21,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -40,12 +42,13 @@ var (
 
 // SessionManager handles polecat session lifecycle.
 type SessionManager struct {
-	tmux *tmux.Tmux
+	tmux session.SessionBackend
 	rig  *rig.Rig
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
-func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
+// t is the session backend, usually session.NewBackend(townRoot).
+func NewSessionManager(t session.SessionBackend, r *rig.Rig) *SessionManager {
 	return &SessionManager{
 		tmux: t,
 		rig:  r,
@@ -73,6 +76,15 @@ type SessionStartOptions struct {
 	// DoltBranch is the polecat-specific Dolt branch for write isolation.
 	// If set, BD_BRANCH env var is injected into the polecat session.
 	DoltBranch string
+
+	// TraceParent is the W3C traceparent of the spawn. If set and tracing is
+	// configured, GT_TRACEPARENT and the exporter settings are injected so
+	// gt commands in the session continue the trace.
+	TraceParent string
+
+	// Resume restarts a crashed session: the agent is told what it was doing
+	// and, on the first attempt, picks up its previous conversation.
+	Resume *checkpoint.Resume
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -248,11 +260,20 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		IncludePrimeInstruction: fallbackInfo.IncludePrimeInBeacon,
 		ExcludeWorkInstructions: fallbackInfo.SendStartupNudge,
 	}
+	if opts.Resume != nil {
+		beaconConfig.Topic = "resumed"
+	}
 	beacon := session.FormatStartupBeacon(beaconConfig)
 
 	command := opts.Command
-	if command == "" {
+	switch {
+	case command != "":
+	case opts.Resume == nil:
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
+	case opts.Resume.ContinueConversation():
+		command = config.BuildPolecatResumeCommand(m.rig.Name, polecat, m.rig.Path, opts.Resume.SessionID(), beacon+"\n\n"+opts.Resume.Prompt())
+	default:
+		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon+"\n\n"+opts.Resume.Prompt())
 	}
 	// Prepend runtime config dir env if needed
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
@@ -268,6 +289,13 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// under concurrent load (gt-5cc2p). Changes merge at gt done time.
 	command = config.PrependEnv(command, map[string]string{"BD_DOLT_AUTO_COMMIT": "off"})
 
+	// Continue the dispatching trace inside the session.
+	if opts.TraceParent != "" {
+		if traceEnv := telemetry.SessionEnv(telemetry.WithTraceParent(context.Background(), opts.TraceParent)); len(traceEnv) > 0 {
+			command = config.PrependEnv(command, traceEnv)
+		}
+	}
+
 	// FIX (ga-6s284): Prepend GT_RIG, GT_POLECAT, GT_ROLE to startup command
 	// so they're inherited by Kimi and other agents. Setting via tmux.SetEnvironment
 	// after session creation doesn't work for all agent types.
@@ -346,13 +374,14 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		}
 	}
 
-	// Apply theme (non-fatal)
-	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	// Apply theme and set pane-died hook for crash detection (tmux only, non-fatal)
+	if realTmux, ok := m.tmux.(*tmux.Tmux); ok {
+		theme := tmux.AssignTheme(m.rig.Name)
+		debugSession("ConfigureGasTownSession", realTmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
-	// Set pane-died hook for crash detection (non-fatal)
-	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
-	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
+		agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
+		debugSession("SetPaneDiedHook", realTmux.SetPaneDiedHook(sessionID, agentID))
+	}
 
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
@@ -457,8 +486,11 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	status := m.tmux.CheckSessionHealth(sessionID, 0)
-	return status == tmux.SessionHealthy, nil
+	running, err := m.tmux.HasSession(sessionID)
+	if err != nil || !running {
+		return false, nil
+	}
+	return m.tmux.IsAgentAlive(sessionID), nil
 }
 
 // Status returns detailed status for a polecat session.
@@ -576,7 +608,11 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
-	return m.tmux.AttachSession(sessionID)
+	realTmux, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return fmt.Errorf("session %s is headless and can't be attached; use gt peek to see its output", sessionID)
+	}
+	return realTmux.AttachSession(sessionID)
 }
 
 // Capture returns the recent output from a polecat session.
@@ -624,7 +660,10 @@ func (m *SessionManager) Inject(polecat, message string) error {
 		debounceMs = 1500
 	}
 
-	return m.tmux.SendKeysDebounced(sessionID, message, debounceMs)
+	if realTmux, ok := m.tmux.(*tmux.Tmux); ok {
+		return realTmux.SendKeysDebounced(sessionID, message, debounceMs)
+	}
+	return m.tmux.NudgeSession(sessionID, message)
 }
 
 // StopAll terminates all polecat sessions for this rig.
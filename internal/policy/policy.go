@@ -40,6 +40,10 @@ type EvalRequest struct {
 	Args        []string  `json:"args,omitempty"`
 	RequestedBy string    `json:"requested_by,omitempty"`
 	Timestamp   time.Time `json:"timestamp,omitempty"`
+
+	// DryRun evaluates without counting the request against rate limits.
+	// Set it when the command won't actually run (previews, approvals).
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // EvalResult describes the policy decision for a command.
@@ -59,12 +63,13 @@ type Document struct {
 
 // Rule defines a conditional override.
 type Rule struct {
-	ID       string     `json:"id"`
-	Decision Decision   `json:"decision"`
-	Reason   string     `json:"reason,omitempty"`
-	Match    RuleMatch  `json:"match"`
-	Enabled  *bool      `json:"enabled,omitempty"`
-	Until    *time.Time `json:"until,omitempty"`
+	ID        string     `json:"id"`
+	Decision  Decision   `json:"decision"`
+	Reason    string     `json:"reason,omitempty"`
+	Match     RuleMatch  `json:"match"`
+	Enabled   *bool      `json:"enabled,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
 }
 
 // RuleMatch defines command metadata predicates.
@@ -74,11 +79,46 @@ type RuleMatch struct {
 	CommandPrefixes []string    `json:"command_prefixes,omitempty"`
 	CommandRegex    string      `json:"command_regex,omitempty"`
 	Classes         []RiskClass `json:"classes,omitempty"`
+
+	// Args are per-argument predicates; all must hold.
+	Args []ArgMatch `json:"args,omitempty"`
+
+	// Windows restricts the rule to times inside one of the windows;
+	// ExceptWindows to times outside all of them.
+	Windows       []TimeWindow `json:"windows,omitempty"`
+	ExceptWindows []TimeWindow `json:"except_windows,omitempty"`
+}
+
+// ArgMatch is a predicate on a command's arguments, e.g. {"flag": "--force"}
+// or {"flag": "--rig", "value": "prod-*"}.
+type ArgMatch struct {
+	// Flag is matched as "--flag", "--flag=value" or "--flag value".
+	Flag string `json:"flag,omitempty"`
+	// Value is a pattern (exact, "*", "prefix*", "*.suffix") for the flag's
+	// value, or for any argument when Flag is empty.
+	Value string `json:"value,omitempty"`
+	// Absent inverts the predicate: it holds when no argument matches.
+	Absent bool `json:"absent,omitempty"`
+}
+
+// TimeWindow is a recurring weekly window, e.g. weekdays 09:00-17:00 in a
+// time zone. A window whose End is before its Start runs past midnight.
+type TimeWindow struct {
+	Days     []string `json:"days,omitempty"`     // mon..sun, "weekdays", "weekends"; empty means every day
+	Start    string   `json:"start,omitempty"`    // "HH:MM", inclusive; empty means 00:00
+	End      string   `json:"end,omitempty"`      // "HH:MM", exclusive; empty means 24:00
+	Timezone string   `json:"timezone,omitempty"` // IANA name; empty means UTC
 }
 
 // Evaluator evaluates commands against default classification + document rules.
 type Evaluator struct {
-	doc *Document
+	doc   *Document
+	rates RateStore
+
+	// unusable is set when the policy document exists but can't be read or
+	// parsed. Every command is then denied rather than evaluated without the
+	// operator's rules.
+	unusable error
 }
 
 // NewDefaultEvaluator creates an evaluator with default rule behavior.
@@ -99,8 +139,19 @@ func DefaultPolicyPath(townRoot string) string {
 	return filepath.Join(townRoot, "mayor", "policy.json")
 }
 
-// LoadDocument loads a policy document from disk.
+// LoadDocument loads a policy document from disk and validates it.
 func LoadDocument(path string) (*Document, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func readDocument(path string) (*Document, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -115,17 +166,90 @@ func LoadDocument(path string) (*Document, error) {
 	return &doc, nil
 }
 
-// LoadOrDefault returns a default evaluator when policy file is missing.
-func LoadOrDefault(townRoot string) *Evaluator {
+// Validate checks rule predicates that can only fail at evaluation time:
+// regexes, time windows and rate limits.
+func (d *Document) Validate() error {
+	for _, rule := range d.Rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rule Rule) validate() error {
+	if rule.Match.CommandRegex != "" {
+		if _, err := regexp.Compile(rule.Match.CommandRegex); err != nil {
+			return fmt.Errorf("rule %q: invalid command_regex: %w", rule.ID, err)
+		}
+	}
+	for _, w := range append(append([]TimeWindow{}, rule.Match.Windows...), rule.Match.ExceptWindows...) {
+		if _, err := w.Contains(time.Now()); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.ID, err)
+		}
+	}
+	for _, a := range rule.Match.Args {
+		if a.Flag == "" && a.Value == "" {
+			return fmt.Errorf("rule %q: args matcher needs a flag or a value", rule.ID)
+		}
+	}
+	if rule.RateLimit != nil {
+		if err := rule.RateLimit.validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// LoadOrDefault loads the town's policy document, returning the default
+// evaluator when there is none. It never fails open:
+//
+//   - Rules that don't validate are left out and reported in the error;
+//     the valid rules are still enforced.
+//   - A document that can't be read or parsed gives an evaluator that
+//     denies every command, with the error.
+//
+// Callers should surface the error to the operator.
+func LoadOrDefault(townRoot string) (*Evaluator, error) {
 	path := DefaultPolicyPath(townRoot)
-	doc, err := LoadDocument(path)
+	doc, err := readDocument(path)
+	if os.IsNotExist(err) {
+		return NewDefaultEvaluator(), nil
+	}
 	if err != nil {
-		return NewDefaultEvaluator()
+		err = fmt.Errorf("policy %s unusable, denying all commands: %w", path, err)
+		return &Evaluator{doc: &Document{Version: 1}, unusable: err}, err
+	}
+
+	var valid []Rule
+	var problems []string
+	for _, rule := range doc.Rules {
+		if err := rule.validate(); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		valid = append(valid, rule)
+	}
+	doc.Rules = valid
+	if len(problems) > 0 {
+		return NewEvaluator(doc), fmt.Errorf("policy %s: ignoring invalid rules: %s", path, strings.Join(problems, "; "))
 	}
-	return NewEvaluator(doc)
+	return NewEvaluator(doc), nil
+}
+
+// WithRateStore sets the store rate-limited rules count requests in and
+// returns the evaluator. Without one, rate-limited rules never trigger.
+func (e *Evaluator) WithRateStore(store RateStore) *Evaluator {
+	e.rates = store
+	return e
 }
 
 // Evaluate applies risk classification and policy rules to a command.
+//
+// Rate-limited rules let matching requests through until their quota is
+// used up. A request that passes through is counted once the final decision
+// lets it run straight away (allow, or allow with justification), unless
+// req.DryRun is set.
 func (e *Evaluator) Evaluate(req EvalRequest) EvalResult {
 	class, classReason := ClassifyCommand(req.Command, req.Args)
 	baseDecision := defaultDecisionForClass(class)
@@ -135,6 +259,11 @@ func (e *Evaluator) Evaluate(req EvalRequest) EvalResult {
 		Reason:   classReason,
 	}
 
+	if e != nil && e.unusable != nil {
+		result.Decision = DecisionDeny
+		result.Reason = e.unusable.Error()
+		return result
+	}
 	if e == nil || e.doc == nil || len(e.doc.Rules) == 0 {
 		return result
 	}
@@ -144,24 +273,74 @@ func (e *Evaluator) Evaluate(req EvalRequest) EvalResult {
 		now = time.Now().UTC()
 	}
 
+	if e.rates == nil {
+		return e.decide(req, result, now, nil, nil)
+	}
+	// Check quotas and record this request under one lock, so concurrent
+	// requests can't all pass the same "max N" check.
+	decided := result
+	err := e.rates.Update(func(ledger *RateLedger) error {
+		decided = e.decide(req, result, now, ledger, nil)
+		return nil
+	})
+	if err != nil {
+		// Unreadable rate state: quotas can't be checked, so requests a
+		// rate-limited rule matches are denied rather than let through.
+		return e.decide(req, result, now, nil, err)
+	}
+	return decided
+}
+
+// decide applies the document's rules to req on top of the classification
+// in result, counting quotas in ledger (nil: no quotas). A non-nil rateErr
+// means the quota state couldn't be read: the first matching rate-limited
+// rule denies the request.
+func (e *Evaluator) decide(req EvalRequest, result EvalResult, now time.Time, ledger *RateLedger, rateErr error) EvalResult {
+	var counted []rateHit
+	matched := false
 	for _, rule := range e.doc.Rules {
 		if !ruleEnabled(rule, now) {
 			continue
 		}
-		if !ruleMatches(rule.Match, req, class) {
+		if !ruleMatches(rule.Match, req, result.Class, now) {
 			continue
 		}
+		if rule.RateLimit != nil && rateErr != nil {
+			result.Decision = DecisionDeny
+			result.RuleID = rule.ID
+			result.Reason = fmt.Sprintf("rate limit %s can't be checked: %v", rule.RateLimit, rateErr)
+			matched = true
+			break
+		}
+		if rule.RateLimit != nil {
+			hit, exceeded := checkRate(ledger, rule, req, now)
+			if !exceeded {
+				if hit.key != "" {
+					counted = append(counted, hit)
+				}
+				continue
+			}
+			result.Reason = fmt.Sprintf("rate limit exceeded: %s", rule.RateLimit)
+		}
 		result.Decision = rule.Decision
 		result.RuleID = rule.ID
 		if rule.Reason != "" {
 			result.Reason = rule.Reason
 		}
-		return result
+		matched = true
+		break
 	}
 
-	if e.doc.DefaultDecision != "" {
+	if !matched && e.doc.DefaultDecision != "" {
 		result.Decision = e.doc.DefaultDecision
 	}
+
+	runsNow := result.Decision == DecisionAllow || result.Decision == DecisionAllowWithJustification
+	if !req.DryRun && runsNow {
+		for _, hit := range counted {
+			ledger.Record(hit.key, now, hit.window)
+		}
+	}
 	return result
 }
 
@@ -190,7 +369,7 @@ func ruleEnabled(rule Rule, now time.Time) bool {
 	return true
 }
 
-func ruleMatches(match RuleMatch, req EvalRequest, class RiskClass) bool {
+func ruleMatches(match RuleMatch, req EvalRequest, class RiskClass, now time.Time) bool {
 	if len(match.Classes) > 0 && !containsClass(match.Classes, class) {
 		return false
 	}
@@ -224,9 +403,152 @@ func ruleMatches(match RuleMatch, req EvalRequest, class RiskClass) bool {
 		}
 	}
 
+	if len(match.Args) > 0 {
+		args := req.Args
+		if len(args) == 0 {
+			args = strings.Fields(req.Command)
+		}
+		for _, a := range match.Args {
+			if a.matches(args) == a.Absent {
+				return false
+			}
+		}
+	}
+
+	if len(match.Windows) > 0 && !inAnyWindow(match.Windows, now) {
+		return false
+	}
+	if len(match.ExceptWindows) > 0 && inAnyWindow(match.ExceptWindows, now) {
+		return false
+	}
+
 	return true
 }
 
+// matches reports whether any argument satisfies the matcher, ignoring Absent.
+func (a ArgMatch) matches(args []string) bool {
+	for i, arg := range args {
+		if a.Flag == "" {
+			if matchesPatternList([]string{a.Value}, arg) {
+				return true
+			}
+			continue
+		}
+		if arg == a.Flag {
+			if a.Value == "" {
+				return true
+			}
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") && matchesPatternList([]string{a.Value}, args[i+1]) {
+				return true
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(arg, a.Flag+"="); ok {
+			if a.Value == "" || matchesPatternList([]string{a.Value}, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inAnyWindow reports whether t falls in one of the windows. Invalid windows
+// never contain anything.
+func inAnyWindow(windows []TimeWindow, t time.Time) bool {
+	for _, w := range windows {
+		if ok, err := w.Contains(t); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether t falls inside the window.
+func (w TimeWindow) Contains(t time.Time) (bool, error) {
+	loc := time.UTC
+	if w.Timezone != "" {
+		l, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return false, fmt.Errorf("invalid window timezone %q: %w", w.Timezone, err)
+		}
+		loc = l
+	}
+	start, err := parseClock(w.Start, 0)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(w.End, 24*60)
+	if err != nil {
+		return false, err
+	}
+	days, err := parseDays(w.Days)
+	if err != nil {
+		return false, err
+	}
+
+	t = t.In(loc)
+	minute := t.Hour()*60 + t.Minute()
+	today := days[t.Weekday()]
+	if start <= end {
+		return today && minute >= start && minute < end, nil
+	}
+	// Overnight: the part after midnight belongs to the previous day's window.
+	yesterday := days[(t.Weekday()+6)%7]
+	return (today && minute >= start) || (yesterday && minute < end), nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window time %q (want HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+var dayNames = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+// parseDays returns the set of weekdays a window covers.
+func parseDays(names []string) ([7]bool, error) {
+	var days [7]bool
+	if len(names) == 0 {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+	for _, name := range names {
+		n := strings.ToLower(strings.TrimSpace(name))
+		if len(n) > 3 && dayNames[n] == nil {
+			n = n[:3] // "monday" -> "mon"
+		}
+		wds, ok := dayNames[n]
+		if !ok {
+			return days, fmt.Errorf("invalid window day %q", name)
+		}
+		for _, wd := range wds {
+			days[wd] = true
+		}
+	}
+	return days, nil
+}
+
 func containsClass(classes []RiskClass, target RiskClass) bool {
 	for _, c := range classes {
 		if c == target {
@@ -1,6 +1,13 @@
 package policy
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestClassifyCommand(t *testing.T) {
 	t.Parallel()
@@ -60,3 +67,345 @@ func TestEvaluateRuleOverride(t *testing.T) {
 		t.Fatalf("rule id = %q, want override rule", got.RuleID)
 	}
 }
+
+func TestEvaluateArgMatch(t *testing.T) {
+	t.Parallel()
+
+	e := NewEvaluator(&Document{
+		Version: 1,
+		Rules: []Rule{
+			{
+				ID:       "no-forced-nukes",
+				Decision: DecisionDeny,
+				Match: RuleMatch{
+					CommandPrefixes: []string{"gt polecat nuke"},
+					Args:            []ArgMatch{{Flag: "--force"}},
+				},
+			},
+			{
+				ID:       "prod-rigs-need-approval",
+				Decision: DecisionRequireApproval,
+				Match: RuleMatch{
+					Args: []ArgMatch{{Flag: "--rig", Value: "prod-*"}},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		command string
+		want    Decision
+	}{
+		{"gt polecat nuke Toast", DecisionAllowWithJustification},
+		{"gt polecat nuke Toast --force", DecisionDeny},
+		{"gt polecat nuke --force=true Toast", DecisionDeny},
+		{"gt sling gt-abc --rig prod-api", DecisionRequireApproval},
+		{"gt sling gt-abc --rig=prod-api", DecisionRequireApproval},
+		{"gt sling gt-abc --rig staging", DecisionAllowWithJustification},
+	}
+	for _, tt := range tests {
+		if got := e.Evaluate(EvalRequest{Command: tt.command}); got.Decision != tt.want {
+			t.Errorf("Evaluate(%q) = %s (rule %q), want %s", tt.command, got.Decision, got.RuleID, tt.want)
+		}
+	}
+}
+
+func TestEvaluateTimeWindows(t *testing.T) {
+	t.Parallel()
+
+	e := NewEvaluator(&Document{
+		Version: 1,
+		Rules: []Rule{
+			{
+				ID:       "no-sensitive-after-hours",
+				Decision: DecisionDeny,
+				Match: RuleMatch{
+					Classes:       []RiskClass{Class2Sensitive},
+					ExceptWindows: []TimeWindow{{Days: []string{"weekdays"}, Start: "09:00", End: "17:00"}},
+				},
+			},
+		},
+	})
+
+	// 2026-03-04 is a Wednesday, 2026-03-07 a Saturday.
+	tests := []struct {
+		at   time.Time
+		want Decision
+	}{
+		{time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), DecisionRequireApproval},
+		{time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC), DecisionDeny},
+		{time.Date(2026, 3, 4, 8, 59, 0, 0, time.UTC), DecisionDeny},
+		{time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC), DecisionDeny},
+	}
+	for _, tt := range tests {
+		got := e.Evaluate(EvalRequest{Command: "git push origin main", Timestamp: tt.at})
+		if got.Decision != tt.want {
+			t.Errorf("Evaluate at %s = %s, want %s", tt.at.Format(time.RFC1123), got.Decision, tt.want)
+		}
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	t.Parallel()
+
+	overnight := TimeWindow{Days: []string{"Friday"}, Start: "22:00", End: "06:00", Timezone: "America/New_York"}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 6, 23, 0, 0, 0, ny), true},  // Friday night
+		{time.Date(2026, 3, 7, 5, 59, 0, 0, ny), true},  // Saturday early, still Friday's window
+		{time.Date(2026, 3, 7, 6, 0, 0, 0, ny), false},  // Window over
+		{time.Date(2026, 3, 7, 23, 0, 0, 0, ny), false}, // Saturday night: not a window day
+		{time.Date(2026, 3, 6, 21, 0, 0, 0, ny), false},
+	}
+	for _, tt := range tests {
+		got, err := overnight.Contains(tt.at.UTC())
+		if err != nil {
+			t.Fatalf("Contains: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateRateLimit(t *testing.T) {
+	t.Parallel()
+
+	e := NewEvaluator(&Document{
+		Version: 1,
+		Rules: []Rule{
+			{
+				ID:        "nuke-quota",
+				Decision:  DecisionDeny,
+				Match:     RuleMatch{CommandPrefixes: []string{"gt polecat nuke"}},
+				RateLimit: &RateLimit{Max: 5, Per: "1h"},
+			},
+		},
+	}).WithRateStore(NewMemoryRateStore())
+
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	nuke := func(agent string, at time.Time, dryRun bool) EvalResult {
+		return e.Evaluate(EvalRequest{Agent: agent, Command: "gt polecat nuke Toast", Timestamp: at, DryRun: dryRun})
+	}
+
+	// Previews don't use up the quota.
+	for i := 0; i < 10; i++ {
+		if got := nuke("witness", start, true); got.Decision == DecisionDeny {
+			t.Fatalf("dry run %d denied", i)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if got := nuke("witness", start.Add(time.Duration(i)*time.Minute), false); got.Decision == DecisionDeny {
+			t.Fatalf("nuke %d denied: %s", i+1, got.Reason)
+		}
+	}
+	got := nuke("witness", start.Add(10*time.Minute), false)
+	if got.Decision != DecisionDeny || got.RuleID != "nuke-quota" || !strings.Contains(got.Reason, "rate limit exceeded") {
+		t.Fatalf("6th nuke = %+v, want denied by nuke-quota", got)
+	}
+	// Quotas are per agent by default.
+	if got := nuke("deacon", start.Add(10*time.Minute), false); got.Decision == DecisionDeny {
+		t.Errorf("other agent denied: %s", got.Reason)
+	}
+	// The window slides.
+	if got := nuke("witness", start.Add(61*time.Minute), false); got.Decision == DecisionDeny {
+		t.Errorf("nuke after window denied: %s", got.Reason)
+	}
+}
+
+func TestFileRateStoreConcurrentQuota(t *testing.T) {
+	t.Parallel()
+
+	townRoot := t.TempDir()
+	doc := &Document{
+		Version: 1,
+		Rules: []Rule{{
+			ID:        "nuke-quota",
+			Decision:  DecisionDeny,
+			Match:     RuleMatch{CommandPrefixes: []string{"gt polecat nuke"}},
+			RateLimit: &RateLimit{Max: 3, Per: "1h", Scope: RateScopeGlobal},
+		}},
+	}
+
+	// Separate evaluators and stores, as separate gt processes would have.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := NewEvaluator(doc).WithRateStore(NewFileRateStore(townRoot))
+			got := e.Evaluate(EvalRequest{Agent: "witness", Command: "gt polecat nuke Toast"})
+			if got.Decision != DecisionDeny {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 3 {
+		t.Errorf("%d concurrent nukes allowed, want 3", allowed)
+	}
+}
+
+func TestFileRateStoreCorruptDenies(t *testing.T) {
+	t.Parallel()
+
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "daemon", "policy-rates.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEvaluator(&Document{
+		Version: 1,
+		Rules: []Rule{{
+			ID:        "nuke-quota",
+			Decision:  DecisionDeny,
+			Match:     RuleMatch{CommandPrefixes: []string{"gt polecat nuke"}},
+			RateLimit: &RateLimit{Max: 5, Per: "1h"},
+		}},
+	}).WithRateStore(NewFileRateStore(townRoot))
+
+	got := e.Evaluate(EvalRequest{Agent: "witness", Command: "gt polecat nuke Toast"})
+	if got.Decision != DecisionDeny || got.RuleID != "nuke-quota" || !strings.Contains(got.Reason, "parsing policy rates") {
+		t.Errorf("nuke with corrupt rates = %+v, want denied by nuke-quota", got)
+	}
+	// Requests no rate-limited rule matches are unaffected.
+	if got := e.Evaluate(EvalRequest{Agent: "witness", Command: "gt status"}); got.Decision == DecisionDeny {
+		t.Errorf("unrelated command denied: %s", got.Reason)
+	}
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	t.Parallel()
+
+	townRoot := t.TempDir()
+	nuke := EvalRequest{Agent: "witness", Command: "gt polecat nuke Toast", DryRun: true}
+
+	e, err := LoadOrDefault(townRoot)
+	if err != nil {
+		t.Fatalf("missing policy: %v", err)
+	}
+	if got := e.Evaluate(EvalRequest{Command: "gt status"}); got.Decision != DecisionAllow {
+		t.Errorf("default evaluator: %+v", got)
+	}
+
+	writePolicy := func(data string) {
+		t.Helper()
+		path := DefaultPolicyPath(townRoot)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One broken rule doesn't drop the operator's other rules.
+	writePolicy(`{"version": 1, "rules": [
+		{"id": "broken", "decision": "allow", "match": {"command_regex": "("}},
+		{"id": "no-nukes", "decision": "deny", "match": {"command_prefixes": ["gt polecat nuke"]}}
+	]}`)
+	e, err = LoadOrDefault(townRoot)
+	if err == nil || !strings.Contains(err.Error(), `rule "broken"`) {
+		t.Errorf("error = %v, want the broken rule reported", err)
+	}
+	if got := e.Evaluate(nuke); got.Decision != DecisionDeny || got.RuleID != "no-nukes" {
+		t.Errorf("nuke = %+v, want denied by no-nukes", got)
+	}
+
+	// An unparseable document denies everything.
+	writePolicy(`{"rules": [`)
+	e, err = LoadOrDefault(townRoot)
+	if err == nil {
+		t.Fatal("expected error for unparseable policy")
+	}
+	if got := e.Evaluate(EvalRequest{Command: "gt status"}); got.Decision != DecisionDeny {
+		t.Errorf("unparseable policy: gt status = %+v, want deny", got)
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"bad regex", Rule{ID: "r", Match: RuleMatch{CommandRegex: "("}}, "command_regex"},
+		{"bad window time", Rule{ID: "r", Match: RuleMatch{Windows: []TimeWindow{{Start: "9am"}}}}, "window time"},
+		{"bad window day", Rule{ID: "r", Match: RuleMatch{ExceptWindows: []TimeWindow{{Days: []string{"someday"}}}}}, "window day"},
+		{"bad timezone", Rule{ID: "r", Match: RuleMatch{Windows: []TimeWindow{{Timezone: "Mars/Olympus"}}}}, "timezone"},
+		{"empty arg matcher", Rule{ID: "r", Match: RuleMatch{Args: []ArgMatch{{Absent: true}}}}, "args matcher"},
+		{"zero rate", Rule{ID: "r", RateLimit: &RateLimit{Max: 0, Per: "1h"}}, "rate_limit.max"},
+		{"bad rate period", Rule{ID: "r", RateLimit: &RateLimit{Max: 1, Per: "hourly"}}, "rate_limit.per"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			doc := &Document{Version: 1, Rules: []Rule{tt.rule}}
+			err := doc.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	doc := &Document{
+		Version: 1,
+		Rules: []Rule{
+			{
+				ID:        "nuke-quota",
+				Decision:  DecisionDeny,
+				Match:     RuleMatch{CommandPrefixes: []string{"gt polecat nuke"}},
+				RateLimit: &RateLimit{Max: 2, Per: "1h", Scope: RateScopeGlobal},
+			},
+		},
+	}
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	var past []PastDecision
+	// Recorded newest first to check replay sorts by time.
+	for i := 3; i >= 0; i-- {
+		past = append(past, PastDecision{
+			At:       start.Add(time.Duration(i) * time.Minute),
+			Agent:    "witness",
+			Command:  "gt polecat nuke Toast",
+			Decision: DecisionAllowWithJustification,
+		})
+	}
+
+	results := Replay(doc, past)
+	if len(results) != 4 {
+		t.Fatalf("Replay() returned %d results, want 4", len(results))
+	}
+	var changed int
+	for i, r := range results {
+		if i > 0 && r.At.Before(results[i-1].At) {
+			t.Errorf("results not in time order")
+		}
+		if r.Changed() {
+			changed++
+			if i < 2 {
+				t.Errorf("result %d changed, want first two within quota", i)
+			}
+		}
+	}
+	if changed != 2 {
+		t.Errorf("changed = %d, want 2", changed)
+	}
+}
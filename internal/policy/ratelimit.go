@@ -0,0 +1,198 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// RateLimit turns a rule into a quota: matching requests pass through the
+// rule until Max of them have been allowed within Per, after which the rule
+// applies. "max 5 nukes per hour" is a deny rule matching "gt polecat nuke"
+// with {"max": 5, "per": "1h"}.
+type RateLimit struct {
+	Max   int    `json:"max"`
+	Per   string `json:"per"`             // duration, e.g. "1h"
+	Scope string `json:"scope,omitempty"` // "agent" (default): a quota per agent; "global": one shared quota
+}
+
+// Rate limit scopes.
+const (
+	RateScopeAgent  = "agent"
+	RateScopeGlobal = "global"
+)
+
+func (r *RateLimit) String() string {
+	scope := "per agent"
+	if r.Scope == RateScopeGlobal {
+		scope = "total"
+	}
+	return fmt.Sprintf("%d per %s %s", r.Max, r.Per, scope)
+}
+
+func (r *RateLimit) validate() error {
+	if r.Max < 1 {
+		return fmt.Errorf("rate_limit.max must be at least 1, got %d", r.Max)
+	}
+	if d, err := time.ParseDuration(r.Per); err != nil || d <= 0 {
+		return fmt.Errorf("invalid rate_limit.per %q (want a duration like \"1h\")", r.Per)
+	}
+	if r.Scope != "" && r.Scope != RateScopeAgent && r.Scope != RateScopeGlobal {
+		return fmt.Errorf("invalid rate_limit.scope %q (want %q or %q)", r.Scope, RateScopeAgent, RateScopeGlobal)
+	}
+	return nil
+}
+
+// RateStore remembers when rate-limited requests were allowed.
+type RateStore interface {
+	// Update runs fn with exclusive access to the recorded requests, so a
+	// quota check and the record that follows it can't interleave with
+	// another evaluation. Requests fn records are saved when it returns nil.
+	Update(fn func(*RateLedger) error) error
+}
+
+// RateLedger is the set of recorded requests, by quota key, that a
+// RateStore hands to Update.
+type RateLedger struct {
+	hits  map[string][]time.Time
+	dirty bool
+}
+
+// Count returns how many requests were recorded under key since the given time.
+func (l *RateLedger) Count(key string, since time.Time) int {
+	return countSince(l.hits[key], since)
+}
+
+// Record notes a request under key, dropping entries older than window.
+func (l *RateLedger) Record(key string, at time.Time, window time.Duration) {
+	l.hits[key] = append(pruneBefore(l.hits[key], at.Add(-window)), at)
+	l.dirty = true
+}
+
+// rateHit is a request counted against a rule's quota.
+type rateHit struct {
+	key    string
+	window time.Duration
+}
+
+// checkRate reports whether req has used up rule's quota in ledger. When it
+// hasn't, the returned hit is what to record if the request goes ahead.
+// Without a ledger nothing is counted and the quota never runs out.
+func checkRate(ledger *RateLedger, rule Rule, req EvalRequest, now time.Time) (rateHit, bool) {
+	window, err := time.ParseDuration(rule.RateLimit.Per)
+	if err != nil || window <= 0 || ledger == nil {
+		return rateHit{}, false
+	}
+	key := rule.ID
+	if rule.RateLimit.Scope != RateScopeGlobal {
+		key += "|" + req.Agent
+	}
+	hit := rateHit{key: key, window: window}
+	return hit, ledger.Count(key, now.Add(-window)) >= rule.RateLimit.Max
+}
+
+// MemoryRateStore is an in-process RateStore, used when replaying history.
+type MemoryRateStore struct {
+	mu     sync.Mutex
+	ledger RateLedger
+}
+
+// NewMemoryRateStore creates an empty in-memory rate store.
+func NewMemoryRateStore() *MemoryRateStore {
+	return &MemoryRateStore{ledger: RateLedger{hits: make(map[string][]time.Time)}}
+}
+
+// Update implements RateStore.
+func (s *MemoryRateStore) Update(fn func(*RateLedger) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.ledger)
+}
+
+// FileRateStore keeps rate limit state in daemon/policy-rates.json so quotas
+// hold across gt processes.
+type FileRateStore struct {
+	path     string
+	lockPath string
+}
+
+// NewFileRateStore creates a rate store for a town root.
+func NewFileRateStore(townRoot string) *FileRateStore {
+	path := filepath.Join(townRoot, "daemon", "policy-rates.json")
+	return &FileRateStore{path: path, lockPath: path + ".lock"}
+}
+
+// Update implements RateStore. The state file stays locked while fn runs,
+// across processes as well as goroutines.
+func (s *FileRateStore) Update(fn func(*RateLedger) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	lock := flock.New(s.lockPath)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking policy rates: %w", err)
+	}
+	defer lock.Unlock() //nolint:errcheck // best effort
+
+	hits, err := s.load()
+	if err != nil {
+		return err
+	}
+	ledger := &RateLedger{hits: hits}
+	if err := fn(ledger); err != nil {
+		return err
+	}
+	if !ledger.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(ledger.hits)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *FileRateStore) load() (map[string][]time.Time, error) {
+	hits := make(map[string][]time.Time)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hits, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &hits); err != nil {
+		return nil, fmt.Errorf("parsing policy rates: %w", err)
+	}
+	return hits, nil
+}
+
+func countSince(hits []time.Time, since time.Time) int {
+	n := 0
+	for _, t := range hits {
+		if t.After(since) {
+			n++
+		}
+	}
+	return n
+}
+
+func pruneBefore(hits []time.Time, cutoff time.Time) []time.Time {
+	kept := hits[:0]
+	for _, t := range hits {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"sort"
+	"time"
+)
+
+// PastDecision is a policy decision recorded in the audit trail.
+type PastDecision struct {
+	RunID    string    `json:"run_id,omitempty"`
+	At       time.Time `json:"at"`
+	Agent    string    `json:"agent,omitempty"`
+	Repo     string    `json:"repo,omitempty"`
+	Command  string    `json:"command"`
+	Args     []string  `json:"args,omitempty"`
+	Decision Decision  `json:"decision"`
+}
+
+// ReplayResult is a past decision next to what a candidate policy decides.
+type ReplayResult struct {
+	PastDecision
+	New EvalResult `json:"new"`
+}
+
+// Changed reports whether the candidate policy decides differently.
+func (r ReplayResult) Changed() bool {
+	return r.New.Decision != r.Decision
+}
+
+// Replay re-evaluates past decisions against doc in time order. Rate limits
+// count the replayed history itself, so a quota sees the traffic it would
+// have seen had doc been in force.
+func Replay(doc *Document, past []PastDecision) []ReplayResult {
+	ordered := append([]PastDecision(nil), past...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].At.Before(ordered[j].At)
+	})
+
+	e := NewEvaluator(doc).WithRateStore(NewMemoryRateStore())
+	results := make([]ReplayResult, 0, len(ordered))
+	for _, p := range ordered {
+		results = append(results, ReplayResult{
+			PastDecision: p,
+			New: e.Evaluate(EvalRequest{
+				Agent:     p.Agent,
+				Repo:      p.Repo,
+				Command:   p.Command,
+				Args:      p.Args,
+				Timestamp: p.At,
+			}),
+		})
+	}
+	return results
+}
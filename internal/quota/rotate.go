@@ -2,6 +2,7 @@ package quota
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
 )
@@ -21,6 +22,13 @@ type RotatePlan struct {
 	// LimitedSessions are sessions detected as rate-limited.
 	LimitedSessions []ScanResult
 
+	// AtRiskSessions are sessions whose account is projected to hit its
+	// limit soon, added by PlanProactive.
+	AtRiskSessions []ScanResult
+
+	// Scanned is every session the scan looked at.
+	Scanned []ScanResult
+
 	// AvailableAccounts are accounts that can be rotated to.
 	AvailableAccounts []string
 
@@ -56,10 +64,11 @@ func PlanRotation(scanner *Scanner, mgr *Manager, acctCfg *config.AccountsConfig
 	for _, r := range limitedSessions {
 		if r.AccountHandle != "" {
 			state.Accounts[r.AccountHandle] = config.AccountQuotaState{
-				Status:    config.QuotaStatusLimited,
-				LimitedAt: state.Accounts[r.AccountHandle].LimitedAt,
-				ResetsAt:  r.ResetsAt,
-				LastUsed:  state.Accounts[r.AccountHandle].LastUsed,
+				Status:      config.QuotaStatusLimited,
+				LimitedAt:   state.Accounts[r.AccountHandle].LimitedAt,
+				ResetsAt:    r.ResetsAt,
+				LastUsed:    state.Accounts[r.AccountHandle].LastUsed,
+				LimitTokens: state.Accounts[r.AccountHandle].LimitTokens,
 			}
 		}
 	}
@@ -91,5 +100,48 @@ func PlanRotation(scanner *Scanner, mgr *Manager, acctCfg *config.AccountsConfig
 		LimitedSessions:   limitedSessions,
 		AvailableAccounts: available,
 		Assignments:       assignments,
+		Scanned:           results,
 	}, nil
 }
+
+// PlanProactive adds to the plan the sessions on accounts projected to hit
+// their limit within horizon, so they move before they stall. Each goes to
+// the account with the most headroom that isn't at risk itself or already
+// taking another session in this plan.
+func (p *RotatePlan) PlanProactive(usages []AccountUsage, state *config.QuotaState, now time.Time, horizon time.Duration) {
+	atRisk := make(map[string]bool)
+	for _, u := range usages {
+		if u.AtRisk(now, horizon) {
+			atRisk[u.Handle] = true
+		}
+	}
+	if len(atRisk) == 0 {
+		return
+	}
+
+	taken := make(map[string]bool)
+	for _, handle := range p.Assignments {
+		taken[handle] = true
+	}
+	var targets []string
+	for _, handle := range RankByHeadroom(usages, state) {
+		if !atRisk[handle] && !taken[handle] {
+			targets = append(targets, handle)
+		}
+	}
+
+	for _, r := range p.Scanned {
+		if r.RateLimited || !atRisk[r.AccountHandle] {
+			continue
+		}
+		if _, assigned := p.Assignments[r.Session]; assigned {
+			continue
+		}
+		p.AtRiskSessions = append(p.AtRiskSessions, r)
+		if len(targets) == 0 {
+			continue
+		}
+		p.Assignments[r.Session] = targets[0]
+		targets = targets[1:]
+	}
+}
@@ -3,6 +3,10 @@
 // When sessions hit rate limits, the overseer can scan for blocked sessions
 // and rotate them to available accounts. State is persisted to mayor/quota.json
 // with crash-safe atomic writes and file-level locking.
+//
+// Usage is also tracked per account over a rolling window from Claude Code
+// transcripts, so new sessions can be placed on the account with the most
+// headroom and sessions can be moved before their account runs out.
 package quota
 
 import (
@@ -123,10 +127,11 @@ func (m *Manager) MarkLimited(handle string, resetsAt string) error {
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	state.Accounts[handle] = config.AccountQuotaState{
-		Status:    config.QuotaStatusLimited,
-		LimitedAt: now,
-		ResetsAt:  resetsAt,
-		LastUsed:  state.Accounts[handle].LastUsed,
+		Status:      config.QuotaStatusLimited,
+		LimitedAt:   now,
+		ResetsAt:    resetsAt,
+		LastUsed:    state.Accounts[handle].LastUsed,
+		LimitTokens: state.Accounts[handle].LimitTokens,
 	}
 
 	return util.EnsureDirAndWriteJSON(m.statePath(), state)
@@ -147,8 +152,9 @@ func (m *Manager) MarkAvailable(handle string) error {
 
 	existing := state.Accounts[handle]
 	state.Accounts[handle] = config.AccountQuotaState{
-		Status:   config.QuotaStatusAvailable,
-		LastUsed: existing.LastUsed,
+		Status:      config.QuotaStatusAvailable,
+		LastUsed:    existing.LastUsed,
+		LimitTokens: existing.LimitTokens,
 	}
 
 	return util.EnsureDirAndWriteJSON(m.statePath(), state)
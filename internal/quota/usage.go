@@ -0,0 +1,286 @@
+package quota
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultUsageWindow is the rolling window Claude meters usage over.
+const DefaultUsageWindow = 5 * time.Hour
+
+// burnRateWindow is how far back an account's current burn rate is measured.
+const burnRateWindow = 30 * time.Minute
+
+// DefaultRotateHorizon is how close to projected exhaustion a session's
+// account must be for proactive rotation to move it.
+const DefaultRotateHorizon = 15 * time.Minute
+
+// UsageSource returns the responses recorded under an account's config dir
+// since a time. Production reads Claude Code transcripts.
+type UsageSource func(configDir string, since time.Time) ([]costs.UsageEvent, error)
+
+// AccountUsage is an account's consumption over the rolling window, with a
+// projection of when it runs out at the current burn rate.
+type AccountUsage struct {
+	Handle string `json:"handle"`
+
+	// WindowTokens is the metered tokens used in the rolling window: input,
+	// cache writes and output. Cache reads are left out as they barely count
+	// against provider limits.
+	WindowTokens int `json:"window_tokens"`
+
+	// BurnRate is tokens per hour over the last 30 minutes.
+	BurnRate float64 `json:"burn_rate_per_hour"`
+
+	// LimitTokens is the allowance per window: configured (window_tokens in
+	// accounts.json) or learned from the last rate limit. Zero if unknown.
+	LimitTokens int    `json:"limit_tokens,omitempty"`
+	LimitSource string `json:"limit_source,omitempty"` // "configured" or "learned"
+
+	// Limited is true while the account is rate-limited (its reset time, if
+	// known, hasn't passed).
+	Limited  bool       `json:"limited"`
+	ResetsAt *time.Time `json:"resets_at,omitempty"`
+
+	// ProjectedExhaustion is when the account hits its limit at the current
+	// burn rate. Nil when the limit is unknown or nothing is burning.
+	ProjectedExhaustion *time.Time `json:"projected_exhaustion,omitempty"`
+}
+
+// Headroom returns the tokens left in the window, or -1 if the limit is unknown.
+func (u AccountUsage) Headroom() int {
+	if u.LimitTokens <= 0 {
+		return -1
+	}
+	if left := u.LimitTokens - u.WindowTokens; left > 0 {
+		return left
+	}
+	return 0
+}
+
+// meteredTokens is the usage counted against an account's allowance.
+func meteredTokens(u costs.Usage) int {
+	return u.InputTokens + u.CacheWriteTokens + u.OutputTokens
+}
+
+// MeasureUsage measures every registered account's usage at now. Accounts
+// whose logs can't be read show no usage. Sorted by handle.
+func MeasureUsage(accounts *config.AccountsConfig, state *config.QuotaState, source UsageSource, now time.Time) []AccountUsage {
+	if accounts == nil {
+		return nil
+	}
+	if source == nil {
+		source = costs.ClaudeUsageSince
+	}
+
+	usages := make([]AccountUsage, 0, len(accounts.Accounts))
+	for handle, acct := range accounts.Accounts {
+		u := AccountUsage{Handle: handle}
+
+		events, _ := source(util.ExpandHome(acct.ConfigDir), now.Add(-DefaultUsageWindow))
+		rateSince := now.Add(-burnRateWindow)
+		recent := 0
+		for _, e := range events {
+			tokens := meteredTokens(e.Usage)
+			u.WindowTokens += tokens
+			if !e.Time.Before(rateSince) {
+				recent += tokens
+			}
+		}
+		u.BurnRate = float64(recent) / burnRateWindow.Hours()
+
+		qs := state.Accounts[handle]
+		switch {
+		case acct.WindowTokens > 0:
+			u.LimitTokens, u.LimitSource = acct.WindowTokens, "configured"
+		case qs.LimitTokens > 0:
+			u.LimitTokens, u.LimitSource = qs.LimitTokens, "learned"
+		}
+
+		if qs.Status == config.QuotaStatusLimited || qs.Status == config.QuotaStatusCooldown {
+			u.Limited = true
+			if resets, ok := ParseResetsAt(qs.ResetsAt, limitedAt(qs, now)); ok {
+				u.ResetsAt = &resets
+				u.Limited = now.Before(resets)
+			}
+		}
+
+		if headroom := u.Headroom(); headroom >= 0 && u.BurnRate > 0 && !u.Limited {
+			at := now.Add(time.Duration(float64(headroom) / u.BurnRate * float64(time.Hour)))
+			u.ProjectedExhaustion = &at
+		}
+		usages = append(usages, u)
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Handle < usages[j].Handle })
+	return usages
+}
+
+// limitedAt is when an account was found limited, the reference point for
+// its reset time. Falls back to now.
+func limitedAt(qs config.AccountQuotaState, now time.Time) time.Time {
+	if t, err := time.Parse(time.RFC3339, qs.LimitedAt); err == nil {
+		return t
+	}
+	return now
+}
+
+// LearnLimit records an account's window usage at the moment it was found
+// rate-limited as its learned allowance.
+func LearnLimit(state *config.QuotaState, handle string, windowTokens int) {
+	if windowTokens <= 0 {
+		return
+	}
+	qs := state.Accounts[handle]
+	qs.LimitTokens = windowTokens
+	state.Accounts[handle] = qs
+}
+
+// RankByHeadroom returns the handles of accounts that can take new work,
+// most headroom first. Limited accounts are left out. Accounts with an
+// unknown limit are assumed to have the average known limit; if no limit is
+// known at all, the least-used account ranks first. Ties go to the least
+// recently used account.
+func RankByHeadroom(usages []AccountUsage, state *config.QuotaState) []string {
+	knownTotal, known := 0, 0
+	for _, u := range usages {
+		if u.LimitTokens > 0 {
+			knownTotal += u.LimitTokens
+			known++
+		}
+	}
+	headroom := func(u AccountUsage) int {
+		if u.LimitTokens > 0 {
+			return u.Headroom()
+		}
+		if known > 0 {
+			return max(knownTotal/known-u.WindowTokens, 0)
+		}
+		return -u.WindowTokens
+	}
+
+	var candidates []AccountUsage
+	for _, u := range usages {
+		if !u.Limited {
+			candidates = append(candidates, u)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		hi, hj := headroom(candidates[i]), headroom(candidates[j])
+		if hi != hj {
+			return hi > hj
+		}
+		li, lj := state.Accounts[candidates[i].Handle].LastUsed, state.Accounts[candidates[j].Handle].LastUsed
+		if li != lj {
+			return li < lj
+		}
+		return candidates[i].Handle < candidates[j].Handle
+	})
+
+	handles := make([]string, len(candidates))
+	for i, u := range candidates {
+		handles[i] = u.Handle
+	}
+	return handles
+}
+
+// AtRisk reports whether an account is projected to run out within horizon.
+func (u AccountUsage) AtRisk(now time.Time, horizon time.Duration) bool {
+	return u.ProjectedExhaustion != nil && u.ProjectedExhaustion.Before(now.Add(horizon))
+}
+
+// resetClockPattern matches the clock time in a reset message: "7pm",
+// "7:30 pm", "19:00", "3:00 AM".
+var resetClockPattern = regexp.MustCompile(`(?i)^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?`)
+
+// resetZonePattern matches the zone after the clock time: an IANA name in
+// parentheses or a bare abbreviation.
+var resetZonePattern = regexp.MustCompile(`\(([A-Za-z_]+/[A-Za-z_/]+)\)|\b([A-Z]{2,4})\b`)
+
+// zoneAbbreviations maps the abbreviations providers print to locations.
+var zoneAbbreviations = map[string]string{
+	"UTC": "UTC", "GMT": "UTC",
+	"PST": "America/Los_Angeles", "PDT": "America/Los_Angeles", "PT": "America/Los_Angeles",
+	"MST": "America/Denver", "MDT": "America/Denver", "MT": "America/Denver",
+	"CST": "America/Chicago", "CDT": "America/Chicago", "CT": "America/Chicago",
+	"EST": "America/New_York", "EDT": "America/New_York", "ET": "America/New_York",
+}
+
+// ParseResetsAt turns a reset time extracted by the scanner ("7pm
+// (America/Los_Angeles)", "3:00 AM PST") into the first such time after
+// ref, the moment the limit was seen. Without a zone, local time is used.
+func ParseResetsAt(s string, ref time.Time) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	m := resetClockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch strings.ToLower(m[3]) {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour != 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, false
+	}
+
+	loc := time.Local
+	if z := resetZonePattern.FindStringSubmatch(s[len(m[0]):]); z != nil {
+		name := z[1]
+		if name == "" {
+			name = zoneAbbreviations[z[2]]
+		}
+		if l, err := time.LoadLocation(name); name != "" && err == nil {
+			loc = l
+		}
+	}
+
+	r := ref.In(loc)
+	resets := time.Date(r.Year(), r.Month(), r.Day(), hour, minute, 0, 0, loc)
+	if !resets.After(r) {
+		resets = resets.AddDate(0, 0, 1)
+	}
+	return resets, true
+}
+
+// PlaceSession picks the account a new session should run on: the one with
+// the most headroom. Its last-used time is bumped so sessions spawned back to
+// back spread across accounts. Returns "" if no account can take work.
+func (m *Manager) PlaceSession(accounts *config.AccountsConfig, source UsageSource, now time.Time) (string, error) {
+	var handle string
+	err := m.WithLock(func() error {
+		state, err := m.Load()
+		if err != nil {
+			return err
+		}
+		m.EnsureAccountsTracked(state, accounts.Accounts)
+
+		ranked := RankByHeadroom(MeasureUsage(accounts, state, source, now), state)
+		if len(ranked) == 0 {
+			return nil
+		}
+		handle = ranked[0]
+		qs := state.Accounts[handle]
+		qs.LastUsed = now.UTC().Format(time.RFC3339)
+		state.Accounts[handle] = qs
+		return m.SaveUnlocked(state)
+	})
+	return handle, err
+}
@@ -0,0 +1,192 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+func TestParseResetsAt(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	ref := time.Date(2026, 10, 16, 15, 0, 0, 0, la)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7pm (America/Los_Angeles)", time.Date(2026, 10, 16, 19, 0, 0, 0, la)},
+		{"3:00 AM PDT", time.Date(2026, 10, 17, 3, 0, 0, 0, la)},
+		{"12am (America/Los_Angeles)", time.Date(2026, 10, 17, 0, 0, 0, 0, la)},
+		{"2:30 pm PT", time.Date(2026, 10, 17, 14, 30, 0, 0, la)},
+		{"23:00 UTC", time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := ParseResetsAt(tt.in, ref)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("ParseResetsAt(%q) = %v, %v; want %v", tt.in, got, ok, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "soon", "25:00 UTC"} {
+		if _, ok := ParseResetsAt(bad, ref); ok {
+			t.Errorf("ParseResetsAt(%q) should fail", bad)
+		}
+	}
+}
+
+// fakeUsage serves canned usage events by config dir.
+func fakeUsage(events map[string][]costs.UsageEvent) UsageSource {
+	return func(configDir string, since time.Time) ([]costs.UsageEvent, error) {
+		var out []costs.UsageEvent
+		for _, e := range events[configDir] {
+			if !e.Time.Before(since) {
+				out = append(out, e)
+			}
+		}
+		return out, nil
+	}
+}
+
+func event(at time.Time, tokens int) costs.UsageEvent {
+	return costs.UsageEvent{Time: at, Usage: costs.Usage{InputTokens: tokens, CacheReadTokens: 10 * tokens}}
+}
+
+func TestMeasureUsage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	accounts := &config.AccountsConfig{Accounts: map[string]config.Account{
+		"work":     {ConfigDir: "/acct/work", WindowTokens: 1000},
+		"personal": {ConfigDir: "/acct/personal"},
+		"spare":    {ConfigDir: "/acct/spare"},
+	}}
+	state := &config.QuotaState{Accounts: map[string]config.AccountQuotaState{
+		"personal": {LimitTokens: 2000},
+		"spare":    {Status: config.QuotaStatusLimited, LimitedAt: now.Add(-2 * time.Hour).Format(time.RFC3339), ResetsAt: "11am UTC"},
+	}}
+	source := fakeUsage(map[string][]costs.UsageEvent{
+		"/acct/work": {
+			event(now.Add(-6*time.Hour), 5000), // outside the window
+			event(now.Add(-2*time.Hour), 300),
+			event(now.Add(-10*time.Minute), 200),
+		},
+		"/acct/personal": {event(now.Add(-3*time.Hour), 100)},
+	})
+
+	usages := MeasureUsage(accounts, state, source, now)
+	if len(usages) != 3 || usages[0].Handle != "personal" || usages[2].Handle != "work" {
+		t.Fatalf("usages = %+v", usages)
+	}
+	personal, spare, work := usages[0], usages[1], usages[2]
+
+	if work.WindowTokens != 500 || work.LimitSource != "configured" || work.Headroom() != 500 {
+		t.Errorf("work = %+v", work)
+	}
+	// 200 tokens in the last 30 minutes: 400/h, so 500 left lasts 75 minutes.
+	if work.BurnRate != 400 || work.ProjectedExhaustion == nil ||
+		!work.ProjectedExhaustion.Equal(now.Add(75*time.Minute)) {
+		t.Errorf("work projection = %v at %v/h", work.ProjectedExhaustion, work.BurnRate)
+	}
+	if !work.AtRisk(now, 2*time.Hour) || work.AtRisk(now, time.Hour) {
+		t.Error("work should be at risk within 2h but not 1h")
+	}
+
+	if personal.LimitSource != "learned" || personal.BurnRate != 0 || personal.ProjectedExhaustion != nil {
+		t.Errorf("personal = %+v", personal)
+	}
+
+	// Reset at 11am has passed.
+	if spare.Limited || spare.ResetsAt == nil || spare.Headroom() != -1 {
+		t.Errorf("spare = %+v", spare)
+	}
+}
+
+func TestRankByHeadroom(t *testing.T) {
+	state := &config.QuotaState{Accounts: map[string]config.AccountQuotaState{
+		"a": {LastUsed: "2026-10-16T10:00:00Z"},
+		"b": {LastUsed: "2026-10-16T09:00:00Z"},
+	}}
+	usages := []AccountUsage{
+		{Handle: "a", WindowTokens: 100, LimitTokens: 1000},
+		{Handle: "b", WindowTokens: 100, LimitTokens: 1000},
+		{Handle: "c", WindowTokens: 950, LimitTokens: 1000},
+		{Handle: "d", WindowTokens: 400}, // assumed 1000 (the average)
+		{Handle: "e", Limited: true},
+	}
+	got := RankByHeadroom(usages, state)
+	want := []string{"b", "a", "d", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("RankByHeadroom = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("RankByHeadroom = %v, want %v", got, want)
+		}
+	}
+
+	// No limits known: least used first.
+	got = RankByHeadroom([]AccountUsage{{Handle: "x", WindowTokens: 50}, {Handle: "y", WindowTokens: 10}}, state)
+	if len(got) != 2 || got[0] != "y" {
+		t.Errorf("unknown limits = %v, want y first", got)
+	}
+}
+
+func TestPlanProactive(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(5 * time.Minute)
+	state := &config.QuotaState{Accounts: map[string]config.AccountQuotaState{}}
+	usages := []AccountUsage{
+		{Handle: "hot", WindowTokens: 990, LimitTokens: 1000, BurnRate: 120, ProjectedExhaustion: &soon},
+		{Handle: "cool", WindowTokens: 100, LimitTokens: 1000},
+		{Handle: "warm", WindowTokens: 800, LimitTokens: 1000},
+	}
+	plan := &RotatePlan{
+		Assignments: map[string]string{},
+		Scanned: []ScanResult{
+			{Session: "gt-crew-bear", AccountHandle: "hot"},
+			{Session: "gt-crew-wolf", AccountHandle: "hot"},
+			{Session: "gt-witness", AccountHandle: "cool"},
+			{Session: "gt-mayor", AccountHandle: "hot", RateLimited: true},
+		},
+	}
+
+	plan.PlanProactive(usages, state, now, DefaultRotateHorizon)
+
+	if len(plan.AtRiskSessions) != 2 {
+		t.Fatalf("AtRiskSessions = %+v, want bear and wolf", plan.AtRiskSessions)
+	}
+	if plan.Assignments["gt-crew-bear"] != "cool" || plan.Assignments["gt-crew-wolf"] != "warm" {
+		t.Errorf("Assignments = %v", plan.Assignments)
+	}
+	if _, ok := plan.Assignments["gt-witness"]; ok {
+		t.Error("sessions on healthy accounts should stay put")
+	}
+}
+
+func TestPlaceSession(t *testing.T) {
+	townRoot := setupTestTown(t)
+	mgr := NewManager(townRoot)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	accounts := &config.AccountsConfig{Accounts: map[string]config.Account{
+		"work":     {ConfigDir: "/acct/work", WindowTokens: 1000},
+		"personal": {ConfigDir: "/acct/personal", WindowTokens: 1000},
+	}}
+	source := fakeUsage(map[string][]costs.UsageEvent{
+		"/acct/work": {event(now.Add(-time.Hour), 600)},
+	})
+
+	handle, err := mgr.PlaceSession(accounts, source, now)
+	if err != nil || handle != "personal" {
+		t.Fatalf("PlaceSession = %q, %v; want personal", handle, err)
+	}
+	state, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Accounts["personal"].LastUsed != now.Format(time.RFC3339) {
+		t.Errorf("LastUsed = %q, want bumped", state.Accounts["personal"].LastUsed)
+	}
+}
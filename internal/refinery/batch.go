@@ -0,0 +1,363 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// speculativeBranch is the local branch batched MRs are stacked on. It only
+// ever exists in the refinery's worktree; landing pushes its commits straight
+// to the target.
+const speculativeBranch = "refinery/speculative"
+
+// BatchOutcome is what happened to one MR in a batch.
+type BatchOutcome struct {
+	MR     *MRInfo
+	Result ProcessResult
+
+	// Deferred MRs were not decided this round (they did not stack cleanly
+	// on the others, or the batch stopped early) and stay in the queue.
+	// Result.Error says why.
+	Deferred bool
+}
+
+// BatchResult is the outcome of ProcessBatch.
+type BatchResult struct {
+	Target   string
+	Outcomes []BatchOutcome
+
+	// Checks counts gate runs, including bisection. A clean batch takes one.
+	Checks int
+}
+
+func (r *BatchResult) add(mr *MRInfo, result ProcessResult) {
+	r.Outcomes = append(r.Outcomes, BatchOutcome{MR: mr, Result: result})
+}
+
+func (r *BatchResult) deferMR(mr *MRInfo, reason string) {
+	r.Outcomes = append(r.Outcomes, BatchOutcome{MR: mr, Result: ProcessResult{Error: reason}, Deferred: true})
+}
+
+// stackedMR is an MR squash-merged onto the speculative branch.
+type stackedMR struct {
+	mr     *MRInfo
+	commit string
+}
+
+// SelectBatch picks the MRs for the next batch: the highest-scoring
+// unblocked MR plus up to size-1 more with the same target, in score order.
+// A nil scorer uses the default weights without queue context.
+func SelectBatch(mrs []*MRInfo, size int, scorer *Scorer, now time.Time) []*MRInfo {
+	var ready []*MRInfo
+	for _, mr := range mrs {
+		if mr != nil && mr.BlockedBy == "" {
+			ready = append(ready, mr)
+		}
+	}
+	if len(ready) == 0 || size < 1 {
+		return nil
+	}
+	if scorer == nil {
+		scorer = NewScorer(DefaultScoreConfig())
+	}
+	scores := make(map[string]float64, len(ready))
+	for _, mr := range ready {
+		scores[mr.ID] = scorer.Score(mr, now)
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return scores[ready[i].ID] > scores[ready[j].ID]
+	})
+
+	target := ready[0].Target
+	var batch []*MRInfo
+	for _, mr := range ready {
+		if mr.Target == target {
+			batch = append(batch, mr)
+			if len(batch) == size {
+				break
+			}
+		}
+	}
+	return batch
+}
+
+// ProcessBatch merges several MRs for the same target with one gate run.
+//
+// The MRs are squash-merged in order onto a speculative branch cut from the
+// target, and the gates run once on the result. If they pass, the whole stack
+// lands with a single push. If they fail, the stack is bisected to find the
+// first MR whose addition breaks the gates: the MRs before it land, it fails
+// on its own, and the MRs after it are restacked and tried again.
+//
+// MRs that don't stack cleanly on the ones before them are deferred to a
+// later round rather than failed; only an MR that conflicts with the target
+// itself is reported as a conflict. Like ProcessMRInfo, this does not touch
+// the MR beads: pass the result to HandleBatchResult.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) *BatchResult {
+	if len(mrs) == 0 {
+		return &BatchResult{}
+	}
+	target := mrs[0].Target
+	res := &BatchResult{Target: target}
+
+	ctx, span := telemetry.Start(ctx, "refinery.batch",
+		attribute.String("gt.rig", e.rig.Name),
+		attribute.String("gt.target", target),
+		attribute.Int("gt.batch_size", len(mrs)))
+	defer func() {
+		span.SetAttributes(attribute.Int("gt.batch_checks", res.Checks))
+		telemetry.End(span, nil)
+	}()
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Batch: %d MR(s) for %s\n", len(mrs), target)
+
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("origin/" + target)
+	if err != nil {
+		for _, mr := range mrs {
+			res.deferMR(mr, fmt.Sprintf("failed to resolve origin/%s: %v", target, err))
+		}
+		return res
+	}
+	defer e.leaveSpeculative(target)
+
+	pending := mrs
+	for len(pending) > 0 {
+		stack := e.stackMRs(res, base, target, pending)
+		pending = nil
+		if len(stack) == 0 {
+			break
+		}
+
+		landed, culprit, passRes, failRes := e.bisectStack(ctx, res, base, stack)
+		if landed > 0 {
+			tip := stack[landed-1].commit
+			if err := e.landStack(ctx, target, tip); err != nil {
+				result := ProcessResult{
+					Error:       err.Error(),
+					SlotTimeout: errors.Is(err, errMergeSlotTimeout),
+					Gates:       passRes.Gates,
+					GateTree:    passRes.GateTree,
+				}
+				for _, s := range stack[:landed] {
+					res.add(s.mr, result)
+				}
+				for _, s := range stack[landed:] {
+					res.deferMR(s.mr, "batch stopped: "+err.Error())
+				}
+				return res
+			}
+			for _, s := range stack[:landed] {
+				res.add(s.mr, ProcessResult{
+					Success:     true,
+					MergeCommit: s.commit,
+					Gates:       passRes.Gates,
+					GateTree:    passRes.GateTree,
+				})
+			}
+			base = tip
+		}
+
+		if culprit < 0 {
+			if landed < len(stack) {
+				// The checks failed for a reason bisecting can't isolate
+				// (e.g. invalid gate config); every MR gets that result.
+				for _, s := range stack[landed:] {
+					res.add(s.mr, failRes)
+				}
+			}
+			break
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Batch: %s breaks the gates\n", stack[culprit].mr.ID)
+		res.add(stack[culprit].mr, failRes)
+		for _, s := range stack[culprit+1:] {
+			pending = append(pending, s.mr)
+		}
+	}
+
+	return res
+}
+
+// stackMRs resets the speculative branch to base and squash-merges mrs onto
+// it in order. MRs that can't be stacked are recorded in res.
+func (e *Engineer) stackMRs(res *BatchResult, base, target string, mrs []*MRInfo) []stackedMR {
+	if err := e.git.CheckoutBranchAt(speculativeBranch, base); err != nil {
+		for _, mr := range mrs {
+			res.deferMR(mr, fmt.Sprintf("failed to create %s: %v", speculativeBranch, err))
+		}
+		return nil
+	}
+
+	var stack []stackedMR
+	for _, mr := range mrs {
+		exists, err := e.git.BranchExists(mr.Branch)
+		if err != nil || !exists {
+			res.add(mr, ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)})
+			continue
+		}
+		if subs, _ := e.git.SubmoduleChanges(base, mr.Branch); len(subs) > 0 {
+			// Submodule commits have to be pushed before the parent pointer;
+			// ProcessMRInfo handles that one MR at a time.
+			res.deferMR(mr, "changes submodules; merge it on its own")
+			continue
+		}
+
+		msg := e.squashMessage(mr.Branch, target, mr.SourceIssue)
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			conflicts, _ := e.git.GetConflictingFiles()
+			_ = e.git.ResetHard("HEAD")
+			switch {
+			case len(conflicts) > 0 && len(stack) == 0:
+				res.add(mr, ProcessResult{
+					Conflict: true,
+					Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+				})
+			case len(conflicts) > 0:
+				res.deferMR(mr, fmt.Sprintf("conflicts with earlier MRs in the batch: %v", conflicts))
+			default:
+				res.add(mr, ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)})
+			}
+			continue
+		}
+
+		commit, err := e.git.Rev("HEAD")
+		if err != nil {
+			_ = e.git.ResetHard("HEAD~1")
+			res.deferMR(mr, fmt.Sprintf("failed to get squash commit: %v", err))
+			continue
+		}
+		stack = append(stack, stackedMR{mr: mr, commit: commit})
+	}
+
+	if len(stack) > 0 {
+		ids := make([]string, len(stack))
+		for i, s := range stack {
+			ids[i] = s.mr.ID
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Batch: stacked %s on %s\n", strings.Join(ids, ", "), shortSHA(base))
+	}
+	return stack
+}
+
+// bisectStack runs the checks on the whole stack and, if they fail, bisects
+// for the first MR that breaks them. It returns how many MRs (from the
+// bottom) can land, the culprit's index (-1 if none was isolated), and the
+// check results for the landable prefix and the culprit.
+func (e *Engineer) bisectStack(ctx context.Context, res *BatchResult, base string, stack []stackedMR) (landed, culprit int, passRes, failRes ProcessResult) {
+	check := func(n int) ProcessResult {
+		res.Checks++
+		if err := e.git.ResetHard(stack[n-1].commit); err != nil {
+			return ProcessResult{Error: fmt.Sprintf("failed to check out batch prefix: %v", err)}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Batch: checking %d of %d MR(s)\n", n, len(stack))
+		return e.runChecks(ctx, e.gateInputSince(base))
+	}
+
+	full := check(len(stack))
+	if full.Success {
+		return len(stack), -1, full, ProcessResult{}
+	}
+	if !full.TestsFailed {
+		return 0, -1, ProcessResult{}, full
+	}
+
+	// Invariant: the first lo MRs pass (the target alone is assumed to), the
+	// first hi fail.
+	lo, hi := 0, len(stack)
+	failRes = full
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if r := check(mid); r.Success {
+			lo, passRes = mid, r
+		} else {
+			hi, failRes = mid, r
+		}
+	}
+	return lo, hi - 1, passRes, failRes
+}
+
+// runChecks runs the quality gates, or the legacy test command when no gates
+// are configured, against the checked-out tree.
+func (e *Engineer) runChecks(ctx context.Context, in gateInput) ProcessResult {
+	if len(e.config.Gates) > 0 {
+		return e.runGates(ctx, in)
+	}
+	if e.config.RunTests && e.config.TestCommand != "" {
+		if result := e.runTests(ctx); !result.Success {
+			return ProcessResult{TestsFailed: true, Error: result.Error}
+		}
+	}
+	return ProcessResult{Success: true}
+}
+
+// landStack pushes tip to the target branch, holding the merge slot when the
+// target is the rig's default branch.
+func (e *Engineer) landStack(ctx context.Context, target, tip string) error {
+	if target == e.rig.DefaultBranch() {
+		holder, err := e.acquireMainPushSlot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to acquire merge slot before push: %w", err)
+		}
+		defer func() {
+			if holder == "" {
+				return
+			}
+			if err := e.mergeSlotRelease(holder); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", holder, err)
+			}
+		}()
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Batch: pushing %s to origin/%s...\n", shortSHA(tip), target)
+	if err := e.git.Push("origin", tip+":refs/heads/"+target, false); err != nil {
+		return fmt.Errorf("failed to push to origin: %w", err)
+	}
+	if err := e.git.ResetBranch(target, tip); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update local %s: %v\n", target, err)
+	}
+	return nil
+}
+
+// leaveSpeculative returns the worktree to the target branch.
+func (e *Engineer) leaveSpeculative(target string) {
+	if err := e.git.Checkout(target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to check out %s after batch: %v\n", target, err)
+		return
+	}
+	if err := e.git.ResetHard("origin/" + target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after batch: %v\n", target, err)
+	}
+}
+
+// HandleBatchResult applies a batch's outcomes to the queue: merged MRs are
+// closed, failed ones go through HandleMRInfoFailure (so only the culprit's
+// worker hears about a gate failure), and deferred MRs are left as they are.
+func (e *Engineer) HandleBatchResult(res *BatchResult) {
+	for _, o := range res.Outcomes {
+		switch {
+		case o.Deferred:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Deferred: %s - %s\n", o.MR.ID, o.Result.Error)
+		case o.Result.Success:
+			e.HandleMRInfoSuccess(o.MR, o.Result)
+		default:
+			e.HandleMRInfoFailure(o.MR, o.Result)
+		}
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
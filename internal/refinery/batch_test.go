@@ -0,0 +1,262 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestSelectBatch(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "low", Target: "main", Priority: 4, CreatedAt: now},
+		{ID: "blocked", Target: "main", Priority: 0, CreatedAt: now, BlockedBy: "gt-x"},
+		{ID: "high", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "other-target", Target: "release", Priority: 1, CreatedAt: now},
+		{ID: "mid", Target: "main", Priority: 2, CreatedAt: now},
+	}
+
+	ids := func(batch []*MRInfo) string {
+		var out []string
+		for _, mr := range batch {
+			out = append(out, mr.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(SelectBatch(mrs, 3, nil, now)); got != "high,mid,low" {
+		t.Errorf("SelectBatch(3) = %s, want high,mid,low", got)
+	}
+	if got := ids(SelectBatch(mrs, 2, nil, now)); got != "high,mid" {
+		t.Errorf("SelectBatch(2) = %s, want high,mid", got)
+	}
+	if got := SelectBatch(nil, 3, nil, now); got != nil {
+		t.Errorf("SelectBatch(nil) = %v, want nil", got)
+	}
+}
+
+// batchTestRepo is a refinery worktree cloned from a bare origin, with one
+// MR branch per call to addMR.
+type batchTestRepo struct {
+	t    *testing.T
+	e    *Engineer
+	work string
+}
+
+func newBatchTestRepo(t *testing.T) *batchTestRepo {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	rigPath := t.TempDir()
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	work := filepath.Join(rigPath, "refinery", "rig")
+
+	runGitCmd(t, "", "init", "--bare", "-b", "main", origin)
+	runGitCmd(t, "", "clone", origin, work)
+	runGitCmd(t, work, "config", "user.email", "test@test.com")
+	runGitCmd(t, work, "config", "user.name", "Test")
+	runGitCmd(t, work, "checkout", "-B", "main")
+	writeFile(t, filepath.Join(work, "README.md"), "base\n")
+	runGitCmd(t, work, "add", ".")
+	runGitCmd(t, work, "commit", "-m", "initial")
+	runGitCmd(t, work, "push", "origin", "main")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	e.output = io.Discard
+	e.mergeSlotEnsureExists = func() (string, error) { return "merge-slot", nil }
+	e.mergeSlotAcquire = func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+		return &beads.MergeSlotStatus{ID: "merge-slot", Available: true, Holder: holder}, nil
+	}
+	e.mergeSlotRelease = func(string) error { return nil }
+	// Fails once a "broken" file has been merged.
+	e.config.Gates = map[string]*GateConfig{"check": {Cmd: "test ! -e broken"}}
+
+	return &batchTestRepo{t: t, e: e, work: work}
+}
+
+// addMR creates a branch off main that writes files and returns its MR.
+func (r *batchTestRepo) addMR(id string, files map[string]string) *MRInfo {
+	r.t.Helper()
+	branch := "polecat/" + id
+	runGitCmd(r.t, r.work, "checkout", "-b", branch, "main")
+	for name, content := range files {
+		writeFile(r.t, filepath.Join(r.work, name), content)
+	}
+	runGitCmd(r.t, r.work, "add", ".")
+	runGitCmd(r.t, r.work, "commit", "-m", "feat: "+id)
+	runGitCmd(r.t, r.work, "checkout", "main")
+	return &MRInfo{ID: id, Branch: branch, Target: "main"}
+}
+
+// originFiles lists the files on origin/main.
+func (r *batchTestRepo) originFiles() string {
+	r.t.Helper()
+	runGitCmd(r.t, r.work, "fetch", "origin")
+	return strings.Join(strings.Fields(runGitCmd(r.t, r.work, "ls-tree", "--name-only", "origin/main")), ",")
+}
+
+func runGitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func outcomeSummary(res *BatchResult) map[string]string {
+	got := make(map[string]string, len(res.Outcomes))
+	for _, o := range res.Outcomes {
+		switch {
+		case o.Deferred:
+			got[o.MR.ID] = "deferred"
+		case o.Result.Success:
+			got[o.MR.ID] = "merged"
+		case o.Result.Conflict:
+			got[o.MR.ID] = "conflict"
+		case o.Result.TestsFailed:
+			got[o.MR.ID] = "failed"
+		default:
+			got[o.MR.ID] = "error: " + o.Result.Error
+		}
+	}
+	return got
+}
+
+func TestProcessBatch_AllPass(t *testing.T) {
+	r := newBatchTestRepo(t)
+	batch := []*MRInfo{
+		r.addMR("a", map[string]string{"a.txt": "a\n"}),
+		r.addMR("b", map[string]string{"b.txt": "b\n"}),
+		r.addMR("c", map[string]string{"c.txt": "c\n"}),
+	}
+
+	res := r.e.ProcessBatch(context.Background(), batch)
+
+	want := map[string]string{"a": "merged", "b": "merged", "c": "merged"}
+	if got := outcomeSummary(res); !mapsEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+	if res.Checks != 1 {
+		t.Errorf("Checks = %d, want 1 for a clean batch", res.Checks)
+	}
+	if got := r.originFiles(); got != "README.md,a.txt,b.txt,c.txt" {
+		t.Errorf("origin/main files = %s", got)
+	}
+	for _, o := range res.Outcomes {
+		if o.Result.MergeCommit == "" {
+			t.Errorf("%s: missing merge commit", o.MR.ID)
+		}
+	}
+}
+
+func TestProcessBatch_BisectsCulprit(t *testing.T) {
+	r := newBatchTestRepo(t)
+	batch := []*MRInfo{
+		r.addMR("a", map[string]string{"a.txt": "a\n"}),
+		r.addMR("b", map[string]string{"b.txt": "b\n"}),
+		r.addMR("bad", map[string]string{"broken": "x\n"}),
+		r.addMR("d", map[string]string{"d.txt": "d\n"}),
+	}
+
+	res := r.e.ProcessBatch(context.Background(), batch)
+
+	want := map[string]string{"a": "merged", "b": "merged", "bad": "failed", "d": "merged"}
+	if got := outcomeSummary(res); !mapsEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+	if got := r.originFiles(); got != "README.md,a.txt,b.txt,d.txt" {
+		t.Errorf("origin/main files = %s", got)
+	}
+	for _, o := range res.Outcomes {
+		if o.MR.ID == "bad" && len(o.Result.Gates) == 0 {
+			t.Error("culprit should carry the failing gate results")
+		}
+	}
+}
+
+func TestProcessBatch_StackConflictDeferred(t *testing.T) {
+	r := newBatchTestRepo(t)
+	batch := []*MRInfo{
+		r.addMR("a", map[string]string{"shared.txt": "from a\n"}),
+		r.addMR("b", map[string]string{"shared.txt": "from b\n"}),
+		r.addMR("c", map[string]string{"c.txt": "c\n"}),
+	}
+
+	res := r.e.ProcessBatch(context.Background(), batch)
+
+	want := map[string]string{"a": "merged", "b": "deferred", "c": "merged"}
+	if got := outcomeSummary(res); !mapsEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+	if branch := strings.TrimSpace(runGitCmd(t, r.work, "rev-parse", "--abbrev-ref", "HEAD")); branch != "main" {
+		t.Errorf("worktree left on %s, want main", branch)
+	}
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEngineer_LoadConfig_BatchSize(t *testing.T) {
+	for _, tt := range []struct {
+		size    int
+		wantErr bool
+	}{
+		{size: 4},
+		{size: -1, wantErr: true},
+	} {
+		tmpDir := t.TempDir()
+		data := []byte(fmt.Sprintf(`{"merge_queue": {"batch_size": %d}}`, tt.size))
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+		err := e.LoadConfig()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("batch_size %d: expected error", tt.size)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("batch_size %d: %v", tt.size, err)
+		}
+		if e.Config().BatchSize != tt.size {
+			t.Errorf("BatchSize = %d, want %d", e.Config().BatchSize, tt.size)
+		}
+	}
+}
@@ -11,18 +11,20 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -54,14 +56,62 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// Needs lists gates that must pass (or be skipped) before this one
+	// starts. Together they form the gate DAG; a gate whose needs fail is
+	// blocked rather than run.
+	Needs []string `json:"needs,omitempty"`
+
+	// Paths limits the gate to MRs that change at least one matching file.
+	// Patterns are slash-separated globs where "**" matches any number of
+	// directories (e.g. "**/*.go", "docs/**").
+	Paths []string `json:"paths,omitempty"`
+
+	// IgnorePaths skips the gate when every changed file matches one of
+	// these patterns (e.g. ["docs/**", "**/*.md"] for a test suite).
+	IgnorePaths []string `json:"ignore_paths,omitempty"`
+
+	// Env is extra environment for the gate command.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Retries is how many more times a failing gate is run before it counts
+	// as failed. Use it for gates with known flaky tests.
+	Retries int `json:"retries,omitempty"`
+
+	// NoCache disables result caching for this gate. Set it for gates whose
+	// outcome depends on more than the merged tree (e.g. external services).
+	NoCache bool `json:"no_cache,omitempty"`
 }
 
 // GateResult holds the outcome of a single gate execution.
+//
+// A gate that did not run is Skipped: with Success when its path filters
+// excluded the MR, without Success when a gate it needs did not pass.
 type GateResult struct {
-	Name    string
-	Success bool
-	Error   string
-	Elapsed time.Duration
+	Name     string
+	Success  bool
+	Error    string
+	Elapsed  time.Duration
+	Attempts int  // Runs including retries (0 if not run)
+	Skipped  bool // Not run (see above)
+	Cached   bool // Passed on an earlier run against the same merged tree
+}
+
+// Status returns the result as one word: passed, failed, skipped, blocked,
+// or cached.
+func (r GateResult) Status() string {
+	switch {
+	case r.Cached:
+		return "cached"
+	case r.Skipped && r.Success:
+		return "skipped"
+	case r.Skipped:
+		return "blocked"
+	case r.Success:
+		return "passed"
+	default:
+		return "failed"
+	}
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -105,12 +155,19 @@ type MergeQueueConfig struct {
 
 	// Gates defines named quality gate commands to run before merging.
 	// When non-empty, gates replace the legacy RunTests/TestCommand path.
-	// Each gate runs as a shell command with an optional per-gate timeout.
+	// Each gate runs as a shell command with an optional per-gate timeout,
+	// and may depend on other gates via Needs.
 	Gates map[string]*GateConfig `json:"gates"`
 
 	// GatesParallel controls whether gates run concurrently.
-	// When true, all gates start simultaneously; any failure = overall failure.
+	// When true, every gate starts as soon as its needs have passed; any
+	// failure = overall failure. When false, gates run one at a time in
+	// dependency order and stop at the first failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// BatchSize is the most ready MRs that ProcessBatch stacks onto one
+	// speculative branch and gates together. 0 or 1 disables batching.
+	BatchSize int `json:"batch_size"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -143,8 +200,10 @@ type MRInfo struct {
 	RetryCount      int        // Conflict retry count
 	ConvoyID        string     // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt *time.Time // Convoy creation time
+	ConvoyDueAt     *time.Time // Convoy deadline, if set
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	TraceParent     string     // W3C traceparent of the submitting gt done
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -195,6 +254,10 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	gateCacheDir          string        // Passing gate results by merged tree (empty = no cache)
+
+	// logEvent records audit events such as gate results (nil = discard).
+	logEvent func(eventType, actor string, payload map[string]interface{}) error
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -227,8 +290,10 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		mergeSlotRelease: func(holder string) error {
 			return beadsClient.MergeSlotRelease(holder)
 		},
+		logEvent:              events.LogAudit,
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		gateCacheDir:          filepath.Join(r.Path, constants.DirRuntime, "gate-cache"),
 	}
 }
 
@@ -277,6 +342,7 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		BatchSize            *int                       `json:"batch_size"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -327,7 +393,23 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.Gates != nil {
 		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
 		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
+			gc := &GateConfig{
+				Cmd:         raw.Cmd,
+				Needs:       raw.Needs,
+				Paths:       raw.Paths,
+				IgnorePaths: raw.IgnorePaths,
+				Env:         raw.Env,
+				Retries:     raw.Retries,
+				NoCache:     raw.NoCache,
+			}
+			if raw.Retries < 0 {
+				return fmt.Errorf("gate %q retries must not be negative, got %d", name, raw.Retries)
+			}
+			for _, pattern := range append(append([]string{}, raw.Paths...), raw.IgnorePaths...) {
+				if err := validPathPattern(pattern); err != nil {
+					return fmt.Errorf("gate %q: %w", name, err)
+				}
+			}
 			if raw.Timeout != "" {
 				dur, err := time.ParseDuration(raw.Timeout)
 				if err != nil {
@@ -340,10 +422,19 @@ func (e *Engineer) LoadConfig() error {
 			}
 			e.config.Gates[name] = gc
 		}
+		if _, err := gateOrder(e.config.Gates); err != nil {
+			return fmt.Errorf("invalid gates: %w", err)
+		}
 	}
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.BatchSize != nil {
+		if *mqRaw.BatchSize < 0 {
+			return fmt.Errorf("batch_size must not be negative, got %d", *mqRaw.BatchSize)
+		}
+		e.config.BatchSize = *mqRaw.BatchSize
+	}
 
 	return nil
 }
@@ -351,8 +442,14 @@ func (e *Engineer) LoadConfig() error {
 // gateConfigRaw is the JSON-friendly representation of a gate config
 // with timeout as a string duration.
 type gateConfigRaw struct {
-	Cmd     string `json:"cmd"`
-	Timeout string `json:"timeout"`
+	Cmd         string            `json:"cmd"`
+	Timeout     string            `json:"timeout"`
+	Needs       []string          `json:"needs"`
+	Paths       []string          `json:"paths"`
+	IgnorePaths []string          `json:"ignore_paths"`
+	Env         map[string]string `json:"env"`
+	Retries     int               `json:"retries"`
+	NoCache     bool              `json:"no_cache"`
 }
 
 // Config returns the current merge queue configuration.
@@ -368,6 +465,11 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+
+	// Gates holds per-gate outcomes when quality gates ran, and GateTree the
+	// merged tree hash they were evaluated against.
+	Gates    []GateResult
+	GateTree string
 }
 
 // doMerge performs the actual git merge operation.
@@ -451,17 +553,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
-		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
-		if !gateResult.Success {
-			return gateResult
-		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
+	// Step 4: Run legacy tests if configured. Quality gates, when configured,
+	// replace this and run against the merged tree after Step 6.
+	if len(e.config.Gates) == 0 && e.config.RunTests && e.config.TestCommand != "" {
 		// Legacy test command path (backward compatible)
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
+		testCtx, span := telemetry.Start(ctx, "refinery.tests")
+		result := e.runTests(testCtx)
+		telemetry.End(span, processErr(result))
 		if !result.Success {
 			return ProcessResult{
 				Success:     false,
@@ -473,17 +572,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
+	originalMsg := e.squashMessage(branch, target, sourceIssue)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
 	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
@@ -512,6 +601,19 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 6.5: Run quality gates against the merged tree. The squash commit
+	// is still local, so a failure only needs the target reset to origin.
+	var gateResult ProcessResult
+	if len(e.config.Gates) > 0 {
+		gateResult = e.runGates(ctx, e.gateInputSince("HEAD~1"))
+		if !gateResult.Success {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after gate failure: %v\n", target, resetErr)
+			}
+			return gateResult
+		}
+	}
+
 	// Step 7: Acquire merge slot before push to serialize writes to the default branch.
 	// Only serialize pushes to the rig's default branch (typically main).
 	// Integration-branch and feature-branch pushes don't need serialization.
@@ -532,6 +634,8 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 				Success:     false,
 				SlotTimeout: errors.Is(slotErr, errMergeSlotTimeout),
 				Error:       fmt.Sprintf("failed to acquire merge slot before push: %v", slotErr),
+				Gates:       gateResult.Gates,
+				GateTree:    gateResult.GateTree,
 			}
 		}
 		defer func() {
@@ -554,8 +658,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after push failure: %v\n", target, resetErr)
 		}
 		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to push to origin: %v", err),
+			Success:  false,
+			Error:    fmt.Sprintf("failed to push to origin: %v", err),
+			Gates:    gateResult.Gates,
+			GateTree: gateResult.GateTree,
 		}
 	}
 
@@ -563,9 +669,27 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		Gates:       gateResult.Gates,
+		GateTree:    gateResult.GateTree,
 	}
 }
 
+// squashMessage returns the commit message for squash-merging branch. It
+// reuses the branch's own message to preserve the conventional commit format
+// (feat:/fix:) instead of creating redundant merge commits.
+func (e *Engineer) squashMessage(branch, target, sourceIssue string) string {
+	originalMsg, err := e.git.GetBranchCommitMessage(branch)
+	if err != nil {
+		// Fallback to a descriptive message if we can't get the original
+		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
+		if sourceIssue != "" {
+			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+	}
+	return originalMsg
+}
+
 func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	slotID, err := e.mergeSlotEnsureExists()
 	if err != nil {
@@ -681,131 +805,6 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 }
 
-// runGate executes a single quality gate command and returns the result.
-func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
-	start := time.Now()
-
-	if strings.TrimSpace(gate.Cmd) == "" {
-		return GateResult{
-			Name:    name,
-			Success: false,
-			Error:   "gate command is empty",
-			Elapsed: time.Since(start),
-		}
-	}
-
-	// Apply per-gate timeout if configured
-	gateCtx := ctx
-	if gate.Timeout > 0 {
-		var cancel context.CancelFunc
-		gateCtx, cancel = context.WithTimeout(ctx, gate.Timeout)
-		defer cancel()
-	}
-
-	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
-	cmd.Dir = e.workDir
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	elapsed := time.Since(start)
-
-	if err == nil {
-		return GateResult{
-			Name:    name,
-			Success: true,
-			Elapsed: elapsed,
-		}
-	}
-
-	errMsg := fmt.Sprintf("%v", err)
-	if gateCtx.Err() == context.DeadlineExceeded {
-		errMsg = fmt.Sprintf("timed out after %v", gate.Timeout)
-	}
-	if stderrStr := strings.TrimSpace(stderr.String()); stderrStr != "" {
-		// Cap stderr to avoid huge error messages
-		if len(stderrStr) > 500 {
-			stderrStr = stderrStr[:500] + "..."
-		}
-		errMsg = fmt.Sprintf("%s: %s", errMsg, stderrStr)
-	}
-
-	return GateResult{
-		Name:    name,
-		Success: false,
-		Error:   errMsg,
-		Elapsed: elapsed,
-	}
-}
-
-// runGates executes all configured quality gates and returns a ProcessResult.
-// Gates run in parallel if GatesParallel is true; otherwise sequentially.
-// Any single gate failure means overall failure.
-func (e *Engineer) runGates(ctx context.Context) ProcessResult {
-	gates := e.config.Gates
-	if len(gates) == 0 {
-		return ProcessResult{Success: true}
-	}
-
-	// Sort gate names for deterministic ordering
-	names := make([]string, 0, len(gates))
-	for name := range gates {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(names), e.config.GatesParallel)
-
-	var results []GateResult
-
-	if e.config.GatesParallel {
-		results = make([]GateResult, len(names))
-		var wg sync.WaitGroup
-		for i, name := range names {
-			wg.Add(1)
-			go func(idx int, gateName string) {
-				defer wg.Done()
-				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
-			}(i, name)
-		}
-		wg.Wait()
-	} else {
-		for _, name := range names {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
-			results = append(results, result)
-			if !result.Success {
-				// Sequential mode: stop on first failure
-				break
-			}
-		}
-	}
-
-	// Report results
-	var failures []string
-	for _, r := range results {
-		if r.Success {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
-		} else {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
-			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
-		}
-	}
-
-	if len(failures) > 0 {
-		return ProcessResult{
-			Success:     false,
-			TestsFailed: true,
-			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
-		}
-	}
-
-	_, _ = fmt.Fprintln(e.output, "[Engineer] All quality gates passed")
-	return ProcessResult{Success: true}
-}
-
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
 // This ensures crew members have access to newly merged code without manual sync.
 func (e *Engineer) syncCrewWorkspaces() {
@@ -838,8 +837,20 @@ func (e *Engineer) syncCrewWorkspaces() {
 	}
 }
 
-// ProcessMRInfo processes a merge request from MRInfo.
-func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
+// ProcessMRInfo processes a merge request from MRInfo. The work is traced as
+// part of the MR's trace when it carries a traceparent.
+func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) (result ProcessResult) {
+	ctx, span := telemetry.Start(telemetry.WithTraceParent(ctx, mr.TraceParent), "refinery.process_mr",
+		attribute.String("gt.mr", mr.ID),
+		attribute.String("gt.bead", mr.SourceIssue),
+		attribute.String("gt.branch", mr.Branch),
+		attribute.String("gt.target", mr.Target),
+		attribute.String("gt.rig", e.rig.Name))
+	defer func() {
+		span.SetAttributes(attribute.Bool("gt.conflict", result.Conflict))
+		telemetry.End(span, processErr(result))
+	}()
+
 	// MR fields are directly on the struct
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mr.Branch)
@@ -851,6 +862,14 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
 
+// processErr returns a failed result's error for span status, or nil.
+func processErr(r ProcessResult) error {
+	if r.Success {
+		return nil
+	}
+	return errors.New(r.Error)
+}
+
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Release merge slot if this was a conflict resolution
@@ -879,6 +898,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
 			newDesc := beads.SetMRFields(mrBead, mrFields)
+			if len(result.Gates) > 0 {
+				newDesc = withGateRuns(&beads.Issue{Description: newDesc}, result, time.Now())
+			}
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
 			}
@@ -937,6 +959,10 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 // For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
+	// Keep the gate history even when the merge didn't land, so re-runs show
+	// what changed (and what the cache saved).
+	e.recordGateRuns(mr.ID, result)
+
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
 	// The MR stays in queue and will be retried on the next poll cycle.
 	// No polecat notification needed since there's nothing for a worker to fix.
@@ -1127,16 +1153,31 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 	return issue.Status != "closed", nil
 }
 
+// MRInfoFromIssue converts a merge-request bead into an MRInfo, or returns
+// nil if the bead has no MR fields.
+func MRInfoFromIssue(issue *beads.Issue) *MRInfo {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil
+	}
+	return issueToMRInfo(issue, fields)
+}
+
 // issueToMRInfo converts a beads issue (with parsed MR fields) into an MRInfo.
 // Shared by ListReadyMRs, ListBlockedMRs, and ListAllOpenMRs.
 func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
-	// Parse convoy created_at if present
-	var convoyCreatedAt *time.Time
+	// Parse convoy timestamps if present
+	var convoyCreatedAt, convoyDueAt *time.Time
 	if fields.ConvoyCreatedAt != "" {
 		if t, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
 			convoyCreatedAt = &t
 		}
 	}
+	if fields.ConvoyDueAt != "" {
+		if t, err := time.Parse(time.RFC3339, fields.ConvoyDueAt); err == nil {
+			convoyDueAt = &t
+		}
+	}
 
 	// Parse issue timestamps
 	var createdAt, updatedAt time.Time
@@ -1161,9 +1202,11 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		Title:           issue.Title,
 		Priority:        issue.Priority,
 		AgentBead:       fields.AgentBead,
+		TraceParent:     fields.TraceParent,
 		RetryCount:      fields.RetryCount,
 		ConvoyID:        fields.ConvoyID,
 		ConvoyCreatedAt: convoyCreatedAt,
+		ConvoyDueAt:     convoyDueAt,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -438,7 +439,7 @@ func TestRunGates_Sequential_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), gateInput{})
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -462,7 +463,7 @@ func TestRunGates_Sequential_StopsOnFirstFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), gateInput{})
 	if result.Success {
 		t.Error("expected failure")
 	}
@@ -489,7 +490,7 @@ func TestRunGates_Parallel_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), gateInput{})
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -507,7 +508,7 @@ func TestRunGates_Parallel_AnyFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), gateInput{})
 	if result.Success {
 		t.Error("expected failure when any gate fails")
 	}
@@ -526,7 +527,7 @@ func TestRunGates_Empty(t *testing.T) {
 	e.output = io.Discard
 	e.config.Gates = nil
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), gateInput{})
 	if !result.Success {
 		t.Error("expected success with no gates configured")
 	}
@@ -540,6 +541,18 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 	}
 }
 
+func TestIssueToMRInfo_TraceParent(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	issue := &beads.Issue{
+		ID:          "gt-mr1",
+		Description: "branch: polecat/nux\ntarget: main\nsource_issue: gt-abc\ntraceparent: " + tp,
+	}
+	mr := issueToMRInfo(issue, beads.ParseMRFields(issue))
+	if mr.TraceParent != tp {
+		t.Errorf("TraceParent = %q, want %q", mr.TraceParent, tp)
+	}
+}
+
 func TestPostMergeConvoyCheck_NoTownBeads(t *testing.T) {
 	// postMergeConvoyCheck should silently return when town-level beads doesn't exist
 	tmpDir, err := os.MkdirTemp("", "engineer-convoy-test-*")
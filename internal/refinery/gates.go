@@ -0,0 +1,559 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxGateHistory caps the gate_run lines kept on an MR bead. Older runs are
+// dropped first.
+const maxGateHistory = 50
+
+// Gate cache entries are pruned after each gate run: entries older than
+// gateCacheMaxAge go, and only the newest maxGateCacheEntries are kept.
+const (
+	gateCacheMaxAge     = 7 * 24 * time.Hour
+	maxGateCacheEntries = 1000
+)
+
+// gateInput is what the gate pipeline knows about the MR under test.
+type gateInput struct {
+	// tree is the merged tree hash. Empty disables the result cache.
+	tree string
+
+	// changed lists the files the MR changes. Nil disables path filters
+	// (every gate runs); an empty, non-nil slice means nothing changed.
+	changed []string
+}
+
+// gateInputSince describes HEAD for the gate pipeline: its tree, and the
+// files changed since base. Lookup failures only cost caching or path
+// filtering, so they are warnings.
+func (e *Engineer) gateInputSince(base string) gateInput {
+	var in gateInput
+	if tree, err := e.git.Rev("HEAD^{tree}"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not resolve merged tree, gate cache disabled: %v\n", err)
+	} else {
+		in.tree = tree
+	}
+	if files, err := e.git.ChangedFiles(base, "HEAD"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files, path filters disabled: %v\n", err)
+	} else {
+		in.changed = append([]string{}, files...)
+	}
+	return in
+}
+
+// gateOrder returns the gate names in dependency order, breaking ties by
+// name so runs are deterministic. It fails on unknown needs and cycles.
+func gateOrder(gates map[string]*GateConfig) ([]string, error) {
+	indegree := make(map[string]int, len(gates))
+	dependents := make(map[string][]string, len(gates))
+	for name := range gates {
+		indegree[name] = 0
+	}
+	for name, gate := range gates {
+		for _, need := range gate.Needs {
+			if _, ok := gates[need]; !ok {
+				return nil, fmt.Errorf("gate %q needs unknown gate %q", name, need)
+			}
+			indegree[name]++
+			dependents[need] = append(dependents[need], name)
+		}
+	}
+
+	var ready []string
+	for name, n := range indegree {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(gates))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dep := range dependents[name] {
+			indegree[dep]--
+			if indegree[dep] == 0 {
+				ready = append(ready, dep)
+			}
+		}
+	}
+
+	if len(order) < len(gates) {
+		var cyclic []string
+		for name, n := range indegree {
+			if n > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("dependency cycle among gates: %s", strings.Join(cyclic, ", "))
+	}
+	return order, nil
+}
+
+// validPathPattern checks that a gate path filter is a usable glob.
+func validPathPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("empty path pattern")
+	}
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchPath reports whether file matches pattern. Both are slash-separated;
+// "**" matches zero or more whole path segments and other segments follow
+// path.Match.
+func matchPath(pattern, file string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			if len(pat) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pat[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func matchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if matchPath(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// gateApplies reports whether a gate's path filters select an MR that
+// changes the given files.
+func gateApplies(gate *GateConfig, changed []string) bool {
+	if len(gate.Paths) > 0 {
+		hit := false
+		for _, f := range changed {
+			if matchAny(gate.Paths, f) {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			return false
+		}
+	}
+	if len(gate.IgnorePaths) > 0 {
+		for _, f := range changed {
+			if !matchAny(gate.IgnorePaths, f) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// gateCacheEntry records a gate that passed against a merged tree.
+type gateCacheEntry struct {
+	Gate     string        `json:"gate"`
+	Tree     string        `json:"tree"`
+	Elapsed  time.Duration `json:"elapsed"`
+	PassedAt time.Time     `json:"passed_at"`
+}
+
+// gateCacheKey identifies a gate run: the merged tree plus everything in the
+// gate config that can change its outcome.
+func gateCacheKey(tree, name string, gate *GateConfig) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "tree=%s\x00gate=%s\x00cmd=%s\x00", tree, name, gate.Cmd)
+	keys := make([]string, 0, len(gate.Env))
+	for k := range gate.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "env:%s=%s\x00", k, gate.Env[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gateCacheHit reports whether the gate already passed for key.
+func (e *Engineer) gateCacheHit(key string) bool {
+	if e.gateCacheDir == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(e.gateCacheDir, key+".json"))
+	if err != nil {
+		return false
+	}
+	var entry gateCacheEntry
+	return json.Unmarshal(data, &entry) == nil
+}
+
+// storeGateCache records a passing gate for key. Failures are only logged:
+// the cache is an optimization.
+func (e *Engineer) storeGateCache(key, tree string, r GateResult) {
+	if e.gateCacheDir == "" {
+		return
+	}
+	entry := gateCacheEntry{Gate: r.Name, Tree: tree, Elapsed: r.Elapsed, PassedAt: time.Now().UTC()}
+	if err := util.EnsureDirAndWriteJSON(filepath.Join(e.gateCacheDir, key+".json"), entry); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to cache gate %q result: %v\n", r.Name, err)
+	}
+}
+
+// pruneGateCache removes cache entries older than gateCacheMaxAge, then the
+// oldest entries beyond maxGateCacheEntries. Failures are ignored: a stale
+// entry only costs disk space.
+func (e *Engineer) pruneGateCache(now time.Time) {
+	if e.gateCacheDir == "" {
+		return
+	}
+	dirEntries, err := os.ReadDir(e.gateCacheDir)
+	if err != nil {
+		return
+	}
+
+	type cached struct {
+		path    string
+		modTime time.Time
+	}
+	var kept []cached
+	for _, de := range dirEntries {
+		if de.IsDir() || filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(e.gateCacheDir, de.Name())
+		if now.Sub(info.ModTime()) > gateCacheMaxAge {
+			_ = os.Remove(path)
+			continue
+		}
+		kept = append(kept, cached{path: path, modTime: info.ModTime()})
+	}
+	if len(kept) <= maxGateCacheEntries {
+		return
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.After(kept[j].modTime) })
+	for _, c := range kept[maxGateCacheEntries:] {
+		_ = os.Remove(c.path)
+	}
+}
+
+// runGateTraced runs a gate under a refinery.gate span.
+func (e *Engineer) runGateTraced(ctx context.Context, name string, gate *GateConfig) GateResult {
+	ctx, span := telemetry.Start(ctx, "refinery.gate", attribute.String("gt.gate", name))
+	result := e.runGate(ctx, name, gate)
+	var err error
+	if !result.Success {
+		err = errors.New(result.Error)
+	}
+	telemetry.End(span, err)
+	return result
+}
+
+// runGate executes a single quality gate command and returns the result.
+func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	start := time.Now()
+
+	if strings.TrimSpace(gate.Cmd) == "" {
+		return GateResult{
+			Name:    name,
+			Success: false,
+			Error:   "gate command is empty",
+			Elapsed: time.Since(start),
+		}
+	}
+
+	// Apply per-gate timeout if configured
+	gateCtx := ctx
+	if gate.Timeout > 0 {
+		var cancel context.CancelFunc
+		gateCtx, cancel = context.WithTimeout(ctx, gate.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
+	cmd.Dir = e.workDir
+	if len(gate.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range gate.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	elapsed := time.Since(start)
+
+	if err == nil {
+		return GateResult{
+			Name:    name,
+			Success: true,
+			Elapsed: elapsed,
+		}
+	}
+
+	errMsg := fmt.Sprintf("%v", err)
+	if gateCtx.Err() == context.DeadlineExceeded {
+		errMsg = fmt.Sprintf("timed out after %v", gate.Timeout)
+	}
+	if stderrStr := strings.TrimSpace(stderr.String()); stderrStr != "" {
+		// Cap stderr to avoid huge error messages
+		if len(stderrStr) > 500 {
+			stderrStr = stderrStr[:500] + "..."
+		}
+		errMsg = fmt.Sprintf("%s: %s", errMsg, stderrStr)
+	}
+
+	return GateResult{
+		Name:    name,
+		Success: false,
+		Error:   errMsg,
+		Elapsed: elapsed,
+	}
+}
+
+// evalGate decides a single gate: blocked by a failed need, skipped by its
+// path filters, satisfied from the cache, or run (with retries).
+func (e *Engineer) evalGate(ctx context.Context, name string, gate *GateConfig, in gateInput, blockedBy string) GateResult {
+	if blockedBy != "" {
+		return GateResult{Name: name, Skipped: true, Error: fmt.Sprintf("needs %q, which did not pass", blockedBy)}
+	}
+	if in.changed != nil && !gateApplies(gate, in.changed) {
+		return GateResult{Name: name, Success: true, Skipped: true}
+	}
+
+	var key string
+	if in.tree != "" && !gate.NoCache {
+		key = gateCacheKey(in.tree, name, gate)
+		if e.gateCacheHit(key) {
+			return GateResult{Name: name, Success: true, Cached: true}
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gate.Cmd)
+	var result GateResult
+	var total time.Duration
+	for attempt := 1; ; attempt++ {
+		result = e.runGateTraced(ctx, name, gate)
+		total += result.Elapsed
+		result.Attempts = attempt
+		if result.Success || attempt > gate.Retries || ctx.Err() != nil {
+			break
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: attempt %d/%d failed, retrying - %s\n",
+			name, attempt, gate.Retries+1, result.Error)
+	}
+	result.Elapsed = total
+
+	if result.Success && key != "" {
+		e.storeGateCache(key, in.tree, result)
+	}
+	return result
+}
+
+// firstFailedNeed returns the first need of gate that did not pass, or "".
+func firstFailedNeed(gate *GateConfig, done map[string]GateResult) string {
+	for _, need := range gate.Needs {
+		if r, ok := done[need]; ok && !r.Success {
+			return need
+		}
+	}
+	return ""
+}
+
+// runGates executes the configured quality gates as a DAG and returns a
+// ProcessResult carrying every gate's outcome.
+// Gates run in parallel if GatesParallel is true; otherwise sequentially.
+// Any single gate failure means overall failure.
+func (e *Engineer) runGates(ctx context.Context, in gateInput) ProcessResult {
+	gates := e.config.Gates
+	if len(gates) == 0 {
+		return ProcessResult{Success: true}
+	}
+
+	order, err := gateOrder(gates)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid gates: %v", err),
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(order), e.config.GatesParallel)
+
+	var results []GateResult
+	done := make(map[string]GateResult, len(order))
+
+	if e.config.GatesParallel {
+		// Each gate waits for its needs to finish, then starts.
+		results = make([]GateResult, len(order))
+		finished := make(map[string]chan struct{}, len(order))
+		for _, name := range order {
+			finished[name] = make(chan struct{})
+		}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for i, name := range order {
+			wg.Add(1)
+			go func(idx int, gateName string) {
+				defer wg.Done()
+				defer close(finished[gateName])
+				gate := gates[gateName]
+				for _, need := range gate.Needs {
+					<-finished[need]
+				}
+				mu.Lock()
+				blockedBy := firstFailedNeed(gate, done)
+				mu.Unlock()
+
+				r := e.evalGate(ctx, gateName, gate, in, blockedBy)
+				mu.Lock()
+				done[gateName] = r
+				mu.Unlock()
+				results[idx] = r
+			}(i, name)
+		}
+		wg.Wait()
+	} else {
+		for _, name := range order {
+			result := e.evalGate(ctx, name, gates[name], in, "")
+			results = append(results, result)
+			done[name] = result
+			if !result.Success {
+				// Sequential mode: stop on first failure
+				break
+			}
+		}
+	}
+
+	e.pruneGateCache(time.Now())
+
+	// Report results
+	var failures []string
+	for _, r := range results {
+		if r.Attempts > 0 && e.logEvent != nil {
+			_ = e.logEvent(events.TypeGateResult, e.rig.Name+"/refinery",
+				events.GatePayload(e.rig.Name, r.Name, r.Success, r.Elapsed))
+		}
+		switch r.Status() {
+		case "passed":
+			note := ""
+			if r.Attempts > 1 {
+				note = fmt.Sprintf(", %d attempts", r.Attempts)
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v%s)\n", r.Name, r.Elapsed.Truncate(time.Millisecond), note)
+		case "cached":
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (cached for this tree)\n", r.Name)
+		case "skipped":
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: skipped (no matching changes)\n", r.Name)
+		case "blocked":
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: not run - %s\n", r.Name, r.Error)
+		default:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
+			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+		}
+	}
+
+	if len(failures) > 0 {
+		return ProcessResult{
+			Success:     false,
+			TestsFailed: true,
+			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			Gates:       results,
+			GateTree:    in.tree,
+		}
+	}
+
+	_, _ = fmt.Fprintln(e.output, "[Engineer] All quality gates passed")
+	return ProcessResult{Success: true, Gates: results, GateTree: in.tree}
+}
+
+// withGateRuns returns issue's description with the gate outcomes in result
+// appended to its gate history.
+func withGateRuns(issue *beads.Issue, result ProcessResult, now time.Time) string {
+	runs := beads.ParseGateRuns(issue)
+	tree := result.GateTree
+	if len(tree) > 12 {
+		tree = tree[:12]
+	}
+	at := now.UTC().Format(time.RFC3339)
+	for _, r := range result.Gates {
+		runs = append(runs, beads.GateRun{
+			Gate:     r.Name,
+			Status:   r.Status(),
+			Elapsed:  r.Elapsed,
+			Attempts: r.Attempts,
+			Tree:     tree,
+			At:       at,
+		})
+	}
+	if len(runs) > maxGateHistory {
+		runs = runs[len(runs)-maxGateHistory:]
+	}
+	return beads.SetGateRuns(issue, runs)
+}
+
+// recordGateRuns appends the gate outcomes in result to the MR bead's gate
+// history. Best-effort: failures are logged.
+func (e *Engineer) recordGateRuns(mrID string, result ProcessResult) {
+	if mrID == "" || len(result.Gates) == 0 {
+		return
+	}
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR %s to record gate results: %v\n", mrID, err)
+		return
+	}
+	desc := withGateRuns(issue, result, time.Now())
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record gate results on MR %s: %v\n", mrID, err)
+	}
+}
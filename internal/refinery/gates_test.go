@@ -0,0 +1,448 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func newGateTestEngineer(t *testing.T, gates map[string]*GateConfig, parallel bool) *Engineer {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	e.config.Gates = gates
+	e.config.GatesParallel = parallel
+	return e
+}
+
+func gateStatuses(result ProcessResult) map[string]string {
+	got := make(map[string]string, len(result.Gates))
+	for _, g := range result.Gates {
+		got[g.Name] = g.Status()
+	}
+	return got
+}
+
+func TestGateOrder(t *testing.T) {
+	order, err := gateOrder(map[string]*GateConfig{
+		"test":  {Needs: []string{"build"}},
+		"lint":  {},
+		"build": {},
+		"e2e":   {Needs: []string{"test", "lint"}},
+	})
+	if err != nil {
+		t.Fatalf("gateOrder: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "build,lint,test,e2e" {
+		t.Errorf("order = %s, want build,lint,test,e2e", got)
+	}
+
+	_, err = gateOrder(map[string]*GateConfig{"test": {Needs: []string{"nope"}}})
+	if err == nil || !strings.Contains(err.Error(), `unknown gate "nope"`) {
+		t.Errorf("unknown need error = %v", err)
+	}
+
+	_, err = gateOrder(map[string]*GateConfig{
+		"a":  {Needs: []string{"b"}},
+		"b":  {Needs: []string{"a"}},
+		"ok": {},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cycle among gates: a, b") {
+		t.Errorf("cycle error = %v", err)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"docs/**", "docs/guide.md", true},
+		{"docs/**", "docs/a/b/c.md", true},
+		{"docs/**", "internal/docs.go", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/design/plan.md", true},
+		{"**/*.md", "main.go", false},
+		{"*.go", "main.go", true},
+		{"*.go", "internal/main.go", false},
+		{"internal/**/*_test.go", "internal/refinery/gates_test.go", true},
+		{"internal/**/*_test.go", "internal/gates_test.go", true},
+		{"go.mod", "go.mod", true},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestGateApplies(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    *GateConfig
+		changed []string
+		want    bool
+	}{
+		{"no filters", &GateConfig{}, []string{"docs/a.md"}, true},
+		{"paths hit", &GateConfig{Paths: []string{"**/*.go"}}, []string{"docs/a.md", "cmd/main.go"}, true},
+		{"paths miss", &GateConfig{Paths: []string{"**/*.go"}}, []string{"docs/a.md"}, false},
+		{"docs-only ignored", &GateConfig{IgnorePaths: []string{"docs/**", "**/*.md"}}, []string{"docs/a.md", "README.md"}, false},
+		{"code not ignored", &GateConfig{IgnorePaths: []string{"docs/**"}}, []string{"docs/a.md", "main.go"}, true},
+		{"nothing changed", &GateConfig{IgnorePaths: []string{"docs/**"}}, []string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gateApplies(tt.gate, tt.changed); got != tt.want {
+				t.Errorf("gateApplies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGates_DAGBlocksDependents(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			markerDir := t.TempDir()
+			e := newGateTestEngineer(t, map[string]*GateConfig{
+				"build": {Cmd: "exit 1"},
+				"test":  {Cmd: fmt.Sprintf("touch %s/test", markerDir), Needs: []string{"build"}},
+				"lint":  {Cmd: "true"},
+			}, parallel)
+
+			result := e.runGates(context.Background(), gateInput{})
+			if result.Success || !result.TestsFailed {
+				t.Fatalf("expected gate failure, got %+v", result)
+			}
+			if _, err := os.Stat(filepath.Join(markerDir, "test")); !os.IsNotExist(err) {
+				t.Error("gate 'test' ran even though its need 'build' failed")
+			}
+			if strings.Contains(result.Error, "test:") {
+				t.Errorf("blocked gate should not be reported as a failure: %s", result.Error)
+			}
+
+			got := gateStatuses(result)
+			if got["build"] != "failed" {
+				t.Errorf("build = %q, want failed", got["build"])
+			}
+			if parallel {
+				// Independent gates still run; dependents are blocked.
+				if got["lint"] != "passed" || got["test"] != "blocked" {
+					t.Errorf("statuses = %v, want lint passed and test blocked", got)
+				}
+			} else if len(result.Gates) != 1 {
+				// Sequential mode stops at the first failure (build sorts first).
+				t.Errorf("sequential ran %v, want only build", got)
+			}
+		})
+	}
+}
+
+func TestRunGates_ParallelWaitsForNeeds(t *testing.T) {
+	markerDir := t.TempDir()
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"build": {Cmd: fmt.Sprintf("sleep 0.2 && touch %s/built", markerDir)},
+		"test":  {Cmd: fmt.Sprintf("test -f %s/built", markerDir), Needs: []string{"build"}},
+	}, true)
+
+	result := e.runGates(context.Background(), gateInput{})
+	if !result.Success {
+		t.Fatalf("expected success (test should start after build), got: %s", result.Error)
+	}
+}
+
+func TestRunGates_PathFiltersSkip(t *testing.T) {
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"test":  {Cmd: "exit 1", IgnorePaths: []string{"docs/**", "**/*.md"}},
+		"docs":  {Cmd: "true", Paths: []string{"docs/**"}},
+		"after": {Cmd: "true", Needs: []string{"test"}},
+	}, false)
+
+	result := e.runGates(context.Background(), gateInput{changed: []string{"docs/guide.md", "README.md"}})
+	if !result.Success {
+		t.Fatalf("docs-only MR should skip the failing test gate, got: %s", result.Error)
+	}
+	got := gateStatuses(result)
+	if got["test"] != "skipped" || got["docs"] != "passed" || got["after"] != "passed" {
+		t.Errorf("statuses = %v, want test skipped, docs and after passed", got)
+	}
+
+	// Without a changed-file list, filters are off and every gate runs.
+	result = e.runGates(context.Background(), gateInput{})
+	if result.Success {
+		t.Error("expected test gate to run and fail when changed files are unknown")
+	}
+}
+
+func TestRunGates_RetriesFlakyGate(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "count")
+	// Fails on the first two runs, passes on the third.
+	flaky := fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; [ $n -ge 3 ]`, counter)
+
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"flaky": {Cmd: flaky, Retries: 2},
+	}, false)
+	result := e.runGates(context.Background(), gateInput{})
+	if !result.Success {
+		t.Fatalf("expected flaky gate to pass on retry, got: %s", result.Error)
+	}
+	if result.Gates[0].Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", result.Gates[0].Attempts)
+	}
+
+	_ = os.Remove(counter)
+	e.config.Gates["flaky"].Retries = 1
+	result = e.runGates(context.Background(), gateInput{})
+	if result.Success {
+		t.Error("expected failure when retries run out")
+	}
+	if result.Gates[0].Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", result.Gates[0].Attempts)
+	}
+}
+
+func TestRunGates_Env(t *testing.T) {
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"env": {Cmd: `[ "$GATE_MODE" = "strict" ] && [ -n "$PATH" ]`, Env: map[string]string{"GATE_MODE": "strict"}},
+	}, false)
+	if result := e.runGates(context.Background(), gateInput{}); !result.Success {
+		t.Fatalf("gate env not applied: %s", result.Error)
+	}
+}
+
+func TestRunGates_CacheByTree(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	cmd := fmt.Sprintf("echo run >> %s", counter)
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"suite":   {Cmd: cmd},
+		"nocache": {Cmd: cmd, NoCache: true},
+	}, false)
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+
+	in := gateInput{tree: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"}
+	first := e.runGates(context.Background(), in)
+	if !first.Success || runs() != 2 {
+		t.Fatalf("first run: success=%v runs=%d, want both gates run", first.Success, runs())
+	}
+	if first.GateTree != in.tree {
+		t.Errorf("GateTree = %q, want %q", first.GateTree, in.tree)
+	}
+
+	second := e.runGates(context.Background(), in)
+	if got := gateStatuses(second); got["suite"] != "cached" || got["nocache"] != "passed" {
+		t.Errorf("second run statuses = %v, want suite cached, nocache passed", got)
+	}
+	if runs() != 3 {
+		t.Errorf("runs = %d after re-queue, want only the no_cache gate to rerun", runs())
+	}
+
+	// A different merged tree, or a changed command, misses the cache.
+	_ = e.runGates(context.Background(), gateInput{tree: "different"})
+	if runs() != 5 {
+		t.Errorf("runs = %d, want a new tree to rerun everything", runs())
+	}
+	e.config.Gates["suite"].Cmd = cmd + " # changed"
+	third := e.runGates(context.Background(), in)
+	if got := gateStatuses(third); got["suite"] != "passed" {
+		t.Errorf("changed command status = %q, want passed (cache miss)", got["suite"])
+	}
+}
+
+func TestPruneGateCache(t *testing.T) {
+	e := newGateTestEngineer(t, nil, false)
+	now := time.Now()
+	write := func(name string, age time.Duration) {
+		t.Helper()
+		path := filepath.Join(e.gateCacheDir, name+".json")
+		if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(e.gateCacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write("stale", gateCacheMaxAge+time.Hour)
+	for i := 0; i < maxGateCacheEntries+2; i++ {
+		write(fmt.Sprintf("entry-%04d", i), time.Duration(i)*time.Second)
+	}
+	e.pruneGateCache(now)
+
+	entries, err := os.ReadDir(e.gateCacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxGateCacheEntries {
+		t.Errorf("%d entries after prune, want %d", len(entries), maxGateCacheEntries)
+	}
+	if e.gateCacheHit("stale") {
+		t.Error("entry older than the max age was kept")
+	}
+	// The oldest entries beyond the cap go; the newest stay.
+	if e.gateCacheHit(fmt.Sprintf("entry-%04d", maxGateCacheEntries+1)) || !e.gateCacheHit("entry-0000") {
+		t.Error("prune should drop the oldest entries over the cap")
+	}
+}
+
+func TestRunGates_FailureNotCached(t *testing.T) {
+	e := newGateTestEngineer(t, map[string]*GateConfig{"fail": {Cmd: "exit 1"}}, false)
+	in := gateInput{tree: "abc"}
+	_ = e.runGates(context.Background(), in)
+	if got := gateStatuses(e.runGates(context.Background(), in)); got["fail"] != "failed" {
+		t.Errorf("status = %q, failures must not be cached", got["fail"])
+	}
+}
+
+func TestRunGates_LogsGateResults(t *testing.T) {
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"ok":   {Cmd: "true"},
+		"fail": {Cmd: "exit 1", Needs: []string{"ok"}},
+	}, false)
+	var logged []string
+	e.logEvent = func(eventType, actor string, payload map[string]interface{}) error {
+		logged = append(logged, fmt.Sprintf("%s %s %v %v", eventType, actor, payload["gate"], payload["result"]))
+		return nil
+	}
+	_ = e.runGates(context.Background(), gateInput{})
+	want := []string{
+		"gate_result test-rig/refinery ok pass",
+		"gate_result test-rig/refinery fail fail",
+	}
+	if !reflect.DeepEqual(logged, want) {
+		t.Errorf("logged = %v, want %v", logged, want)
+	}
+}
+
+func TestRunGates_InvalidDAG(t *testing.T) {
+	e := newGateTestEngineer(t, map[string]*GateConfig{
+		"a": {Cmd: "true", Needs: []string{"a"}},
+	}, false)
+	result := e.runGates(context.Background(), gateInput{})
+	if result.Success || !strings.Contains(result.Error, "cycle") {
+		t.Errorf("expected cycle error, got %+v", result)
+	}
+}
+
+func TestEngineer_LoadConfig_GatePipeline(t *testing.T) {
+	writeConfig := func(t *testing.T, gates map[string]interface{}) *Engineer {
+		t.Helper()
+		dir := t.TempDir()
+		data, _ := json.Marshal(map[string]interface{}{
+			"merge_queue": map[string]interface{}{"gates": gates},
+		})
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return NewEngineer(&rig.Rig{Name: "test-rig", Path: dir})
+	}
+
+	e := writeConfig(t, map[string]interface{}{
+		"build": map[string]interface{}{"cmd": "go build ./..."},
+		"test": map[string]interface{}{
+			"cmd":          "go test ./...",
+			"timeout":      "20m",
+			"needs":        []string{"build"},
+			"ignore_paths": []string{"docs/**", "**/*.md"},
+			"env":          map[string]string{"GOFLAGS": "-count=1"},
+			"retries":      2,
+		},
+		"e2e": map[string]interface{}{
+			"cmd":      "make e2e",
+			"needs":    []string{"test"},
+			"paths":    []string{"internal/**"},
+			"no_cache": true,
+		},
+	})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	test := e.config.Gates["test"]
+	if strings.Join(test.Needs, ",") != "build" || test.Retries != 2 || test.Timeout != 20*time.Minute {
+		t.Errorf("test gate = %+v", test)
+	}
+	if len(test.IgnorePaths) != 2 || test.Env["GOFLAGS"] != "-count=1" {
+		t.Errorf("test gate filters/env = %+v", test)
+	}
+	if e2e := e.config.Gates["e2e"]; !e2e.NoCache || e2e.Paths[0] != "internal/**" {
+		t.Errorf("e2e gate = %+v", e2e)
+	}
+
+	bad := []struct {
+		name  string
+		gates map[string]interface{}
+	}{
+		{"unknown need", map[string]interface{}{"test": map[string]interface{}{"cmd": "true", "needs": []string{"build"}}}},
+		{"cycle", map[string]interface{}{
+			"a": map[string]interface{}{"cmd": "true", "needs": []string{"b"}},
+			"b": map[string]interface{}{"cmd": "true", "needs": []string{"a"}},
+		}},
+		{"negative retries", map[string]interface{}{"a": map[string]interface{}{"cmd": "true", "retries": -1}}},
+		{"bad pattern", map[string]interface{}{"a": map[string]interface{}{"cmd": "true", "paths": []string{"[docs"}}}},
+	}
+	for _, tt := range bad {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := writeConfig(t, tt.gates).LoadConfig(); err == nil {
+				t.Error("expected LoadConfig error")
+			}
+		})
+	}
+}
+
+func TestWithGateRuns(t *testing.T) {
+	issue := &beads.Issue{Description: "branch: polecat/nux\ngate_run: build failed 1s attempts=1 tree=aaaaaaaaaaaa at=2026-10-15T00:00:00Z"}
+	result := ProcessResult{
+		GateTree: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Gates: []GateResult{
+			{Name: "build", Success: true, Elapsed: 2 * time.Second, Attempts: 1},
+			{Name: "test", Success: true, Cached: true},
+			{Name: "docs", Success: true, Skipped: true},
+		},
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	runs := beads.ParseGateRuns(&beads.Issue{Description: withGateRuns(issue, result, now)})
+	if len(runs) != 4 {
+		t.Fatalf("got %d runs, want history of 1 plus 3 new: %+v", len(runs), runs)
+	}
+	if runs[0].Status != "failed" {
+		t.Errorf("earlier run lost: %+v", runs[0])
+	}
+	want := beads.GateRun{Gate: "build", Status: "passed", Elapsed: 2 * time.Second, Attempts: 1, Tree: "4b825dc642cb", At: "2026-10-16T12:00:00Z"}
+	if runs[1] != want {
+		t.Errorf("run = %+v, want %+v", runs[1], want)
+	}
+	if runs[2].Status != "cached" || runs[3].Status != "skipped" {
+		t.Errorf("statuses = %s, %s; want cached, skipped", runs[2].Status, runs[3].Status)
+	}
+
+	// History is capped, dropping the oldest runs.
+	many := ProcessResult{GateTree: "t"}
+	for i := 0; i < maxGateHistory+5; i++ {
+		many.Gates = append(many.Gates, GateResult{Name: fmt.Sprintf("g%d", i), Success: true, Attempts: 1})
+	}
+	runs = beads.ParseGateRuns(&beads.Issue{Description: withGateRuns(issue, many, now)})
+	if len(runs) != maxGateHistory || runs[len(runs)-1].Gate != fmt.Sprintf("g%d", maxGateHistory+4) {
+		t.Errorf("capped history has %d runs ending in %s", len(runs), runs[len(runs)-1].Gate)
+	}
+}
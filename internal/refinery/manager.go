@@ -56,13 +56,18 @@ func (m *Manager) SessionName() string {
 	return session.RefinerySessionName(session.PrefixFor(m.rig.Name))
 }
 
+// sessions returns the town's session backend (tmux or headless PTYs).
+func (m *Manager) sessions() session.SessionBackend {
+	return session.NewBackend(filepath.Dir(m.rig.Path))
+}
+
 // IsRunning checks if the refinery session is active and healthy.
 // Checks both tmux session existence AND agent process liveness to avoid
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := m.sessions()
 	sessionName := m.SessionName()
 	status := t.CheckSessionHealth(sessionName, 0)
 	return status == tmux.SessionHealthy, nil
@@ -74,14 +79,14 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
+	t := m.sessions()
 	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := m.sessions()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -101,7 +106,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.sessions()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -197,8 +202,10 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = realTmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	}
 
 	// Accept bypass permissions warning dialog if it appears.
 	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
@@ -206,7 +213,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Wait for Claude to start and show its prompt - fatal if Claude fails to launch
 	// WaitForRuntimeReady waits for the runtime to be ready
-	if err := t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForRuntimeReady(t, sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
 		_ = t.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("waiting for refinery to start: %w", err)
@@ -222,7 +229,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := m.sessions()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
@@ -257,13 +264,14 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 		issue *beads.Issue
 		score float64
 	}
+	scorer := m.newQueueScorer(issues)
 	scored := make([]scoredIssue, 0, len(issues))
 	for _, issue := range issues {
 		// Defensive filter: bd status filters can drift; queue must only include open MRs.
 		if issue == nil || issue.Status != "open" {
 			continue
 		}
-		score := m.calculateIssueScore(issue, now)
+		score := m.calculateIssueScore(scorer, issue, now)
 		scored = append(scored, scoredIssue{issue: issue, score: score})
 	}
 
@@ -289,37 +297,36 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	return items, nil
 }
 
+// newQueueScorer builds the rig's MR scorer over the open MR beads,
+// falling back to the default weights if the scoring config is unreadable.
+func (m *Manager) newQueueScorer(issues []*beads.Issue) *Scorer {
+	var mrs []*MRInfo
+	for _, issue := range issues {
+		if mr := MRInfoFromIssue(issue); mr != nil {
+			mrs = append(mrs, mr)
+		}
+	}
+	scorer, err := NewEngineer(m.rig).NewScorer(mrs)
+	if err != nil {
+		return NewScorer(DefaultScoreConfig())
+	}
+	return scorer
+}
+
 // calculateIssueScore computes the priority score for an MR issue.
 // Higher scores mean higher priority (process first).
-func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64 {
-	fields := beads.ParseMRFields(issue)
-
-	// Parse MR creation time
-	mrCreatedAt := parseTime(issue.CreatedAt)
-	if mrCreatedAt.IsZero() {
-		mrCreatedAt = now // Fallback
+func (m *Manager) calculateIssueScore(scorer *Scorer, issue *beads.Issue, now time.Time) float64 {
+	mr := MRInfoFromIssue(issue)
+	if mr == nil {
+		mr = &MRInfo{ID: issue.ID, Priority: issue.Priority}
 	}
-
-	// Build score input
-	input := ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
+	if mr.CreatedAt.IsZero() {
+		mr.CreatedAt = parseTime(issue.CreatedAt)
 	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
+	if mr.CreatedAt.IsZero() {
+		mr.CreatedAt = now // Fallback
 	}
-
-	return ScoreMRWithDefaults(input)
+	return scorer.Score(mr, now)
 }
 
 // issueToMR converts a beads issue to a MergeRequest.
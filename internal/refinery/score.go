@@ -4,7 +4,13 @@
 package refinery
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // ScoreConfig contains tunable weights for MR priority scoring.
@@ -37,6 +43,34 @@ type ScoreConfig struct {
 	// MaxRetryPenalty caps the total retry penalty to prevent permanent deprioritization.
 	// Default: 300.0 (after 6 retries, penalty is capped)
 	MaxRetryPenalty float64
+
+	// DeadlineWeight is points added per hour once a convoy's due date is
+	// within DeadlineHorizon. It keeps growing after the deadline passes.
+	// Default: 20.0 (+960 at the deadline with the default horizon)
+	DeadlineWeight float64
+
+	// DeadlineHorizon is how long before a convoy's due date the deadline
+	// factor starts to count.
+	// Default: 48h
+	DeadlineHorizon time.Duration
+
+	// UnblockWeight is points added per open issue the MR's source issue blocks.
+	// Landing work others are waiting on frees up the rest of the town.
+	// Default: 50.0
+	UnblockWeight float64
+
+	// MaxUnblockBonus caps the unblock bonus.
+	// Default: 300.0
+	MaxUnblockBonus float64
+
+	// OverlapPenalty is subtracted per file the MR changes that an in-flight
+	// MR also changes, so likely conflicts wait for the other merge to land.
+	// Default: 25.0
+	OverlapPenalty float64
+
+	// MaxOverlapPenalty caps the overlap penalty.
+	// Default: 250.0
+	MaxOverlapPenalty float64
 }
 
 // DefaultScoreConfig returns sensible defaults for MR scoring.
@@ -48,7 +82,59 @@ func DefaultScoreConfig() ScoreConfig {
 		RetryPenalty:    50.0,
 		MRAgeWeight:     1.0,
 		MaxRetryPenalty: 300.0,
+
+		DeadlineWeight:    20.0,
+		DeadlineHorizon:   48 * time.Hour,
+		UnblockWeight:     50.0,
+		MaxUnblockBonus:   300.0,
+		OverlapPenalty:    25.0,
+		MaxOverlapPenalty: 250.0,
+	}
+}
+
+// ScoreConfigFromSettings applies rig settings overrides to the defaults.
+// The settings are validated when loaded, so this does not re-check them.
+func ScoreConfigFromSettings(s *config.MRScoringConfig) ScoreConfig {
+	cfg := DefaultScoreConfig()
+	if s == nil {
+		return cfg
+	}
+	set := func(dst *float64, v *float64) {
+		if v != nil {
+			*dst = *v
+		}
+	}
+	set(&cfg.BaseScore, s.BaseScore)
+	set(&cfg.PriorityWeight, s.PriorityWeight)
+	set(&cfg.ConvoyAgeWeight, s.ConvoyAgeWeight)
+	set(&cfg.MRAgeWeight, s.MRAgeWeight)
+	set(&cfg.RetryPenalty, s.RetryPenalty)
+	set(&cfg.MaxRetryPenalty, s.MaxRetryPenalty)
+	set(&cfg.DeadlineWeight, s.DeadlineWeight)
+	set(&cfg.UnblockWeight, s.UnblockWeight)
+	set(&cfg.MaxUnblockBonus, s.MaxUnblockBonus)
+	set(&cfg.OverlapPenalty, s.OverlapPenalty)
+	set(&cfg.MaxOverlapPenalty, s.MaxOverlapPenalty)
+	if d, err := time.ParseDuration(s.DeadlineHorizon); err == nil && d > 0 {
+		cfg.DeadlineHorizon = d
 	}
+	return cfg
+}
+
+// LoadScoreConfig returns the scoring weights for the rig at rigPath:
+// the defaults overlaid with merge_queue.scoring from its settings file.
+func LoadScoreConfig(rigPath string) (ScoreConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return DefaultScoreConfig(), nil
+		}
+		return DefaultScoreConfig(), err
+	}
+	if settings.MergeQueue == nil {
+		return DefaultScoreConfig(), nil
+	}
+	return ScoreConfigFromSettings(settings.MergeQueue.Scoring), nil
 }
 
 // ScoreInput contains the data needed to score an MR.
@@ -65,15 +151,54 @@ type ScoreInput struct {
 	// Nil if MR is not part of a convoy (standalone work).
 	ConvoyCreatedAt *time.Time
 
+	// ConvoyDueAt is the convoy's deadline, if it has one.
+	ConvoyDueAt *time.Time
+
 	// RetryCount is how many times this MR has been retried after conflicts.
 	// 0 = first attempt.
 	RetryCount int
 
+	// Unblocks is how many open issues the MR's source issue blocks.
+	Unblocks int
+
+	// OverlapFiles is how many of the MR's files in-flight MRs also change,
+	// and OverlapWith names those MRs.
+	OverlapFiles int
+	OverlapWith  []string
+
 	// Now is the current time (for deterministic testing).
 	// If zero, time.Now() is used.
 	Now time.Time
 }
 
+// ScoreFactor is one term of an MR's score.
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// ScoreBreakdown is an MR's score with the factors that make it up.
+type ScoreBreakdown struct {
+	Total   float64       `json:"total"`
+	Factors []ScoreFactor `json:"factors"`
+}
+
+// scoreFactor computes one term of the score. Factors that don't apply to an
+// MR return zero points and are left out of its breakdown.
+type scoreFactor func(in ScoreInput, cfg ScoreConfig, now time.Time) ScoreFactor
+
+// scoreFactors are applied in order on top of BaseScore. New factors go here.
+var scoreFactors = []scoreFactor{
+	convoyAgeFactor,
+	convoyDeadlineFactor,
+	priorityFactor,
+	retryFactor,
+	mrAgeFactor,
+	unblockFactor,
+	overlapFactor,
+}
+
 // ScoreMR calculates the priority score for a merge request.
 // Higher scores mean higher priority (process first).
 //
@@ -81,51 +206,144 @@ type ScoreInput struct {
 //
 //	score = BaseScore
 //	      + ConvoyAgeWeight * hoursOld(convoy)       // Prevent convoy starvation
+//	      + DeadlineWeight * hoursInto(horizon)      // Convoy due date approaching
 //	      + PriorityWeight * (4 - priority)          // P0=+400, P4=+0
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
+//	      + min(UnblockWeight * unblocks, MaxUnblockBonus)   // Others are waiting
+//	      - min(OverlapPenalty * overlapFiles, MaxOverlapPenalty)  // Likely conflict
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	return ExplainMR(input, config).Total
+}
+
+// ExplainMR scores an MR and reports each factor's contribution.
+func ExplainMR(input ScoreInput, config ScoreConfig) ScoreBreakdown {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	b := ScoreBreakdown{
+		Total:   config.BaseScore,
+		Factors: []ScoreFactor{{Name: "base", Points: config.BaseScore}},
+	}
+	for _, factor := range scoreFactors {
+		f := factor(input, config, now)
+		if f.Points == 0 {
+			continue
+		}
+		b.Total += f.Points
+		b.Factors = append(b.Factors, f)
+	}
+	return b
+}
 
-	// Convoy age factor: prevent starvation of old convoys
-	if input.ConvoyCreatedAt != nil {
-		convoyAge := now.Sub(*input.ConvoyCreatedAt)
-		convoyHours := convoyAge.Hours()
+// convoyAgeFactor prevents starvation of old convoys.
+func convoyAgeFactor(in ScoreInput, cfg ScoreConfig, now time.Time) ScoreFactor {
+	f := ScoreFactor{Name: "convoy_age"}
+	if in.ConvoyCreatedAt != nil {
+		convoyHours := now.Sub(*in.ConvoyCreatedAt).Hours()
 		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
+			f.Points = cfg.ConvoyAgeWeight * convoyHours
+			f.Detail = fmt.Sprintf("convoy %s old", formatHours(convoyHours))
 		}
 	}
+	return f
+}
+
+// convoyDeadlineFactor ramps up as a convoy's due date approaches.
+func convoyDeadlineFactor(in ScoreInput, cfg ScoreConfig, now time.Time) ScoreFactor {
+	f := ScoreFactor{Name: "deadline"}
+	if in.ConvoyDueAt == nil {
+		return f
+	}
+	remaining := in.ConvoyDueAt.Sub(now)
+	if remaining >= cfg.DeadlineHorizon {
+		return f
+	}
+	f.Points = cfg.DeadlineWeight * (cfg.DeadlineHorizon - remaining).Hours()
+	if remaining >= 0 {
+		f.Detail = fmt.Sprintf("convoy due in %s", formatHours(remaining.Hours()))
+	} else {
+		f.Detail = fmt.Sprintf("convoy overdue by %s", formatHours(-remaining.Hours()))
+	}
+	return f
+}
 
-	// Priority factor: P0 (0) gets +400, P4 (4) gets +0
-	priorityBonus := 4 - input.Priority
+// priorityFactor gives P0 (0) +400 and P4 (4) +0 with the default weight.
+func priorityFactor(in ScoreInput, cfg ScoreConfig, _ time.Time) ScoreFactor {
+	priorityBonus := 4 - in.Priority
 	if priorityBonus < 0 {
 		priorityBonus = 0 // Clamp for invalid priorities > 4
 	}
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	return ScoreFactor{
+		Name:   "priority",
+		Points: cfg.PriorityWeight * float64(priorityBonus),
+		Detail: fmt.Sprintf("P%d", in.Priority),
+	}
+}
 
-	// Retry penalty: prevent thrashing on repeatedly failing MRs
-	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
-	if retryPenalty > config.MaxRetryPenalty {
-		retryPenalty = config.MaxRetryPenalty
+// retryFactor prevents thrashing on repeatedly failing MRs.
+func retryFactor(in ScoreInput, cfg ScoreConfig, _ time.Time) ScoreFactor {
+	retryPenalty := cfg.RetryPenalty * float64(in.RetryCount)
+	if retryPenalty > cfg.MaxRetryPenalty {
+		retryPenalty = cfg.MaxRetryPenalty
 	}
-	score -= retryPenalty
+	return ScoreFactor{
+		Name:   "retries",
+		Points: -retryPenalty,
+		Detail: fmt.Sprintf("%d retries", in.RetryCount),
+	}
+}
 
-	// MR age factor: FIFO ordering as tiebreaker
-	mrAge := now.Sub(input.MRCreatedAt)
-	mrHours := mrAge.Hours()
+// mrAgeFactor orders by submission time as a tiebreaker.
+func mrAgeFactor(in ScoreInput, cfg ScoreConfig, now time.Time) ScoreFactor {
+	f := ScoreFactor{Name: "mr_age"}
+	if in.MRCreatedAt.IsZero() {
+		return f // Unknown submission time
+	}
+	mrHours := now.Sub(in.MRCreatedAt).Hours()
 	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+		f.Points = cfg.MRAgeWeight * mrHours
+		f.Detail = fmt.Sprintf("queued %s", formatHours(mrHours))
 	}
+	return f
+}
 
-	return score
+// unblockFactor favors work that other open issues are waiting on.
+func unblockFactor(in ScoreInput, cfg ScoreConfig, _ time.Time) ScoreFactor {
+	bonus := cfg.UnblockWeight * float64(in.Unblocks)
+	if bonus > cfg.MaxUnblockBonus {
+		bonus = cfg.MaxUnblockBonus
+	}
+	return ScoreFactor{
+		Name:   "unblocks",
+		Points: bonus,
+		Detail: fmt.Sprintf("unblocks %d issue(s)", in.Unblocks),
+	}
+}
+
+// overlapFactor holds back MRs that touch files an in-flight merge touches.
+func overlapFactor(in ScoreInput, cfg ScoreConfig, _ time.Time) ScoreFactor {
+	penalty := cfg.OverlapPenalty * float64(in.OverlapFiles)
+	if penalty > cfg.MaxOverlapPenalty {
+		penalty = cfg.MaxOverlapPenalty
+	}
+	f := ScoreFactor{Name: "overlap", Points: -penalty}
+	if in.OverlapFiles > 0 {
+		f.Detail = fmt.Sprintf("%d file(s) shared with %s", in.OverlapFiles, strings.Join(in.OverlapWith, ", "))
+	}
+	return f
+}
+
+func formatHours(h float64) string {
+	if h < 48 {
+		return fmt.Sprintf("%.0fh", h)
+	}
+	return fmt.Sprintf("%.1fd", h/24)
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
@@ -141,12 +359,181 @@ func (mr *MRInfo) Score() float64 {
 
 // ScoreAt calculates the priority score at a specific time (for deterministic testing).
 func (mr *MRInfo) ScoreAt(now time.Time) float64 {
-	input := ScoreInput{
+	return ScoreMRWithDefaults(mr.scoreInput(now))
+}
+
+// scoreInput returns the score input carried on the MR itself, without
+// queue context.
+func (mr *MRInfo) scoreInput(now time.Time) ScoreInput {
+	return ScoreInput{
 		Priority:        mr.Priority,
 		MRCreatedAt:     mr.CreatedAt,
 		ConvoyCreatedAt: mr.ConvoyCreatedAt,
+		ConvoyDueAt:     mr.ConvoyDueAt,
 		RetryCount:      mr.RetryCount,
 		Now:             now,
 	}
-	return ScoreMRWithDefaults(input)
+}
+
+// Scorer scores MRs with a rig's weights and the queue context the
+// per-MR fields don't carry: how many open issues each source issue blocks,
+// and which files the MRs already being merged touch.
+type Scorer struct {
+	Config ScoreConfig
+
+	unblocks  map[string]int // issue ID → open issues blocked by it
+	inFlight  []*MRInfo      // claimed MRs; other MRs' files are compared to theirs
+	files     func(*MRInfo) []string
+	fileCache map[string][]string
+}
+
+// NewScorer returns a Scorer with no queue context. Without WithDependents
+// and WithInFlight it scores exactly like ScoreMR on the MR's own fields.
+func NewScorer(cfg ScoreConfig) *Scorer {
+	return &Scorer{Config: cfg, fileCache: make(map[string][]string)}
+}
+
+// WithDependents records, for each issue, how many open issues it blocks.
+// Issues from bd show carry their Dependents, which are counted exactly;
+// bd list output only has DependentCount, which is used when they are
+// missing.
+func (s *Scorer) WithDependents(issues []*beads.Issue) *Scorer {
+	s.unblocks = make(map[string]int)
+	for _, issue := range issues {
+		if issue == nil {
+			continue
+		}
+		s.unblocks[issue.ID] = openDependents(issue)
+	}
+	return s
+}
+
+func openDependents(issue *beads.Issue) int {
+	if len(issue.Dependents) == 0 {
+		return issue.DependentCount
+	}
+	n := 0
+	for _, dep := range issue.Dependents {
+		if dep.Status == "closed" {
+			continue
+		}
+		if dep.DependencyType != "" && dep.DependencyType != "blocks" {
+			continue // parent-child and other links don't gate work
+		}
+		n++
+	}
+	return n
+}
+
+// WithInFlight records the MRs currently being merged, and how to list the
+// files an MR changes.
+func (s *Scorer) WithInFlight(mrs []*MRInfo, files func(*MRInfo) []string) *Scorer {
+	s.inFlight = mrs
+	s.files = files
+	return s
+}
+
+// Input returns the full score input for mr, queue context included.
+func (s *Scorer) Input(mr *MRInfo, now time.Time) ScoreInput {
+	in := mr.scoreInput(now)
+	if mr.SourceIssue != "" {
+		in.Unblocks = s.unblocks[mr.SourceIssue]
+	}
+	if s.files == nil || len(s.inFlight) == 0 {
+		return in
+	}
+
+	mine := make(map[string]bool)
+	for _, f := range s.changedFiles(mr) {
+		mine[f] = true
+	}
+	shared := make(map[string]bool)
+	for _, other := range s.inFlight {
+		if other.ID == mr.ID {
+			continue
+		}
+		overlaps := false
+		for _, f := range s.changedFiles(other) {
+			if mine[f] {
+				shared[f] = true
+				overlaps = true
+			}
+		}
+		if overlaps {
+			in.OverlapWith = append(in.OverlapWith, other.ID)
+		}
+	}
+	in.OverlapFiles = len(shared)
+	return in
+}
+
+// Explain scores mr and reports each factor's contribution.
+func (s *Scorer) Explain(mr *MRInfo, now time.Time) ScoreBreakdown {
+	return ExplainMR(s.Input(mr, now), s.Config)
+}
+
+// Score returns mr's priority score. Higher scores are processed first.
+func (s *Scorer) Score(mr *MRInfo, now time.Time) float64 {
+	return s.Explain(mr, now).Total
+}
+
+func (s *Scorer) changedFiles(mr *MRInfo) []string {
+	if files, ok := s.fileCache[mr.ID]; ok {
+		return files
+	}
+	files := s.files(mr)
+	s.fileCache[mr.ID] = files
+	return files
+}
+
+// NewScorer builds a Scorer for this rig: weights from the rig settings,
+// dependents of the source issues of the MRs in open (from bd show), and
+// in-flight files for the claimed MRs among open. Queue context that can't
+// be read is left out rather than failing the ranking.
+func (e *Engineer) NewScorer(open []*MRInfo) (*Scorer, error) {
+	cfg, err := LoadScoreConfig(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading scoring config: %w", err)
+	}
+	s := NewScorer(cfg)
+
+	var sources []string
+	seen := make(map[string]bool)
+	for _, mr := range open {
+		if mr.SourceIssue != "" && !seen[mr.SourceIssue] {
+			seen[mr.SourceIssue] = true
+			sources = append(sources, mr.SourceIssue)
+		}
+	}
+	if len(sources) > 0 {
+		if shown, err := e.beads.ShowMultiple(sources); err == nil {
+			issues := make([]*beads.Issue, 0, len(shown))
+			for _, issue := range shown {
+				issues = append(issues, issue)
+			}
+			s.WithDependents(issues)
+		}
+	}
+
+	var inFlight []*MRInfo
+	for _, mr := range open {
+		if mr.Assignee != "" {
+			inFlight = append(inFlight, mr)
+		}
+	}
+	return s.WithInFlight(inFlight, e.mrChangedFiles), nil
+}
+
+// mrChangedFiles lists the files mr's branch changes relative to its target,
+// using the remote-tracking branch when there is no local one.
+func (e *Engineer) mrChangedFiles(mr *MRInfo) []string {
+	ref := mr.Branch
+	if exists, err := e.git.BranchExists(ref); err != nil || !exists {
+		ref = "origin/" + mr.Branch
+	}
+	files, err := e.git.BranchChangedFiles("origin/"+mr.Target, ref)
+	if err != nil {
+		return nil
+	}
+	return files
 }
@@ -0,0 +1,177 @@
+package refinery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestScoreMR_DefaultFormula(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	convoy := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		input ScoreInput
+		want  float64
+	}{
+		{"fresh P0", ScoreInput{Priority: 0, MRCreatedAt: now, Now: now}, 1400},
+		{"fresh P4", ScoreInput{Priority: 4, MRCreatedAt: now, Now: now}, 1000},
+		{"P2 queued 10h", ScoreInput{Priority: 2, MRCreatedAt: now.Add(-10 * time.Hour), Now: now}, 1210},
+		{"day-old convoy", ScoreInput{Priority: 4, MRCreatedAt: now, ConvoyCreatedAt: &convoy, Now: now}, 1240},
+		{"retry penalty capped", ScoreInput{Priority: 4, MRCreatedAt: now, RetryCount: 10, Now: now}, 700},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScoreMRWithDefaults(tt.input); got != tt.want {
+				t.Errorf("ScoreMR = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreMR_ConvoyDeadline(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	cfg := DefaultScoreConfig()
+	base := ScoreInput{Priority: 4, MRCreatedAt: now, Now: now}
+
+	deadline := func(due time.Time) float64 {
+		in := base
+		in.ConvoyDueAt = &due
+		return ScoreMR(in, cfg) - ScoreMR(base, cfg)
+	}
+
+	if got := deadline(now.Add(72 * time.Hour)); got != 0 {
+		t.Errorf("due beyond horizon: bonus = %v, want 0", got)
+	}
+	if got := deadline(now.Add(24 * time.Hour)); got != cfg.DeadlineWeight*24 {
+		t.Errorf("due in 24h: bonus = %v, want %v", got, cfg.DeadlineWeight*24)
+	}
+	atDue := deadline(now)
+	if overdue := deadline(now.Add(-6 * time.Hour)); overdue <= atDue {
+		t.Errorf("overdue bonus %v should exceed at-deadline bonus %v", overdue, atDue)
+	}
+}
+
+func TestExplainMR_FactorsSumToTotal(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	convoy := now.Add(-5 * time.Hour)
+	due := now.Add(2 * time.Hour)
+	in := ScoreInput{
+		Priority:        1,
+		MRCreatedAt:     now.Add(-3 * time.Hour),
+		ConvoyCreatedAt: &convoy,
+		ConvoyDueAt:     &due,
+		RetryCount:      1,
+		Unblocks:        20, // capped
+		OverlapFiles:    2,
+		OverlapWith:     []string{"gt-mr-a"},
+		Now:             now,
+	}
+	cfg := DefaultScoreConfig()
+	b := ExplainMR(in, cfg)
+
+	var sum float64
+	points := make(map[string]float64)
+	for _, f := range b.Factors {
+		sum += f.Points
+		points[f.Name] = f.Points
+	}
+	if sum != b.Total {
+		t.Errorf("factors sum to %v, total is %v", sum, b.Total)
+	}
+	if b.Total != ScoreMR(in, cfg) {
+		t.Errorf("ExplainMR total %v != ScoreMR %v", b.Total, ScoreMR(in, cfg))
+	}
+	if points["unblocks"] != cfg.MaxUnblockBonus {
+		t.Errorf("unblocks = %v, want cap %v", points["unblocks"], cfg.MaxUnblockBonus)
+	}
+	if points["overlap"] != -2*cfg.OverlapPenalty {
+		t.Errorf("overlap = %v, want %v", points["overlap"], -2*cfg.OverlapPenalty)
+	}
+	for _, name := range []string{"base", "convoy_age", "deadline", "priority", "retries", "mr_age"} {
+		if _, ok := points[name]; !ok {
+			t.Errorf("missing factor %q in %+v", name, b.Factors)
+		}
+	}
+}
+
+func TestScorer_QueueContext(t *testing.T) {
+	now := time.Now()
+	files := map[string][]string{
+		"mr-a":      {"go.mod", "cmd/a.go"},
+		"mr-b":      {"cmd/b.go"},
+		"mr-flight": {"go.mod", "cmd/a.go", "README.md"},
+	}
+	a := &MRInfo{ID: "mr-a", SourceIssue: "gt-a", Priority: 2, CreatedAt: now}
+	b := &MRInfo{ID: "mr-b", SourceIssue: "gt-b", Priority: 2, CreatedAt: now}
+	flight := &MRInfo{ID: "mr-flight", Priority: 2, CreatedAt: now, Assignee: "refinery-1"}
+
+	// gt-a as bd list reports it (counts only), gt-b as bd show does.
+	var sources []*beads.Issue
+	if err := json.Unmarshal([]byte(`[
+		{"id": "gt-a", "status": "open", "dependency_count": 0, "dependent_count": 1},
+		{"id": "gt-b", "status": "open", "dependent_count": 4, "dependents": [
+			{"id": "gt-x", "status": "open", "dependency_type": "blocks"},
+			{"id": "gt-y", "status": "in_progress", "dependency_type": "blocks"},
+			{"id": "gt-z", "status": "closed", "dependency_type": "blocks"},
+			{"id": "gt-e", "status": "open", "dependency_type": "parent-child"}
+		]}
+	]`), &sources); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScorer(DefaultScoreConfig()).
+		WithDependents(sources).
+		WithInFlight([]*MRInfo{flight}, func(mr *MRInfo) []string { return files[mr.ID] })
+
+	inA := s.Input(a, now)
+	if inA.Unblocks != 1 || inA.OverlapFiles != 2 || len(inA.OverlapWith) != 1 {
+		t.Errorf("mr-a input = %+v, want 1 unblock and 2 overlapping files", inA)
+	}
+	inB := s.Input(b, now)
+	if inB.Unblocks != 2 || inB.OverlapFiles != 0 {
+		t.Errorf("mr-b input = %+v, want 2 unblocks and no overlap", inB)
+	}
+	if s.Score(b, now) <= s.Score(a, now) {
+		t.Errorf("mr-b (unblocks more, no overlap) should outrank mr-a")
+	}
+	if got := s.Input(flight, now).OverlapFiles; got != 0 {
+		t.Errorf("in-flight MR overlaps itself: %d files", got)
+	}
+}
+
+func TestLoadScoreConfig(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg, err := LoadScoreConfig(rigPath)
+	if err != nil {
+		t.Fatalf("LoadScoreConfig without settings: %v", err)
+	}
+	if cfg != DefaultScoreConfig() {
+		t.Errorf("missing settings should give defaults, got %+v", cfg)
+	}
+
+	settings := `{"type": "rig-settings", "version": 1, "merge_queue": {"scoring": {
+		"priority_weight": 150, "unblock_weight": 0, "deadline_horizon": "24h"}}}`
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = LoadScoreConfig(rigPath)
+	if err != nil {
+		t.Fatalf("LoadScoreConfig: %v", err)
+	}
+	if cfg.PriorityWeight != 150 || cfg.UnblockWeight != 0 || cfg.DeadlineHorizon != 24*time.Hour {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+	if cfg.RetryPenalty != DefaultScoreConfig().RetryPenalty {
+		t.Errorf("unset weight changed: RetryPenalty = %v", cfg.RetryPenalty)
+	}
+}
@@ -554,19 +554,20 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		_, _ = typesCmd.CombinedOutput()
 	}
 
-	// Auto-create DoltHub remote for the rig's beads database.
-	// Requires DOLTHUB_TOKEN and DOLTHUB_ORG environment variables.
+	// Auto-create a remote for the rig's beads database.
+	// Requires DOLTHUB_TOKEN and DOLTHUB_ORG environment variables (or
+	// GT_DOLT_REMOTE and GT_DOLT_REMOTE_ORG for a file remote).
 	// Non-fatal: sync will work without a remote; user can add one manually later.
-	if token := doltserver.DoltHubToken(); token != "" {
-		if org := doltserver.DoltHubOrg(); org != "" {
+	if provider, err := doltserver.RemoteProviderFromEnv(); err == nil && provider.Check() == nil {
+		if org := doltserver.RemoteOrg(); org != "" {
 			dbName := "beads_" + opts.Name
 			dbDir := doltserver.RigDatabaseDir(m.townRoot, dbName)
-			fmt.Printf("  Setting up DoltHub remote for %s/%s...\n", org, doltserver.DoltHubRepoName(dbName))
-			if err := doltserver.SetupDoltHubRemote(dbDir, org, dbName, token); err != nil {
-				fmt.Printf("  Warning: DoltHub remote setup failed: %v\n", err)
+			fmt.Printf("  Setting up %s remote for %s/%s...\n", provider.Name(), org, doltserver.DoltHubRepoName(dbName))
+			if err := doltserver.SetupRemote(dbDir, provider, org, dbName); err != nil {
+				fmt.Printf("  Warning: %s remote setup failed: %v\n", provider.Name(), err)
 				fmt.Printf("  You can set up the remote manually later with 'gt dolt sync'.\n")
 			} else {
-				fmt.Printf("   ✓ DoltHub remote configured and initial push complete\n")
+				fmt.Printf("   ✓ %s remote configured and initial push complete\n", provider.Name())
 			}
 		}
 	}
@@ -106,6 +106,22 @@ func (s *Store) ReadRun(runID string) ([]Event, error) {
 	if strings.TrimSpace(runID) == "" {
 		return nil, fmt.Errorf("run id is required")
 	}
+	return s.read(func(evt Event) bool { return evt.RunID == runID })
+}
+
+// ReadEvents returns events of the given type (all types if empty) at or
+// after since, ordered by timestamp.
+func (s *Store) ReadEvents(eventType string, since time.Time) ([]Event, error) {
+	return s.read(func(evt Event) bool {
+		if eventType != "" && evt.EventType != eventType {
+			return false
+		}
+		return !evt.Timestamp.Before(since)
+	})
+}
+
+// read returns the events keep accepts, ordered by timestamp.
+func (s *Store) read(keep func(Event) bool) ([]Event, error) {
 	if _, err := os.Stat(s.path); err != nil {
 		if os.IsNotExist(err) {
 			return []Event{}, nil
@@ -127,6 +143,7 @@ func (s *Store) ReadRun(runID string) ([]Event, error) {
 
 	var out []Event
 	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024) // command output can be long
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
@@ -136,7 +153,7 @@ func (s *Store) ReadRun(runID string) ([]Event, error) {
 		if err := json.Unmarshal([]byte(line), &evt); err != nil {
 			continue
 		}
-		if evt.RunID == runID {
+		if keep(evt) {
 			evt.Payload = RedactPayload(evt.Payload)
 			out = append(out, evt)
 		}
@@ -43,6 +43,38 @@ func TestAppendAndReadRun(t *testing.T) {
 	}
 }
 
+func TestReadEvents(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	now := time.Now().UTC()
+	for i, evt := range []Event{
+		{RunID: NewRunID(), EventType: "policy_evaluated", Timestamp: now.Add(-2 * time.Hour)},
+		{RunID: NewRunID(), EventType: "command_started", Timestamp: now.Add(-time.Minute)},
+		{RunID: NewRunID(), EventType: "policy_evaluated", Timestamp: now.Add(-time.Minute)},
+	} {
+		if err := store.Append(evt); err != nil {
+			t.Fatalf("Append(%d): %v", i, err)
+		}
+	}
+
+	events, err := store.ReadEvents("policy_evaluated", time.Time{})
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events len = %d, want 2", len(events))
+	}
+
+	events, err = store.ReadEvents("policy_evaluated", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ReadEvents(since): %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("events since an hour ago = %d, want 1", len(events))
+	}
+}
+
 func TestRedactString(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// SessionBackend is what agent sessions run on: the operations the session
+// lifecycle needs to start, drive, inspect and stop a session. *tmux.Tmux is
+// one implementation; headless.Client (PTYs hosted by the daemon) is another.
+//
+// Backend-specific extras (tmux themes, key bindings, pane-died hooks,
+// attaching) are not part of the interface; callers type-assert to
+// *tmux.Tmux for those and skip them otherwise.
+type SessionBackend interface {
+	// NewSessionWithCommand starts a detached session running command.
+	NewSessionWithCommand(name, workDir, command string) error
+	// HasSession reports whether the session exists.
+	HasSession(name string) (bool, error)
+	// ListSessions returns the names of all sessions.
+	ListSessions() ([]string, error)
+	// KillSession removes the session.
+	KillSession(name string) error
+	// KillSessionWithProcesses kills the session and every process in it.
+	KillSessionWithProcesses(name string) error
+	// GetSessionInfo returns creation and activity information.
+	GetSessionInfo(name string) (*tmux.SessionInfo, error)
+	// GetSessionActivity returns when the session last had activity.
+	GetSessionActivity(name string) (time.Time, error)
+
+	// CapturePane returns the last lines of the session's output.
+	CapturePane(session string, lines int) (string, error)
+	// SendKeysRaw sends a key name ("Enter", "C-c") or text without Enter.
+	SendKeysRaw(session, keys string) error
+	// SendKeys types text and presses Enter.
+	SendKeys(session, keys string) error
+	// NudgeSession delivers a message to the agent and submits it.
+	NudgeSession(session, message string) error
+	// AcceptBypassPermissionsWarning dismisses the agent's bypass
+	// permissions dialog if it is showing.
+	AcceptBypassPermissionsWarning(session string) error
+
+	// SetEnvironment and GetEnvironment manage the session environment.
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+
+	// IsAgentAlive reports whether the agent process is running.
+	IsAgentAlive(session string) bool
+	// CheckSessionHealth classifies the session as healthy, dead, running
+	// a dead agent, or hung (no activity for maxInactivity, if positive).
+	CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus
+	// GetPaneID returns the ID of the session's agent pane.
+	GetPaneID(session string) (string, error)
+	// GetPanePID returns the PID of the session's agent pane process.
+	GetPanePID(session string) (string, error)
+	// WaitForCommand waits until the pane runs something other than
+	// excludeCommands (typically shells).
+	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
+
+	// SetRemainOnExit keeps the session when its process exits.
+	SetRemainOnExit(pane string, on bool) error
+	// SetAutoRespawnHook restarts the session's command when it exits.
+	SetAutoRespawnHook(session string) error
+}
+
+var (
+	_ SessionBackend = (*tmux.Tmux)(nil)
+	_ SessionBackend = (*headless.Client)(nil)
+)
+
+// BackendName returns the session backend configured for a town:
+// GT_SESSION_BACKEND if set, else session_backend from the town settings,
+// else tmux. Unknown values fall back to tmux.
+func BackendName(townRoot string) string {
+	name := os.Getenv("GT_SESSION_BACKEND")
+	if name == "" && townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			name = settings.SessionBackend
+		}
+	}
+	if name == config.SessionBackendPTY {
+		return config.SessionBackendPTY
+	}
+	return config.SessionBackendTmux
+}
+
+// NewBackend returns the session backend configured for a town.
+func NewBackend(townRoot string) SessionBackend {
+	if BackendName(townRoot) == config.SessionBackendPTY {
+		return headless.NewClient(townRoot)
+	}
+	return tmux.NewTmux()
+}
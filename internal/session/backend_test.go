@@ -0,0 +1,128 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// fakeBackend is an in-memory SessionBackend. Methods the tests don't use
+// fall through to the nil embedded interface and panic.
+type fakeBackend struct {
+	SessionBackend
+	sessions map[string]bool // session -> agent alive
+	keys     []string
+	killed   []string
+}
+
+func newFakeBackend(sessions map[string]bool) *fakeBackend {
+	return &fakeBackend{sessions: sessions}
+}
+
+func (f *fakeBackend) HasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+
+func (f *fakeBackend) IsAgentAlive(name string) bool {
+	return f.sessions[name]
+}
+
+func (f *fakeBackend) SendKeysRaw(name, keys string) error {
+	f.keys = append(f.keys, keys)
+	return nil
+}
+
+func (f *fakeBackend) KillSessionWithProcesses(name string) error {
+	if _, ok := f.sessions[name]; !ok {
+		return tmux.ErrSessionNotFound
+	}
+	delete(f.sessions, name)
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+func TestKillExistingSession_Zombie(t *testing.T) {
+	b := newFakeBackend(map[string]bool{"gt-zombie": false})
+
+	killed, err := KillExistingSession(b, "gt-zombie", true)
+	if err != nil {
+		t.Fatalf("KillExistingSession: %v", err)
+	}
+	if !killed || len(b.killed) != 1 {
+		t.Errorf("killed = %v (%v), want zombie session killed", killed, b.killed)
+	}
+}
+
+func TestKillExistingSession_AliveAgent(t *testing.T) {
+	b := newFakeBackend(map[string]bool{"gt-live": true})
+
+	killed, err := KillExistingSession(b, "gt-live", true)
+	if err == nil {
+		t.Fatal("expected error for a session with a live agent")
+	}
+	if killed || len(b.killed) != 0 {
+		t.Errorf("live session was killed")
+	}
+
+	killed, err = KillExistingSession(b, "gt-live", false)
+	if err != nil || !killed {
+		t.Errorf("KillExistingSession(checkAlive=false) = %v, %v; want killed", killed, err)
+	}
+}
+
+func TestStopSession(t *testing.T) {
+	b := newFakeBackend(map[string]bool{"gt-a": true})
+
+	if err := StopSession(b, "gt-missing", false); err == nil {
+		t.Error("expected error stopping a missing session")
+	}
+	if err := StopSession(b, "gt-a", false); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	if len(b.killed) != 1 || b.killed[0] != "gt-a" {
+		t.Errorf("killed = %v, want [gt-a]", b.killed)
+	}
+	if len(b.keys) != 0 {
+		t.Errorf("non-graceful stop sent keys %v", b.keys)
+	}
+}
+
+func TestBackendName(t *testing.T) {
+	townRoot := t.TempDir()
+	settingsPath := config.TownSettingsPath(townRoot)
+
+	t.Setenv("GT_SESSION_BACKEND", "")
+	if got := BackendName(townRoot); got != config.SessionBackendTmux {
+		t.Errorf("default backend = %q, want tmux", got)
+	}
+
+	settings := config.NewTownSettings()
+	settings.SessionBackend = config.SessionBackendPTY
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := BackendName(townRoot); got != config.SessionBackendPTY {
+		t.Errorf("backend from settings = %q, want pty", got)
+	}
+	if _, ok := NewBackend(townRoot).(*headless.Client); !ok {
+		t.Errorf("NewBackend with pty settings is %T, want *headless.Client", NewBackend(townRoot))
+	}
+
+	t.Setenv("GT_SESSION_BACKEND", "tmux")
+	if got := BackendName(townRoot); got != config.SessionBackendTmux {
+		t.Errorf("env override = %q, want tmux", got)
+	}
+
+	t.Setenv("GT_SESSION_BACKEND", "screen")
+	if got := BackendName(townRoot); got != config.SessionBackendTmux {
+		t.Errorf("unknown backend = %q, want tmux fallback", got)
+	}
+}
@@ -114,7 +114,7 @@ type StartResult struct {
 	RuntimeConfig *config.RuntimeConfig
 }
 
-// StartSession creates a tmux session following the standard Gas Town lifecycle.
+// StartSession creates a session on t following the standard Gas Town lifecycle.
 //
 // The lifecycle handles:
 //  1. Resolve runtime config for the role
@@ -129,7 +129,7 @@ type StartResult struct {
 // Role-specific concerns (issue validation, fallback nudges, pane-died hooks,
 // crew cycle bindings, etc.) should be handled by the caller before/after
 // calling StartSession.
-func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
+func StartSession(t SessionBackend, cfg SessionConfig) (*StartResult, error) {
 	if cfg.SessionID == "" {
 		return nil, fmt.Errorf("SessionID is required")
 	}
@@ -200,9 +200,9 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 		_ = t.SetEnvironment(cfg.SessionID, k, cfg.ExtraEnv[k])
 	}
 
-	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
+	// 7. Apply theme (tmux only: other backends have no status bar).
+	if realTmux, ok := t.(*tmux.Tmux); ok && cfg.Theme != nil {
+		_ = realTmux.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
 	}
 
 	// 8. Wait for agent to start.
@@ -257,7 +257,7 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
 // before force-killing. This allows the agent to clean up.
-func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
+func StopSession(t SessionBackend, sessionID string, graceful bool) error {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -296,7 +296,7 @@ func mapKeysSorted(m map[string]string) []string {
 // If checkAlive is true, only kills zombie sessions (tmux alive but agent dead).
 // If the session exists and the agent is alive, returns ErrAlreadyRunning.
 // If checkAlive is false, kills any existing session unconditionally.
-func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool, error) {
+func KillExistingSession(t SessionBackend, sessionID string, checkAlive bool) (bool, error) {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("checking session: %w", err)
@@ -341,6 +341,16 @@ func ReadyDelay(rc *config.RuntimeConfig) {
 	runtime.SleepForReadyDelay(rc)
 }
 
+// WaitForRuntimeReady waits for the agent in a session to be ready for
+// input. On tmux this watches for the runtime's ready prompt; other backends
+// wait for the agent process to replace the shell.
+func WaitForRuntimeReady(t SessionBackend, sessionID string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		return realTmux.WaitForRuntimeReady(sessionID, rc, timeout)
+	}
+	return t.WaitForCommand(sessionID, constants.SupportedShells, timeout)
+}
+
 // ShutdownDelay is the standard delay after session creation.
 // Some roles use this instead of the runtime's ready delay.
 func ShutdownDelay() time.Duration {
@@ -108,10 +108,14 @@ func TestBuildCommand_WithAgentOverride(t *testing.T) {
 }
 
 func TestKillExistingSession_NoSession(t *testing.T) {
-	// KillExistingSession with nil tmux would panic, but we test the logic
-	// by verifying it's callable. Full integration tests need a real tmux.
-	// This test verifies the function signature and basic flow.
-	t.Skip("requires tmux for integration testing")
+	b := newFakeBackend(map[string]bool{})
+	killed, err := KillExistingSession(b, "gt-none", true)
+	if err != nil {
+		t.Fatalf("KillExistingSession: %v", err)
+	}
+	if killed {
+		t.Error("reported a kill with no session")
+	}
 }
 
 func TestMapKeysSorted(t *testing.T) {
@@ -8,8 +8,6 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
-
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // pidStartTimeFunc is overridden in tests. This package's tests must NOT use
@@ -33,7 +31,7 @@ func pidFile(townRoot, sessionID string) string {
 	return filepath.Join(pidsDir(townRoot), sessionID+".pid")
 }
 
-// TrackSessionPID captures the pane PID of a tmux session and writes it
+// TrackSessionPID captures the pane PID of a session and writes it
 // to a PID tracking file. This is defense-in-depth: if a session dies
 // unexpectedly and KillSessionWithProcesses can't find the tmux pane,
 // we still have the PID on disk for cleanup.
@@ -41,7 +39,7 @@ func pidFile(townRoot, sessionID string) string {
 // This is best-effort — errors are returned but callers should treat them
 // as non-fatal since the primary kill mechanism (KillSessionWithProcesses)
 // doesn't depend on PID files.
-func TrackSessionPID(townRoot, sessionID string, t *tmux.Tmux) error {
+func TrackSessionPID(townRoot, sessionID string, t SessionBackend) error {
 	pidStr, err := t.GetPanePID(sessionID)
 	if err != nil {
 		return fmt.Errorf("getting pane PID: %w", err)
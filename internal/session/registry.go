@@ -135,21 +135,27 @@ type beadsEntry struct {
 
 // BuildPrefixRegistryFromFile reads a rigs.json file and returns a PrefixRegistry.
 func BuildPrefixRegistryFromFile(path string) (*PrefixRegistry, error) {
-	r := NewPrefixRegistry()
-
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return r, nil
+			return NewPrefixRegistry(), nil
 		}
 		return nil, err
 	}
 
+	return ParsePrefixRegistry(data)
+}
+
+// ParsePrefixRegistry builds a PrefixRegistry from rigs.json contents.
+// Used when rigs.json is read from somewhere other than the local disk,
+// such as a remote town.
+func ParsePrefixRegistry(data []byte) (*PrefixRegistry, error) {
 	var rigs rigsJSON
 	if err := json.Unmarshal(data, &rigs); err != nil {
 		return nil, err
 	}
 
+	r := NewPrefixRegistry()
 	for rigName, entry := range rigs.Rigs {
 		if entry.Beads != nil && entry.Beads.Prefix != "" {
 			r.Register(entry.Beads.Prefix, rigName)
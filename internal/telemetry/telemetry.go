@@ -0,0 +1,222 @@
+// Package telemetry provides OpenTelemetry tracing for Gas Town work units.
+//
+// A unit of work crosses several short-lived processes (gt sling, the
+// polecat's session, gt done, the refinery), so the trace context has to be
+// carried between them explicitly. It travels as a W3C traceparent string:
+//
+//   - in the GT_TRACEPARENT env var of spawned sessions
+//   - in the traceparent field of the hooked bead and the MR bead
+//   - in the traceparent label of mail
+//
+// Tracing is off unless an exporter is configured. OTEL_EXPORTER_OTLP_ENDPOINT
+// (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) sends spans to an OTLP/HTTP
+// collector; GT_TRACE_FILE appends them as JSON lines to a file for offline
+// use. Both may be set. The remaining OTEL_* variables are honored by the SDK.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Environment variables.
+const (
+	// EnvTraceParent carries the W3C traceparent into spawned sessions.
+	EnvTraceParent = "GT_TRACEPARENT"
+
+	// EnvTraceFile names a file that spans are appended to as JSON lines.
+	EnvTraceFile = "GT_TRACE_FILE"
+)
+
+// tracerName is the instrumentation scope for all Gas Town spans.
+const tracerName = "github.com/steveyegge/gastown"
+
+// flushTimeout bounds how long Flush and shutdown wait on the exporters.
+const flushTimeout = 5 * time.Second
+
+var (
+	provider   *sdktrace.TracerProvider
+	propagator = propagation.TraceContext{}
+)
+
+// Enabled reports whether Init configured an exporter in this process.
+func Enabled() bool {
+	return provider != nil
+}
+
+// Configured reports whether the environment asks for tracing.
+func Configured() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return os.Getenv(EnvTraceFile) != "" || otlpConfigured()
+}
+
+func otlpConfigured() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init installs the global tracer provider for this process. service is the
+// default service.name (OTEL_SERVICE_NAME overrides it). The returned function
+// flushes and shuts the provider down; it is safe to call when Init failed or
+// tracing is not configured.
+func Init(ctx context.Context, service string) (func(), error) {
+	if provider != nil || !Configured() {
+		return func() {}, nil
+	}
+
+	var opts []sdktrace.TracerProviderOption
+	var errs []error
+
+	if path := os.Getenv(EnvTraceFile); path != "" {
+		exp, err := newFileExporter(path)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			// Synchronous: sessions are often killed right after their last
+			// command, so spans must be on disk as soon as they end.
+			opts = append(opts, sdktrace.WithSyncer(exp))
+		}
+	}
+	if otlpConfigured() {
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("creating OTLP exporter: %w", err))
+		} else {
+			opts = append(opts, sdktrace.WithBatcher(exp))
+		}
+	}
+	if len(opts) == 0 {
+		return func() {}, errors.Join(errs...)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", service)))
+	if err != nil {
+		res = resource.Default()
+	}
+	if os.Getenv("OTEL_SERVICE_NAME") != "" {
+		// resource.Default already picked it up; don't override it with ours.
+		res = resource.Default()
+	}
+	opts = append(opts, sdktrace.WithResource(res))
+
+	provider = sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+		_ = provider.Shutdown(ctx)
+	}, errors.Join(errs...)
+}
+
+// newFileExporter appends spans to path, one JSON object per line. Many gt
+// processes share the file, so it is opened in append mode and each span is a
+// single write.
+func newFileExporter(path string) (sdktrace.SpanExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating trace file directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: trace output is not sensitive
+	if err != nil {
+		return nil, fmt.Errorf("opening trace file: %w", err)
+	}
+	return stdouttrace.New(stdouttrace.WithWriter(f))
+}
+
+// Flush exports any buffered spans. Call it before the process may be killed
+// from outside (e.g., before a session kills itself).
+func Flush() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	_ = provider.ForceFlush(ctx)
+}
+
+// Start begins a span. When tracing is off the span is a no-op that still
+// carries the parent's context, so propagation keeps working.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err (if any) on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or "" if there
+// is none.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// WithTraceParent returns ctx with the remote span context described by
+// traceparent. Invalid or empty values leave ctx unchanged.
+func WithTraceParent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+}
+
+// FromEnv returns ctx parented on GT_TRACEPARENT, if set.
+func FromEnv(ctx context.Context) context.Context {
+	return WithTraceParent(ctx, os.Getenv(EnvTraceParent))
+}
+
+// sessionEnvVars are the exporter settings passed to spawned sessions. They
+// end up on the session's command line, so anything that may carry
+// credentials (OTEL_EXPORTER_OTLP_HEADERS in particular) is left out;
+// sessions pick those up from their own environment.
+var sessionEnvVars = []string{
+	EnvTraceFile,
+	"OTEL_EXPORTER_OTLP_ENDPOINT",
+	"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+	"OTEL_EXPORTER_OTLP_PROTOCOL",
+	"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL",
+}
+
+// SessionEnv returns the env vars a spawned session needs to continue the
+// trace in ctx: GT_TRACEPARENT plus the exporter endpoint and protocol this
+// process uses. Returns nil when tracing is not configured.
+func SessionEnv(ctx context.Context) map[string]string {
+	if !Configured() {
+		return nil
+	}
+	env := make(map[string]string)
+	for _, k := range sessionEnvVars {
+		if v := os.Getenv(k); v != "" {
+			env[k] = v
+		}
+	}
+	if tp := TraceParent(ctx); tp != "" {
+		env[EnvTraceParent] = tp
+	}
+	return env
+}
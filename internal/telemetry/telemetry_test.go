@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// clearTracingEnv unsets every variable that would turn tracing on, so tests
+// don't depend on the developer's shell.
+func clearTracingEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if k == EnvTraceFile || k == EnvTraceParent || strings.HasPrefix(k, "OTEL_") {
+			t.Setenv(k, "")
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestTraceParentRoundTrip(t *testing.T) {
+	ctx := WithTraceParent(context.Background(), testTraceParent)
+	if got := TraceParent(ctx); got != testTraceParent {
+		t.Errorf("TraceParent = %q, want %q", got, testTraceParent)
+	}
+
+	if got := TraceParent(WithTraceParent(context.Background(), "not-a-traceparent")); got != "" {
+		t.Errorf("invalid traceparent should be ignored, got %q", got)
+	}
+	if got := TraceParent(context.Background()); got != "" {
+		t.Errorf("empty context should have no traceparent, got %q", got)
+	}
+}
+
+func TestStartWithoutProviderPropagatesParent(t *testing.T) {
+	clearTracingEnv(t)
+	t.Setenv(EnvTraceParent, testTraceParent)
+
+	ctx, span := Start(FromEnv(context.Background()), "gt.test")
+	defer span.End()
+
+	if span.IsRecording() {
+		t.Error("span should not record when tracing is not configured")
+	}
+	if got := TraceParent(ctx); got != testTraceParent {
+		t.Errorf("TraceParent = %q, want parent %q carried through", got, testTraceParent)
+	}
+}
+
+func TestSessionEnv(t *testing.T) {
+	clearTracingEnv(t)
+	ctx := WithTraceParent(context.Background(), testTraceParent)
+
+	if env := SessionEnv(ctx); env != nil {
+		t.Errorf("SessionEnv without configuration = %v, want nil", env)
+	}
+
+	t.Setenv(EnvTraceFile, "/tmp/traces.jsonl")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "authorization=Bearer secret")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "x-api-key=secret")
+	env := SessionEnv(ctx)
+	want := map[string]string{
+		EnvTraceFile:                  "/tmp/traces.jsonl",
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf",
+		EnvTraceParent:                testTraceParent,
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("SessionEnv = %v, want %v (no headers)", env, want)
+	}
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if Configured() {
+		t.Error("OTEL_SDK_DISABLED=true should disable tracing")
+	}
+}
+
+func TestInitFileExporter(t *testing.T) {
+	clearTracingEnv(t)
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	t.Setenv(EnvTraceFile, path)
+
+	shutdown, err := Init(context.Background(), "gastown-test")
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() {
+		provider = nil
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Init with GT_TRACE_FILE")
+	}
+
+	parent := WithTraceParent(context.Background(), testTraceParent)
+	ctx, span := Start(parent, "gt.done")
+	_, child := Start(ctx, "mq.submit")
+	End(child, errors.New("boom"))
+	End(span, nil)
+	shutdown()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading trace file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d span lines, want 2:\n%s", len(lines), data)
+	}
+
+	type spanJSON struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+		Parent      struct{ SpanID string }
+		Status      struct{ Code string }
+	}
+	var spans []spanJSON
+	for _, line := range lines {
+		var s spanJSON
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			t.Fatalf("parsing span %q: %v", line, err)
+		}
+		spans = append(spans, s)
+	}
+
+	// Spans are written as they end: child first.
+	if spans[0].Name != "mq.submit" || spans[1].Name != "gt.done" {
+		t.Fatalf("span order = %s, %s", spans[0].Name, spans[1].Name)
+	}
+	for _, s := range spans {
+		if s.SpanContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("%s: trace ID = %s, want the propagated one", s.Name, s.SpanContext.TraceID)
+		}
+	}
+	if spans[1].Parent.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("gt.done parent = %s, want remote parent", spans[1].Parent.SpanID)
+	}
+	if spans[0].Status.Code != "Error" {
+		t.Errorf("mq.submit status = %s, want Error", spans[0].Status.Code)
+	}
+}
@@ -1328,6 +1328,13 @@ func processMatchesNames(pid string, names []string) bool {
 	return false
 }
 
+// ProcessTreeMatches reports whether a process, or any of its descendants,
+// runs one of the named binaries. Used by session backends that don't go
+// through tmux to detect the agent process the same way IsRuntimeRunning does.
+func ProcessTreeMatches(pid string, names []string) bool {
+	return processMatchesNames(pid, names) || hasDescendantWithNames(pid, names, 0)
+}
+
 // hasDescendantWithNames checks if a process has any descendant (child, grandchild, etc.)
 // matching any of the given names. Recursively traverses the process tree up to maxDepth.
 // Used when the pane command is a shell (bash, zsh) that launched an agent.
@@ -66,6 +66,11 @@ func logPath(townRoot string) string {
 	return filepath.Join(logDir(townRoot), "town.log")
 }
 
+// LogPath returns the path of the town log for the given town root.
+func LogPath(townRoot string) string {
+	return logPath(townRoot)
+}
+
 // NewLogger creates a new Logger for the given town root.
 func NewLogger(townRoot string) *Logger {
 	return &Logger{
@@ -349,7 +349,12 @@ func parseGtEventLine(line string) *Event {
 	if err := json.Unmarshal([]byte(line), &ge); err != nil {
 		return nil
 	}
+	return gtEventToEvent(ge, line)
+}
 
+// gtEventToEvent converts a feed-visible gt event to a feed Event. Returns nil
+// for audit-only events.
+func gtEventToEvent(ge GtEvent, line string) *Event {
 	// Only show feed-visible events
 	if ge.Visibility != "feed" && ge.Visibility != "both" {
 		return nil
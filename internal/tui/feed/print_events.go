@@ -3,13 +3,17 @@ package feed
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/eventstore"
 )
 
 // PrintOptions controls filtering and behavior for PrintGtEvents.
@@ -44,21 +48,31 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		sinceTime = time.Now().Add(-dur)
 	}
 
-	var events []Event
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if event := parseGtEventLine(line); event != nil {
-			if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
-				events = append(events, *event)
+	// Read the initial batch from the event store when it can answer the
+	// filters, otherwise from the file. Either way, follow mode tails the
+	// file from its current end.
+	events, err := storeGtEvents(townRoot, sinceTime, opts)
+	if err == nil {
+		if _, err := file.Seek(0, io.SeekEnd); err != nil {
+			return fmt.Errorf("reading events: %w", err)
+		}
+	} else {
+		events = nil
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if event := parseGtEventLine(line); event != nil {
+				if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
+					events = append(events, *event)
+				}
 			}
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("reading events: %w", err)
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("reading events: %w", err)
+		}
 	}
 
 	// Sort by time descending (most recent first)
@@ -122,6 +136,63 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 	}
 }
 
+// storeGtEvents returns the newest feed-visible gt events matching opts from
+// the event store. Returns an error if the store is unavailable or can't
+// express the filters (the --mol substring match).
+func storeGtEvents(townRoot string, sinceTime time.Time, opts PrintOptions) ([]Event, error) {
+	if opts.Mol != "" {
+		return nil, fmt.Errorf("molecule filter needs a scan")
+	}
+	store, err := eventstore.OpenTown(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	if _, err := store.Sync(townRoot); err != nil {
+		return nil, err
+	}
+
+	q := eventstore.Query{
+		Sources:    []string{eventstore.SourceEvents},
+		Visibility: []string{"feed", "both"},
+		Since:      sinceTime,
+		Limit:      opts.Limit,
+	}
+	if q.Limit <= 0 {
+		q.Limit = maxStoreFeedEvents
+	}
+	if opts.Type != "" {
+		q.Types = []string{opts.Type}
+	}
+	if opts.Rig != "" {
+		q.Rigs = []string{opts.Rig}
+	}
+	found, err := store.Query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(found))
+	for _, e := range found {
+		ge := GtEvent{
+			Timestamp:  e.Time.UTC().Format(time.RFC3339),
+			Source:     "gt",
+			Type:       e.Type,
+			Actor:      e.Actor,
+			Payload:    e.Payload,
+			Visibility: e.Visibility,
+		}
+		raw, _ := json.Marshal(ge)
+		if event := gtEventToEvent(ge, string(raw)); event != nil {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+// maxStoreFeedEvents caps an unlimited feed read from the event store.
+const maxStoreFeedEvents = 10000
+
 // matchesFilters checks whether an event passes the --since, --mol, --type, and --rig filters.
 func matchesFilters(event *Event, sinceTime time.Time, mol, eventType, rig string) bool {
 	if !sinceTime.IsZero() && event.Time.Before(sinceTime) {
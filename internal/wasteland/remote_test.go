@@ -0,0 +1,104 @@
+package wasteland
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestConfigProvider(t *testing.T) {
+	t.Setenv("DOLTHUB_TOKEN", "")
+	cfg := &Config{Upstream: "steveyegge/wl-commons"}
+	p, err := cfg.Provider()
+	if err != nil || p.Name() != doltserver.RemoteProviderDoltHub {
+		t.Errorf("default Provider() = %v, %v; want dolthub", p, err)
+	}
+
+	root := t.TempDir()
+	cfg.Remote = root
+	p, err = cfg.Provider()
+	if err != nil || p.Name() != doltserver.RemoteProviderFile {
+		t.Fatalf("file Provider() = %v, %v; want file", p, err)
+	}
+	if got := p.URL("ops", "wl-commons"); !strings.HasSuffix(got, "/ops/wl-commons") || !strings.HasPrefix(got, "file://") {
+		t.Errorf("URL = %q", got)
+	}
+}
+
+// TestJoinFlow_FileRemote runs the gt wl join steps against a file remote:
+// fork the commons, clone the fork, add upstream, register, push. Needs dolt.
+func TestJoinFlow_FileRemote(t *testing.T) {
+	if _, err := exec.LookPath("dolt"); err != nil {
+		t.Skip("dolt not installed")
+	}
+	t.Setenv("DOLT_ROOT_PATH", t.TempDir())
+	dolt := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("dolt", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("dolt %s: %v (%s)", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	dolt("", "config", "--global", "--add", "user.name", "gt-test")
+	dolt("", "config", "--global", "--add", "user.email", "gt-test@example.com")
+
+	provider := &doltserver.FileRemote{Root: filepath.Join(t.TempDir(), "remotes")}
+	if err := provider.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Publish an upstream commons with an empty rigs table.
+	seed := t.TempDir()
+	dolt(seed, "init")
+	dolt(seed, "sql", "-q", `CREATE TABLE rigs (
+		handle VARCHAR(255) PRIMARY KEY, display_name TEXT, dolthub_org TEXT,
+		owner_email TEXT, gt_version TEXT, trust_level INT,
+		registered_at DATETIME, last_seen DATETIME)`)
+	dolt(seed, "add", ".")
+	dolt(seed, "commit", "-m", "commons schema")
+	if err := provider.CreateRepo("ops", "wl-commons"); err != nil {
+		t.Fatal(err)
+	}
+	dolt(seed, "remote", "add", "origin", provider.URL("ops", "wl-commons"))
+	dolt(seed, "push", "origin", "main")
+
+	// Join as alice.
+	localDir := LocalCloneDir(t.TempDir(), "ops", "wl-commons")
+	if err := provider.ForkRepo("ops", "wl-commons", "alice"); err != nil {
+		t.Fatalf("ForkRepo: %v", err)
+	}
+	if err := CloneLocally(provider.URL("alice", "wl-commons"), localDir); err != nil {
+		t.Fatalf("CloneLocally: %v", err)
+	}
+	if err := AddUpstreamRemote(localDir, provider.URL("ops", "wl-commons")); err != nil {
+		t.Fatalf("AddUpstreamRemote: %v", err)
+	}
+	if err := RegisterRig(localDir, "alice", "alice", "Alice's Workshop", "alice@example.com", "dev"); err != nil {
+		t.Fatalf("RegisterRig: %v", err)
+	}
+	if err := PushToOrigin(localDir); err != nil {
+		t.Fatalf("PushToOrigin: %v", err)
+	}
+
+	// The registration reached the fork, not the upstream.
+	handles := func(org string) string {
+		dir := filepath.Join(t.TempDir(), org)
+		dolt("", "clone", provider.URL(org, "wl-commons"), dir)
+		return dolt(dir, "sql", "-r", "csv", "-q", "SELECT handle FROM rigs")
+	}
+	if got := handles("alice"); !strings.Contains(got, "alice") {
+		t.Errorf("fork rigs = %q, want alice registered", got)
+	}
+	if got := handles("ops"); strings.Contains(got, "alice") {
+		t.Errorf("upstream rigs = %q, want no registration before a merge", got)
+	}
+
+	// gt wl sync pulls from the upstream remote.
+	dolt(localDir, "pull", "upstream", "main")
+}
@@ -5,27 +5,34 @@
 // to the commons' rigs table, and contribute wanted work items and
 // completions through DoltHub's fork/PR/merge primitives.
 //
+// The commons can also live on a file remote (see doltserver.FileRemote),
+// for a private wasteland on an internal box or an offline test run.
+//
 // See ~/hop/docs/wasteland/design.md for the full design.
 package wasteland
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 // Config holds the wasteland configuration for a rig.
 type Config struct {
-	// Upstream is the DoltHub path of the upstream commons (e.g., "steveyegge/wl-commons").
+	// Upstream is the org/db path of the upstream commons (e.g., "steveyegge/wl-commons").
 	Upstream string `json:"upstream"`
 
-	// ForkOrg is the DoltHub org where the fork lives (e.g., "alice-dev").
+	// Remote is the GT_DOLT_REMOTE value the town joined with: empty for
+	// DoltHub, or the root of a file remote.
+	Remote string `json:"remote,omitempty"`
+
+	// ForkOrg is the org where the fork lives (e.g., "alice-dev").
 	ForkOrg string `json:"fork_org"`
 
 	// ForkDB is the database name of the fork (e.g., "wl-commons").
@@ -72,12 +79,14 @@ func SaveConfig(townRoot string, cfg *Config) error {
 	return os.WriteFile(ConfigPath(townRoot), data, 0644)
 }
 
-// dolthubAPIBase is the DoltHub REST API base URL.
-// Var so tests can override it.
-var dolthubAPIBase = "https://www.dolthub.com/api/v1alpha1"
+// Provider returns the remote provider hosting this wasteland's commons.
+func (c *Config) Provider() (doltserver.RemoteProvider, error) {
+	return doltserver.ParseRemoteProvider(c.Remote)
+}
 
-// dolthubRemoteBase is the Dolt remote API base URL.
-const dolthubRemoteBase = "https://doltremoteapi.dolthub.com"
+// dolthubAPIBase overrides the DoltHub REST API base URL.
+// Var so tests can override it; empty uses the real API.
+var dolthubAPIBase = ""
 
 // ParseUpstream parses an upstream path like "steveyegge/wl-commons" into org and db.
 func ParseUpstream(upstream string) (org, db string, err error) {
@@ -91,56 +100,13 @@ func ParseUpstream(upstream string) (org, db string, err error) {
 // ForkDoltHubRepo forks a DoltHub database to the target org.
 // Uses the DoltHub fork API endpoint.
 func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
-	body := map[string]string{
-		"owner_name":     toOrg,
-		"new_repo_name":  fromDB,
-		"from_owner":     fromOrg,
-		"from_repo_name": fromDB,
-	}
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("marshaling fork request: %w", err)
-	}
-
-	url := dolthubAPIBase + "/database/fork"
-	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
-	if err != nil {
-		return fmt.Errorf("creating fork request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("authorization", "token "+token)
-
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("DoltHub fork API request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil
-	}
-
-	// Parse error response
-	var errResp struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-	}
-	if decErr := json.NewDecoder(resp.Body).Decode(&errResp); decErr == nil {
-		// "already exists" is not an error — fork was already created
-		if strings.Contains(strings.ToLower(errResp.Message), "already exists") {
-			return nil
-		}
-		return fmt.Errorf("DoltHub fork API error (HTTP %d): %s", resp.StatusCode, errResp.Message)
-	}
-	return fmt.Errorf("DoltHub fork API error (HTTP %d)", resp.StatusCode)
+	p := &doltserver.DoltHubProvider{Token: token, APIBase: dolthubAPIBase}
+	return p.ForkRepo(fromOrg, fromDB, toOrg)
 }
 
-// CloneLocally clones a DoltHub database to a local directory.
-// Returns the absolute path to the clone.
-func CloneLocally(org, db, targetDir string) error {
-	remoteURL := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db)
-
+// CloneLocally clones the database at remoteURL (see RemoteProvider.URL) to
+// a local directory.
+func CloneLocally(remoteURL, targetDir string) error {
 	if err := os.MkdirAll(filepath.Dir(targetDir), 0755); err != nil {
 		return fmt.Errorf("creating parent directory: %w", err)
 	}
@@ -213,10 +179,8 @@ func PushToOrigin(localDir string) error {
 	return nil
 }
 
-// AddUpstreamRemote adds the upstream commons as a remote named "upstream".
-func AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	url := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB)
-
+// AddUpstreamRemote adds the upstream commons at url as a remote named "upstream".
+func AddUpstreamRemote(localDir, url string) error {
 	// Check if upstream remote already exists
 	checkCmd := exec.Command("dolt", "remote", "-v")
 	checkCmd.Dir = localDir
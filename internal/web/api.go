@@ -75,6 +75,8 @@ type APIHandler struct {
 	runLog *runlog.Store
 	// dashboardToken optionally enforces API auth for mutating endpoints.
 	dashboardToken string
+	// streams fans live session output out to WebSocket viewers.
+	streams *sessionStreamer
 }
 
 const optionsCacheTTL = 30 * time.Second
@@ -94,7 +96,11 @@ func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration) *APIHandler {
 	var approvalStore *approvals.Store
 	var runStore *runlog.Store
 	if townRoot != "" {
-		evaluator = policy.LoadOrDefault(townRoot)
+		loaded, err := policy.LoadOrDefault(townRoot)
+		if err != nil {
+			log.Printf("warning: %v", err)
+		}
+		evaluator = loaded.WithRateStore(policy.NewFileRateStore(townRoot))
 		approvalStore = approvals.NewStore(townRoot)
 		runStore = runlog.NewStore(townRoot)
 	}
@@ -110,6 +116,7 @@ func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration) *APIHandler {
 		approvalStore:     approvalStore,
 		runLog:            runStore,
 		dashboardToken:    dashboardToken(),
+		streams:           newSessionStreamer(session.NewBackend(townRoot)),
 	}
 }
 
@@ -182,6 +189,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSSE(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/session/stream" && r.Method == http.MethodGet:
+		h.handleSessionStream(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -245,7 +254,10 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 			PolicyDecision: string(eval.Decision),
 			Payload: map[string]interface{}{
 				"command":        strings.Join(args, " "),
+				"args":           args,
+				"repo":           policy.NormalizeRepo(h.workDir),
 				"class":          eval.Class,
+				"rule_id":        eval.RuleID,
 				"requested_by":   "dashboard",
 				"whitelist_safe": meta.Safe,
 			},
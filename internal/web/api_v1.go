@@ -60,6 +60,7 @@ func (h *APIHandler) handlePolicyEvaluate(w http.ResponseWriter, r *http.Request
 		Args:        req.Args,
 		RequestedBy: strings.TrimSpace(req.RequestedBy),
 		Timestamp:   time.Now().UTC(),
+		DryRun:      true,
 	})
 
 	w.Header().Set("Content-Type", "application/json")
@@ -94,6 +95,7 @@ func (h *APIHandler) handleApprovalCreate(w http.ResponseWriter, r *http.Request
 		Command:     req.Command,
 		RequestedBy: strings.TrimSpace(req.RequestedBy),
 		Timestamp:   time.Now().UTC(),
+		DryRun:      true,
 	})
 
 	ttl := 15 * time.Minute
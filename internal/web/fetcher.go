@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/eventstore"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -119,6 +120,11 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}, nil
 }
 
+// TownRoot returns the town root this fetcher reads from.
+func (f *LiveConvoyFetcher) TownRoot() string {
+	return f.townRoot
+}
+
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	// List all open convoy issues
@@ -1585,6 +1591,10 @@ func (f *LiveConvoyFetcher) FetchIssues() ([]IssueRow, error) {
 
 // FetchActivity returns recent activity from the event log.
 func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
+	if rows, err := f.fetchStoreActivity(); err == nil {
+		return rows, nil
+	}
+
 	eventsPath := filepath.Join(f.townRoot, ".events.jsonl")
 
 	// Read events file
@@ -1627,29 +1637,60 @@ func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
 			continue
 		}
 
-		row := ActivityRow{
-			Type:         event.Type,
-			Category:     eventCategory(event.Type),
-			Actor:        formatAgentAddress(event.Actor),
-			Rig:          extractRig(event.Actor),
-			Icon:         eventIcon(event.Type),
-			RawTimestamp: event.Timestamp,
-		}
-
-		// Calculate time ago
-		if t, err := time.Parse(time.RFC3339, event.Timestamp); err == nil {
-			row.Time = formatTimestamp(t)
-		}
+		rows = append(rows, activityRow(event.Timestamp, event.Type, event.Actor, event.Payload))
+	}
 
-		// Generate human-readable summary
-		row.Summary = eventSummary(event.Type, event.Actor, event.Payload)
+	return rows, nil
+}
 
-		rows = append(rows, row)
+// fetchStoreActivity returns the 50 newest feed events from the event store.
+// Returns an error if the store is unavailable.
+func (f *LiveConvoyFetcher) fetchStoreActivity() ([]ActivityRow, error) {
+	store, err := eventstore.OpenTown(f.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	if _, err := store.Sync(f.townRoot); err != nil {
+		return nil, err
 	}
 
+	found, err := store.Query(eventstore.Query{
+		Sources:    []string{eventstore.SourceEvents},
+		Visibility: []string{"", "feed", "both"},
+		Limit:      50,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]ActivityRow, 0, len(found))
+	for _, e := range found {
+		rows = append(rows, activityRow(e.Time.UTC().Format(time.RFC3339), e.Type, e.Actor, e.Payload))
+	}
 	return rows, nil
 }
 
+// activityRow builds the activity row for one event.
+func activityRow(timestamp, eventType, actor string, payload map[string]interface{}) ActivityRow {
+	row := ActivityRow{
+		Type:         eventType,
+		Category:     eventCategory(eventType),
+		Actor:        formatAgentAddress(actor),
+		Rig:          extractRig(actor),
+		Icon:         eventIcon(eventType),
+		RawTimestamp: timestamp,
+	}
+
+	// Calculate time ago
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		row.Time = formatTimestamp(t)
+	}
+
+	// Generate human-readable summary
+	row.Summary = eventSummary(eventType, actor, payload)
+	return row
+}
+
 // eventCategory classifies an event type into a filter category.
 func eventCategory(eventType string) string {
 	switch eventType {
@@ -329,6 +329,7 @@ func enrichIssuesWithAssignees(issues []IssueRow, hooks []HookRow) []IssueRow {
 }
 
 // NewDashboardMux creates an HTTP handler that serves both the dashboard and API.
+// Fetchers bound to a workspace also get a Prometheus /metrics endpoint.
 // webCfg may be nil, in which case defaults are used.
 func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (http.Handler, error) {
 	if webCfg == nil {
@@ -355,6 +356,9 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 	mux := http.NewServeMux()
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
+	if p, ok := fetcher.(townRootProvider); ok {
+		mux.Handle("/metrics", NewMetricsHandler(p.TownRoot()))
+	}
 	mux.Handle("/", convoyHandler)
 
 	return mux, nil
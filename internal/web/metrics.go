@@ -0,0 +1,42 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// MetricsHandler serves town metrics in the Prometheus text format.
+type MetricsHandler struct {
+	collector *metrics.Collector
+}
+
+// NewMetricsHandler creates a /metrics handler for the given town.
+func NewMetricsHandler(townRoot string) *MetricsHandler {
+	return &MetricsHandler{collector: metrics.NewCollector(townRoot)}
+}
+
+// ServeHTTP handles GET /metrics. When GT_DASHBOARD_TOKEN is set, scrapers
+// must send it as a bearer token like any other dashboard client.
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requestHasDashboardToken(r, dashboardToken()) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	set := h.collector.Collect()
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_ = set.Write(w) // headers are already sent; a write error means the scraper went away
+}
+
+// townRootProvider is implemented by fetchers bound to a workspace.
+type townRootProvider interface {
+	TownRoot() string
+}
@@ -0,0 +1,529 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/runlog"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const (
+	// streamPollInterval is how often a streamed session's pane is captured.
+	// One capture serves every viewer of the session.
+	streamPollInterval = 500 * time.Millisecond
+
+	// streamLines is how many lines of pane output each frame carries.
+	streamLines = 200
+
+	// streamSendBuffer is how many frames may queue for a viewer before it
+	// is considered too slow and disconnected.
+	streamSendBuffer = 16
+
+	streamWriteTimeout = 10 * time.Second
+	streamPongTimeout  = 60 * time.Second
+	streamPingInterval = 25 * time.Second
+
+	// streamMaxMessage bounds a client message (auth or input).
+	streamMaxMessage = 64 << 10
+)
+
+// streamInputKeys are the key names a viewer may send. Text input goes
+// through NudgeSession; raw keys are limited to what's needed to steer an
+// agent's TUI (interrupt, dialogs, menus).
+var streamInputKeys = map[string]bool{
+	"Enter": true, "Escape": true, "Tab": true, "BTab": true, "BSpace": true,
+	"Up": true, "Down": true, "Left": true, "Right": true,
+	"PageUp": true, "PageDown": true,
+	"C-c": true, "C-d": true,
+}
+
+// streamRoles are the session roles that can be streamed.
+var streamRoles = map[session.Role]bool{
+	session.RolePolecat:  true,
+	session.RoleCrew:     true,
+	session.RoleWitness:  true,
+	session.RoleRefinery: true,
+}
+
+// StreamFrame is a server-to-viewer message on /api/session/stream.
+type StreamFrame struct {
+	// Type is "hello", "output", "viewers", "auth", "input", "ended" or "error".
+	Type      string `json:"type"`
+	Session   string `json:"session,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Viewers   int    `json:"viewers,omitempty"`
+	Input     bool   `json:"input,omitempty"` // hello: input channel enabled
+	OK        bool   `json:"ok,omitempty"`
+	Decision  string `json:"decision,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	RunID     string `json:"run_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// StreamMessage is a viewer-to-server message on /api/session/stream.
+//
+//	{"type":"auth","token":"...","viewer":"alice"}
+//	{"type":"input","text":"focus on the failing test"}
+//	{"type":"input","keys":"C-c"}
+type StreamMessage struct {
+	Type   string `json:"type"`
+	Token  string `json:"token,omitempty"`
+	Viewer string `json:"viewer,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Keys   string `json:"keys,omitempty"`
+}
+
+// sessionStreamer fans pane output out to WebSocket viewers. Each session
+// with at least one viewer has a single poller; it stops when the last
+// viewer leaves.
+type sessionStreamer struct {
+	backend  session.SessionBackend
+	interval time.Duration
+
+	mu      sync.Mutex
+	streams map[string]*sessionStream
+}
+
+func newSessionStreamer(backend session.SessionBackend) *sessionStreamer {
+	return &sessionStreamer{
+		backend:  backend,
+		interval: streamPollInterval,
+		streams:  make(map[string]*sessionStream),
+	}
+}
+
+// sessionStream is one streamed session and its viewers.
+type sessionStream struct {
+	name    string
+	viewers map[*streamViewer]bool
+	last    string
+	stop    chan struct{}
+}
+
+// streamViewer is one connected viewer.
+type streamViewer struct {
+	send   chan StreamFrame
+	name   string // Self-reported in the auth message, for the audit trail
+	remote string
+	authed bool
+	closed bool // send is closed; guarded by sessionStreamer.mu
+}
+
+// join adds a viewer to a session's stream, starting the poller if it is the
+// first viewer. The viewer immediately gets the latest output, if any.
+func (s *sessionStreamer) join(name string, v *streamViewer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.streams[name]
+	if !ok {
+		st = &sessionStream{
+			name:    name,
+			viewers: make(map[*streamViewer]bool),
+			stop:    make(chan struct{}),
+		}
+		s.streams[name] = st
+		go s.poll(st)
+	}
+	st.viewers[v] = true
+	if st.last != "" {
+		s.deliver(v, outputFrame(name, st.last))
+	}
+	s.broadcast(st, StreamFrame{Type: "viewers", Session: name, Viewers: len(st.viewers)})
+}
+
+// leave removes a viewer, stopping the poller when none remain.
+func (s *sessionStreamer) leave(name string, v *streamViewer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeViewer(v)
+	st, ok := s.streams[name]
+	if !ok || !st.viewers[v] {
+		return
+	}
+	delete(st.viewers, v)
+	if len(st.viewers) == 0 {
+		close(st.stop)
+		delete(s.streams, name)
+		return
+	}
+	s.broadcast(st, StreamFrame{Type: "viewers", Session: name, Viewers: len(st.viewers)})
+}
+
+// viewers returns the number of viewers of a session.
+func (s *sessionStreamer) viewers(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.streams[name]; ok {
+		return len(st.viewers)
+	}
+	return 0
+}
+
+// poll captures the session's pane until the stream stops, sending output
+// to viewers whenever it changes.
+func (s *sessionStreamer) poll(st *sessionStream) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		content, err := s.backend.CapturePane(st.name, streamLines)
+
+		s.mu.Lock()
+		select {
+		case <-st.stop:
+			s.mu.Unlock()
+			return
+		default:
+		}
+		switch {
+		case sessionGone(err):
+			s.broadcast(st, StreamFrame{Type: "ended", Session: st.name})
+			for v := range st.viewers {
+				s.closeViewer(v)
+			}
+			delete(s.streams, st.name)
+			s.mu.Unlock()
+			return
+		case err != nil:
+			s.broadcast(st, StreamFrame{Type: "error", Session: st.name, Error: err.Error()})
+		case content != st.last:
+			st.last = content
+			s.broadcast(st, outputFrame(st.name, content))
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-st.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sessionGone reports whether a capture error means the session (or the
+// server hosting it) no longer exists.
+func sessionGone(err error) bool {
+	return errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) ||
+		errors.Is(err, headless.ErrNoServer)
+}
+
+// broadcast sends a frame to every viewer of a stream. Caller holds s.mu.
+func (s *sessionStreamer) broadcast(st *sessionStream, frame StreamFrame) {
+	for v := range st.viewers {
+		s.deliver(v, frame)
+	}
+}
+
+// deliver queues a frame for a viewer, disconnecting viewers that can't keep
+// up rather than letting them stall the stream. Caller holds s.mu.
+func (s *sessionStreamer) deliver(v *streamViewer, frame StreamFrame) {
+	if v.closed {
+		return
+	}
+	select {
+	case v.send <- frame:
+	default:
+		s.closeViewer(v) // Its connection closes and leave removes it
+	}
+}
+
+// closeViewer closes a viewer's send queue, ending its writer. Caller holds
+// s.mu.
+func (s *sessionStreamer) closeViewer(v *streamViewer) {
+	if !v.closed {
+		v.closed = true
+		close(v.send)
+	}
+}
+
+func outputFrame(name, content string) StreamFrame {
+	return StreamFrame{
+		Type:      "output",
+		Session:   name,
+		Content:   content,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 32 << 10,
+	CheckOrigin: func(r *http.Request) bool {
+		// Same localhost-only rule as the rest of the API.
+		origin := strings.TrimSpace(r.Header.Get("Origin"))
+		return origin == "" || isLocalOrigin(origin)
+	},
+}
+
+// validateStreamSession checks that a session name is safe and names a
+// polecat, crew, witness or refinery session.
+func validateStreamSession(name string) error {
+	if name == "" {
+		return fmt.Errorf("missing session parameter")
+	}
+	for _, c := range name {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_') {
+			return fmt.Errorf("invalid session name: contains invalid characters")
+		}
+	}
+	identity, err := session.ParseSessionName(name)
+	if err != nil {
+		return fmt.Errorf("invalid session name: %v", err)
+	}
+	if !streamRoles[identity.Role] {
+		return fmt.Errorf("streaming is limited to polecat, crew, witness and refinery sessions")
+	}
+	return nil
+}
+
+// handleSessionStream upgrades to a WebSocket that streams a session's pane
+// output to the viewer. Any number of viewers can watch the same session.
+//
+// When GT_DASHBOARD_TOKEN is set, a viewer that authenticates with it (an
+// "auth" message, or the token header on the upgrade request) can send
+// input. Each input is checked by the policy evaluator as
+// "nudge <session> <text>" and recorded in the run log with the viewer's
+// name. Without a dashboard token the stream is read-only.
+func (h *APIHandler) handleSessionStream(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("session")
+	if err := validateStreamSession(name); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	has, err := h.streams.backend.HasSession(name)
+	if err != nil {
+		h.sendError(w, "Checking session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !has {
+		h.sendError(w, "Session not found: "+name, http.StatusNotFound)
+		return
+	}
+
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already replied
+	}
+	defer conn.Close()
+
+	v := &streamViewer{
+		send:   make(chan StreamFrame, streamSendBuffer),
+		remote: remoteHost(r),
+		name:   strings.TrimSpace(r.URL.Query().Get("viewer")),
+	}
+	v.authed = h.dashboardToken != "" && requestHasDashboardToken(r, h.dashboardToken)
+
+	v.send <- StreamFrame{Type: "hello", Session: name, Input: h.dashboardToken != ""}
+	h.streams.join(name, v)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writeStream(conn, v.send)
+		_ = conn.Close() // Unblock the reader if the writer stopped first
+	}()
+
+	conn.SetReadLimit(streamMaxMessage)
+	_ = conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+	})
+	for {
+		var msg StreamMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+		reply := h.handleStreamMessage(name, v, msg)
+		if !h.streams.reply(v, reply) {
+			break
+		}
+	}
+	h.streams.leave(name, v)
+	<-done
+}
+
+// reply queues a direct response to one viewer. It returns false once the
+// viewer has been disconnected.
+func (s *sessionStreamer) reply(v *streamViewer, frame StreamFrame) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v.closed {
+		return false
+	}
+	select {
+	case v.send <- frame:
+		return true
+	default:
+		s.closeViewer(v)
+		return false
+	}
+}
+
+// writeStream writes frames to the connection until send is closed, pinging
+// to keep idle connections alive.
+func writeStream(conn *websocket.Conn, send <-chan StreamFrame) {
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case frame, ok := <-send:
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if !ok {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := conn.WriteJSON(frame); err != nil {
+				return
+			}
+		case <-ping.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleStreamMessage handles one viewer message and returns the reply.
+func (h *APIHandler) handleStreamMessage(name string, v *streamViewer, msg StreamMessage) StreamFrame {
+	switch msg.Type {
+	case "auth":
+		if h.dashboardToken == "" {
+			return StreamFrame{Type: "auth", Error: "input disabled: GT_DASHBOARD_TOKEN is not set"}
+		}
+		if strings.TrimSpace(msg.Token) != h.dashboardToken {
+			v.authed = false
+			return StreamFrame{Type: "auth", Error: "invalid token"}
+		}
+		v.authed = true
+		if viewer := strings.TrimSpace(msg.Viewer); viewer != "" {
+			v.name = viewer
+		}
+		return StreamFrame{Type: "auth", OK: true}
+	case "input":
+		return h.handleStreamInput(name, v, msg)
+	default:
+		return StreamFrame{Type: "error", Error: fmt.Sprintf("unknown message type %q", msg.Type)}
+	}
+}
+
+// handleStreamInput checks a viewer's input against the token and policy,
+// sends it to the session and records it in the run log.
+func (h *APIHandler) handleStreamInput(name string, v *streamViewer, msg StreamMessage) StreamFrame {
+	if h.dashboardToken == "" {
+		return StreamFrame{Type: "input", Error: "input disabled: GT_DASHBOARD_TOKEN is not set"}
+	}
+	if !v.authed {
+		return StreamFrame{Type: "input", Error: "not authenticated: send an auth message with the dashboard token"}
+	}
+	if (msg.Text == "") == (msg.Keys == "") {
+		return StreamFrame{Type: "input", Error: "input needs exactly one of text or keys"}
+	}
+	if msg.Keys != "" && !streamInputKeys[msg.Keys] {
+		return StreamFrame{Type: "input", Error: fmt.Sprintf("key %q not allowed", msg.Keys)}
+	}
+
+	input := msg.Text
+	if input == "" {
+		input = msg.Keys
+	}
+	args := []string{"nudge", name, input}
+	viewer := v.label()
+	eval := h.policyEvaluator.Evaluate(policy.EvalRequest{
+		Agent:       "dashboard",
+		Repo:        policy.NormalizeRepo(h.workDir),
+		Command:     strings.Join(args, " "),
+		Args:        args,
+		RequestedBy: viewer,
+		Timestamp:   time.Now().UTC(),
+	})
+
+	runID := runlog.NewRunID()
+	allowed := eval.Decision == policy.DecisionAllow || eval.Decision == policy.DecisionAllowWithJustification
+	var sendErr error
+	if allowed {
+		if msg.Text != "" {
+			sendErr = h.streams.backend.NudgeSession(name, msg.Text)
+		} else {
+			sendErr = h.streams.backend.SendKeysRaw(name, msg.Keys)
+		}
+	}
+
+	if h.runLog != nil {
+		state := "succeeded"
+		switch {
+		case !allowed:
+			state = "blocked"
+		case sendErr != nil:
+			state = "failed"
+		}
+		payload := map[string]interface{}{
+			"session":     name,
+			"viewer":      viewer,
+			"remote_addr": v.remote,
+			"class":       eval.Class,
+			"rule_id":     eval.RuleID,
+		}
+		if msg.Text != "" {
+			payload["text"] = msg.Text
+		} else {
+			payload["keys"] = msg.Keys
+		}
+		if sendErr != nil {
+			payload["error"] = sendErr.Error()
+		}
+		_ = h.runLog.Append(runlog.Event{
+			RunID:          runID,
+			AgentID:        "dashboard",
+			EventType:      "session_input",
+			State:          state,
+			PolicyDecision: string(eval.Decision),
+			Payload:        payload,
+		})
+	}
+
+	reply := StreamFrame{Type: "input", Decision: string(eval.Decision), Reason: eval.Reason, RunID: runID}
+	switch {
+	case !allowed:
+		reply.Error = fmt.Sprintf("blocked by policy (%s)", eval.Decision)
+	case sendErr != nil:
+		reply.Error = sendErr.Error()
+	default:
+		reply.OK = true
+	}
+	return reply
+}
+
+// label identifies the viewer in the audit trail.
+func (v *streamViewer) label() string {
+	name := v.name
+	if name == "" {
+		name = "anonymous"
+	}
+	if v.remote != "" {
+		return name + "@" + v.remote
+	}
+	return name
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
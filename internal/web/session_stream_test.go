@@ -0,0 +1,302 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// fakeStreamBackend serves pane content from memory and records input.
+type fakeStreamBackend struct {
+	session.SessionBackend
+
+	mu      sync.Mutex
+	panes   map[string]string
+	nudges  []string
+	keys    []string
+	capture int
+}
+
+func (f *fakeStreamBackend) HasSession(name string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.panes[name]
+	return ok, nil
+}
+
+func (f *fakeStreamBackend) CapturePane(name string, lines int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.capture++
+	content, ok := f.panes[name]
+	if !ok {
+		return "", tmux.ErrSessionNotFound
+	}
+	return content, nil
+}
+
+func (f *fakeStreamBackend) NudgeSession(name, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nudges = append(f.nudges, message)
+	return nil
+}
+
+func (f *fakeStreamBackend) SendKeysRaw(name, keys string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, keys)
+	return nil
+}
+
+func (f *fakeStreamBackend) setPane(name, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.panes[name] = content
+}
+
+func (f *fakeStreamBackend) removePane(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.panes, name)
+}
+
+func setupStreamRegistry(t *testing.T) {
+	t.Helper()
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+}
+
+// newStreamTestServer serves a governed API handler whose sessions come from
+// a fake backend.
+func newStreamTestServer(t *testing.T, token string) (*APIHandler, *fakeStreamBackend, *httptest.Server) {
+	t.Helper()
+	setupStreamRegistry(t)
+
+	backend := &fakeStreamBackend{panes: map[string]string{"gt-toast": "hello from toast"}}
+	h := newGovernedTestHandler(t)
+	h.dashboardToken = token
+	h.streams = newSessionStreamer(backend)
+	h.streams.interval = 10 * time.Millisecond
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return h, backend, srv
+}
+
+func dialStream(t *testing.T, srv *httptest.Server, sessionName string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/session/stream?session=" + sessionName
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial %s: %v (status %d)", url, err, status)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// readUntil reads frames until one matches, failing after a timeout.
+func readUntil(t *testing.T, conn *websocket.Conn, match func(StreamFrame) bool) StreamFrame {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var frame StreamFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		if match(frame) {
+			return frame
+		}
+	}
+}
+
+func frameType(typ string) func(StreamFrame) bool {
+	return func(f StreamFrame) bool { return f.Type == typ }
+}
+
+func TestValidateStreamSession(t *testing.T) {
+	setupStreamRegistry(t)
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"gt-toast", false},
+		{"gt-crew-max", false},
+		{"gt-witness", false},
+		{"gt-refinery", false},
+		{"hq-mayor", true},
+		{"hq-deacon", true},
+		{"", true},
+		{"gt-toast;ls", true},
+		{"unknown-toast", true},
+	}
+	for _, tt := range tests {
+		err := validateStreamSession(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateStreamSession(%q) err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSessionStream_Rejects(t *testing.T) {
+	_, _, srv := newStreamTestServer(t, "")
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"session=hq-mayor", http.StatusBadRequest},
+		{"session=gt-nobody", http.StatusNotFound},
+	} {
+		resp, err := http.Get(srv.URL + "/api/session/stream?" + tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.query, resp.StatusCode, tc.want)
+		}
+	}
+}
+
+func TestSessionStream_MultipleViewers(t *testing.T) {
+	h, backend, srv := newStreamTestServer(t, "")
+
+	a := dialStream(t, srv, "gt-toast")
+	hello := readUntil(t, a, frameType("hello"))
+	if hello.Input {
+		t.Error("input should be disabled without a dashboard token")
+	}
+	if got := readUntil(t, a, frameType("output")); got.Content != "hello from toast" {
+		t.Errorf("first output = %q", got.Content)
+	}
+
+	b := dialStream(t, srv, "gt-toast")
+	if got := readUntil(t, b, frameType("output")); got.Content != "hello from toast" {
+		t.Errorf("late viewer output = %q", got.Content)
+	}
+	readUntil(t, a, func(f StreamFrame) bool { return f.Type == "viewers" && f.Viewers == 2 })
+
+	backend.setPane("gt-toast", "working on it")
+	for _, conn := range []*websocket.Conn{a, b} {
+		readUntil(t, conn, func(f StreamFrame) bool { return f.Type == "output" && f.Content == "working on it" })
+	}
+
+	_ = b.Close()
+	readUntil(t, a, func(f StreamFrame) bool { return f.Type == "viewers" && f.Viewers == 1 })
+
+	backend.removePane("gt-toast")
+	readUntil(t, a, frameType("ended"))
+	deadline := time.Now().Add(2 * time.Second)
+	for h.streams.viewers("gt-toast") != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := h.streams.viewers("gt-toast"); n != 0 {
+		t.Errorf("viewers after session ended = %d, want 0", n)
+	}
+}
+
+func TestSessionStream_InputDisabledWithoutToken(t *testing.T) {
+	_, backend, srv := newStreamTestServer(t, "")
+
+	conn := dialStream(t, srv, "gt-toast")
+	readUntil(t, conn, frameType("hello"))
+	if err := conn.WriteJSON(StreamMessage{Type: "input", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	reply := readUntil(t, conn, frameType("input"))
+	if reply.OK || reply.Error == "" {
+		t.Errorf("input without token = %+v, want error", reply)
+	}
+	if len(backend.nudges) != 0 {
+		t.Errorf("input was sent: %v", backend.nudges)
+	}
+}
+
+func TestSessionStream_Input(t *testing.T) {
+	h, backend, srv := newStreamTestServer(t, "secret")
+
+	conn := dialStream(t, srv, "gt-toast")
+	if hello := readUntil(t, conn, frameType("hello")); !hello.Input {
+		t.Fatal("input should be enabled with a dashboard token")
+	}
+
+	send := func(msg StreamMessage, typ string) StreamFrame {
+		t.Helper()
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+		return readUntil(t, conn, frameType(typ))
+	}
+
+	if reply := send(StreamMessage{Type: "input", Text: "hi"}, "input"); reply.OK {
+		t.Error("input accepted before auth")
+	}
+	if reply := send(StreamMessage{Type: "auth", Token: "wrong"}, "auth"); reply.OK {
+		t.Error("auth accepted a wrong token")
+	}
+	if reply := send(StreamMessage{Type: "auth", Token: "secret", Viewer: "alice"}, "auth"); !reply.OK {
+		t.Fatalf("auth failed: %+v", reply)
+	}
+
+	reply := send(StreamMessage{Type: "input", Text: "focus on the failing test"}, "input")
+	if !reply.OK || reply.RunID == "" {
+		t.Fatalf("input = %+v, want ok", reply)
+	}
+	if reply := send(StreamMessage{Type: "input", Keys: "C-c"}, "input"); !reply.OK {
+		t.Errorf("key input = %+v, want ok", reply)
+	}
+	if reply := send(StreamMessage{Type: "input", Keys: "C-z"}, "input"); reply.OK {
+		t.Error("disallowed key was accepted")
+	}
+	blocked := send(StreamMessage{Type: "input", Text: "run rm -rf / now"}, "input")
+	if blocked.OK || blocked.Decision != "deny" {
+		t.Errorf("destructive input = %+v, want denied by policy", blocked)
+	}
+
+	backend.mu.Lock()
+	nudges, keys := backend.nudges, backend.keys
+	backend.mu.Unlock()
+	if len(nudges) != 1 || nudges[0] != "focus on the failing test" {
+		t.Errorf("nudges = %v", nudges)
+	}
+	if len(keys) != 1 || keys[0] != "C-c" {
+		t.Errorf("keys = %v", keys)
+	}
+
+	events, err := h.runLog.ReadEvents("session_input", time.Time{})
+	if err != nil {
+		t.Fatalf("reading run log: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("session_input events = %d, want 3", len(events))
+	}
+	first := events[0]
+	if first.RunID != reply.RunID || first.State != "succeeded" {
+		t.Errorf("first event = %+v", first)
+	}
+	if viewer, _ := first.Payload["viewer"].(string); !strings.HasPrefix(viewer, "alice@") {
+		t.Errorf("viewer = %q, want alice@<addr>", viewer)
+	}
+	if first.Payload["session"] != "gt-toast" || first.Payload["text"] != "focus on the failing test" {
+		t.Errorf("payload = %v", first.Payload)
+	}
+	if last := events[2]; last.State != "blocked" || last.PolicyDecision != "deny" {
+		t.Errorf("blocked event = %+v", last)
+	}
+}
@@ -3004,6 +3004,14 @@
         statusEl.textContent = '';
         preview.style.display = 'block';
 
+        // Stream live output; fall back to polling the preview endpoint
+        // when streaming isn't available (e.g. mayor/deacon sessions).
+        openSessionStream(sessionName, contentEl, statusEl, function() {
+            startSessionPolling(sessionName, contentEl, statusEl);
+        });
+    }
+
+    function startSessionPolling(sessionName, contentEl, statusEl) {
         // Fetch immediately
         fetchSessionPreview(sessionName, contentEl, statusEl);
 
@@ -3014,6 +3022,124 @@
         }, 3000);
     }
 
+    // ============================================
+    // SESSION LIVE STREAM (WebSocket)
+    // ============================================
+    var sessionStream = null;
+
+    function openSessionStream(sessionName, contentEl, statusEl, fallback) {
+        closeSessionStream();
+        if (!window.WebSocket) {
+            fallback();
+            return;
+        }
+
+        var proto = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+        var ws = new WebSocket(proto + '//' + window.location.host +
+            '/api/session/stream?session=' + encodeURIComponent(sessionName));
+        var gotHello = false;
+        var viewers = 1;
+        sessionStream = ws;
+
+        ws.onmessage = function(evt) {
+            var frame;
+            try { frame = JSON.parse(evt.data); } catch (e) { return; }
+            switch (frame.type) {
+            case 'hello':
+                gotHello = true;
+                showSessionInput(frame.input);
+                break;
+            case 'output':
+                contentEl.textContent = frame.content || '(empty)';
+                contentEl.scrollTop = contentEl.scrollHeight;
+                statusEl.textContent = 'live · ' + viewers + (viewers === 1 ? ' viewer' : ' viewers');
+                break;
+            case 'viewers':
+                viewers = frame.viewers || 1;
+                statusEl.textContent = 'live · ' + viewers + (viewers === 1 ? ' viewer' : ' viewers');
+                break;
+            case 'auth':
+                if (frame.ok) {
+                    document.getElementById('session-input-auth').style.display = 'none';
+                    document.getElementById('session-input-send').style.display = 'flex';
+                    setSessionInputStatus('input unlocked');
+                } else {
+                    setSessionInputStatus(frame.error || 'authentication failed');
+                }
+                break;
+            case 'input':
+                setSessionInputStatus(frame.ok ? 'sent' : (frame.error || 'input failed'));
+                break;
+            case 'ended':
+                statusEl.textContent = 'session ended';
+                showSessionInput(false);
+                break;
+            case 'error':
+                statusEl.textContent = 'error: ' + (frame.error || 'unknown');
+                break;
+            }
+        };
+        ws.onclose = function() {
+            if (sessionStream === ws) sessionStream = null;
+            if (!gotHello) fallback();
+        };
+    }
+
+    function closeSessionStream() {
+        if (sessionStream) {
+            var ws = sessionStream;
+            sessionStream = null;
+            ws.onclose = null;
+            ws.close();
+        }
+        showSessionInput(false);
+    }
+
+    function showSessionInput(enabled) {
+        var input = document.getElementById('session-input');
+        if (!input) return;
+        input.style.display = enabled ? 'block' : 'none';
+        document.getElementById('session-input-auth').style.display = 'flex';
+        document.getElementById('session-input-send').style.display = 'none';
+        setSessionInputStatus('');
+    }
+
+    function setSessionInputStatus(text) {
+        var el = document.getElementById('session-input-status');
+        if (el) el.textContent = text;
+    }
+
+    function sendSessionStream(msg) {
+        if (sessionStream && sessionStream.readyState === WebSocket.OPEN) {
+            sessionStream.send(JSON.stringify(msg));
+        }
+    }
+
+    document.addEventListener('click', function(e) {
+        if (e.target.id === 'session-input-unlock') {
+            sendSessionStream({
+                type: 'auth',
+                token: document.getElementById('session-input-token').value,
+                viewer: document.getElementById('session-input-viewer').value
+            });
+        } else if (e.target.id === 'session-input-submit') {
+            var textEl = document.getElementById('session-input-text');
+            if (textEl.value) {
+                sendSessionStream({ type: 'input', text: textEl.value });
+                textEl.value = '';
+            }
+        } else if (e.target.classList && e.target.classList.contains('session-input-key')) {
+            sendSessionStream({ type: 'input', keys: e.target.getAttribute('data-key') });
+        }
+    });
+
+    document.addEventListener('keydown', function(e) {
+        if (e.key === 'Enter' && e.target.id === 'session-input-text') {
+            e.preventDefault();
+            document.getElementById('session-input-submit').click();
+        }
+    });
+
     function fetchSessionPreview(sessionName, contentEl, statusEl) {
         fetch('/api/session/preview?session=' + encodeURIComponent(sessionName))
             .then(function(r) { return r.json(); })
@@ -3040,6 +3166,7 @@
             clearInterval(sessionPreviewInterval);
             sessionPreviewInterval = null;
         }
+        closeSessionStream();
 
         var preview = document.getElementById('session-preview');
         if (preview) preview.style.display = 'none';
@@ -15,7 +15,6 @@ import (
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -28,6 +27,11 @@ import (
 // tmux output (tool calls, status updates). 30 minutes of silence is abnormal.
 const HungSessionThresholdMinutes = 30
 
+// newSessionBackend returns the session backend polecats run on (tmux or the
+// daemon's headless PTYs), so liveness checks look where the sessions are.
+// Tests replace it with a fake.
+var newSessionBackend = session.NewBackend
+
 // initRegistryFromWorkDir initializes the session prefix registry from a work
 // directory. This ensures session.PrefixFor(rigName) returns the correct rig
 // prefix (e.g., "tr" for testrig) instead of the default "gt".
@@ -612,7 +616,7 @@ func nudgeRefinery(townRoot, rigName string) error {
 	sessionName := session.RefinerySessionName(session.PrefixFor(rigName))
 
 	// Check if refinery is running
-	t := tmux.NewTmux()
+	t := newSessionBackend(townRoot)
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking refinery session: %w", err)
@@ -627,6 +631,11 @@ func nudgeRefinery(townRoot, rigName string) error {
 	// Queue the nudge for cooperative delivery at next turn boundary
 	nudgeMsg := "MERGE_READY received - check inbox for pending work"
 	if townRoot != "" {
+		// One pending MERGE_READY nudge is enough: skip if the refinery
+		// hasn't picked up or finished with the last one yet.
+		if pending, err := nudge.Outstanding(townRoot, sessionName, rigName+"/witness", nudgeMsg); err == nil && pending != nil {
+			return nil
+		}
 		return nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
 			Sender:  rigName + "/witness",
 			Message: nudgeMsg,
@@ -771,7 +780,8 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	initRegistryFromWorkDir(workDir)
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
-	t := tmux.NewTmux()
+	townRoot, _ := workspace.Find(workDir)
+	t := newSessionBackend(townRoot)
 
 	// Check if session exists and kill it
 	if running, _ := t.HasSession(sessionName); running {
@@ -984,7 +994,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result
 	}
 
-	t := tmux.NewTmux()
+	t := newSessionBackend(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1090,7 +1100,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 
 // detectZombieLiveSession checks a polecat with a live tmux session for zombie indicators:
 // stuck done-intent, dead agent process, or closed bead while still running.
-func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
+func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.SessionBackend, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
 	// Check for done-intent stuck too long (polecat hung in gt done).
 	if doneIntent != nil && time.Since(doneIntent.Timestamp) > 60*time.Second {
 		_, stuckHookBead := getAgentBeadState(workDir, agentBeadID)
@@ -1146,7 +1156,7 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 
 // detectZombieDeadSession checks a polecat with a dead tmux session for zombie indicators:
 // stale done-intent, or active agent state / hooked bead with no session.
-func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
+func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.SessionBackend, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
 	// Done-intent: polecat was trying to exit.
 	if doneIntent != nil {
 		age := time.Since(doneIntent.Timestamp)
@@ -1185,12 +1195,29 @@ func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, session
 		HookBead:    hookBead,
 	}
 
+	// Work is still on the hook: restart the session in its worktree from
+	// the checkpoint before treating the work as lost. Once the bead's resume
+	// budget is spent, fall through to cleanup and escalation.
+	if hookBead != "" {
+		if err := resumePolecat(workDir, rigName, polecatName); err == nil {
+			zombie.Action = "resumed"
+			return zombie, true
+		}
+	}
+
 	cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
 	handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus, router, &zombie)
 	zombie.BeadRecovered = resetAbandonedBead(workDir, rigName, hookBead, polecatName, router)
 	return zombie, true
 }
 
+// resumePolecat restarts a crashed polecat's session from its checkpoint,
+// charging the hooked bead's resume budget. Fails once the budget is spent.
+// Replaced in tests.
+var resumePolecat = func(workDir, rigName, polecatName string) error {
+	return util.ExecRun(workDir, "gt", "polecat", "resume", fmt.Sprintf("%s/%s", rigName, polecatName))
+}
+
 // isZombieState returns true if the agent state or hook bead indicates a zombie.
 func isZombieState(agentState, hookBead string) bool {
 	if hookBead != "" {
@@ -1292,7 +1319,7 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		return result // No polecats directory
 	}
 
-	t := tmux.NewTmux()
+	t := newSessionBackend(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1482,7 +1509,7 @@ func DetectOrphanedBeads(workDir, rigName string, router *mail.Router) *DetectOr
 		beadList = append(beadList, batch...)
 	}
 
-	t := tmux.NewTmux()
+	t := newSessionBackend(townRoot)
 
 	for _, bead := range beadList {
 		if bead.Assignee == "" {
@@ -1624,7 +1651,7 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 	// Step 2: Check each polecat-assigned bead
 	polecatPrefix := rigName + "/polecats/"
-	t := tmux.NewTmux()
+	t := newSessionBackend(townRoot)
 	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
 
 	for _, b := range allBeads {
@@ -1858,13 +1885,17 @@ func getAgentBeadLabels(workDir, agentBeadID string) []string {
 // sessionRecreated checks whether a tmux session was (re)created after the
 // given timestamp. Returns true if the session exists and was created after
 // detectedAt, indicating a new session replaced the dead one (TOCTOU guard).
-func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bool {
+func sessionRecreated(t session.SessionBackend, sessionName string, detectedAt time.Time) bool {
 	alive, err := t.HasSession(sessionName)
 	if err != nil || !alive {
 		return false // Still dead — not recreated
 	}
 	// Session exists now. Check if it was created after our detection.
-	createdAt, err := session.SessionCreatedAt(sessionName)
+	info, err := t.GetSessionInfo(sessionName)
+	var createdAt time.Time
+	if err == nil {
+		createdAt, err = session.ParseTmuxSessionCreated(info.Created)
+	}
 	if err != nil {
 		// Can't determine creation time — assume recreated to be safe.
 		// Better to skip a real zombie than kill a live session.
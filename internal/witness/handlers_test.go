@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -1249,3 +1250,69 @@ esac
 	}
 }
 
+// liveBackend is a session backend on which the given sessions are running
+// with live, active agents. Unimplemented methods panic.
+type liveBackend struct {
+	session.SessionBackend
+	sessions map[string]bool
+}
+
+func (b *liveBackend) HasSession(name string) (bool, error) { return b.sessions[name], nil }
+func (b *liveBackend) IsAgentAlive(name string) bool        { return b.sessions[name] }
+func (b *liveBackend) GetSessionActivity(string) (time.Time, error) {
+	return time.Now(), nil
+}
+
+func TestDetectZombiePolecats_UsesConfiguredBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell-script bd")
+	}
+	// A working polecat whose session lives on a non-tmux backend (headless
+	// PTYs) must not be mistaken for a dead session and resumed or nuked.
+	townRoot := t.TempDir()
+	rigName := "testrig"
+	if err := os.MkdirAll(filepath.Join(townRoot, rigName, "polecats", "nux"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  show) echo '[{"agent_state":"working","hook_bead":"gt-work1","status":"in_progress"}]' ;;
+  *) echo "[]" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_ = session.InitRegistry(townRoot)
+	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), "nux")
+	var backendTown string
+	oldBackend := newSessionBackend
+	newSessionBackend = func(town string) session.SessionBackend {
+		backendTown = town
+		return &liveBackend{sessions: map[string]bool{sessionName: true}}
+	}
+	defer func() { newSessionBackend = oldBackend }()
+
+	resumed := false
+	oldResume := resumePolecat
+	resumePolecat = func(workDir, rigName, polecatName string) error {
+		resumed = true
+		return nil
+	}
+	defer func() { resumePolecat = oldResume }()
+
+	result := DetectZombiePolecats(townRoot, rigName, nil)
+	if backendTown != townRoot {
+		t.Errorf("backend town = %q, want %q", backendTown, townRoot)
+	}
+	if result.Checked != 1 {
+		t.Errorf("Checked = %d, want 1", result.Checked)
+	}
+	if len(result.Zombies) != 0 || resumed {
+		t.Errorf("live polecat treated as zombie: zombies=%+v resumed=%v", result.Zombies, resumed)
+	}
+}